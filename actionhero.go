@@ -0,0 +1,562 @@
+// Package actionhero is the public entry point for applications built on
+// Go ActionHero. The framework itself lives in internal packages; this package
+// re-exports the types an application needs to define actions and provides
+// Run to boot a server with them.
+package actionhero
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/fixtures"
+	"github.com/evantahler/go-actionhero/internal/i18n"
+	"github.com/evantahler/go-actionhero/internal/initializers"
+	"github.com/evantahler/go-actionhero/internal/mail"
+	_ "github.com/evantahler/go-actionhero/internal/servers" // Registers the web server
+	"github.com/evantahler/go-actionhero/internal/util"
+	"github.com/evantahler/go-actionhero/internal/views"
+	"github.com/sirupsen/logrus"
+)
+
+// Core types re-exported for application code
+type (
+	// API is the main ActionHero application instance
+	API = api.API
+	// Action is the interface that all actions must implement
+	Action = api.Action
+	// BaseAction should be embedded in all action implementations
+	BaseAction = api.BaseAction
+	// ActionDescriptor is a registered action's metadata (see API.GetActionDescriptor)
+	ActionDescriptor = api.ActionDescriptor
+	// ActionBuilder defines an action inline (see NewAction)
+	ActionBuilder = api.ActionBuilder
+	// ActionFunc runs an action defined with NewAction
+	ActionFunc = api.ActionFunc
+	// ActionsValidator checks the actions that registering or replacing an action would leave registered
+	ActionsValidator = api.ActionsValidator
+	// TypedAction is an action with typed input and output (see NewTypedAction)
+	TypedAction[In, Out any] = api.TypedAction[In, Out]
+	// Connection represents a client connection
+	Connection = api.Connection
+	// ConnectionPool reuses the connections of short-lived requests (e.g., in a custom server)
+	ConnectionPool = api.ConnectionPool
+	// ClientInfo describes the client behind a connection (see Connection.ClientInfo)
+	ClientInfo = api.ClientInfo
+	// GeoLocation is where a client IP is (see ClientInfo.Location)
+	GeoLocation = api.GeoLocation
+	// GeoLocator finds where client IPs are (see API.GeoIP)
+	GeoLocator = api.GeoLocator
+	// WebConfig defines HTTP route configuration for an action
+	WebConfig = api.WebConfig
+	// CORSConfig is the CORS policy of an action's route (see WebConfig.CORS)
+	CORSConfig = api.CORSConfig
+	// TaskConfig defines background task configuration for an action
+	TaskConfig = api.TaskConfig
+	// HTTPMethod represents HTTP methods
+	HTTPMethod = api.HTTPMethod
+	// DocsConfig holds optional OpenAPI metadata for an action
+	DocsConfig = api.DocsConfig
+	// ConcurrencyConfig limits how many executions of an action run at once
+	ConcurrencyConfig = api.ConcurrencyConfig
+	// DeprecationConfig marks an action as deprecated
+	DeprecationConfig = api.DeprecationConfig
+	// CacheConfig caches an action's successful responses
+	CacheConfig = api.CacheConfig
+	// Cache stores values that expire (API.Cache holds cached responses)
+	Cache = api.Cache
+	// MemoryCache is a Cache held in process memory
+	MemoryCache = api.MemoryCache
+	// MessageCatalog holds translated error messages by locale (see API.Messages)
+	MessageCatalog = i18n.Catalog
+	// Middleware defines hooks that run before and/or after action execution
+	Middleware = api.Middleware
+	// Namespace applies a route prefix, middleware, and OpenAPI tag to a group of actions
+	Namespace = api.Namespace
+	// SecurityScheme describes how a middleware authenticates requests
+	SecurityScheme = api.SecurityScheme
+	// SecuredMiddleware is authentication middleware that documents its security scheme
+	SecuredMiddleware = api.SecuredMiddleware
+	// RawResponse is returned by actions that produce a non-JSON body
+	RawResponse = api.RawResponse
+	// FileResponse is returned by actions that send a file, streamed with Range support
+	FileResponse = api.FileResponse
+	// PaginationInput is the page, sort, and filters of a list action (see ParsePagination)
+	PaginationInput = api.PaginationInput
+	// PaginationOptions are the limits of a list action's PaginationInput
+	PaginationOptions = api.PaginationOptions
+	// SortField is a field a list is sorted by
+	SortField = api.SortField
+	// PaginatedResponse is returned by list actions (see Paginate)
+	PaginatedResponse = api.PaginatedResponse
+	// PaginationMeta describes the page of a PaginatedResponse
+	PaginationMeta = api.PaginationMeta
+	// Config holds all configuration for the application
+	Config = config.Config
+	// LoggerConfig is the logger section of Config
+	LoggerConfig = config.LoggerConfig
+	// SessionConfig is the session section of Config
+	SessionConfig = config.SessionConfig
+	// LoadOption customizes where LoadConfig looks for configuration
+	LoadOption = config.LoadOption
+	// ConfigSource describes where a config value came from (see Config.Sources)
+	ConfigSource = config.Source
+	// Logger is the framework logger
+	Logger = util.Logger
+	// LogHook receives log entries (see RegisterLogHook and NewLogHook)
+	LogHook = logrus.Hook
+	// LogEntry is a log entry passed to hooks
+	LogEntry = logrus.Entry
+	// LogFormatter formats log entries (see RegisterLogFormatter)
+	LogFormatter = logrus.Formatter
+	// LogHookFactory builds a log hook from the logger configuration
+	LogHookFactory = util.LogHookFactory
+	// TypedError represents an error with a specific type
+	TypedError = util.TypedError
+	// TypedErrorOption sets optional fields of a TypedError
+	TypedErrorOption = util.TypedErrorOption
+	// ErrorType identifies a category of errors
+	ErrorType = util.ErrorType
+	// ErrorTypeInfo describes how errors of a registered type are presented to clients
+	ErrorTypeInfo = util.ErrorTypeInfo
+	// ErrorReport describes an error passed to error reporters
+	ErrorReport = api.ErrorReport
+	// ErrorReporter is called for every unhandled action error, panic, and task failure
+	ErrorReporter = api.ErrorReporter
+	// Event is passed to event handlers registered with API.On
+	Event = api.Event
+	// EventHandler handles a framework event
+	EventHandler = api.EventHandler
+	// Initializer is a component initialized, started, and stopped with the API
+	Initializer = api.Initializer
+	// InitializerDependencies is implemented by initializers that must run after others
+	InitializerDependencies = api.InitializerDependencies
+	// Server is a transport (e.g., the web server) started and stopped with the API
+	Server = api.Server
+	// ServerFactory creates a server for the API (see RegisterServerFactory)
+	ServerFactory = api.ServerFactory
+	// CertificateSource provides TLS certificates to servers (e.g., an ACME manager's GetCertificate)
+	CertificateSource = api.CertificateSource
+	// Broadcaster sends messages to a channel's subscribers (the web server, unless another is provided)
+	Broadcaster = api.Broadcaster
+	// Mailer sends templated email (see API.Mail)
+	Mailer = api.Mailer
+	// MailTransport delivers encoded email (provide one to replace mail.transport)
+	MailTransport = mail.Transport
+	// MailTransportFunc adapts a function to a MailTransport
+	MailTransportFunc = mail.TransportFunc
+	// Files stores uploaded files on local disk or in an S3-compatible bucket (see API.Files)
+	Files = api.Files
+	// TaskQueues reports on task queues and retries failed tasks (provide one for the admin dashboard)
+	TaskQueues = api.TaskQueues
+	// TaskQueue describes a task queue and how many tasks are in it
+	TaskQueue = api.TaskQueue
+	// Task is a job queued to run an action in the background (see API.Enqueue)
+	Task = api.Task
+	// Tasks queues actions to run as background tasks (see API.Tasks)
+	Tasks = api.Tasks
+	// TaskBackend stores queued tasks until a worker runs them
+	TaskBackend = api.TaskBackend
+	// FailedTask is a task that failed, kept so it can be retried
+	FailedTask = api.FailedTask
+	// Breaker is the circuit breaker of a downstream dependency (see API.Breaker)
+	Breaker = api.Breaker
+	// BreakerSettings are the thresholds of a Breaker
+	BreakerSettings = api.BreakerSettings
+	// BreakerStats is a snapshot of a Breaker
+	BreakerStats = api.BreakerStats
+	// UptimeCheck is the latest result of checking a URL (see API.UptimeChecks)
+	UptimeCheck = api.UptimeCheck
+	// UptimeChecker reports the latest result of each uptime check
+	UptimeChecker = api.UptimeChecker
+	// Tenant is the customer a request is for, in a multi-tenant app (see TenantFromContext)
+	Tenant = api.Tenant
+	// TenantResolver finds the tenant of a request (set API.TenantResolver to replace tenancy.source)
+	TenantResolver = api.TenantResolver
+	// TenantResolverFunc adapts a function to a TenantResolver
+	TenantResolverFunc = api.TenantResolverFunc
+	// DryRunResponse is what a mutating action responds with in a dry run (see IsDryRun)
+	DryRunResponse = api.DryRunResponse
+	// Progress is an update on how far a long-running action has got (see Connection.Progress)
+	Progress = api.Progress
+	// ProgressHandler delivers an action's progress to its client
+	ProgressHandler = api.ProgressHandler
+	// Seeder inserts fixture rows into a database table (provide one to seed tables)
+	Seeder = fixtures.Seeder
+	// SeederFunc adapts a function to a Seeder
+	SeederFunc = fixtures.SeederFunc
+	// Fixtures is seed data loaded from YAML or JSON files (see LoadFixtures)
+	Fixtures = fixtures.Fixtures
+	// ServerAddresses is implemented by servers that listen on network addresses
+	ServerAddresses = api.ServerAddresses
+	// BootReport summarizes a start of the API (see API.BootReport)
+	BootReport = api.BootReport
+	// ComponentBootReport is an initializer's or server's part of a BootReport
+	ComponentBootReport = api.ComponentBootReport
+	// Plugin bundles actions, initializers, servers, and a config section, mounted with API.Use
+	Plugin = api.Plugin
+	// PluginConfig is implemented by plugins with their own config section
+	PluginConfig = api.PluginConfig
+	// BasePlugin can be embedded in plugins to provide empty defaults
+	BasePlugin = api.BasePlugin
+)
+
+// Security scheme types
+const (
+	SecuritySchemeAPIKey = api.SecuritySchemeAPIKey
+	SecuritySchemeHTTP   = api.SecuritySchemeHTTP
+)
+
+// HTTP method constants
+const (
+	HTTPMethodGET     = api.HTTPMethodGET
+	HTTPMethodPOST    = api.HTTPMethodPOST
+	HTTPMethodPUT     = api.HTTPMethodPUT
+	HTTPMethodDELETE  = api.HTTPMethodDELETE
+	HTTPMethodPATCH   = api.HTTPMethodPATCH
+	HTTPMethodOPTIONS = api.HTTPMethodOPTIONS
+)
+
+// DryRunHeader is the request header that asks for a dry run
+const DryRunHeader = api.DryRunHeader
+
+// ByteSize is a size in bytes (e.g., an action's ActionMaxResponseSize)
+type ByteSize = config.ByteSize
+
+// Byte size units. KB/MB/GB are decimal; KiB/MiB/GiB are binary.
+const (
+	Kilobyte = config.Kilobyte
+	Megabyte = config.Megabyte
+	Gigabyte = config.Gigabyte
+	Kibibyte = config.Kibibyte
+	Mebibyte = config.Mebibyte
+	Gibibyte = config.Gibibyte
+)
+
+// Framework event names for API.On
+const (
+	EventInitialize      = api.EventInitialize
+	EventStart           = api.EventStart
+	EventStop            = api.EventStop
+	EventActionComplete  = api.EventActionComplete
+	EventActionError     = api.EventActionError
+	EventConnectionOpen  = api.EventConnectionOpen
+	EventConnectionClose = api.EventConnectionClose
+	EventConfigReloaded  = api.EventConfigReloaded
+	EventTaskEnqueued    = api.EventTaskEnqueued
+	EventTaskComplete    = api.EventTaskComplete
+	EventTaskError       = api.EventTaskError
+)
+
+// ErrMailDisabled is returned by API.Mail until mail.enabled is set
+var ErrMailDisabled = api.ErrMailDisabled
+
+// ErrTasksDisabled is wrapped by the errors of API.Enqueue until tasks.enabled is set
+var ErrTasksDisabled = api.ErrTasksDisabled
+
+// ErrViewNotFound is returned by Connection.Render for a page not in views.directory
+var ErrViewNotFound = views.ErrViewNotFound
+
+// ErrStorageDisabled is returned by API.Files until storage.enabled is set
+var ErrStorageDisabled = api.ErrStorageDisabled
+
+// ErrFileNotFound is returned by API.Files when getting a file that is not stored
+var ErrFileNotFound = api.ErrFileNotFound
+
+// ErrCircuitOpen is returned by API.HTTPClient for requests to a host that keeps failing
+var ErrCircuitOpen = api.ErrCircuitOpen
+
+// ErrBreakerOpen is wrapped by the errors of calls refused by an open Breaker
+var ErrBreakerOpen = api.ErrBreakerOpen
+
+// BreakerMiddleware fails an action fast while the breaker of the dependency name is open
+func BreakerMiddleware(name string) Middleware {
+	return api.BreakerMiddleware(name)
+}
+
+// NewAction starts defining an action inline, e.g.
+// NewAction("user:list").Get("/users").Handler(fn)
+func NewAction(name string) *ActionBuilder {
+	return api.NewAction(name)
+}
+
+// NewTypedAction adapts a TypedAction to the Action interface
+func NewTypedAction[In, Out any](action TypedAction[In, Out]) Action {
+	return api.NewTypedAction[In, Out](action)
+}
+
+// ParsePagination reads and validates the page, perPage, cursor, sort, and
+// filter params of a list action
+func ParsePagination(params interface{}, opts PaginationOptions) (PaginationInput, error) {
+	return api.ParsePagination(params, opts)
+}
+
+// Paginate returns items as the page of input in a list of total items (a
+// negative total when it isn't known)
+func Paginate(input PaginationInput, items interface{}, total int) *PaginatedResponse {
+	return api.Paginate(input, items, total)
+}
+
+// EncodeCursor encodes a position in a list as an opaque cursor
+func EncodeCursor(position string) string {
+	return api.EncodeCursor(position)
+}
+
+// DecodeCursor decodes a cursor made by EncodeCursor
+func DecodeCursor(cursor string) (string, error) {
+	return api.DecodeCursor(cursor)
+}
+
+// Provide makes value the API's resource of type T (e.g., a database handle
+// set up by an initializer), replacing any previous one
+func Provide[T any](a *API, value T) {
+	api.Provide(a, value)
+}
+
+// Lookup returns the API's resource of type T, and whether there is one
+func Lookup[T any](a *API) (T, bool) {
+	return api.Lookup[T](a)
+}
+
+// Resource returns the resource of type T of the API running the action
+// whose context is ctx
+func Resource[T any](ctx context.Context) (T, error) {
+	return api.Resource[T](ctx)
+}
+
+// LoadFixtures reads fixtures from a YAML or JSON file, or a directory of them
+func LoadFixtures(path string) (*Fixtures, error) {
+	return fixtures.Load(path)
+}
+
+// SeedFixtures loads the fixtures at path into the API's cache and, with the
+// provided Seeder, its database
+func SeedFixtures(ctx context.Context, a *API, path string) error {
+	return fixtures.Seed(ctx, a, path)
+}
+
+// NewMemoryCache creates an empty in-memory cache
+func NewMemoryCache() *MemoryCache {
+	return api.NewMemoryCache()
+}
+
+// MarshalParams converts action params into a strongly-typed input struct
+func MarshalParams(params interface{}, target interface{}) error {
+	return api.MarshalParams(params, target)
+}
+
+// SessionCookie returns the cookie carrying a session ID, with the attributes from the session config
+func SessionCookie(cfg SessionConfig, sessionID string) *http.Cookie {
+	return api.SessionCookie(cfg, sessionID)
+}
+
+// ExpiredSessionCookie returns a cookie that removes the session cookie
+func ExpiredSessionCookie(cfg SessionConfig) *http.Cookie {
+	return api.ExpiredSessionCookie(cfg)
+}
+
+// NewConnection creates a new connection (useful for testing actions)
+func NewConnection(connType, identifier, id string, rawConnection interface{}) *Connection {
+	return api.NewConnection(connType, identifier, id, rawConnection)
+}
+
+// RequestIDFromContext returns the request/correlation ID of the current action
+func RequestIDFromContext(ctx context.Context) string {
+	return util.RequestIDFromContext(ctx)
+}
+
+// APIFromContext returns the API running the action whose context is ctx
+// (nil outside an action)
+func APIFromContext(ctx context.Context) *API {
+	return api.APIFromContext(ctx)
+}
+
+// ConfigFromContext returns the config of the API running the action whose
+// context is ctx (nil outside an action)
+func ConfigFromContext(ctx context.Context) *Config {
+	return api.ConfigFromContext(ctx)
+}
+
+// ConnectionFromContext returns the connection the action whose context is
+// ctx runs for (nil outside an action)
+func ConnectionFromContext(ctx context.Context) *Connection {
+	return api.ConnectionFromContext(ctx)
+}
+
+// WithAPI returns a copy of ctx carrying a and its config, like an action's
+// context (e.g., to call an action's Run directly in a test)
+func WithAPI(ctx context.Context, a *API) context.Context {
+	return api.WithAPI(ctx, a)
+}
+
+// T translates key into the locales of the action whose context is ctx,
+// filling each {name} with args[name] (see API.Messages). Untranslated keys
+// are returned as-is.
+func T(ctx context.Context, key string, args map[string]interface{}) string {
+	return api.T(ctx, key, args)
+}
+
+// LocalesFromContext returns the locales the action whose context is ctx
+// runs with, most preferred first
+func LocalesFromContext(ctx context.Context) []string {
+	return api.LocalesFromContext(ctx)
+}
+
+// TenantFromContext returns the tenant the action whose context is ctx runs
+// for, or nil
+func TenantFromContext(ctx context.Context) *Tenant {
+	return api.TenantFromContext(ctx)
+}
+
+// IsDryRun returns whether the action whose context is ctx runs as a dry
+// run, and so must not change anything
+func IsDryRun(ctx context.Context) bool {
+	return api.IsDryRun(ctx)
+}
+
+// WithDryRun returns a copy of ctx that runs actions as a dry run when
+// dryRun is set
+func WithDryRun(ctx context.Context, dryRun bool) context.Context {
+	return api.WithDryRun(ctx, dryRun)
+}
+
+// ReportProgress reports the progress of the action whose context is ctx,
+// from 0 to 100 percent, identifying its request
+func ReportProgress(ctx context.Context, percent float64, message string) {
+	api.ReportProgress(ctx, percent, message)
+}
+
+// TenantCacheKey returns key scoped to the tenant of ctx, or key itself when
+// there is none
+func TenantCacheKey(ctx context.Context, key string) string {
+	return api.TenantCacheKey(ctx, key)
+}
+
+// WithRequestID returns a copy of ctx carrying the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return util.WithRequestID(ctx, requestID)
+}
+
+// NewTypedError creates an error of the given type
+func NewTypedError(typ ErrorType, message string, opts ...TypedErrorOption) *TypedError {
+	return util.NewTypedError(typ, message, opts...)
+}
+
+// RegisterErrorType maps an error type to an HTTP status and, optionally, a client-safe message
+func RegisterErrorType(typ ErrorType, info ErrorTypeInfo) error {
+	return util.RegisterErrorType(typ, info)
+}
+
+// RegisterLogHook registers a log hook by name so it can be enabled with logger.hooks
+func RegisterLogHook(name string, factory LogHookFactory) error {
+	return util.RegisterLogHook(name, factory)
+}
+
+// NewLogHook returns a hook that calls fire for entries at minLevel or more severe
+func NewLogHook(minLevel string, fire func(entry *LogEntry) error) (LogHook, error) {
+	return util.NewLogHook(minLevel, fire)
+}
+
+// RegisterLogFormatter registers a log formatter by name so it can be selected with logger.format
+func RegisterLogFormatter(name string, formatter LogFormatter) error {
+	return util.RegisterLogFormatter(name, formatter)
+}
+
+// LoadConfig loads configuration from files and environment variables
+func LoadConfig(opts ...LoadOption) (*Config, error) {
+	return config.Load(opts...)
+}
+
+// WithConfigPaths sets the directories LoadConfig searches for config files
+func WithConfigPaths(paths ...string) LoadOption {
+	return config.WithPaths(paths...)
+}
+
+// WithConfigDir sets the directory of per-environment config files (default "config")
+func WithConfigDir(dir string) LoadOption {
+	return config.WithConfigDir(dir)
+}
+
+// WithEnvPrefix sets the prefix for environment variable overrides
+func WithEnvPrefix(prefix string) LoadOption {
+	return config.WithEnvPrefix(prefix)
+}
+
+// WithConfigFile reads the base config from path instead of searching for one
+func WithConfigFile(path string) LoadOption {
+	return config.WithConfigFile(path)
+}
+
+// WithConfigOverrides sets config values by key, overriding every other source
+func WithConfigOverrides(overrides map[string]string) LoadOption {
+	return config.WithOverrides(overrides)
+}
+
+// RegisterConfigSection registers a strongly-typed config block for a plugin
+// or app code. Call it before LoadConfig (e.g., from an init function).
+func RegisterConfigSection(name string, target interface{}, defaults interface{}) error {
+	return config.RegisterSection(name, target, defaults)
+}
+
+// RegisterServerFactory registers how to create the server called name, so
+// New creates it unless server.<name>.enabled is false. Call it before
+// LoadConfig (e.g., from an init function).
+func RegisterServerFactory(name string, factory ServerFactory) error {
+	return api.RegisterServerFactory(name, factory)
+}
+
+// ConfigSection returns the loaded values of a registered config section
+func ConfigSection[T any](cfg *Config, name string) *T {
+	return config.GetSection[T](cfg, name)
+}
+
+// New creates an API instance from the loaded configuration with the given
+// actions registered and the servers enabled in the configuration attached
+// (the web server, and any registered with RegisterServerFactory)
+func New(cfg *Config, actions ...Action) (*API, error) {
+	logger := util.NewLogger(cfg.Logger)
+	apiInstance := api.New(cfg, logger)
+	initializers.Register(apiInstance)
+
+	for _, action := range actions {
+		if err := apiInstance.RegisterAction(action); err != nil {
+			return nil, err
+		}
+	}
+
+	apiInstance.RegisterConfiguredServers()
+	return apiInstance, nil
+}
+
+// Run loads configuration, starts an API with the given actions, and blocks
+// until SIGINT/SIGTERM, then shuts down gracefully
+func Run(actions ...Action) error {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	apiInstance, err := New(cfg, actions...)
+	if err != nil {
+		return err
+	}
+
+	if err := apiInstance.Initialize(); err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+	if err := apiInstance.Start(); err != nil {
+		return fmt.Errorf("failed to start: %w", err)
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	<-sigChan
+
+	apiInstance.Logger.Info("Shutting down gracefully...")
+	return apiInstance.Stop()
+}
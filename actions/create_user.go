@@ -26,33 +26,40 @@ type CreateUserAction struct {
 	api.BaseAction
 }
 
-// NewCreateUserAction creates and configures a new CreateUserAction
-func NewCreateUserAction() *CreateUserAction {
-	return &CreateUserAction{
+// NewCreateUserAction creates and configures a new CreateUserAction, adapted
+// to the Action interface
+func NewCreateUserAction() api.Action {
+	return api.NewTypedAction[CreateUserInput, CreateUserOutput](&CreateUserAction{
 		BaseAction: api.BaseAction{
 			ActionName:        "user:create",
 			ActionDescription: "Creates a new user",
-			ActionInputs:      CreateUserInput{},
 			ActionWeb: &api.WebConfig{
 				Route:  "/users",
 				Method: api.HTTPMethodPOST,
 			},
+			ActionDocs: &api.DocsConfig{
+				RequestExample: CreateUserInput{
+					Name:     "Mario",
+					Email:    "mario@example.com",
+					Password: "its-a-me!",
+				},
+				ResponseExample: CreateUserOutput{
+					Created: true,
+					UserID:  123,
+					Name:    "Mario",
+					Email:   "mario@example.com",
+				},
+			},
 		},
-	}
+	})
 }
 
 func init() {
 	Register(func() api.Action { return NewCreateUserAction() })
 }
 
-// Run executes the action with strong typing
-func (a *CreateUserAction) Run(ctx context.Context, params interface{}, conn *api.Connection) (interface{}, error) {
-	// Marshal params to strongly-typed input
-	var input CreateUserInput
-	if err := api.MarshalParams(params, &input); err != nil {
-		return nil, err
-	}
-
+// Run executes the action with typed input and output
+func (a *CreateUserAction) Run(ctx context.Context, input CreateUserInput, conn *api.Connection) (CreateUserOutput, error) {
 	// TODO: In a real implementation, this would:
 	// 1. Validate the input (email format, password strength, etc.)
 	// 2. Check if user already exists
@@ -28,6 +28,7 @@ func NewEchoAction() *EchoAction {
 			ActionName:        "echo",
 			ActionDescription: "Echoes back the parameters sent to it",
 			ActionInputs:      EchoInput{},
+			ActionOutputs:     EchoOutput{},
 			ActionWeb: &api.WebConfig{
 				Route:  "/echo/:message",
 				Method: api.HTTPMethodGET,
@@ -2,6 +2,7 @@ package actions
 
 import (
 	"context"
+	"runtime"
 	"time"
 
 	"github.com/evantahler/go-actionhero/internal/api"
@@ -12,9 +13,22 @@ type StatusInput struct{}
 
 // StatusOutput defines the output structure for the status action
 type StatusOutput struct {
-	Status    string `json:"status"`
-	Timestamp int64  `json:"timestamp"`
-	Uptime    string `json:"uptime"`
+	Status         string         `json:"status"`
+	Timestamp      int64          `json:"timestamp"`
+	StartedAt      int64          `json:"startedAt"`     // Unix time the API started (0 if not started)
+	Uptime         string         `json:"uptime"`        // Human-readable uptime, e.g. "1h2m3s"
+	UptimeSeconds  int64          `json:"uptimeSeconds"` // Uptime in whole seconds
+	Goroutines     int            `json:"goroutines"`
+	HeapAllocBytes uint64         `json:"heapAllocBytes"`
+	HeapSysBytes   uint64         `json:"heapSysBytes"`
+	Connections    map[string]int `json:"connections"` // Open connections per server
+	Actions        int            `json:"actions"`
+	Initializers   int            `json:"initializers"`
+	// Circuit breakers of downstream dependencies, by name
+	Breakers []api.BreakerStats `json:"breakers"`
+	// Latest results of the uptime checks, by URL; Status is "degraded"
+	// while any URL is down
+	Checks []api.UptimeCheck `json:"checks"`
 }
 
 // StatusAction returns the server status
@@ -29,6 +43,7 @@ func NewStatusAction() *StatusAction {
 			ActionName:        "status",
 			ActionDescription: "Return the status of the server",
 			ActionInputs:      StatusInput{},
+			ActionOutputs:     StatusOutput{},
 			ActionWeb: &api.WebConfig{
 				Route:  "/status",
 				Method: api.HTTPMethodGET,
@@ -49,10 +64,50 @@ func (a *StatusAction) Run(ctx context.Context, params interface{}, conn *api.Co
 		return nil, err
 	}
 
+	now := time.Now()
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	output := StatusOutput{
+		Status:         "ok",
+		Timestamp:      now.Unix(),
+		Uptime:         "0s",
+		Goroutines:     runtime.NumGoroutine(),
+		HeapAllocBytes: memStats.HeapAlloc,
+		HeapSysBytes:   memStats.HeapSys,
+		Connections:    map[string]int{},
+		Breakers:       []api.BreakerStats{},
+		Checks:         []api.UptimeCheck{},
+	}
+
+	apiInstance := api.APIFromContext(ctx)
+	if apiInstance == nil {
+		return output, nil
+	}
+
+	if startedAt := apiInstance.StartedAt(); !startedAt.IsZero() {
+		uptime := now.Sub(startedAt).Truncate(time.Second)
+		output.StartedAt = startedAt.Unix()
+		output.Uptime = uptime.String()
+		output.UptimeSeconds = int64(uptime.Seconds())
+	}
+
+	for _, server := range apiInstance.GetServers() {
+		if counter, ok := server.(api.ConnectionCounter); ok {
+			output.Connections[server.Name()] = counter.ConnectionCount()
+		}
+	}
+
+	output.Actions = len(apiInstance.GetActions())
+	output.Initializers = len(apiInstance.GetInitializers())
+	output.Breakers = apiInstance.BreakerStats()
+	output.Checks = apiInstance.UptimeChecks()
+	for _, check := range output.Checks {
+		if check.CheckedAt != 0 && !check.Up {
+			output.Status = "degraded"
+		}
+	}
+
 	// Return strongly-typed output
-	return StatusOutput{
-		Status:    "ok",
-		Timestamp: time.Now().Unix(),
-		Uptime:    "running",
-	}, nil
+	return output, nil
 }
@@ -0,0 +1,63 @@
+package actions
+
+import (
+	"context"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+)
+
+// StatusDetailedInput defines the input for the status:detailed action (no inputs required)
+type StatusDetailedInput struct{}
+
+// StatusDetailedOutput defines the output structure for the status:detailed action
+type StatusDetailedOutput struct {
+	Status    string                       `json:"status"`
+	Timestamp int64                        `json:"timestamp"`
+	Actions   map[string]api.ActionMetrics `json:"actions"`
+}
+
+// StatusDetailedAction returns the server status along with per-action
+// latency percentiles and success/error rates
+type StatusDetailedAction struct {
+	api.BaseAction
+}
+
+// NewStatusDetailedAction creates and configures a new StatusDetailedAction
+func NewStatusDetailedAction() *StatusDetailedAction {
+	return &StatusDetailedAction{
+		BaseAction: api.BaseAction{
+			ActionName:        "status:detailed",
+			ActionDescription: "Return the status of the server with per-action latency and error metrics",
+			ActionInputs:      StatusDetailedInput{},
+			ActionOutputs:     StatusDetailedOutput{},
+			ActionWeb: &api.WebConfig{
+				Route:  "/status/detailed",
+				Method: api.HTTPMethodGET,
+			},
+		},
+	}
+}
+
+func init() {
+	Register(func() api.Action { return NewStatusDetailedAction() })
+}
+
+// Run executes the action with strong typing
+func (a *StatusDetailedAction) Run(ctx context.Context, params interface{}, conn *api.Connection) (interface{}, error) {
+	var input StatusDetailedInput
+	if err := api.MarshalParams(params, &input); err != nil {
+		return nil, err
+	}
+
+	metrics := map[string]api.ActionMetrics{}
+	if apiInstance := api.APIFromContext(ctx); apiInstance != nil {
+		metrics = apiInstance.Metrics.Snapshot()
+	}
+
+	return StatusDetailedOutput{
+		Status:    "ok",
+		Timestamp: time.Now().Unix(),
+		Actions:   metrics,
+	}, nil
+}
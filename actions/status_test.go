@@ -0,0 +1,117 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// countingServer is a server that reports a fixed number of open connections
+type countingServer struct {
+	name  string
+	count int
+}
+
+func (s *countingServer) Name() string         { return s.name }
+func (s *countingServer) Initialize() error    { return nil }
+func (s *countingServer) Start() error         { return nil }
+func (s *countingServer) Stop() error          { return nil }
+func (s *countingServer) ConnectionCount() int { return s.count }
+
+func TestStatusAction_RuntimeStats(t *testing.T) {
+	logger := util.NewLogger(config.LoggerConfig{Level: "error"})
+	apiInstance := api.New(&config.Config{}, logger)
+
+	if err := apiInstance.RegisterAction(NewStatusAction()); err != nil {
+		t.Fatalf("Failed to register status action: %v", err)
+	}
+	if err := apiInstance.RegisterAction(NewEchoAction()); err != nil {
+		t.Fatalf("Failed to register echo action: %v", err)
+	}
+	apiInstance.RegisterServer(&countingServer{name: "web", count: 3})
+	apiInstance.Breaker("payments").Failure()
+
+	if err := apiInstance.Start(); err != nil {
+		t.Fatalf("Failed to start API: %v", err)
+	}
+	defer func() { _ = apiInstance.Stop() }()
+
+	conn := api.NewConnection("test", "127.0.0.1", "status-test", nil)
+	result := conn.Act(context.Background(), apiInstance, "status", nil, "GET", "")
+	if result.Error != nil {
+		t.Fatalf("Status action failed: %v", result.Error)
+	}
+
+	output, ok := result.Response.(StatusOutput)
+	if !ok {
+		t.Fatalf("Expected StatusOutput, got %T", result.Response)
+	}
+
+	if output.Status != "ok" {
+		t.Errorf("Expected status 'ok', got %q", output.Status)
+	}
+	if output.StartedAt != apiInstance.StartedAt().Unix() {
+		t.Errorf("Expected startedAt %d, got %d", apiInstance.StartedAt().Unix(), output.StartedAt)
+	}
+	if output.Uptime == "running" || output.Uptime == "" {
+		t.Errorf("Expected a real uptime, got %q", output.Uptime)
+	}
+	if output.Goroutines <= 0 {
+		t.Errorf("Expected goroutine count, got %d", output.Goroutines)
+	}
+	if output.HeapAllocBytes == 0 {
+		t.Error("Expected heap usage to be reported")
+	}
+	if output.Connections["web"] != 3 {
+		t.Errorf("Expected 3 web connections, got %v", output.Connections)
+	}
+	if output.Actions != 2 {
+		t.Errorf("Expected 2 actions, got %d", output.Actions)
+	}
+	if output.Initializers != 0 {
+		t.Errorf("Expected 0 initializers, got %d", output.Initializers)
+	}
+	if len(output.Breakers) != 1 || output.Breakers[0].Name != "payments" || output.Breakers[0].Failures != 1 {
+		t.Errorf("Expected the payments breaker with 1 failure, got %+v", output.Breakers)
+	}
+}
+
+// fixedChecks is an uptime checker with fixed results
+type fixedChecks []api.UptimeCheck
+
+func (c fixedChecks) UptimeChecks() []api.UptimeCheck { return c }
+
+func TestStatusAction_UptimeChecks(t *testing.T) {
+	logger := util.NewLogger(config.LoggerConfig{Level: "error"})
+	apiInstance := api.New(&config.Config{}, logger)
+	if err := apiInstance.RegisterAction(NewStatusAction()); err != nil {
+		t.Fatalf("Failed to register status action: %v", err)
+	}
+
+	run := func() StatusOutput {
+		conn := api.NewConnection("test", "127.0.0.1", "status-test", nil)
+		result := conn.Act(context.Background(), apiInstance, "status", nil, "GET", "")
+		if result.Error != nil {
+			t.Fatalf("Status action failed: %v", result.Error)
+		}
+		return result.Response.(StatusOutput)
+	}
+
+	api.Provide[api.UptimeChecker](apiInstance, fixedChecks{
+		{URL: "http://db.internal/health", Up: true, CheckedAt: 1},
+		{URL: "http://search.internal/health"}, // Not checked yet
+	})
+	if output := run(); output.Status != "ok" || len(output.Checks) != 2 {
+		t.Errorf("Expected ok with 2 checks, got %q with %+v", output.Status, output.Checks)
+	}
+
+	api.Provide[api.UptimeChecker](apiInstance, fixedChecks{
+		{URL: "http://db.internal/health", Up: false, CheckedAt: 1, Error: "responded 503 Service Unavailable"},
+	})
+	if output := run(); output.Status != "degraded" {
+		t.Errorf("Expected degraded while a check is down, got %q", output.Status)
+	}
+}
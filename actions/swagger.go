@@ -2,19 +2,45 @@ package actions
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"reflect"
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/openapi"
+	"github.com/evantahler/go-actionhero/internal/util"
+	"go.yaml.in/yaml/v3"
 )
 
-const swaggerVersion = "3.0.0"
+// Swagger document formats
+const (
+	swaggerFormatJSON = "json"
+	swaggerFormatYAML = "yaml"
+)
+
+// SwaggerInput defines the input for the swagger action
+type SwaggerInput struct {
+	Format string `json:"format" validate:"omitempty,oneof=json yaml"`
+}
 
 // SwaggerAction returns API documentation in OpenAPI format
 type SwaggerAction struct {
 	api.BaseAction
+
+	// The document is built once and reused until the registered actions change
+	cacheMu sync.Mutex
+	cache   *swaggerCache
+}
+
+// swaggerCache holds a built document and the API state it was built from
+type swaggerCache struct {
+	api      *api.API
+	revision uint64
+	document map[string]interface{}
+	yaml     []byte
 }
 
 // NewSwaggerAction creates and configures a new SwaggerAction
@@ -23,6 +49,7 @@ func NewSwaggerAction() *SwaggerAction {
 		BaseAction: api.BaseAction{
 			ActionName:        "swagger",
 			ActionDescription: "Return API documentation in the OpenAPI specification",
+			ActionInputs:      SwaggerInput{},
 			ActionWeb: &api.WebConfig{
 				Route:  "/swagger",
 				Method: api.HTTPMethodGET,
@@ -47,14 +74,88 @@ func (a *SwaggerAction) Run(ctx context.Context, params interface{}, conn *api.C
 		return nil, fmt.Errorf("config not found in context")
 	}
 
+	var input SwaggerInput
+	if err := api.MarshalParams(params, &input); err != nil {
+		return nil, err
+	}
+
+	cache := a.cachedDocument(apiInstance, cfg)
+
+	switch strings.ToLower(input.Format) {
+	case "", swaggerFormatJSON:
+		return cache.document, nil
+	case swaggerFormatYAML, "yml":
+		return &api.RawResponse{ContentType: "application/yaml", Body: cache.yaml}, nil
+	default:
+		return nil, util.NewTypedError(
+			util.ErrorTypeConnectionActionParamValidation,
+			fmt.Sprintf("format must be %s or %s", swaggerFormatJSON, swaggerFormatYAML),
+			util.WithKey("format"),
+			util.WithValue(input.Format),
+		)
+	}
+}
+
+// cachedDocument returns the OpenAPI document, rebuilding it only when the
+// registered actions have changed since it was last built
+func (a *SwaggerAction) cachedDocument(apiInstance *api.API, cfg *config.Config) *swaggerCache {
+	a.cacheMu.Lock()
+	defer a.cacheMu.Unlock()
+
+	revision := apiInstance.ActionsRevision()
+	if a.cache != nil && a.cache.api == apiInstance && a.cache.revision == revision {
+		return a.cache
+	}
+
+	document := BuildSwaggerDocument(apiInstance, cfg)
+	yamlData, err := MarshalSwaggerYAML(document)
+	if err != nil {
+		// The document is built from plain maps, so this should not happen
+		apiInstance.Logger.Errorf("Failed to encode OpenAPI document as YAML: %v", err)
+	}
+
+	a.cache = &swaggerCache{
+		api:      apiInstance,
+		revision: revision,
+		document: document,
+		yaml:     yamlData,
+	}
+	return a.cache
+}
+
+// MarshalSwaggerYAML encodes an OpenAPI document as YAML
+func MarshalSwaggerYAML(document map[string]interface{}) ([]byte, error) {
+	// Round-trip through JSON so YAML uses the same field names
+	jsonData, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal document: %w", err)
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(jsonData, &generic); err != nil {
+		return nil, fmt.Errorf("failed to convert document: %w", err)
+	}
+	return yaml.Marshal(generic)
+}
+
+// BuildSwaggerDocument builds the OpenAPI document for all web-enabled actions
+// registered with the API. It does not require a running server, so it can be
+// used to generate the spec offline (e.g., `actionhero swagger export`).
+func BuildSwaggerDocument(apiInstance *api.API, cfg *config.Config) map[string]interface{} {
 	paths := make(map[string]interface{})
 	components := map[string]interface{}{
 		"schemas": make(map[string]interface{}),
 	}
 
-	actions := apiInstance.GetActions()
-	for _, action := range actions {
-		webConfig := api.GetActionWeb(action)
+	// Shadowed actions aren't served, so they aren't documented either. Route
+	// conflicts fail initialization; document every action if there are any.
+	webActions, err := api.WebRoutes(apiInstance.GetActionDescriptors())
+	if err != nil {
+		webActions = apiInstance.GetActionDescriptors()
+	}
+
+	for _, action := range webActions {
+		webConfig := action.Web
 		if webConfig == nil || webConfig.Route == "" {
 			continue
 		}
@@ -62,9 +163,9 @@ func (a *SwaggerAction) Run(ctx context.Context, params interface{}, conn *api.C
 		// Convert :param format to OpenAPI {param} format
 		path := convertRouteToSwagger(webConfig.Route)
 		method := strings.ToLower(string(webConfig.Method))
-		actionName := api.GetActionName(action)
+		actionName := action.Name
 		tag := strings.Split(actionName, ":")[0]
-		summary := api.GetActionDescription(action)
+		summary := action.Description
 		if summary == "" {
 			summary = actionName
 		}
@@ -72,21 +173,26 @@ func (a *SwaggerAction) Run(ctx context.Context, params interface{}, conn *api.C
 		// Extract path parameters
 		pathParams := extractPathParameters(webConfig.Route)
 
-		// Build request body for non-GET/HEAD methods with inputs
+		// GET/HEAD inputs are read from the query string; others from the body
 		var requestBody interface{}
-		inputs := api.GetActionInputs(action)
-		if inputs != nil && method != "get" && method != "head" {
+		inputs := action.Inputs
+		if inputs != nil && (method == "get" || method == "head") {
+			pathParams = append(pathParams, openapi.QueryParameters(inputs, pathParams)...)
+		} else if inputs != nil {
 			schemaName := strings.ReplaceAll(actionName, ":", "_") + "_Request"
-			schema := buildSchemaFromStruct(inputs)
+			schema := openapi.SchemaFromStruct(inputs)
 			components["schemas"].(map[string]interface{})[schemaName] = schema
 
+			// The web server accepts both JSON and URL-encoded form bodies
+			schemaRef := map[string]interface{}{"$ref": "#/components/schemas/" + schemaName}
 			requestBody = map[string]interface{}{
 				"required": true,
 				"content": map[string]interface{}{
 					"application/json": map[string]interface{}{
-						"schema": map[string]interface{}{
-							"$ref": "#/components/schemas/" + schemaName,
-						},
+						"schema": schemaRef,
+					},
+					"application/x-www-form-urlencoded": map[string]interface{}{
+						"schema": schemaRef,
 					},
 				},
 			}
@@ -97,10 +203,18 @@ func (a *SwaggerAction) Run(ctx context.Context, params interface{}, conn *api.C
 			paths[path] = make(map[string]interface{})
 		}
 
+		// Build the response schema from the action's outputs, if declared
+		var responseSchema map[string]interface{}
+		if outputs := action.Outputs; outputs != nil {
+			schemaName := strings.ReplaceAll(actionName, ":", "_") + "_Response"
+			components["schemas"].(map[string]interface{})[schemaName] = openapi.SchemaFromStruct(outputs)
+			responseSchema = map[string]interface{}{"$ref": "#/components/schemas/" + schemaName}
+		}
+
 		operation := map[string]interface{}{
 			"summary":   summary,
 			"tags":      []string{tag},
-			"responses": buildSwaggerResponses(),
+			"responses": buildSwaggerResponses(responseSchema),
 		}
 
 		if len(pathParams) > 0 {
@@ -111,30 +225,166 @@ func (a *SwaggerAction) Run(ctx context.Context, params interface{}, conn *api.C
 			operation["requestBody"] = requestBody
 		}
 
+		applyActionDocs(operation, actionName, action.Docs)
+		if action.Deprecation != nil {
+			operation["deprecated"] = true
+			operation["description"] = action.Deprecation.Notice(actionName)
+		}
+
+		// Document authentication enforced by the action's middleware
+		if schemes := api.SecuritySchemes(action.Middleware); len(schemes) > 0 {
+			securitySchemes, ok := components["securitySchemes"].(map[string]interface{})
+			if !ok {
+				securitySchemes = make(map[string]interface{})
+				components["securitySchemes"] = securitySchemes
+			}
+
+			// Every middleware runs, so all schemes are required together
+			requirement := make(map[string][]string, len(schemes))
+			for _, scheme := range schemes {
+				securitySchemes[scheme.Name] = buildSecurityScheme(scheme)
+				requirement[scheme.Name] = []string{}
+			}
+			operation["security"] = []map[string][]string{requirement}
+		}
+
 		paths[path].(map[string]interface{})[method] = operation
 	}
 
+	version := cfg.Server.Web.OpenAPIVersion
+	if version == "" {
+		version = config.OpenAPIVersion30
+	}
+	if version == config.OpenAPIVersion31 {
+		upgradeSchemasTo31(paths, components)
+	}
+
 	document := map[string]interface{}{
-		"openapi": swaggerVersion,
-		"info": map[string]interface{}{
-			"version":     "1.0.0",
-			"title":       cfg.Process.Name,
-			"description": "Go ActionHero API Server",
-			"license": map[string]string{
-				"name": "MIT",
-			},
+		"openapi":    version,
+		"info":       buildSwaggerInfo(cfg),
+		"servers":    buildSwaggerServers(cfg),
+		"paths":      paths,
+		"components": components,
+	}
+
+	return document
+}
+
+// buildSwaggerInfo builds the document's info section from the OpenAPI config
+func buildSwaggerInfo(cfg *config.Config) map[string]interface{} {
+	// Unset values fall back to the defaults
+	defaults := config.DefaultOpenAPIConfig()
+	orDefault := func(value, fallback string) string {
+		if value == "" {
+			return fallback
+		}
+		return value
+	}
+
+	info := map[string]interface{}{
+		"title":       orDefault(cfg.OpenAPI.Title, cfg.Process.Name),
+		"version":     orDefault(cfg.OpenAPI.Version, defaults.Version),
+		"description": orDefault(cfg.OpenAPI.Description, defaults.Description),
+		"license": map[string]string{
+			"name": orDefault(cfg.OpenAPI.License, defaults.License),
 		},
-		"servers": []map[string]string{
+	}
+	if cfg.OpenAPI.LicenseURL != "" {
+		info["license"].(map[string]string)["url"] = cfg.OpenAPI.LicenseURL
+	}
+
+	contact := map[string]string{}
+	if cfg.OpenAPI.ContactName != "" {
+		contact["name"] = cfg.OpenAPI.ContactName
+	}
+	if cfg.OpenAPI.ContactEmail != "" {
+		contact["email"] = cfg.OpenAPI.ContactEmail
+	}
+	if cfg.OpenAPI.ContactURL != "" {
+		contact["url"] = cfg.OpenAPI.ContactURL
+	}
+	if len(contact) > 0 {
+		info["contact"] = contact
+	}
+
+	return info
+}
+
+// buildSwaggerServers builds the document's servers section, falling back to
+// the web server's own address when no server URLs are configured
+func buildSwaggerServers(cfg *config.Config) []map[string]string {
+	urls := cfg.OpenAPI.ServerURLs()
+	if len(urls) == 0 {
+		return []map[string]string{
 			{
 				"url":         fmt.Sprintf("http://%s:%d", cfg.Server.Web.Host, cfg.Server.Web.Port),
 				"description": "API Server",
 			},
-		},
-		"paths":      paths,
-		"components": components,
+		}
+	}
+
+	servers := make([]map[string]string, 0, len(urls))
+	for _, url := range urls {
+		servers = append(servers, map[string]string{"url": url})
+	}
+	return servers
+}
+
+// applyActionDocs adds the operationId and any per-action OpenAPI metadata
+// (tags, deprecation, examples) to an operation
+func applyActionDocs(operation map[string]interface{}, actionName string, docs *api.DocsConfig) {
+	operation["operationId"] = operationIDFromName(actionName)
+	if docs == nil {
+		return
+	}
+
+	if docs.OperationID != "" {
+		operation["operationId"] = docs.OperationID
+	}
+	if len(docs.Tags) > 0 {
+		operation["tags"] = docs.Tags
+	}
+	if docs.Deprecated {
+		operation["deprecated"] = true
 	}
 
-	return document, nil
+	if docs.RequestExample != nil {
+		if requestBody, ok := operation["requestBody"].(map[string]interface{}); ok {
+			content := requestBody["content"].(map[string]interface{})["application/json"].(map[string]interface{})
+			content["example"] = docs.RequestExample
+		} else if params, ok := operation["parameters"].([]map[string]interface{}); ok {
+			// GET/HEAD params: attach each example value to its parameter
+			var example map[string]interface{}
+			if err := api.MarshalParams(docs.RequestExample, &example); err == nil {
+				for _, param := range params {
+					if value, ok := example[param["name"].(string)]; ok {
+						param["example"] = value
+					}
+				}
+			}
+		}
+	}
+
+	if docs.ResponseExample != nil {
+		responses := operation["responses"].(map[string]interface{})
+		content := responses["200"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})
+		content["example"] = map[string]interface{}{
+			"success": true,
+			"data":    docs.ResponseExample,
+		}
+	}
+}
+
+// operationIDFromName derives an operationId from an action name
+// (e.g., "user:create" becomes "userCreate")
+func operationIDFromName(actionName string) string {
+	parts := strings.FieldsFunc(actionName, func(r rune) bool {
+		return r == ':' || r == '-' || r == '_' || r == '.' || r == '/'
+	})
+	for i := 1; i < len(parts); i++ {
+		parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+	}
+	return strings.Join(parts, "")
 }
 
 // convertRouteToSwagger converts :param format to {param} format
@@ -167,98 +417,88 @@ func extractPathParameters(route string) []map[string]interface{} {
 	return params
 }
 
-// buildSchemaFromStruct builds an OpenAPI schema from a Go struct
-func buildSchemaFromStruct(input interface{}) map[string]interface{} {
-	schema := map[string]interface{}{
-		"type":       "object",
-		"properties": make(map[string]interface{}),
+// upgradeSchemasTo31 rewrites OpenAPI 3.0 schema keywords in place to their
+// OpenAPI 3.1 (JSON Schema 2020-12) equivalents
+func upgradeSchemasTo31(paths, components map[string]interface{}) {
+	for _, schema := range components["schemas"].(map[string]interface{}) {
+		upgradeSchemaTo31(schema.(map[string]interface{}))
 	}
 
-	required := make([]string, 0)
-	properties := schema["properties"].(map[string]interface{})
-
-	inputType := reflect.TypeOf(input)
-	if inputType.Kind() == reflect.Ptr {
-		inputType = inputType.Elem()
-	}
-
-	if inputType.Kind() != reflect.Struct {
-		return schema
+	for _, operations := range paths {
+		for _, operation := range operations.(map[string]interface{}) {
+			params, _ := operation.(map[string]interface{})["parameters"].([]map[string]interface{})
+			for _, param := range params {
+				if schema, ok := param["schema"].(map[string]interface{}); ok {
+					upgradeSchemaTo31(schema)
+				}
+			}
+		}
 	}
+}
 
-	for i := 0; i < inputType.NumField(); i++ {
-		field := inputType.Field(i)
-		jsonTag := field.Tag.Get("json")
-		if jsonTag == "" || jsonTag == "-" {
-			continue
+// upgradeSchemaTo31 converts a single schema (and its nested schemas) to OpenAPI 3.1
+func upgradeSchemaTo31(schema map[string]interface{}) {
+	if nullable, _ := schema["nullable"].(bool); nullable {
+		delete(schema, "nullable")
+		if t, ok := schema["type"]; ok {
+			schema["type"] = []interface{}{t, "null"}
 		}
+	}
 
-		// Parse json tag (might have options like "name,omitempty")
-		fieldName := strings.Split(jsonTag, ",")[0]
-
-		// Determine field type
-		fieldSchema := map[string]interface{}{
-			"type": getJSONType(field.Type),
-		}
+	// exclusiveMinimum/exclusiveMaximum are numbers rather than boolean flags
+	if exclusive, _ := schema["exclusiveMinimum"].(bool); exclusive {
+		schema["exclusiveMinimum"] = schema["minimum"]
+		delete(schema, "minimum")
+	}
+	if exclusive, _ := schema["exclusiveMaximum"].(bool); exclusive {
+		schema["exclusiveMaximum"] = schema["maximum"]
+		delete(schema, "maximum")
+	}
 
-		// Check if required
-		validateTag := field.Tag.Get("validate")
-		if strings.Contains(validateTag, "required") {
-			required = append(required, fieldName)
-		}
+	if schema["format"] == "byte" {
+		delete(schema, "format")
+		schema["contentEncoding"] = "base64"
+	}
 
-		// Add min/max constraints for strings
-		if field.Type.Kind() == reflect.String && validateTag != "" {
-			if strings.Contains(validateTag, "min=") {
-				minRe := regexp.MustCompile(`min=(\d+)`)
-				if matches := minRe.FindStringSubmatch(validateTag); len(matches) > 1 {
-					fieldSchema["minLength"] = matches[1]
-				}
-			}
-			if strings.Contains(validateTag, "max=") {
-				maxRe := regexp.MustCompile(`max=(\d+)`)
-				if matches := maxRe.FindStringSubmatch(validateTag); len(matches) > 1 {
-					fieldSchema["maxLength"] = matches[1]
-				}
-			}
-			if strings.Contains(validateTag, "email") {
-				fieldSchema["format"] = "email"
-			}
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		for _, property := range properties {
+			upgradeSchemaTo31(property.(map[string]interface{}))
 		}
-
-		properties[fieldName] = fieldSchema
 	}
-
-	if len(required) > 0 {
-		schema["required"] = required
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		upgradeSchemaTo31(items)
+	}
+	if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+		upgradeSchemaTo31(additional)
 	}
-
-	return schema
 }
 
-// getJSONType converts Go type to JSON schema type
-func getJSONType(t reflect.Type) string {
-	switch t.Kind() {
-	case reflect.String:
-		return "string"
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return "integer"
-	case reflect.Float32, reflect.Float64:
-		return "number"
-	case reflect.Bool:
-		return "boolean"
-	case reflect.Array, reflect.Slice:
-		return "array"
-	case reflect.Map, reflect.Struct:
-		return "object"
-	default:
-		return "string"
+// buildSecurityScheme converts a middleware's security scheme to an OpenAPI security scheme object
+func buildSecurityScheme(scheme api.SecurityScheme) map[string]interface{} {
+	result := map[string]interface{}{
+		"type": scheme.Type,
+	}
+	if scheme.Description != "" {
+		result["description"] = scheme.Description
+	}
+
+	switch scheme.Type {
+	case api.SecuritySchemeHTTP:
+		result["scheme"] = scheme.Scheme
+		if scheme.BearerFormat != "" {
+			result["bearerFormat"] = scheme.BearerFormat
+		}
+	case api.SecuritySchemeAPIKey:
+		result["in"] = scheme.In
+		result["name"] = scheme.ParamName
 	}
+
+	return result
 }
 
-// buildSwaggerResponses builds standard OpenAPI response definitions
-func buildSwaggerResponses() map[string]interface{} {
+// buildSwaggerResponses builds standard OpenAPI response definitions.
+// dataSchema describes the action's response data; nil leaves it untyped.
+func buildSwaggerResponses(dataSchema map[string]interface{}) map[string]interface{} {
 	errorSchema := map[string]interface{}{
 		"type": "object",
 		"properties": map[string]interface{}{
@@ -266,12 +506,24 @@ func buildSwaggerResponses() map[string]interface{} {
 		},
 	}
 
+	// Successful responses are wrapped by the web server as {"success": true, "data": ...}
+	successSchema := map[string]interface{}{}
+	if dataSchema != nil {
+		successSchema = map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"success": map[string]string{"type": "boolean"},
+				"data":    dataSchema,
+			},
+		}
+	}
+
 	return map[string]interface{}{
 		"200": map[string]interface{}{
 			"description": "successful operation",
 			"content": map[string]interface{}{
 				"application/json": map[string]interface{}{
-					"schema": map[string]interface{}{},
+					"schema": successSchema,
 				},
 			},
 		},
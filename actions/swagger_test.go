@@ -2,7 +2,10 @@ package actions
 
 import (
 	"context"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/evantahler/go-actionhero/internal/api"
 	"github.com/evantahler/go-actionhero/internal/config"
@@ -42,9 +45,7 @@ func TestSwaggerAction_ValidOpenAPIStructure(t *testing.T) {
 	}
 
 	// Create context with API and config
-	ctx := context.Background()
-	ctx = context.WithValue(ctx, api.ContextKeyAPI, apiInstance)
-	ctx = context.WithValue(ctx, api.ContextKeyConfig, cfg)
+	ctx := api.WithAPI(context.Background(), apiInstance)
 
 	// Create connection
 	conn := api.NewConnection("test", "127.0.0.1", "test-id", nil)
@@ -125,9 +126,7 @@ func TestSwaggerAction_DocumentsAllActions(t *testing.T) {
 	}
 
 	// Create context
-	ctx := context.Background()
-	ctx = context.WithValue(ctx, api.ContextKeyAPI, apiInstance)
-	ctx = context.WithValue(ctx, api.ContextKeyConfig, cfg)
+	ctx := api.WithAPI(context.Background(), apiInstance)
 
 	// Execute swagger action
 	conn := api.NewConnection("test", "127.0.0.1", "test-id", nil)
@@ -190,9 +189,7 @@ func TestSwaggerAction_PathParameters(t *testing.T) {
 	}
 
 	// Create context
-	ctx := context.Background()
-	ctx = context.WithValue(ctx, api.ContextKeyAPI, apiInstance)
-	ctx = context.WithValue(ctx, api.ContextKeyConfig, cfg)
+	ctx := api.WithAPI(context.Background(), apiInstance)
 
 	// Execute swagger action
 	conn := api.NewConnection("test", "127.0.0.1", "test-id", nil)
@@ -250,9 +247,7 @@ func TestSwaggerAction_RequestBodySchemas(t *testing.T) {
 	}
 
 	// Create context
-	ctx := context.Background()
-	ctx = context.WithValue(ctx, api.ContextKeyAPI, apiInstance)
-	ctx = context.WithValue(ctx, api.ContextKeyConfig, cfg)
+	ctx := api.WithAPI(context.Background(), apiInstance)
 
 	// Execute swagger action
 	conn := api.NewConnection("test", "127.0.0.1", "test-id", nil)
@@ -363,9 +358,7 @@ func TestSwaggerAction_StandardResponseCodes(t *testing.T) {
 	}
 
 	// Create context
-	ctx := context.Background()
-	ctx = context.WithValue(ctx, api.ContextKeyAPI, apiInstance)
-	ctx = context.WithValue(ctx, api.ContextKeyConfig, cfg)
+	ctx := api.WithAPI(context.Background(), apiInstance)
 
 	// Execute swagger action
 	conn := api.NewConnection("test", "127.0.0.1", "test-id", nil)
@@ -472,3 +465,577 @@ func TestSwaggerAction_MissingConfigInContext(t *testing.T) {
 		t.Errorf("Expected specific error message, got '%v'", err)
 	}
 }
+
+func TestSwaggerAction_ResponseSchemas(t *testing.T) {
+	cfg := &config.Config{
+		Process: config.ProcessConfig{Name: "test-server"},
+		Server:  config.ServerConfig{Web: config.WebServerConfig{Host: "localhost", Port: 8080}},
+	}
+	logger := util.NewLogger(config.LoggerConfig{Level: "error"})
+	apiInstance := api.New(cfg, logger)
+
+	if err := apiInstance.RegisterAction(NewCreateUserAction()); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	if err := apiInstance.RegisterAction(NewStatusDetailedAction()); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	if err := apiInstance.RegisterAction(NewSwaggerAction()); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	doc := BuildSwaggerDocument(apiInstance, cfg)
+	paths := doc["paths"].(map[string]interface{})
+	schemas := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+
+	// The 200 response wraps the action's output schema in the web server's envelope
+	usersPost := paths["/users"].(map[string]interface{})["post"].(map[string]interface{})
+	ok200 := usersPost["responses"].(map[string]interface{})["200"].(map[string]interface{})
+	schema := ok200["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})
+	properties, ok := schema["properties"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected 200 response schema to have properties")
+	}
+	data := properties["data"].(map[string]interface{})
+	if data["$ref"] != "#/components/schemas/user_create_Response" {
+		t.Errorf("Expected data to reference user_create_Response, got %v", data["$ref"])
+	}
+
+	userResponse, ok := schemas["user_create_Response"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected user_create_Response schema to be in components")
+	}
+	userProps := userResponse["properties"].(map[string]interface{})
+	if userProps["userId"].(map[string]interface{})["type"] != "integer" {
+		t.Error("Expected userId to be an integer")
+	}
+	if userProps["created"].(map[string]interface{})["type"] != "boolean" {
+		t.Error("Expected created to be a boolean")
+	}
+
+	// Nested maps of structs are described with additionalProperties
+	detailed := schemas["status_detailed_Response"].(map[string]interface{})
+	actionsProp := detailed["properties"].(map[string]interface{})["actions"].(map[string]interface{})
+	if actionsProp["type"] != "object" {
+		t.Errorf("Expected actions to be an object, got %v", actionsProp["type"])
+	}
+	metrics, ok := actionsProp["additionalProperties"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected actions to have additionalProperties")
+	}
+	if metrics["properties"].(map[string]interface{})["p99Ms"] == nil {
+		t.Error("Expected nested metrics schema to include p99Ms")
+	}
+
+	// Actions without outputs keep an untyped response
+	swaggerGet := paths["/swagger"].(map[string]interface{})["get"].(map[string]interface{})
+	swagger200 := swaggerGet["responses"].(map[string]interface{})["200"].(map[string]interface{})
+	swaggerSchema := swagger200["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})
+	if len(swaggerSchema) != 0 {
+		t.Errorf("Expected empty schema for actions without outputs, got %v", swaggerSchema)
+	}
+}
+
+type searchInput struct {
+	ID    string   `json:"id"`
+	Query string   `json:"q" validate:"required,min=2"`
+	Limit int      `json:"limit"`
+	Tags  []string `json:"tags"`
+}
+
+type searchAction struct {
+	api.BaseAction
+}
+
+func (a *searchAction) Run(ctx context.Context, params interface{}, conn *api.Connection) (interface{}, error) {
+	return nil, nil
+}
+
+func TestSwaggerAction_QueryParameters(t *testing.T) {
+	cfg := &config.Config{
+		Process: config.ProcessConfig{Name: "test-server"},
+		Server:  config.ServerConfig{Web: config.WebServerConfig{Host: "localhost", Port: 8080}},
+	}
+	logger := util.NewLogger(config.LoggerConfig{Level: "error"})
+	apiInstance := api.New(cfg, logger)
+
+	if err := apiInstance.RegisterAction(&searchAction{BaseAction: api.BaseAction{
+		ActionName:   "search",
+		ActionInputs: searchInput{},
+		ActionWeb:    &api.WebConfig{Route: "/search/:id", Method: api.HTTPMethodGET},
+	}}); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	doc := BuildSwaggerDocument(apiInstance, cfg)
+	paths := doc["paths"].(map[string]interface{})
+	searchGet := paths["/search/{id}"].(map[string]interface{})["get"].(map[string]interface{})
+
+	if _, ok := searchGet["requestBody"]; ok {
+		t.Error("Expected GET action not to have a request body")
+	}
+
+	parameters := searchGet["parameters"].([]map[string]interface{})
+	byName := make(map[string]map[string]interface{})
+	for _, param := range parameters {
+		byName[param["name"].(string)] = param
+	}
+
+	if len(parameters) != 4 {
+		t.Fatalf("Expected 4 parameters (1 path + 3 query), got %d", len(parameters))
+	}
+	if byName["id"]["in"] != "path" {
+		t.Errorf("Expected id to be a path parameter, got %v", byName["id"]["in"])
+	}
+
+	q := byName["q"]
+	if q["in"] != "query" {
+		t.Errorf("Expected q to be a query parameter, got %v", q["in"])
+	}
+	if q["required"] != true {
+		t.Error("Expected q to be required")
+	}
+	if q["schema"].(map[string]interface{})["minLength"] != 2 {
+		t.Errorf("Expected q minLength 2, got %v", q["schema"].(map[string]interface{})["minLength"])
+	}
+
+	limit := byName["limit"]
+	if limit["required"] != false {
+		t.Error("Expected limit to be optional")
+	}
+	if limit["schema"].(map[string]interface{})["type"] != "integer" {
+		t.Errorf("Expected limit to be an integer, got %v", limit["schema"])
+	}
+
+	tags := byName["tags"]
+	tagsSchema := tags["schema"].(map[string]interface{})
+	if tagsSchema["type"] != "array" || tagsSchema["items"].(map[string]interface{})["type"] != stringType {
+		t.Errorf("Expected tags to be an array of strings, got %v", tagsSchema)
+	}
+	if tags["explode"] != true {
+		t.Error("Expected array query parameter to be exploded")
+	}
+}
+
+type bearerMiddleware struct{}
+
+func (m *bearerMiddleware) RunBefore(params interface{}, conn *api.Connection) (*api.MiddlewareResponse, error) {
+	return nil, nil
+}
+
+func (m *bearerMiddleware) RunAfter(params interface{}, conn *api.Connection) (*api.MiddlewareResponse, error) {
+	return nil, nil
+}
+
+func (m *bearerMiddleware) SecurityScheme() api.SecurityScheme {
+	return api.SecurityScheme{Name: "bearerAuth", Type: api.SecuritySchemeHTTP, Scheme: "bearer", BearerFormat: "JWT"}
+}
+
+type apiKeyMiddleware struct{ bearerMiddleware }
+
+func (m *apiKeyMiddleware) SecurityScheme() api.SecurityScheme {
+	return api.SecurityScheme{Name: "apiKey", Type: api.SecuritySchemeAPIKey, In: "header", ParamName: "X-API-Key"}
+}
+
+func TestSwaggerAction_SecuritySchemes(t *testing.T) {
+	cfg := &config.Config{
+		Process: config.ProcessConfig{Name: "test-server"},
+		Server:  config.ServerConfig{Web: config.WebServerConfig{Host: "localhost", Port: 8080}},
+	}
+	logger := util.NewLogger(config.LoggerConfig{Level: "error"})
+	apiInstance := api.New(cfg, logger)
+
+	if err := apiInstance.RegisterAction(&searchAction{BaseAction: api.BaseAction{
+		ActionName:       "secret",
+		ActionMiddleware: []api.Middleware{&bearerMiddleware{}, &apiKeyMiddleware{}},
+		ActionWeb:        &api.WebConfig{Route: "/secret", Method: api.HTTPMethodGET},
+	}}); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	if err := apiInstance.RegisterAction(NewStatusAction()); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	doc := BuildSwaggerDocument(apiInstance, cfg)
+	paths := doc["paths"].(map[string]interface{})
+	components := doc["components"].(map[string]interface{})
+
+	schemes, ok := components["securitySchemes"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected securitySchemes in components")
+	}
+	bearer := schemes["bearerAuth"].(map[string]interface{})
+	if bearer["type"] != "http" || bearer["scheme"] != "bearer" || bearer["bearerFormat"] != "JWT" {
+		t.Errorf("Unexpected bearer scheme: %v", bearer)
+	}
+	apiKey := schemes["apiKey"].(map[string]interface{})
+	if apiKey["type"] != "apiKey" || apiKey["in"] != "header" || apiKey["name"] != "X-API-Key" {
+		t.Errorf("Unexpected apiKey scheme: %v", apiKey)
+	}
+
+	secretGet := paths["/secret"].(map[string]interface{})["get"].(map[string]interface{})
+	security, ok := secretGet["security"].([]map[string][]string)
+	if !ok || len(security) != 1 {
+		t.Fatalf("Expected a single security requirement, got %v", secretGet["security"])
+	}
+	if _, ok := security[0]["bearerAuth"]; !ok {
+		t.Error("Expected bearerAuth to be required")
+	}
+	if _, ok := security[0]["apiKey"]; !ok {
+		t.Error("Expected apiKey to be required")
+	}
+
+	// Actions without auth middleware are not marked as secured
+	statusGet := paths["/status"].(map[string]interface{})["get"].(map[string]interface{})
+	if _, ok := statusGet["security"]; ok {
+		t.Error("Expected status action to have no security requirement")
+	}
+}
+
+type richInput struct {
+	Age      int               `json:"age" validate:"gte=18,lt=130"`
+	Score    float64           `json:"score" validate:"min=0.5"`
+	Role     string            `json:"role" validate:"oneof=admin user guest"`
+	Level    int               `json:"level" validate:"oneof=1 2 3"`
+	Code     string            `json:"code" validate:"alphanum,len=6"`
+	Website  string            `json:"website" validate:"url"`
+	Emails   []string          `json:"emails" validate:"min=1,max=5,dive,email"`
+	Labels   map[string]string `json:"labels" validate:"dive,max=10"`
+	Nickname *string           `json:"nickname"`
+	Avatar   []byte            `json:"avatar"`
+	Address  struct {
+		City string `json:"city" validate:"required"`
+	} `json:"address"`
+}
+
+func buildRichSchema(t *testing.T, version string) map[string]interface{} {
+	t.Helper()
+
+	cfg := &config.Config{
+		Process: config.ProcessConfig{Name: "test-server"},
+		Server: config.ServerConfig{Web: config.WebServerConfig{
+			Host: "localhost", Port: 8080, OpenAPIVersion: version,
+		}},
+	}
+	logger := util.NewLogger(config.LoggerConfig{Level: "error"})
+	apiInstance := api.New(cfg, logger)
+
+	if err := apiInstance.RegisterAction(&searchAction{BaseAction: api.BaseAction{
+		ActionName:   "rich",
+		ActionInputs: richInput{},
+		ActionWeb:    &api.WebConfig{Route: "/rich", Method: api.HTTPMethodPOST},
+	}}); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	doc := BuildSwaggerDocument(apiInstance, cfg)
+	if doc["openapi"] != version {
+		t.Errorf("Expected openapi version %s, got %v", version, doc["openapi"])
+	}
+	schemas := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	return schemas["rich_Request"].(map[string]interface{})["properties"].(map[string]interface{})
+}
+
+func TestSwaggerAction_ValidateTagKeywords(t *testing.T) {
+	props := buildRichSchema(t, config.OpenAPIVersion30)
+	prop := func(name string) map[string]interface{} {
+		return props[name].(map[string]interface{})
+	}
+
+	age := prop("age")
+	if age["minimum"] != int64(18) || age["maximum"] != int64(130) || age["exclusiveMaximum"] != true {
+		t.Errorf("Unexpected age bounds: %v", age)
+	}
+	if prop("score")["minimum"] != 0.5 {
+		t.Errorf("Expected score minimum 0.5, got %v", prop("score")["minimum"])
+	}
+
+	role := prop("role")["enum"].([]interface{})
+	if len(role) != 3 || role[0] != "admin" {
+		t.Errorf("Unexpected role enum: %v", role)
+	}
+	level := prop("level")["enum"].([]interface{})
+	if len(level) != 3 || level[0] != int64(1) {
+		t.Errorf("Expected integer level enum, got %v", level)
+	}
+
+	code := prop("code")
+	if code["pattern"] != "^[a-zA-Z0-9]+$" || code["minLength"] != 6 || code["maxLength"] != 6 {
+		t.Errorf("Unexpected code schema: %v", code)
+	}
+	if prop("website")["format"] != "uri" {
+		t.Errorf("Expected website format uri, got %v", prop("website")["format"])
+	}
+
+	emails := prop("emails")
+	if emails["minItems"] != 1 || emails["maxItems"] != 5 {
+		t.Errorf("Unexpected emails bounds: %v", emails)
+	}
+	if emails["items"].(map[string]interface{})["format"] != "email" {
+		t.Errorf("Expected email items, got %v", emails["items"])
+	}
+	labels := prop("labels")["additionalProperties"].(map[string]interface{})
+	if labels["maxLength"] != 10 {
+		t.Errorf("Expected label values maxLength 10, got %v", labels)
+	}
+
+	if prop("nickname")["nullable"] != true {
+		t.Error("Expected pointer field to be nullable")
+	}
+	if prop("avatar")["format"] != "byte" {
+		t.Errorf("Expected []byte format byte, got %v", prop("avatar"))
+	}
+
+	address := prop("address")
+	if address["required"].([]string)[0] != "city" {
+		t.Errorf("Expected nested struct schema with required city, got %v", address)
+	}
+}
+
+func TestSwaggerAction_OpenAPI31(t *testing.T) {
+	props := buildRichSchema(t, config.OpenAPIVersion31)
+	prop := func(name string) map[string]interface{} {
+		return props[name].(map[string]interface{})
+	}
+
+	age := prop("age")
+	if age["exclusiveMaximum"] != int64(130) {
+		t.Errorf("Expected numeric exclusiveMaximum, got %v", age["exclusiveMaximum"])
+	}
+	if _, ok := age["maximum"]; ok {
+		t.Error("Expected maximum to be replaced by exclusiveMaximum")
+	}
+
+	nickname := prop("nickname")
+	if _, ok := nickname["nullable"]; ok {
+		t.Error("Expected nullable to be removed in 3.1")
+	}
+	types, ok := nickname["type"].([]interface{})
+	if !ok || len(types) != 2 || types[1] != "null" {
+		t.Errorf("Expected nickname type [string null], got %v", nickname["type"])
+	}
+
+	avatar := prop("avatar")
+	if avatar["contentEncoding"] != "base64" || avatar["format"] != nil {
+		t.Errorf("Expected []byte to use contentEncoding, got %v", avatar)
+	}
+}
+
+func TestSwaggerAction_FormatAndCache(t *testing.T) {
+	cfg := &config.Config{
+		Process: config.ProcessConfig{Name: "test-server"},
+		Server:  config.ServerConfig{Web: config.WebServerConfig{Host: "localhost", Port: 8080}},
+	}
+	logger := util.NewLogger(config.LoggerConfig{Level: "error"})
+	apiInstance := api.New(cfg, logger)
+
+	action := NewSwaggerAction()
+	if err := apiInstance.RegisterAction(action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	ctx := api.WithAPI(context.Background(), apiInstance)
+	conn := api.NewConnection("test", "127.0.0.1", "test-id", nil)
+
+	// YAML is returned as a raw response
+	response, err := action.Run(ctx, map[string]interface{}{"format": "yaml"}, conn)
+	if err != nil {
+		t.Fatalf("Failed to run swagger action: %v", err)
+	}
+	raw, ok := response.(*api.RawResponse)
+	if !ok {
+		t.Fatalf("Expected a raw response for YAML, got %T", response)
+	}
+	if raw.ContentType != "application/yaml" || !strings.Contains(string(raw.Body), "openapi: 3.0.0") {
+		t.Errorf("Unexpected YAML response: %s %s", raw.ContentType, raw.Body)
+	}
+
+	// The document is cached between requests
+	first, _ := action.Run(ctx, nil, conn)
+	second, _ := action.Run(ctx, nil, conn)
+	if reflect.ValueOf(first).Pointer() != reflect.ValueOf(second).Pointer() {
+		t.Error("Expected the document to be cached between requests")
+	}
+
+	// Registering an action invalidates the cache
+	if err := apiInstance.RegisterAction(NewStatusAction()); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	third, _ := action.Run(ctx, nil, conn)
+	paths := third.(map[string]interface{})["paths"].(map[string]interface{})
+	if paths["/status"] == nil {
+		t.Error("Expected the document to be rebuilt after registering an action")
+	}
+
+	// Unknown formats are rejected
+	if _, err := action.Run(ctx, map[string]interface{}{"format": "xml"}, conn); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestSwaggerAction_ActionDocs(t *testing.T) {
+	cfg := &config.Config{
+		Process: config.ProcessConfig{Name: "test-server"},
+		Server:  config.ServerConfig{Web: config.WebServerConfig{Host: "localhost", Port: 8080}},
+	}
+	logger := util.NewLogger(config.LoggerConfig{Level: "error"})
+	apiInstance := api.New(cfg, logger)
+
+	if err := apiInstance.RegisterAction(NewCreateUserAction()); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	if err := apiInstance.RegisterAction(&searchAction{BaseAction: api.BaseAction{
+		ActionName:   "search:legacy",
+		ActionInputs: searchInput{},
+		ActionWeb:    &api.WebConfig{Route: "/legacy/search", Method: api.HTTPMethodGET},
+		ActionDocs: &api.DocsConfig{
+			OperationID:    "legacySearch",
+			Tags:           []string{"search", "legacy"},
+			Deprecated:     true,
+			RequestExample: map[string]interface{}{"q": "mushrooms", "limit": 10},
+		},
+	}}); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	doc := BuildSwaggerDocument(apiInstance, cfg)
+	paths := doc["paths"].(map[string]interface{})
+
+	// Default operationId is derived from the action name
+	usersPost := paths["/users"].(map[string]interface{})["post"].(map[string]interface{})
+	if usersPost["operationId"] != "userCreate" {
+		t.Errorf("Expected operationId 'userCreate', got %v", usersPost["operationId"])
+	}
+
+	requestContent := usersPost["requestBody"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})
+	if example, ok := requestContent["example"].(CreateUserInput); !ok || example.Name != "Mario" {
+		t.Errorf("Expected request example, got %v", requestContent["example"])
+	}
+
+	responses := usersPost["responses"].(map[string]interface{})
+	responseContent := responses["200"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})
+	responseExample, ok := responseContent["example"].(map[string]interface{})
+	if !ok || responseExample["success"] != true || responseExample["data"] == nil {
+		t.Errorf("Expected wrapped response example, got %v", responseContent["example"])
+	}
+
+	legacyGet := paths["/legacy/search"].(map[string]interface{})["get"].(map[string]interface{})
+	if legacyGet["operationId"] != "legacySearch" {
+		t.Errorf("Expected explicit operationId, got %v", legacyGet["operationId"])
+	}
+	if legacyGet["deprecated"] != true {
+		t.Error("Expected operation to be deprecated")
+	}
+	tags := legacyGet["tags"].([]string)
+	if len(tags) != 2 || tags[1] != "legacy" {
+		t.Errorf("Expected custom tags, got %v", tags)
+	}
+
+	// Query parameter examples are taken from the request example
+	for _, param := range legacyGet["parameters"].([]map[string]interface{}) {
+		if param["name"] == "q" && param["example"] != "mushrooms" {
+			t.Errorf("Expected q example 'mushrooms', got %v", param["example"])
+		}
+	}
+}
+
+func TestSwaggerAction_ActionDeprecation(t *testing.T) {
+	cfg := &config.Config{
+		Process: config.ProcessConfig{Name: "test-server"},
+		Server:  config.ServerConfig{Web: config.WebServerConfig{Host: "localhost", Port: 8080}},
+	}
+	logger := util.NewLogger(config.LoggerConfig{Level: "error"})
+	apiInstance := api.New(cfg, logger)
+
+	if err := apiInstance.RegisterAction(&searchAction{BaseAction: api.BaseAction{
+		ActionName: "search:old",
+		ActionWeb:  &api.WebConfig{Route: "/old/search", Method: api.HTTPMethodGET},
+		ActionDeprecation: &api.DeprecationConfig{
+			Replacement: "search:new",
+			Sunset:      time.Date(2027, 1, 31, 0, 0, 0, 0, time.UTC),
+		},
+	}}); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	doc := BuildSwaggerDocument(apiInstance, cfg)
+	operation := doc["paths"].(map[string]interface{})["/old/search"].(map[string]interface{})["get"].(map[string]interface{})
+	if operation["deprecated"] != true {
+		t.Error("Expected operation to be deprecated")
+	}
+	want := "search:old is deprecated; use search:new instead (sunset 2027-01-31)"
+	if operation["description"] != want {
+		t.Errorf("Expected description %q, got %v", want, operation["description"])
+	}
+}
+
+func TestSwaggerAction_Namespace(t *testing.T) {
+	cfg := &config.Config{
+		Process: config.ProcessConfig{Name: "test-server"},
+		Server:  config.ServerConfig{Web: config.WebServerConfig{Host: "localhost", Port: 8080}},
+	}
+	logger := util.NewLogger(config.LoggerConfig{Level: "error"})
+	apiInstance := api.New(cfg, logger)
+
+	if err := apiInstance.RegisterNamespace(api.Namespace{Name: "search", RoutePrefix: "/v2", Tag: "Search"}); err != nil {
+		t.Fatalf("Failed to register namespace: %v", err)
+	}
+	if err := apiInstance.RegisterAction(&searchAction{BaseAction: api.BaseAction{
+		ActionName: "search:new",
+		ActionWeb:  &api.WebConfig{Route: "/search", Method: api.HTTPMethodGET},
+	}}); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	doc := BuildSwaggerDocument(apiInstance, cfg)
+	path, ok := doc["paths"].(map[string]interface{})["/v2/search"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected the prefixed path, got %v", doc["paths"])
+	}
+	tags := path["get"].(map[string]interface{})["tags"].([]string)
+	if len(tags) != 1 || tags[0] != "Search" {
+		t.Errorf("Expected the namespace tag, got %v", tags)
+	}
+}
+
+func TestSwaggerAction_ConfiguredInfoAndServers(t *testing.T) {
+	cfg := &config.Config{
+		Process: config.ProcessConfig{Name: "test-server"},
+		Server:  config.ServerConfig{Web: config.WebServerConfig{Host: "localhost", Port: 8080}},
+		OpenAPI: config.OpenAPIConfig{
+			Title:        "Pet Store",
+			Version:      "2.3.0",
+			Description:  "Pets as a service",
+			ContactName:  "API Team",
+			ContactEmail: "api@example.com",
+			License:      "Apache 2.0",
+			LicenseURL:   "https://www.apache.org/licenses/LICENSE-2.0",
+			Servers:      "https://api.example.com, http://localhost:8080",
+		},
+	}
+	logger := util.NewLogger(config.LoggerConfig{Level: "error"})
+	apiInstance := api.New(cfg, logger)
+
+	doc := BuildSwaggerDocument(apiInstance, cfg)
+
+	info := doc["info"].(map[string]interface{})
+	if info["title"] != "Pet Store" || info["version"] != "2.3.0" || info["description"] != "Pets as a service" {
+		t.Errorf("Unexpected info: %v", info)
+	}
+	contact := info["contact"].(map[string]string)
+	if contact["name"] != "API Team" || contact["email"] != "api@example.com" {
+		t.Errorf("Unexpected contact: %v", contact)
+	}
+	license := info["license"].(map[string]string)
+	if license["name"] != "Apache 2.0" || license["url"] != "https://www.apache.org/licenses/LICENSE-2.0" {
+		t.Errorf("Unexpected license: %v", license)
+	}
+
+	servers := doc["servers"].([]map[string]string)
+	if len(servers) != 2 {
+		t.Fatalf("Expected 2 servers, got %d", len(servers))
+	}
+	if servers[0]["url"] != "https://api.example.com" || servers[1]["url"] != "http://localhost:8080" {
+		t.Errorf("Unexpected servers: %v", servers)
+	}
+}
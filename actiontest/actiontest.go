@@ -0,0 +1,232 @@
+// Package actiontest runs actions in tests through the same pipeline a server
+// uses (Connection.Act), without hand-building an API, config, logger,
+// context, and connection:
+//
+//	func TestGreet(t *testing.T) {
+//	    result := actiontest.Run(t, NewGreetAction(), map[string]interface{}{"name": "Mario"})
+//	    output := actiontest.Output[GreetOutput](t, result)
+//	    if output.Greeting != "hello Mario" {
+//	        t.Errorf("unexpected greeting %q", output.Greeting)
+//	    }
+//	}
+package actiontest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/fixtures"
+	"github.com/evantahler/go-actionhero/internal/openapi"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// Result is the outcome of running an action
+type Result struct {
+	Response   interface{}     // The action's response (nil on error)
+	Error      error           // The action's error, or a validation error
+	RequestID  string          // Correlation ID used for the run
+	Cached     bool            // The response was served from the response cache
+	API        *api.API        // The API the action ran in
+	Connection *api.Connection // The connection the action ran on
+	Broadcasts *Recorder       // Messages the action broadcast
+}
+
+// Option customizes how an action is run
+type Option func(*options)
+
+type options struct {
+	cfg      *config.Config
+	actions  []api.Action
+	setup    []func(a *api.API) error
+	fixtures []string
+	conn     *api.Connection
+	ctx      context.Context
+	validate bool
+}
+
+// WithConfig changes the config the API is built with. It starts from the
+// defaults, logging only errors.
+func WithConfig(configure func(cfg *config.Config)) Option {
+	return func(o *options) {
+		configure(o.cfg)
+	}
+}
+
+// WithActions registers other actions alongside the one under test
+func WithActions(actions ...api.Action) Option {
+	return func(o *options) {
+		o.actions = append(o.actions, actions...)
+	}
+}
+
+// WithSetup runs fn on the API before the action, e.g. to provide resources,
+// register namespaces, add message translations, or subscribe to events
+func WithSetup(fn func(a *api.API) error) Option {
+	return func(o *options) {
+		o.setup = append(o.setup, fn)
+	}
+}
+
+// WithFixtures loads fixture files (or directories of them) into the API's
+// cache and, with the fixtures.Seeder provided by a WithSetup, its database,
+// after the setup funcs run
+func WithFixtures(paths ...string) Option {
+	return func(o *options) {
+		o.fixtures = append(o.fixtures, paths...)
+	}
+}
+
+// WithConnection runs the action on conn (e.g., one with a session, sticky
+// params, or values set by middleware) instead of a new test connection
+func WithConnection(conn *api.Connection) Option {
+	return func(o *options) {
+		o.conn = conn
+	}
+}
+
+// WithContext runs the action with ctx (e.g., one with a request ID or a
+// deadline)
+func WithContext(ctx context.Context) Option {
+	return func(o *options) {
+		o.ctx = ctx
+	}
+}
+
+// WithoutValidation skips checking params against the action's input schema
+func WithoutValidation() Option {
+	return func(o *options) {
+		o.validate = false
+	}
+}
+
+// Config returns the config actions are run with by default: every section's
+// defaults, with the logger only logging errors
+func Config() *config.Config {
+	cfg := &config.Config{
+		Process:    config.DefaultProcessConfig(),
+		Logger:     config.DefaultLoggerConfig(),
+		Database:   config.DefaultDatabaseConfig(),
+		Redis:      config.DefaultRedisConfig(),
+		Session:    config.DefaultSessionConfig(),
+		Server:     config.ServerConfig{Web: config.DefaultWebServerConfig()},
+		Tasks:      config.DefaultTasksConfig(),
+		Sentry:     config.DefaultSentryConfig(),
+		StatsD:     config.DefaultStatsDConfig(),
+		Mail:       config.DefaultMailConfig(),
+		Storage:    config.DefaultStorageConfig(),
+		HTTPClient: config.DefaultHTTPClientConfig(),
+		Breaker:    config.DefaultBreakerConfig(),
+		Tenancy:    config.DefaultTenancyConfig(),
+		GeoIP:      config.DefaultGeoIPConfig(),
+		Uptime:     config.DefaultUptimeConfig(),
+		I18n:       config.DefaultI18nConfig(),
+		Views:      config.DefaultViewsConfig(),
+		OpenAPI:    config.DefaultOpenAPIConfig(),
+		Secrets:    config.DefaultSecretsConfig(),
+	}
+	cfg.Logger.Level = "error"
+	cfg.Logger.Colorize = false
+	return cfg
+}
+
+// Run registers action in a new API and runs it with params, as a server
+// would: params are checked against the action's input schema (when it
+// declares Inputs), then Connection.Act runs the action's middleware
+// (including its namespace's), applies the timeout, concurrency limit, and
+// response cache, and emits the action's events. Setup failures
+// fail the test; the action's own error is returned in the Result.
+func Run(t testing.TB, action api.Action, params map[string]interface{}, opts ...Option) *Result {
+	t.Helper()
+
+	o := &options{cfg: Config(), ctx: context.Background(), validate: true}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	logger := util.NewLogger(o.cfg.Logger)
+	t.Cleanup(func() { _ = logger.Close() })
+	a := api.New(o.cfg, logger)
+
+	for _, registered := range append([]api.Action{action}, o.actions...) {
+		if err := a.RegisterAction(registered); err != nil {
+			t.Fatalf("actiontest: %v", err)
+		}
+	}
+	// Setup may provide a real Broadcaster in place of the recorder
+	recorder := NewRecorder()
+	api.Provide[api.Broadcaster](a, recorder)
+	for _, fn := range o.setup {
+		if err := fn(a); err != nil {
+			t.Fatalf("actiontest: setup failed: %v", err)
+		}
+	}
+	for _, path := range o.fixtures {
+		if err := fixtures.Seed(o.ctx, a, path); err != nil {
+			t.Fatalf("actiontest: %v", err)
+		}
+	}
+
+	conn := o.conn
+	if conn == nil {
+		conn = NewConnection("test", WithID("actiontest"))
+	}
+	descriptor := api.DescribeAction(action)
+	result := &Result{API: a, Connection: conn, Broadcasts: recorder}
+
+	if o.validate && descriptor.Inputs != nil {
+		if err := validate(descriptor.Inputs, params); err != nil {
+			result.Error = err
+			return result
+		}
+	}
+
+	actResult := conn.Act(o.ctx, a, descriptor.Name, params, "", "")
+	result.Response = actResult.Response
+	result.Error = actResult.Error
+	result.RequestID = actResult.RequestID
+	result.Cached = actResult.Cached
+	return result
+}
+
+// validate checks params against the input schema, as the web server does
+// with server.web.validaterequests
+func validate(inputs interface{}, params map[string]interface{}) error {
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	errs := openapi.Validate(openapi.SchemaFromStruct(inputs), params)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return util.NewTypedError(util.ErrorTypeConnectionActionParamValidation,
+		"params do not match the schema: "+strings.Join(messages, "; "), util.WithValue(errs))
+}
+
+// Output returns the action's response as T, failing the test if the action
+// returned an error. Responses of another type (e.g., maps) are converted
+// through JSON, like typed action inputs.
+func Output[T any](t testing.TB, result *Result) T {
+	t.Helper()
+
+	var output T
+	if result.Error != nil {
+		t.Fatalf("actiontest: action failed: %v", result.Error)
+		return output
+	}
+	if typed, ok := result.Response.(T); ok {
+		return typed
+	}
+	if err := api.MarshalParams(result.Response, &output); err != nil {
+		t.Fatalf("actiontest: %v", fmt.Errorf("response is %T, not %T: %w", result.Response, output, err))
+	}
+	return output
+}
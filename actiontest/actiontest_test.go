@@ -0,0 +1,173 @@
+package actiontest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/evantahler/go-actionhero/actions"
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+func TestRun_TypedAction(t *testing.T) {
+	result := Run(t, actions.NewCreateUserAction(), map[string]interface{}{
+		"name":     "Mario",
+		"email":    "mario@example.com",
+		"password": "its-a-me!",
+	})
+
+	output := Output[actions.CreateUserOutput](t, result)
+	if !output.Created || output.Name != "Mario" {
+		t.Errorf("Expected Mario to be created, got %+v", output)
+	}
+	if result.RequestID == "" {
+		t.Error("Expected a request ID")
+	}
+}
+
+func TestRun_Validation(t *testing.T) {
+	result := Run(t, actions.NewCreateUserAction(), map[string]interface{}{
+		"name":     "Mario",
+		"email":    "not-an-email",
+		"password": "its-a-me!",
+	})
+
+	var typedErr *util.TypedError
+	if !errors.As(result.Error, &typedErr) || typedErr.Type != util.ErrorTypeConnectionActionParamValidation {
+		t.Fatalf("Expected a validation error, got %v", result.Error)
+	}
+	if result.Response != nil {
+		t.Errorf("Expected the action not to run, got %v", result.Response)
+	}
+
+	result = Run(t, actions.NewCreateUserAction(), map[string]interface{}{"email": "not-an-email"}, WithoutValidation())
+	if result.Error != nil {
+		t.Errorf("Expected the action to run without validation, got %v", result.Error)
+	}
+}
+
+func TestRun_ConvertsOutput(t *testing.T) {
+	result := Run(t, actions.NewEchoAction(), map[string]interface{}{"message": "hi"})
+
+	output := Output[actions.EchoOutput](t, result)
+	if output.Received["message"] != "hi" {
+		t.Errorf("Expected the message to be echoed, got %+v", output)
+	}
+}
+
+// requireUser refuses actions on connections without a "user" value
+type requireUser struct{}
+
+var errNoUser = errors.New("sign in first")
+
+func (requireUser) RunBefore(_ interface{}, conn *api.Connection) (*api.MiddlewareResponse, error) {
+	if _, ok := conn.Get("user"); !ok {
+		return nil, errNoUser
+	}
+	return nil, nil
+}
+
+func (requireUser) RunAfter(_ interface{}, _ *api.Connection) (*api.MiddlewareResponse, error) {
+	return nil, nil
+}
+
+func TestRun_Middleware(t *testing.T) {
+	ran := false
+	handler := func(context.Context, interface{}, *api.Connection) (interface{}, error) {
+		ran = true
+		return "secret", nil
+	}
+
+	// The action's own middleware
+	result := Run(t, api.NewAction("account:show").Middleware(requireUser{}).Handler(handler), nil)
+	if !errors.Is(result.Error, errNoUser) || ran {
+		t.Errorf("Expected the middleware to refuse the action, got %v (ran: %v)", result.Error, ran)
+	}
+
+	conn := NewConnection("test")
+	conn.Set("user", "mario")
+	result = Run(t, api.NewAction("account:show").Middleware(requireUser{}).Handler(handler), nil, WithConnection(conn))
+	if Output[string](t, result) != "secret" {
+		t.Errorf("Expected the action to run for a signed-in user, got %v", result.Response)
+	}
+
+	// A namespace's middleware
+	ran = false
+	result = Run(t, api.NewAction("admin:stats").Handler(handler), nil,
+		WithSetup(func(a *api.API) error {
+			return a.RegisterNamespace(api.Namespace{Name: "admin", Middleware: []api.Middleware{requireUser{}}})
+		}),
+	)
+	if !errors.Is(result.Error, errNoUser) || ran {
+		t.Errorf("Expected the namespace middleware to refuse the action, got %v (ran: %v)", result.Error, ran)
+	}
+}
+
+// slowInput is provided as a resource to the test:slow action
+type slowInput struct{ delay time.Duration }
+
+func TestRun_Options(t *testing.T) {
+	slow := api.NewAction("test:slow").
+		Handler(func(ctx context.Context, params interface{}, conn *api.Connection) (interface{}, error) {
+			input, err := api.Resource[slowInput](ctx)
+			if err != nil {
+				return nil, err
+			}
+			select {
+			case <-time.After(input.delay):
+				return conn.ID, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		})
+
+	conn := api.NewConnection("websocket", "10.0.0.1", "my-connection", nil)
+	result := Run(t, slow, nil,
+		WithConfig(func(cfg *config.Config) { cfg.Process.ActionTimeout = 20 * time.Millisecond }),
+		WithSetup(func(a *api.API) error {
+			api.Provide(a, slowInput{delay: time.Second})
+			return nil
+		}),
+		WithConnection(conn),
+	)
+	if result.Error == nil {
+		t.Fatal("Expected the configured action timeout to stop the action")
+	}
+	if result.Connection != conn {
+		t.Error("Expected the action to run on the given connection")
+	}
+
+	result = Run(t, slow, nil,
+		WithSetup(func(a *api.API) error {
+			api.Provide(a, slowInput{})
+			return nil
+		}),
+		WithConnection(conn),
+	)
+	if Output[string](t, result) != "my-connection" {
+		t.Errorf("Expected the connection ID, got %v", result.Response)
+	}
+}
+
+func TestRun_WithFixtures(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "flags.yaml"), []byte("cache:\n  \"feature:chat\": enabled\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write fixtures: %v", err)
+	}
+
+	flag := api.NewAction("test:flag").
+		Handler(func(ctx context.Context, params interface{}, conn *api.Connection) (interface{}, error) {
+			value, _, err := api.APIFromContext(ctx).Cache.Get(ctx, "feature:chat")
+			return string(value), err
+		})
+
+	result := Run(t, flag, nil, WithFixtures(dir))
+	if Output[string](t, result) != "enabled" {
+		t.Errorf("Expected the seeded flag, got %v", result.Response)
+	}
+}
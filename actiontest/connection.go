@@ -0,0 +1,111 @@
+package actiontest
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/evantahler/go-actionhero/internal/config"
+)
+
+// connectionCount numbers test connections and sessions, so their IDs differ
+var connectionCount atomic.Int64
+
+// ConnectionOption sets up a connection built with NewConnection
+type ConnectionOption func(conn *api.Connection)
+
+// NewConnection builds a connection of any type (e.g., "web", "websocket",
+// "cli") for running actions in tests, from 127.0.0.1 with a unique ID:
+//
+//	conn := actiontest.NewConnection("websocket",
+//	    actiontest.WithSession(map[string]interface{}{"userId": 1}),
+//	    actiontest.WithSubscriptions("chat:lobby"),
+//	)
+func NewConnection(connType string, opts ...ConnectionOption) *api.Connection {
+	id := fmt.Sprintf("test-%s-%d", connType, connectionCount.Add(1))
+	conn := api.NewConnection(connType, "127.0.0.1", id, nil)
+	conn.SetClientInfo(api.ClientInfo{RemoteIP: "127.0.0.1", Protocol: connType})
+	for _, opt := range opts {
+		opt(conn)
+	}
+	return conn
+}
+
+// NewSession builds session data with a unique ID, created now, named after
+// the default session cookie
+func NewSession(data map[string]interface{}) *api.SessionData {
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	return &api.SessionData{
+		ID:         fmt.Sprintf("test-session-%d", connectionCount.Add(1)),
+		CookieName: config.DefaultSessionConfig().CookieName,
+		CreatedAt:  time.Now().Unix(),
+		Data:       data,
+	}
+}
+
+// WithID sets the connection's ID
+func WithID(id string) ConnectionOption {
+	return func(conn *api.Connection) {
+		conn.ID = id
+	}
+}
+
+// WithIdentifier sets the connection's identifier (its remote address) and
+// client IP
+func WithIdentifier(identifier string) ConnectionOption {
+	return func(conn *api.Connection) {
+		conn.Identifier = identifier
+		info := conn.ClientInfo()
+		info.RemoteIP = identifier
+		info.Fingerprint = "" // Recomputed for the new IP
+		conn.SetClientInfo(info)
+	}
+}
+
+// WithSession loads a new session holding data (see NewSession)
+func WithSession(data map[string]interface{}) ConnectionOption {
+	return func(conn *api.Connection) {
+		conn.SetSession(NewSession(data))
+	}
+}
+
+// WithSubscriptions subscribes the connection to channels
+func WithSubscriptions(channels ...string) ConnectionOption {
+	return func(conn *api.Connection) {
+		for _, channel := range channels {
+			conn.Subscribe(channel)
+		}
+	}
+}
+
+// WithParam sets a sticky param, passed to every action run on the connection
+func WithParam(key string, value interface{}) ConnectionOption {
+	return func(conn *api.Connection) {
+		conn.SetParam(key, value)
+	}
+}
+
+// WithValue stores a value on the connection, as middleware would with Set
+// (e.g., the authenticated user)
+func WithValue(key string, value interface{}) ConnectionOption {
+	return func(conn *api.Connection) {
+		conn.Set(key, value)
+	}
+}
+
+// WithLocales sets the connection's preferred locales
+func WithLocales(locales ...string) ConnectionOption {
+	return func(conn *api.Connection) {
+		conn.SetLocales(locales...)
+	}
+}
+
+// WithClientInfo sets what is known about the client (user agent, TLS, ...)
+func WithClientInfo(info api.ClientInfo) ConnectionOption {
+	return func(conn *api.Connection) {
+		conn.SetClientInfo(info)
+	}
+}
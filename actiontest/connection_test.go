@@ -0,0 +1,79 @@
+package actiontest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+)
+
+func TestNewConnection(t *testing.T) {
+	conn := NewConnection("websocket",
+		WithSession(map[string]interface{}{"userId": 1}),
+		WithSubscriptions("chat:lobby", "chat:help"),
+		WithParam("room", "lobby"),
+		WithValue("user", "Mario"),
+		WithLocales("fr", "en"),
+		WithIdentifier("10.0.0.1"),
+	)
+
+	if conn.Type != "websocket" || conn.ID == "" {
+		t.Errorf("Expected a websocket connection with an ID, got %q %q", conn.Type, conn.ID)
+	}
+	if other := NewConnection("websocket"); other.ID == conn.ID {
+		t.Errorf("Expected unique IDs, got %q twice", conn.ID)
+	}
+	if !conn.IsSessionLoaded() || conn.Session.Data["userId"] != 1 || conn.Session.ID == "" {
+		t.Errorf("Expected a loaded session, got %+v", conn.Session)
+	}
+	if channels := conn.Channels(); !reflect.DeepEqual(channels, []string{"chat:help", "chat:lobby"}) {
+		t.Errorf("Expected subscriptions, got %v", channels)
+	}
+	if conn.Params()["room"] != "lobby" {
+		t.Errorf("Expected the sticky param, got %v", conn.Params())
+	}
+	if user, _ := conn.Get("user"); user != "Mario" {
+		t.Errorf("Expected the value, got %v", user)
+	}
+	if locales := conn.Locales(); !reflect.DeepEqual(locales, []string{"fr", "en"}) {
+		t.Errorf("Expected locales, got %v", locales)
+	}
+	if info := conn.ClientInfo(); conn.Identifier != "10.0.0.1" || info.RemoteIP != "10.0.0.1" || info.Fingerprint == "" {
+		t.Errorf("Expected the identifier to set the client IP, got %q %+v", conn.Identifier, info)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	announce := api.NewAction("test:announce").
+		Handler(func(ctx context.Context, params interface{}, conn *api.Connection) (interface{}, error) {
+			broadcaster, err := api.Resource[api.Broadcaster](ctx)
+			if err != nil {
+				return nil, err
+			}
+			message := struct {
+				Text string `json:"text"`
+				From string `json:"from"`
+			}{Text: "hello", From: conn.ID}
+			return nil, broadcaster.Broadcast("chat:lobby", message)
+		})
+
+	conn := NewConnection("websocket", WithID("luigi"))
+	result := Run(t, announce, nil, WithConnection(conn))
+	if result.Error != nil {
+		t.Fatalf("Expected the action to succeed, got %v", result.Error)
+	}
+
+	AssertBroadcast(t, result.Broadcasts, "chat:lobby", map[string]interface{}{"text": "hello", "from": "luigi"})
+	AssertNoBroadcast(t, result.Broadcasts, "chat:help")
+	if broadcasts := result.Broadcasts.Broadcasts(""); len(broadcasts) != 1 {
+		t.Errorf("Expected one broadcast, got %v", broadcasts)
+	}
+
+	inner := &testing.T{}
+	AssertBroadcast(inner, result.Broadcasts, "chat:lobby", map[string]interface{}{"text": "goodbye"})
+	AssertNoBroadcast(inner, result.Broadcasts, "")
+	if !inner.Failed() {
+		t.Error("Expected mismatched assertions to fail")
+	}
+}
@@ -0,0 +1,96 @@
+package actiontest
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// Broadcast is a message an action broadcast to a channel
+type Broadcast struct {
+	Channel string
+	Data    interface{}
+}
+
+// Recorder is an api.Broadcaster that records broadcasts instead of sending
+// them. Run provides one to every action it runs (see Result.Broadcasts).
+type Recorder struct {
+	mu         sync.Mutex
+	broadcasts []Broadcast
+}
+
+// NewRecorder creates an empty Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Broadcast records a message
+func (r *Recorder) Broadcast(channel string, data interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.broadcasts = append(r.broadcasts, Broadcast{Channel: channel, Data: data})
+	return nil
+}
+
+// Broadcasts returns the messages broadcast to channel, in order ("" = every
+// channel)
+func (r *Recorder) Broadcasts(channel string) []Broadcast {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var broadcasts []Broadcast
+	for _, broadcast := range r.broadcasts {
+		if channel == "" || broadcast.Channel == channel {
+			broadcasts = append(broadcasts, broadcast)
+		}
+	}
+	return broadcasts
+}
+
+// AssertBroadcast fails the test unless want was broadcast to channel.
+// Messages are compared as JSON, as subscribers would receive them, so a
+// struct matches the equivalent map.
+func AssertBroadcast(t testing.TB, r *Recorder, channel string, want interface{}) {
+	t.Helper()
+
+	wantJSON, err := normalize(want)
+	if err != nil {
+		t.Fatalf("actiontest: %v", err)
+		return
+	}
+	broadcasts := r.Broadcasts(channel)
+	for _, broadcast := range broadcasts {
+		got, err := normalize(broadcast.Data)
+		if err == nil && reflect.DeepEqual(got, wantJSON) {
+			return
+		}
+	}
+
+	sent := make([]interface{}, len(broadcasts))
+	for i, broadcast := range broadcasts {
+		sent[i] = broadcast.Data
+	}
+	t.Errorf("actiontest: expected broadcast to %q of %v, got %v", channel, want, sent)
+}
+
+// AssertNoBroadcast fails the test if anything was broadcast to channel (""
+// = any channel)
+func AssertNoBroadcast(t testing.TB, r *Recorder, channel string) {
+	t.Helper()
+
+	if broadcasts := r.Broadcasts(channel); len(broadcasts) > 0 {
+		t.Errorf("actiontest: expected no broadcasts to %q, got %v", channel, broadcasts)
+	}
+}
+
+// normalize round-trips v through JSON
+func normalize(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	err = json.Unmarshal(data, &normalized)
+	return normalized, err
+}
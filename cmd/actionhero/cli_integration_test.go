@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -111,8 +112,17 @@ func TestCLI_StatusAction(t *testing.T) {
 		t.Error("Expected 'timestamp' field in response")
 	}
 
-	if respData["uptime"] != "running" {
-		t.Errorf("Expected uptime 'running', got %v", respData["uptime"])
+	// The API isn't started for CLI runs, so uptime is zero
+	if respData["uptime"] != "0s" {
+		t.Errorf("Expected uptime '0s', got %v", respData["uptime"])
+	}
+
+	if respData["goroutines"] == nil || respData["heapAllocBytes"] == nil {
+		t.Error("Expected runtime stats in response")
+	}
+
+	if actionCount, ok := respData["actions"].(float64); !ok || actionCount < 1 {
+		t.Errorf("Expected loaded action count, got %v", respData["actions"])
 	}
 }
 
@@ -149,6 +159,82 @@ func TestCLI_EchoAction(t *testing.T) {
 	}
 }
 
+func TestCLI_GlobalFlagsNotParams(t *testing.T) {
+	stdout, stderr, exitCode := runCLI(t, "echo", "--message", "hi", "--set", "logger.level=info", "--quiet")
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d\nStderr: %s", exitCode, stderr)
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal([]byte(stdout), &response); err != nil {
+		t.Fatalf("Failed to parse JSON response: %v\nOutput: %s", err, stdout)
+	}
+	respData, _ := response["response"].(map[string]interface{})
+	received, _ := respData["received"].(map[string]interface{})
+	if received["message"] != "hi" {
+		t.Errorf("Expected the action's param, got %v", received)
+	}
+	for _, name := range []string{"set", "quiet", "config"} {
+		if _, ok := received[name]; ok {
+			t.Errorf("Expected the global --%s flag not to be passed to the action, got %v", name, received)
+		}
+	}
+}
+
+func TestCLI_ActionTimeoutFlag(t *testing.T) {
+	stdout, stderr, exitCode := runCLI(t, "echo", "--message", "hi", "--timeout", "10s", "--quiet")
+
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d\nStderr: %s", exitCode, stderr)
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal([]byte(stdout), &response); err != nil {
+		t.Fatalf("Failed to parse JSON response: %v\nOutput: %s", err, stdout)
+	}
+	respData, _ := response["response"].(map[string]interface{})
+	received, _ := respData["received"].(map[string]interface{})
+	if _, ok := received["timeout"]; ok {
+		t.Errorf("Expected --timeout not to be passed to the action, got %v", received)
+	}
+
+	_, _, exitCode = runCLI(t, "echo", "--message", "hi", "--timeout", "soon", "--quiet")
+	if exitCode == 0 {
+		t.Error("Expected an invalid --timeout to fail")
+	}
+}
+
+func TestCLI_DryRun(t *testing.T) {
+	stdout, stderr, exitCode := runCLI(t,
+		"user:create",
+		"--name", "Test User",
+		"--email", "test@example.com",
+		"--password", "testpass123",
+		"--dry-run",
+		"--quiet",
+	)
+
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d\nStderr: %s", exitCode, stderr)
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal([]byte(stdout), &response); err != nil {
+		t.Fatalf("Failed to parse JSON response: %v\nOutput: %s", err, stdout)
+	}
+	respData, _ := response["response"].(map[string]interface{})
+	if respData["dryRun"] != true || respData["action"] != "user:create" || respData["method"] != "POST" {
+		t.Errorf("Expected a dry run of user:create, got %v", respData)
+	}
+	params, _ := respData["params"].(map[string]interface{})
+	if params["name"] != "Test User" {
+		t.Errorf("Expected the params it would have run with, got %v", params)
+	}
+	if _, ok := params["dry-run"]; ok {
+		t.Error("Expected --dry-run not to be passed to the action")
+	}
+}
+
 func TestCLI_UserCreateAction(t *testing.T) {
 	stdout, stderr, exitCode := runCLI(t,
 		"user:create",
@@ -295,3 +381,176 @@ func TestCLI_NoColorFlag(t *testing.T) {
 		t.Fatalf("Failed to parse JSON response: %v\nOutput: %s", err, stdout)
 	}
 }
+
+func TestCLI_SwaggerExport(t *testing.T) {
+	outFile := filepath.Join(t.TempDir(), "openapi.json")
+	_, stderr, exitCode := runCLI(t, "swagger", "export", "--out", outFile, "--quiet")
+
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d\nStderr: %s", exitCode, stderr)
+	}
+
+	data, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("Failed to read exported document: %v", err)
+	}
+
+	var document map[string]interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		t.Fatalf("Failed to parse exported document: %v", err)
+	}
+
+	if document["openapi"] == nil {
+		t.Error("Expected 'openapi' field in exported document")
+	}
+
+	paths, ok := document["paths"].(map[string]interface{})
+	if !ok || paths["/status"] == nil {
+		t.Error("Expected /status to be documented in exported document")
+	}
+}
+
+func TestCLI_SwaggerExportYAML(t *testing.T) {
+	stdout, stderr, exitCode := runCLI(t, "swagger", "export", "--yaml", "--quiet")
+
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d\nStderr: %s", exitCode, stderr)
+	}
+
+	if !strings.Contains(stdout, "openapi:") || !strings.Contains(stdout, "/status:") {
+		t.Errorf("Expected YAML document on stdout, got: %s", stdout)
+	}
+}
+
+func TestCLI_SwaggerExportOpenAPI31(t *testing.T) {
+	stdout, stderr, exitCode := runCLI(t, "swagger", "export", "--openapi-version", "3.1.0", "--quiet")
+
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d\nStderr: %s", exitCode, stderr)
+	}
+
+	var document map[string]interface{}
+	if err := json.Unmarshal([]byte(stdout), &document); err != nil {
+		t.Fatalf("Expected JSON document on stdout: %v", err)
+	}
+	if document["openapi"] != "3.1.0" {
+		t.Errorf("Expected openapi 3.1.0, got %v", document["openapi"])
+	}
+}
+
+func TestCLI_TaskWorkerTasksDisabled(t *testing.T) {
+	t.Setenv("ACTIONHERO_TASKS_ENABLED", "false")
+
+	_, _, exitCode := runCLI(t, "task", "worker", "--no-color")
+
+	if exitCode == 0 {
+		t.Error("Expected non-zero exit code when tasks are disabled")
+	}
+}
+
+func TestCLI_ConfigSetOverrides(t *testing.T) {
+	stdout, stderr, exitCode := runCLI(t, "config", "--format", "json",
+		"--set", "server.web.port=9123", "--set", "server.web.allowedorigins=https://a.example.com,https://b.example.com")
+
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr)
+	}
+	if !strings.Contains(stdout, `"Port": 9123`) {
+		t.Errorf("Expected port override in output, got: %s", stdout)
+	}
+	if !strings.Contains(stdout, "https://a.example.com,https://b.example.com") {
+		t.Errorf("Expected value containing commas to be kept whole, got: %s", stdout)
+	}
+}
+
+func TestCLI_ConfigSources(t *testing.T) {
+	stdout, stderr, exitCode := runCLI(t, "config", "--sources", "--format", "json", "--set", "server.web.port=9123")
+
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr)
+	}
+
+	var sources map[string]struct {
+		Value  interface{} `json:"value"`
+		Source string      `json:"source"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &sources); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v\nOutput: %s", err, stdout)
+	}
+	if port := sources["server.web.port"]; port.Value != float64(9123) || port.Source != "override" {
+		t.Errorf("Expected port 9123 from override, got %+v", port)
+	}
+	if level := sources["logger.level"]; level.Source != "default" {
+		t.Errorf("Expected logger.level from default, got %+v", level)
+	}
+}
+
+func TestCLI_ConfigEncrypt(t *testing.T) {
+	key, stderr, exitCode := runCLI(t, "config", "encrypt", "--generate-key")
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr)
+	}
+	key = strings.TrimSpace(key)
+
+	t.Setenv("ACTIONHERO_SECRETS_ENCRYPTIONKEY", key)
+	encrypted, stderr, exitCode := runCLI(t, "config", "encrypt", "s3cret")
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr)
+	}
+	encrypted = strings.TrimSpace(encrypted)
+	if !strings.HasPrefix(encrypted, "enc:") {
+		t.Fatalf("Expected an enc: value, got %s", encrypted)
+	}
+
+	stdout, stderr, exitCode := runCLI(t, "config", "--sources", "--format", "json", "--set", "session.cookiename="+encrypted)
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr)
+	}
+	if !strings.Contains(stdout, `"value": "s3cret"`) {
+		t.Errorf("Expected decrypted value in output, got: %s", stdout)
+	}
+}
+
+func TestCLI_ConfigSetInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		set      string
+		expected string
+	}{
+		{"unknown key", "server.web.prot=9000", "unknown config key"},
+		{"missing value", "server.web.port", "use key=value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, stderr, exitCode := runCLI(t, "config", "--set", tt.set, "--no-color")
+
+			if exitCode == 0 {
+				t.Error("Expected non-zero exit code")
+			}
+			if !strings.Contains(stderr, tt.expected) {
+				t.Errorf("Expected stderr to mention %q, got: %s", tt.expected, stderr)
+			}
+		})
+	}
+}
+
+func TestCLI_ConfigFileFlag(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "custom.toml")
+	if err := os.WriteFile(path, []byte("[process]\nname = \"from-flag-file\"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	stdout, stderr, exitCode := runCLI(t, "config", "--format", "json", "--config", path)
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr)
+	}
+	if !strings.Contains(stdout, "from-flag-file") {
+		t.Errorf("Expected process name from --config file, got: %s", stdout)
+	}
+
+	_, stderr, exitCode = runCLI(t, "config", "--config", filepath.Join(t.TempDir(), "missing.yaml"), "--no-color")
+	if exitCode == 0 || !strings.Contains(stderr, "missing.yaml") {
+		t.Errorf("Expected a missing --config file to fail, got exit %d: %s", exitCode, stderr)
+	}
+}
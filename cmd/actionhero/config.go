@@ -4,7 +4,12 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"reflect"
+	"sort"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/evantahler/go-actionhero/internal/config"
 	"github.com/evantahler/go-actionhero/internal/util"
@@ -12,8 +17,9 @@ import (
 )
 
 const (
-	formatJSON = "json"
-	formatList = "list"
+	formatJSON     = "json"
+	formatList     = "list"
+	formatMarkdown = "markdown"
 )
 
 // dumpConfig displays the current configuration in a formatted way
@@ -36,34 +42,88 @@ func dumpConfig(cfg *config.Config, logger *util.Logger, format string) {
 func dumpConfigJSON(cfg *config.Config, logger *util.Logger) {
 	// Create a safe copy for JSON output (mask passwords)
 	jsonCfg := struct {
-		Process  config.ProcessConfig  `json:"process"`
-		Logger   config.LoggerConfig   `json:"logger"`
-		Database config.DatabaseConfig `json:"database"`
-		Redis    config.RedisConfig    `json:"redis"`
-		Session  config.SessionConfig  `json:"session"`
-		Server   config.ServerConfig   `json:"server"`
-		Tasks    config.TasksConfig    `json:"tasks"`
+		Process    config.ProcessConfig              `json:"process"`
+		Logger     config.LoggerConfig               `json:"logger"`
+		Database   config.DatabaseConfig             `json:"database"`
+		Redis      config.RedisConfig                `json:"redis"`
+		Session    config.SessionConfig              `json:"session"`
+		Server     config.ServerConfig               `json:"server"`
+		Tasks      config.TasksConfig                `json:"tasks"`
+		Sentry     config.SentryConfig               `json:"sentry"`
+		StatsD     config.StatsDConfig               `json:"statsd"`
+		Mail       config.MailConfig                 `json:"mail"`
+		Storage    config.StorageConfig              `json:"storage"`
+		HTTPClient config.HTTPClientConfig           `json:"httpclient"`
+		Breaker    config.BreakerConfig              `json:"breaker"`
+		Tenancy    config.TenancyConfig              `json:"tenancy"`
+		GeoIP      config.GeoIPConfig                `json:"geoip"`
+		Uptime     config.UptimeConfig               `json:"uptime"`
+		I18n       config.I18nConfig                 `json:"i18n"`
+		Views      config.ViewsConfig                `json:"views"`
+		OpenAPI    config.OpenAPIConfig              `json:"openapi"`
+		Secrets    config.SecretsConfig              `json:"secrets"`
+		Sections   map[string]map[string]interface{} `json:"sections,omitempty"`
+		Files      []string                          `json:"files,omitempty"`
 	}{
-		Process:  cfg.Process,
-		Logger:   cfg.Logger,
-		Database: cfg.Database,
-		Redis:    cfg.Redis,
-		Session:  cfg.Session,
-		Server:   cfg.Server,
-		Tasks:    cfg.Tasks,
+		Process:    cfg.Process,
+		Logger:     cfg.Logger,
+		Database:   cfg.Database,
+		Redis:      cfg.Redis,
+		Session:    cfg.Session,
+		Server:     cfg.Server,
+		Tasks:      cfg.Tasks,
+		Sentry:     cfg.Sentry,
+		StatsD:     cfg.StatsD,
+		Mail:       cfg.Mail,
+		Storage:    cfg.Storage,
+		HTTPClient: cfg.HTTPClient,
+		Breaker:    cfg.Breaker,
+		Tenancy:    cfg.Tenancy,
+		GeoIP:      cfg.GeoIP,
+		Uptime:     cfg.Uptime,
+		I18n:       cfg.I18n,
+		Views:      cfg.Views,
+		OpenAPI:    cfg.OpenAPI,
+		Secrets:    cfg.Secrets,
+		Files:      cfg.Files,
+	}
+	for name, section := range cfg.Sections {
+		if jsonCfg.Sections == nil {
+			jsonCfg.Sections = make(map[string]map[string]interface{})
+		}
+		jsonCfg.Sections[name] = sectionValues(section)
 	}
 
 	// Mask passwords
 	if cfg.Database.Password != "" {
-		jsonCfg.Database.Password = maskPassword(cfg.Database.Password)
+		jsonCfg.Database.Password = config.Mask(cfg.Database.Password)
 	} else {
 		jsonCfg.Database.Password = ""
 	}
 	if cfg.Redis.Password != "" {
-		jsonCfg.Redis.Password = maskPassword(cfg.Redis.Password)
+		jsonCfg.Redis.Password = config.Mask(cfg.Redis.Password)
 	} else {
 		jsonCfg.Redis.Password = ""
 	}
+	jsonCfg.Sentry.DSN = maskDSN(cfg.Sentry.DSN)
+	if cfg.Mail.SMTPPassword != "" {
+		jsonCfg.Mail.SMTPPassword = config.Mask(cfg.Mail.SMTPPassword)
+	}
+	if cfg.Server.Web.AdminPassword != "" {
+		jsonCfg.Server.Web.AdminPassword = config.Mask(cfg.Server.Web.AdminPassword)
+	}
+	if cfg.Storage.URLSecret != "" {
+		jsonCfg.Storage.URLSecret = config.Mask(cfg.Storage.URLSecret)
+	}
+	if cfg.Tenancy.TokenSecret != "" {
+		jsonCfg.Tenancy.TokenSecret = config.Mask(cfg.Tenancy.TokenSecret)
+	}
+	if cfg.Secrets.VaultToken != "" {
+		jsonCfg.Secrets.VaultToken = config.Mask(cfg.Secrets.VaultToken)
+	}
+	if cfg.Secrets.EncryptionKey != "" {
+		jsonCfg.Secrets.EncryptionKey = config.Mask(cfg.Secrets.EncryptionKey)
+	}
 
 	jsonData, err := json.MarshalIndent(jsonCfg, "", "  ")
 	if err != nil {
@@ -94,15 +154,87 @@ func dumpConfigList(cfg *config.Config, logger *util.Logger) {
 		logger.Info(sectionColor.Sprint("  " + strings.Repeat("─", len(title)+2)))
 	}
 
+	// Config files
+	printSection("Config Files")
+	printKV("Environment", displayEnvironment())
+	printKV("Merge Order", configMergeOrder())
+	if len(cfg.Files) == 0 {
+		printKV("Loaded", "none (defaults and environment variables only)")
+	}
+	for i, file := range cfg.Files {
+		printKV(fmt.Sprintf("Loaded %d", i+1), file)
+	}
+
 	// Process
 	printSection("Process")
 	printKV("Name", cfg.Process.Name)
+	printKV("Pid File", cfg.Process.PidFile)
+	printKV("Log File", cfg.Process.LogFile)
+	printKV("Watch Config", fmt.Sprintf("%v", cfg.Process.WatchConfig))
+	if cfg.Process.ActionTimeout > 0 {
+		printKV("Action Timeout", cfg.Process.ActionTimeout.String())
+	} else {
+		printKV("Action Timeout", "none")
+	}
+	printKV("Parallel Startup", fmt.Sprintf("%v", cfg.Process.ParallelStartup))
+	if cfg.Process.StartupTimeout > 0 {
+		printKV("Startup Timeout", cfg.Process.StartupTimeout.String())
+	} else {
+		printKV("Startup Timeout", "none")
+	}
+	printKV("Error Disclosure", cfg.Process.ErrorDisclosure)
 
 	// Logger
 	printSection("Logger")
 	printKV("Level", cfg.Logger.Level)
 	printKV("Colorize", fmt.Sprintf("%v", cfg.Logger.Colorize))
 	printKV("Timestamp", fmt.Sprintf("%v", cfg.Logger.Timestamp))
+	if cfg.Logger.Format != "" {
+		printKV("Format", cfg.Logger.Format)
+	} else if cfg.Logger.Colorize {
+		printKV("Format", "text")
+	} else {
+		printKV("Format", "json")
+	}
+	if cfg.Logger.SlowAction > 0 {
+		printKV("Slow Action Threshold", cfg.Logger.SlowAction.String())
+	} else {
+		printKV("Slow Action Threshold", "disabled")
+	}
+	if cfg.Logger.ErrorSampleFirst > 0 {
+		printKV("Error Sampling", fmt.Sprintf("first %d, then every %d, per %s",
+			cfg.Logger.ErrorSampleFirst, cfg.Logger.ErrorSampleThereafter, cfg.Logger.ErrorSampleWindow))
+	} else {
+		printKV("Error Sampling", "disabled")
+	}
+	if cfg.Logger.AsyncBuffer > 0 {
+		printKV("Async Buffer", fmt.Sprintf("%d entries", cfg.Logger.AsyncBuffer))
+	} else {
+		printKV("Async Buffer", "disabled")
+	}
+	if cfg.Logger.File != "" {
+		printKV("File", cfg.Logger.File)
+		printKV("Stdout", fmt.Sprintf("%v", cfg.Logger.Stdout))
+		rotation := "never"
+		if cfg.Logger.FileMaxSize > 0 || cfg.Logger.FileMaxAge > 0 {
+			rotation = fmt.Sprintf("at %s or after %s, keeping %d (compress: %v)",
+				cfg.Logger.FileMaxSize, cfg.Logger.FileMaxAge, cfg.Logger.FileMaxBackups, cfg.Logger.FileCompress)
+		}
+		printKV("File Rotation", rotation)
+	} else {
+		printKV("File", "none")
+	}
+	if cfg.Logger.Syslog != "" {
+		printKV("Syslog", fmt.Sprintf("%s (tag %s, facility %s)", cfg.Logger.Syslog, cfg.Logger.SyslogTag, cfg.Logger.SyslogFacility))
+	} else {
+		printKV("Syslog", "disabled")
+	}
+	printKV("Journald", fmt.Sprintf("%v", cfg.Logger.Journald))
+	if cfg.Logger.Hooks != "" {
+		printKV("Hooks", cfg.Logger.Hooks)
+	} else {
+		printKV("Hooks", "none")
+	}
 
 	// Database
 	printSection("Database")
@@ -110,21 +242,30 @@ func dumpConfigList(cfg *config.Config, logger *util.Logger) {
 	printKV("Host", cfg.Database.Host)
 	printKV("Port", fmt.Sprintf("%d", cfg.Database.Port))
 	printKV("User", cfg.Database.User)
-	printKV("Password", maskPassword(cfg.Database.Password))
+	printKV("Password", config.Mask(cfg.Database.Password))
 	printKV("Database", cfg.Database.Database)
 	printKV("SSL Mode", cfg.Database.SSLMode)
+	printKV("Fixtures", cfg.Database.Fixtures)
+	printKV("Seed On Start", fmt.Sprintf("%v", cfg.Database.SeedOnStart))
 
 	// Redis
 	printSection("Redis")
 	printKV("Host", cfg.Redis.Host)
 	printKV("Port", fmt.Sprintf("%d", cfg.Redis.Port))
-	printKV("Password", maskPassword(cfg.Redis.Password))
+	printKV("Password", config.Mask(cfg.Redis.Password))
 	printKV("DB", fmt.Sprintf("%d", cfg.Redis.DB))
 
 	// Session
 	printSection("Session")
 	printKV("Cookie Name", cfg.Session.CookieName)
-	printKV("TTL", fmt.Sprintf("%d seconds", cfg.Session.TTL))
+	printKV("TTL", cfg.Session.TTL.String())
+	printKV("SameSite", cfg.Session.SameSite)
+	printKV("Secure", fmt.Sprintf("%v", cfg.Session.Secure))
+	printKV("HttpOnly", fmt.Sprintf("%v", cfg.Session.HTTPOnly))
+	if cfg.Session.Domain != "" {
+		printKV("Domain", cfg.Session.Domain)
+	}
+	printKV("Path", cfg.Session.Path)
 
 	// Server
 	printSection("Server - Web")
@@ -140,6 +281,82 @@ func dumpConfigList(cfg *config.Config, logger *util.Logger) {
 		printKV("Static Files Route", cfg.Server.Web.StaticFilesRoute)
 		printKV("Static Files Directory", cfg.Server.Web.StaticFilesDirectory)
 	}
+	printKV("Metrics Enabled", fmt.Sprintf("%v", cfg.Server.Web.MetricsEnabled))
+	if cfg.Server.Web.MetricsEnabled {
+		printKV("Metrics Route", cfg.Server.Web.MetricsRoute)
+	}
+	printKV("Debug Enabled", fmt.Sprintf("%v", cfg.Server.Web.DebugEnabled))
+	if cfg.Server.Web.DebugEnabled {
+		printKV("Debug Route", cfg.Server.Web.DebugRoute)
+		if cfg.Server.Web.DebugPort > 0 {
+			printKV("Debug Listener", fmt.Sprintf("%s:%d", cfg.Server.Web.DebugHost, cfg.Server.Web.DebugPort))
+		}
+	}
+	printKV("Admin Enabled", fmt.Sprintf("%v", cfg.Server.Web.AdminEnabled))
+	if cfg.Server.Web.AdminEnabled {
+		printKV("Admin Route", cfg.Server.Web.AdminRoute)
+		printKV("Admin User", cfg.Server.Web.AdminUser)
+	}
+
+	printKV("OpenAPI Version", cfg.Server.Web.OpenAPIVersion)
+	printKV("Validate Requests", fmt.Sprintf("%v", cfg.Server.Web.ValidateRequests))
+	if cfg.Server.Web.IdleTimeout > 0 {
+		printKV("Idle Timeout", cfg.Server.Web.IdleTimeout.String())
+	} else {
+		printKV("Idle Timeout", "none")
+	}
+	if cfg.Server.Web.TLSCertFile != "" {
+		printKV("TLS Certificate", cfg.Server.Web.TLSCertFile)
+		printKV("TLS Key", cfg.Server.Web.TLSKeyFile)
+	} else {
+		printKV("TLS", "disabled")
+	}
+	if cfg.Server.Web.AllowedIPs != "" {
+		printKV("Allowed IPs", cfg.Server.Web.AllowedIPs)
+	}
+	if cfg.Server.Web.DeniedIPs != "" {
+		printKV("Denied IPs", cfg.Server.Web.DeniedIPs)
+	}
+	if cfg.Server.Web.MetricsAllowedIPs != "" {
+		printKV("Metrics Allowed IPs", cfg.Server.Web.MetricsAllowedIPs)
+	}
+	if cfg.Server.Web.DebugAllowedIPs != "" {
+		printKV("Debug Allowed IPs", cfg.Server.Web.DebugAllowedIPs)
+	}
+	if cfg.Server.Web.AdminAllowedIPs != "" {
+		printKV("Admin Allowed IPs", cfg.Server.Web.AdminAllowedIPs)
+	}
+	if cfg.Server.Web.MessageRate > 0 {
+		printKV("Message Rate Limit", fmt.Sprintf("%g/s, burst %d, %d warnings",
+			cfg.Server.Web.MessageRate, cfg.Server.Web.MessageBurst, cfg.Server.Web.MessageRateWarnings))
+	} else {
+		printKV("Message Rate Limit", "none")
+	}
+	printKV("Max Actions In Flight", fmt.Sprintf("%d per connection", cfg.Server.Web.MaxActionsInFlight))
+	printKV("Ordered Responses", fmt.Sprintf("%v", cfg.Server.Web.OrderedResponses))
+	if cfg.Server.Web.BroadcastWorkers > 0 {
+		printKV("Broadcast Workers", fmt.Sprintf("%d", cfg.Server.Web.BroadcastWorkers))
+	} else {
+		printKV("Broadcast Workers", "one per CPU")
+	}
+	if cfg.Server.Web.RouteCacheSize > 0 {
+		printKV("Route Cache Size", fmt.Sprintf("%d", cfg.Server.Web.RouteCacheSize))
+	} else {
+		printKV("Route Cache Size", "disabled")
+	}
+
+	// Other registered servers
+	if len(cfg.Server.Enabled) > 0 {
+		printSection("Server - Others")
+		names := make([]string, 0, len(cfg.Server.Enabled))
+		for name := range cfg.Server.Enabled {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			printKV(name, fmt.Sprintf("enabled: %v", cfg.Server.Enabled[name]))
+		}
+	}
 
 	// Tasks
 	printSection("Tasks")
@@ -147,18 +364,330 @@ func dumpConfigList(cfg *config.Config, logger *util.Logger) {
 	if cfg.Tasks.Enabled {
 		printKV("Task Processors", fmt.Sprintf("%d", cfg.Tasks.TaskProcessors))
 		printKV("Queues", fmt.Sprintf("%v", cfg.Tasks.Queues))
-		printKV("Timeout", fmt.Sprintf("%d ms", cfg.Tasks.Timeout))
-		printKV("Stuck Worker Timeout", fmt.Sprintf("%d ms", cfg.Tasks.StuckWorkerTimeout))
+		printKV("Timeout", cfg.Tasks.Timeout.String())
+		printKV("Stuck Worker Timeout", cfg.Tasks.StuckWorkerTimeout.String())
 		printKV("Retry Stuck Jobs", fmt.Sprintf("%v", cfg.Tasks.RetryStuckJobs))
 	}
 
+	// Sentry
+	printSection("Sentry")
+	if cfg.Sentry.DSN != "" {
+		printKV("DSN", maskDSN(cfg.Sentry.DSN))
+		printKV("Environment", cfg.Sentry.Environment)
+		printKV("Sample Rate", fmt.Sprintf("%g", cfg.Sentry.SampleRate))
+	} else {
+		printKV("DSN", "(disabled)")
+	}
+
+	// StatsD
+	printSection("StatsD")
+	printKV("Enabled", fmt.Sprintf("%v", cfg.StatsD.Enabled))
+	if cfg.StatsD.Enabled {
+		printKV("Address", fmt.Sprintf("%s:%d", cfg.StatsD.Host, cfg.StatsD.Port))
+		printKV("Prefix", cfg.StatsD.Prefix)
+		printKV("Tags", cfg.StatsD.Tags)
+	}
+
+	// Mail
+	printSection("Mail")
+	printKV("Enabled", fmt.Sprintf("%v", cfg.Mail.Enabled))
+	if cfg.Mail.Enabled {
+		printKV("Transport", cfg.Mail.Transport)
+		printKV("From", cfg.Mail.From)
+		printKV("Templates", cfg.Mail.Templates)
+		printKV("Async", fmt.Sprintf("%v (queue size %d)", cfg.Mail.Async, cfg.Mail.QueueSize))
+		if cfg.Mail.Transport == "ses" {
+			printKV("SES Region", cfg.Mail.SESRegion)
+		} else {
+			printKV("SMTP Address", fmt.Sprintf("%s:%d", cfg.Mail.SMTPHost, cfg.Mail.SMTPPort))
+			printKV("SMTP User", cfg.Mail.SMTPUser)
+			printKV("SMTP Password", config.Mask(cfg.Mail.SMTPPassword))
+		}
+	}
+
+	// Storage
+	printSection("Storage")
+	printKV("Enabled", fmt.Sprintf("%v", cfg.Storage.Enabled))
+	if cfg.Storage.Enabled {
+		printKV("Backend", cfg.Storage.Backend)
+		if cfg.Storage.Backend == "s3" {
+			printKV("S3 Bucket", cfg.Storage.S3Bucket)
+			printKV("S3 Region", cfg.Storage.S3Region)
+			if cfg.Storage.S3Endpoint != "" {
+				printKV("S3 Endpoint", fmt.Sprintf("%s (path style: %v)", cfg.Storage.S3Endpoint, cfg.Storage.S3PathStyle))
+			}
+		} else {
+			printKV("Directory", cfg.Storage.Directory)
+			printKV("Route", cfg.Storage.Route)
+			if cfg.Storage.PublicURL != "" {
+				printKV("Public URL", cfg.Storage.PublicURL)
+			}
+			if cfg.Storage.URLSecret != "" {
+				printKV("URL Secret", config.Mask(cfg.Storage.URLSecret))
+			} else {
+				printKV("URL Secret", "(random per process)")
+			}
+		}
+	}
+
+	// HTTP client
+	printSection("HTTP Client")
+	if cfg.HTTPClient.Timeout > 0 {
+		printKV("Timeout", cfg.HTTPClient.Timeout.String())
+	} else {
+		printKV("Timeout", "none")
+	}
+	if cfg.HTTPClient.Retries > 0 {
+		printKV("Retries", fmt.Sprintf("%d (backoff %s, up to %s)",
+			cfg.HTTPClient.Retries, cfg.HTTPClient.RetryBackoff, cfg.HTTPClient.RetryMaxBackoff))
+	} else {
+		printKV("Retries", "disabled")
+	}
+	if cfg.HTTPClient.BreakerThreshold > 0 {
+		printKV("Circuit Breaker", fmt.Sprintf("after %d failures, for %s",
+			cfg.HTTPClient.BreakerThreshold, cfg.HTTPClient.BreakerCooldown))
+	} else {
+		printKV("Circuit Breaker", "disabled")
+	}
+	if cfg.HTTPClient.UserAgent != "" {
+		printKV("User Agent", cfg.HTTPClient.UserAgent)
+	}
+
+	// Circuit breakers
+	printSection("Circuit Breakers")
+	if cfg.Breaker.Threshold > 0 {
+		printKV("Threshold", fmt.Sprintf("%d failures", cfg.Breaker.Threshold))
+		printKV("Cooldown", cfg.Breaker.Cooldown.String())
+		printKV("Half-Open Probes", fmt.Sprintf("%d", cfg.Breaker.HalfOpenProbes))
+	} else {
+		printKV("Threshold", "disabled")
+	}
+
+	// Multi-tenancy
+	printSection("Tenancy")
+	printKV("Enabled", fmt.Sprintf("%v", cfg.Tenancy.Enabled))
+	if cfg.Tenancy.Enabled {
+		switch cfg.Tenancy.Source {
+		case config.TenantSourceHeader:
+			printKV("Source", fmt.Sprintf("header %s", cfg.Tenancy.Header))
+		case config.TenantSourceSubdomain:
+			printKV("Source", fmt.Sprintf("subdomain of %s", cfg.Tenancy.BaseDomain))
+		case config.TenantSourceClaim:
+			printKV("Source", fmt.Sprintf("token claim %s", cfg.Tenancy.Claim))
+			printKV("Token Secret", config.Mask(cfg.Tenancy.TokenSecret))
+		}
+		printKV("Required", fmt.Sprintf("%v", cfg.Tenancy.Required))
+		if cfg.Tenancy.Database != "" {
+			printKV("Database", cfg.Tenancy.Database)
+		}
+		if cfg.Tenancy.Schema != "" {
+			printKV("Schema", cfg.Tenancy.Schema)
+		}
+	}
+
+	// GeoIP
+	printSection("GeoIP")
+	printKV("Enabled", fmt.Sprintf("%v", cfg.GeoIP.Enabled))
+	if cfg.GeoIP.Enabled {
+		printKV("Database", cfg.GeoIP.Database)
+	}
+
+	// Uptime
+	printSection("Uptime Checks")
+	printKV("Enabled", fmt.Sprintf("%v", cfg.Uptime.Enabled))
+	if cfg.Uptime.Enabled {
+		printKV("URLs", cfg.Uptime.URLs)
+		printKV("Interval", cfg.Uptime.Interval.String())
+		printKV("Timeout", cfg.Uptime.Timeout.String())
+	}
+
+	// I18n
+	printSection("I18n")
+	printKV("Directory", cfg.I18n.Directory)
+	printKV("Default Locale", cfg.I18n.DefaultLocale)
+	printKV("Locale Param", cfg.I18n.LocaleParam)
+
+	// Views
+	printSection("Views")
+	printKV("Directory", cfg.Views.Directory)
+	printKV("Layout", cfg.Views.Layout)
+	printKV("Reload", fmt.Sprintf("%v", cfg.Views.Reload))
+
+	// OpenAPI
+	printSection("OpenAPI")
+	printKV("Title", cfg.OpenAPI.Title)
+	printKV("Version", cfg.OpenAPI.Version)
+	printKV("License", cfg.OpenAPI.License)
+	printKV("Servers", cfg.OpenAPI.Servers)
+
+	// Secrets
+	printSection("Secrets")
+	if cfg.Secrets.EncryptionKey != "" {
+		printKV("Encryption Key", config.Mask(cfg.Secrets.EncryptionKey))
+	}
+	if cfg.Secrets.VaultAddress != "" {
+		printKV("Vault Address", cfg.Secrets.VaultAddress)
+		printKV("Vault Token", config.Mask(cfg.Secrets.VaultToken))
+	}
+	if cfg.Secrets.AWSRegion != "" {
+		printKV("AWS Region", cfg.Secrets.AWSRegion)
+	}
+	printKV("Cache TTL", cfg.Secrets.CacheTTL.String())
+	printKV("Refresh", cfg.Secrets.Refresh.String())
+
+	// Registered sections (plugins and app code)
+	for _, name := range sortedSectionNames(cfg) {
+		printSection(name)
+		values := sectionValues(cfg.Sections[name])
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			printKV(key, fmt.Sprintf("%v", values[key]))
+		}
+	}
+
 	logger.Info("")
 }
 
-// maskPassword masks sensitive password values
-func maskPassword(password string) string {
-	if password == "" {
-		return "(empty)"
+// displayEnvironment returns the current environment for display
+func displayEnvironment() string {
+	if env := config.Environment(); env != "" {
+		return env
+	}
+	return "(not set; set GO_ENV or NODE_ENV)"
+}
+
+// configMergeOrder describes the order config directory files are merged in
+func configMergeOrder() string {
+	names := config.ConfigDirOrder(config.Environment())
+	for i, name := range names {
+		names[i] = config.DefaultConfigDir + "/" + name + ".*"
+	}
+	return strings.Join(names, " → ") + " (later files win; then .env files, env vars, and --set)"
+}
+
+// dumpConfigSources displays each config value with where it came from
+func dumpConfigSources(cfg *config.Config, logger *util.Logger, format string) {
+	if format != formatList && format != formatJSON {
+		logger.Errorf("  Invalid format '%s'. Use 'list' or 'json'", format)
+		return
+	}
+
+	values := cfg.MaskedValues()
+	if format == formatJSON {
+		jsonData, err := json.MarshalIndent(values, "", "  ")
+		if err != nil {
+			logger.Errorf("Failed to marshal config to JSON: %v", err)
+			return
+		}
+		fmt.Println(string(jsonData))
+		return
+	}
+
+	keyColor := color.New(color.FgYellow)
+	valueColor := color.New(color.FgWhite)
+	sourceColor := color.New(color.FgHiBlack)
+	logger.Info("")
+	for _, key := range cfg.Keys() {
+		value := values[key]
+		logger.Info(fmt.Sprintf("  %s: %s %s", keyColor.Sprint(key),
+			valueColor.Sprint(value.Value), sourceColor.Sprintf("[%s]", value.Source)))
+	}
+}
+
+// sectionValues flattens a registered section into dot-separated keys,
+// masking fields that look sensitive
+func sectionValues(section interface{}) map[string]interface{} {
+	values := make(map[string]interface{})
+	flattenSection(reflect.Indirect(reflect.ValueOf(section)), "", values)
+	return values
+}
+
+// flattenSection adds each leaf field of a struct to values
+func flattenSection(v reflect.Value, prefix string, values map[string]interface{}) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key := strings.ToLower(field.Name)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		value := v.Field(i)
+		switch {
+		case value.Kind() == reflect.Struct:
+			flattenSection(value, key, values)
+		case value.Kind() == reflect.String && config.IsSensitive(field.Name):
+			values[key] = config.Mask(value.String())
+		case value.Type() == reflect.TypeOf(time.Duration(0)):
+			values[key] = value.Interface().(time.Duration).String()
+		default:
+			values[key] = value.Interface()
+		}
+	}
+}
+
+// sortedSectionNames returns the names of the registered sections in order
+func sortedSectionNames(cfg *config.Config) []string {
+	names := make([]string, 0, len(cfg.Sections))
+	for name := range cfg.Sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// maskDSN masks the key in a DSN (https://key@host/project), keeping the host visible
+func maskDSN(dsn string) string {
+	at := strings.LastIndex(dsn, "@")
+	scheme := strings.Index(dsn, "://")
+	if at == -1 || scheme == -1 || at < scheme {
+		return config.Mask(dsn)
+	}
+	key := dsn[scheme+3 : at]
+	return dsn[:scheme+3] + strings.Repeat("*", len(key)) + dsn[at:]
+}
+
+// writeEnvDocs writes the environment variables of the config settings to w
+// as an aligned list, JSON, or a markdown table
+func writeEnvDocs(w io.Writer, vars []config.EnvVar, format string) error {
+	switch format {
+	case formatJSON:
+		jsonData, err := json.MarshalIndent(vars, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal environment variables to JSON: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(jsonData))
+		return err
+	case formatMarkdown:
+		fmt.Fprintln(w, "| Variable | Setting | Type | Default |")
+		fmt.Fprintln(w, "| --- | --- | --- | --- |")
+		for _, v := range vars {
+			fmt.Fprintf(w, "| `%s` | `%s` | %s | %s |\n", v.Name, v.Key, v.Type, markdownCode(v.Default))
+		}
+		return nil
+	case formatList:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "VARIABLE\tTYPE\tDEFAULT")
+		for _, v := range vars {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", v.Name, v.Type, v.Default)
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("invalid format '%s'. Use 'list', 'json', or 'markdown'", format)
+	}
+}
+
+// markdownCode formats a value as inline code in a markdown table cell
+// (empty values are left blank)
+func markdownCode(value string) string {
+	if value == "" {
+		return ""
 	}
-	return strings.Repeat("*", len(password))
+	return "`" + strings.ReplaceAll(value, "|", "\\|") + "`"
 }
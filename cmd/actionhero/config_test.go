@@ -2,9 +2,11 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/evantahler/go-actionhero/internal/config"
 	"github.com/evantahler/go-actionhero/internal/util"
@@ -33,6 +35,7 @@ func TestDumpConfig(t *testing.T) {
 
 	// Check that all sections are present
 	sections := []string{
+		"CONFIG FILES",
 		"PROCESS",
 		"LOGGER",
 		"DATABASE",
@@ -65,39 +68,6 @@ func TestDumpConfig(t *testing.T) {
 	}
 }
 
-func TestMaskPassword(t *testing.T) {
-	tests := []struct {
-		name     string
-		password string
-		want     string
-	}{
-		{
-			name:     "empty password",
-			password: "",
-			want:     "(empty)",
-		},
-		{
-			name:     "short password",
-			password: "abc",
-			want:     "***",
-		},
-		{
-			name:     "long password",
-			password: "verylongpassword123",
-			want:     strings.Repeat("*", len("verylongpassword123")),
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := maskPassword(tt.password)
-			if got != tt.want {
-				t.Errorf("maskPassword(%q) = %q, want %q", tt.password, got, tt.want)
-			}
-		})
-	}
-}
-
 func TestDumpConfig_WithEnvOverrides(t *testing.T) {
 	// Disable colors for testing
 	color.NoColor = true
@@ -139,3 +109,70 @@ func TestDumpConfig_WithEnvOverrides(t *testing.T) {
 		t.Error("Expected '9000' (from env var) not found in output")
 	}
 }
+
+func TestSectionValues(t *testing.T) {
+	section := &struct {
+		Endpoint string
+		APIKey   string
+		Timeout  time.Duration
+		Limits   struct{ Burst int }
+	}{Endpoint: "https://plugin.example.com", APIKey: "abc123", Timeout: 2 * time.Second}
+	section.Limits.Burst = 10
+
+	values := sectionValues(section)
+
+	expected := map[string]interface{}{
+		"endpoint":     "https://plugin.example.com",
+		"apikey":       "******",
+		"timeout":      "2s",
+		"limits.burst": 10,
+	}
+	for key, want := range expected {
+		if values[key] != want {
+			t.Errorf("Expected %s=%v, got %v", key, want, values[key])
+		}
+	}
+}
+
+func TestWriteEnvDocs(t *testing.T) {
+	vars := []config.EnvVar{
+		{Name: "ACTIONHERO_SERVER_WEB_PORT", Key: "server.web.port", Type: "int", Default: "8080"},
+		{Name: "ACTIONHERO_SERVER_WEB_ALLOWEDIPS", Key: "server.web.allowedips", Type: "string", Default: ""},
+	}
+
+	var list bytes.Buffer
+	if err := writeEnvDocs(&list, vars, formatList); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(list.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "VARIABLE") || strings.Join(strings.Fields(lines[1]), " ") != "ACTIONHERO_SERVER_WEB_PORT int 8080" {
+		t.Errorf("Expected an aligned list, got:\n%s", list.String())
+	}
+
+	var markdown bytes.Buffer
+	if err := writeEnvDocs(&markdown, vars, formatMarkdown); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, want := range []string{
+		"| Variable | Setting | Type | Default |",
+		"| `ACTIONHERO_SERVER_WEB_PORT` | `server.web.port` | int | `8080` |",
+		"| `ACTIONHERO_SERVER_WEB_ALLOWEDIPS` | `server.web.allowedips` | string |  |",
+	} {
+		if !strings.Contains(markdown.String(), want) {
+			t.Errorf("Expected markdown to contain %q, got:\n%s", want, markdown.String())
+		}
+	}
+
+	var jsonOut bytes.Buffer
+	if err := writeEnvDocs(&jsonOut, vars, formatJSON); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var decoded []config.EnvVar
+	if err := json.Unmarshal(jsonOut.Bytes(), &decoded); err != nil || len(decoded) != 2 || decoded[0] != vars[0] {
+		t.Errorf("Expected the variables as JSON, got %s (%v)", jsonOut.String(), err)
+	}
+
+	if err := writeEnvDocs(&bytes.Buffer{}, vars, "yaml"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// daemonEnvVar marks a process that was started in the background by `start --daemon`
+const daemonEnvVar = "ACTIONHERO_DAEMON_CHILD"
+
+// stopTimeout is how long `stop` waits for the daemon to exit
+const stopTimeout = 30 * time.Second
+
+// isDaemonChild returns whether this process is the backgrounded daemon
+func isDaemonChild() bool {
+	return os.Getenv(daemonEnvVar) == "1"
+}
+
+// startDaemon re-executes the current binary in the background with its
+// output redirected to logFile, and records the child's pid in pidFile
+func startDaemon(pidFile, logFile string) (int, error) {
+	if pid, err := readPidFile(pidFile); err == nil && processRunning(pid) {
+		return 0, fmt.Errorf("already running with pid %d (pid file: %s)", pid, pidFile)
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return 0, fmt.Errorf("failed to find executable: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(logFile), 0755); err != nil {
+		return 0, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	logOutput, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer func() { _ = logOutput.Close() }()
+
+	child := exec.Command(executable, os.Args[1:]...)
+	child.Env = append(os.Environ(), daemonEnvVar+"=1")
+	child.Stdout = logOutput
+	child.Stderr = logOutput
+	child.SysProcAttr = detachedProcAttr()
+
+	if err := child.Start(); err != nil {
+		return 0, fmt.Errorf("failed to start daemon: %w", err)
+	}
+
+	pid := child.Process.Pid
+	if err := writePidFile(pidFile, pid); err != nil {
+		_ = child.Process.Kill()
+		return 0, err
+	}
+
+	// The daemon lives on after we exit
+	if err := child.Process.Release(); err != nil {
+		return 0, fmt.Errorf("failed to release daemon process: %w", err)
+	}
+
+	return pid, nil
+}
+
+// stopDaemon signals the pid recorded in pidFile with SIGTERM, waits for it to
+// exit, and removes the pid file
+func stopDaemon(pidFile string) (int, error) {
+	pid, err := readPidFile(pidFile)
+	if err != nil {
+		return 0, err
+	}
+
+	if !processRunning(pid) {
+		_ = os.Remove(pidFile)
+		return pid, fmt.Errorf("process %d is not running (removed stale pid file %s)", pid, pidFile)
+	}
+
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return pid, fmt.Errorf("failed to find process %d: %w", pid, err)
+	}
+	if err := process.Signal(syscall.SIGTERM); err != nil {
+		return pid, fmt.Errorf("failed to signal process %d: %w", pid, err)
+	}
+
+	deadline := time.Now().Add(stopTimeout)
+	for processRunning(pid) {
+		if time.Now().After(deadline) {
+			return pid, fmt.Errorf("process %d did not stop within %s", pid, stopTimeout)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	_ = os.Remove(pidFile)
+	return pid, nil
+}
+
+// writePidFile writes pid to path, creating parent directories as needed
+func writePidFile(path string, pid int) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create pid file directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(strconv.Itoa(pid)+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write pid file: %w", err)
+	}
+	return nil
+}
+
+// readPidFile reads the pid stored at path
+func readPidFile(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, fmt.Errorf("pid file %s not found (is the server running as a daemon?)", path)
+		}
+		return 0, fmt.Errorf("failed to read pid file: %w", err)
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, fmt.Errorf("pid file %s does not contain a valid pid", path)
+	}
+	return pid, nil
+}
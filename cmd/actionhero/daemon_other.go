@@ -0,0 +1,24 @@
+//go:build !unix && !windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// detachedProcAttr returns no special attributes on other platforms
+func detachedProcAttr() *syscall.SysProcAttr {
+	return nil
+}
+
+// processRunning returns whether a process with the given pid exists.
+// FindProcess always succeeds here, so the process is probed with signal 0;
+// platforms that can't deliver it report the process as not running.
+func processRunning(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return process.Signal(syscall.Signal(0)) == nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPidFile_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run", "actionhero.pid")
+
+	if err := writePidFile(path, 4242); err != nil {
+		t.Fatalf("Failed to write pid file: %v", err)
+	}
+
+	pid, err := readPidFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read pid file: %v", err)
+	}
+	if pid != 4242 {
+		t.Errorf("Expected pid 4242, got %d", pid)
+	}
+}
+
+func TestReadPidFile_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := readPidFile(filepath.Join(dir, "missing.pid")); err == nil {
+		t.Error("Expected error for missing pid file")
+	}
+
+	invalid := filepath.Join(dir, "invalid.pid")
+	if err := os.WriteFile(invalid, []byte("not-a-pid"), 0644); err != nil {
+		t.Fatalf("Failed to write pid file: %v", err)
+	}
+	if _, err := readPidFile(invalid); err == nil {
+		t.Error("Expected error for invalid pid file")
+	}
+}
+
+func TestProcessRunning(t *testing.T) {
+	if !processRunning(os.Getpid()) {
+		t.Error("Expected current process to be running")
+	}
+	// A pid above the kernel maximum cannot exist
+	if processRunning(1<<22 + 1) {
+		t.Error("Expected a missing process not to be running")
+	}
+}
+
+func TestStopDaemon_StalePidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "actionhero.pid")
+
+	// Use a pid above the kernel maximum so it cannot exist
+	if err := writePidFile(path, 1<<22+1); err != nil {
+		t.Fatalf("Failed to write pid file: %v", err)
+	}
+
+	if _, err := stopDaemon(path); err == nil {
+		t.Error("Expected error when stopping a process that is not running")
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Expected stale pid file to be removed")
+	}
+}
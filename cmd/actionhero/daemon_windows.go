@@ -0,0 +1,24 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// detachedProcAttr returns no special attributes on Windows
+func detachedProcAttr() *syscall.SysProcAttr {
+	return nil
+}
+
+// processRunning returns whether a process with the given pid exists. On
+// Windows, FindProcess opens the process, which fails once it is gone.
+func processRunning(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = process.Release()
+	return true
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/fixtures"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// dbCmd groups the database commands
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Database commands",
+}
+
+// dbSeedCmd represents the db seed command
+var dbSeedCmd = &cobra.Command{
+	Use:   "seed [path]",
+	Short: "Load fixtures into the database and cache",
+	Long: `Start the ActionHero initializers (without any servers), then load the fixtures at path
+(default: database.fixtures), a YAML or JSON file or a directory of them. Rows are inserted
+with the fixtures.Seeder the app provides; cache values are stored in the API's cache.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		path := cfg.Database.Fixtures
+		if len(args) == 1 {
+			path = args[0]
+		}
+		seedDatabase(path)
+	},
+}
+
+// seedDatabase starts the API without servers and loads the fixtures at path
+func seedDatabase(path string) {
+	prepareSeedConfig(cfg)
+
+	apiInstance := newAPI()
+	if err := apiInstance.Initialize(); err != nil {
+		logger.Fatalf("Failed to initialize: %v", err)
+	}
+	if err := apiInstance.Start(); err != nil {
+		logger.Fatalf("Failed to start: %v", err)
+	}
+
+	err := fixtures.Seed(context.Background(), apiInstance, path)
+	if stopErr := apiInstance.Stop(); stopErr != nil {
+		logger.Errorf("Error during shutdown: %v", stopErr)
+	}
+	if err != nil {
+		logger.Fatalf("Failed to seed: %v", err)
+	}
+	logger.Info(color.GreenString("Seeded fixtures from %s", path))
+}
+
+// prepareSeedConfig adjusts the config the API is started with to seed: the
+// fixtures are seeded once, by the command, and no task processors run, so
+// queued jobs aren't taken while seeding
+func prepareSeedConfig(c *config.Config) {
+	c.Database.SeedOnStart = false
+	c.Tasks.TaskProcessors = 0
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+)
+
+func TestPrepareSeedConfig(t *testing.T) {
+	c := &config.Config{
+		Database: config.DatabaseConfig{SeedOnStart: true},
+		Tasks:    config.TasksConfig{Enabled: true, TaskProcessors: 4},
+	}
+	prepareSeedConfig(c)
+	if c.Database.SeedOnStart {
+		t.Error("Expected fixtures not to be seeded on start too")
+	}
+	if c.Tasks.TaskProcessors != 0 {
+		t.Errorf("Expected no task processors while seeding, got %d", c.Tasks.TaskProcessors)
+	}
+	if !c.Tasks.Enabled {
+		t.Error("Expected tasks to stay enabled, so fixtures can enqueue them")
+	}
+}
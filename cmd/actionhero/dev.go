@@ -0,0 +1,315 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+)
+
+// devDebounce is how long the watcher waits for changes to settle before rebuilding
+const devDebounce = 300 * time.Millisecond
+
+// devStopTimeout is how long a running server gets to exit before it is killed
+const devStopTimeout = 10 * time.Second
+
+// devCmd represents the dev command
+var devCmd = &cobra.Command{
+	Use:   "dev",
+	Short: "Run the server and restart it when files change",
+	Long: `Build and start the server, then watch the project for changes. When a file matching
+the include globs (and none of the exclude globs) changes, the binary is rebuilt and the
+server restarted. Build failures are reported and the previous server keeps running.`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		include, _ := cmd.Flags().GetStringSlice("include")
+		exclude, _ := cmd.Flags().GetStringSlice("exclude")
+		pkg, _ := cmd.Flags().GetString("build")
+
+		watcher, err := newDevWatcher(".", pkg, include, exclude)
+		if err != nil {
+			logger.Fatalf("Failed to start dev mode: %v", err)
+		}
+		if err := watcher.Run(); err != nil {
+			logger.Fatalf("Dev mode failed: %v", err)
+		}
+	},
+}
+
+// devWatcher rebuilds and restarts the server when watched files change
+type devWatcher struct {
+	root    string
+	pkg     string
+	binary  string
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+
+	server *exec.Cmd
+	exited chan struct{}
+}
+
+// newDevWatcher creates a watcher for root, building pkg on change
+func newDevWatcher(root, pkg string, include, exclude []string) (*devWatcher, error) {
+	includeRes, err := compileGlobs(include)
+	if err != nil {
+		return nil, err
+	}
+	excludeRes, err := compileGlobs(exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	return &devWatcher{
+		root:    root,
+		pkg:     pkg,
+		binary:  filepath.Join(os.TempDir(), fmt.Sprintf("actionhero-dev-%d", os.Getpid())),
+		include: includeRes,
+		exclude: excludeRes,
+	}, nil
+}
+
+// Run builds and starts the server, then rebuilds/restarts on changes until interrupted
+func (d *devWatcher) Run() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	defer func() { _ = watcher.Close() }()
+	defer func() { _ = os.Remove(d.binary) }()
+
+	if err := d.watchTree(watcher, d.root); err != nil {
+		return err
+	}
+
+	if err := d.build(); err != nil {
+		logger.Errorf("Build failed: %v", err)
+	} else {
+		d.startServer()
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	var debounce <-chan time.Time
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			// Watch directories created after startup
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					_ = d.watchTree(watcher, event.Name)
+				}
+			}
+			if d.matches(event.Name) {
+				logger.Debugf("Change detected: %s", event.Name)
+				debounce = time.After(devDebounce)
+			}
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			logger.Warnf("File watcher error: %v", err)
+
+		case <-debounce:
+			debounce = nil
+			logger.Info(color.CyanString("Changes detected, rebuilding..."))
+			if err := d.build(); err != nil {
+				logger.Errorf("Build failed, keeping the previous server running: %v", err)
+				continue
+			}
+			d.stopServer()
+			d.startServer()
+
+		case <-sigChan:
+			logger.Info("Shutting down dev mode...")
+			d.stopServer()
+			return nil
+		}
+	}
+}
+
+// watchTree adds dir and its subdirectories (except excluded ones) to the watcher
+func (d *devWatcher) watchTree(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		if path != d.root && d.excluded(path+"/") {
+			return filepath.SkipDir
+		}
+		if err := watcher.Add(path); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// matches returns whether a changed path should trigger a rebuild
+func (d *devWatcher) matches(path string) bool {
+	rel := d.relative(path)
+	if d.excluded(rel) {
+		return false
+	}
+	for _, re := range d.include {
+		if re.MatchString(rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// excluded returns whether path matches any exclude glob
+func (d *devWatcher) excluded(path string) bool {
+	rel := d.relative(path)
+	for _, re := range d.exclude {
+		if re.MatchString(rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// relative returns path relative to the watched root, using forward slashes
+func (d *devWatcher) relative(path string) string {
+	if rel, err := filepath.Rel(d.root, path); err == nil {
+		path = rel
+	}
+	return filepath.ToSlash(path)
+}
+
+// build compiles the server binary
+func (d *devWatcher) build() error {
+	build := exec.Command("go", "build", "-o", d.binary, d.pkg)
+	build.Dir = d.root
+	output, err := build.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w\n%s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// startServer starts the freshly built binary with the same global flags
+func (d *devWatcher) startServer() {
+	server := exec.Command(d.binary, serverArgs()...)
+	server.Stdout = os.Stdout
+	server.Stderr = os.Stderr
+	server.Stdin = os.Stdin
+
+	if err := server.Start(); err != nil {
+		logger.Errorf("Failed to start server: %v", err)
+		return
+	}
+
+	exited := make(chan struct{})
+	go func() {
+		_ = server.Wait()
+		close(exited)
+	}()
+
+	d.server = server
+	d.exited = exited
+	logger.Info(color.GreenString("Server started (pid %d)", server.Process.Pid))
+}
+
+// stopServer sends SIGTERM to the running server and waits for it to exit
+func (d *devWatcher) stopServer() {
+	if d.server == nil {
+		return
+	}
+
+	_ = d.server.Process.Signal(syscall.SIGTERM)
+	select {
+	case <-d.exited:
+	case <-time.After(devStopTimeout):
+		logger.Warnf("Server did not stop within %s, killing it", devStopTimeout)
+		_ = d.server.Process.Kill()
+		<-d.exited
+	}
+
+	d.server = nil
+	d.exited = nil
+}
+
+// serverArgs returns the arguments the built binary is started with
+func serverArgs() []string {
+	return append([]string{"start"}, globalFlagArgs()...)
+}
+
+// globalFlagArgs returns the global flags this process was started with,
+// including the config file and overrides
+func globalFlagArgs() []string {
+	args := make([]string, 0)
+	if noColor {
+		args = append(args, "--no-color")
+	}
+	if noTimestamp {
+		args = append(args, "--no-timestamp")
+	}
+	if quiet {
+		args = append(args, "--quiet")
+	}
+	if configFile != "" {
+		args = append(args, "--config="+configFile)
+	}
+	for _, set := range configSets {
+		args = append(args, "--set="+set)
+	}
+	return args
+}
+
+// compileGlobs converts glob patterns into anchored regular expressions
+func compileGlobs(globs []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(globs))
+	for _, glob := range globs {
+		re, err := globToRegexp(glob)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %w", glob, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// globToRegexp converts a glob to a regular expression.
+// "**/" matches any number of directories, "**" matches anything,
+// "*" matches within a single path segment and "?" matches one character.
+func globToRegexp(glob string) (*regexp.Regexp, error) {
+	var pattern strings.Builder
+	pattern.WriteString("^")
+
+	for i := 0; i < len(glob); i++ {
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			pattern.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			pattern.WriteString(".*")
+			i++
+		case glob[i] == '*':
+			pattern.WriteString("[^/]*")
+		case glob[i] == '?':
+			pattern.WriteString("[^/]")
+		default:
+			pattern.WriteString(regexp.QuoteMeta(string(glob[i])))
+		}
+	}
+
+	pattern.WriteString("$")
+	return regexp.Compile(pattern.String())
+}
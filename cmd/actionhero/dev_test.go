@@ -0,0 +1,84 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGlobToRegexp(t *testing.T) {
+	tests := []struct {
+		glob  string
+		path  string
+		match bool
+	}{
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "internal/api/api.go", true},
+		{"**/*.go", "internal/api/api.txt", false},
+		{"*.go", "main.go", true},
+		{"*.go", "internal/main.go", false},
+		{"go.mod", "go.mod", true},
+		{"go.mod", "go.modx", false},
+		{"config*.yaml", "config.test.yaml", true},
+		{".git/**", ".git/HEAD", true},
+		{".git/**", ".github/workflows/test.yml", false},
+		{"**/*_test.go", "internal/api/api_test.go", true},
+		{"file?.txt", "file1.txt", true},
+		{"file?.txt", "file12.txt", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.glob+" "+tt.path, func(t *testing.T) {
+			re, err := globToRegexp(tt.glob)
+			if err != nil {
+				t.Fatalf("Failed to compile glob: %v", err)
+			}
+			if got := re.MatchString(tt.path); got != tt.match {
+				t.Errorf("Expected match=%v, got %v", tt.match, got)
+			}
+		})
+	}
+}
+
+func TestDevWatcher_Matches(t *testing.T) {
+	watcher, err := newDevWatcher(".", "./cmd/actionhero",
+		[]string{"**/*.go", "go.mod"},
+		[]string{"vendor/**", "**/*_test.go"})
+	if err != nil {
+		t.Fatalf("Failed to create watcher: %v", err)
+	}
+
+	tests := []struct {
+		path  string
+		match bool
+	}{
+		{"main.go", true},
+		{"internal/api/api.go", true},
+		{"go.mod", true},
+		{"README.md", false},
+		{"vendor/pkg/lib.go", false},
+		{"internal/api/api_test.go", false},
+	}
+
+	for _, tt := range tests {
+		if got := watcher.matches(tt.path); got != tt.match {
+			t.Errorf("matches(%q) = %v, want %v", tt.path, got, tt.match)
+		}
+	}
+}
+
+func TestServerArgs(t *testing.T) {
+	defer func(color, timestamp, q bool, file string, sets []string) {
+		noColor, noTimestamp, quiet, configFile, configSets = color, timestamp, q, file, sets
+	}(noColor, noTimestamp, quiet, configFile, configSets)
+
+	noColor, noTimestamp, quiet = true, false, true
+	configFile = "config/dev.yaml"
+	configSets = []string{"logger.level=debug", "server.web.port=9000"}
+
+	want := []string{"start", "--no-color", "--quiet", "--config=config/dev.yaml",
+		"--set=logger.level=debug", "--set=server.web.port=9000"}
+	got := serverArgs()
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+	"github.com/fatih/color"
+)
+
+// doctorDialTimeout bounds each connectivity check
+const doctorDialTimeout = 2 * time.Second
+
+// checkResult is the outcome of a single validate/doctor check
+type checkResult struct {
+	Name string
+	Err  error
+}
+
+// validateChecks returns one check per configuration validation problem,
+// or a single passing check if the configuration is valid
+func validateChecks(cfg *config.Config) []checkResult {
+	err := cfg.Validate()
+	if err == nil {
+		return []checkResult{{Name: "configuration values"}}
+	}
+
+	results := make([]checkResult, 0)
+	var joined interface{ Unwrap() []error }
+	if errors.As(err, &joined) {
+		for _, e := range joined.Unwrap() {
+			results = append(results, checkResult{Name: "configuration values", Err: e})
+		}
+		return results
+	}
+	return []checkResult{{Name: "configuration values", Err: err}}
+}
+
+// doctorChecks runs the validation checks plus connectivity checks against
+// the services and resources the configuration points at
+func doctorChecks(cfg *config.Config) []checkResult {
+	results := validateChecks(cfg)
+
+	results = append(results,
+		checkResult{Name: "redis reachable", Err: checkTCP(cfg.Redis.Host, cfg.Redis.Port)},
+		checkResult{Name: "database reachable", Err: checkTCP(cfg.Database.Host, cfg.Database.Port)},
+	)
+
+	if cfg.Server.Web.Enabled {
+		results = append(results, checkResult{
+			Name: "web port available",
+			Err:  checkPortAvailable(cfg.Server.Web.Host, cfg.Server.Web.Port),
+		})
+	}
+
+	if cfg.Server.Web.StaticFilesEnabled {
+		results = append(results, checkResult{
+			Name: "static files directory",
+			Err:  checkDirectory(cfg.Server.Web.StaticFilesDirectory),
+		})
+	}
+
+	return results
+}
+
+// checkTCP verifies that a TCP connection can be opened to host:port
+func checkTCP(host string, port int) error {
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+	conn, err := net.DialTimeout("tcp", address, doctorDialTimeout)
+	if err != nil {
+		return fmt.Errorf("cannot connect to %s: %w", address, err)
+	}
+	return conn.Close()
+}
+
+// checkPortAvailable verifies that the server could bind host:port
+func checkPortAvailable(host string, port int) error {
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("cannot listen on %s: %w", address, err)
+	}
+	return listener.Close()
+}
+
+// checkDirectory verifies that path exists and is a directory
+func checkDirectory(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("cannot read %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", path)
+	}
+	return nil
+}
+
+// printCheckReport prints the check results and returns the number of failures
+func printCheckReport(results []checkResult, logger *util.Logger) int {
+	okColor := color.New(color.FgGreen)
+	failColor := color.New(color.FgRed)
+
+	failures := 0
+	logger.Info("")
+	for _, result := range results {
+		if result.Err != nil {
+			failures++
+			logger.Info(fmt.Sprintf("  %s %s: %v", failColor.Sprint("✖"), result.Name, result.Err))
+		} else {
+			logger.Info(fmt.Sprintf("  %s %s", okColor.Sprint("✔"), result.Name))
+		}
+	}
+	logger.Info("")
+
+	if failures > 0 {
+		logger.Info(failColor.Sprintf("  %d of %d checks failed", failures, len(results)))
+	} else {
+		logger.Info(okColor.Sprintf("  All %d checks passed", len(results)))
+	}
+	logger.Info("")
+
+	return failures
+}
+
+// runChecks prints a report for the given checks and exits non-zero on failure
+func runChecks(results []checkResult, logger *util.Logger) {
+	if printCheckReport(results, logger) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+	"github.com/fatih/color"
+)
+
+func TestValidateChecks(t *testing.T) {
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	results := validateChecks(cfg)
+	if len(results) != 1 || results[0].Err != nil {
+		t.Errorf("Expected a single passing check, got %+v", results)
+	}
+
+	cfg.Logger.Level = "loud"
+	cfg.Server.Web.Port = 0
+	results = validateChecks(cfg)
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 failing checks, got %d", len(results))
+	}
+	for _, result := range results {
+		if result.Err == nil {
+			t.Errorf("Expected check %s to fail", result.Name)
+		}
+	}
+}
+
+func TestCheckTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	if err := checkTCP("127.0.0.1", port); err != nil {
+		t.Errorf("Expected open port to be reachable, got %v", err)
+	}
+
+	_ = listener.Close()
+	if err := checkTCP("127.0.0.1", port); err == nil {
+		t.Error("Expected closed port to be unreachable")
+	}
+}
+
+func TestCheckPortAvailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	if err := checkPortAvailable("127.0.0.1", port); err == nil {
+		t.Error("Expected port in use to be unavailable")
+	}
+}
+
+func TestCheckDirectory(t *testing.T) {
+	dir := t.TempDir()
+	if err := checkDirectory(dir); err != nil {
+		t.Errorf("Expected directory to pass, got %v", err)
+	}
+
+	file := filepath.Join(dir, "file.txt")
+	if err := os.WriteFile(file, []byte("x"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := checkDirectory(file); err == nil {
+		t.Error("Expected file to fail directory check")
+	}
+	if err := checkDirectory(filepath.Join(dir, "missing")); err == nil {
+		t.Error("Expected missing path to fail directory check")
+	}
+}
+
+func TestPrintCheckReport(t *testing.T) {
+	color.NoColor = true
+
+	var buf bytes.Buffer
+	logger := util.NewLogger(config.LoggerConfig{Level: "info"})
+	logger.SetOutput(&buf)
+
+	failures := printCheckReport([]checkResult{
+		{Name: "passing"},
+		{Name: "failing", Err: os.ErrNotExist},
+	}, logger)
+
+	if failures != 1 {
+		t.Errorf("Expected 1 failure, got %d", failures)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "passing") || !strings.Contains(output, "failing") {
+		t.Errorf("Expected both checks in output, got: %s", output)
+	}
+	if !strings.Contains(output, "1 of 2 checks failed") {
+		t.Errorf("Expected summary line in output, got: %s", output)
+	}
+}
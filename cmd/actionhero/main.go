@@ -9,12 +9,17 @@ import (
 	"os/signal"
 	"os/user"
 	"reflect"
+	"strings"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/evantahler/go-actionhero/actions"
 	"github.com/evantahler/go-actionhero/internal/api"
 	"github.com/evantahler/go-actionhero/internal/config"
-	"github.com/evantahler/go-actionhero/internal/servers"
+	"github.com/evantahler/go-actionhero/internal/i18n"
+	"github.com/evantahler/go-actionhero/internal/initializers"
+	_ "github.com/evantahler/go-actionhero/internal/servers" // Registers the web server
 	"github.com/evantahler/go-actionhero/internal/util"
 	"github.com/fatih/color"
 	"github.com/sirupsen/logrus"
@@ -27,6 +32,8 @@ var (
 	noColor     bool
 	noTimestamp bool
 	quiet       bool
+	configFile  string
+	configSets  []string
 
 	// Config and logger (set after LoadConfig)
 	cfg    *config.Config
@@ -53,11 +60,35 @@ var startCmd = &cobra.Command{
 	Use:   "start",
 	Short: "Start the ActionHero server",
 	Long:  `Start the ActionHero server and begin accepting connections.`,
-	Run: func(_ *cobra.Command, _ []string) {
+	Run: func(cmd *cobra.Command, _ []string) {
+		daemon, _ := cmd.Flags().GetBool("daemon")
+		if daemon && !isDaemonChild() {
+			pid, err := startDaemon(cfg.Process.PidFile, cfg.Process.LogFile)
+			if err != nil {
+				logger.Fatalf("Failed to start daemon: %v", err)
+			}
+			logger.Infof("Server started in the background with pid %d (pid file: %s, log file: %s)",
+				pid, cfg.Process.PidFile, cfg.Process.LogFile)
+			return
+		}
 		startServer()
 	},
 }
 
+// stopCmd represents the stop command
+var stopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop a daemonized ActionHero server",
+	Long:  `Send SIGTERM to the server started with 'start --daemon' (found via the pid file) and wait for it to exit.`,
+	Run: func(_ *cobra.Command, _ []string) {
+		pid, err := stopDaemon(cfg.Process.PidFile)
+		if err != nil {
+			logger.Fatalf("Failed to stop daemon: %v", err)
+		}
+		logger.Infof("Stopped server with pid %d", pid)
+	},
+}
+
 // configCmd represents the config command
 var configCmd = &cobra.Command{
 	Use:   "config",
@@ -72,41 +103,166 @@ var configCmd = &cobra.Command{
 			showWelcome()
 		}
 	},
+	Annotations: map[string]string{skipConfigValidation: "true"},
 	Run: func(cmd *cobra.Command, _ []string) {
 		format, _ := cmd.Flags().GetString("format")
+		if sources, _ := cmd.Flags().GetBool("sources"); sources {
+			dumpConfigSources(cfg, logger, format)
+			return
+		}
 		dumpConfig(cfg, logger, format)
 	},
 }
 
+// configValidateCmd represents the config validate command
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate the current configuration",
+	Long:  `Load the configuration and check types and ranges (ports, log levels, durations). Exits non-zero if any check fails.`,
+	PreRun: func(_ *cobra.Command, _ []string) {
+		disableTimestampsForCommand()
+	},
+	Annotations: map[string]string{skipConfigValidation: "true"},
+	Run: func(_ *cobra.Command, _ []string) {
+		runChecks(validateChecks(cfg), logger)
+	},
+}
+
+// configDoctorCmd represents the config doctor command
+var configDoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Validate the configuration and check connectivity",
+	Long:  `Validate the configuration, then attempt to reach Redis and the database, bind the web port, and read the static files directory. Exits non-zero if any check fails.`,
+	PreRun: func(_ *cobra.Command, _ []string) {
+		disableTimestampsForCommand()
+	},
+	Annotations: map[string]string{skipConfigValidation: "true"},
+	Run: func(_ *cobra.Command, _ []string) {
+		runChecks(doctorChecks(cfg), logger)
+	},
+}
+
+// configEncryptCmd represents the config encrypt command
+var configEncryptCmd = &cobra.Command{
+	Use:   "encrypt [value]",
+	Short: "Encrypt a value for use in config files",
+	Long:  `Encrypt a value with secrets.encryptionkey, printing an "enc:..." value that is decrypted when config is loaded. Reads the value from stdin when no argument is given. Use --generate-key to create a new key.`,
+	Args:  cobra.MaximumNArgs(1),
+	PreRun: func(_ *cobra.Command, _ []string) {
+		disableTimestampsForCommand()
+	},
+	Annotations: map[string]string{skipConfigValidation: "true"},
+	Run: func(cmd *cobra.Command, args []string) {
+		if generate, _ := cmd.Flags().GetBool("generate-key"); generate {
+			key, err := config.GenerateEncryptionKey()
+			if err != nil {
+				logger.Fatalf("%v", err)
+			}
+			fmt.Println(key)
+			return
+		}
+
+		var plaintext string
+		if len(args) == 1 {
+			plaintext = args[0]
+		} else {
+			data, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				logger.Fatalf("Failed to read value from stdin: %v", err)
+			}
+			plaintext = strings.TrimRight(string(data), "\r\n")
+		}
+
+		encrypted, err := config.EncryptValue(cfg.Secrets, plaintext)
+		if err != nil {
+			logger.Fatalf("Failed to encrypt value: %v", err)
+		}
+		fmt.Println(encrypted)
+	},
+}
+
+// configEnvDocsCmd represents the config env-docs command
+var configEnvDocsCmd = &cobra.Command{
+	Use:   "env-docs",
+	Short: "List the environment variables that set each config value",
+	Long:  `Print every ACTIONHERO_* environment variable, with the setting it sets, its type, and its default. The list is generated from the config itself (including registered sections and servers), so it can't drift; use --format markdown for runbooks and container docs.`,
+	PreRun: func(_ *cobra.Command, _ []string) {
+		disableTimestampsForCommand()
+	},
+	Annotations: map[string]string{skipConfigValidation: "true"},
+	Run: func(cmd *cobra.Command, _ []string) {
+		vars, err := config.EnvVars(config.DefaultEnvPrefix)
+		if err != nil {
+			logger.Fatalf("Failed to read config defaults: %v", err)
+		}
+		format, _ := cmd.Flags().GetString("format")
+		if err := writeEnvDocs(os.Stdout, vars, format); err != nil {
+			logger.Fatalf("%v", err)
+		}
+	},
+}
+
 func init() {
 	// Global flags (persistent across all commands)
 	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output")
 	rootCmd.PersistentFlags().BoolVar(&noTimestamp, "no-timestamp", false, "Disable timestamps in output")
 	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Quiet mode (hide logging output)")
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "Config file (or config directory) to read instead of config/ or config.{yaml,yml,toml,json}")
+	rootCmd.PersistentFlags().StringArrayVar(&configSets, "set", nil, "Override a config key (e.g., --set server.web.port=9000); repeatable")
+
+	// Start command flags
+	startCmd.Flags().Bool("daemon", false, "Run the server in the background (see process.pidfile and process.logfile)")
+
+	// Dev command flags
+	devCmd.Flags().StringSlice("include", []string{"**/*.go", "go.mod", "go.sum", "config*.yaml", "config*.yml", "config*.toml", "config*.json", "config/*", ".env*"},
+		"Globs of files that trigger a rebuild")
+	devCmd.Flags().StringSlice("exclude", []string{".git/**", "vendor/**", "log/**", "**/*_test.go"},
+		"Globs of files to ignore")
+	devCmd.Flags().String("build", "./cmd/actionhero", "Package to build")
+
+	// New command flags
+	newCmd.Flags().String("module", "", "Go module path (default: the project name)")
+	newCmd.Flags().String("framework-path", "", "Local path to go-actionhero (adds a replace directive)")
+	newCmd.Flags().Bool("force", false, "Write into an existing non-empty directory")
 
 	// Config command flags
 	configCmd.Flags().String("format", "list", "Output format: list or json")
+	configCmd.Flags().Bool("sources", false, "Show where each value came from (default, config file, env var, or --set)")
+	configEncryptCmd.Flags().Bool("generate-key", false, "Print a new random encryption key instead")
+	configEnvDocsCmd.Flags().String("format", "list", "Output format: list, json, or markdown")
 
 	// Add subcommands
 	rootCmd.AddCommand(startCmd)
+	rootCmd.AddCommand(stopCmd)
+	rootCmd.AddCommand(devCmd)
+	rootCmd.AddCommand(newCmd)
+	rootCmd.AddCommand(taskCmd)
+	taskCmd.AddCommand(taskWorkerCmd)
+	rootCmd.AddCommand(dbCmd)
+	dbCmd.AddCommand(dbSeedCmd)
 	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configValidateCmd)
+	configCmd.AddCommand(configDoctorCmd)
+	configCmd.AddCommand(configEncryptCmd)
+	configCmd.AddCommand(configEnvDocsCmd)
 
 	// Register action commands
 	registerActionCommands()
+	registerSwaggerCommands()
 }
 
 // registerActionCommands adds each action as a CLI command
 func registerActionCommands() {
 	// Get all auto-registered actions
 	for _, action := range actions.GetAll() {
-		addActionCommand(action)
+		addActionCommand(api.DescribeAction(action))
 	}
 }
 
 // addActionCommand creates a CLI command for an action
-func addActionCommand(action api.Action) {
-	actionName := api.GetActionName(action)
-	actionDesc := api.GetActionDescription(action)
+func addActionCommand(action *api.ActionDescriptor) {
+	actionName := action.Name
+	actionDesc := action.Description
 
 	cmd := &cobra.Command{
 		Use:   actionName,
@@ -114,12 +270,12 @@ func addActionCommand(action api.Action) {
 		Long: fmt.Sprintf("Run action: %s\n\n%s\n\nInputs should be passed as flags. The server will be initialized and started, and the action will be executed via a CLI connection.",
 			actionName, actionDesc),
 		Run: func(cmd *cobra.Command, args []string) {
-			runActionViaCLI(cmd, action)
+			runActionViaCLI(cmd, actionName)
 		},
 	}
 
 	// Add flags for action inputs
-	inputs := api.GetActionInputs(action)
+	inputs := action.Inputs
 	if inputs != nil {
 		inputType := reflect.TypeOf(inputs)
 		if inputType.Kind() == reflect.Struct {
@@ -164,20 +320,42 @@ func addActionCommand(action api.Action) {
 		}
 	}
 
+	// The run's own flags, unless an input already uses the name
+	if cmd.Flags().Lookup("timeout") == nil {
+		cmd.Flags().Duration("timeout", 0, "Abort the action if it runs longer than this (e.g., 30s)")
+		_ = cmd.Flags().SetAnnotation("timeout", cliRunFlag, []string{"true"})
+	}
+	if cmd.Flags().Lookup("dry-run") == nil {
+		cmd.Flags().Bool("dry-run", false, "Show what the action would do instead of running it, if it changes anything")
+		_ = cmd.Flags().SetAnnotation("dry-run", cliRunFlag, []string{"true"})
+	}
+
 	rootCmd.AddCommand(cmd)
 }
 
-// runActionViaCLI executes an action via CLI connection
-func runActionViaCLI(cmd *cobra.Command, action api.Action) {
-	// Create API instance
-	apiInstance := api.New(cfg, logger)
+// cliRunFlag is a flag annotation for flags of an action command that
+// configure the run, rather than being inputs of the action
+const cliRunFlag = "cliRunFlag"
 
-	// Register all actions
-	for _, action := range actions.GetAll() {
-		if err := apiInstance.RegisterAction(action); err != nil {
-			logger.Fatalf("Failed to register action: %v", err)
-		}
-	}
+// Exit codes of an action run from the CLI that doesn't succeed
+const (
+	exitCodeActionError       = 1
+	exitCodeActionTimeout     = 124 // As timeout(1)
+	exitCodeActionInterrupted = 130 // 128 + SIGINT
+)
+
+// cliCancelGrace is how long an interrupted or timed out action has to
+// return before the CLI exits without it
+const cliCancelGrace = 5 * time.Second
+
+// cliHeartbeatInterval is how often the CLI repeats an action's latest
+// progress while the action runs
+const cliHeartbeatInterval = 5 * time.Second
+
+// runActionViaCLI executes an action via CLI connection
+func runActionViaCLI(cmd *cobra.Command, actionName string) {
+	// Create API instance with all actions registered
+	apiInstance := newAPI()
 
 	// Initialize API (but don't start servers)
 	if err := apiInstance.Initialize(); err != nil {
@@ -194,40 +372,48 @@ func runActionViaCLI(cmd *cobra.Command, action api.Action) {
 
 	// Create CLI connection
 	conn := api.NewConnection("cli", connectionID, connectionID, nil)
+	conn.SetLocales(cliLocales()...)
+	conn.SetClientInfo(api.ClientInfo{RemoteIP: "127.0.0.1", UserAgent: "actionhero-cli", Protocol: "cli"})
 
-	// Collect parameters from flags
+	// Collect parameters from the action's own flags (not the global ones)
 	params := make(map[string]interface{})
+	inherited := cmd.InheritedFlags()
 	cmd.Flags().Visit(func(flag *pflag.Flag) {
-		// Skip global flags
-		if flag.Name == "no-color" || flag.Name == "no-timestamp" || flag.Name == "quiet" {
+		if inherited.Lookup(flag.Name) != nil || flag.Annotations[cliRunFlag] != nil {
 			return
 		}
 		params[flag.Name] = flag.Value.String()
 	})
 
-	// Execute action
-	actionName := api.GetActionName(action)
-	result := conn.Act(context.Background(), apiInstance, actionName, params, "CLI", "")
+	// Execute action, canceling its context on SIGINT/SIGTERM or --timeout
+	var timeout time.Duration
+	if flag := cmd.Flags().Lookup("timeout"); flag != nil && flag.Annotations[cliRunFlag] != nil {
+		timeout, _ = cmd.Flags().GetDuration("timeout")
+	}
+	if flag := cmd.Flags().Lookup("dry-run"); flag != nil && flag.Annotations[cliRunFlag] != nil {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		conn.SetDryRun(dryRun)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	stopProgress := func() {}
+	if !quiet {
+		progress := newCLIProgress(os.Stderr)
+		conn.SetProgressHandler(progress.report)
+		stopProgress = progress.heartbeat(cliHeartbeatInterval)
+	}
+	result, exitCode := actWithCancellation(ctx, stop, timeout, cliCancelGrace, func(ctx context.Context) api.ActResult {
+		return conn.Act(ctx, apiInstance, actionName, params, "CLI", "")
+	})
+	stopProgress()
 
 	// Prepare output
 	output := map[string]interface{}{
 		"response": result.Response,
 	}
-	exitCode := 0
 
 	if result.Error != nil {
-		exitCode = 1
-		if typedErr, ok := result.Error.(*util.TypedError); ok {
-			output["error"] = map[string]interface{}{
-				"message": typedErr.Message,
-				"code":    typedErr.Code(),
-				"type":    typedErr.Type,
-			}
-		} else {
-			output["error"] = map[string]interface{}{
-				"message": result.Error.Error(),
-			}
-		}
+		output["error"] = apiInstance.ErrorJSON(result.Error, result.RequestID, result.Locales...)
 	}
 
 	// Output JSON to stdout (or stderr if error)
@@ -245,26 +431,157 @@ func runActionViaCLI(cmd *cobra.Command, action api.Action) {
 	os.Exit(exitCode)
 }
 
+// actWithCancellation runs act with ctx, limited to timeout when it is set,
+// and returns its result and the process's exit code. Once ctx is done (the
+// CLI was interrupted) or the timeout passes, the action has grace to return
+// before the result is given up on; stop is called then, so that another
+// interrupt kills the process.
+func actWithCancellation(ctx context.Context, stop context.CancelFunc, timeout, grace time.Duration, act func(context.Context) api.ActResult) (api.ActResult, int) {
+	interrupted := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	done := make(chan api.ActResult, 1)
+	go func() { done <- act(ctx) }()
+
+	var result api.ActResult
+	finished := true
+	select {
+	case result = <-done:
+	case <-ctx.Done():
+		stop()
+		select {
+		case result = <-done:
+		case <-time.After(grace):
+			finished = false
+		}
+	}
+
+	if finished && result.Error == nil {
+		return result, 0 // Even if it finished after being canceled
+	}
+	switch {
+	case interrupted.Err() != nil:
+		result.Response = nil
+		result.Error = util.NewTypedError(util.ErrorTypeConnectionActionRun, "action was interrupted",
+			util.WithOriginalError(interrupted.Err()))
+		return result, exitCodeActionInterrupted
+	case ctx.Err() != nil && timeout > 0:
+		result.Response = nil
+		result.Error = util.NewTypedError(util.ErrorTypeConnectionActionTimeout,
+			fmt.Sprintf("action timed out after %s", timeout),
+			util.WithOriginalError(ctx.Err()))
+		return result, exitCodeActionTimeout
+	}
+	return result, exitCodeActionError
+}
+
+// cliProgress prints an action's progress reports as JSON lines, and repeats
+// the latest as a heartbeat while the action runs, so operators can tell a
+// long action hasn't hung
+type cliProgress struct {
+	w     io.Writer
+	start time.Time
+
+	mu   sync.Mutex
+	last *api.Progress
+}
+
+// newCLIProgress creates a progress printer writing to w
+func newCLIProgress(w io.Writer) *cliProgress {
+	return &cliProgress{w: w, start: time.Now()}
+}
+
+// report prints progress, and keeps it for the heartbeat
+func (p *cliProgress) report(progress api.Progress) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.last = &progress
+	p.print("progress", progress)
+}
+
+// heartbeat repeats the latest progress every interval, once the action has
+// reported some, until the returned func is called
+func (p *cliProgress) heartbeat(interval time.Duration) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				p.mu.Lock()
+				if p.last != nil {
+					p.print("heartbeat", *p.last)
+				}
+				p.mu.Unlock()
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// print writes progress as a JSON line of the given type. The caller holds
+// p.mu.
+func (p *cliProgress) print(kind string, progress api.Progress) {
+	line, _ := json.Marshal(map[string]interface{}{
+		"type":      kind,
+		"percent":   progress.Percent,
+		"message":   progress.Message,
+		"elapsedMs": time.Since(p.start).Milliseconds(),
+	})
+	fmt.Fprintln(p.w, string(line))
+}
+
+// cliLocales returns the user's locale from the environment (LC_ALL, then
+// LANG), e.g. "fr_FR.UTF-8" gives fr-FR
+func cliLocales() []string {
+	for _, name := range []string{"LC_ALL", "LANG"} {
+		locale, _, _ := strings.Cut(os.Getenv(name), ".")
+		if locale != "" && locale != "C" && locale != "POSIX" {
+			return i18n.ParseAcceptLanguage(locale)
+		}
+	}
+	return nil
+}
+
+// skipConfigValidation is a command annotation for commands that load the
+// configuration without failing on validation errors
+const skipConfigValidation = "skipConfigValidation"
+
 // loadConfigAndInitLogger loads configuration and initializes the logger
 // This runs before any command execution
-func loadConfigAndInitLogger(_ *cobra.Command, _ []string) error {
-	var err error
-
-	// Load configuration
-	cfg, err = config.Load()
+func loadConfigAndInitLogger(cmd *cobra.Command, _ []string) error {
+	opts, err := configLoadOptions()
 	if err != nil {
 		_, _ = color.New(color.FgRed).Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Override config with CLI flags
-	if noColor {
-		cfg.Logger.Colorize = false
+	// Load configuration. Commands that inspect the configuration load it
+	// without validation, so they can report every problem themselves.
+	if cmd.Annotations[skipConfigValidation] == "true" {
+		cfg, err = config.LoadWithoutValidation(opts...)
+	} else {
+		cfg, err = config.Load(opts...)
 	}
-	if noTimestamp {
-		cfg.Logger.Timestamp = false
+	if err != nil {
+		_, _ = color.New(color.FgRed).Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
+		os.Exit(1)
 	}
 
+	applyCLIOverrides(cfg)
+
 	// Initialize logger
 	logger = util.NewLogger(cfg.Logger)
 
@@ -281,6 +598,64 @@ func loadConfigAndInitLogger(_ *cobra.Command, _ []string) error {
 	return nil
 }
 
+// configLoadOptions returns the config load options set by --config and --set
+func configLoadOptions() ([]config.LoadOption, error) {
+	var opts []config.LoadOption
+	if configFile != "" {
+		opts = append(opts, config.WithConfigFile(configFile))
+	}
+
+	if len(configSets) > 0 {
+		overrides := make(map[string]string, len(configSets))
+		for _, set := range configSets {
+			key, value, ok := strings.Cut(set, "=")
+			if !ok || strings.TrimSpace(key) == "" {
+				return nil, fmt.Errorf("invalid --set %q: use key=value (e.g., --set server.web.port=9000)", set)
+			}
+			overrides[strings.TrimSpace(key)] = value
+		}
+		opts = append(opts, config.WithOverrides(overrides))
+	}
+
+	return opts, nil
+}
+
+// applyCLIOverrides overrides loaded config with CLI flags
+func applyCLIOverrides(cfg *config.Config) {
+	if noColor {
+		cfg.Logger.Colorize = false
+	}
+	if noTimestamp {
+		cfg.Logger.Timestamp = false
+	}
+
+	// Daemon output goes to a log file, so skip terminal colors
+	if isDaemonChild() {
+		cfg.Logger.Colorize = false
+	}
+}
+
+// watchConfig reloads the API's config when config or .env files change
+func watchConfig(apiInstance *api.API) *config.Watcher {
+	// Reloads keep the --config file and --set overrides (already validated at startup)
+	opts, _ := configLoadOptions()
+	watcher, err := config.Watch(func(next *config.Config, err error) {
+		if err != nil {
+			logger.Errorf("Config reload failed, keeping the current config: %v", err)
+			return
+		}
+		applyCLIOverrides(next)
+		apiInstance.ReloadConfig(next)
+	}, opts...)
+	if err != nil {
+		logger.Warnf("Config hot reload disabled: %v", err)
+		return nil
+	}
+
+	logger.Info("Watching config files for changes")
+	return watcher
+}
+
 // disableTimestampsForCommand disables timestamps in the logger for display commands
 func disableTimestampsForCommand() {
 	if logger != nil && !noTimestamp {
@@ -291,37 +666,72 @@ func disableTimestampsForCommand() {
 	}
 }
 
+// bannerLine frames the welcome message and boot report
+const bannerLine = "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"
+
 // showWelcome displays the welcome message
 func showWelcome() {
-	headerLine := "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"
 	titleLine := "  🚀 Go ActionHero"
 
-	logger.Info(color.New(color.FgBlue, color.Bold).Sprint(headerLine))
+	logger.Info(color.New(color.FgBlue, color.Bold).Sprint(bannerLine))
 	logger.Info(color.New(color.FgBlue, color.Bold).Sprint(titleLine))
-	logger.Info(color.New(color.FgBlue, color.Bold).Sprint(headerLine))
+	logger.Info(color.New(color.FgBlue, color.Bold).Sprint(bannerLine))
 	logger.Info(color.New(color.FgCyan).Sprintf("  Process: %s", cfg.Process.Name))
 	logger.Info(color.New(color.FgCyan).Sprintf("  Logger Level: %s", cfg.Logger.Level))
 	logger.Info(color.New(color.FgCyan).Sprintf("  Web Server: %s:%d", cfg.Server.Web.Host, cfg.Server.Web.Port))
-	logger.Info(color.New(color.FgBlue, color.Bold).Sprint(headerLine))
+	logger.Info(color.New(color.FgBlue, color.Bold).Sprint(bannerLine))
 }
 
-// startServer initializes and starts the ActionHero server
-func startServer() {
-	showWelcome()
+// showBootReport displays what started, where it listens, and how long each
+// component took
+func showBootReport(apiInstance *api.API) {
+	report, ok := apiInstance.BootReport()
+	if !ok {
+		return
+	}
 
-	// Create API instance
-	apiInstance := api.New(cfg, logger)
+	banner := color.New(color.FgBlue, color.Bold)
+	info := color.New(color.FgCyan)
+	detail := color.New(color.FgHiBlack)
 
-	// Register all actions
-	for _, action := range actions.GetAll() {
-		if err := apiInstance.RegisterAction(action); err != nil {
-			logger.Fatalf("Failed to register action: %v", err)
+	environment := report.Environment
+	if environment == "" {
+		environment = "default"
+	}
+
+	logger.Info(banner.Sprint(bannerLine))
+	logger.Info(banner.Sprintf("  🚀 Go ActionHero started in %s", report.Duration.Round(time.Millisecond)))
+	logger.Info(banner.Sprint(bannerLine))
+	logger.Info(info.Sprintf("  Process: %s (environment: %s)", report.Process, environment))
+	logger.Info(info.Sprintf("  Actions: %d", report.Actions))
+	for _, server := range report.Servers {
+		addresses := strings.Join(server.Addresses, ", ")
+		if addresses == "" {
+			addresses = "no network address"
 		}
+		logger.Info(info.Sprintf("  Server %s: %s", server.Name, addresses) +
+			detail.Sprintf(" (%s)", bootTimings(server)))
+	}
+	for _, initializer := range report.Initializers {
+		logger.Info(info.Sprintf("  Initializer %s", initializer.Name) +
+			detail.Sprintf(" (%s)", bootTimings(initializer)))
 	}
+	logger.Info(banner.Sprint(bannerLine))
+}
+
+// bootTimings formats how long a component took to initialize and start
+func bootTimings(component api.ComponentBootReport) string {
+	return fmt.Sprintf("initialize %s, start %s",
+		component.Initialize.Round(time.Microsecond), component.Start.Round(time.Microsecond))
+}
 
-	// Register web server
-	webServer := servers.NewWebServer(apiInstance)
-	apiInstance.RegisterServer(webServer)
+// startServer initializes and starts the ActionHero server
+func startServer() {
+	// Create API instance with all actions registered
+	apiInstance := newAPI()
+
+	// Register the servers enabled in the config
+	apiInstance.RegisterConfiguredServers()
 
 	// Initialize API
 	logger.Info("Initializing...")
@@ -335,8 +745,37 @@ func startServer() {
 		logger.Fatalf("Failed to start: %v", err)
 	}
 
+	showBootReport(apiInstance)
 	logger.Info(color.GreenString("Server is running! Press Ctrl+C to stop."))
 
+	if cfg.Process.WatchConfig {
+		if watcher := watchConfig(apiInstance); watcher != nil {
+			defer func() { _ = watcher.Close() }()
+		}
+	}
+
+	waitForShutdown(apiInstance)
+
+	logger.Info(color.GreenString("Server stopped successfully"))
+}
+
+// newAPI creates an API instance with the built-in initializers (the same as
+// actionhero.New) and all actions registered
+func newAPI() *api.API {
+	apiInstance := api.New(cfg, logger)
+	initializers.Register(apiInstance)
+
+	for _, action := range actions.GetAll() {
+		if err := apiInstance.RegisterAction(action); err != nil {
+			logger.Fatalf("Failed to register action: %v", err)
+		}
+	}
+
+	return apiInstance
+}
+
+// waitForShutdown blocks until SIGINT/SIGTERM and then stops the API gracefully
+func waitForShutdown(apiInstance *api.API) {
 	// Wait for interrupt signal
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
@@ -349,7 +788,10 @@ func startServer() {
 		os.Exit(1)
 	}
 
-	logger.Info(color.GreenString("Server stopped successfully"))
+	if isDaemonChild() {
+		_ = os.Remove(cfg.Process.PidFile)
+	}
+	_ = logger.Close()
 }
 
 func main() {
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+func TestActWithCancellation(t *testing.T) {
+	// waitForCancel is an action that returns once its context is done
+	waitForCancel := func(ctx context.Context) api.ActResult {
+		<-ctx.Done()
+		return api.ActResult{Error: ctx.Err(), RequestID: "req-1"}
+	}
+	// ignoreCancel is an action that never notices its context is done
+	block := make(chan struct{})
+	defer close(block)
+	ignoreCancel := func(_ context.Context) api.ActResult {
+		<-block
+		return api.ActResult{Response: "late"}
+	}
+
+	errorType := func(err error) util.ErrorType {
+		var typedErr *util.TypedError
+		if !errors.As(err, &typedErr) {
+			return ""
+		}
+		return typedErr.Type
+	}
+
+	t.Run("success", func(t *testing.T) {
+		result, code := actWithCancellation(context.Background(), func() {}, time.Second, time.Second,
+			func(_ context.Context) api.ActResult { return api.ActResult{Response: "ok"} })
+		if code != 0 || result.Response != "ok" || result.Error != nil {
+			t.Errorf("Expected the response with exit code 0, got %v, %v (%d)", result.Response, result.Error, code)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, code := actWithCancellation(context.Background(), func() {}, 0, time.Second,
+			func(_ context.Context) api.ActResult { return api.ActResult{Error: errors.New("boom")} })
+		if code != exitCodeActionError {
+			t.Errorf("Expected exit code %d, got %d", exitCodeActionError, code)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		stopped := false
+		result, code := actWithCancellation(context.Background(), func() { stopped = true }, 20*time.Millisecond, time.Second, waitForCancel)
+		if code != exitCodeActionTimeout {
+			t.Errorf("Expected exit code %d, got %d", exitCodeActionTimeout, code)
+		}
+		if errorType(result.Error) != util.ErrorTypeConnectionActionTimeout {
+			t.Errorf("Expected a timeout error, got %v", result.Error)
+		}
+		if result.RequestID != "req-1" {
+			t.Errorf("Expected the action's request ID, got %q", result.RequestID)
+		}
+		if !stopped {
+			t.Error("Expected the signal handling to be stopped")
+		}
+	})
+
+	t.Run("interrupted", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(20*time.Millisecond, cancel)
+		result, code := actWithCancellation(ctx, cancel, time.Minute, time.Second, waitForCancel)
+		if code != exitCodeActionInterrupted {
+			t.Errorf("Expected exit code %d, got %d", exitCodeActionInterrupted, code)
+		}
+		if errorType(result.Error) != util.ErrorTypeConnectionActionRun {
+			t.Errorf("Expected an interrupted error, got %v", result.Error)
+		}
+	})
+
+	t.Run("action ignores cancellation", func(t *testing.T) {
+		start := time.Now()
+		result, code := actWithCancellation(context.Background(), func() {}, 20*time.Millisecond, 20*time.Millisecond, ignoreCancel)
+		if code != exitCodeActionTimeout {
+			t.Errorf("Expected exit code %d, got %d", exitCodeActionTimeout, code)
+		}
+		if result.Response != nil {
+			t.Errorf("Expected no response, got %v", result.Response)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Expected to give up after the grace period, took %s", elapsed)
+		}
+	})
+}
+
+func TestCLIProgress(t *testing.T) {
+	var buf bytes.Buffer
+	var mu sync.Mutex
+	progress := newCLIProgress(writerFunc(func(p []byte) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return buf.Write(p)
+	}))
+	lines := func() []map[string]interface{} {
+		mu.Lock()
+		defer mu.Unlock()
+		var lines []map[string]interface{}
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			var parsed map[string]interface{}
+			if err := json.Unmarshal([]byte(line), &parsed); err != nil {
+				t.Fatalf("Expected a JSON line, got %q", line)
+			}
+			lines = append(lines, parsed)
+		}
+		return lines
+	}
+
+	// No heartbeat until the action reports progress
+	stop := progress.heartbeat(10 * time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+	if got := lines(); len(got) != 0 {
+		t.Fatalf("Expected no output before any progress, got %v", got)
+	}
+
+	progress.report(api.Progress{Percent: 40, Message: "importing"})
+	time.Sleep(50 * time.Millisecond)
+	stop()
+
+	got := lines()
+	if len(got) < 2 {
+		t.Fatalf("Expected the progress and heartbeats, got %v", got)
+	}
+	if got[0]["type"] != "progress" || got[0]["percent"] != 40.0 || got[0]["message"] != "importing" {
+		t.Errorf("Expected the progress line first, got %v", got[0])
+	}
+	for _, line := range got[1:] {
+		if line["type"] != "heartbeat" || line["percent"] != 40.0 || line["elapsedMs"] == nil {
+			t.Errorf("Expected a heartbeat repeating the progress, got %v", line)
+		}
+	}
+
+	// Nothing is printed once stopped
+	count := len(got)
+	time.Sleep(30 * time.Millisecond)
+	if len(lines()) != count {
+		t.Errorf("Expected no heartbeats after stopping")
+	}
+}
+
+// writerFunc adapts a func to io.Writer
+type writerFunc func([]byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+)
+
+//go:embed templates/new
+var newProjectTemplates embed.FS
+
+// newProjectTemplateRoot is the embedded directory holding the project templates
+const newProjectTemplateRoot = "templates/new"
+
+// dotfileTemplates maps template names to the dotfiles they produce, so the
+// templates themselves stay visible in the source tree
+var dotfileTemplates = map[string]string{
+	"env.example": ".env.example",
+	"gitignore":   ".gitignore",
+}
+
+// projectNameRegex restricts project names to safe directory/binary names
+var projectNameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]*$`)
+
+// newProjectOptions holds the values available to the project templates
+type newProjectOptions struct {
+	Name          string // Directory and binary name
+	Module        string // Go module path
+	FrameworkPath string // Optional local path to go-actionhero (adds a replace directive)
+}
+
+// newCmd represents the new command
+var newCmd = &cobra.Command{
+	Use:   "new <name>",
+	Short: "Create a new ActionHero application",
+	Long: `Create a new application directory with a go.mod, an example action and test,
+config files, and a Dockerfile, wired to the Go ActionHero framework.`,
+	Args: cobra.ExactArgs(1),
+	PreRun: func(_ *cobra.Command, _ []string) {
+		disableTimestampsForCommand()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		module, _ := cmd.Flags().GetString("module")
+		frameworkPath, _ := cmd.Flags().GetString("framework-path")
+		force, _ := cmd.Flags().GetBool("force")
+
+		opts := newProjectOptions{
+			Name:          filepath.Base(args[0]),
+			Module:        module,
+			FrameworkPath: frameworkPath,
+		}
+		if opts.Module == "" {
+			opts.Module = opts.Name
+		}
+
+		files, err := createProject(args[0], opts, force)
+		if err != nil {
+			logger.Fatalf("Failed to create project: %v", err)
+		}
+
+		for _, file := range files {
+			logger.Infof("  created %s", file)
+		}
+		logger.Info("")
+		logger.Infof("Project %s created. Next steps:", opts.Name)
+		logger.Infof("  cd %s && go mod tidy && go test ./... && go run .", args[0])
+	},
+}
+
+// createProject renders the project templates into dir and returns the
+// relative paths of the created files
+func createProject(dir string, opts newProjectOptions, force bool) ([]string, error) {
+	if !projectNameRegex.MatchString(opts.Name) {
+		return nil, fmt.Errorf("invalid project name %q: use letters, digits, '-' and '_'", opts.Name)
+	}
+
+	if opts.FrameworkPath != "" {
+		absPath, err := filepath.Abs(opts.FrameworkPath)
+		if err != nil {
+			return nil, fmt.Errorf("invalid framework path: %w", err)
+		}
+		opts.FrameworkPath = absPath
+	}
+
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 && !force {
+		return nil, fmt.Errorf("directory %s already exists and is not empty (use --force to overwrite)", dir)
+	}
+
+	created := make([]string, 0)
+	err := fs.WalkDir(newProjectTemplates, newProjectTemplateRoot, func(templatePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !strings.HasSuffix(templatePath, ".tmpl") {
+			return nil
+		}
+
+		relPath := strings.TrimSuffix(strings.TrimPrefix(templatePath, newProjectTemplateRoot+"/"), ".tmpl")
+		if dotfile, ok := dotfileTemplates[path.Base(relPath)]; ok {
+			relPath = path.Join(path.Dir(relPath), dotfile)
+		}
+
+		content, err := renderProjectTemplate(templatePath, opts)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(relPath))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", relPath, err)
+		}
+		if err := os.WriteFile(target, content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", relPath, err)
+		}
+
+		created = append(created, relPath)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// renderProjectTemplate executes a single embedded template
+func renderProjectTemplate(templatePath string, opts newProjectOptions) ([]byte, error) {
+	source, err := newProjectTemplates.ReadFile(templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template %s: %w", templatePath, err)
+	}
+
+	tmpl, err := template.New(path.Base(templatePath)).Parse(string(source))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", templatePath, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, opts); err != nil {
+		return nil, fmt.Errorf("failed to render template %s: %w", templatePath, err)
+	}
+	return buf.Bytes(), nil
+}
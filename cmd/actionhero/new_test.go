@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCreateProject(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "myapp")
+
+	files, err := createProject(dir, newProjectOptions{Name: "myapp", Module: "github.com/me/myapp"}, false)
+	if err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	expected := []string{
+		"go.mod",
+		"main.go",
+		"actions/actions.go",
+		"actions/hello.go",
+		"actions/hello_test.go",
+		"config/default.yaml",
+		".env.example",
+		".gitignore",
+		"Dockerfile",
+		"README.md",
+	}
+	if len(files) != len(expected) {
+		t.Errorf("Expected %d files, got %d: %v", len(expected), len(files), files)
+	}
+	for _, file := range expected {
+		if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+			t.Errorf("Expected file %s to exist: %v", file, err)
+		}
+	}
+
+	goMod, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		t.Fatalf("Failed to read go.mod: %v", err)
+	}
+	if !strings.Contains(string(goMod), "module github.com/me/myapp") {
+		t.Errorf("Expected module path in go.mod, got:\n%s", goMod)
+	}
+
+	mainGo, err := os.ReadFile(filepath.Join(dir, "main.go"))
+	if err != nil {
+		t.Fatalf("Failed to read main.go: %v", err)
+	}
+	if !strings.Contains(string(mainGo), `"github.com/me/myapp/actions"`) {
+		t.Errorf("Expected actions import in main.go, got:\n%s", mainGo)
+	}
+}
+
+func TestCreateProject_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := createProject(filepath.Join(dir, "bad"), newProjectOptions{Name: "bad name", Module: "bad"}, false); err == nil {
+		t.Error("Expected error for invalid project name")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "existing.txt"), []byte("x"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if _, err := createProject(dir, newProjectOptions{Name: "app", Module: "app"}, false); err == nil {
+		t.Error("Expected error for non-empty directory")
+	}
+	if _, err := createProject(dir, newProjectOptions{Name: "app", Module: "app"}, true); err != nil {
+		t.Errorf("Expected --force to allow non-empty directory, got %v", err)
+	}
+}
+
+func TestCreateProject_BuildsAndTests(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping generated project build in short mode")
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	projectRoot := filepath.Join(wd, "..", "..")
+
+	dir := filepath.Join(t.TempDir(), "myapp")
+	opts := newProjectOptions{Name: "myapp", Module: "example.com/myapp", FrameworkPath: projectRoot}
+	if _, err := createProject(dir, opts, false); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	for _, args := range [][]string{{"mod", "tidy"}, {"vet", "./..."}, {"test", "./..."}} {
+		cmd := exec.Command("go", args...)
+		cmd.Dir = dir
+		var output bytes.Buffer
+		cmd.Stdout = &output
+		cmd.Stderr = &output
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("go %s failed: %v\n%s", strings.Join(args, " "), err, output.String())
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// taskCmd groups the background task commands
+var taskCmd = &cobra.Command{
+	Use:   "task",
+	Short: "Background task commands",
+}
+
+// taskWorkerCmd represents the task worker command
+var taskWorkerCmd = &cobra.Command{
+	Use:   "worker",
+	Short: "Start a worker-only process",
+	Long: `Start the ActionHero initializers (including the task workers) without any servers,
+so web and worker tiers can be scaled independently from the same binary.`,
+	Run: func(_ *cobra.Command, _ []string) {
+		startWorker()
+	},
+}
+
+// startWorker initializes and starts the API without registering any servers
+func startWorker() {
+	if !cfg.Tasks.Enabled {
+		logger.Fatalf("Tasks are disabled (tasks.enabled=false); refusing to start a worker")
+	}
+
+	// Create API instance with all actions registered (no servers)
+	apiInstance := newAPI()
+
+	logger.Info("Initializing...")
+	if err := apiInstance.Initialize(); err != nil {
+		logger.Fatalf("Failed to initialize: %v", err)
+	}
+
+	logger.Info("Starting...")
+	if err := apiInstance.Start(); err != nil {
+		logger.Fatalf("Failed to start: %v", err)
+	}
+
+	showBootReport(apiInstance)
+	logger.Info(color.GreenString("Worker is running (queues: %v)! Press Ctrl+C to stop.", cfg.Tasks.Queues))
+
+	waitForShutdown(apiInstance)
+
+	logger.Info(color.GreenString("Worker stopped successfully"))
+}
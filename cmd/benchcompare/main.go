@@ -0,0 +1,167 @@
+// Command benchcompare compares two sets of `go test -bench -benchmem`
+// results and fails when a benchmark got slower or allocates more than a
+// threshold, so performance-motivated changes can be checked before merging:
+//
+//	go test -run '^$' -bench . -benchmem -count 5 ./... > base.txt
+//	# ...make the change...
+//	go test -run '^$' -bench . -benchmem -count 5 ./... > new.txt
+//	go run ./cmd/benchcompare -threshold 10 base.txt new.txt
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Metrics compared, in output order
+var metrics = []string{"ns/op", "B/op", "allocs/op"}
+
+// results maps "package.Benchmark" to each metric's samples
+type results map[string]map[string][]float64
+
+func main() {
+	threshold := flag.Float64("threshold", 10, "percent increase of a metric that counts as a regression")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: benchcompare [-threshold percent] base.txt new.txt\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	base, err := parseFile(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	current, err := parseFile(flag.Arg(1))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	regressions := compare(os.Stdout, base, current, *threshold)
+	if len(regressions) > 0 {
+		fmt.Fprintf(os.Stderr, "\n%d regression(s) over %g%%:\n", len(regressions), *threshold)
+		for _, regression := range regressions {
+			fmt.Fprintln(os.Stderr, "  "+regression)
+		}
+		os.Exit(1)
+	}
+}
+
+// parseFile reads benchmark results from a file
+func parseFile(path string) (results, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	parsed, err := parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return parsed, nil
+}
+
+// parse reads `go test -bench` output. Benchmark names are qualified by the
+// preceding "pkg:" line, and the -GOMAXPROCS suffix is dropped so results
+// from machines with different CPU counts can be compared.
+func parse(r io.Reader) (results, error) {
+	parsed := results{}
+	pkg := ""
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if value, ok := strings.CutPrefix(line, "pkg: "); ok {
+			pkg = strings.TrimSpace(value)
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+
+		name := fields[0]
+		if i := strings.LastIndex(name, "-"); i > 0 {
+			if _, err := strconv.Atoi(name[i+1:]); err == nil {
+				name = name[:i]
+			}
+		}
+		if pkg != "" {
+			name = pkg + "." + name
+		}
+
+		// After the iteration count, fields are "value unit" pairs
+		for i := 2; i+1 < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s value %q for %s", fields[i+1], fields[i], name)
+			}
+			if parsed[name] == nil {
+				parsed[name] = map[string][]float64{}
+			}
+			parsed[name][fields[i+1]] = append(parsed[name][fields[i+1]], value)
+		}
+	}
+	return parsed, scanner.Err()
+}
+
+// median returns the middle sample, which ignores the odd noisy run
+func median(samples []float64) float64 {
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+// compare prints the median of each metric before and after for the
+// benchmarks in both sets, and returns the regressions over threshold percent
+func compare(w io.Writer, base, current results, threshold float64) []string {
+	names := make([]string, 0, len(current))
+	for name := range current {
+		if _, ok := base[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "benchmark\tmetric\tbase\tnew\tdelta")
+	var regressions []string
+	for _, name := range names {
+		for _, metric := range metrics {
+			before, after := base[name][metric], current[name][metric]
+			if len(before) == 0 || len(after) == 0 {
+				continue
+			}
+			old, cur := median(before), median(after)
+			delta := 0.0
+			if old != 0 {
+				delta = (cur - old) / old * 100
+			} else if cur != 0 {
+				delta = 100
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%.4g\t%.4g\t%+.1f%%\n", name, metric, old, cur, delta)
+			if delta > threshold {
+				regressions = append(regressions, fmt.Sprintf("%s %s: %.4g -> %.4g (%+.1f%%)", name, metric, old, cur, delta))
+			}
+		}
+	}
+	_ = tw.Flush()
+	return regressions
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+const baseOutput = `goos: linux
+pkg: github.com/evantahler/go-actionhero/internal/servers
+BenchmarkMatchRoute/cache=0-8   	  200000	      6000 ns/op	     384 B/op	       4 allocs/op
+BenchmarkMatchRoute/cache=0-8   	  200000	      6400 ns/op	     384 B/op	       4 allocs/op
+BenchmarkMatchRoute/cache=0-8   	  200000	      9000 ns/op	     384 B/op	       4 allocs/op
+BenchmarkSendError-8            	   20000	      2000 ns/op	    1360 B/op	      12 allocs/op
+PASS
+`
+
+func TestParse(t *testing.T) {
+	parsed, err := parse(strings.NewReader(baseOutput))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	name := "github.com/evantahler/go-actionhero/internal/servers.BenchmarkMatchRoute/cache=0"
+	if got := len(parsed[name]["ns/op"]); got != 3 {
+		t.Fatalf("Expected 3 ns/op samples for %s, got %d (%v)", name, got, parsed)
+	}
+	if got := median(parsed[name]["ns/op"]); got != 6400 {
+		t.Errorf("Expected median 6400, got %g", got)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	base, _ := parse(strings.NewReader(baseOutput))
+	current, _ := parse(strings.NewReader(strings.NewReplacer(
+		"-8 ", "-4 ", // Different CPU counts are still compared
+		"6400 ns/op", "6600 ns/op", // +3%, under the threshold
+		"12 allocs/op", "14 allocs/op", // +16.7%, a regression
+	).Replace(baseOutput)))
+
+	regressions := compare(io.Discard, base, current, 10)
+	if len(regressions) != 1 || !strings.Contains(regressions[0], "BenchmarkSendError allocs/op") {
+		t.Errorf("Expected only the SendError allocs regression, got %v", regressions)
+	}
+}
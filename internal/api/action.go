@@ -6,6 +6,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/config"
 )
 
 // HTTPMethod represents HTTP methods
@@ -25,6 +29,43 @@ const (
 type WebConfig struct {
 	Route  string     // Route pattern (e.g., "/user/:id")
 	Method HTTPMethod // HTTP method
+	// AllowedIPs restricts the route to these IPs and CIDR ranges, on top of
+	// server.web.allowedips (e.g., "10.0.0.0/8" for internal-only routes)
+	AllowedIPs []string
+	// Override serves the action instead of another action declaring the
+	// same method and route, which would otherwise fail initialization
+	Override bool
+	// CORS replaces the server's CORS policy for the route (nil = the
+	// server.web.allowedorigins policy)
+	CORS *CORSConfig
+	// CacheControl is the Cache-Control header of the action's successful
+	// responses (e.g., "public, max-age=60"; "" = none)
+	CacheControl string
+}
+
+// CORSConfig is the CORS policy of an action's route
+type CORSConfig struct {
+	// AllowedOrigins are the origins browsers may call the route from (e.g.,
+	// "https://app.example.com"; "*" = any; nil = server.web.allowedorigins)
+	AllowedOrigins []string
+	// AllowCredentials lets browsers send cookies and HTTP auth with requests
+	// from those origins
+	AllowCredentials bool
+}
+
+// AllowOrigin returns the Access-Control-Allow-Origin header of a request
+// from origin, or "" when origin may not call the route. With credentials,
+// "*" allows origin itself, as browsers refuse credentials for "*".
+func (c *CORSConfig) AllowOrigin(origin string) string {
+	for _, allowed := range c.AllowedOrigins {
+		switch {
+		case allowed == "*" && (!c.AllowCredentials || origin == ""):
+			return "*"
+		case allowed == "*", origin != "" && strings.EqualFold(allowed, origin):
+			return origin
+		}
+	}
+	return ""
 }
 
 // TaskConfig defines background task configuration for an action
@@ -33,11 +74,56 @@ type TaskConfig struct {
 	Frequency int64  // Frequency in milliseconds (0 = not recurrent)
 }
 
+// ConcurrencyConfig limits how many executions of an action run at once.
+// Requests over the limit wait for a free slot, then are shed with a
+// CONNECTION_ACTION_SATURATED error (HTTP 503 with Retry-After).
+type ConcurrencyConfig struct {
+	Max          int           // Executions that may run at once (0 = unlimited)
+	QueueTimeout time.Duration // How long a request waits for a slot (0 = shed immediately)
+	RetryAfter   time.Duration // Suggested to shed callers (default: QueueTimeout, or 1s)
+}
+
+// DeprecationConfig marks an action as deprecated. Each call is logged as a
+// warning, web responses carry Deprecation and Sunset headers, and the
+// OpenAPI document flags the operation.
+type DeprecationConfig struct {
+	Replacement string    // Name of the action to use instead (optional)
+	Since       time.Time // When the action was deprecated (optional)
+	Sunset      time.Time // When the action will be removed (optional)
+	Message     string    // Extra guidance for callers (optional)
+}
+
+// Notice describes the deprecation for callers, e.g.
+// "user:get is deprecated; use user:view instead (sunset 2026-01-31)"
+func (d *DeprecationConfig) Notice(actionName string) string {
+	notice := actionName + " is deprecated"
+	if d.Replacement != "" {
+		notice += "; use " + d.Replacement + " instead"
+	}
+	if !d.Sunset.IsZero() {
+		notice += " (sunset " + d.Sunset.UTC().Format(time.DateOnly) + ")"
+	}
+	if d.Message != "" {
+		notice += ". " + d.Message
+	}
+	return notice
+}
+
+// DocsConfig holds optional OpenAPI metadata for an action
+type DocsConfig struct {
+	OperationID     string      // Explicit operationId (default: derived from the action name)
+	Tags            []string    // Tags to group the operation under (default: the action name's prefix)
+	Deprecated      bool        // Mark the operation as deprecated
+	RequestExample  interface{} // Example request params
+	ResponseExample interface{} // Example response data
+}
+
 // Action is the interface that all actions must implement.
 // Actions should embed BaseAction and implement only the Run method.
 //
-// For type safety, define input and output structs for your action,
-// then implement Run to handle the conversion from interface{} to your types.
+// For type safety, define input and output structs for your action, then
+// implement TypedAction and adapt it with NewTypedAction (or implement Run to
+// handle the conversion from interface{} to your types with MarshalParams).
 type Action interface {
 	// Run executes the action with the given parameters and connection.
 	// The params will typically be a map[string]interface{} that should be
@@ -60,6 +146,10 @@ type BaseAction struct {
 	// Inputs represents the input schema for validation and type coercion
 	ActionInputs interface{}
 
+	// Outputs is an example of the action's response type (e.g., MyOutput{}),
+	// used to document the response schema. Optional.
+	ActionOutputs interface{}
+
 	// Middleware is a list of middleware to apply to this action
 	ActionMiddleware []Middleware
 
@@ -68,103 +158,147 @@ type BaseAction struct {
 
 	// Task is the task configuration, or nil if not available as a task
 	ActionTask *TaskConfig
-}
 
-// GetActionName returns the action's name using reflection
-func GetActionName(action Action) string {
-	val := reflect.ValueOf(action)
-	if val.Kind() == reflect.Ptr {
-		val = val.Elem()
-	}
+	// Docs is optional OpenAPI metadata (examples, tags, deprecation)
+	ActionDocs *DocsConfig
 
-	// Try to get ActionName field
-	if nameField := val.FieldByName("ActionName"); nameField.IsValid() {
-		return nameField.String()
-	}
+	// Timeout bounds how long the action may run; 0 uses the default
+	// (process.actiontimeout). The action's context carries the deadline.
+	ActionTimeout time.Duration
 
-	// Fallback to type name if ActionName is not set
-	t := val.Type()
-	return t.Name()
-}
+	// Concurrency caps concurrent executions of the action, or nil for no cap
+	ActionConcurrency *ConcurrencyConfig
 
-// GetActionDescription returns the action's description using reflection
-func GetActionDescription(action Action) string {
-	val := reflect.ValueOf(action)
-	if val.Kind() == reflect.Ptr {
-		val = val.Elem()
-	}
+	// Cache caches the action's responses, or nil to always run the action
+	ActionCache *CacheConfig
 
-	if descField := val.FieldByName("ActionDescription"); descField.IsValid() {
-		desc := descField.String()
-		if desc != "" {
-			return desc
-		}
-	}
+	// Deprecation marks the action as deprecated, or nil if it isn't
+	ActionDeprecation *DeprecationConfig
 
-	// Fallback to default description
-	return fmt.Sprintf("An Action: %s", GetActionName(action))
+	// MaxResponseSize is the largest response the action may send (its JSON
+	// encoding, or a RawResponse's body; 0 = unlimited). Larger responses
+	// are replaced with a logged CONNECTION_ACTION_RESPONSE_TOO_LARGE error.
+	ActionMaxResponseSize config.ByteSize
+
+	// DryRun declares that the action handles dry runs itself (see
+	// IsDryRun), so it runs in a dry run even if it is mutating. Otherwise,
+	// a mutating action responds with a DryRunResponse instead of running.
+	ActionDryRun bool
 }
 
-// GetActionInputs returns the action's input schema using reflection
-func GetActionInputs(action Action) interface{} {
-	val := reflect.ValueOf(action)
-	if val.Kind() == reflect.Ptr {
-		val = val.Elem()
-	}
+// baseAction returns the action's configuration. It is promoted to every
+// action that embeds BaseAction, so DescribeAction can read the configuration
+// without looking up fields by name.
+func (b BaseAction) baseAction() BaseAction {
+	return b
+}
 
-	if inputsField := val.FieldByName("ActionInputs"); inputsField.IsValid() {
-		return inputsField.Interface()
-	}
+// configuredAction is implemented by actions that embed BaseAction
+type configuredAction interface {
+	baseAction() BaseAction
+}
 
-	return nil
+// ActionDescriptor is an action's metadata, read once when the action is
+// registered so routing, documentation, and the CLI don't inspect the action
+// on every use
+type ActionDescriptor struct {
+	Action      Action
+	Name        string
+	Description string
+	Inputs      interface{}
+	Outputs     interface{}
+	Middleware  []Middleware
+	Web         *WebConfig
+	Task        *TaskConfig
+	Docs        *DocsConfig
+	Timeout     time.Duration
+	Concurrency *ConcurrencyConfig
+	Cache       *CacheConfig
+	Deprecation *DeprecationConfig
+	// MaxResponseSize is the largest response the action may send (0 = unlimited)
+	MaxResponseSize config.ByteSize
+	// DryRun is set when the action handles dry runs itself
+	DryRun bool
+
+	// limiter enforces Concurrency, shared by every execution of the action
+	limiter *actionLimiter
 }
 
-// GetActionMiddleware returns the action's middleware using reflection
-func GetActionMiddleware(action Action) []Middleware {
-	val := reflect.ValueOf(action)
-	if val.Kind() == reflect.Ptr {
-		val = val.Elem()
+// DescribeAction reads an action's metadata from its embedded BaseAction.
+// Without one (or without an ActionName), the name is the action's type name.
+func DescribeAction(action Action) *ActionDescriptor {
+	descriptor := &ActionDescriptor{Action: action}
+	if configured, ok := action.(configuredAction); ok {
+		base := configured.baseAction()
+		descriptor.Name = base.ActionName
+		descriptor.Description = base.ActionDescription
+		descriptor.Inputs = base.ActionInputs
+		descriptor.Outputs = base.ActionOutputs
+		descriptor.Middleware = base.ActionMiddleware
+		descriptor.Web = base.ActionWeb
+		descriptor.Task = base.ActionTask
+		descriptor.Docs = base.ActionDocs
+		descriptor.Timeout = base.ActionTimeout
+		descriptor.Concurrency = base.ActionConcurrency
+		descriptor.Cache = base.ActionCache
+		descriptor.Deprecation = base.ActionDeprecation
+		descriptor.MaxResponseSize = base.ActionMaxResponseSize
+		descriptor.DryRun = base.ActionDryRun
+		descriptor.limiter = newActionLimiter(base.ActionConcurrency)
 	}
 
-	if mwField := val.FieldByName("ActionMiddleware"); mwField.IsValid() {
-		if mw, ok := mwField.Interface().([]Middleware); ok {
-			return mw
+	if descriptor.Name == "" {
+		t := reflect.TypeOf(action)
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
 		}
+		descriptor.Name = t.Name()
+	}
+	if descriptor.Description == "" {
+		descriptor.Description = fmt.Sprintf("An Action: %s", descriptor.Name)
 	}
+	return descriptor
+}
 
-	return nil
+// GetActionName returns the action's name. For registered actions, prefer
+// API.GetActionDescriptor, which reads the metadata once.
+func GetActionName(action Action) string {
+	return DescribeAction(action).Name
 }
 
-// GetActionWeb returns the action's web configuration using reflection
-func GetActionWeb(action Action) *WebConfig {
-	val := reflect.ValueOf(action)
-	if val.Kind() == reflect.Ptr {
-		val = val.Elem()
-	}
+// GetActionDescription returns the action's description
+func GetActionDescription(action Action) string {
+	return DescribeAction(action).Description
+}
 
-	if webField := val.FieldByName("ActionWeb"); webField.IsValid() {
-		if web, ok := webField.Interface().(*WebConfig); ok {
-			return web
-		}
-	}
+// GetActionInputs returns the action's input schema
+func GetActionInputs(action Action) interface{} {
+	return DescribeAction(action).Inputs
+}
 
-	return nil
+// GetActionOutputs returns the action's output schema
+func GetActionOutputs(action Action) interface{} {
+	return DescribeAction(action).Outputs
 }
 
-// GetActionTask returns the action's task configuration using reflection
-func GetActionTask(action Action) *TaskConfig {
-	val := reflect.ValueOf(action)
-	if val.Kind() == reflect.Ptr {
-		val = val.Elem()
-	}
+// GetActionMiddleware returns the action's middleware
+func GetActionMiddleware(action Action) []Middleware {
+	return DescribeAction(action).Middleware
+}
 
-	if taskField := val.FieldByName("ActionTask"); taskField.IsValid() {
-		if task, ok := taskField.Interface().(*TaskConfig); ok {
-			return task
-		}
-	}
+// GetActionWeb returns the action's web configuration
+func GetActionWeb(action Action) *WebConfig {
+	return DescribeAction(action).Web
+}
 
-	return nil
+// GetActionTask returns the action's task configuration
+func GetActionTask(action Action) *TaskConfig {
+	return DescribeAction(action).Task
+}
+
+// GetActionDocs returns the action's OpenAPI metadata
+func GetActionDocs(action Action) *DocsConfig {
+	return DescribeAction(action).Docs
 }
 
 // MarshalParams is a helper function to convert params (interface{}) to a strongly-typed struct.
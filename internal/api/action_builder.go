@@ -0,0 +1,176 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+)
+
+// ActionFunc runs an action defined with NewAction
+type ActionFunc func(ctx context.Context, params interface{}, conn *Connection) (interface{}, error)
+
+// ActionBuilder defines an action inline, without declaring a struct:
+//
+//	api.NewAction("user:list").
+//	    Description("Lists users").
+//	    Get("/users").
+//	    Inputs(ListUsersInput{}).
+//	    Handler(func(ctx context.Context, params interface{}, conn *api.Connection) (interface{}, error) {
+//	        return listUsers(ctx)
+//	    })
+type ActionBuilder struct {
+	base BaseAction
+}
+
+// NewAction starts defining an action with the given name
+func NewAction(name string) *ActionBuilder {
+	return &ActionBuilder{base: BaseAction{ActionName: name}}
+}
+
+// Description sets the action's description
+func (b *ActionBuilder) Description(description string) *ActionBuilder {
+	b.base.ActionDescription = description
+	return b
+}
+
+// Inputs sets the action's input schema (e.g., MyInput{})
+func (b *ActionBuilder) Inputs(inputs interface{}) *ActionBuilder {
+	b.base.ActionInputs = inputs
+	return b
+}
+
+// Outputs sets an example of the action's response type (e.g., MyOutput{})
+func (b *ActionBuilder) Outputs(outputs interface{}) *ActionBuilder {
+	b.base.ActionOutputs = outputs
+	return b
+}
+
+// Middleware appends middleware to the action
+func (b *ActionBuilder) Middleware(middleware ...Middleware) *ActionBuilder {
+	b.base.ActionMiddleware = append(b.base.ActionMiddleware, middleware...)
+	return b
+}
+
+// Route serves the action over HTTP at route with method
+func (b *ActionBuilder) Route(method HTTPMethod, route string) *ActionBuilder {
+	b.base.ActionWeb = &WebConfig{Route: route, Method: method}
+	return b
+}
+
+// Override serves the action instead of any other action with the same
+// method and route (call it after Route)
+func (b *ActionBuilder) Override() *ActionBuilder {
+	if b.base.ActionWeb != nil {
+		b.base.ActionWeb.Override = true
+	}
+	return b
+}
+
+// CORS sets the CORS policy of the action's route (call it after Route)
+func (b *ActionBuilder) CORS(cors *CORSConfig) *ActionBuilder {
+	if b.base.ActionWeb != nil {
+		b.base.ActionWeb.CORS = cors
+	}
+	return b
+}
+
+// CacheControl sets the Cache-Control header of the action's successful
+// responses (call it after Route)
+func (b *ActionBuilder) CacheControl(directives string) *ActionBuilder {
+	if b.base.ActionWeb != nil {
+		b.base.ActionWeb.CacheControl = directives
+	}
+	return b
+}
+
+// Get serves the action over HTTP as GET route
+func (b *ActionBuilder) Get(route string) *ActionBuilder {
+	return b.Route(HTTPMethodGET, route)
+}
+
+// Post serves the action over HTTP as POST route
+func (b *ActionBuilder) Post(route string) *ActionBuilder {
+	return b.Route(HTTPMethodPOST, route)
+}
+
+// Put serves the action over HTTP as PUT route
+func (b *ActionBuilder) Put(route string) *ActionBuilder {
+	return b.Route(HTTPMethodPUT, route)
+}
+
+// Patch serves the action over HTTP as PATCH route
+func (b *ActionBuilder) Patch(route string) *ActionBuilder {
+	return b.Route(HTTPMethodPATCH, route)
+}
+
+// Delete serves the action over HTTP as DELETE route
+func (b *ActionBuilder) Delete(route string) *ActionBuilder {
+	return b.Route(HTTPMethodDELETE, route)
+}
+
+// Task makes the action available as a background task
+func (b *ActionBuilder) Task(task *TaskConfig) *ActionBuilder {
+	b.base.ActionTask = task
+	return b
+}
+
+// Docs sets the action's OpenAPI metadata
+func (b *ActionBuilder) Docs(docs *DocsConfig) *ActionBuilder {
+	b.base.ActionDocs = docs
+	return b
+}
+
+// Timeout bounds how long the action may run
+func (b *ActionBuilder) Timeout(timeout time.Duration) *ActionBuilder {
+	b.base.ActionTimeout = timeout
+	return b
+}
+
+// Concurrency caps concurrent executions of the action
+func (b *ActionBuilder) Concurrency(concurrency *ConcurrencyConfig) *ActionBuilder {
+	b.base.ActionConcurrency = concurrency
+	return b
+}
+
+// Cache caches the action's responses
+func (b *ActionBuilder) Cache(cache *CacheConfig) *ActionBuilder {
+	b.base.ActionCache = cache
+	return b
+}
+
+// MaxResponseSize limits the size of the action's responses
+func (b *ActionBuilder) MaxResponseSize(size config.ByteSize) *ActionBuilder {
+	b.base.ActionMaxResponseSize = size
+	return b
+}
+
+// DryRun declares that the action handles dry runs itself (see IsDryRun), so
+// it runs in a dry run even if it is mutating
+func (b *ActionBuilder) DryRun() *ActionBuilder {
+	b.base.ActionDryRun = true
+	return b
+}
+
+// Deprecated marks the action as deprecated
+func (b *ActionBuilder) Deprecated(deprecation *DeprecationConfig) *ActionBuilder {
+	b.base.ActionDeprecation = deprecation
+	return b
+}
+
+// Handler finishes the definition: the returned action runs fn and can be
+// registered like any other action
+func (b *ActionBuilder) Handler(fn ActionFunc) Action {
+	return &funcAction{BaseAction: b.base, fn: fn}
+}
+
+// funcAction is an action built with NewAction
+type funcAction struct {
+	BaseAction
+	fn ActionFunc
+}
+
+// Run runs the action's handler
+func (a *funcAction) Run(ctx context.Context, params interface{}, conn *Connection) (interface{}, error) {
+	return a.fn(ctx, params, conn)
+}
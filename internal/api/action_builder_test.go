@@ -0,0 +1,114 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+type builderInput struct {
+	Name string `json:"name"`
+}
+
+func TestNewAction(t *testing.T) {
+	action := NewAction("user:list").
+		Description("Lists users").
+		Get("/users").
+		Inputs(builderInput{}).
+		Timeout(time.Second).
+		Handler(func(ctx context.Context, params interface{}, conn *Connection) (interface{}, error) {
+			var input builderInput
+			if err := MarshalParams(params, &input); err != nil {
+				return nil, err
+			}
+			return "hello " + input.Name, nil
+		})
+
+	descriptor := DescribeAction(action)
+	if descriptor.Name != "user:list" || descriptor.Description != "Lists users" {
+		t.Errorf("Expected name and description to be set, got %+v", descriptor)
+	}
+	if descriptor.Web == nil || descriptor.Web.Method != HTTPMethodGET || descriptor.Web.Route != "/users" {
+		t.Errorf("Expected GET /users, got %+v", descriptor.Web)
+	}
+	if _, ok := descriptor.Inputs.(builderInput); !ok {
+		t.Errorf("Expected inputs to be set, got %T", descriptor.Inputs)
+	}
+	if descriptor.Timeout != time.Second {
+		t.Errorf("Expected timeout 1s, got %v", descriptor.Timeout)
+	}
+
+	apiInstance := New(&config.Config{}, util.NewLogger(config.DefaultLoggerConfig()))
+	if err := apiInstance.RegisterAction(action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	conn := NewConnection("test", "test", "builder", nil)
+	result := conn.Act(context.Background(), apiInstance, "user:list", map[string]interface{}{"name": "Mario"}, "", "")
+	if result.Error != nil || result.Response != "hello Mario" {
+		t.Errorf("Expected 'hello Mario', got %v (error: %v)", result.Response, result.Error)
+	}
+}
+
+func TestActionBuilder_Middleware(t *testing.T) {
+	errUnauthorized := errors.New("unauthorized")
+	ran := false
+	action := NewAction("user:delete").
+		Middleware(&namespaceMiddleware{name: "audit"}, &namespaceMiddleware{name: "auth", err: errUnauthorized}).
+		Handler(func(context.Context, interface{}, *Connection) (interface{}, error) {
+			ran = true
+			return nil, nil
+		})
+	if middleware := DescribeAction(action).Middleware; len(middleware) != 2 {
+		t.Fatalf("Expected both middleware to be set, got %v", middleware)
+	}
+
+	apiInstance := New(&config.Config{}, util.NewLogger(config.LoggerConfig{Level: "error"}))
+	if err := apiInstance.RegisterAction(action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	conn := NewConnection("test", "test", "builder", nil)
+	result := conn.Act(context.Background(), apiInstance, "user:delete", nil, "", "")
+	if !errors.Is(result.Error, errUnauthorized) {
+		t.Errorf("Expected the builder's middleware to refuse the action, got %v", result.Error)
+	}
+	if ran {
+		t.Error("Expected the refused action not to run")
+	}
+}
+
+func TestActionBuilder_Routes(t *testing.T) {
+	noop := func(context.Context, interface{}, *Connection) (interface{}, error) { return nil, nil }
+	tests := []struct {
+		builder *ActionBuilder
+		method  HTTPMethod
+	}{
+		{NewAction("a").Get("/a"), HTTPMethodGET},
+		{NewAction("a").Post("/a"), HTTPMethodPOST},
+		{NewAction("a").Put("/a"), HTTPMethodPUT},
+		{NewAction("a").Patch("/a"), HTTPMethodPATCH},
+		{NewAction("a").Delete("/a"), HTTPMethodDELETE},
+		{NewAction("a").Route(HTTPMethodOPTIONS, "/a"), HTTPMethodOPTIONS},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.method), func(t *testing.T) {
+			web := DescribeAction(tt.builder.Handler(noop)).Web
+			if web == nil || web.Method != tt.method || web.Route != "/a" {
+				t.Errorf("Expected %s /a, got %+v", tt.method, web)
+			}
+		})
+	}
+}
+
+func TestActionBuilder_WebOptions(t *testing.T) {
+	noop := func(context.Context, interface{}, *Connection) (interface{}, error) { return nil, nil }
+	cors := &CORSConfig{AllowedOrigins: []string{"https://app.example.com"}}
+	web := DescribeAction(NewAction("a").Get("/a").Override().CORS(cors).CacheControl("no-store").Handler(noop)).Web
+	if !web.Override || web.CORS != cors || web.CacheControl != "no-store" {
+		t.Errorf("Expected the route's options to be set, got %+v", web)
+	}
+}
@@ -3,23 +3,70 @@ package api
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"sort"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/evantahler/go-actionhero/internal/breaker"
 	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/i18n"
 	"github.com/evantahler/go-actionhero/internal/util"
+	"github.com/evantahler/go-actionhero/internal/views"
 )
 
 // API is the main singleton that manages the entire ActionHero application
 type API struct {
-	// Configuration
+	// Configuration the API was created with. Settings reloaded since (see
+	// ReloadConfig) are only in CurrentConfig, so this is never changed
+	// while actions run.
 	Config *config.Config
 
 	// Logger
 	Logger *util.Logger
 
+	// Per-action latency and error metrics
+	Metrics *Metrics
+
+	// Latency and error metrics of outbound requests made with HTTPClient,
+	// by method and host (e.g., "GET api.example.com")
+	HTTPMetrics *Metrics
+
+	// Cache stores cached action responses (in memory by default)
+	Cache Cache
+
+	// Messages translates errors sent to clients (see ErrorJSON)
+	Messages *i18n.Catalog
+
+	// Mail sends templated email (returns ErrMailDisabled until mail is enabled)
+	Mail Mailer
+
+	// Tasks queues actions to run in the background (returns
+	// ErrTasksDisabled until tasks are enabled)
+	Tasks *Tasks
+
+	// Views renders server-side HTML pages (see Connection.Render)
+	Views *views.Views
+
+	// GeoIP finds where client IPs are (finds nothing until geoip.enabled is
+	// set)
+	GeoIP GeoLocator
+
+	// TenantResolver finds the tenant of each request when tenancy is enabled
+	// (nil = read it from the source configured by tenancy.source)
+	TenantResolver TenantResolver
+
+	// Files stores uploaded files (returns ErrStorageDisabled until storage
+	// is enabled)
+	Files Files
+
 	// Actions registry
-	actions   map[string]Action
-	actionsMu sync.RWMutex
+	actions         map[string]*ActionDescriptor
+	namespaces      map[string]Namespace
+	actionsRevision uint64 // Incremented whenever the registered actions change
+	validators      []ActionsValidator
+	actionsMu       sync.RWMutex
 
 	// Servers
 	servers   []Server
@@ -29,11 +76,47 @@ type API struct {
 	initializers   []Initializer
 	initializersMu sync.RWMutex
 
+	// Plugins, in the order they were mounted
+	plugins   []Plugin
+	pluginsMu sync.RWMutex
+
+	// Resources shared with actions, by type (see Provide and Resource)
+	resources *resources
+
+	// Circuit breakers of downstream dependencies, created on first use
+	// (see Breaker)
+	breakers     *breaker.Registry
+	breakersOnce sync.Once
+
+	// Outbound HTTP client, created on first use (see HTTPClient)
+	httpClient     *http.Client
+	httpClientOnce sync.Once
+
+	// Error reporters
+	reporters   []ErrorReporter
+	reportersMu sync.RWMutex
+
+	// Config with reloaded settings applied (see CurrentConfig), and the
+	// lock serializing reloads
+	reloaded atomic.Pointer[reloadedConfig]
+	reloadMu sync.Mutex
+
+	// Event handlers, by event name
+	events   map[string][]EventHandler
+	eventsMu sync.RWMutex
+
 	// Lifecycle state
-	running bool
-	mu      sync.RWMutex
+	running   bool
+	startedAt time.Time
+	sentry    *SentryReporter // The built-in Sentry reporter, which survives restarts
+	mu        sync.RWMutex
+
+	// Boot timings and the report of the last start
+	boot       bootTimings
+	bootReport *BootReport
+	bootMu     sync.Mutex
 
-	// Context for graceful shutdown
+	// Context for graceful shutdown, replaced when the API starts after a stop
 	ctx    context.Context
 	cancel context.CancelFunc
 }
@@ -60,16 +143,29 @@ type Initializer interface {
 func New(cfg *config.Config, logger *util.Logger) *API {
 	ctx, cancel := context.WithCancel(context.Background())
 
-	return &API{
+	a := &API{
 		Config:       cfg,
 		Logger:       logger,
-		actions:      make(map[string]Action),
+		Metrics:      NewMetrics(),
+		HTTPMetrics:  NewMetrics(),
+		Cache:        NewMemoryCache(),
+		Messages:     i18n.NewCatalog(),
+		Mail:         disabledMailer{},
+		Files:        disabledFiles{},
+		GeoIP:        disabledGeoLocator{},
+		Views:        views.New(),
+		actions:      make(map[string]*ActionDescriptor),
+		namespaces:   make(map[string]Namespace),
 		servers:      make([]Server, 0),
 		initializers: make([]Initializer, 0),
+		events:       make(map[string][]EventHandler),
+		resources:    newResources(),
 		running:      false,
 		ctx:          ctx,
 		cancel:       cancel,
 	}
+	a.Tasks = &Tasks{api: a}
+	return a
 }
 
 // RegisterAction registers an action in the API
@@ -77,35 +173,165 @@ func (a *API) RegisterAction(action Action) error {
 	a.actionsMu.Lock()
 	defer a.actionsMu.Unlock()
 
-	name := GetActionName(action)
+	descriptor := a.describeAction(action)
+	name := descriptor.Name
 	if _, exists := a.actions[name]; exists {
 		return fmt.Errorf("action '%s' is already registered", name)
 	}
+	if err := a.validateActions(descriptor); err != nil {
+		return fmt.Errorf("action '%s' can't be registered: %w", name, err)
+	}
 
-	a.actions[name] = action
+	a.actions[name] = descriptor
+	a.actionsRevision++
 	a.Logger.Debugf("Registered action: %s", name)
 	return nil
 }
 
+// UnregisterAction removes a registered action, so it can no longer be run.
+// Servers pick up the change (e.g., the web server drops its route) without a
+// restart, and the action's cached responses are cleared.
+func (a *API) UnregisterAction(name string) error {
+	a.actionsMu.Lock()
+	if _, exists := a.actions[name]; !exists {
+		a.actionsMu.Unlock()
+		return fmt.Errorf("action '%s' is not registered", name)
+	}
+	delete(a.actions, name)
+	a.actionsRevision++
+	a.actionsMu.Unlock()
+
+	a.Logger.Debugf("Unregistered action: %s", name)
+	a.clearActionCache(name)
+	return nil
+}
+
+// ReplaceAction swaps a registered action for a new implementation with the
+// same name. Requests already running finish with the old action; later
+// requests, routes, and documentation use the new one. The action's cached
+// responses are cleared.
+func (a *API) ReplaceAction(action Action) error {
+	a.actionsMu.Lock()
+	descriptor := a.describeAction(action)
+	name := descriptor.Name
+	if _, exists := a.actions[name]; !exists {
+		a.actionsMu.Unlock()
+		return fmt.Errorf("action '%s' is not registered", name)
+	}
+	if err := a.validateActions(descriptor); err != nil {
+		a.actionsMu.Unlock()
+		return fmt.Errorf("action '%s' can't be replaced: %w", name, err)
+	}
+	a.actions[name] = descriptor
+	a.actionsRevision++
+	a.actionsMu.Unlock()
+
+	a.Logger.Debugf("Replaced action: %s", name)
+	a.clearActionCache(name)
+	return nil
+}
+
+// ActionsValidator checks the actions that registering or replacing an action
+// would leave registered, refusing the change by returning an error. It is
+// called with the actions locked, so it must not register or look up actions.
+type ActionsValidator func(descriptors []*ActionDescriptor) error
+
+// RegisterActionsValidator adds a check run before every action is registered
+// or replaced (e.g., the web server refuses actions whose routes conflict)
+func (a *API) RegisterActionsValidator(validator ActionsValidator) {
+	a.actionsMu.Lock()
+	defer a.actionsMu.Unlock()
+	a.validators = append(a.validators, validator)
+}
+
+// validateActions runs the validators on the registered actions, with
+// descriptor added or replacing the action of the same name. Callers hold
+// actionsMu.
+func (a *API) validateActions(descriptor *ActionDescriptor) error {
+	if len(a.validators) == 0 {
+		return nil
+	}
+	descriptors := make([]*ActionDescriptor, 0, len(a.actions)+1)
+	for name, existing := range a.actions {
+		if name != descriptor.Name {
+			descriptors = append(descriptors, existing)
+		}
+	}
+	descriptors = append(descriptors, descriptor)
+	sort.Slice(descriptors, func(i, j int) bool {
+		return descriptors[i].Name < descriptors[j].Name
+	})
+	for _, validator := range a.validators {
+		if err := validator(descriptors); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clearActionCache drops an action's cached responses, which may no longer
+// match what the action returns
+func (a *API) clearActionCache(name string) {
+	if a.Cache == nil {
+		return
+	}
+	if err := a.InvalidateActionCache(a.Context(), name); err != nil {
+		a.Logger.Warnf("Failed to clear cached responses of %s: %v", name, err)
+	}
+}
+
+// ActionsRevision returns a counter that changes whenever the registered
+// actions change, so derived data (e.g., the OpenAPI document) can be cached
+func (a *API) ActionsRevision() uint64 {
+	a.actionsMu.RLock()
+	defer a.actionsMu.RUnlock()
+	return a.actionsRevision
+}
+
 // GetAction retrieves an action by name
 func (a *API) GetAction(name string) (Action, bool) {
 	a.actionsMu.RLock()
 	defer a.actionsMu.RUnlock()
 
-	action, exists := a.actions[name]
-	return action, exists
+	descriptor, exists := a.actions[name]
+	if !exists {
+		return nil, false
+	}
+	return descriptor.Action, true
 }
 
-// GetActions returns all registered actions
+// GetActions returns all registered actions, sorted by name
 func (a *API) GetActions() []Action {
+	descriptors := a.GetActionDescriptors()
+	actions := make([]Action, len(descriptors))
+	for i, descriptor := range descriptors {
+		actions[i] = descriptor.Action
+	}
+	return actions
+}
+
+// GetActionDescriptor returns the metadata of a registered action by name
+func (a *API) GetActionDescriptor(name string) (*ActionDescriptor, bool) {
+	a.actionsMu.RLock()
+	defer a.actionsMu.RUnlock()
+
+	descriptor, exists := a.actions[name]
+	return descriptor, exists
+}
+
+// GetActionDescriptors returns the metadata of all registered actions, sorted by name
+func (a *API) GetActionDescriptors() []*ActionDescriptor {
 	a.actionsMu.RLock()
 	defer a.actionsMu.RUnlock()
 
-	actions := make([]Action, 0, len(a.actions))
-	for _, action := range a.actions {
-		actions = append(actions, action)
+	descriptors := make([]*ActionDescriptor, 0, len(a.actions))
+	for _, descriptor := range a.actions {
+		descriptors = append(descriptors, descriptor)
 	}
-	return actions
+	sort.Slice(descriptors, func(i, j int) bool {
+		return descriptors[i].Name < descriptors[j].Name
+	})
+	return descriptors
 }
 
 // RegisterServer registers a server in the API
@@ -136,27 +362,34 @@ func (a *API) RegisterInitializer(initializer Initializer) {
 	a.Logger.Debugf("Registered initializer: %s", initializer.Name())
 }
 
-// GetInitializers returns all registered initializers sorted by priority
+// GetInitializers returns all registered initializers in run order: each
+// runs after the initializers it depends on, and otherwise lower priorities
+// run first. If the dependencies can't be satisfied (Initialize reports why),
+// they are returned in priority order.
 func (a *API) GetInitializers() []Initializer {
+	initializers, err := a.orderedInitializers()
+	if err != nil {
+		return sortByPriority(a.registeredInitializers())
+	}
+	return initializers
+}
+
+// registeredInitializers returns a copy of the initializers in registration order
+func (a *API) registeredInitializers() []Initializer {
 	a.initializersMu.RLock()
 	defer a.initializersMu.RUnlock()
 
-	// Create a copy
 	initializers := make([]Initializer, len(a.initializers))
 	copy(initializers, a.initializers)
-
-	// Sort by priority (lower priority runs first)
-	for i := 0; i < len(initializers); i++ {
-		for j := i + 1; j < len(initializers); j++ {
-			if initializers[i].Priority() > initializers[j].Priority() {
-				initializers[i], initializers[j] = initializers[j], initializers[i]
-			}
-		}
-	}
-
 	return initializers
 }
 
+// orderedInitializers sorts the initializers by their dependencies, erroring
+// on unknown dependencies and cycles
+func (a *API) orderedInitializers() ([]Initializer, error) {
+	return orderInitializers(a.registeredInitializers())
+}
+
 // Initialize initializes all components in the proper order
 func (a *API) Initialize() error {
 	a.mu.Lock()
@@ -167,26 +400,57 @@ func (a *API) Initialize() error {
 	a.mu.Unlock()
 
 	a.Logger.Info("Initializing ActionHero...")
+	a.resetBootTimings()
 
-	// Initialize all initializers in priority order
-	initializers := a.GetInitializers()
-	for _, initializer := range initializers {
-		a.Logger.Infof("Initializing: %s", initializer.Name())
-		if err := initializer.Initialize(a); err != nil {
-			return fmt.Errorf("failed to initialize %s: %w", initializer.Name(), err)
+	// Register the built-in Sentry reporter if configured (once, so restarts
+	// don't report errors twice)
+	a.mu.Lock()
+	registerSentry := a.Config != nil && a.Config.Sentry.DSN != "" && a.sentry == nil
+	a.mu.Unlock()
+	if registerSentry {
+		reporter, err := NewSentryReporter(a.Config.Sentry, a.Config.Process.Name, a.Logger)
+		if err != nil {
+			return fmt.Errorf("failed to initialize sentry: %w", err)
 		}
+		a.RegisterErrorReporter(reporter.Report)
+		a.mu.Lock()
+		a.sentry = reporter
+		a.mu.Unlock()
+		a.Logger.Info("Sentry error reporting enabled")
+	}
+
+	// Load the message catalogs, so initializers can add to or override them
+	if err := a.loadMessages(); err != nil {
+		return err
+	}
+
+	// Initialize all initializers in dependency (then priority) order
+	initializers, err := a.orderedInitializers()
+	if err != nil {
+		return fmt.Errorf("failed to order initializers: %w", err)
+	}
+	if err := a.runStartupSteps("initialize", initializerSteps(initializers, func(initializer Initializer) error {
+		a.Logger.Infof("Initializing: %s", initializer.Name())
+		return a.timeBootStep("initialize", "initializer:"+initializer.Name(), func() error { return initializer.Initialize(a) })
+	})); err != nil {
+		return err
+	}
+
+	// Load the views once initializers have added their template funcs
+	if err := a.loadViews(); err != nil {
+		return err
 	}
 
 	// Initialize all servers
-	servers := a.GetServers()
-	for _, server := range servers {
+	if err := a.runStartupSteps("initialize", serverSteps(a.GetServers(), func(server Server) error {
 		a.Logger.Infof("Initializing server: %s", server.Name())
-		if err := server.Initialize(); err != nil {
-			return fmt.Errorf("failed to initialize server %s: %w", server.Name(), err)
-		}
+		return a.timeBootStep("initialize", "server:"+server.Name(), server.Initialize)
+	})); err != nil {
+		return err
 	}
 
 	a.Logger.Info("ActionHero initialized successfully")
+	a.Emit(context.Background(), Event{Name: EventInitialize})
 	return nil
 }
 
@@ -198,29 +462,39 @@ func (a *API) Start() error {
 		return fmt.Errorf("API is already running")
 	}
 	a.running = true
+	a.startedAt = time.Now()
+	startedAt := a.startedAt
+	if a.ctx.Err() != nil {
+		// Stopped before: start with a fresh context
+		a.ctx, a.cancel = context.WithCancel(context.Background())
+	}
 	a.mu.Unlock()
 
 	a.Logger.Info("Starting ActionHero...")
 
-	// Start all initializers in priority order
-	initializers := a.GetInitializers()
-	for _, initializer := range initializers {
+	// Start all initializers in dependency (then priority) order
+	if err := a.runStartupSteps("start", initializerSteps(a.GetInitializers(), func(initializer Initializer) error {
 		a.Logger.Infof("Starting: %s", initializer.Name())
-		if err := initializer.Start(a); err != nil {
-			return fmt.Errorf("failed to start %s: %w", initializer.Name(), err)
-		}
+		return a.timeBootStep("start", "initializer:"+initializer.Name(), func() error { return initializer.Start(a) })
+	})); err != nil {
+		return err
 	}
 
 	// Start all servers
-	servers := a.GetServers()
-	for _, server := range servers {
+	if err := a.runStartupSteps("start", serverSteps(a.GetServers(), func(server Server) error {
 		a.Logger.Infof("Starting server: %s", server.Name())
-		if err := server.Start(); err != nil {
-			return fmt.Errorf("failed to start server %s: %w", server.Name(), err)
-		}
+		return a.timeBootStep("start", "server:"+server.Name(), server.Start)
+	})); err != nil {
+		return err
 	}
 
-	a.Logger.Info("ActionHero started successfully")
+	report := a.buildBootReport(startedAt)
+	a.bootMu.Lock()
+	a.bootReport = report
+	a.bootMu.Unlock()
+
+	a.Logger.Infof("ActionHero started successfully in %s", report.Duration.Round(time.Millisecond))
+	a.Emit(context.Background(), Event{Name: EventStart, Boot: report})
 	return nil
 }
 
@@ -232,12 +506,13 @@ func (a *API) Stop() error {
 		return fmt.Errorf("API is not running")
 	}
 	a.running = false
+	cancel := a.cancel
 	a.mu.Unlock()
 
 	a.Logger.Info("Stopping ActionHero...")
 
 	// Cancel context to signal shutdown
-	a.cancel()
+	cancel()
 
 	// Stop all servers (in reverse order)
 	servers := a.GetServers()
@@ -259,7 +534,16 @@ func (a *API) Stop() error {
 		}
 	}
 
+	// Send the errors still queued for Sentry
+	a.mu.RLock()
+	sentry := a.sentry
+	a.mu.RUnlock()
+	if sentry != nil && !sentry.Flush(sentryTimeout) {
+		a.Logger.Warn("Timed out sending queued errors to Sentry")
+	}
+
 	a.Logger.Info("ActionHero stopped successfully")
+	a.Emit(context.Background(), Event{Name: EventStop})
 	return nil
 }
 
@@ -270,7 +554,88 @@ func (a *API) IsRunning() bool {
 	return a.running
 }
 
-// Context returns the API's context (for graceful shutdown)
+// reloadedConfig is a config with reloaded settings applied, and the Config
+// it was derived from
+type reloadedConfig struct {
+	base *config.Config
+	cfg  *config.Config
+}
+
+// CurrentConfig returns the configuration with the settings applied by
+// ReloadConfig. Each reload publishes a new copy instead of changing the
+// current one, so a caller reads one consistent snapshot, and may keep it
+// (e.g., for the rest of a request).
+func (a *API) CurrentConfig() *config.Config {
+	if reloaded := a.reloaded.Load(); reloaded != nil && reloaded.base == a.Config {
+		return reloaded.cfg
+	}
+	return a.Config
+}
+
+// ReloadConfig applies the reloadable settings from next to the running API
+// and emits config:reloaded. Settings that need a restart are left unchanged
+// and logged. It returns every change found, so callers can report them.
+func (a *API) ReloadConfig(next *config.Config) []config.Change {
+	a.reloadMu.Lock()
+	defer a.reloadMu.Unlock()
+
+	current := a.CurrentConfig()
+	changes := config.Diff(current, next)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	applied := 0
+	for _, change := range changes {
+		if change.RequiresRestart {
+			a.Logger.Warnf("Config %s changed, but requires a restart to take effect", change.Key)
+			continue
+		}
+		a.Logger.Infof("Config %s changed: %v -> %v", change.Key, change.Old, change.New)
+		applied++
+	}
+
+	snapshot := *current
+	config.ApplyReloadable(&snapshot, changes)
+	a.reloaded.Store(&reloadedConfig{base: a.Config, cfg: &snapshot})
+	if err := a.Logger.UpdateLevel(snapshot.Logger.Level); err != nil {
+		a.Logger.Warnf("Invalid log level %q: %v", snapshot.Logger.Level, err)
+	}
+
+	a.Logger.Infof("Config reloaded: %d applied, %d require a restart", applied, len(changes)-applied)
+	a.Emit(a.Context(), Event{Name: EventConfigReloaded, Changes: changes})
+	return changes
+}
+
+// StartedAt returns when the API was last started (zero if it hasn't been started)
+func (a *API) StartedAt() time.Time {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.startedAt
+}
+
+// Restart stops the API if it is running (draining servers and initializers),
+// then initializes and starts everything again with a fresh context
+func (a *API) Restart() error {
+	a.Logger.Info("Restarting ActionHero...")
+	if a.IsRunning() {
+		if err := a.Stop(); err != nil {
+			return fmt.Errorf("failed to stop: %w", err)
+		}
+	}
+	if err := a.Initialize(); err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+	if err := a.Start(); err != nil {
+		return fmt.Errorf("failed to start: %w", err)
+	}
+	return nil
+}
+
+// Context returns the API's context, which is canceled when the API stops
+// (for graceful shutdown). A restarted API has a new context.
 func (a *API) Context() context.Context {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
 	return a.ctx
 }
@@ -3,7 +3,9 @@ package api
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/evantahler/go-actionhero/internal/config"
 	"github.com/evantahler/go-actionhero/internal/util"
@@ -163,6 +165,121 @@ func TestRegisterAction(t *testing.T) {
 	}
 }
 
+func TestUnregisterAction(t *testing.T) {
+	api := New(&config.Config{}, util.NewLogger(config.DefaultLoggerConfig()))
+	if err := api.RegisterAction(newMockAction("test:action", "Test action")); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	revision := api.ActionsRevision()
+
+	if err := api.UnregisterAction("test:action"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, exists := api.GetAction("test:action"); exists {
+		t.Error("Expected action to be unregistered")
+	}
+	if api.ActionsRevision() == revision {
+		t.Error("Expected the actions revision to change")
+	}
+
+	if err := api.UnregisterAction("test:action"); err == nil {
+		t.Error("Expected error when unregistering a missing action")
+	}
+
+	// The name can be registered again
+	if err := api.RegisterAction(newMockAction("test:action", "Again")); err != nil {
+		t.Errorf("Expected to register the name again, got %v", err)
+	}
+}
+
+func TestReplaceAction(t *testing.T) {
+	api := New(&config.Config{}, util.NewLogger(config.DefaultLoggerConfig()))
+	if err := api.ReplaceAction(newMockAction("test:action", "New")); err == nil {
+		t.Error("Expected error when replacing a missing action")
+	}
+
+	if err := api.RegisterAction(newMockAction("test:action", "Old")); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	revision := api.ActionsRevision()
+
+	replacement := newMockAction("test:action", "New")
+	if err := api.ReplaceAction(replacement); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	descriptor, _ := api.GetActionDescriptor("test:action")
+	if descriptor.Action != replacement || descriptor.Description != "New" {
+		t.Errorf("Expected the replacement action, got %+v", descriptor)
+	}
+	if api.ActionsRevision() == revision {
+		t.Error("Expected the actions revision to change")
+	}
+}
+
+func TestActionsValidator(t *testing.T) {
+	api := New(&config.Config{}, util.NewLogger(config.DefaultLoggerConfig()))
+	if err := api.RegisterAction(newMockAction("test:action", "Old")); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	var checked []string
+	api.RegisterActionsValidator(func(descriptors []*ActionDescriptor) error {
+		checked = checked[:0]
+		for _, descriptor := range descriptors {
+			checked = append(checked, descriptor.Name+"="+descriptor.Description)
+			if descriptor.Description == "Invalid" {
+				return errors.New("invalid action")
+			}
+		}
+		return nil
+	})
+	revision := api.ActionsRevision()
+
+	if err := api.ReplaceAction(newMockAction("test:action", "Invalid")); err == nil {
+		t.Error("Expected the validator to refuse the replacement")
+	}
+	if err := api.RegisterAction(newMockAction("test:other", "Invalid")); err == nil {
+		t.Error("Expected the validator to refuse the registration")
+	}
+	descriptor, _ := api.GetActionDescriptor("test:action")
+	if descriptor.Description != "Old" {
+		t.Errorf("Expected the old action to be kept, got %q", descriptor.Description)
+	}
+	if _, exists := api.GetAction("test:other"); exists {
+		t.Error("Expected the refused action not to be registered")
+	}
+	if api.ActionsRevision() != revision {
+		t.Error("Expected the actions revision not to change")
+	}
+
+	if err := api.ReplaceAction(newMockAction("test:action", "New")); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if strings.Join(checked, ",") != "test:action=New" {
+		t.Errorf("Expected the validator to see the replacement only, got %v", checked)
+	}
+}
+
+func TestReplaceAction_ClearsCache(t *testing.T) {
+	api := New(&config.Config{}, util.NewLogger(config.DefaultLoggerConfig()))
+	cached := &countingAction{BaseAction: BaseAction{ActionName: "test:cached", ActionCache: &CacheConfig{TTL: time.Minute}}}
+	if err := api.RegisterAction(cached); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	actCached(context.Background(), api, nil)
+
+	replacement := &countingAction{BaseAction: cached.BaseAction}
+	if err := api.ReplaceAction(replacement); err != nil {
+		t.Fatalf("Failed to replace action: %v", err)
+	}
+	if result := actCached(context.Background(), api, nil); result.Cached {
+		t.Error("Expected the old action's responses to be cleared")
+	}
+	if replacement.runs.Load() != 1 {
+		t.Errorf("Expected the replacement to run, ran %d times", replacement.runs.Load())
+	}
+}
+
 func TestGetActions(t *testing.T) {
 	api := New(&config.Config{}, util.NewLogger(config.DefaultLoggerConfig()))
 
@@ -178,6 +295,102 @@ func TestGetActions(t *testing.T) {
 	}
 }
 
+func TestGetActionDescriptors(t *testing.T) {
+	api := New(&config.Config{}, util.NewLogger(config.DefaultLoggerConfig()))
+
+	web := &WebConfig{Route: "/two", Method: HTTPMethodGET}
+	action2 := newMockAction("action:two", "Action two")
+	action2.ActionWeb = web
+	_ = api.RegisterAction(action2)
+	_ = api.RegisterAction(newMockAction("action:one", "Action one"))
+
+	descriptors := api.GetActionDescriptors()
+	if len(descriptors) != 2 || descriptors[0].Name != "action:one" || descriptors[1].Name != "action:two" {
+		t.Fatalf("Expected descriptors sorted by name, got %+v", descriptors)
+	}
+
+	descriptor, ok := api.GetActionDescriptor("action:two")
+	if !ok {
+		t.Fatal("Expected descriptor for action:two")
+	}
+	if descriptor.Action != action2 || descriptor.Description != "Action two" || descriptor.Web != web {
+		t.Errorf("Expected descriptor to hold the action's metadata, got %+v", descriptor)
+	}
+}
+
+// plainAction implements Action without embedding BaseAction
+type plainAction struct{}
+
+func (plainAction) Run(_ context.Context, _ interface{}, _ *Connection) (interface{}, error) {
+	return nil, nil
+}
+
+func TestDescribeAction_Defaults(t *testing.T) {
+	descriptor := DescribeAction(plainAction{})
+	if descriptor.Name != "plainAction" {
+		t.Errorf("Expected the type name, got %s", descriptor.Name)
+	}
+	if descriptor.Description != "An Action: plainAction" {
+		t.Errorf("Expected the default description, got %s", descriptor.Description)
+	}
+	if descriptor.Web != nil || descriptor.Inputs != nil {
+		t.Error("Expected no web config or inputs")
+	}
+}
+
+func TestDeprecationConfig_Notice(t *testing.T) {
+	tests := []struct {
+		name        string
+		deprecation DeprecationConfig
+		want        string
+	}{
+		{"bare", DeprecationConfig{}, "user:get is deprecated"},
+		{"replacement", DeprecationConfig{Replacement: "user:view"}, "user:get is deprecated; use user:view instead"},
+		{
+			"everything",
+			DeprecationConfig{
+				Replacement: "user:view",
+				Sunset:      time.Date(2027, 1, 31, 12, 0, 0, 0, time.UTC),
+				Message:     "See the migration guide.",
+			},
+			"user:get is deprecated; use user:view instead (sunset 2027-01-31). See the migration guide.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.deprecation.Notice("user:get"); got != tt.want {
+				t.Errorf("Notice() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCORSConfig_AllowOrigin(t *testing.T) {
+	partner := "https://partner.example.com"
+	tests := []struct {
+		name   string
+		cors   CORSConfig
+		origin string
+		want   string
+	}{
+		{"listed origin", CORSConfig{AllowedOrigins: []string{partner}}, partner, partner},
+		{"listed origin, other case", CORSConfig{AllowedOrigins: []string{partner}}, "https://Partner.example.com", "https://Partner.example.com"},
+		{"unlisted origin", CORSConfig{AllowedOrigins: []string{partner}}, "https://evil.example.com", ""},
+		{"no origin", CORSConfig{AllowedOrigins: []string{partner}}, "", ""},
+		{"any origin", CORSConfig{AllowedOrigins: []string{"*"}}, partner, "*"},
+		{"any origin with credentials", CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true}, partner, partner},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cors.AllowOrigin(tt.origin); got != tt.want {
+				t.Errorf("AllowOrigin(%q) = %q, want %q", tt.origin, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestRegisterServer(t *testing.T) {
 	api := New(&config.Config{}, util.NewLogger(config.DefaultLoggerConfig()))
 
@@ -282,6 +495,10 @@ func TestStartStop(t *testing.T) {
 		t.Error("Expected API to be running")
 	}
 
+	if api.StartedAt().IsZero() {
+		t.Error("Expected StartedAt to be set after Start")
+	}
+
 	if !initializer.startCalled {
 		t.Error("Expected initializer Start to be called")
 	}
@@ -367,3 +584,43 @@ func TestContext(t *testing.T) {
 		t.Error("Expected context to be cancelled after Stop")
 	}
 }
+
+func TestRestart(t *testing.T) {
+	api := New(&config.Config{}, util.NewLogger(config.DefaultLoggerConfig()))
+	initializer := &mockInitializer{name: "test-init", priority: 1}
+	api.RegisterInitializer(initializer)
+
+	if err := api.Initialize(); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	if err := api.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	first := api.Context()
+
+	initializer.initializeCalled = false
+	initializer.stopCalled = false
+	if err := api.Restart(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !initializer.stopCalled || !initializer.initializeCalled {
+		t.Error("Expected the initializer to be stopped and initialized again")
+	}
+	if !api.IsRunning() {
+		t.Error("Expected API to be running after restart")
+	}
+	if first.Err() == nil {
+		t.Error("Expected the first context to be cancelled")
+	}
+	if api.Context().Err() != nil {
+		t.Error("Expected a fresh context after restart")
+	}
+
+	// Start after Stop also gets a fresh context
+	_ = api.Stop()
+	_ = api.Start()
+	if api.Context().Err() != nil {
+		t.Error("Expected a fresh context when starting after a stop")
+	}
+	_ = api.Stop()
+}
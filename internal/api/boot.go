@@ -0,0 +1,122 @@
+package api
+
+import (
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+)
+
+// BootReport summarizes a start of the API: what is serving, where, and how
+// long each component took to come up
+type BootReport struct {
+	Process      string
+	Environment  string        // NODE_ENV or GO_ENV ("" when unset)
+	StartedAt    time.Time     // When Start finished
+	Duration     time.Duration // From the start of Initialize to the end of Start
+	Actions      int
+	Initializers []ComponentBootReport // In run order
+	Servers      []ComponentBootReport // In registration order
+}
+
+// ComponentBootReport is an initializer's or server's part of a BootReport
+type ComponentBootReport struct {
+	Name       string
+	Addresses  []string // Where a server listens (see ServerAddresses)
+	Initialize time.Duration
+	Start      time.Duration
+}
+
+// bootTimings records how long each component took to initialize and start
+type bootTimings struct {
+	initializeStarted time.Time
+	initialize        map[string]time.Duration
+	start             map[string]time.Duration
+}
+
+// resetBootTimings starts recording a new boot
+func (a *API) resetBootTimings() {
+	a.bootMu.Lock()
+	defer a.bootMu.Unlock()
+	a.boot = bootTimings{
+		initializeStarted: time.Now(),
+		initialize:        make(map[string]time.Duration),
+		start:             make(map[string]time.Duration),
+	}
+}
+
+// timeBootStep runs fn and records its duration under key for verb
+// ("initialize" or "start")
+func (a *API) timeBootStep(verb, key string, fn func() error) error {
+	began := time.Now()
+	err := fn()
+	elapsed := time.Since(began)
+
+	a.bootMu.Lock()
+	defer a.bootMu.Unlock()
+	if a.boot.initialize == nil {
+		// Started without Initialize
+		a.boot.initialize = make(map[string]time.Duration)
+		a.boot.start = make(map[string]time.Duration)
+	}
+	if verb == "initialize" {
+		a.boot.initialize[key] = elapsed
+	} else {
+		a.boot.start[key] = elapsed
+	}
+	return err
+}
+
+// buildBootReport builds the report for the start that just finished
+func (a *API) buildBootReport(startedAt time.Time) *BootReport {
+	report := &BootReport{
+		Environment: config.Environment(),
+		StartedAt:   time.Now(),
+		Actions:     len(a.GetActionDescriptors()),
+	}
+	if a.Config != nil {
+		report.Process = a.Config.Process.Name
+	}
+
+	a.bootMu.Lock()
+	defer a.bootMu.Unlock()
+
+	began := a.boot.initializeStarted
+	if began.IsZero() || began.After(startedAt) {
+		began = startedAt
+	}
+	report.Duration = report.StartedAt.Sub(began)
+
+	for _, initializer := range a.GetInitializers() {
+		key := "initializer:" + initializer.Name()
+		report.Initializers = append(report.Initializers, ComponentBootReport{
+			Name:       initializer.Name(),
+			Initialize: a.boot.initialize[key],
+			Start:      a.boot.start[key],
+		})
+	}
+	for _, server := range a.GetServers() {
+		key := "server:" + server.Name()
+		component := ComponentBootReport{
+			Name:       server.Name(),
+			Initialize: a.boot.initialize[key],
+			Start:      a.boot.start[key],
+		}
+		if addresses, ok := server.(ServerAddresses); ok {
+			component.Addresses = addresses.Addresses()
+		}
+		report.Servers = append(report.Servers, component)
+	}
+	return report
+}
+
+// BootReport returns the report of the API's last start, and whether it has
+// started
+func (a *API) BootReport() (BootReport, bool) {
+	a.bootMu.Lock()
+	defer a.bootMu.Unlock()
+
+	if a.bootReport == nil {
+		return BootReport{}, false
+	}
+	return *a.bootReport, true
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// addressedServer is a mock server that reports where it listens
+type addressedServer struct {
+	mockServer
+}
+
+func (s *addressedServer) Addresses() []string { return []string{"http://localhost:1234"} }
+
+func TestBootReport(t *testing.T) {
+	t.Setenv("NODE_ENV", "staging")
+
+	cfg := &config.Config{Process: config.ProcessConfig{Name: "boot-test"}}
+	api := New(cfg, util.NewLogger(config.LoggerConfig{Level: "error"}))
+	if _, ok := api.BootReport(); ok {
+		t.Fatal("Expected no boot report before Start")
+	}
+
+	api.RegisterInitializer(&timedInitializer{name: "slow", delay: 10 * time.Millisecond})
+	api.RegisterServer(&addressedServer{mockServer{name: "web"}})
+	api.RegisterServer(&mockServer{name: "plain"})
+	if err := api.RegisterAction(newMockAction("test:action", "Test action")); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	var emitted *BootReport
+	api.On(EventStart, func(_ context.Context, event Event) { emitted = event.Boot })
+
+	if err := api.Initialize(); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	if err := api.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer func() { _ = api.Stop() }()
+
+	report, ok := api.BootReport()
+	if !ok {
+		t.Fatal("Expected a boot report after Start")
+	}
+	if emitted == nil || !reflect.DeepEqual(*emitted, report) {
+		t.Errorf("Expected the start event to carry the boot report, got %+v", emitted)
+	}
+
+	if report.Process != "boot-test" || report.Environment != "staging" || report.Actions != 1 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	if report.Duration < 10*time.Millisecond {
+		t.Errorf("Expected the boot to take at least the initializer's 10ms, got %v", report.Duration)
+	}
+
+	if len(report.Initializers) != 1 || report.Initializers[0].Name != "slow" || report.Initializers[0].Initialize < 10*time.Millisecond {
+		t.Errorf("Expected the initializer's timing, got %+v", report.Initializers)
+	}
+	if len(report.Servers) != 2 {
+		t.Fatalf("Expected 2 servers, got %+v", report.Servers)
+	}
+	if !reflect.DeepEqual(report.Servers[0].Addresses, []string{"http://localhost:1234"}) {
+		t.Errorf("Expected the web server's address, got %v", report.Servers[0].Addresses)
+	}
+	if report.Servers[1].Addresses != nil {
+		t.Errorf("Expected no addresses for a server without them, got %v", report.Servers[1].Addresses)
+	}
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"github.com/evantahler/go-actionhero/internal/breaker"
+	"github.com/evantahler/go-actionhero/internal/config"
+)
+
+// Breaker is the circuit breaker of a downstream dependency (see API.Breaker)
+type Breaker = breaker.Breaker
+
+// BreakerSettings are the thresholds of a Breaker
+type BreakerSettings = breaker.Settings
+
+// BreakerStats is a snapshot of a Breaker
+type BreakerStats = breaker.Stats
+
+// BreakerState is the state of a Breaker: closed, open, or half-open
+type BreakerState = breaker.State
+
+// Breaker states
+const (
+	BreakerClosed   = breaker.StateClosed
+	BreakerOpen     = breaker.StateOpen
+	BreakerHalfOpen = breaker.StateHalfOpen
+)
+
+// ErrBreakerOpen is wrapped by the errors of calls refused by an open Breaker
+var ErrBreakerOpen = breaker.ErrOpen
+
+// Breaker returns the circuit breaker of the dependency name, created with
+// the breaker section's settings on first use. Run calls to the dependency
+// through it, so that once it keeps failing they fail fast with a
+// DEPENDENCY_UNAVAILABLE error (a 503 with Retry-After) instead of waiting
+// on it:
+//
+//	err := api.APIFromContext(ctx).Breaker("payments").Do(func() error {
+//		return payments.Charge(ctx, order)
+//	})
+func (a *API) Breaker(name string) *Breaker {
+	return a.breakerRegistry().Get(name)
+}
+
+// BreakerWithSettings returns the circuit breaker of the dependency name,
+// created with settings on first use (a breaker keeps the settings it was
+// created with)
+func (a *API) BreakerWithSettings(name string, settings BreakerSettings) *Breaker {
+	return a.breakerRegistry().GetWithSettings(name, settings)
+}
+
+// BreakerStats returns a snapshot of every circuit breaker, by name,
+// including the HTTPClient's per-host breakers ("http:<host>")
+func (a *API) BreakerStats() []BreakerStats {
+	return a.breakerRegistry().Stats()
+}
+
+// breakerRegistry returns the API's breakers, creating the registry on
+// first use
+func (a *API) breakerRegistry() *breaker.Registry {
+	a.breakersOnce.Do(func() {
+		cfg := config.DefaultBreakerConfig()
+		if a.Config != nil {
+			cfg = a.Config.Breaker
+		}
+		a.breakers = breaker.NewRegistry(breaker.Settings{
+			Threshold:      cfg.Threshold,
+			Cooldown:       cfg.Cooldown,
+			HalfOpenProbes: cfg.HalfOpenProbes,
+		}, a.Logger)
+	})
+	return a.breakers
+}
+
+// BreakerMiddleware returns middleware that fails an action fast, with a
+// DEPENDENCY_UNAVAILABLE error, while the breaker of the dependency name is
+// open. The action reports its calls' outcomes by running them through the
+// same breaker (e.g., with Do).
+func BreakerMiddleware(name string) Middleware {
+	return breakerMiddleware{name: name}
+}
+
+type breakerMiddleware struct {
+	name string
+}
+
+// RunBefore refuses the action while the breaker is open
+func (m breakerMiddleware) RunBefore(_ interface{}, conn *Connection) (*MiddlewareResponse, error) {
+	conn.mu.RLock()
+	a := conn.api
+	conn.mu.RUnlock()
+	if a == nil {
+		return nil, nil
+	}
+
+	b := a.Breaker(m.name)
+	if b.State() == BreakerOpen {
+		// Allow counts the rejection and builds the error with its Retry-After
+		if err := b.Allow(); err != nil {
+			return nil, err
+		}
+		b.Cancel()
+	}
+	return nil, nil
+}
+
+// RunAfter does nothing: the action reports its calls' outcomes
+func (m breakerMiddleware) RunAfter(_ interface{}, _ *Connection) (*MiddlewareResponse, error) {
+	return nil, nil
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+func TestAPI_Breaker(t *testing.T) {
+	cfg := &config.Config{Breaker: config.BreakerConfig{Threshold: 2, Cooldown: time.Minute, HalfOpenProbes: 1}}
+	apiInstance := New(cfg, util.NewLogger(config.LoggerConfig{Level: "error"}))
+
+	payments := apiInstance.Breaker("payments")
+	if payments != apiInstance.Breaker("payments") {
+		t.Error("Expected the same breaker for a name")
+	}
+	payments.Failure()
+	payments.Failure()
+	if err := payments.Allow(); !errors.Is(err, ErrBreakerOpen) {
+		t.Fatalf("Expected the configured threshold to open the breaker, got %v", err)
+	}
+
+	search := apiInstance.BreakerWithSettings("search", BreakerSettings{Threshold: 10, Cooldown: time.Second})
+	search.Failure()
+	search.Failure()
+	if search.State() != BreakerClosed {
+		t.Errorf("Expected the breaker's own threshold, got %s", search.State())
+	}
+}
+
+func TestBreakerMiddleware(t *testing.T) {
+	cfg := &config.Config{Breaker: config.BreakerConfig{Threshold: 1, Cooldown: time.Minute, HalfOpenProbes: 1}}
+	apiInstance := New(cfg, util.NewLogger(config.LoggerConfig{Level: "error"}))
+	runs := 0
+	if err := apiInstance.RegisterAction(NewAction("test:charge").
+		Middleware(BreakerMiddleware("payments")).
+		Handler(func(context.Context, interface{}, *Connection) (interface{}, error) {
+			runs++
+			return "charged", nil
+		})); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	conn := NewConnection("test", "127.0.0.1", "breaker-test", nil)
+
+	if result := conn.Act(context.Background(), apiInstance, "test:charge", nil, "POST", ""); result.Error != nil || runs != 1 {
+		t.Fatalf("Expected a closed breaker to let the action run, got %v", result.Error)
+	}
+
+	apiInstance.Breaker("payments").Failure()
+	result := conn.Act(context.Background(), apiInstance, "test:charge", nil, "POST", "")
+	var typedErr *util.TypedError
+	if !errors.As(result.Error, &typedErr) || typedErr.Type != util.ErrorTypeDependencyUnavailable {
+		t.Fatalf("Expected a DEPENDENCY_UNAVAILABLE error while open, got %v", result.Error)
+	}
+	if typedErr.RetryAfter <= 0 {
+		t.Errorf("Expected a Retry-After, got %s", typedErr.RetryAfter)
+	}
+	if runs != 1 {
+		t.Errorf("Expected the action not to run while the breaker is open, ran %d times", runs)
+	}
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache stores values that expire. The API's Cache defaults to an in-memory
+// store; replace it with a shared backend (e.g., Redis) so processes share
+// entries.
+type Cache interface {
+	// Get returns the value stored at key, and whether it was found
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set stores value at key for ttl (0 = no expiry)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete removes the value stored at key
+	Delete(ctx context.Context, key string) error
+
+	// DeletePrefix removes every value whose key starts with prefix
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// memoryCacheSweepInterval is how often Set drops expired entries
+const memoryCacheSweepInterval = time.Minute
+
+// MemoryCache is a Cache held in process memory
+type MemoryCache struct {
+	entries   map[string]memoryCacheEntry
+	lastSweep time.Time
+	mu        sync.Mutex
+}
+
+type memoryCacheEntry struct {
+	value   []byte
+	expires time.Time // Zero for no expiry
+}
+
+// NewMemoryCache creates an empty in-memory cache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		entries:   make(map[string]memoryCacheEntry),
+		lastSweep: time.Now(),
+	}
+}
+
+// Get returns the value stored at key, and whether it was found
+func (c *MemoryCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if entry.expired(time.Now()) {
+		delete(c.entries, key)
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+// Set stores value at key for ttl (0 = no expiry)
+func (c *MemoryCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(c.lastSweep) > memoryCacheSweepInterval {
+		for k, entry := range c.entries {
+			if entry.expired(now) {
+				delete(c.entries, k)
+			}
+		}
+		c.lastSweep = now
+	}
+
+	entry := memoryCacheEntry{value: value}
+	if ttl > 0 {
+		entry.expires = now.Add(ttl)
+	}
+	c.entries[key] = entry
+	return nil
+}
+
+// Delete removes the value stored at key
+func (c *MemoryCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+	return nil
+}
+
+// DeletePrefix removes every value whose key starts with prefix
+func (c *MemoryCache) DeletePrefix(_ context.Context, prefix string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of stored entries, including expired ones not yet dropped
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+func (e memoryCacheEntry) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
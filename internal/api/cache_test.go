@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryCache_GetSet(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache()
+
+	if _, ok, err := cache.Get(ctx, "missing"); ok || err != nil {
+		t.Fatalf("Expected a miss, got ok=%v err=%v", ok, err)
+	}
+
+	if err := cache.Set(ctx, "key", []byte("value"), 0); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	value, ok, err := cache.Get(ctx, "key")
+	if !ok || err != nil || string(value) != "value" {
+		t.Fatalf("Expected 'value', got %q ok=%v err=%v", value, ok, err)
+	}
+}
+
+func TestMemoryCache_Expiry(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache()
+
+	if err := cache.Set(ctx, "key", []byte("value"), 10*time.Millisecond); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if _, ok, _ := cache.Get(ctx, "key"); !ok {
+		t.Fatal("Expected a hit before the TTL passed")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if _, ok, _ := cache.Get(ctx, "key"); ok {
+		t.Fatal("Expected a miss after the TTL passed")
+	}
+	if cache.Len() != 0 {
+		t.Errorf("Expected the expired entry to be dropped, have %d entries", cache.Len())
+	}
+}
+
+func TestMemoryCache_Delete(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache()
+	for _, key := range []string{"a:1", "a:2", "b:1"} {
+		if err := cache.Set(ctx, key, []byte(key), 0); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+
+	if err := cache.Delete(ctx, "b:1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, ok, _ := cache.Get(ctx, "b:1"); ok {
+		t.Error("Expected b:1 to be deleted")
+	}
+
+	if err := cache.DeletePrefix(ctx, "a:"); err != nil {
+		t.Fatalf("DeletePrefix failed: %v", err)
+	}
+	if cache.Len() != 0 {
+		t.Errorf("Expected every a: key to be deleted, have %d entries", cache.Len())
+	}
+}
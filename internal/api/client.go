@@ -0,0 +1,73 @@
+package api
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ClientInfo describes the client behind a connection, as far as its
+// transport can tell
+type ClientInfo struct {
+	RemoteIP       string // Client IP, without the port
+	UserAgent      string
+	Protocol       string // e.g., "HTTP/1.1", "HTTP/2.0", "websocket", or "cli"
+	Subprotocol    string // WebSocket subprotocol negotiated with the client
+	TLSVersion     string // e.g., "TLS 1.3" ("" = not encrypted)
+	TLSCipherSuite string
+	TLSServerName  string      // SNI host name requested by the client
+	Fingerprint    string      // Stable hash of the above, the same for every connection from the client
+	Location       GeoLocation // Where RemoteIP is, when GeoIP is enabled (see API.LocateClient)
+}
+
+// ClientInfoFromRequest captures the client info of an HTTP request (or of
+// the request upgraded to a WebSocket)
+func ClientInfoFromRequest(r *http.Request) ClientInfo {
+	remoteIP, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remoteIP = r.RemoteAddr
+	}
+
+	info := ClientInfo{
+		RemoteIP:  remoteIP,
+		UserAgent: r.UserAgent(),
+		Protocol:  r.Proto,
+	}
+	if r.TLS != nil {
+		info.TLSVersion = tls.VersionName(r.TLS.Version)
+		info.TLSCipherSuite = tls.CipherSuiteName(r.TLS.CipherSuite)
+		info.TLSServerName = r.TLS.ServerName
+	}
+	info.Fingerprint = info.fingerprint(r.Header.Get("Accept-Language"), r.Header.Get("Accept-Encoding"))
+	return info
+}
+
+// fingerprint hashes what identifies the client (but not the connection,
+// like its port), plus any extra transport-specific values
+func (i ClientInfo) fingerprint(extra ...string) string {
+	parts := append([]string{i.RemoteIP, i.UserAgent, i.TLSVersion, i.TLSCipherSuite, i.TLSServerName}, extra...)
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// SetClientInfo records the connection's client info, computing its
+// fingerprint if it isn't set
+func (c *Connection) SetClientInfo(info ClientInfo) {
+	if info.Fingerprint == "" {
+		info.Fingerprint = info.fingerprint()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clientInfo = info
+}
+
+// ClientInfo returns the connection's client info
+func (c *Connection) ClientInfo() ClientInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.clientInfo
+}
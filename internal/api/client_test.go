@@ -0,0 +1,65 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+func TestClientInfoFromRequest(t *testing.T) {
+	req := httptest.NewRequest("GET", "https://example.com/api/status", nil)
+	req.RemoteAddr = "10.0.0.1:51000"
+	req.Header.Set("User-Agent", "test-agent/1.0")
+
+	info := ClientInfoFromRequest(req)
+	if info.RemoteIP != "10.0.0.1" || info.UserAgent != "test-agent/1.0" || info.Protocol != "HTTP/1.1" {
+		t.Errorf("Unexpected client info: %+v", info)
+	}
+	if info.TLSVersion != "TLS 1.2" || info.TLSServerName != "example.com" {
+		t.Errorf("Expected TLS details, got %+v", info)
+	}
+	if info.Fingerprint == "" {
+		t.Fatal("Expected a fingerprint")
+	}
+
+	// The same client on another port has the same fingerprint
+	req.RemoteAddr = "10.0.0.1:51001"
+	if other := ClientInfoFromRequest(req); other.Fingerprint != info.Fingerprint {
+		t.Errorf("Expected a stable fingerprint, got %s and %s", info.Fingerprint, other.Fingerprint)
+	}
+
+	req.Header.Set("User-Agent", "other-agent/2.0")
+	if other := ClientInfoFromRequest(req); other.Fingerprint == info.Fingerprint {
+		t.Error("Expected another client to have another fingerprint")
+	}
+}
+
+func TestConnection_ClientInfoLogged(t *testing.T) {
+	var buf bytes.Buffer
+	logger := util.NewLogger(config.LoggerConfig{Level: "info"})
+	logger.SetOutput(&buf)
+	apiInstance := New(&config.Config{}, logger)
+	if err := apiInstance.RegisterAction(newMockAction("test:action", "Test action")); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	conn := NewConnection("cli", "cli:test", "cli:test", nil)
+	conn.SetClientInfo(ClientInfo{RemoteIP: "127.0.0.1", UserAgent: "actionhero-cli", Protocol: "cli"})
+	if conn.ClientInfo().Fingerprint == "" {
+		t.Error("Expected SetClientInfo to compute a fingerprint")
+	}
+
+	conn.Act(context.Background(), apiInstance, "test:action", nil, "CLI", "")
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected a JSON log entry, got %q", buf.String())
+	}
+	if entry["userAgent"] != "actionhero-cli" || entry["fingerprint"] != conn.ClientInfo().Fingerprint {
+		t.Errorf("Expected the client info in the access log, got %v", entry)
+	}
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// defaultRetryAfter is suggested to shed callers when neither RetryAfter nor
+// QueueTimeout is set
+const defaultRetryAfter = time.Second
+
+// actionLimiter is a semaphore bounding an action's concurrent executions
+type actionLimiter struct {
+	slots        chan struct{}
+	queueTimeout time.Duration
+	retryAfter   time.Duration
+}
+
+// newActionLimiter returns a limiter for cfg, or nil when cfg sets no limit
+func newActionLimiter(cfg *ConcurrencyConfig) *actionLimiter {
+	if cfg == nil || cfg.Max <= 0 {
+		return nil
+	}
+
+	retryAfter := cfg.RetryAfter
+	if retryAfter <= 0 {
+		retryAfter = cfg.QueueTimeout
+	}
+	if retryAfter <= 0 {
+		retryAfter = defaultRetryAfter
+	}
+	return &actionLimiter{
+		slots:        make(chan struct{}, cfg.Max),
+		queueTimeout: cfg.QueueTimeout,
+		retryAfter:   retryAfter,
+	}
+}
+
+// acquire takes a slot, waiting up to the queue timeout for one to free up.
+// The returned func releases the slot. A nil limiter never blocks.
+func (l *actionLimiter) acquire(ctx context.Context, actionName string) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, nil
+	default:
+	}
+	if l.queueTimeout <= 0 {
+		return nil, l.saturated(actionName)
+	}
+
+	timer := time.NewTimer(l.queueTimeout)
+	defer timer.Stop()
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, nil
+	case <-timer.C:
+		return nil, l.saturated(actionName)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// release frees a slot taken by acquire
+func (l *actionLimiter) release() {
+	<-l.slots
+}
+
+// saturated returns the error for a request shed at the concurrency limit
+func (l *actionLimiter) saturated(actionName string) error {
+	return util.NewTypedError(util.ErrorTypeConnectionActionSaturated,
+		fmt.Sprintf("action %s is at its concurrency limit (%d)", actionName, cap(l.slots)),
+		util.WithRetryAfter(l.retryAfter))
+}
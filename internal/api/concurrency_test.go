@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// blockingAction runs until release is closed
+type blockingAction struct {
+	BaseAction
+	started chan struct{}
+	release chan struct{}
+}
+
+func (a *blockingAction) Run(ctx context.Context, params interface{}, conn *Connection) (interface{}, error) {
+	a.started <- struct{}{}
+	<-a.release
+	return "done", nil
+}
+
+func newBlockingAction(concurrency *ConcurrencyConfig) *blockingAction {
+	return &blockingAction{
+		BaseAction: BaseAction{ActionName: "test:blocking", ActionConcurrency: concurrency},
+		started:    make(chan struct{}, 10),
+		release:    make(chan struct{}),
+	}
+}
+
+func TestNewActionLimiter(t *testing.T) {
+	tests := []struct {
+		name           string
+		cfg            *ConcurrencyConfig
+		wantNil        bool
+		wantRetryAfter time.Duration
+	}{
+		{"nil config", nil, true, 0},
+		{"unlimited", &ConcurrencyConfig{Max: 0}, true, 0},
+		{"default retry after", &ConcurrencyConfig{Max: 1}, false, time.Second},
+		{"queue timeout retry after", &ConcurrencyConfig{Max: 1, QueueTimeout: 3 * time.Second}, false, 3 * time.Second},
+		{"explicit retry after", &ConcurrencyConfig{Max: 1, QueueTimeout: time.Second, RetryAfter: 5 * time.Second}, false, 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := newActionLimiter(tt.cfg)
+			if (limiter == nil) != tt.wantNil {
+				t.Fatalf("Expected nil limiter = %v, got %v", tt.wantNil, limiter)
+			}
+			if limiter != nil && limiter.retryAfter != tt.wantRetryAfter {
+				t.Errorf("Expected retryAfter %v, got %v", tt.wantRetryAfter, limiter.retryAfter)
+			}
+		})
+	}
+}
+
+func TestConnection_Act_ConcurrencyLimit(t *testing.T) {
+	apiInstance := New(&config.Config{}, util.NewLogger(config.DefaultLoggerConfig()))
+	action := newBlockingAction(&ConcurrencyConfig{Max: 1, RetryAfter: 2 * time.Second})
+	if err := apiInstance.RegisterAction(action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		conn := NewConnection("web", "127.0.0.1", "first", nil)
+		if result := conn.Act(context.Background(), apiInstance, "test:blocking", nil, "GET", ""); result.Error != nil {
+			t.Errorf("Expected the first execution to succeed, got %v", result.Error)
+		}
+	}()
+	<-action.started
+
+	conn := NewConnection("web", "127.0.0.1", "second", nil)
+	result := conn.Act(context.Background(), apiInstance, "test:blocking", nil, "GET", "")
+	typedErr, ok := result.Error.(*util.TypedError)
+	if !ok || typedErr.Type != util.ErrorTypeConnectionActionSaturated {
+		t.Fatalf("Expected a %s error, got %v", util.ErrorTypeConnectionActionSaturated, result.Error)
+	}
+	if typedErr.HTTPStatus() != 503 {
+		t.Errorf("Expected status 503, got %d", typedErr.HTTPStatus())
+	}
+	if typedErr.RetryAfter != 2*time.Second {
+		t.Errorf("Expected RetryAfter 2s, got %v", typedErr.RetryAfter)
+	}
+	if shouldReportError(result.Error) {
+		t.Error("Expected shed requests not to be reported")
+	}
+
+	close(action.release)
+	wg.Wait()
+
+	// The slot is free again
+	if result := conn.Act(context.Background(), apiInstance, "test:blocking", nil, "GET", ""); result.Error != nil {
+		t.Fatalf("Expected execution after release to succeed, got %v", result.Error)
+	}
+}
+
+func TestConnection_Act_ConcurrencyQueue(t *testing.T) {
+	apiInstance := New(&config.Config{}, util.NewLogger(config.DefaultLoggerConfig()))
+	action := newBlockingAction(&ConcurrencyConfig{Max: 1, QueueTimeout: time.Second})
+	if err := apiInstance.RegisterAction(action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	results := make(chan ActResult, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			conn := NewConnection("web", "127.0.0.1", "queued", nil)
+			results <- conn.Act(context.Background(), apiInstance, "test:blocking", nil, "GET", "")
+		}()
+	}
+
+	// Only one runs at a time; the other waits in the queue for the slot
+	<-action.started
+	select {
+	case <-action.started:
+		t.Fatal("Expected the second execution to wait for a slot")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(action.release)
+	for i := 0; i < 2; i++ {
+		if result := <-results; result.Error != nil {
+			t.Errorf("Expected queued executions to succeed, got %v", result.Error)
+		}
+	}
+}
+
+func TestConnection_Act_ConcurrencySlotHeldPastTimeout(t *testing.T) {
+	apiInstance := New(&config.Config{}, util.NewLogger(config.DefaultLoggerConfig()))
+	action := newBlockingAction(&ConcurrencyConfig{Max: 1})
+	action.ActionTimeout = 10 * time.Millisecond
+	if err := apiInstance.RegisterAction(action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	conn := NewConnection("web", "127.0.0.1", "timeout", nil)
+	result := conn.Act(context.Background(), apiInstance, "test:blocking", nil, "GET", "")
+	if !isActionTimeout(result.Error) {
+		t.Fatalf("Expected a timeout, got %v", result.Error)
+	}
+
+	// The timed-out action is still running, so its slot is still taken
+	result = conn.Act(context.Background(), apiInstance, "test:blocking", nil, "GET", "")
+	if typedErr, ok := result.Error.(*util.TypedError); !ok || typedErr.Type != util.ErrorTypeConnectionActionSaturated {
+		t.Fatalf("Expected a %s error, got %v", util.ErrorTypeConnectionActionSaturated, result.Error)
+	}
+	close(action.release)
+}
@@ -3,22 +3,42 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"runtime/debug"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/evantahler/go-actionhero/internal/config"
 	"github.com/evantahler/go-actionhero/internal/util"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 )
 
-// Context keys for passing API and Config
+// Context keys for passing the API, Config, connection, and negotiated
+// locales. Act sets them for every action, whichever server it came from (as
+// well as the request ID and tenant); read them with APIFromContext,
+// ConfigFromContext, ConnectionFromContext, LocalesFromContext,
+// util.RequestIDFromContext, and TenantFromContext. Actions can read the API
+// and Config (and any other shared service) with Resource instead.
 type ContextKey string
 
 const (
-	ContextKeyAPI    ContextKey = "api"
-	ContextKeyConfig ContextKey = "config"
+	ContextKeyAPI        ContextKey = "api"
+	ContextKeyConfig     ContextKey = "config"
+	ContextKeyLocales    ContextKey = "locales"
+	ContextKeyConnection ContextKey = "connection"
 )
 
+// WithAPI returns a copy of ctx carrying api and its Config, as actions'
+// contexts do (e.g., to call an action's Run directly in a test)
+func WithAPI(ctx context.Context, api *API) context.Context {
+	ctx = context.WithValue(ctx, ContextKeyAPI, api)
+	return context.WithValue(ctx, ContextKeyConfig, api.CurrentConfig())
+}
+
 // APIFromContext retrieves the API instance from context
 func APIFromContext(ctx context.Context) *API {
 	if api, ok := ctx.Value(ContextKeyAPI).(*API); ok {
@@ -35,6 +55,21 @@ func ConfigFromContext(ctx context.Context) *config.Config {
 	return nil
 }
 
+// WithConnection returns a copy of ctx carrying conn
+func WithConnection(ctx context.Context, conn *Connection) context.Context {
+	return context.WithValue(ctx, ContextKeyConnection, conn)
+}
+
+// ConnectionFromContext returns the connection an action runs for, or nil
+// (e.g., for code called by the action that isn't handed the connection)
+func ConnectionFromContext(ctx context.Context) *Connection {
+	if ctx == nil {
+		return nil
+	}
+	conn, _ := ctx.Value(ContextKeyConnection).(*Connection)
+	return conn
+}
+
 // SessionData represents session information
 type SessionData struct {
 	ID         string
@@ -54,8 +89,22 @@ type Connection struct {
 
 	mu            sync.RWMutex
 	sessionLoaded bool
+	params        map[string]interface{} // Sticky params merged into every action call
+	locales       []string               // Preferred locales for messages, most preferred first
+	values        map[string]interface{} // Scratch storage for middleware and actions (see Set)
+	lastActive    time.Time              // When the client last sent something (see Touch)
+	clientInfo    ClientInfo             // Who is connected (see SetClientInfo)
+	tenant        *Tenant                // Tenant the connection is for (see SetTenant)
+	dryRun        bool                   // Actions run as dry runs (see SetDryRun)
+	progress      ProgressHandler        // Delivers progress reports (see SetProgressHandler)
+	api           *API                   // API the connection last ran an action with (see Render)
+	running       atomic.Int32           // Actions running with a timeout, which may outlive Act (see ConnectionPool.Put)
 }
 
+// ConnectionTypeTask is the type of the connections the task worker runs
+// actions on. Their failures are reported by the worker (see ErrorSourceTask).
+const ConnectionTypeTask = "task"
+
 // NewConnection creates a new connection
 func NewConnection(connType, identifier, id string, rawConnection interface{}) *Connection {
 	return &Connection{
@@ -64,6 +113,7 @@ func NewConnection(connType, identifier, id string, rawConnection interface{}) *
 		ID:            id,
 		Subscriptions: make(map[string]bool),
 		RawConnection: rawConnection,
+		lastActive:    time.Now(),
 	}
 }
 
@@ -88,6 +138,21 @@ func (c *Connection) IsSubscribed(channel string) bool {
 	return c.Subscriptions[channel]
 }
 
+// Channels returns the channels the connection is subscribed to, sorted
+func (c *Connection) Channels() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	channels := make([]string, 0, len(c.Subscriptions))
+	for channel, subscribed := range c.Subscriptions {
+		if subscribed {
+			channels = append(channels, channel)
+		}
+	}
+	sort.Strings(channels)
+	return channels
+}
+
 // SetSession sets the session data
 func (c *Connection) SetSession(session *SessionData) {
 	c.mu.Lock()
@@ -103,10 +168,167 @@ func (c *Connection) IsSessionLoaded() bool {
 	return c.sessionLoaded
 }
 
+// ExpireSession clears the session if it was created more than ttl ago,
+// reporting whether it did
+func (c *Connection) ExpireSession(ttl time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Session == nil || ttl <= 0 || time.Since(time.Unix(c.Session.CreatedAt, 0)) <= ttl {
+		return false
+	}
+	c.Session = nil
+	return true
+}
+
+// Touch records activity from the client, so the connection isn't
+// considered idle
+func (c *Connection) Touch() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastActive = time.Now()
+}
+
+// IdleFor returns how long it has been since the client last sent something
+func (c *Connection) IdleFor() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return time.Since(c.lastActive)
+}
+
+// SetParam stores a sticky param: it is passed to every later action called
+// on this connection, unless the call sets the same param itself
+func (c *Connection) SetParam(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.params == nil {
+		c.params = make(map[string]interface{})
+	}
+	c.params[key] = value
+}
+
+// DeleteParam removes a sticky param
+func (c *Connection) DeleteParam(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.params, key)
+}
+
+// ClearParams removes every sticky param
+func (c *Connection) ClearParams() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.params = nil
+}
+
+// Params returns a copy of the connection's sticky params
+func (c *Connection) Params() map[string]interface{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	params := make(map[string]interface{}, len(c.params))
+	for key, value := range c.params {
+		params[key] = value
+	}
+	return params
+}
+
+// SetLocales sets the connection's preferred locales (e.g., from an
+// Accept-Language header), most preferred first
+func (c *Connection) SetLocales(locales ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.locales = append([]string(nil), locales...)
+}
+
+// Locales returns the connection's preferred locales, most preferred first
+func (c *Connection) Locales() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]string(nil), c.locales...)
+}
+
+// Set stores a value on the connection for later middleware and actions
+// (e.g., the authenticated user). Unlike Session.Data, values aren't
+// persisted and last only as long as the connection.
+func (c *Connection) Set(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.values == nil {
+		c.values = make(map[string]interface{})
+	}
+	c.values[key] = value
+}
+
+// Get returns a value stored with Set and whether it was set
+func (c *Connection) Get(key string) (interface{}, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	value, ok := c.values[key]
+	return value, ok
+}
+
+// Delete removes a value stored with Set
+func (c *Connection) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.values, key)
+}
+
+// SetTenant sets the tenant the connection's actions run for (see
+// TenantFromContext)
+func (c *Connection) SetTenant(tenant *Tenant) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tenant = tenant
+}
+
+// Tenant returns the tenant the connection is for, or nil
+func (c *Connection) Tenant() *Tenant {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.tenant
+}
+
+// SetDryRun sets whether the connection's actions run as dry runs (see
+// IsDryRun)
+func (c *Connection) SetDryRun(dryRun bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dryRun = dryRun
+}
+
+// DryRun returns whether the connection's actions run as dry runs
+func (c *Connection) DryRun() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.dryRun
+}
+
+// withParams returns params merged over the connection's sticky params
+func (c *Connection) withParams(params map[string]interface{}) map[string]interface{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if len(c.params) == 0 {
+		return params
+	}
+
+	merged := make(map[string]interface{}, len(c.params)+len(params))
+	for key, value := range c.params {
+		merged[key] = value
+	}
+	for key, value := range params {
+		merged[key] = value
+	}
+	return merged
+}
+
 // ActResult contains the result of an action execution
 type ActResult struct {
-	Response interface{}
-	Error    error
+	Response  interface{}
+	Error     error
+	RequestID string   // Correlation ID used for this execution
+	Cached    bool     // The response was served from the response cache
+	Locales   []string // Locales the action ran with, to translate its error into (see NegotiateLocales)
 }
 
 // Act executes an action with the given parameters, handling all middleware,
@@ -122,39 +344,305 @@ func (c *Connection) Act(
 ) ActResult {
 	startTime := time.Now()
 	loggerStatus := "OK"
+	c.Touch()
+	c.mu.Lock()
+	c.api = api
+	c.mu.Unlock()
+	params = c.withParams(params)
+	locales := api.NegotiateLocales(params, c.Locales())
 	var response interface{}
 	var err error
+	found := false
+
+	// Reuse the caller's request ID (e.g. from an X-Request-ID header) or start a new one
+	requestID := util.RequestIDFromContext(ctx)
+	if requestID == "" {
+		requestID = uuid.New().String()
+		ctx = util.WithRequestID(ctx, requestID)
+	}
+
+	// Give the action (and event handlers) the API, its config, and the
+	// connection, the same for every server
+	ctx = WithAPI(ctx, api)
+	cfg := ConfigFromContext(ctx) // One snapshot of reloadable settings for the whole run
+	ctx = WithConnection(ctx, c)
+	ctx = WithLocales(ctx, locales)
+	if tenant := c.Tenant(); tenant != nil && TenantFromContext(ctx) == nil {
+		ctx = WithTenant(ctx, tenant)
+	}
+	if c.DryRun() {
+		ctx = WithDryRun(ctx, true)
+	}
 
 	defer func() {
 		// Log the request after execution
-		duration := time.Since(startTime).Milliseconds()
-		c.logRequest(api.Logger, loggerStatus, actionName, duration, method, url, params, err)
+		elapsed := time.Since(startTime)
+		c.logRequest(ctx, api.Logger, loggerStatus, actionName, elapsed.Milliseconds(), method, url, params, err)
+		if cfg != nil && cfg.Logger.SlowAction > 0 && elapsed > cfg.Logger.SlowAction {
+			c.logSlowAction(ctx, api.Logger, actionName, elapsed.Milliseconds(), cfg.Logger.SlowAction, params)
+		}
+
+		// Only record known actions, so unknown names can't grow the metrics unbounded
+		if found {
+			api.Metrics.Record(actionName, elapsed, err)
+
+			event := Event{
+				Name:       EventActionComplete,
+				Action:     actionName,
+				Connection: c,
+				Params:     params,
+				Response:   response,
+				Error:      err,
+				Duration:   elapsed,
+			}
+			if err != nil {
+				event.Name = EventActionError
+			}
+			api.Emit(ctx, event)
+		}
 	}()
 
 	// Find the action
-	action, exists := api.GetAction(actionName)
+	descriptor, exists := api.GetActionDescriptor(actionName)
 	if !exists {
 		loggerStatus = "ERROR"
 		err = fmt.Errorf("action not found: %s", actionName)
-		return ActResult{Response: nil, Error: err}
+		return ActResult{Response: nil, Error: err, RequestID: requestID, Locales: locales}
 	}
+	found = true
 
-	// Store API instance and config in context for actions that need them
-	ctx = context.WithValue(ctx, ContextKeyAPI, api)
-	ctx = context.WithValue(ctx, ContextKeyConfig, api.Config)
+	if descriptor.Deprecation != nil {
+		c.logDeprecatedAction(ctx, api.Logger, descriptor)
+	}
+
+	// Middleware (e.g., authentication) may refuse the action, or answer for
+	// it, before anything else happens
+	var stopped bool
+	params, response, stopped, err = runBefore(descriptor.Middleware, params, c)
+	if stopped {
+		if err != nil {
+			loggerStatus = "ERROR"
+		}
+		return ActResult{Response: response, Error: err, RequestID: requestID, Locales: locales}
+	}
+
+	// In a dry run, describe what a mutating action would do instead of
+	// running it, unless it handles dry runs itself
+	if IsDryRun(ctx) && descriptor.Mutating() && !descriptor.DryRun {
+		loggerStatus = "DRY_RUN"
+		response = descriptor.dryRunResponse(params)
+		return ActResult{Response: response, Error: nil, RequestID: requestID, Locales: locales}
+	}
+
+	// Serve cached responses without running the action
+	cacheable := descriptor.Cache != nil && descriptor.Cache.TTL > 0 && api.Cache != nil
+	if cacheable {
+		if cached, hit := api.cachedResponse(ctx, descriptor, params); hit {
+			response = cached
+			return ActResult{Response: response, Error: nil, RequestID: requestID, Cached: true, Locales: locales}
+		}
+	}
+
+	// Wait for a free slot when the action's concurrency is capped
+	release, err := descriptor.limiter.acquire(ctx, actionName)
+	if err != nil {
+		loggerStatus = "ERROR"
+		return ActResult{Response: nil, Error: err, RequestID: requestID, Locales: locales}
+	}
 
 	// Execute the action
-	response, err = action.Run(ctx, params, c)
+	var stack string
+	response, stack, err = c.runActionWithTimeout(ctx, descriptor.Action, params, actionTimeout(cfg, descriptor), release)
 	if err != nil {
 		loggerStatus = "ERROR"
-		return ActResult{Response: nil, Error: err}
+		if isActionTimeout(err) {
+			loggerStatus = "TIMEOUT"
+		}
+		// Failed tasks are reported by the task worker, as ErrorSourceTask
+		if c.Type != ConnectionTypeTask && (stack != "" || shouldReportError(err)) {
+			source := ErrorSourceAction
+			if stack != "" {
+				source = ErrorSourcePanic
+			}
+			api.ReportError(ctx, ErrorReport{
+				Error:      err,
+				Source:     source,
+				Action:     actionName,
+				Connection: c,
+				Params:     params,
+				Stack:      stack,
+			})
+		}
+		return ActResult{Response: nil, Error: err, RequestID: requestID, Locales: locales}
+	}
+
+	response, err = runAfter(descriptor.Middleware, params, c, response)
+	if err != nil {
+		loggerStatus = "ERROR"
+		response = nil
+		return ActResult{Response: nil, Error: err, RequestID: requestID, Locales: locales}
 	}
 
-	return ActResult{Response: response, Error: nil}
+	// Measure the response, and refuse it when it is over the action's limit
+	if size, ok := responseSize(response); ok {
+		tooLarge := descriptor.MaxResponseSize > 0 && size > int64(descriptor.MaxResponseSize)
+		api.Metrics.RecordResponseSize(actionName, size, tooLarge)
+		if tooLarge {
+			loggerStatus = "ERROR"
+			response = nil
+			err = newResponseTooLargeError(size, descriptor.MaxResponseSize)
+			if c.Type != ConnectionTypeTask {
+				api.ReportError(ctx, ErrorReport{
+					Error:      err,
+					Source:     ErrorSourceAction,
+					Action:     actionName,
+					Connection: c,
+					Params:     params,
+				})
+			}
+			return ActResult{Response: nil, Error: err, RequestID: requestID, Locales: locales}
+		}
+	}
+
+	if cacheable {
+		api.cacheResponse(ctx, descriptor, params, response)
+	}
+	return ActResult{Response: response, Error: nil, RequestID: requestID, Locales: locales}
+}
+
+// runAction runs the action, converting a panic into an error.
+// The stack is only returned when the action panicked.
+func (c *Connection) runAction(ctx context.Context, action Action, params map[string]interface{}) (response interface{}, stack string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			stack = string(debug.Stack())
+			err = util.NewTypedError(util.ErrorTypeConnectionActionRun, fmt.Sprintf("action panicked: %v", r))
+			response = nil
+		}
+	}()
+
+	response, err = action.Run(ctx, params, c)
+	return response, "", err
+}
+
+// actionTimeout returns how long the action may run: its own ActionTimeout,
+// or the default from process.actiontimeout in cfg (0 = no timeout)
+func actionTimeout(cfg *config.Config, descriptor *ActionDescriptor) time.Duration {
+	if descriptor.Timeout > 0 {
+		return descriptor.Timeout
+	}
+	if cfg != nil {
+		return cfg.Process.ActionTimeout
+	}
+	return 0
+}
+
+// runActionWithTimeout runs the action with a deadline on its context. When
+// the deadline passes, a timeout error is returned without waiting for the
+// action; it keeps running until it notices its context is done. release is
+// called once the action has returned (even after a timeout), so a
+// concurrency slot stays taken while the action is still running.
+func (c *Connection) runActionWithTimeout(ctx context.Context, action Action, params map[string]interface{}, timeout time.Duration, release func()) (interface{}, string, error) {
+	if timeout <= 0 {
+		defer release()
+		return c.runAction(ctx, action, params)
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		response interface{}
+		stack    string
+		err      error
+	}
+	done := make(chan result, 1)
+	c.running.Add(1)
+	go func() {
+		defer c.running.Add(-1)
+		defer release()
+		response, stack, err := c.runAction(ctx, action, params)
+		done <- result{response, stack, err}
+	}()
+
+	select {
+	case res := <-done:
+		if res.err != nil && errors.Is(res.err, context.DeadlineExceeded) && timedOut(parent, ctx) {
+			return nil, "", newActionTimeoutError(timeout, res.err)
+		}
+		return res.response, res.stack, res.err
+	case <-ctx.Done():
+		if timedOut(parent, ctx) {
+			return nil, "", newActionTimeoutError(timeout, ctx.Err())
+		}
+		// The caller canceled: wait for the action, as without a timeout
+		res := <-done
+		return res.response, res.stack, res.err
+	}
+}
+
+// timedOut returns whether ctx hit its own deadline (rather than its parent
+// being canceled or expiring)
+func timedOut(parent, ctx context.Context) bool {
+	return errors.Is(ctx.Err(), context.DeadlineExceeded) && parent.Err() == nil
+}
+
+// newActionTimeoutError returns the error for an action that ran past timeout
+func newActionTimeoutError(timeout time.Duration, cause error) error {
+	return util.NewTypedError(util.ErrorTypeConnectionActionTimeout,
+		fmt.Sprintf("action timed out after %s", timeout),
+		util.WithOriginalError(cause))
+}
+
+// isActionTimeout returns whether err is an action timeout
+func isActionTimeout(err error) bool {
+	var typedErr *util.TypedError
+	return errors.As(err, &typedErr) && typedErr.Type == util.ErrorTypeConnectionActionTimeout
+}
+
+// logDeprecatedAction warns that a deprecated action was called, and by whom
+func (c *Connection) logDeprecatedAction(ctx context.Context, logger *util.Logger, descriptor *ActionDescriptor) {
+	logger.WithContext(ctx).Warnf("%s %s [%s:%s] %s",
+		logger.ColorizeIf("[ACTION:DEPRECATED]", util.ColorYellow, true),
+		descriptor.Deprecation.Notice(descriptor.Name),
+		c.Type,
+		c.ID,
+		c.Identifier,
+	)
+}
+
+// logSlowAction warns that an action took longer than the configured threshold
+func (c *Connection) logSlowAction(
+	ctx context.Context,
+	logger *util.Logger,
+	actionName string,
+	duration int64,
+	threshold time.Duration,
+	params map[string]interface{},
+) {
+	paramsJSON := "{}"
+	if params != nil {
+		if jsonBytes, jsonErr := json.Marshal(redactParams(params)); jsonErr == nil {
+			paramsJSON = string(jsonBytes)
+		}
+	}
+
+	logger.WithContext(ctx).Warnf("%s %s took %dms (threshold %dms) [%s:%s] %s %s",
+		logger.ColorizeIf("[ACTION:SLOW]", util.ColorYellow, true),
+		actionName,
+		duration,
+		threshold.Milliseconds(),
+		c.Type,
+		c.ID,
+		c.Identifier,
+		paramsJSON,
+	)
 }
 
 // logRequest logs the action execution similar to the Bun version
 func (c *Connection) logRequest(
+	ctx context.Context,
 	logger *util.Logger,
 	status string,
 	actionName string,
@@ -164,11 +652,24 @@ func (c *Connection) logRequest(
 	params map[string]interface{},
 	err error,
 ) {
+	// Skip formatting the line when it would be discarded
+	if !logger.IsLevelEnabled(logrus.InfoLevel) {
+		return
+	}
+
+	// Repeated identical errors (e.g. a broken downstream) are sampled
+	if err != nil && !logger.AllowError(actionName+": "+err.Error()) {
+		return
+	}
+
 	// Format status prefix with colors
 	var statusPrefix string
-	if status == "OK" {
+	switch status {
+	case "OK":
 		statusPrefix = logger.ColorizeIf("[ACTION:OK]", util.ColorBlue, true)
-	} else {
+	case "TIMEOUT":
+		statusPrefix = logger.ColorizeIf("[ACTION:TIMEOUT]", util.ColorYellow, true)
+	default:
 		statusPrefix = logger.ColorizeIf("[ACTION:ERROR]", util.ColorMagenta, true)
 	}
 
@@ -180,8 +681,7 @@ func (c *Connection) logRequest(
 	// Format params as JSON (colorized if enabled)
 	paramsJSON := "{}"
 	if params != nil {
-		// TODO: Sanitize secret params before logging
-		if jsonBytes, jsonErr := json.Marshal(params); jsonErr == nil {
+		if jsonBytes, jsonErr := json.Marshal(redactParams(params)); jsonErr == nil {
 			paramsJSON = logger.ColorizeIf(string(jsonBytes), util.ColorGray, false)
 		}
 	}
@@ -204,8 +704,22 @@ func (c *Connection) logRequest(
 		urlStr = fmt.Sprintf(" (%s)", url)
 	}
 
-	// Log the request (matching Bun format)
-	logger.Infof("%s %s (%dms)%s %s%s%s %s",
+	// Log the request (matching Bun format), with the client's details as fields
+	entry := logger.WithContext(ctx)
+	client := c.ClientInfo()
+	if client.UserAgent != "" {
+		entry = entry.WithField("userAgent", client.UserAgent)
+	}
+	if client.Fingerprint != "" {
+		entry = entry.WithField("fingerprint", client.Fingerprint)
+	}
+	if client.Location.Country != "" {
+		entry = entry.WithField("country", client.Location.Country)
+	}
+	if client.Location.Region != "" {
+		entry = entry.WithField("region", client.Location.Region)
+	}
+	entry.Infof("%s %s (%dms)%s %s%s%s %s",
 		statusPrefix,
 		actionName,
 		duration,
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// newBenchAPI returns an API that only logs errors, so dispatch isn't
+// measured with logging
+func newBenchAPI(b *testing.B) *API {
+	a := New(&config.Config{}, util.NewLogger(config.LoggerConfig{Level: "error"}))
+	if err := a.RegisterAction(newMockAction("bench:mock", "benchmarked")); err != nil {
+		b.Fatalf("Failed to register action: %v", err)
+	}
+	if err := a.RegisterAction(NewTypedAction[greetInput, greetOutput](&greetAction{
+		BaseAction: BaseAction{ActionName: "bench:typed"},
+	})); err != nil {
+		b.Fatalf("Failed to register action: %v", err)
+	}
+	return a
+}
+
+func BenchmarkConnection_Act(b *testing.B) {
+	a := newBenchAPI(b)
+	ctx := context.Background()
+	params := map[string]interface{}{"name": "Mario", "times": 1}
+
+	for _, name := range []string{"bench:mock", "bench:typed"} {
+		b.Run(name, func(b *testing.B) {
+			conn := NewConnection("bench", "127.0.0.1", "bench-id", nil)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if result := conn.Act(ctx, a, name, params, "", ""); result.Error != nil {
+					b.Fatal(result.Error)
+				}
+			}
+		})
+	}
+}
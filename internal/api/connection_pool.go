@@ -0,0 +1,64 @@
+package api
+
+import (
+	"sync"
+	"time"
+)
+
+// ConnectionPool reuses the connections of short-lived requests (e.g., the
+// web server's HTTP requests), so each request doesn't allocate its own. A
+// connection is put back once its request has completed, and must not be
+// used after that: event handlers and error reporters may read a request's
+// connection while they are called, but not keep it.
+type ConnectionPool struct {
+	pool sync.Pool
+}
+
+// Get returns a pooled connection, or a new one, set up as NewConnection
+// would
+func (p *ConnectionPool) Get(connType, identifier, id string, rawConnection interface{}) *Connection {
+	c, ok := p.pool.Get().(*Connection)
+	if !ok {
+		return NewConnection(connType, identifier, id, rawConnection)
+	}
+	c.Type = connType
+	c.Identifier = identifier
+	c.ID = id
+	c.RawConnection = rawConnection
+	c.lastActive = time.Now()
+	return c
+}
+
+// Put clears c and returns it to the pool. A connection whose action is
+// still running (it timed out) isn't reused.
+func (p *ConnectionPool) Put(c *Connection) {
+	if c.running.Load() > 0 {
+		return
+	}
+	c.reset()
+	p.pool.Put(c)
+}
+
+// reset clears the connection for reuse, keeping its maps' storage
+func (c *Connection) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	subscriptions, values := c.Subscriptions, c.values
+	clear(subscriptions)
+	clear(values)
+
+	c.Type, c.Identifier, c.ID = "", "", ""
+	c.Session = nil
+	c.Subscriptions = subscriptions
+	c.RawConnection = nil
+	c.sessionLoaded = false
+	c.params = nil
+	c.locales = nil
+	c.values = values
+	c.lastActive = time.Time{}
+	c.clientInfo = ClientInfo{}
+	c.tenant = nil
+	c.dryRun = false
+	c.progress = nil
+	c.api = nil
+}
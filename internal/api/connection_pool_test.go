@@ -0,0 +1,63 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestConnectionPool(t *testing.T) {
+	var pool ConnectionPool
+
+	conn := pool.Get("http", "127.0.0.1", "first", nil)
+	if conn.Type != "http" || conn.ID != "first" || conn.Subscriptions == nil {
+		t.Fatalf("Expected a connection set up like NewConnection, got %+v", conn)
+	}
+	conn.Subscribe("room")
+	conn.SetSession(&SessionData{ID: "session"})
+	conn.SetParam("sticky", true)
+	conn.SetLocales("fr")
+	conn.Set("user", "mario")
+	conn.SetClientInfo(ClientInfo{RemoteIP: "10.0.0.1"})
+	conn.SetTenant(&Tenant{ID: "acme"})
+	conn.SetDryRun(true)
+	conn.SetProgressHandler(func(Progress) {})
+
+	// Nothing of a request is left for the next one
+	conn.reset()
+	if conn.ID != "" || conn.Session != nil || conn.IsSubscribed("room") || len(conn.Params()) != 0 ||
+		len(conn.Locales()) != 0 || conn.ClientInfo() != (ClientInfo{}) || conn.Tenant() != nil ||
+		conn.DryRun() || conn.progress != nil {
+		t.Errorf("Expected a reset connection, got %+v", conn)
+	}
+	if _, ok := conn.Get("user"); ok {
+		t.Error("Expected the connection's values to be cleared")
+	}
+
+	reused := pool.Get("http", "127.0.0.2", "second", nil)
+	if reused.ID != "second" || reused.Identifier != "127.0.0.2" || reused.IsSubscribed("room") {
+		t.Errorf("Expected a connection for the second request, got %+v", reused)
+	}
+}
+
+func TestConnectionPool_RunningAction(t *testing.T) {
+	var pool ConnectionPool
+	conn := pool.Get("http", "127.0.0.1", "slow", nil)
+
+	// The action outlives its timeout, so the connection isn't reused
+	// while it runs
+	done := make(chan struct{})
+	_, _, err := conn.runActionWithTimeout(context.Background(), NewAction("test:slow").Handler(
+		func(context.Context, interface{}, *Connection) (interface{}, error) {
+			<-done
+			return nil, nil
+		}), nil, 10*time.Millisecond, func() {})
+	if !isActionTimeout(err) {
+		t.Fatalf("Expected a timeout, got %v", err)
+	}
+	pool.Put(conn)
+	if conn.ID != "slow" {
+		t.Errorf("Expected the running action's connection to be left alone, got %+v", conn)
+	}
+	close(done)
+}
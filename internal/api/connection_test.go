@@ -3,8 +3,13 @@ package api
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"reflect"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/evantahler/go-actionhero/internal/config"
 	"github.com/evantahler/go-actionhero/internal/util"
@@ -47,6 +52,137 @@ func TestConnection_Unsubscribe(t *testing.T) {
 	}
 }
 
+func TestConnection_Channels(t *testing.T) {
+	conn := NewConnection("web", "127.0.0.1", "test-id", nil)
+
+	conn.Subscribe("news")
+	conn.Subscribe("alerts")
+	conn.Subscribe("chat")
+	conn.Unsubscribe("chat")
+	if got := conn.Channels(); !reflect.DeepEqual(got, []string{"alerts", "news"}) {
+		t.Errorf("Expected [alerts news], got %v", got)
+	}
+}
+
+func TestAPI_ErrorJSON_Localized(t *testing.T) {
+	a := New(&config.Config{}, util.NewLogger(config.DefaultLoggerConfig()))
+	a.Messages.Add("fr", map[string]string{
+		string(util.ErrorTypeConnectionActionParamRequired): "Le paramètre {key} est requis",
+	})
+
+	err := util.NewTypedError(util.ErrorTypeConnectionActionParamRequired, "email is required", util.WithKey("email"))
+	if body := a.ErrorJSON(err, "req-1", "fr-FR", "en"); body.Message != "Le paramètre email est requis" {
+		t.Errorf("Expected the French message, got %q", body.Message)
+	}
+	if body := a.ErrorJSON(err, "req-1", "en"); body.Message != "email is required" {
+		t.Errorf("Expected the original message without a translation, got %q", body.Message)
+	}
+	if body := a.ErrorJSON(err, "req-1"); body.Message != "email is required" {
+		t.Errorf("Expected the original message without locales, got %q", body.Message)
+	}
+}
+
+func TestConnection_Locales(t *testing.T) {
+	conn := NewConnection("test", "test-id", "conn-1", nil)
+	if len(conn.Locales()) != 0 {
+		t.Errorf("Expected no locales by default, got %v", conn.Locales())
+	}
+
+	conn.SetLocales("fr-CH", "fr")
+	locales := conn.Locales()
+	if len(locales) != 2 || locales[0] != "fr-CH" {
+		t.Errorf("Expected [fr-CH fr], got %v", locales)
+	}
+	locales[0] = "changed"
+	if conn.Locales()[0] != "fr-CH" {
+		t.Error("Expected Locales to return a copy")
+	}
+}
+
+func TestConnection_StickyParams(t *testing.T) {
+	conn := NewConnection("websocket", "127.0.0.1", "test-id", nil)
+	if merged := conn.withParams(map[string]interface{}{"a": 1}); len(merged) != 1 {
+		t.Errorf("Expected params unchanged without sticky params, got %v", merged)
+	}
+
+	conn.SetParam("locale", "fr")
+	conn.SetParam("token", "abc")
+	merged := conn.withParams(map[string]interface{}{"locale": "en"})
+	if merged["locale"] != "en" || merged["token"] != "abc" {
+		t.Errorf("Expected call params to override sticky params, got %v", merged)
+	}
+
+	conn.DeleteParam("token")
+	if params := conn.Params(); len(params) != 1 || params["locale"] != "fr" {
+		t.Errorf("Expected only locale, got %v", params)
+	}
+
+	conn.ClearParams()
+	if params := conn.Params(); len(params) != 0 {
+		t.Errorf("Expected no params, got %v", params)
+	}
+}
+
+func TestConnection_Values(t *testing.T) {
+	conn := NewConnection("web", "127.0.0.1", "test-id", nil)
+
+	if _, ok := conn.Get("user"); ok {
+		t.Error("Expected no value before Set")
+	}
+
+	conn.Set("user", "evan")
+	if value, ok := conn.Get("user"); !ok || value != "evan" {
+		t.Errorf("Expected the stored value, got %v, %v", value, ok)
+	}
+
+	conn.Delete("user")
+	if _, ok := conn.Get("user"); ok {
+		t.Error("Expected the value to be deleted")
+	}
+
+	// Middleware and actions may run concurrently on WebSocket connections
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			conn.Set(fmt.Sprintf("key%d", i), i)
+			conn.Get("key0")
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestConnection_ExpireSession(t *testing.T) {
+	conn := NewConnection("websocket", "127.0.0.1", "test-id", nil)
+	if conn.ExpireSession(time.Hour) {
+		t.Error("Expected nothing to expire without a session")
+	}
+
+	conn.SetSession(&SessionData{ID: "fresh", CreatedAt: time.Now().Unix()})
+	if conn.ExpireSession(time.Hour) || conn.Session == nil {
+		t.Error("Expected a fresh session to be kept")
+	}
+
+	conn.SetSession(&SessionData{ID: "stale", CreatedAt: time.Now().Add(-2 * time.Hour).Unix()})
+	if !conn.ExpireSession(time.Hour) || conn.Session != nil {
+		t.Error("Expected a stale session to be removed")
+	}
+}
+
+func TestConnection_IdleFor(t *testing.T) {
+	conn := NewConnection("websocket", "127.0.0.1", "test-id", nil)
+	time.Sleep(20 * time.Millisecond)
+	if conn.IdleFor() < 20*time.Millisecond {
+		t.Errorf("Expected the connection to be idle since it was created, got %s", conn.IdleFor())
+	}
+
+	conn.Touch()
+	if conn.IdleFor() >= 20*time.Millisecond {
+		t.Errorf("Expected Touch to reset the idle time, got %s", conn.IdleFor())
+	}
+}
+
 func TestConnection_SetSession(t *testing.T) {
 	conn := NewConnection("web", "127.0.0.1", "test-id", nil)
 	session := &SessionData{
@@ -117,7 +253,7 @@ func TestConnection_Act_LoggingSuccess(t *testing.T) {
 	conn := NewConnection("http", "127.0.0.1", "test-conn-id", nil)
 
 	// Execute action
-	result := conn.Act(context.Background(), apiInstance, "test:success", map[string]interface{}{"foo": "bar"}, "GET", "http://localhost/test")
+	result := conn.Act(context.Background(), apiInstance, "test:success", map[string]interface{}{"foo": "bar", "password": "hunter2"}, "GET", "http://localhost/test")
 
 	if result.Error != nil {
 		t.Fatalf("Expected no error, got %v", result.Error)
@@ -144,6 +280,9 @@ func TestConnection_Act_LoggingSuccess(t *testing.T) {
 			t.Errorf("Expected log to contain %q, but it didn't.\nLog output: %s", expected, logOutput)
 		}
 	}
+	if strings.Contains(logOutput, "hunter2") {
+		t.Errorf("Expected the password to be masked in the log.\nLog output: %s", logOutput)
+	}
 }
 
 func TestConnection_Act_LoggingError(t *testing.T) {
@@ -260,3 +399,388 @@ func TestConnection_Act_LoggingActionNotFound(t *testing.T) {
 		}
 	}
 }
+
+type slowLogAction struct {
+	BaseAction
+	delay time.Duration
+}
+
+func (a *slowLogAction) Run(ctx context.Context, params interface{}, conn *Connection) (interface{}, error) {
+	time.Sleep(a.delay)
+	return nil, nil
+}
+
+func TestConnection_Act_SlowActionWarning(t *testing.T) {
+	tests := []struct {
+		name        string
+		slowAction  time.Duration
+		delay       time.Duration
+		wantWarning bool
+	}{
+		{"exceeds threshold", 5 * time.Millisecond, 20 * time.Millisecond, true},
+		{"under threshold", time.Second, 0, false},
+		{"disabled", 0, 20 * time.Millisecond, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logBuf bytes.Buffer
+			logger := util.NewLogger(config.LoggerConfig{Level: "info"})
+			logger.SetOutput(&logBuf)
+			logger.SetFormatter(&logrus.TextFormatter{
+				DisableColors:    true,
+				DisableTimestamp: true,
+			})
+
+			cfg := &config.Config{Logger: config.LoggerConfig{SlowAction: tt.slowAction}}
+			apiInstance := New(cfg, logger)
+			if err := apiInstance.RegisterAction(&slowLogAction{
+				BaseAction: BaseAction{ActionName: "test:slow"},
+				delay:      tt.delay,
+			}); err != nil {
+				t.Fatalf("Failed to register action: %v", err)
+			}
+
+			conn := NewConnection("web", "127.0.0.1", "slow-conn-id", nil)
+			conn.Act(context.Background(), apiInstance, "test:slow", map[string]interface{}{"foo": "bar"}, "GET", "")
+
+			logOutput := logBuf.String()
+			hasWarning := strings.Contains(logOutput, "[ACTION:SLOW]")
+			if hasWarning != tt.wantWarning {
+				t.Fatalf("Expected slow warning = %v, got log: %s", tt.wantWarning, logOutput)
+			}
+			if !tt.wantWarning {
+				return
+			}
+
+			for _, expected := range []string{"level=warning", "test:slow", "slow-conn-id", "127.0.0.1", "foo", "bar"} {
+				if !strings.Contains(logOutput, expected) {
+					t.Errorf("Expected log to contain %q, but it didn't.\nLog output: %s", expected, logOutput)
+				}
+			}
+		})
+	}
+}
+
+// waitingAction returns once its context is done, or after delay
+type waitingAction struct {
+	BaseAction
+	delay time.Duration
+}
+
+func (a *waitingAction) Run(ctx context.Context, params interface{}, conn *Connection) (interface{}, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(a.delay):
+		return "done", nil
+	}
+}
+
+func TestConnection_Act_Timeout(t *testing.T) {
+	tests := []struct {
+		name           string
+		actionTimeout  time.Duration
+		defaultTimeout time.Duration
+		action         Action
+		wantTimeout    bool
+	}{
+		{"action timeout", 10 * time.Millisecond, 0, &waitingAction{delay: time.Second}, true},
+		{"default timeout", 0, 10 * time.Millisecond, &waitingAction{delay: time.Second}, true},
+		{"action timeout overrides default", time.Second, 10 * time.Millisecond, &waitingAction{delay: 30 * time.Millisecond}, false},
+		{"ignores context", 10 * time.Millisecond, 0, &slowLogAction{delay: 200 * time.Millisecond}, true},
+		{"no timeout", 0, 0, &waitingAction{delay: 20 * time.Millisecond}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logBuf bytes.Buffer
+			logger := util.NewLogger(config.LoggerConfig{Level: "info"})
+			logger.SetOutput(&logBuf)
+			logger.SetFormatter(&logrus.TextFormatter{
+				DisableColors:    true,
+				DisableTimestamp: true,
+			})
+
+			base := BaseAction{ActionName: "test:timeout", ActionTimeout: tt.actionTimeout}
+			switch action := tt.action.(type) {
+			case *waitingAction:
+				action.BaseAction = base
+			case *slowLogAction:
+				action.BaseAction = base
+			}
+
+			cfg := &config.Config{Process: config.ProcessConfig{ActionTimeout: tt.defaultTimeout}}
+			apiInstance := New(cfg, logger)
+			if err := apiInstance.RegisterAction(tt.action); err != nil {
+				t.Fatalf("Failed to register action: %v", err)
+			}
+
+			conn := NewConnection("web", "127.0.0.1", "timeout-conn-id", nil)
+			start := time.Now()
+			result := conn.Act(context.Background(), apiInstance, "test:timeout", nil, "GET", "")
+
+			if !tt.wantTimeout {
+				if result.Error != nil {
+					t.Fatalf("Expected no error, got %v", result.Error)
+				}
+				return
+			}
+
+			if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+				t.Errorf("Expected Act to return at the deadline, took %v", elapsed)
+			}
+			typedErr, ok := result.Error.(*util.TypedError)
+			if !ok || typedErr.Type != util.ErrorTypeConnectionActionTimeout {
+				t.Fatalf("Expected a %s error, got %v", util.ErrorTypeConnectionActionTimeout, result.Error)
+			}
+			if typedErr.HTTPStatus() != 504 {
+				t.Errorf("Expected status 504, got %d", typedErr.HTTPStatus())
+			}
+			if !strings.Contains(logBuf.String(), "[ACTION:TIMEOUT]") {
+				t.Errorf("Expected a timeout log, got: %s", logBuf.String())
+			}
+		})
+	}
+}
+
+func TestConnection_Act_TimeoutCallerCanceled(t *testing.T) {
+	apiInstance := New(&config.Config{}, util.NewLogger(config.DefaultLoggerConfig()))
+	if err := apiInstance.RegisterAction(&waitingAction{
+		BaseAction: BaseAction{ActionName: "test:timeout", ActionTimeout: time.Second},
+		delay:      time.Second,
+	}); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	conn := NewConnection("web", "127.0.0.1", "timeout-conn-id", nil)
+	result := conn.Act(ctx, apiInstance, "test:timeout", nil, "GET", "")
+	if result.Error != context.Canceled {
+		t.Fatalf("Expected the caller's cancellation, got %v", result.Error)
+	}
+}
+
+func TestConnection_Act_DeprecationWarning(t *testing.T) {
+	var logBuf bytes.Buffer
+	logger := util.NewLogger(config.LoggerConfig{Level: "info"})
+	logger.SetOutput(&logBuf)
+	logger.SetFormatter(&logrus.TextFormatter{
+		DisableColors:    true,
+		DisableTimestamp: true,
+	})
+
+	apiInstance := New(&config.Config{}, logger)
+	if err := apiInstance.RegisterAction(&testLogAction{
+		BaseAction: BaseAction{
+			ActionName:        "test:old",
+			ActionDeprecation: &DeprecationConfig{Replacement: "test:new"},
+		},
+	}); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	conn := NewConnection("web", "127.0.0.1", "deprecated-conn-id", nil)
+	conn.Act(context.Background(), apiInstance, "test:old", nil, "GET", "")
+
+	logOutput := logBuf.String()
+	for _, expected := range []string{"level=warning", "[ACTION:DEPRECATED]", "test:old is deprecated; use test:new instead", "deprecated-conn-id"} {
+		if !strings.Contains(logOutput, expected) {
+			t.Errorf("Expected log to contain %q, but it didn't.\nLog output: %s", expected, logOutput)
+		}
+	}
+}
+
+func TestConnection_Act_RequestID(t *testing.T) {
+	var logBuf bytes.Buffer
+	logger := util.NewLogger(config.LoggerConfig{Level: "info"})
+	logger.SetOutput(&logBuf)
+	logger.SetFormatter(&logrus.TextFormatter{
+		DisableColors:    true,
+		DisableTimestamp: true,
+	})
+
+	apiInstance := New(&config.Config{}, logger)
+	if err := apiInstance.RegisterAction(&testLogAction{
+		BaseAction:  BaseAction{ActionName: "test:error"},
+		shouldError: true,
+	}); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	var reported string
+	apiInstance.RegisterErrorReporter(func(_ context.Context, report ErrorReport) {
+		reported = report.RequestID
+	})
+
+	conn := NewConnection("http", "127.0.0.1", "test-id", nil)
+
+	// A request ID already in the context is reused
+	ctx := util.WithRequestID(context.Background(), "req-from-caller")
+	result := conn.Act(ctx, apiInstance, "test:error", nil, "GET", "")
+	if result.RequestID != "req-from-caller" {
+		t.Errorf("Expected request ID 'req-from-caller', got %q", result.RequestID)
+	}
+	if !strings.Contains(logBuf.String(), "requestId=req-from-caller") {
+		t.Errorf("Expected request ID in action log, got: %s", logBuf.String())
+	}
+	if reported != "req-from-caller" {
+		t.Errorf("Expected request ID in error report, got %q", reported)
+	}
+
+	// Otherwise a new one is generated
+	result = conn.Act(context.Background(), apiInstance, "test:error", nil, "GET", "")
+	if result.RequestID == "" || result.RequestID == "req-from-caller" {
+		t.Errorf("Expected a new request ID, got %q", result.RequestID)
+	}
+}
+
+func TestConnection_Act_Context(t *testing.T) {
+	apiInstance := New(&config.Config{}, util.NewLogger(config.LoggerConfig{Level: "error"}))
+	var ctx context.Context
+	if err := apiInstance.RegisterAction(NewAction("test:context").Handler(
+		func(actionCtx context.Context, _ interface{}, _ *Connection) (interface{}, error) {
+			ctx = actionCtx
+			return nil, nil
+		})); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	conn := NewConnection("cli", "cli", "test-id", nil)
+	conn.SetLocales("fr")
+	result := conn.Act(context.Background(), apiInstance, "test:context", nil, "CLI", "")
+	if result.Error != nil {
+		t.Fatalf("Action failed: %v", result.Error)
+	}
+
+	if APIFromContext(ctx) != apiInstance || ConfigFromContext(ctx) != apiInstance.Config {
+		t.Error("Expected the API and its config in the action's context")
+	}
+	if ConnectionFromContext(ctx) != conn {
+		t.Error("Expected the connection in the action's context")
+	}
+	if util.RequestIDFromContext(ctx) != result.RequestID {
+		t.Errorf("Expected request ID %q in the action's context", result.RequestID)
+	}
+	if locales := LocalesFromContext(ctx); len(locales) == 0 || locales[0] != "fr" {
+		t.Errorf("Expected the negotiated locales in the action's context, got %v", locales)
+	}
+	if ConnectionFromContext(context.Background()) != nil {
+		t.Error("Expected no connection in other contexts")
+	}
+}
+
+func TestConnection_Act_MaxResponseSize(t *testing.T) {
+	apiInstance := New(&config.Config{}, util.NewLogger(config.LoggerConfig{Level: "error"}))
+	respond := func(response interface{}) ActionFunc {
+		return func(context.Context, interface{}, *Connection) (interface{}, error) { return response, nil }
+	}
+	for _, action := range []Action{
+		NewAction("test:small").MaxResponseSize(config.Kilobyte).Handler(respond(map[string]string{"ok": "yes"})),
+		NewAction("test:large").MaxResponseSize(config.Kilobyte).Handler(respond(strings.Repeat("x", 2000))),
+		NewAction("test:raw").MaxResponseSize(config.Kilobyte).Handler(respond(&RawResponse{Body: make([]byte, 1001)})),
+	} {
+		if err := apiInstance.RegisterAction(action); err != nil {
+			t.Fatalf("Failed to register action: %v", err)
+		}
+	}
+
+	var reported []string
+	apiInstance.RegisterErrorReporter(func(_ context.Context, report ErrorReport) {
+		reported = append(reported, report.Action)
+	})
+
+	conn := NewConnection("test", "127.0.0.1", "test-id", nil)
+	if result := conn.Act(context.Background(), apiInstance, "test:small", nil, "GET", ""); result.Error != nil {
+		t.Errorf("Expected a small response to be sent, got %v", result.Error)
+	}
+	for _, name := range []string{"test:large", "test:raw"} {
+		result := conn.Act(context.Background(), apiInstance, name, nil, "GET", "")
+		var typedErr *util.TypedError
+		if result.Response != nil || !errors.As(result.Error, &typedErr) || typedErr.Type != util.ErrorTypeConnectionActionResponseTooLarge {
+			t.Errorf("Expected %s to fail with RESPONSE_TOO_LARGE, got %v", name, result.Error)
+		}
+	}
+	if len(reported) != 2 {
+		t.Errorf("Expected the oversized responses to be reported, got %v", reported)
+	}
+
+	metrics := apiInstance.Metrics.Snapshot()
+	if small := metrics["test:small"]; small.MaxResponseBytes != int64(len(`{"ok":"yes"}`)) || small.ResponsesTooLarge != 0 {
+		t.Errorf("Expected the small response's size, got %+v", small)
+	}
+	if large := metrics["test:large"]; large.MaxResponseBytes != 2002 || large.ResponsesTooLarge != 1 || large.Errors != 1 {
+		t.Errorf("Expected the large response counted as too large, got %+v", large)
+	}
+}
+
+// stubMiddleware returns fixed results from its hooks, recording the calls
+type stubMiddleware struct {
+	before, after *MiddlewareResponse
+	calls         *[]string
+	name          string
+}
+
+func (m stubMiddleware) RunBefore(_ interface{}, _ *Connection) (*MiddlewareResponse, error) {
+	*m.calls = append(*m.calls, m.name+":before")
+	return m.before, nil
+}
+
+func (m stubMiddleware) RunAfter(_ interface{}, _ *Connection) (*MiddlewareResponse, error) {
+	*m.calls = append(*m.calls, m.name+":after")
+	return m.after, nil
+}
+
+func TestConnection_Act_Middleware(t *testing.T) {
+	apiInstance := New(&config.Config{}, util.NewLogger(config.LoggerConfig{Level: "error"}))
+	var calls []string
+	echo := func(_ context.Context, params interface{}, _ *Connection) (interface{}, error) {
+		calls = append(calls, "action")
+		return params.(map[string]interface{})["user"], nil
+	}
+
+	auth := stubMiddleware{name: "auth", calls: &calls, before: &MiddlewareResponse{
+		UpdatedParams: map[string]interface{}{"user": "evan"},
+	}}
+	wrap := stubMiddleware{name: "wrap", calls: &calls, after: &MiddlewareResponse{
+		UpdatedResponse: map[string]string{"wrapped": "yes"},
+	}}
+	answer := stubMiddleware{name: "answer", calls: &calls, before: &MiddlewareResponse{
+		UpdatedResponse: "from middleware",
+	}}
+	if err := apiInstance.RegisterAction(NewAction("test:updated").Middleware(auth).Handler(echo)); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	if err := apiInstance.RegisterAction(NewAction("test:wrapped").Middleware(auth, wrap).Handler(echo)); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	if err := apiInstance.RegisterAction(NewAction("test:answered").Middleware(answer, wrap).Handler(echo)); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	conn := NewConnection("test", "127.0.0.1", "test-id", nil)
+
+	result := conn.Act(context.Background(), apiInstance, "test:updated", map[string]interface{}{"user": "anonymous"}, "GET", "")
+	if result.Error != nil || result.Response != "evan" {
+		t.Errorf("Expected the action to get the middleware's params, got %v %v", result.Response, result.Error)
+	}
+
+	calls = nil
+	result = conn.Act(context.Background(), apiInstance, "test:wrapped", nil, "GET", "")
+	if !reflect.DeepEqual(result.Response, map[string]string{"wrapped": "yes"}) {
+		t.Errorf("Expected RunAfter to replace the response, got %v", result.Response)
+	}
+	if expected := []string{"auth:before", "wrap:before", "action", "auth:after", "wrap:after"}; !reflect.DeepEqual(calls, expected) {
+		t.Errorf("Expected calls %v, got %v", expected, calls)
+	}
+
+	calls = nil
+	result = conn.Act(context.Background(), apiInstance, "test:answered", nil, "GET", "")
+	if result.Error != nil || result.Response != "from middleware" {
+		t.Errorf("Expected the middleware's response, got %v %v", result.Response, result.Error)
+	}
+	if expected := []string{"answer:before"}; !reflect.DeepEqual(calls, expected) {
+		t.Errorf("Expected the action and later middleware to be skipped, got %v", calls)
+	}
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// DryRunHeader is the request header that asks for a dry run (e.g.,
+// "X-Dry-Run: true"). On a WebSocket upgrade, it applies to the whole
+// connection.
+const DryRunHeader = "X-Dry-Run"
+
+// ContextKeyDryRun carries whether an action runs as a dry run
+const ContextKeyDryRun ContextKey = "dryRun"
+
+// DryRunResponse is what a mutating action responds with in a dry run,
+// instead of running: what would have happened
+type DryRunResponse struct {
+	DryRun bool                   `json:"dryRun"`
+	Action string                 `json:"action"`
+	Method string                 `json:"method,omitempty"` // HTTP method of the action's route
+	Route  string                 `json:"route,omitempty"`
+	Params map[string]interface{} `json:"params"`
+}
+
+// ParseDryRun returns whether value (e.g., of the X-Dry-Run header) asks for
+// a dry run: "1", "true", "yes", or "on"
+func ParseDryRun(value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if on, err := strconv.ParseBool(value); err == nil {
+		return on
+	}
+	return value == "yes" || value == "on"
+}
+
+// WithDryRun returns a copy of ctx that runs actions as a dry run when
+// dryRun is set
+func WithDryRun(ctx context.Context, dryRun bool) context.Context {
+	return context.WithValue(ctx, ContextKeyDryRun, dryRun)
+}
+
+// IsDryRun returns whether the action whose context is ctx runs as a dry
+// run, and so must not change anything
+func IsDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(ContextKeyDryRun).(bool)
+	return dryRun
+}
+
+// Mutating returns whether the action may change something, and so isn't run
+// in a dry run unless it handles dry runs itself (see BaseAction.ActionDryRun).
+// Only actions served over GET are known not to.
+func (d *ActionDescriptor) Mutating() bool {
+	return d.Web == nil || d.Web.Method != HTTPMethodGET
+}
+
+// dryRunResponse describes running the action with params
+func (d *ActionDescriptor) dryRunResponse(params map[string]interface{}) *DryRunResponse {
+	response := &DryRunResponse{DryRun: true, Action: d.Name, Params: params}
+	if d.Web != nil {
+		response.Method = string(d.Web.Method)
+		response.Route = d.Web.Route
+	}
+	if response.Params == nil {
+		response.Params = map[string]interface{}{}
+	}
+	return response
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+func TestParseDryRun(t *testing.T) {
+	for value, want := range map[string]bool{
+		"":      false,
+		"1":     true,
+		"true":  true,
+		"TRUE":  true,
+		" yes ": true,
+		"on":    true,
+		"0":     false,
+		"false": false,
+		"no":    false,
+		"maybe": false,
+	} {
+		if got := ParseDryRun(value); got != want {
+			t.Errorf("ParseDryRun(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestConnection_Act_DryRun(t *testing.T) {
+	apiInstance := New(&config.Config{}, util.NewLogger(config.LoggerConfig{Level: "error"}))
+	runs := make(map[string]int)
+	var sawDryRun bool
+	handler := func(name string) ActionFunc {
+		return func(ctx context.Context, _ interface{}, _ *Connection) (interface{}, error) {
+			runs[name]++
+			sawDryRun = IsDryRun(ctx)
+			return "ran", nil
+		}
+	}
+	for _, action := range []Action{
+		NewAction("test:create").Post("/things").Handler(handler("test:create")),
+		NewAction("test:list").Get("/things").Handler(handler("test:list")),
+		NewAction("test:internal").Handler(handler("test:internal")),
+		NewAction("test:import").Post("/imports").DryRun().Handler(handler("test:import")),
+	} {
+		if err := apiInstance.RegisterAction(action); err != nil {
+			t.Fatalf("Failed to register action: %v", err)
+		}
+	}
+
+	conn := NewConnection("cli", "cli", "test-id", nil)
+	conn.SetDryRun(true)
+
+	t.Run("mutating actions are described, not run", func(t *testing.T) {
+		for _, name := range []string{"test:create", "test:internal"} {
+			result := conn.Act(context.Background(), apiInstance, name, map[string]interface{}{"name": "x"}, "CLI", "")
+			if result.Error != nil {
+				t.Fatalf("Expected a dry run of %s to succeed, got %v", name, result.Error)
+			}
+			response, ok := result.Response.(*DryRunResponse)
+			if !ok || !response.DryRun || response.Action != name || response.Params["name"] != "x" {
+				t.Errorf("Expected a dry run response for %s, got %#v", name, result.Response)
+			}
+			if runs[name] != 0 {
+				t.Errorf("Expected %s not to run", name)
+			}
+		}
+
+		result := conn.Act(context.Background(), apiInstance, "test:create", nil, "CLI", "")
+		if response := result.Response.(*DryRunResponse); response.Method != "POST" || response.Route != "/things" || response.Params == nil {
+			t.Errorf("Expected the action's route and empty params, got %#v", response)
+		}
+	})
+
+	t.Run("read-only actions run", func(t *testing.T) {
+		result := conn.Act(context.Background(), apiInstance, "test:list", nil, "CLI", "")
+		if result.Response != "ran" || runs["test:list"] != 1 || !sawDryRun {
+			t.Errorf("Expected test:list to run as a dry run, got %v", result.Response)
+		}
+	})
+
+	t.Run("actions that handle dry runs run", func(t *testing.T) {
+		result := conn.Act(context.Background(), apiInstance, "test:import", nil, "CLI", "")
+		if result.Response != "ran" || runs["test:import"] != 1 || !sawDryRun {
+			t.Errorf("Expected test:import to run as a dry run, got %v", result.Response)
+		}
+	})
+
+	t.Run("without a dry run", func(t *testing.T) {
+		conn := NewConnection("cli", "cli", "test-id-2", nil)
+		result := conn.Act(context.Background(), apiInstance, "test:create", nil, "CLI", "")
+		if result.Response != "ran" || runs["test:create"] != 1 || sawDryRun {
+			t.Errorf("Expected test:create to run normally, got %v", result.Response)
+		}
+
+		result = conn.Act(WithDryRun(context.Background(), true), apiInstance, "test:create", nil, "CLI", "")
+		if _, ok := result.Response.(*DryRunResponse); !ok {
+			t.Errorf("Expected a dry run from the context, got %v", result.Response)
+		}
+	})
+}
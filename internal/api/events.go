@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+)
+
+// Framework events that handlers can subscribe to with On
+const (
+	EventInitialize      = "initialize"       // All initializers and servers have been initialized
+	EventStart           = "start"            // All initializers and servers have started
+	EventStop            = "stop"             // All servers and initializers have stopped
+	EventActionComplete  = "action:complete"  // An action ran successfully
+	EventActionError     = "action:error"     // An action returned an error or panicked
+	EventConnectionOpen  = "connection:open"  // A long-lived (e.g. WebSocket) connection opened
+	EventConnectionClose = "connection:close" // A long-lived (e.g. WebSocket) connection closed
+	EventConnectionIdle  = "connection:idle"  // An idle connection is being closed (connection:close follows)
+	EventSessionExpire   = "session:expire"   // A connection's session outlived session.ttl and was removed
+	EventConfigReloaded  = "config:reloaded"  // Config files changed and reloadable settings were applied
+	EventTaskEnqueued    = "task:enqueued"    // A task was queued (see Tasks.Enqueue)
+	EventTaskComplete    = "task:complete"    // A task worker ran a task successfully
+	EventTaskError       = "task:error"       // A task failed (it is recorded on the failed list)
+)
+
+// Event is passed to event handlers. Fields that don't apply to an event are left empty.
+type Event struct {
+	Name       string
+	Action     string
+	Connection *Connection // HTTP connections are reused once the request completes: read it, don't keep it
+	Params     map[string]interface{}
+	Response   interface{}
+	Error      error
+	Duration   time.Duration
+	Changes    []config.Change // Settings that changed (config:reloaded)
+	Boot       *BootReport     // What started and how long it took (start)
+}
+
+// EventHandler handles a framework event
+type EventHandler func(ctx context.Context, event Event)
+
+// On registers a handler for the named event. Handlers run synchronously,
+// in registration order, on the goroutine that emitted the event.
+func (a *API) On(name string, handler EventHandler) {
+	a.eventsMu.Lock()
+	defer a.eventsMu.Unlock()
+	a.events[name] = append(a.events[name], handler)
+}
+
+// Emit calls every handler registered for event.Name
+func (a *API) Emit(ctx context.Context, event Event) {
+	a.eventsMu.RLock()
+	handlers := make([]EventHandler, len(a.events[event.Name]))
+	copy(handlers, a.events[event.Name])
+	a.eventsMu.RUnlock()
+
+	for _, handler := range handlers {
+		a.callEventHandler(ctx, handler, event)
+	}
+}
+
+// callEventHandler runs a single handler, so a panicking handler can't take down the emitter
+func (a *API) callEventHandler(ctx context.Context, handler EventHandler, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			a.Logger.Errorf("Event handler for %s panicked: %v\n%s", event.Name, r, debug.Stack())
+		}
+	}()
+	handler(ctx, event)
+}
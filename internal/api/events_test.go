@@ -0,0 +1,189 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+func TestEvents_Lifecycle(t *testing.T) {
+	api := New(&config.Config{}, util.NewLogger(config.LoggerConfig{Level: "fatal"}))
+	api.RegisterServer(&mockServer{name: "test-server"})
+
+	received := make([]string, 0)
+	for _, name := range []string{EventInitialize, EventStart, EventStop} {
+		api.On(name, func(_ context.Context, event Event) {
+			received = append(received, event.Name)
+		})
+	}
+
+	if err := api.Initialize(); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	if err := api.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := api.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	expected := []string{EventInitialize, EventStart, EventStop}
+	if len(received) != len(expected) {
+		t.Fatalf("Expected events %v, got %v", expected, received)
+	}
+	for i := range expected {
+		if received[i] != expected[i] {
+			t.Errorf("Expected event %d to be %s, got %s", i, expected[i], received[i])
+		}
+	}
+}
+
+func TestEvents_Actions(t *testing.T) {
+	api, _ := setupReporterTest(t, func() (interface{}, error) {
+		return "ok", nil
+	})
+	if err := api.RegisterAction(&reporterTestAction{
+		BaseAction: BaseAction{ActionName: "test:fail"},
+		run:        func() (interface{}, error) { return nil, errors.New("failed") },
+	}); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	var completed, failed []Event
+	api.On(EventActionComplete, func(_ context.Context, event Event) {
+		completed = append(completed, event)
+	})
+	api.On(EventActionError, func(_ context.Context, event Event) {
+		failed = append(failed, event)
+	})
+
+	conn := NewConnection("web", "127.0.0.1", "conn-id", nil)
+	conn.Act(context.Background(), api, "test:report", map[string]interface{}{"a": 1}, "GET", "")
+	conn.Act(context.Background(), api, "test:fail", nil, "GET", "")
+	conn.Act(context.Background(), api, "missing", nil, "GET", "")
+
+	if len(completed) != 1 {
+		t.Fatalf("Expected 1 action:complete event, got %d", len(completed))
+	}
+	if completed[0].Action != "test:report" || completed[0].Response != "ok" || completed[0].Connection != conn {
+		t.Errorf("Unexpected action:complete event: %+v", completed[0])
+	}
+	if completed[0].Params["a"] != 1 {
+		t.Errorf("Expected params on event, got %v", completed[0].Params)
+	}
+
+	if len(failed) != 1 {
+		t.Fatalf("Expected 1 action:error event, got %d", len(failed))
+	}
+	if failed[0].Action != "test:fail" || failed[0].Error == nil {
+		t.Errorf("Unexpected action:error event: %+v", failed[0])
+	}
+}
+
+func TestEvents_HandlerPanicRecovered(t *testing.T) {
+	api := New(&config.Config{}, util.NewLogger(config.LoggerConfig{Level: "fatal"}))
+
+	called := false
+	api.On("custom", func(_ context.Context, _ Event) { panic("boom") })
+	api.On("custom", func(_ context.Context, _ Event) { called = true })
+
+	api.Emit(context.Background(), Event{Name: "custom"})
+
+	if !called {
+		t.Error("Expected later handlers to run after a handler panics")
+	}
+}
+
+func TestReloadConfig(t *testing.T) {
+	cfg := &config.Config{
+		Logger: config.LoggerConfig{Level: "info"},
+		Server: config.ServerConfig{Web: config.WebServerConfig{Port: 8080}},
+	}
+	api := New(cfg, util.NewLogger(cfg.Logger))
+
+	var reloaded []config.Change
+	api.On(EventConfigReloaded, func(_ context.Context, event Event) {
+		reloaded = event.Changes
+	})
+
+	next := *cfg
+	next.Logger.Level = "debug"
+	next.Server.Web.Port = 9090
+
+	changes := api.ReloadConfig(&next)
+	if len(changes) != 2 || len(reloaded) != 2 {
+		t.Fatalf("Expected 2 changes returned and emitted, got %v and %v", changes, reloaded)
+	}
+
+	if level := api.CurrentConfig().Logger.Level; level != "debug" {
+		t.Errorf("Expected logger level to be applied, got %s", level)
+	}
+	if api.Config.Logger.Level != "info" {
+		t.Errorf("Expected the original config to be left unchanged, got %s", api.Config.Logger.Level)
+	}
+	if api.Logger.GetLevel().String() != "debug" {
+		t.Errorf("Expected logger to be at debug level, got %s", api.Logger.GetLevel())
+	}
+	if port := api.CurrentConfig().Server.Web.Port; port != 8080 {
+		t.Errorf("Expected port to require a restart, got %d", port)
+	}
+
+	// Nothing changed, nothing emitted
+	reloaded = nil
+	if changes := api.ReloadConfig(api.CurrentConfig()); changes != nil || reloaded != nil {
+		t.Errorf("Expected no changes, got %v", changes)
+	}
+}
+
+func TestReloadConfig_ConcurrentAct(t *testing.T) {
+	cfg := &config.Config{
+		Logger:  config.LoggerConfig{Level: "error", SlowAction: time.Hour},
+		Process: config.ProcessConfig{ActionTimeout: time.Minute, ErrorDisclosure: config.ErrorDisclosureStandard},
+	}
+	api := New(cfg, util.NewLogger(cfg.Logger))
+	if err := api.RegisterAction(NewAction("test:fail").Handler(func(context.Context, interface{}, *Connection) (interface{}, error) {
+		return nil, errors.New("boom")
+	})); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		conn := NewConnection("test", "127.0.0.1", "test-id", nil)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			result := conn.Act(context.Background(), api, "test:fail", nil, "GET", "")
+			api.ErrorJSON(result.Error, result.RequestID)
+		}
+	}()
+
+	for i := 0; i < 200; i++ {
+		next := *api.CurrentConfig()
+		next.Logger.SlowAction = time.Duration(i+1) * time.Second
+		next.Process.ActionTimeout = time.Duration(i+1) * time.Second
+		if i%2 == 0 {
+			next.Process.ErrorDisclosure = config.ErrorDisclosureDebug
+		} else {
+			next.Process.ErrorDisclosure = config.ErrorDisclosureStandard
+		}
+		api.ReloadConfig(&next)
+	}
+	close(done)
+	wg.Wait()
+
+	if got := api.CurrentConfig().Process.ActionTimeout; got != 200*time.Second {
+		t.Errorf("Expected the last reload to apply, got %v", got)
+	}
+}
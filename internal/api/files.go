@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrStorageDisabled is returned by the API's Files until a store is set
+// (e.g., by the storage initializer, when storage.enabled is set)
+var ErrStorageDisabled = errors.New("file storage is not enabled (storage.enabled)")
+
+// ErrFileNotFound is returned when getting a file that is not stored
+var ErrFileNotFound = errors.New("file not found")
+
+// Files stores files by key, on local disk or in an S3-compatible bucket.
+// Keys are slash-separated relative paths (e.g., "avatars/42.png").
+//
+//	err := api.APIFromContext(ctx).Files.Put(ctx, "avatars/42.png", upload, "image/png")
+type Files interface {
+	// Put stores body under key, replacing any file already there
+	Put(ctx context.Context, key string, body io.Reader, contentType string) error
+
+	// Get opens the file stored under key (ErrFileNotFound when there is
+	// none); the caller closes it
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the file stored under key, if there is one
+	Delete(ctx context.Context, key string) error
+
+	// SignedURL returns a URL anyone can download the file from until
+	// expires has passed
+	SignedURL(ctx context.Context, key string, expires time.Duration) (string, error)
+}
+
+// disabledFiles is the API's Files until a store is set
+type disabledFiles struct{}
+
+// Put returns ErrStorageDisabled
+func (disabledFiles) Put(_ context.Context, _ string, _ io.Reader, _ string) error {
+	return ErrStorageDisabled
+}
+
+// Get returns ErrStorageDisabled
+func (disabledFiles) Get(_ context.Context, _ string) (io.ReadCloser, error) {
+	return nil, ErrStorageDisabled
+}
+
+// Delete returns ErrStorageDisabled
+func (disabledFiles) Delete(_ context.Context, _ string) error {
+	return ErrStorageDisabled
+}
+
+// SignedURL returns ErrStorageDisabled
+func (disabledFiles) SignedURL(_ context.Context, _ string, _ time.Duration) (string, error) {
+	return "", ErrStorageDisabled
+}
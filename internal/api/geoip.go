@@ -0,0 +1,43 @@
+package api
+
+// GeoLocation is where a client IP is, as found in the GeoIP database
+// (see geoip.enabled). Fields the database doesn't have are empty.
+type GeoLocation struct {
+	Country     string `json:"country,omitempty"`     // ISO 3166-1 country code, e.g. "GB"
+	CountryName string `json:"countryName,omitempty"` // English country name, e.g. "United Kingdom"
+	Region      string `json:"region,omitempty"`      // ISO 3166-2 subdivision code, e.g. "ENG"
+	RegionName  string `json:"regionName,omitempty"`  // English subdivision name, e.g. "England"
+	City        string `json:"city,omitempty"`        // English city name
+}
+
+// IsZero returns whether nothing is known about the location
+func (l GeoLocation) IsZero() bool {
+	return l == GeoLocation{}
+}
+
+// GeoLocator finds where client IPs are. The API's GeoIP finds nothing until
+// a locator is set (e.g., by the geoip initializer, when geoip.enabled is set).
+type GeoLocator interface {
+	// Locate returns where ip is, and whether it was found
+	Locate(ip string) (GeoLocation, bool)
+}
+
+// disabledGeoLocator is the API's GeoIP until a locator is set
+type disabledGeoLocator struct{}
+
+// Locate finds nothing
+func (disabledGeoLocator) Locate(_ string) (GeoLocation, bool) {
+	return GeoLocation{}, false
+}
+
+// LocateClient returns info with the Location of its RemoteIP, when the
+// API's GeoIP finds it
+func (a *API) LocateClient(info ClientInfo) ClientInfo {
+	if a.GeoIP == nil || info.RemoteIP == "" {
+		return info
+	}
+	if location, ok := a.GeoIP.Locate(info.RemoteIP); ok {
+		info.Location = location
+	}
+	return info
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/httpclient"
+)
+
+// ErrCircuitOpen is wrapped by the HTTPClient's errors for requests to a host
+// that failed too many requests in a row, until httpclient.breakercooldown
+// passes. It is ErrBreakerOpen: the hosts' breakers are among the API's
+// breakers, as "http:<host>".
+var ErrCircuitOpen = httpclient.ErrCircuitOpen
+
+// HTTPClient returns the client actions use to call other services. It is
+// configured by the httpclient section: requests time out, idempotent ones
+// are retried with backoff, hosts that keep failing are cut off by a circuit
+// breaker, the action's request ID is sent as X-Request-ID, and each request's
+// latency is recorded in HTTPMetrics by method and host. Make requests with
+// the action's context, so they carry its request ID and are canceled with it:
+//
+//	req, _ := http.NewRequestWithContext(ctx, "GET", "https://api.example.com/v1/rates", nil)
+//	resp, err := api.APIFromContext(ctx).HTTPClient().Do(req)
+func (a *API) HTTPClient() *http.Client {
+	a.httpClientOnce.Do(func() {
+		cfg := config.DefaultHTTPClientConfig()
+		if a.Config != nil {
+			cfg = a.Config.HTTPClient
+		}
+		var metrics httpclient.Recorder
+		if a.HTTPMetrics != nil {
+			metrics = a.HTTPMetrics
+		}
+		a.httpClient = httpclient.New(cfg, metrics, a.breakerRegistry(), a.Logger)
+	})
+	return a.httpClient
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+func TestAPI_HTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{HTTPClient: config.DefaultHTTPClientConfig()}
+	apiInstance := New(cfg, util.NewLogger(config.DefaultLoggerConfig()))
+
+	client := apiInstance.HTTPClient()
+	if client != apiInstance.HTTPClient() {
+		t.Error("Expected the same client every time")
+	}
+	if client.Timeout != cfg.HTTPClient.Timeout {
+		t.Errorf("Expected timeout %s, got %s", cfg.HTTPClient.Timeout, client.Timeout)
+	}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	name := "GET " + strings.TrimPrefix(server.URL, "http://")
+	if metrics, ok := apiInstance.HTTPMetrics.Snapshot()[name]; !ok || metrics.Count != 1 {
+		t.Errorf("Expected one request recorded as %q, got %v", name, apiInstance.HTTPMetrics.Snapshot())
+	}
+}
+
+func TestAPI_HTTPClientBreakers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{HTTPClient: config.DefaultHTTPClientConfig(), Breaker: config.DefaultBreakerConfig()}
+	cfg.HTTPClient.Retries = 0
+	cfg.HTTPClient.BreakerThreshold = 1
+	apiInstance := New(cfg, util.NewLogger(config.LoggerConfig{Level: "error"}))
+
+	resp, err := apiInstance.HTTPClient().Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	name := "http:" + strings.TrimPrefix(server.URL, "http://")
+	stats := apiInstance.BreakerStats()
+	if len(stats) != 1 || stats[0].Name != name || stats[0].State != BreakerOpen {
+		t.Errorf("Expected the host's open breaker among the API's breakers, got %+v", stats)
+	}
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/evantahler/go-actionhero/internal/i18n"
+)
+
+// WithLocales returns a copy of ctx carrying the locales to translate into,
+// most preferred first
+func WithLocales(ctx context.Context, locales []string) context.Context {
+	return context.WithValue(ctx, ContextKeyLocales, locales)
+}
+
+// LocalesFromContext returns the locales an action is running with (see
+// NegotiateLocales)
+func LocalesFromContext(ctx context.Context) []string {
+	locales, _ := ctx.Value(ContextKeyLocales).([]string)
+	return locales
+}
+
+// NegotiateLocales returns the locales to translate into, most preferred
+// first: the locale param (i18n.localeparam, e.g. "fr" or an Accept-Language
+// list) when params has one, then preferred (e.g., from the connection's
+// Accept-Language header), then i18n.defaultlocale
+func (a *API) NegotiateLocales(params map[string]interface{}, preferred []string) []string {
+	var param, fallback string
+	if a.Config != nil {
+		param, fallback = a.Config.I18n.LocaleParam, a.Config.I18n.DefaultLocale
+	}
+
+	var locales []string
+	if value, ok := params[param].(string); ok && param != "" && strings.TrimSpace(value) != "" {
+		locales = append(locales, i18n.ParseAcceptLanguage(value)...)
+	}
+	locales = append(locales, preferred...)
+	if fallback != "" {
+		locales = append(locales, fallback)
+	}
+
+	// Drop repeats, keeping each locale's most preferred position
+	seen := make(map[string]bool, len(locales))
+	unique := locales[:0]
+	for _, locale := range locales {
+		if !seen[locale] {
+			seen[locale] = true
+			unique = append(unique, locale)
+		}
+	}
+	return unique
+}
+
+// T translates key into the locales of the action running with ctx, using
+// the API's Messages catalog. Each {name} in the message is replaced with
+// args[name]. Untranslated keys are returned as-is, so they stand out:
+//
+//	greeting := api.T(ctx, "greeting", map[string]interface{}{"name": user.Name})
+func T(ctx context.Context, key string, args map[string]interface{}) string {
+	a := APIFromContext(ctx)
+	if a == nil || a.Messages == nil {
+		return key
+	}
+	vars := make(map[string]string, len(args))
+	for name, value := range args {
+		vars[name] = fmt.Sprint(value)
+	}
+	if message, ok := a.Messages.Translate(LocalesFromContext(ctx), key, vars); ok {
+		return message
+	}
+	return key
+}
+
+// loadMessages adds the catalogs in i18n.directory to Messages, skipping a
+// missing directory
+func (a *API) loadMessages() error {
+	if a.Config == nil || a.Config.I18n.Directory == "" || a.Messages == nil {
+		return nil
+	}
+	dir := a.Config.I18n.Directory
+	loaded, err := a.Messages.LoadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load message catalogs: %w", err)
+	}
+	a.Logger.Infof("Loaded %d message catalogs from %s", loaded, dir)
+	return nil
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// newI18nAPI creates an API with the default i18n config and French and
+// English messages
+func newI18nAPI() *API {
+	a := New(&config.Config{I18n: config.DefaultI18nConfig()}, util.NewLogger(config.LoggerConfig{Level: "fatal"}))
+	a.Messages.Add("fr", map[string]string{
+		"greeting": "Bonjour {name}",
+		string(util.ErrorTypeConnectionActionParamRequired): "Le paramètre {key} est requis",
+	})
+	a.Messages.Add("en", map[string]string{"greeting": "Hello {name}"})
+	return a
+}
+
+func TestAPI_NegotiateLocales(t *testing.T) {
+	a := newI18nAPI()
+
+	tests := []struct {
+		name      string
+		params    map[string]interface{}
+		preferred []string
+		want      []string
+	}{
+		{"default locale only", nil, nil, []string{"en"}},
+		{"preferred first", nil, []string{"de-CH", "de"}, []string{"de-CH", "de", "en"}},
+		{"locale param first", map[string]interface{}{"locale": "fr"}, []string{"de"}, []string{"fr", "de", "en"}},
+		{"locale param list", map[string]interface{}{"locale": "pt-BR, fr;q=0.5"}, nil, []string{"pt-BR", "fr", "en"}},
+		{"no repeats", map[string]interface{}{"locale": "en"}, []string{"fr", "en"}, []string{"en", "fr"}},
+		{"non-string param ignored", map[string]interface{}{"locale": 1}, nil, []string{"en"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := a.NegotiateLocales(tt.params, tt.preferred); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	a.Config.I18n.LocaleParam = ""
+	a.Config.I18n.DefaultLocale = ""
+	if got := a.NegotiateLocales(map[string]interface{}{"locale": "fr"}, nil); len(got) != 0 {
+		t.Errorf("Expected no locales with the param and default disabled, got %v", got)
+	}
+}
+
+func TestT(t *testing.T) {
+	a := newI18nAPI()
+	if err := a.RegisterAction(NewAction("greet").Handler(func(ctx context.Context, _ interface{}, _ *Connection) (interface{}, error) {
+		return T(ctx, "greeting", map[string]interface{}{"name": "Mario"}), nil
+	})); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	conn := NewConnection("test", "127.0.0.1", "conn-1", nil)
+	if result := conn.Act(context.Background(), a, "greet", nil, "", ""); result.Response != "Hello Mario" {
+		t.Errorf("Expected the default locale, got %v", result.Response)
+	}
+	conn.SetLocales("fr-CA")
+	if result := conn.Act(context.Background(), a, "greet", nil, "", ""); result.Response != "Bonjour Mario" {
+		t.Errorf("Expected the connection's locale, got %v", result.Response)
+	}
+	conn.SetParam("locale", "en")
+	if result := conn.Act(context.Background(), a, "greet", nil, "", ""); result.Response != "Hello Mario" {
+		t.Errorf("Expected the sticky locale param, got %v", result.Response)
+	}
+	if result := conn.Act(context.Background(), a, "greet", map[string]interface{}{"locale": "fr"}, "", ""); result.Response != "Bonjour Mario" {
+		t.Errorf("Expected the locale param, got %v", result.Response)
+	}
+
+	if got := T(context.Background(), "greeting", nil); got != "greeting" {
+		t.Errorf("Expected the key outside an action, got %q", got)
+	}
+	ctx := WithLocales(context.WithValue(context.Background(), ContextKeyAPI, a), []string{"fr"})
+	if got := T(ctx, "farewell", nil); got != "farewell" {
+		t.Errorf("Expected an untranslated key as-is, got %q", got)
+	}
+}
+
+func TestAct_ErrorLocales(t *testing.T) {
+	a := newI18nAPI()
+	if err := a.RegisterAction(NewAction("fail").Handler(func(_ context.Context, _ interface{}, _ *Connection) (interface{}, error) {
+		return nil, util.NewTypedError(util.ErrorTypeConnectionActionParamRequired, "email is required", util.WithKey("email"))
+	})); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	conn := NewConnection("test", "127.0.0.1", "conn-1", nil)
+	result := conn.Act(context.Background(), a, "fail", map[string]interface{}{"locale": "fr"}, "", "")
+	if body := a.ErrorJSON(result.Error, result.RequestID, result.Locales...); body.Message != "Le paramètre email est requis" {
+		t.Errorf("Expected the error in the locale param's language, got %q", body.Message)
+	}
+}
+
+func TestAPI_LoadMessages(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "es.yaml"), []byte("greeting: Hola {name}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	a := newI18nAPI()
+	a.Config.I18n.Directory = dir
+	if err := a.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	if got, _ := a.Messages.Translate([]string{"es"}, "greeting", map[string]string{"name": "Ana"}); got != "Hola Ana" {
+		t.Errorf("Expected the loaded catalog, got %q", got)
+	}
+
+	a = newI18nAPI()
+	a.Config.I18n.Directory = filepath.Join(dir, "missing")
+	if err := a.Initialize(); err != nil {
+		t.Errorf("Expected a missing directory to be skipped, got %v", err)
+	}
+}
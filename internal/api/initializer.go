@@ -0,0 +1,108 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// InitializerDependencies is implemented by initializers that must run after
+// others. Dependencies are initializer names; an initializer is initialized
+// and started after everything it depends on, and stopped before them.
+type InitializerDependencies interface {
+	// DependsOn returns the names of the initializers this one needs
+	DependsOn() []string
+}
+
+// sortByPriority returns the initializers sorted by priority (lower first),
+// keeping registration order for equal priorities
+func sortByPriority(initializers []Initializer) []Initializer {
+	sort.SliceStable(initializers, func(i, j int) bool {
+		return initializers[i].Priority() < initializers[j].Priority()
+	})
+	return initializers
+}
+
+// orderInitializers topologically sorts initializers by their dependencies.
+// Among initializers whose dependencies are all satisfied, lower priorities
+// (then earlier registrations) run first.
+func orderInitializers(initializers []Initializer) ([]Initializer, error) {
+	initializers = sortByPriority(initializers)
+
+	byName := make(map[string]int, len(initializers))
+	for i, initializer := range initializers {
+		if _, exists := byName[initializer.Name()]; exists {
+			return nil, fmt.Errorf("initializer '%s' is registered more than once", initializer.Name())
+		}
+		byName[initializer.Name()] = i
+	}
+
+	// pending counts each initializer's unsatisfied dependencies; dependents
+	// lists who is waiting on each one
+	pending := make([]int, len(initializers))
+	dependents := make([][]int, len(initializers))
+	for i, initializer := range initializers {
+		deps, ok := initializer.(InitializerDependencies)
+		if !ok {
+			continue
+		}
+		for _, dep := range deps.DependsOn() {
+			j, exists := byName[dep]
+			if !exists {
+				return nil, fmt.Errorf("initializer '%s' depends on unknown initializer '%s'", initializer.Name(), dep)
+			}
+			pending[i]++
+			dependents[j] = append(dependents[j], i)
+		}
+	}
+
+	ordered := make([]Initializer, 0, len(initializers))
+	done := make([]bool, len(initializers))
+	for len(ordered) < len(initializers) {
+		// Take the first ready initializer in priority order
+		next := -1
+		for i := range initializers {
+			if !done[i] && pending[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			return nil, fmt.Errorf("initializer dependency cycle: %s", describeCycle(initializers, byName, done))
+		}
+
+		done[next] = true
+		ordered = append(ordered, initializers[next])
+		for _, dependent := range dependents[next] {
+			pending[dependent]--
+		}
+	}
+	return ordered, nil
+}
+
+// describeCycle follows unsatisfied dependencies from a remaining initializer
+// until one repeats, returning the cycle (e.g., "a -> b -> a")
+func describeCycle(initializers []Initializer, byName map[string]int, done []bool) string {
+	start := 0
+	for done[start] {
+		start++
+	}
+
+	var path []string
+	seen := make(map[int]int)
+	for current := start; ; {
+		if at, ok := seen[current]; ok {
+			return strings.Join(append(path[at:], initializers[current].Name()), " -> ")
+		}
+		seen[current] = len(path)
+		path = append(path, initializers[current].Name())
+
+		// Every remaining initializer waits on another remaining one
+		for _, dep := range initializers[current].(InitializerDependencies).DependsOn() {
+			if j := byName[dep]; !done[j] {
+				current = j
+				break
+			}
+		}
+	}
+}
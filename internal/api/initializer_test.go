@@ -0,0 +1,124 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// dependentInitializer is a mock initializer with dependencies
+type dependentInitializer struct {
+	mockInitializer
+	deps []string
+}
+
+func (d *dependentInitializer) DependsOn() []string { return d.deps }
+
+func initializerNames(initializers []Initializer) string {
+	names := make([]string, len(initializers))
+	for i, initializer := range initializers {
+		names[i] = initializer.Name()
+	}
+	return strings.Join(names, ",")
+}
+
+func TestOrderInitializers(t *testing.T) {
+	tests := []struct {
+		name         string
+		initializers []Initializer
+		expected     string
+	}{
+		{
+			name: "priority only",
+			initializers: []Initializer{
+				&mockInitializer{name: "b", priority: 10},
+				&mockInitializer{name: "a", priority: 5},
+				&mockInitializer{name: "c", priority: 10},
+			},
+			expected: "a,b,c",
+		},
+		{
+			name: "dependencies override priority",
+			initializers: []Initializer{
+				&dependentInitializer{mockInitializer: mockInitializer{name: "web", priority: 1}, deps: []string{"db", "cache"}},
+				&dependentInitializer{mockInitializer: mockInitializer{name: "cache", priority: 5}, deps: []string{"db"}},
+				&mockInitializer{name: "db", priority: 10},
+				&mockInitializer{name: "metrics", priority: 2},
+			},
+			expected: "metrics,db,cache,web",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ordered, err := orderInitializers(tt.initializers)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if got := initializerNames(ordered); got != tt.expected {
+				t.Errorf("Expected order %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestOrderInitializers_Errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		initializers []Initializer
+		expected     string
+	}{
+		{
+			name: "unknown dependency",
+			initializers: []Initializer{
+				&dependentInitializer{mockInitializer: mockInitializer{name: "web"}, deps: []string{"db"}},
+			},
+			expected: "initializer 'web' depends on unknown initializer 'db'",
+		},
+		{
+			name: "cycle",
+			initializers: []Initializer{
+				&mockInitializer{name: "free"},
+				&dependentInitializer{mockInitializer: mockInitializer{name: "a"}, deps: []string{"b"}},
+				&dependentInitializer{mockInitializer: mockInitializer{name: "b"}, deps: []string{"c"}},
+				&dependentInitializer{mockInitializer: mockInitializer{name: "c"}, deps: []string{"a"}},
+			},
+			expected: "initializer dependency cycle: a -> b -> c -> a",
+		},
+		{
+			name: "duplicate name",
+			initializers: []Initializer{
+				&mockInitializer{name: "db"},
+				&mockInitializer{name: "db"},
+			},
+			expected: "registered more than once",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := orderInitializers(tt.initializers)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestInitialize_DependencyCycle(t *testing.T) {
+	api := New(&config.Config{}, util.NewLogger(config.DefaultLoggerConfig()))
+	a := &dependentInitializer{mockInitializer: mockInitializer{name: "a"}, deps: []string{"b"}}
+	b := &dependentInitializer{mockInitializer: mockInitializer{name: "b"}, deps: []string{"a"}}
+	api.RegisterInitializer(a)
+	api.RegisterInitializer(b)
+
+	err := api.Initialize()
+	if err == nil || !strings.Contains(err.Error(), "failed to order initializers") {
+		t.Errorf("Expected a clear boot error, got %v", err)
+	}
+	if a.initializeCalled || b.initializeCalled {
+		t.Error("Expected no initializer to run")
+	}
+}
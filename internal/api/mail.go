@@ -0,0 +1,27 @@
+package api
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrMailDisabled is returned by the API's Mail until a mailer is set (e.g.,
+// by the mail initializer, when mail.enabled is set)
+var ErrMailDisabled = errors.New("mail is not enabled (mail.enabled)")
+
+// Mailer sends email rendered from templates:
+//
+//	err := api.APIFromContext(ctx).Mail.Send(ctx, user.Email, "welcome", user)
+type Mailer interface {
+	// Send renders the named template with data and sends it to to (one or
+	// more comma-separated addresses)
+	Send(ctx context.Context, to, template string, data interface{}) error
+}
+
+// disabledMailer is the API's Mail until a mailer is set
+type disabledMailer struct{}
+
+// Send returns ErrMailDisabled
+func (disabledMailer) Send(_ context.Context, _, _ string, _ interface{}) error {
+	return ErrMailDisabled
+}
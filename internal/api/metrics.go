@@ -0,0 +1,179 @@
+package api
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// metricsSampleSize is the number of recent latency samples kept per action
+// for percentile calculations
+const metricsSampleSize = 1024
+
+// ActionMetrics is a point-in-time summary of an action's latency, error
+// rate, and response sizes
+type ActionMetrics struct {
+	Count       int64   `json:"count"`
+	Errors      int64   `json:"errors"`
+	SuccessRate float64 `json:"successRate"`
+	ErrorRate   float64 `json:"errorRate"`
+	P50Ms       float64 `json:"p50Ms"`
+	P95Ms       float64 `json:"p95Ms"`
+	P99Ms       float64 `json:"p99Ms"`
+	MaxMs       float64 `json:"maxMs"`
+
+	AvgResponseBytes  float64 `json:"avgResponseBytes"`  // Mean serialized size of the action's responses
+	MaxResponseBytes  int64   `json:"maxResponseBytes"`  // Largest response
+	ResponsesTooLarge int64   `json:"responsesTooLarge"` // Responses refused for exceeding ActionMaxResponseSize
+}
+
+// Metrics tracks per-action latency and success/error counts in memory
+type Metrics struct {
+	actions map[string]*actionStats
+	mu      sync.RWMutex
+}
+
+// actionStats holds counters and a ring buffer of recent latencies for one action
+type actionStats struct {
+	count   int64
+	errors  int64
+	samples []time.Duration
+	next    int
+
+	responses     int64 // Responses measured
+	responseBytes int64 // Their total size
+	maxResponse   int64
+	tooLarge      int64
+
+	mu sync.Mutex
+}
+
+// NewMetrics creates an empty metrics store
+func NewMetrics() *Metrics {
+	return &Metrics{
+		actions: make(map[string]*actionStats),
+	}
+}
+
+// Record adds a single action execution to the metrics
+func (m *Metrics) Record(actionName string, duration time.Duration, err error) {
+	stats := m.stats(actionName)
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+
+	stats.count++
+	if err != nil {
+		stats.errors++
+	}
+
+	if len(stats.samples) < metricsSampleSize {
+		stats.samples = append(stats.samples, duration)
+	} else {
+		stats.samples[stats.next] = duration
+	}
+	stats.next = (stats.next + 1) % metricsSampleSize
+}
+
+// RecordResponseSize adds the serialized size of one of an action's
+// responses to the metrics, and whether it was refused as too large
+func (m *Metrics) RecordResponseSize(actionName string, size int64, tooLarge bool) {
+	stats := m.stats(actionName)
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+
+	stats.responses++
+	stats.responseBytes += size
+	stats.maxResponse = max(stats.maxResponse, size)
+	if tooLarge {
+		stats.tooLarge++
+	}
+}
+
+// stats returns the stats of an action, creating them on first use
+func (m *Metrics) stats(actionName string) *actionStats {
+	m.mu.RLock()
+	stats, exists := m.actions[actionName]
+	m.mu.RUnlock()
+	if exists {
+		return stats
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stats, exists = m.actions[actionName]
+	if !exists {
+		stats = &actionStats{samples: make([]time.Duration, 0, metricsSampleSize)}
+		m.actions[actionName] = stats
+	}
+	return stats
+}
+
+// Snapshot returns a summary of every action that has been recorded
+func (m *Metrics) Snapshot() map[string]ActionMetrics {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	snapshot := make(map[string]ActionMetrics, len(m.actions))
+	for name, stats := range m.actions {
+		snapshot[name] = stats.summary()
+	}
+	return snapshot
+}
+
+// Reset clears all recorded metrics
+func (m *Metrics) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.actions = make(map[string]*actionStats)
+}
+
+// summary computes the counters and latency percentiles for an action
+func (s *actionStats) summary() ActionMetrics {
+	s.mu.Lock()
+	sorted := make([]time.Duration, len(s.samples))
+	copy(sorted, s.samples)
+	result := ActionMetrics{
+		Count:             s.count,
+		Errors:            s.errors,
+		MaxResponseBytes:  s.maxResponse,
+		ResponsesTooLarge: s.tooLarge,
+	}
+	if s.responses > 0 {
+		result.AvgResponseBytes = float64(s.responseBytes) / float64(s.responses)
+	}
+	s.mu.Unlock()
+
+	if result.Count > 0 {
+		result.ErrorRate = float64(result.Errors) / float64(result.Count)
+		result.SuccessRate = 1 - result.ErrorRate
+	}
+
+	if len(sorted) == 0 {
+		return result
+	}
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	result.P50Ms = durationMs(percentile(sorted, 0.50))
+	result.P95Ms = durationMs(percentile(sorted, 0.95))
+	result.P99Ms = durationMs(percentile(sorted, 0.99))
+	result.MaxMs = durationMs(sorted[len(sorted)-1])
+
+	return result
+}
+
+// percentile returns the nearest-rank percentile p (0-1) of sorted samples
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(p*float64(len(sorted))+0.999999) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+// durationMs converts a duration to fractional milliseconds
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
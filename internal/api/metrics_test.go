@@ -0,0 +1,130 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+func TestMetrics_RecordAndSnapshot(t *testing.T) {
+	m := NewMetrics()
+
+	for i := 1; i <= 100; i++ {
+		var err error
+		if i%10 == 0 {
+			err = errors.New("failed")
+		}
+		m.Record("test:action", time.Duration(i)*time.Millisecond, err)
+	}
+
+	snapshot := m.Snapshot()
+	stats, ok := snapshot["test:action"]
+	if !ok {
+		t.Fatal("Expected metrics for test:action")
+	}
+
+	if stats.Count != 100 {
+		t.Errorf("Expected count 100, got %d", stats.Count)
+	}
+	if stats.Errors != 10 {
+		t.Errorf("Expected 10 errors, got %d", stats.Errors)
+	}
+	if stats.ErrorRate != 0.1 {
+		t.Errorf("Expected error rate 0.1, got %v", stats.ErrorRate)
+	}
+	if stats.SuccessRate != 0.9 {
+		t.Errorf("Expected success rate 0.9, got %v", stats.SuccessRate)
+	}
+	if stats.P50Ms != 50 {
+		t.Errorf("Expected p50 50ms, got %v", stats.P50Ms)
+	}
+	if stats.P95Ms != 95 {
+		t.Errorf("Expected p95 95ms, got %v", stats.P95Ms)
+	}
+	if stats.P99Ms != 99 {
+		t.Errorf("Expected p99 99ms, got %v", stats.P99Ms)
+	}
+	if stats.MaxMs != 100 {
+		t.Errorf("Expected max 100ms, got %v", stats.MaxMs)
+	}
+}
+
+func TestMetrics_SampleWindow(t *testing.T) {
+	m := NewMetrics()
+
+	// Fill the window with slow samples, then overwrite it with fast ones
+	for i := 0; i < metricsSampleSize; i++ {
+		m.Record("test:action", time.Second, nil)
+	}
+	for i := 0; i < metricsSampleSize; i++ {
+		m.Record("test:action", time.Millisecond, nil)
+	}
+
+	stats := m.Snapshot()["test:action"]
+	if stats.Count != int64(2*metricsSampleSize) {
+		t.Errorf("Expected count %d, got %d", 2*metricsSampleSize, stats.Count)
+	}
+	if stats.MaxMs != 1 {
+		t.Errorf("Expected old samples to be evicted (max 1ms), got %v", stats.MaxMs)
+	}
+}
+
+func TestMetrics_Reset(t *testing.T) {
+	m := NewMetrics()
+	m.Record("test:action", time.Millisecond, nil)
+	m.Reset()
+
+	if len(m.Snapshot()) != 0 {
+		t.Error("Expected no metrics after reset")
+	}
+}
+
+func TestConnection_Act_RecordsMetrics(t *testing.T) {
+	logger := util.NewLogger(config.LoggerConfig{Level: "error"})
+	apiInstance := New(&config.Config{}, logger)
+
+	if err := apiInstance.RegisterAction(&testLogAction{
+		BaseAction:  BaseAction{ActionName: "test:success"},
+		shouldError: false,
+	}); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	if err := apiInstance.RegisterAction(&testLogAction{
+		BaseAction:  BaseAction{ActionName: "test:error"},
+		shouldError: true,
+	}); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	conn := NewConnection("http", "127.0.0.1", "test-conn-id", nil)
+	conn.Act(context.Background(), apiInstance, "test:success", nil, "GET", "")
+	conn.Act(context.Background(), apiInstance, "test:error", nil, "GET", "")
+	conn.Act(context.Background(), apiInstance, "missing", nil, "GET", "")
+
+	snapshot := apiInstance.Metrics.Snapshot()
+	if snapshot["test:success"].Count != 1 || snapshot["test:success"].Errors != 0 {
+		t.Errorf("Unexpected metrics for test:success: %+v", snapshot["test:success"])
+	}
+	if snapshot["test:error"].Count != 1 || snapshot["test:error"].Errors != 1 {
+		t.Errorf("Unexpected metrics for test:error: %+v", snapshot["test:error"])
+	}
+	if _, exists := snapshot["missing"]; exists {
+		t.Error("Expected unknown actions not to be recorded")
+	}
+}
+
+func TestMetrics_RecordResponseSize(t *testing.T) {
+	m := NewMetrics()
+	m.RecordResponseSize("report:export", 100, false)
+	m.RecordResponseSize("report:export", 300, false)
+	m.RecordResponseSize("report:export", 5000, true)
+
+	stats := m.Snapshot()["report:export"]
+	if stats.AvgResponseBytes != 1800 || stats.MaxResponseBytes != 5000 || stats.ResponsesTooLarge != 1 {
+		t.Errorf("Expected avg 1800, max 5000, 1 too large, got %+v", stats)
+	}
+}
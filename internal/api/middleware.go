@@ -16,3 +16,84 @@ type Middleware interface {
 	// Can modify the response
 	RunAfter(params interface{}, conn *Connection) (*MiddlewareResponse, error)
 }
+
+// runBefore runs the middleware's RunBefore hooks in order. An UpdatedParams
+// map replaces the params later hooks and the action get. A hook that returns
+// an error or an UpdatedResponse stops the action: it fails with the error, or
+// responds with the response (stopped is then true).
+func runBefore(middleware []Middleware, params map[string]interface{}, conn *Connection) (map[string]interface{}, interface{}, bool, error) {
+	for _, mw := range middleware {
+		result, err := mw.RunBefore(params, conn)
+		if err != nil {
+			return params, nil, true, err
+		}
+		if result == nil {
+			continue
+		}
+		if updated, ok := result.UpdatedParams.(map[string]interface{}); ok {
+			params = updated
+		}
+		if result.UpdatedResponse != nil {
+			return params, result.UpdatedResponse, true, nil
+		}
+	}
+	return params, nil, false, nil
+}
+
+// runAfter runs the middleware's RunAfter hooks in order, once the action has
+// succeeded. An UpdatedResponse replaces the response; an error fails the
+// action.
+func runAfter(middleware []Middleware, params map[string]interface{}, conn *Connection, response interface{}) (interface{}, error) {
+	for _, mw := range middleware {
+		result, err := mw.RunAfter(params, conn)
+		if err != nil {
+			return nil, err
+		}
+		if result != nil && result.UpdatedResponse != nil {
+			response = result.UpdatedResponse
+		}
+	}
+	return response, nil
+}
+
+// Security scheme types, as used by OpenAPI
+const (
+	SecuritySchemeAPIKey = "apiKey"
+	SecuritySchemeHTTP   = "http"
+)
+
+// SecurityScheme describes how a middleware authenticates requests, for API documentation
+type SecurityScheme struct {
+	Name         string // Unique scheme name (e.g., "bearerAuth")
+	Type         string // SecuritySchemeAPIKey or SecuritySchemeHTTP
+	Scheme       string // HTTP auth scheme for SecuritySchemeHTTP (e.g., "bearer", "basic")
+	BearerFormat string // Token format hint for bearer auth (e.g., "JWT")
+	In           string // Where an API key is sent: "header", "query", or "cookie"
+	ParamName    string // Header, query, or cookie name carrying the API key
+	Description  string
+}
+
+// SecuredMiddleware is implemented by authentication middleware that wants
+// its requirements documented (e.g., in the swagger action)
+type SecuredMiddleware interface {
+	Middleware
+
+	// SecurityScheme returns the scheme this middleware enforces
+	SecurityScheme() SecurityScheme
+}
+
+// GetActionSecuritySchemes returns the security schemes enforced by the action's middleware
+func GetActionSecuritySchemes(action Action) []SecurityScheme {
+	return SecuritySchemes(GetActionMiddleware(action))
+}
+
+// SecuritySchemes returns the security schemes enforced by middleware
+func SecuritySchemes(middleware []Middleware) []SecurityScheme {
+	var schemes []SecurityScheme
+	for _, mw := range middleware {
+		if secured, ok := mw.(SecuredMiddleware); ok {
+			schemes = append(schemes, secured.SecurityScheme())
+		}
+	}
+	return schemes
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Namespace groups the actions whose names start with "<Name>:" (e.g.
+// "admin:users" is in the "admin" namespace), applying shared settings once
+// instead of repeating them on each action
+type Namespace struct {
+	Name        string       // e.g. "admin" for "admin:*"
+	RoutePrefix string       // Prepended to the routes of the namespace's web actions (e.g. "/admin")
+	Middleware  []Middleware // Run before each action's own middleware
+	Tag         string       // OpenAPI tag for actions without their own tags (default: the action name's prefix)
+}
+
+// RegisterNamespace registers a namespace. It applies to actions registered
+// before and after it. When namespaces nest (e.g. "admin" and "admin:users"),
+// an action uses the most specific one.
+func (a *API) RegisterNamespace(namespace Namespace) error {
+	namespace.Name = strings.TrimSuffix(namespace.Name, ":*")
+	if namespace.Name == "" {
+		return fmt.Errorf("namespace name is required")
+	}
+
+	a.actionsMu.Lock()
+	defer a.actionsMu.Unlock()
+
+	if _, exists := a.namespaces[namespace.Name]; exists {
+		return fmt.Errorf("namespace '%s' is already registered", namespace.Name)
+	}
+	a.namespaces[namespace.Name] = namespace
+
+	// Apply it to the actions already registered
+	for name, descriptor := range a.actions {
+		if ns, ok := a.namespaceOf(name); ok && ns.Name == namespace.Name {
+			a.actions[name] = applyNamespace(DescribeAction(descriptor.Action), namespace)
+		}
+	}
+	a.actionsRevision++
+	a.Logger.Debugf("Registered namespace: %s", namespace.Name)
+	return nil
+}
+
+// GetNamespace returns a registered namespace by name
+func (a *API) GetNamespace(name string) (Namespace, bool) {
+	a.actionsMu.RLock()
+	defer a.actionsMu.RUnlock()
+
+	namespace, ok := a.namespaces[name]
+	return namespace, ok
+}
+
+// describeAction reads an action's metadata and applies its namespace.
+// Callers must hold actionsMu.
+func (a *API) describeAction(action Action) *ActionDescriptor {
+	descriptor := DescribeAction(action)
+	if namespace, ok := a.namespaceOf(descriptor.Name); ok {
+		descriptor = applyNamespace(descriptor, namespace)
+	}
+	return descriptor
+}
+
+// namespaceOf returns the most specific namespace of an action name.
+// Callers must hold actionsMu.
+func (a *API) namespaceOf(actionName string) (Namespace, bool) {
+	var found Namespace
+	ok := false
+	for name, namespace := range a.namespaces {
+		if strings.HasPrefix(actionName, name+":") && len(name) > len(found.Name) {
+			found, ok = namespace, true
+		}
+	}
+	return found, ok
+}
+
+// applyNamespace applies a namespace's settings to a descriptor. The action's
+// own configuration is copied, not changed.
+func applyNamespace(descriptor *ActionDescriptor, namespace Namespace) *ActionDescriptor {
+	if descriptor.Web != nil && namespace.RoutePrefix != "" {
+		web := *descriptor.Web
+		web.Route = strings.TrimSuffix(namespace.RoutePrefix, "/") + web.Route
+		descriptor.Web = &web
+	}
+
+	if len(namespace.Middleware) > 0 {
+		middleware := make([]Middleware, 0, len(namespace.Middleware)+len(descriptor.Middleware))
+		middleware = append(middleware, namespace.Middleware...)
+		descriptor.Middleware = append(middleware, descriptor.Middleware...)
+	}
+
+	if namespace.Tag != "" && (descriptor.Docs == nil || len(descriptor.Docs.Tags) == 0) {
+		docs := DocsConfig{}
+		if descriptor.Docs != nil {
+			docs = *descriptor.Docs
+		}
+		docs.Tags = []string{namespace.Tag}
+		descriptor.Docs = &docs
+	}
+	return descriptor
+}
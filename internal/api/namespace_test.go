@@ -0,0 +1,166 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+type namespaceMiddleware struct {
+	name string
+	err  error // Returned by RunBefore, refusing the action
+}
+
+func (m *namespaceMiddleware) RunBefore(_ interface{}, _ *Connection) (*MiddlewareResponse, error) {
+	return nil, m.err
+}
+
+func (m *namespaceMiddleware) RunAfter(_ interface{}, _ *Connection) (*MiddlewareResponse, error) {
+	return nil, nil
+}
+
+func newRoutedAction(name, route string) *mockAction {
+	action := newMockAction(name, "")
+	action.ActionWeb = &WebConfig{Route: route, Method: HTTPMethodGET}
+	return action
+}
+
+func TestRegisterNamespace(t *testing.T) {
+	a := New(&config.Config{}, util.NewLogger(config.DefaultLoggerConfig()))
+	auth := &namespaceMiddleware{name: "auth"}
+	own := &namespaceMiddleware{name: "own"}
+
+	// Registered before the namespace
+	early := newRoutedAction("admin:users", "/users")
+	if err := a.RegisterAction(early); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	if err := a.RegisterNamespace(Namespace{
+		Name:        "admin:*",
+		RoutePrefix: "/admin/",
+		Middleware:  []Middleware{auth},
+		Tag:         "Administration",
+	}); err != nil {
+		t.Fatalf("Failed to register namespace: %v", err)
+	}
+
+	// Registered after the namespace, with its own middleware and tags
+	late := newRoutedAction("admin:stats", "/stats")
+	late.ActionMiddleware = []Middleware{own}
+	late.ActionDocs = &DocsConfig{Tags: []string{"stats"}}
+	if err := a.RegisterAction(late); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	// Outside the namespace
+	if err := a.RegisterAction(newRoutedAction("administrator:list", "/list")); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	users, _ := a.GetActionDescriptor("admin:users")
+	if users.Web.Route != "/admin/users" {
+		t.Errorf("Expected route '/admin/users', got %q", users.Web.Route)
+	}
+	if len(users.Middleware) != 1 || users.Middleware[0] != auth {
+		t.Errorf("Expected the namespace middleware, got %v", users.Middleware)
+	}
+	if users.Docs == nil || len(users.Docs.Tags) != 1 || users.Docs.Tags[0] != "Administration" {
+		t.Errorf("Expected the namespace tag, got %v", users.Docs)
+	}
+	if early.ActionWeb.Route != "/users" {
+		t.Errorf("Expected the action's own config to be unchanged, got %q", early.ActionWeb.Route)
+	}
+
+	stats, _ := a.GetActionDescriptor("admin:stats")
+	if stats.Web.Route != "/admin/stats" {
+		t.Errorf("Expected route '/admin/stats', got %q", stats.Web.Route)
+	}
+	if len(stats.Middleware) != 2 || stats.Middleware[0] != auth || stats.Middleware[1] != own {
+		t.Errorf("Expected namespace middleware before the action's own, got %v", stats.Middleware)
+	}
+	if len(stats.Docs.Tags) != 1 || stats.Docs.Tags[0] != "stats" {
+		t.Errorf("Expected the action's own tags, got %v", stats.Docs.Tags)
+	}
+
+	list, _ := a.GetActionDescriptor("administrator:list")
+	if list.Web.Route != "/list" || len(list.Middleware) != 0 {
+		t.Errorf("Expected action outside the namespace to be unchanged, got %q %v", list.Web.Route, list.Middleware)
+	}
+
+	if _, ok := a.GetNamespace("admin"); !ok {
+		t.Error("Expected namespace to be registered")
+	}
+}
+
+func TestRegisterNamespace_Nested(t *testing.T) {
+	a := New(&config.Config{}, util.NewLogger(config.DefaultLoggerConfig()))
+
+	if err := a.RegisterNamespace(Namespace{Name: "admin", RoutePrefix: "/admin"}); err != nil {
+		t.Fatalf("Failed to register namespace: %v", err)
+	}
+	if err := a.RegisterNamespace(Namespace{Name: "admin:reports", RoutePrefix: "/admin/reports"}); err != nil {
+		t.Fatalf("Failed to register namespace: %v", err)
+	}
+	if err := a.RegisterAction(newRoutedAction("admin:reports:daily", "/daily")); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	daily, _ := a.GetActionDescriptor("admin:reports:daily")
+	if daily.Web.Route != "/admin/reports/daily" {
+		t.Errorf("Expected the most specific namespace's prefix, got %q", daily.Web.Route)
+	}
+}
+
+func TestRegisterNamespace_Errors(t *testing.T) {
+	a := New(&config.Config{}, util.NewLogger(config.DefaultLoggerConfig()))
+
+	if err := a.RegisterNamespace(Namespace{}); err == nil {
+		t.Error("Expected an error for a namespace without a name")
+	}
+	if err := a.RegisterNamespace(Namespace{Name: "admin"}); err != nil {
+		t.Fatalf("Failed to register namespace: %v", err)
+	}
+	if err := a.RegisterNamespace(Namespace{Name: "admin:*"}); err == nil {
+		t.Error("Expected an error for a duplicate namespace")
+	}
+}
+
+func TestRegisterNamespace_MiddlewareRefusesActions(t *testing.T) {
+	a := New(&config.Config{}, util.NewLogger(config.LoggerConfig{Level: "error"}))
+	errNotAdmin := errors.New("admins only")
+	if err := a.RegisterNamespace(Namespace{
+		Name:       "admin",
+		Middleware: []Middleware{&namespaceMiddleware{name: "requireAdmin", err: errNotAdmin}},
+	}); err != nil {
+		t.Fatalf("Failed to register namespace: %v", err)
+	}
+
+	ran := false
+	run := func(context.Context, interface{}, *Connection) (interface{}, error) {
+		ran = true
+		return "ok", nil
+	}
+	if err := a.RegisterAction(NewAction("admin:users").Handler(run)); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	if err := a.RegisterAction(NewAction("public:status").Handler(run)); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	conn := NewConnection("test", "127.0.0.1", "namespace-test", nil)
+	result := conn.Act(context.Background(), a, "admin:users", nil, "GET", "")
+	if !errors.Is(result.Error, errNotAdmin) || result.Response != nil {
+		t.Errorf("Expected the namespace middleware to refuse the action, got %v %v", result.Response, result.Error)
+	}
+	if ran {
+		t.Error("Expected the refused action not to run")
+	}
+
+	if result := conn.Act(context.Background(), a, "public:status", nil, "GET", ""); result.Error != nil || !ran {
+		t.Errorf("Expected the action outside the namespace to run, got %v", result.Error)
+	}
+}
@@ -0,0 +1,309 @@
+package api
+
+import (
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// Pagination defaults, used when PaginationOptions leaves them unset
+const (
+	DefaultPerPage    = 25
+	DefaultMaxPerPage = 100
+)
+
+// SortField is a field a list is sorted by
+type SortField struct {
+	Field      string `json:"field"`
+	Descending bool   `json:"descending,omitempty"`
+}
+
+// String formats the field as it is given in the sort param ("-" for descending)
+func (s SortField) String() string {
+	if s.Descending {
+		return "-" + s.Field
+	}
+	return s.Field
+}
+
+// PaginationInput is the standard input of list actions: which page to
+// return, how to sort it, and how to filter it. Read it from an action's
+// params with ParsePagination.
+type PaginationInput struct {
+	Page    int               `json:"page"`             // Page to return, from 1
+	PerPage int               `json:"perPage"`          // Items per page
+	Cursor  string            `json:"cursor,omitempty"` // Where the page starts, for cursor pagination (see DecodeCursor)
+	Sort    []SortField       `json:"sort,omitempty"`
+	Filters map[string]string `json:"filters,omitempty"`
+}
+
+// Offset returns how many items come before the page
+func (p PaginationInput) Offset() int {
+	return (p.Page - 1) * p.PerPage
+}
+
+// Limit returns how many items the page holds
+func (p PaginationInput) Limit() int {
+	return p.PerPage
+}
+
+// PaginationOptions are the limits of a list action's PaginationInput
+type PaginationOptions struct {
+	DefaultPerPage int      // Items per page when perPage isn't given (default: DefaultPerPage)
+	MaxPerPage     int      // Largest accepted perPage (default: DefaultMaxPerPage)
+	SortFields     []string // Fields the list can be sorted by (none = sorting isn't accepted)
+	DefaultSort    string   // Sort when none is given (e.g., "-createdAt")
+	Filters        []string // Fields the list can be filtered by (none = filters aren't accepted)
+}
+
+// ParsePagination reads the pagination params of a list action and
+// validates them against opts:
+//
+//   - page and perPage, as numbers or numeric strings
+//   - cursor, an opaque position from a previous page's nextCursor
+//   - sort, comma-separated fields, "-" first for descending (e.g., "-createdAt,name")
+//   - filters, as a "filters" object or "filter[field]" params (e.g., ?filter[status]=active)
+//
+// Invalid params are returned as CONNECTION_ACTION_PARAM_VALIDATION errors.
+func ParsePagination(params interface{}, opts PaginationOptions) (PaginationInput, error) {
+	values, _ := params.(map[string]interface{})
+	if opts.DefaultPerPage <= 0 {
+		opts.DefaultPerPage = DefaultPerPage
+	}
+	if opts.MaxPerPage <= 0 {
+		opts.MaxPerPage = DefaultMaxPerPage
+	}
+
+	input := PaginationInput{Page: 1, PerPage: opts.DefaultPerPage}
+	var err error
+	if value, ok := values["page"]; ok {
+		if input.Page, err = paginationInt("page", value); err != nil {
+			return PaginationInput{}, err
+		}
+	}
+	if value, ok := values["perPage"]; ok {
+		if input.PerPage, err = paginationInt("perPage", value); err != nil {
+			return PaginationInput{}, err
+		}
+	}
+	if value, ok := values["cursor"]; ok {
+		cursor, isString := value.(string)
+		if !isString {
+			return PaginationInput{}, paginationError("cursor", value, "must be a string")
+		}
+		input.Cursor = cursor
+	}
+
+	sortParam := opts.DefaultSort
+	if value, ok := values["sort"]; ok {
+		if sortParam, err = paginationSortParam(value); err != nil {
+			return PaginationInput{}, err
+		}
+	}
+	input.Sort = ParseSort(sortParam)
+
+	if input.Filters, err = paginationFilters(values); err != nil {
+		return PaginationInput{}, err
+	}
+
+	return input, input.Validate(opts)
+}
+
+// Validate checks the input against opts. ParsePagination validates what it
+// parses, so this is for inputs built another way (e.g., decoded from JSON).
+func (p PaginationInput) Validate(opts PaginationOptions) error {
+	maxPerPage := opts.MaxPerPage
+	if maxPerPage <= 0 {
+		maxPerPage = DefaultMaxPerPage
+	}
+
+	if p.Page < 1 {
+		return paginationError("page", p.Page, "must be at least 1")
+	}
+	if p.PerPage < 1 || p.PerPage > maxPerPage {
+		return paginationError("perPage", p.PerPage, fmt.Sprintf("must be between 1 and %d", maxPerPage))
+	}
+	if p.Cursor != "" {
+		if _, err := DecodeCursor(p.Cursor); err != nil {
+			return paginationError("cursor", p.Cursor, "is not a valid cursor")
+		}
+	}
+	for _, field := range p.Sort {
+		if !slices.Contains(opts.SortFields, field.Field) {
+			return paginationError("sort", field.String(), fmt.Sprintf("must be one of: %s", strings.Join(opts.SortFields, ", ")))
+		}
+	}
+	for field := range p.Filters {
+		if !slices.Contains(opts.Filters, field) {
+			return paginationError("filter["+field+"]", p.Filters[field], fmt.Sprintf("can only filter by: %s", strings.Join(opts.Filters, ", ")))
+		}
+	}
+	return nil
+}
+
+// ParseSort parses comma-separated sort fields, "-" first for descending
+// (e.g., "-createdAt,name")
+func ParseSort(value string) []SortField {
+	var fields []SortField
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if name, descending := strings.CutPrefix(field, "-"); descending {
+			fields = append(fields, SortField{Field: name, Descending: true})
+		} else {
+			fields = append(fields, SortField{Field: strings.TrimPrefix(field, "+")})
+		}
+	}
+	return fields
+}
+
+// EncodeCursor encodes a position in a list (e.g., the last item's ID) as
+// an opaque cursor for PaginatedResponse.NextCursor
+func EncodeCursor(position string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(position))
+}
+
+// DecodeCursor decodes a cursor made by EncodeCursor
+func DecodeCursor(cursor string) (string, error) {
+	position, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", fmt.Errorf("invalid cursor: %w", err)
+	}
+	return string(position), nil
+}
+
+// PaginationMeta describes the page of a PaginatedResponse
+type PaginationMeta struct {
+	Page       int    `json:"page"`
+	PerPage    int    `json:"perPage"`
+	Total      *int   `json:"total,omitempty"`      // Items in the whole list (nil = unknown)
+	TotalPages *int   `json:"totalPages,omitempty"` // Pages in the whole list (nil = unknown)
+	NextCursor string `json:"nextCursor,omitempty"` // Cursor of the next page, for cursor pagination
+	HasMore    bool   `json:"hasMore"`              // There are items after this page
+}
+
+// PaginatedResponse is the response of a list action: a page of items and
+// where it is in the list. The web server also describes the page in
+// X-Total-Count and Link headers.
+type PaginatedResponse struct {
+	Items      interface{}    `json:"items"`
+	Pagination PaginationMeta `json:"pagination"`
+}
+
+// Paginate returns items as the page of input in a list of total items.
+// Use a negative total when it isn't known, and WithNextCursor for cursor
+// pagination.
+func Paginate(input PaginationInput, items interface{}, total int) *PaginatedResponse {
+	meta := PaginationMeta{Page: input.Page, PerPage: input.PerPage}
+	if total >= 0 {
+		totalPages := 0
+		if input.PerPage > 0 {
+			totalPages = (total + input.PerPage - 1) / input.PerPage
+		}
+		meta.Total = &total
+		meta.TotalPages = &totalPages
+		meta.HasMore = input.Page < totalPages
+	}
+	return &PaginatedResponse{Items: items, Pagination: meta}
+}
+
+// WithNextCursor sets the cursor of the next page ("" = this is the last page)
+func (r *PaginatedResponse) WithNextCursor(cursor string) *PaginatedResponse {
+	r.Pagination.NextCursor = cursor
+	r.Pagination.HasMore = cursor != ""
+	return r
+}
+
+// paginationInt reads a number param given as a number or a numeric string
+func paginationInt(key string, value interface{}) (int, error) {
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case float64:
+		if v == float64(int(v)) {
+			return int(v), nil
+		}
+	case string:
+		if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			return n, nil
+		}
+	}
+	return 0, paginationError(key, value, "must be a whole number")
+}
+
+// paginationSortParam reads the sort param, given as a string or a list of
+// strings (e.g., repeated ?sort= params)
+func paginationSortParam(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case []string:
+		return strings.Join(v, ","), nil
+	case []interface{}:
+		fields := make([]string, 0, len(v))
+		for _, field := range v {
+			s, ok := field.(string)
+			if !ok {
+				return "", paginationError("sort", value, "must be a string or a list of strings")
+			}
+			fields = append(fields, s)
+		}
+		return strings.Join(fields, ","), nil
+	}
+	return "", paginationError("sort", value, "must be a string or a list of strings")
+}
+
+// paginationFilters reads the "filters" object and "filter[field]" params
+func paginationFilters(values map[string]interface{}) (map[string]string, error) {
+	filters := make(map[string]string)
+	if value, ok := values["filters"]; ok {
+		object, isObject := value.(map[string]interface{})
+		if !isObject {
+			return nil, paginationError("filters", value, "must be an object")
+		}
+		for field, filter := range object {
+			filters[field] = fmt.Sprint(filter)
+		}
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys) // Deterministic when a filter is given twice
+	for _, key := range keys {
+		field, ok := strings.CutPrefix(key, "filter[")
+		if !ok || !strings.HasSuffix(field, "]") {
+			continue
+		}
+		field = strings.TrimSuffix(field, "]")
+		switch v := values[key].(type) {
+		case []string:
+			filters[field] = strings.Join(v, ",")
+		default:
+			filters[field] = fmt.Sprint(v)
+		}
+	}
+
+	if len(filters) == 0 {
+		return nil, nil
+	}
+	return filters, nil
+}
+
+// paginationError is a validation error of a pagination param
+func paginationError(key string, value interface{}, message string) error {
+	return util.NewTypedError(
+		util.ErrorTypeConnectionActionParamValidation,
+		fmt.Sprintf("%s %s", key, message),
+		util.WithKey(key),
+		util.WithValue(value),
+	)
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+var testPaginationOptions = PaginationOptions{
+	MaxPerPage:  50,
+	SortFields:  []string{"name", "createdAt"},
+	DefaultSort: "-createdAt",
+	Filters:     []string{"status"},
+}
+
+func TestParsePagination(t *testing.T) {
+	input, err := ParsePagination(map[string]interface{}{
+		"page":           "3",
+		"perPage":        float64(10),
+		"sort":           "name,-createdAt",
+		"filter[status]": "active",
+	}, testPaginationOptions)
+	if err != nil {
+		t.Fatalf("ParsePagination failed: %v", err)
+	}
+
+	want := PaginationInput{
+		Page:    3,
+		PerPage: 10,
+		Sort:    []SortField{{Field: "name"}, {Field: "createdAt", Descending: true}},
+		Filters: map[string]string{"status": "active"},
+	}
+	if !reflect.DeepEqual(input, want) {
+		t.Errorf("Expected %+v, got %+v", want, input)
+	}
+	if input.Offset() != 20 || input.Limit() != 10 {
+		t.Errorf("Expected offset 20 and limit 10, got %d and %d", input.Offset(), input.Limit())
+	}
+}
+
+func TestParsePagination_Defaults(t *testing.T) {
+	input, err := ParsePagination(map[string]interface{}{}, testPaginationOptions)
+	if err != nil {
+		t.Fatalf("ParsePagination failed: %v", err)
+	}
+	if input.Page != 1 || input.PerPage != DefaultPerPage {
+		t.Errorf("Expected page 1 of %d, got page %d of %d", DefaultPerPage, input.Page, input.PerPage)
+	}
+	if len(input.Sort) != 1 || input.Sort[0].String() != "-createdAt" {
+		t.Errorf("Expected the default sort, got %v", input.Sort)
+	}
+	if input.Filters != nil {
+		t.Errorf("Expected no filters, got %v", input.Filters)
+	}
+}
+
+func TestParsePagination_FiltersObject(t *testing.T) {
+	input, err := ParsePagination(map[string]interface{}{
+		"filters": map[string]interface{}{"status": "archived"},
+		"sort":    []interface{}{"name"},
+	}, testPaginationOptions)
+	if err != nil {
+		t.Fatalf("ParsePagination failed: %v", err)
+	}
+	if input.Filters["status"] != "archived" {
+		t.Errorf("Expected the status filter, got %v", input.Filters)
+	}
+	if len(input.Sort) != 1 || input.Sort[0].Field != "name" {
+		t.Errorf("Expected to sort by name, got %v", input.Sort)
+	}
+}
+
+func TestParsePagination_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		key    string
+	}{
+		{"page not a number", map[string]interface{}{"page": "two"}, "page"},
+		{"page zero", map[string]interface{}{"page": 0}, "page"},
+		{"fractional page", map[string]interface{}{"page": 1.5}, "page"},
+		{"per page too large", map[string]interface{}{"perPage": "51"}, "perPage"},
+		{"per page zero", map[string]interface{}{"perPage": "0"}, "perPage"},
+		{"unknown sort field", map[string]interface{}{"sort": "password"}, "sort"},
+		{"unknown filter", map[string]interface{}{"filter[role]": "admin"}, "filter[role]"},
+		{"filters not an object", map[string]interface{}{"filters": "status"}, "filters"},
+		{"invalid cursor", map[string]interface{}{"cursor": "not a cursor!"}, "cursor"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePagination(tt.params, testPaginationOptions)
+			typedErr, ok := err.(*util.TypedError)
+			if !ok {
+				t.Fatalf("Expected a typed error, got %v", err)
+			}
+			if typedErr.Type != util.ErrorTypeConnectionActionParamValidation {
+				t.Errorf("Expected a param validation error, got %s", typedErr.Type)
+			}
+			if typedErr.Key != tt.key {
+				t.Errorf("Expected key %q, got %q", tt.key, typedErr.Key)
+			}
+		})
+	}
+}
+
+func TestCursor(t *testing.T) {
+	cursor := EncodeCursor("user:42")
+	position, err := DecodeCursor(cursor)
+	if err != nil {
+		t.Fatalf("DecodeCursor failed: %v", err)
+	}
+	if position != "user:42" {
+		t.Errorf("Expected user:42, got %q", position)
+	}
+
+	input, err := ParsePagination(map[string]interface{}{"cursor": cursor}, testPaginationOptions)
+	if err != nil {
+		t.Fatalf("ParsePagination failed: %v", err)
+	}
+	if input.Cursor != cursor {
+		t.Errorf("Expected the cursor, got %q", input.Cursor)
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	input := PaginationInput{Page: 2, PerPage: 10}
+	page := Paginate(input, []string{"a", "b"}, 25)
+
+	body, err := json.Marshal(page)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	want := `{"items":["a","b"],"pagination":{"page":2,"perPage":10,"total":25,"totalPages":3,"hasMore":true}}`
+	if string(body) != want {
+		t.Errorf("Expected %s, got %s", want, body)
+	}
+
+	last := Paginate(PaginationInput{Page: 3, PerPage: 10}, nil, 25)
+	if last.Pagination.HasMore {
+		t.Error("Expected the last page to have no more items")
+	}
+}
+
+func TestPaginate_Cursor(t *testing.T) {
+	page := Paginate(PaginationInput{Page: 1, PerPage: 10}, []string{"a"}, -1).WithNextCursor(EncodeCursor("a"))
+	if page.Pagination.Total != nil || page.Pagination.TotalPages != nil {
+		t.Error("Expected no total when it is unknown")
+	}
+	if !page.Pagination.HasMore || page.Pagination.NextCursor == "" {
+		t.Errorf("Expected a next cursor, got %+v", page.Pagination)
+	}
+
+	page.WithNextCursor("")
+	if page.Pagination.HasMore {
+		t.Error("Expected no more items without a next cursor")
+	}
+}
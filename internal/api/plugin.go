@@ -0,0 +1,103 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+)
+
+// Plugin bundles reusable functionality (e.g., auth, an admin UI, or metrics)
+// so it can be published as a separate Go module and mounted with API.Use
+type Plugin interface {
+	// Name returns the unique name of the plugin
+	Name() string
+
+	// Actions returns the actions the plugin adds
+	Actions() []Action
+
+	// Initializers returns the initializers the plugin adds
+	Initializers() []Initializer
+
+	// Servers returns the servers the plugin adds, built for api
+	Servers(api *API) []Server
+}
+
+// PluginConfig is implemented by plugins with their own config section
+type PluginConfig interface {
+	// ConfigSection returns the section's name, a pointer to its struct, and
+	// its defaults (see config.RegisterSection)
+	ConfigSection() (name string, target interface{}, defaults interface{})
+}
+
+// BasePlugin can be embedded in plugins to provide empty defaults for the
+// parts a plugin doesn't use
+type BasePlugin struct{}
+
+// Actions returns no actions
+func (BasePlugin) Actions() []Action { return nil }
+
+// Initializers returns no initializers
+func (BasePlugin) Initializers() []Initializer { return nil }
+
+// Servers returns no servers
+func (BasePlugin) Servers(*API) []Server { return nil }
+
+// Use mounts a plugin: its config section is registered (and loaded, if the
+// config was loaded before it was registered), then its actions, initializers,
+// and servers are registered. Call Use before Initialize.
+func (a *API) Use(plugin Plugin) error {
+	name := plugin.Name()
+
+	a.pluginsMu.Lock()
+	for _, existing := range a.plugins {
+		if existing.Name() == name {
+			a.pluginsMu.Unlock()
+			return fmt.Errorf("plugin '%s' is already in use", name)
+		}
+	}
+	a.plugins = append(a.plugins, plugin)
+	a.pluginsMu.Unlock()
+
+	if pluginConfig, ok := plugin.(PluginConfig); ok {
+		section, target, defaults := pluginConfig.ConfigSection()
+		if !config.IsSectionRegistered(section) {
+			if err := config.RegisterSection(section, target, defaults); err != nil {
+				return fmt.Errorf("plugin %s: %w", name, err)
+			}
+		}
+		if a.Config != nil && a.Config.Sections[section] == nil {
+			if err := config.LoadSection(a.Config, section); err != nil {
+				return fmt.Errorf("plugin %s: %w", name, err)
+			}
+		}
+	}
+
+	for _, action := range plugin.Actions() {
+		if err := a.RegisterAction(action); err != nil {
+			return fmt.Errorf("plugin %s: %w", name, err)
+		}
+	}
+	for _, initializer := range plugin.Initializers() {
+		a.RegisterInitializer(initializer)
+	}
+	for _, server := range plugin.Servers(a) {
+		if a.Config != nil && !a.Config.Server.IsEnabled(server.Name()) {
+			a.Logger.Infof("Server %s is disabled", server.Name())
+			continue
+		}
+		a.RegisterServer(server)
+	}
+
+	a.Logger.Infof("Using plugin: %s", name)
+	return nil
+}
+
+// GetPlugins returns the plugins in use, in the order they were mounted
+func (a *API) GetPlugins() []Plugin {
+	a.pluginsMu.RLock()
+	defer a.pluginsMu.RUnlock()
+
+	plugins := make([]Plugin, len(a.plugins))
+	copy(plugins, a.plugins)
+	return plugins
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+type testPluginConfig struct {
+	Greeting string
+}
+
+type testPlugin struct {
+	BasePlugin
+	server *mockServer
+}
+
+func (p *testPlugin) Name() string { return "test-plugin" }
+
+func (p *testPlugin) Actions() []Action {
+	return []Action{newMockAction("plugin:hello", "From a plugin")}
+}
+
+func (p *testPlugin) Initializers() []Initializer {
+	return []Initializer{&mockInitializer{name: "plugin-initializer"}}
+}
+
+func (p *testPlugin) Servers(_ *API) []Server {
+	return []Server{p.server}
+}
+
+func (p *testPlugin) ConfigSection() (string, interface{}, interface{}) {
+	return "testplugin", &testPluginConfig{}, testPluginConfig{Greeting: "hello"}
+}
+
+func TestAPI_Use(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("ACTIONHERO_TESTPLUGIN_GREETING", "howdy")
+
+	// Config is loaded before the plugin's section is registered
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	api := New(cfg, util.NewLogger(config.DefaultLoggerConfig()))
+	plugin := &testPlugin{server: &mockServer{name: "plugin-server"}}
+	if err := api.Use(plugin); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, ok := api.GetAction("plugin:hello"); !ok {
+		t.Error("Expected the plugin's action to be registered")
+	}
+	if len(api.GetInitializers()) != 1 || api.GetInitializers()[0].Name() != "plugin-initializer" {
+		t.Errorf("Expected the plugin's initializer to be registered, got %v", api.GetInitializers())
+	}
+	if len(api.GetServers()) != 1 || api.GetServers()[0] != plugin.server {
+		t.Error("Expected the plugin's server to be registered")
+	}
+	if len(api.GetPlugins()) != 1 {
+		t.Errorf("Expected 1 plugin, got %d", len(api.GetPlugins()))
+	}
+
+	section := config.GetSection[testPluginConfig](cfg, "testplugin")
+	if section == nil || section.Greeting != "howdy" {
+		t.Errorf("Expected the plugin's config section loaded from env, got %+v", section)
+	}
+
+	if err := api.Use(plugin); err == nil || !strings.Contains(err.Error(), "already in use") {
+		t.Errorf("Expected an error mounting the plugin twice, got %v", err)
+	}
+}
+
+type basicPlugin struct {
+	BasePlugin
+}
+
+func (basicPlugin) Name() string { return "basic" }
+
+func TestAPI_UseWithoutConfig(t *testing.T) {
+	api := New(&config.Config{}, util.NewLogger(config.DefaultLoggerConfig()))
+	if err := api.Use(basicPlugin{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(api.GetActions()) != 0 || len(api.GetServers()) != 0 || len(api.GetInitializers()) != 0 {
+		t.Error("Expected BasePlugin to add nothing")
+	}
+}
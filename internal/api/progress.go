@@ -0,0 +1,59 @@
+package api
+
+import (
+	"context"
+
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// Progress is an update on how far a long-running action has got
+type Progress struct {
+	Percent   float64 `json:"percent"` // 0 to 100
+	Message   string  `json:"message,omitempty"`
+	RequestID string  `json:"requestId,omitempty"` // The action's request, when reported with ReportProgress
+}
+
+// ProgressHandler delivers an action's progress to its client (see
+// Connection.SetProgressHandler). It may be called from several actions at
+// once, and must not block.
+type ProgressHandler func(Progress)
+
+// SetProgressHandler sets how the connection delivers progress reports: the
+// CLI prints them, and the web server sends WebSocket clients progress
+// messages. Without one (e.g., over HTTP), reports are dropped.
+func (c *Connection) SetProgressHandler(handler ProgressHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.progress = handler
+}
+
+// Progress reports how far the running action has got, from 0 to 100
+// percent, with an optional message (e.g., "imported 400 of 1000 rows"), so
+// the client can tell it hasn't hung. Use ReportProgress to also identify
+// the action's request.
+func (c *Connection) Progress(percent float64, message string) {
+	c.reportProgress(Progress{Percent: percent, Message: message})
+}
+
+// ReportProgress reports progress like Connection.Progress, for the action
+// whose context is ctx. The report carries the action's request ID, so a
+// WebSocket client running several actions at once can tell them apart.
+func ReportProgress(ctx context.Context, percent float64, message string) {
+	conn := ConnectionFromContext(ctx)
+	if conn == nil {
+		return
+	}
+	conn.reportProgress(Progress{Percent: percent, Message: message, RequestID: util.RequestIDFromContext(ctx)})
+}
+
+// reportProgress passes progress to the connection's handler
+func (c *Connection) reportProgress(progress Progress) {
+	c.mu.RLock()
+	handler := c.progress
+	c.mu.RUnlock()
+	if handler == nil {
+		return
+	}
+	progress.Percent = min(max(progress.Percent, 0), 100)
+	handler(progress)
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+func TestConnection_Progress(t *testing.T) {
+	apiInstance := New(&config.Config{}, util.NewLogger(config.LoggerConfig{Level: "error"}))
+	action := NewAction("test:import").Handler(func(ctx context.Context, _ interface{}, conn *Connection) (interface{}, error) {
+		conn.Progress(-5, "starting")
+		conn.Progress(50, "halfway")
+		ReportProgress(ctx, 150, "")
+		return "done", nil
+	})
+	if err := apiInstance.RegisterAction(action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	// Without a handler, reports are dropped
+	conn := NewConnection("web", "127.0.0.1", "test-id", nil)
+	if result := conn.Act(context.Background(), apiInstance, "test:import", nil, "GET", ""); result.Error != nil {
+		t.Fatalf("Expected the action to succeed, got %v", result.Error)
+	}
+
+	var reports []Progress
+	conn.SetProgressHandler(func(progress Progress) { reports = append(reports, progress) })
+	ctx := util.WithRequestID(context.Background(), "req-1")
+	if result := conn.Act(ctx, apiInstance, "test:import", nil, "GET", ""); result.Error != nil {
+		t.Fatalf("Expected the action to succeed, got %v", result.Error)
+	}
+
+	want := []Progress{
+		{Percent: 0, Message: "starting"},
+		{Percent: 50, Message: "halfway"},
+		{Percent: 100, RequestID: "req-1"},
+	}
+	if len(reports) != len(want) {
+		t.Fatalf("Expected %d reports, got %v", len(want), reports)
+	}
+	for i := range want {
+		if reports[i] != want[i] {
+			t.Errorf("Report %d: expected %+v, got %+v", i, want[i], reports[i])
+		}
+	}
+}
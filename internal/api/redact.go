@@ -0,0 +1,78 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+)
+
+// redactParams returns params with the values of secret keys masked (by the
+// same rule as secret settings, see config.IsSensitive: e.g. "password",
+// "apiToken"), in nested objects too, so they can be logged or reported.
+// params itself is never changed; it is returned as is when it holds no
+// secrets.
+func redactParams(params map[string]interface{}) map[string]interface{} {
+	redacted, _ := redactMap(params)
+	return redacted
+}
+
+// redactMap returns m with its secrets masked, copying it only when it has
+// any, and whether it had any
+func redactMap(m map[string]interface{}) (map[string]interface{}, bool) {
+	var copied map[string]interface{}
+	for key, value := range m {
+		masked, changed := redactValue(key, value)
+		if !changed {
+			continue
+		}
+		if copied == nil {
+			copied = make(map[string]interface{}, len(m))
+			for k, v := range m {
+				copied[k] = v
+			}
+		}
+		copied[key] = masked
+	}
+	if copied == nil {
+		return m, false
+	}
+	return copied, true
+}
+
+// redactValue masks the value of a secret key, or the secrets nested in it
+func redactValue(key string, value interface{}) (interface{}, bool) {
+	if value == nil {
+		return nil, false
+	}
+	if config.IsSensitive(key) {
+		if s, ok := value.(string); ok {
+			return config.Mask(s), true
+		}
+		return config.Mask(fmt.Sprint(value)), true
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return redactMap(v)
+	case []interface{}:
+		var copied []interface{}
+		for i, item := range v {
+			nested, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			masked, changed := redactMap(nested)
+			if !changed {
+				continue
+			}
+			if copied == nil {
+				copied = append([]interface{}(nil), v...)
+			}
+			copied[i] = masked
+		}
+		if copied != nil {
+			return copied, true
+		}
+	}
+	return value, false
+}
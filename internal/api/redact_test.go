@@ -0,0 +1,38 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedactParams(t *testing.T) {
+	params := map[string]interface{}{
+		"name":     "Mario",
+		"password": "its-a-me!",
+		"pin":      1234,
+		"apiKey":   42,
+		"billing":  map[string]interface{}{"cardToken": "tok_123", "country": "IT"},
+		"accounts": []interface{}{map[string]interface{}{"secret": "abc"}, "plain"},
+	}
+
+	redacted := redactParams(params)
+	expected := map[string]interface{}{
+		"name":     "Mario",
+		"password": "*********",
+		"pin":      1234,
+		"apiKey":   "**",
+		"billing":  map[string]interface{}{"cardToken": "*******", "country": "IT"},
+		"accounts": []interface{}{map[string]interface{}{"secret": "***"}, "plain"},
+	}
+	if !reflect.DeepEqual(redacted, expected) {
+		t.Errorf("Expected %v, got %v", expected, redacted)
+	}
+	if params["password"] != "its-a-me!" || params["billing"].(map[string]interface{})["cardToken"] != "tok_123" {
+		t.Error("Expected the params themselves to be unchanged")
+	}
+
+	plain := map[string]interface{}{"name": "Mario"}
+	if reflect.ValueOf(redactParams(plain)).UnsafePointer() != reflect.ValueOf(plain).UnsafePointer() {
+		t.Error("Expected params without secrets not to be copied")
+	}
+}
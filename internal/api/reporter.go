@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// Error sources passed to error reporters
+const (
+	ErrorSourceAction = "action" // An action returned an unhandled error
+	ErrorSourcePanic  = "panic"  // An action panicked
+	ErrorSourceTask   = "task"   // A task failed
+)
+
+// ErrorReport describes an error passed to error reporters
+type ErrorReport struct {
+	Error      error
+	Type       util.ErrorType // Type of the error (ErrorTypeConnectionActionRun when untyped)
+	Source     string         // ErrorSourceAction, ErrorSourcePanic, or ErrorSourceTask
+	Action     string         // Action or task name
+	Connection *Connection    // Connection that ran the action (nil for tasks); read it, don't keep it (see ConnectionPool)
+	Params     map[string]interface{}
+	Stack      string
+	RequestID  string // Correlation ID of the request (from the context when not set)
+}
+
+// ErrorReporter is called for every unhandled action error, panic, and task failure
+type ErrorReporter func(ctx context.Context, report ErrorReport)
+
+// RegisterErrorReporter adds a reporter that is called for every reported error
+func (a *API) RegisterErrorReporter(reporter ErrorReporter) {
+	a.reportersMu.Lock()
+	defer a.reportersMu.Unlock()
+	a.reporters = append(a.reporters, reporter)
+}
+
+// ReportError passes the report to every registered error reporter.
+// Type and Stack are filled in from the error when not already set.
+func (a *API) ReportError(ctx context.Context, report ErrorReport) {
+	var typedErr *util.TypedError
+	if errors.As(report.Error, &typedErr) {
+		if report.Type == "" {
+			report.Type = typedErr.Type
+		}
+		if report.Stack == "" {
+			report.Stack = typedErr.Stack
+		}
+	}
+	if report.Type == "" {
+		report.Type = util.ErrorTypeConnectionActionRun
+	}
+	if report.RequestID == "" {
+		report.RequestID = util.RequestIDFromContext(ctx)
+	}
+
+	a.reportersMu.RLock()
+	reporters := make([]ErrorReporter, len(a.reporters))
+	copy(reporters, a.reporters)
+	a.reportersMu.RUnlock()
+
+	for _, reporter := range reporters {
+		a.callReporter(ctx, reporter, report)
+	}
+}
+
+// callReporter runs a single reporter, so a panicking reporter can't take down the caller
+func (a *API) callReporter(ctx context.Context, reporter ErrorReporter, report ErrorReport) {
+	defer func() {
+		if r := recover(); r != nil {
+			a.Logger.Errorf("Error reporter panicked: %v\n%s", r, debug.Stack())
+		}
+	}()
+	reporter(ctx, report)
+}
+
+// shouldReportError returns whether an action error is unhandled and should be
+// reported: untyped errors and typed errors that map to a 5xx status.
+// Client errors (validation, not found, ...), requests shed at an action's
+// concurrency limit, and calls refused by an open circuit breaker are
+// expected and not reported.
+func shouldReportError(err error) bool {
+	var typedErr *util.TypedError
+	if errors.As(err, &typedErr) {
+		switch typedErr.Type {
+		case util.ErrorTypeConnectionActionSaturated, util.ErrorTypeDependencyUnavailable:
+			return false
+		}
+		return typedErr.HTTPStatus() >= 500
+	}
+	return true
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+type reporterTestAction struct {
+	BaseAction
+	run func() (interface{}, error)
+}
+
+func (a *reporterTestAction) Run(_ context.Context, _ interface{}, _ *Connection) (interface{}, error) {
+	return a.run()
+}
+
+func setupReporterTest(t *testing.T, run func() (interface{}, error)) (*API, *[]ErrorReport) {
+	t.Helper()
+
+	logger := util.NewLogger(config.LoggerConfig{Level: "fatal"})
+	apiInstance := New(&config.Config{}, logger)
+	if err := apiInstance.RegisterAction(&reporterTestAction{
+		BaseAction: BaseAction{ActionName: "test:report"},
+		run:        run,
+	}); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	reports := make([]ErrorReport, 0)
+	apiInstance.RegisterErrorReporter(func(_ context.Context, report ErrorReport) {
+		reports = append(reports, report)
+	})
+
+	return apiInstance, &reports
+}
+
+func TestErrorReporter_ActionError(t *testing.T) {
+	apiInstance, reports := setupReporterTest(t, func() (interface{}, error) {
+		return nil, errors.New("database exploded")
+	})
+
+	conn := NewConnection("web", "127.0.0.1", "conn-id", nil)
+	conn.Act(context.Background(), apiInstance, "test:report", map[string]interface{}{"id": 1}, "GET", "")
+
+	if len(*reports) != 1 {
+		t.Fatalf("Expected 1 report, got %d", len(*reports))
+	}
+
+	report := (*reports)[0]
+	if report.Source != ErrorSourceAction {
+		t.Errorf("Expected source %q, got %q", ErrorSourceAction, report.Source)
+	}
+	if report.Action != "test:report" {
+		t.Errorf("Expected action 'test:report', got %q", report.Action)
+	}
+	if report.Type != util.ErrorTypeConnectionActionRun {
+		t.Errorf("Expected untyped errors to default to %s, got %s", util.ErrorTypeConnectionActionRun, report.Type)
+	}
+	if report.Connection != conn {
+		t.Error("Expected report to include the connection")
+	}
+	if report.Params["id"] != 1 {
+		t.Errorf("Expected report to include params, got %v", report.Params)
+	}
+}
+
+func TestErrorReporter_ClientErrorsNotReported(t *testing.T) {
+	apiInstance, reports := setupReporterTest(t, func() (interface{}, error) {
+		return nil, util.NewTypedError(util.ErrorTypeConnectionActionParamRequired, "name is required")
+	})
+
+	conn := NewConnection("web", "127.0.0.1", "conn-id", nil)
+	conn.Act(context.Background(), apiInstance, "test:report", nil, "GET", "")
+
+	if len(*reports) != 0 {
+		t.Errorf("Expected client errors not to be reported, got %d reports", len(*reports))
+	}
+}
+
+func TestErrorReporter_Panic(t *testing.T) {
+	apiInstance, reports := setupReporterTest(t, func() (interface{}, error) {
+		panic("boom")
+	})
+
+	conn := NewConnection("web", "127.0.0.1", "conn-id", nil)
+	result := conn.Act(context.Background(), apiInstance, "test:report", nil, "GET", "")
+
+	if result.Error == nil || !strings.Contains(result.Error.Error(), "boom") {
+		t.Fatalf("Expected panic to be returned as an error, got %v", result.Error)
+	}
+
+	if len(*reports) != 1 {
+		t.Fatalf("Expected 1 report, got %d", len(*reports))
+	}
+	if (*reports)[0].Source != ErrorSourcePanic {
+		t.Errorf("Expected source %q, got %q", ErrorSourcePanic, (*reports)[0].Source)
+	}
+	if !strings.Contains((*reports)[0].Stack, "reporter_test.go") {
+		t.Error("Expected stack to point at the panicking action")
+	}
+}
+
+func TestErrorReporter_ReporterPanicRecovered(t *testing.T) {
+	apiInstance, reports := setupReporterTest(t, func() (interface{}, error) {
+		return nil, errors.New("failed")
+	})
+	apiInstance.RegisterErrorReporter(func(_ context.Context, _ ErrorReport) {
+		panic("reporter failed")
+	})
+
+	conn := NewConnection("web", "127.0.0.1", "conn-id", nil)
+	conn.Act(context.Background(), apiInstance, "test:report", nil, "GET", "")
+
+	if len(*reports) != 1 {
+		t.Errorf("Expected the first reporter to still run, got %d reports", len(*reports))
+	}
+}
+
+func TestAPI_InitializeInvalidSentryDSN(t *testing.T) {
+	logger := util.NewLogger(config.LoggerConfig{Level: "fatal"})
+	apiInstance := New(&config.Config{Sentry: config.SentryConfig{DSN: "not a dsn"}}, logger)
+
+	if err := apiInstance.Initialize(); err == nil {
+		t.Error("Expected Initialize to fail with an invalid Sentry DSN")
+	}
+}
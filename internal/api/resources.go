@@ -0,0 +1,111 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// resources holds the services shared with actions (database handles,
+// clients, custom services, ...), one per type
+type resources struct {
+	values map[reflect.Type]interface{}
+	mu     sync.RWMutex
+}
+
+func newResources() *resources {
+	return &resources{values: make(map[reflect.Type]interface{})}
+}
+
+// typeOf returns the type T, including interface types
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// Provide makes value the API's resource of type T, replacing any previous
+// one. Initializers typically provide the services they set up:
+//
+//	api.Provide[*sql.DB](a, db)
+//
+// The API, its Config, Logger, Metrics, and Cache are always provided.
+func Provide[T any](a *API, value T) {
+	a.resources.mu.Lock()
+	defer a.resources.mu.Unlock()
+
+	a.resources.values[typeOf[T]()] = value
+	a.Logger.Debugf("Provided resource: %s", typeOf[T]())
+}
+
+// Lookup returns the API's resource of type T, and whether there is one
+func Lookup[T any](a *API) (T, bool) {
+	if value, ok := a.builtinResource(typeOf[T]()); ok {
+		typed, ok := value.(T)
+		return typed, ok
+	}
+
+	a.resources.mu.RLock()
+	defer a.resources.mu.RUnlock()
+
+	value, ok := a.resources.values[typeOf[T]()]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return value.(T), true
+}
+
+// Resource returns the resource of type T of the API running the action
+// whose context is ctx:
+//
+//	db, err := api.Resource[*sql.DB](ctx)
+func Resource[T any](ctx context.Context) (T, error) {
+	var zero T
+	apiInstance := APIFromContext(ctx)
+	if apiInstance == nil {
+		return zero, errors.New("API instance not found in context")
+	}
+
+	value, ok := Lookup[T](apiInstance)
+	if !ok {
+		return zero, fmt.Errorf("no %s resource is provided", typeOf[T]())
+	}
+	return value, nil
+}
+
+// ResourceTypes returns the types of the provided resources (built-ins
+// excluded), sorted
+func (a *API) ResourceTypes() []string {
+	a.resources.mu.RLock()
+	defer a.resources.mu.RUnlock()
+
+	types := make([]string, 0, len(a.resources.values))
+	for t := range a.resources.values {
+		types = append(types, t.String())
+	}
+	sort.Strings(types)
+	return types
+}
+
+// builtinResource returns the API's own services, read from its fields so
+// replacing one (e.g., API.Cache) is always reflected
+func (a *API) builtinResource(t reflect.Type) (interface{}, bool) {
+	switch t {
+	case typeOf[*API]():
+		return a, true
+	case typeOf[*config.Config]():
+		return a.Config, a.Config != nil
+	case typeOf[*util.Logger]():
+		return a.Logger, a.Logger != nil
+	case typeOf[*Metrics]():
+		return a.Metrics, a.Metrics != nil
+	case typeOf[Cache]():
+		return a.Cache, a.Cache != nil
+	}
+	return nil, false
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+type greeter interface {
+	Greet(name string) string
+}
+
+type englishGreeter struct{}
+
+func (englishGreeter) Greet(name string) string { return "hello " + name }
+
+type userStore struct {
+	users []string
+}
+
+func TestProvideAndLookup(t *testing.T) {
+	api := New(&config.Config{}, util.NewLogger(config.DefaultLoggerConfig()))
+
+	if _, ok := Lookup[*userStore](api); ok {
+		t.Fatal("Expected no resource before one is provided")
+	}
+
+	store := &userStore{users: []string{"mario"}}
+	Provide(api, store)
+	Provide[greeter](api, englishGreeter{})
+
+	if got, ok := Lookup[*userStore](api); !ok || got != store {
+		t.Errorf("Expected the provided store, got %v", got)
+	}
+	if got, ok := Lookup[greeter](api); !ok || got.Greet("luigi") != "hello luigi" {
+		t.Errorf("Expected the provided greeter, got %v", got)
+	}
+
+	// Providing again replaces the resource
+	replacement := &userStore{}
+	Provide(api, replacement)
+	if got, _ := Lookup[*userStore](api); got != replacement {
+		t.Error("Expected the resource to be replaced")
+	}
+
+	want := []string{"*api.userStore", "api.greeter"}
+	if got := api.ResourceTypes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected resource types %v, got %v", want, got)
+	}
+}
+
+func TestLookup_Builtins(t *testing.T) {
+	cfg := &config.Config{}
+	api := New(cfg, util.NewLogger(config.DefaultLoggerConfig()))
+
+	if got, ok := Lookup[*API](api); !ok || got != api {
+		t.Error("Expected the API to be provided")
+	}
+	if got, ok := Lookup[*config.Config](api); !ok || got != cfg {
+		t.Error("Expected the config to be provided")
+	}
+	if got, ok := Lookup[*util.Logger](api); !ok || got != api.Logger {
+		t.Error("Expected the logger to be provided")
+	}
+	if got, ok := Lookup[*Metrics](api); !ok || got != api.Metrics {
+		t.Error("Expected the metrics to be provided")
+	}
+
+	// Replacing the cache is reflected
+	cache := NewMemoryCache()
+	api.Cache = cache
+	if got, ok := Lookup[Cache](api); !ok || got != cache {
+		t.Error("Expected the current cache to be provided")
+	}
+}
+
+type resourceAction struct {
+	BaseAction
+}
+
+func (a *resourceAction) Run(ctx context.Context, params interface{}, conn *Connection) (interface{}, error) {
+	store, err := Resource[*userStore](ctx)
+	if err != nil {
+		return nil, err
+	}
+	return store.users, nil
+}
+
+func TestResource(t *testing.T) {
+	api := New(&config.Config{}, util.NewLogger(config.DefaultLoggerConfig()))
+	if err := api.RegisterAction(&resourceAction{BaseAction: BaseAction{ActionName: "users:list"}}); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	conn := NewConnection("test", "test", "resources", nil)
+
+	result := conn.Act(context.Background(), api, "users:list", nil, "", "")
+	if result.Error == nil || result.Error.Error() != "no *api.userStore resource is provided" {
+		t.Errorf("Expected a missing resource error, got %v", result.Error)
+	}
+
+	Provide(api, &userStore{users: []string{"mario"}})
+	result = conn.Act(context.Background(), api, "users:list", nil, "", "")
+	if result.Error != nil || !reflect.DeepEqual(result.Response, []string{"mario"}) {
+		t.Errorf("Expected the provided users, got %v (error: %v)", result.Response, result.Error)
+	}
+
+	if _, err := Resource[*userStore](context.Background()); err == nil {
+		t.Error("Expected an error without an API in the context")
+	}
+}
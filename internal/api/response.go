@@ -0,0 +1,87 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// RawResponse can be returned by actions that produce a non-JSON body (e.g., YAML).
+// The web server writes Body as-is with ContentType instead of the JSON envelope;
+// transports that only speak JSON receive Body as a string.
+type RawResponse struct {
+	ContentType string
+	Body        []byte
+}
+
+// MarshalJSON encodes the body as a JSON string
+func (r *RawResponse) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(r.Body))
+}
+
+// FileResponse can be returned by actions to send a file (e.g., a download or
+// media). The web server streams it with http.ServeContent instead of
+// buffering it, so Range and conditional requests work, and files are sent
+// with sendfile where the OS supports it. Transports that only speak JSON
+// receive the file's name and content type.
+type FileResponse struct {
+	Path        string        // File to send (ignored when Content is set)
+	Content     io.ReadSeeker // Content to send instead of a file; closed after sending if it is an io.Closer
+	Name        string        // File name for the content type and downloads (defaults to Path's base name)
+	ContentType string        // Content type (defaults to one detected from Name or the content)
+	ModTime     time.Time     // Last-Modified time (defaults to the file's modification time)
+	Download    bool          // Ask browsers to save the file instead of displaying it
+}
+
+// FileName returns the name the file is sent as
+func (f *FileResponse) FileName() string {
+	if f.Name != "" {
+		return f.Name
+	}
+	if f.Path != "" {
+		return filepath.Base(f.Path)
+	}
+	return ""
+}
+
+// MarshalJSON encodes the file's name and content type
+func (f *FileResponse) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Name        string `json:"name,omitempty"`
+		ContentType string `json:"contentType,omitempty"`
+	}{f.FileName(), f.ContentType})
+}
+
+// ErrorJSON returns the client-facing form of an action error, disclosing as
+// much as process.errordisclosure allows. The message is translated into the
+// first of the client's locales with a translation for the error's code.
+func (a *API) ErrorJSON(err error, requestID string, locales ...string) util.ErrorJSON {
+	disclosure := config.ErrorDisclosureStandard
+	if cfg := a.CurrentConfig(); cfg != nil && cfg.Process.ErrorDisclosure != "" {
+		disclosure = cfg.Process.ErrorDisclosure
+	}
+	return a.LocalizeError(util.ErrorToJSON(err, requestID, disclosure), locales)
+}
+
+// LocalizeError translates an error's message using the Messages catalog,
+// keyed by its code. {key} and {value} in the translation are replaced with
+// the error's key and value. Untranslated errors are returned as-is.
+func (a *API) LocalizeError(body util.ErrorJSON, locales []string) util.ErrorJSON {
+	if a.Messages == nil || len(locales) == 0 {
+		return body
+	}
+
+	vars := map[string]string{"key": body.Key}
+	if body.Value != nil {
+		vars["value"] = fmt.Sprint(body.Value)
+	}
+	if message, ok := a.Messages.Translate(locales, body.Code, vars); ok {
+		body.Message = message
+	}
+	return body
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// responseCachePrefix namespaces cached action responses in the Cache
+const responseCachePrefix = "actionhero:response:"
+
+// CacheConfig caches an action's successful responses, so repeated requests
+// are served without running the action. Only cache idempotent actions.
+type CacheConfig struct {
+	TTL           time.Duration // How long a response is served from the cache
+	VaryByParams  []string      // Params that select the cached response (nil = all params)
+	VaryByHeaders []string      // Request headers that select the cached response
+}
+
+// responseCacheKey returns the cache key for an action's response to params
+// and headers, built from the values the action's CacheConfig varies by.
+// Responses to a tenant are kept apart from other tenants'.
+func responseCacheKey(ctx context.Context, actionName string, cfg *CacheConfig, params map[string]interface{}, headers http.Header) (string, error) {
+	vary := struct {
+		Params  map[string]interface{} `json:"params"`
+		Headers map[string][]string    `json:"headers"`
+	}{Params: params, Headers: map[string][]string{}}
+
+	if cfg.VaryByParams != nil {
+		vary.Params = make(map[string]interface{}, len(cfg.VaryByParams))
+		for _, name := range cfg.VaryByParams {
+			if value, ok := params[name]; ok {
+				vary.Params[name] = value
+			}
+		}
+	}
+	for _, name := range cfg.VaryByHeaders {
+		if values := headers.Values(name); len(values) > 0 {
+			vary.Headers[http.CanonicalHeaderKey(name)] = values
+		}
+	}
+
+	// json.Marshal sorts map keys, so equal values give equal keys
+	encoded, err := json.Marshal(vary)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(encoded)
+	return responseCacheActionPrefix(actionName) + TenantCacheKey(ctx, hex.EncodeToString(sum[:])), nil
+}
+
+// responseCacheActionPrefix is the prefix of every cached response of an action
+func responseCacheActionPrefix(actionName string) string {
+	return responseCachePrefix + actionName + ":"
+}
+
+// cachedResponse returns the action's cached response to params, if any.
+// Cache failures are logged and treated as misses.
+func (a *API) cachedResponse(ctx context.Context, descriptor *ActionDescriptor, params map[string]interface{}) (interface{}, bool) {
+	key, err := responseCacheKey(ctx, descriptor.Name, descriptor.Cache, params, util.RequestHeadersFromContext(ctx))
+	if err != nil {
+		return nil, false
+	}
+	value, ok, err := a.Cache.Get(ctx, key)
+	if err != nil {
+		a.Logger.WithContext(ctx).Warnf("Failed to read cached response of %s: %v", descriptor.Name, err)
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+
+	var response interface{}
+	if err := json.Unmarshal(value, &response); err != nil {
+		a.Logger.WithContext(ctx).Warnf("Failed to decode cached response of %s: %v", descriptor.Name, err)
+		return nil, false
+	}
+	return response, true
+}
+
+// cacheResponse stores the action's response to params. Raw and file
+// responses aren't cached. Cache failures are logged; the response is still
+// returned.
+func (a *API) cacheResponse(ctx context.Context, descriptor *ActionDescriptor, params map[string]interface{}, response interface{}) {
+	switch response.(type) {
+	case *RawResponse, *FileResponse:
+		return
+	}
+
+	key, err := responseCacheKey(ctx, descriptor.Name, descriptor.Cache, params, util.RequestHeadersFromContext(ctx))
+	if err != nil {
+		return
+	}
+	value, err := json.Marshal(response)
+	if err != nil {
+		a.Logger.WithContext(ctx).Warnf("Failed to encode response of %s for the cache: %v", descriptor.Name, err)
+		return
+	}
+	if err := a.Cache.Set(ctx, key, value, descriptor.Cache.TTL); err != nil {
+		a.Logger.WithContext(ctx).Warnf("Failed to cache response of %s: %v", descriptor.Name, err)
+	}
+}
+
+// InvalidateActionCache removes every cached response of an action
+func (a *API) InvalidateActionCache(ctx context.Context, actionName string) error {
+	return a.Cache.DeletePrefix(ctx, responseCacheActionPrefix(actionName))
+}
+
+// InvalidateCachedResponse removes an action's cached response to params
+// and headers (only the ones the action's CacheConfig varies by matter), for
+// the tenant of ctx
+func (a *API) InvalidateCachedResponse(ctx context.Context, actionName string, params map[string]interface{}, headers http.Header) error {
+	descriptor, ok := a.GetActionDescriptor(actionName)
+	if !ok {
+		return fmt.Errorf("action not found: %s", actionName)
+	}
+	if descriptor.Cache == nil {
+		return nil
+	}
+
+	key, err := responseCacheKey(ctx, actionName, descriptor.Cache, params, headers)
+	if err != nil {
+		return err
+	}
+	return a.Cache.Delete(ctx, key)
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// countingAction counts its runs and echoes its params
+type countingAction struct {
+	BaseAction
+	runs atomic.Int32
+	err  error
+}
+
+func (a *countingAction) Run(ctx context.Context, params interface{}, conn *Connection) (interface{}, error) {
+	a.runs.Add(1)
+	if a.err != nil {
+		return nil, a.err
+	}
+	return map[string]interface{}{"params": params}, nil
+}
+
+func setupCachedAction(t *testing.T, cache *CacheConfig) (*API, *countingAction) {
+	t.Helper()
+	apiInstance := New(&config.Config{}, util.NewLogger(config.DefaultLoggerConfig()))
+	action := &countingAction{BaseAction: BaseAction{ActionName: "test:cached", ActionCache: cache}}
+	if err := apiInstance.RegisterAction(action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	return apiInstance, action
+}
+
+func actCached(ctx context.Context, apiInstance *API, params map[string]interface{}) ActResult {
+	conn := NewConnection("web", "127.0.0.1", "cache-conn", nil)
+	return conn.Act(ctx, apiInstance, "test:cached", params, "GET", "")
+}
+
+func TestConnection_Act_ResponseCache(t *testing.T) {
+	apiInstance, action := setupCachedAction(t, &CacheConfig{TTL: time.Minute})
+	ctx := context.Background()
+
+	first := actCached(ctx, apiInstance, map[string]interface{}{"id": "1"})
+	if first.Error != nil || first.Cached {
+		t.Fatalf("Expected an uncached success, got %+v", first)
+	}
+	second := actCached(ctx, apiInstance, map[string]interface{}{"id": "1"})
+	if second.Error != nil || !second.Cached {
+		t.Fatalf("Expected a cache hit, got %+v", second)
+	}
+	if action.runs.Load() != 1 {
+		t.Errorf("Expected the action to run once, ran %d times", action.runs.Load())
+	}
+
+	response := second.Response.(map[string]interface{})
+	if response["params"].(map[string]interface{})["id"] != "1" {
+		t.Errorf("Expected the cached response, got %v", second.Response)
+	}
+
+	// Different params miss
+	if result := actCached(ctx, apiInstance, map[string]interface{}{"id": "2"}); result.Cached {
+		t.Error("Expected different params to miss")
+	}
+}
+
+func TestConnection_Act_ResponseCacheVary(t *testing.T) {
+	apiInstance, action := setupCachedAction(t, &CacheConfig{
+		TTL:           time.Minute,
+		VaryByParams:  []string{"id"},
+		VaryByHeaders: []string{"Accept-Language"},
+	})
+
+	english := util.WithRequestHeaders(context.Background(), http.Header{"Accept-Language": {"en"}})
+	french := util.WithRequestHeaders(context.Background(), http.Header{"Accept-Language": {"fr"}})
+
+	actCached(english, apiInstance, map[string]interface{}{"id": "1", "page": "1"})
+	if result := actCached(english, apiInstance, map[string]interface{}{"id": "1", "page": "2"}); !result.Cached {
+		t.Error("Expected params outside VaryByParams to be ignored")
+	}
+	if result := actCached(french, apiInstance, map[string]interface{}{"id": "1"}); result.Cached {
+		t.Error("Expected a different header value to miss")
+	}
+	if action.runs.Load() != 2 {
+		t.Errorf("Expected 2 runs, got %d", action.runs.Load())
+	}
+}
+
+func TestConnection_Act_ResponseCacheSkipsErrors(t *testing.T) {
+	apiInstance, action := setupCachedAction(t, &CacheConfig{TTL: time.Minute})
+	action.err = errors.New("boom")
+
+	actCached(context.Background(), apiInstance, nil)
+	if result := actCached(context.Background(), apiInstance, nil); result.Cached || result.Error == nil {
+		t.Fatalf("Expected errors not to be cached, got %+v", result)
+	}
+	if action.runs.Load() != 2 {
+		t.Errorf("Expected 2 runs, got %d", action.runs.Load())
+	}
+}
+
+func TestAPI_InvalidateActionCache(t *testing.T) {
+	apiInstance, action := setupCachedAction(t, &CacheConfig{TTL: time.Minute, VaryByParams: []string{"id"}})
+	ctx := context.Background()
+
+	actCached(ctx, apiInstance, map[string]interface{}{"id": "1"})
+	actCached(ctx, apiInstance, map[string]interface{}{"id": "2"})
+
+	if err := apiInstance.InvalidateCachedResponse(ctx, "test:cached", map[string]interface{}{"id": "1"}, nil); err != nil {
+		t.Fatalf("InvalidateCachedResponse failed: %v", err)
+	}
+	if result := actCached(ctx, apiInstance, map[string]interface{}{"id": "1"}); result.Cached {
+		t.Error("Expected the invalidated response to miss")
+	}
+	if result := actCached(ctx, apiInstance, map[string]interface{}{"id": "2"}); !result.Cached {
+		t.Error("Expected other responses to stay cached")
+	}
+
+	if err := apiInstance.InvalidateActionCache(ctx, "test:cached"); err != nil {
+		t.Fatalf("InvalidateActionCache failed: %v", err)
+	}
+	if result := actCached(ctx, apiInstance, map[string]interface{}{"id": "2"}); result.Cached {
+		t.Error("Expected every response to miss after invalidating the action")
+	}
+	if action.runs.Load() != 4 {
+		t.Errorf("Expected 4 runs, got %d", action.runs.Load())
+	}
+
+	if err := apiInstance.InvalidateCachedResponse(ctx, "missing", nil, nil); err == nil {
+		t.Error("Expected an error for an unknown action")
+	}
+}
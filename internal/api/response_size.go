@@ -0,0 +1,44 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// sizeCounter is a writer that only counts what is written to it
+type sizeCounter int64
+
+func (c *sizeCounter) Write(p []byte) (int, error) {
+	*c += sizeCounter(len(p))
+	return len(p), nil
+}
+
+// responseSize returns the serialized size of an action's response: the
+// length of a RawResponse's body, or of the JSON encoding of anything else
+// (as the web server and WebSocket send it, without the envelope). ok is
+// false for responses whose size isn't known up front (FileResponses are
+// streamed) or that can't be encoded.
+func responseSize(response interface{}) (size int64, ok bool) {
+	switch r := response.(type) {
+	case *FileResponse:
+		return 0, false
+	case *RawResponse:
+		return int64(len(r.Body)), true
+	}
+
+	var counter sizeCounter
+	if err := json.NewEncoder(&counter).Encode(response); err != nil {
+		return 0, false
+	}
+	return int64(counter) - 1, true // Without the encoder's trailing newline
+}
+
+// newResponseTooLargeError returns the error for a response of size bytes,
+// over the action's ActionMaxResponseSize
+func newResponseTooLargeError(size int64, limit config.ByteSize) error {
+	return util.NewTypedError(util.ErrorTypeConnectionActionResponseTooLarge,
+		fmt.Sprintf("response of %d bytes is larger than the action's limit of %s", size, limit))
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// routeParamPattern matches the params of a route (e.g., ":id")
+var routeParamPattern = regexp.MustCompile(`:\w+`)
+
+// WebRoutes returns the actions served over HTTP, without those shadowed by
+// another action's WebConfig.Override. Two actions with the same method and
+// route (params match whatever their names, so /user/:id and /user/:userId
+// are the same route) are an error unless exactly one of them overrides the
+// other.
+func WebRoutes(actions []*ActionDescriptor) ([]*ActionDescriptor, error) {
+	routes := make([]*ActionDescriptor, 0, len(actions))
+	byRoute := make(map[string]int, len(actions)) // Route key -> index in routes
+
+	for _, action := range actions {
+		if action.Web == nil {
+			continue
+		}
+		key := routeKey(action.Web)
+		i, ok := byRoute[key]
+		if !ok {
+			byRoute[key] = len(routes)
+			routes = append(routes, action)
+			continue
+		}
+
+		existing := routes[i]
+		switch {
+		case action.Web.Override && !existing.Web.Override:
+			routes[i] = action
+		case existing.Web.Override && !action.Web.Override:
+			// The existing action keeps the route
+		default:
+			return nil, fmt.Errorf("route %s %s is declared by both %s and %s (set WebConfig.Override on one of them to shadow the other)",
+				action.Web.Method, action.Web.Route, existing.Name, action.Name)
+		}
+	}
+	return routes, nil
+}
+
+// routeKey identifies the method and route of a WebConfig, ignoring the
+// names of the route's params
+func routeKey(web *WebConfig) string {
+	return string(web.Method) + " " + routeParamPattern.ReplaceAllString(web.Route, ":")
+}
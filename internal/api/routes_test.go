@@ -0,0 +1,46 @@
+package api
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWebRoutes(t *testing.T) {
+	routed := func(name string, method HTTPMethod, route string, override bool) *ActionDescriptor {
+		return &ActionDescriptor{Name: name, Web: &WebConfig{Method: method, Route: route, Override: override}}
+	}
+
+	routes, err := WebRoutes([]*ActionDescriptor{
+		routed("user:view", HTTPMethodGET, "/user/:id", false),
+		routed("user:edit", HTTPMethodPUT, "/user/:id", false),
+		{Name: "task:only"},
+	})
+	if err != nil || len(routes) != 2 {
+		t.Fatalf("Expected 2 routes, got %d (%v)", len(routes), err)
+	}
+
+	_, err = WebRoutes([]*ActionDescriptor{
+		routed("user:get", HTTPMethodGET, "/user/:userId", false),
+		routed("user:view", HTTPMethodGET, "/user/:id", false),
+	})
+	if err == nil || !strings.Contains(err.Error(), "user:get") || !strings.Contains(err.Error(), "user:view") {
+		t.Errorf("Expected a conflict naming both actions, got %v", err)
+	}
+
+	for _, order := range [][2]bool{{true, false}, {false, true}} {
+		routes, err := WebRoutes([]*ActionDescriptor{
+			routed("a:first", HTTPMethodGET, "/thing", order[0]),
+			routed("b:second", HTTPMethodGET, "/thing", order[1]),
+		})
+		if err != nil || len(routes) != 1 || !routes[0].Web.Override {
+			t.Errorf("Expected the overriding action to keep the route, got %v (%v)", routes, err)
+		}
+	}
+
+	if _, err := WebRoutes([]*ActionDescriptor{
+		routed("a:first", HTTPMethodGET, "/thing", true),
+		routed("b:second", HTTPMethodGET, "/thing", true),
+	}); err == nil {
+		t.Error("Expected a conflict when both actions override")
+	}
+}
@@ -0,0 +1,220 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+	"github.com/google/uuid"
+)
+
+// sentryTimeout bounds each request to Sentry
+const sentryTimeout = 5 * time.Second
+
+// sentryEvent is the subset of the Sentry event payload we send
+type sentryEvent struct {
+	EventID     string                 `json:"event_id"`
+	Timestamp   string                 `json:"timestamp"`
+	Level       string                 `json:"level"`
+	Platform    string                 `json:"platform"`
+	Logger      string                 `json:"logger"`
+	ServerName  string                 `json:"server_name,omitempty"`
+	Environment string                 `json:"environment,omitempty"`
+	Message     string                 `json:"message"`
+	Exception   *sentryExceptionList   `json:"exception,omitempty"`
+	Tags        map[string]string      `json:"tags"`
+	Extra       map[string]interface{} `json:"extra,omitempty"`
+}
+
+type sentryExceptionList struct {
+	Values []sentryException `json:"values"`
+}
+
+type sentryException struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// sentryQueueSize is how many events can wait to be sent. Errors reported
+// while it is full are dropped, so a burst of errors can't pile up requests.
+const sentryQueueSize = 100
+
+// SentryReporter sends errors to Sentry. Events are queued and sent in the
+// background by one worker, so reporting never blocks the action.
+type SentryReporter struct {
+	cfg        config.SentryConfig
+	serverName string
+	logger     *util.Logger
+	client     *http.Client
+	storeURL   string
+	auth       string
+	queue      chan sentryJob
+}
+
+// sentryJob is an encoded event to send, or (with flushed set) a marker
+// closed once the events queued before it are sent
+type sentryJob struct {
+	body    []byte
+	flushed chan struct{}
+}
+
+// NewSentryReporter returns a SentryReporter for cfg, and starts its worker.
+// Register its Report method with API.RegisterErrorReporter.
+func NewSentryReporter(cfg config.SentryConfig, serverName string, logger *util.Logger) (*SentryReporter, error) {
+	dsn, err := config.ParseSentryDSN(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &SentryReporter{
+		cfg:        cfg,
+		serverName: serverName,
+		logger:     logger,
+		client:     &http.Client{Timeout: sentryTimeout},
+		storeURL:   dsn.StoreURL,
+		auth:       fmt.Sprintf("Sentry sentry_version=7, sentry_client=go-actionhero/1.0, sentry_key=%s", dsn.PublicKey),
+		queue:      make(chan sentryJob, sentryQueueSize),
+	}
+	go r.work()
+	return r, nil
+}
+
+// Report queues an error to be sent, dropping it when the queue is full. It
+// is an ErrorReporter.
+func (r *SentryReporter) Report(_ context.Context, report ErrorReport) {
+	if r.cfg.SampleRate < 1 && rand.Float64() >= r.cfg.SampleRate {
+		return
+	}
+
+	body, err := json.Marshal(newSentryEvent(r.cfg, r.serverName, report))
+	if err != nil {
+		r.logger.Warnf("Failed to encode Sentry event: %v", err)
+		return
+	}
+
+	select {
+	case r.queue <- sentryJob{body: body}:
+	default:
+		if r.logger.AllowError("sentry: queue full") {
+			r.logger.Warnf("Sentry queue is full (%d events); dropping error report", sentryQueueSize)
+		}
+	}
+}
+
+// Flush waits up to timeout for the queued events to be sent, returning
+// whether they were
+func (r *SentryReporter) Flush(timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	flushed := make(chan struct{})
+	select {
+	case r.queue <- sentryJob{flushed: flushed}:
+	case <-timer.C:
+		return false
+	}
+	select {
+	case <-flushed:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// work sends the queued events, one at a time
+func (r *SentryReporter) work() {
+	for job := range r.queue {
+		if job.flushed != nil {
+			close(job.flushed)
+			continue
+		}
+		r.send(job.body)
+	}
+}
+
+// send posts an encoded event to Sentry
+func (r *SentryReporter) send(body []byte) {
+	req, err := http.NewRequest(http.MethodPost, r.storeURL, bytes.NewReader(body))
+	if err != nil {
+		r.logger.Warnf("Failed to create Sentry request: %v", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", r.auth)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		r.logger.Warnf("Failed to send error to Sentry: %v", err)
+		return
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		r.logger.Warnf("Sentry rejected error report: %s", resp.Status)
+	}
+}
+
+// newSentryEvent converts an ErrorReport into a Sentry event
+func newSentryEvent(cfg config.SentryConfig, serverName string, report ErrorReport) sentryEvent {
+	if serverName == "" {
+		serverName, _ = os.Hostname()
+	}
+
+	message := ""
+	if report.Error != nil {
+		message = report.Error.Error()
+	}
+
+	tags := map[string]string{
+		"source":     report.Source,
+		"error_type": string(report.Type),
+	}
+	if report.Action != "" {
+		tags["action"] = report.Action
+	}
+	if report.RequestID != "" {
+		tags["request_id"] = report.RequestID
+	}
+
+	extra := map[string]interface{}{}
+	if report.Connection != nil {
+		tags["connection_type"] = report.Connection.Type
+		extra["connection_id"] = report.Connection.ID
+		extra["connection_identifier"] = report.Connection.Identifier
+	}
+	if report.Params != nil {
+		extra["params"] = redactParams(report.Params)
+	}
+	if report.Stack != "" {
+		extra["stack"] = report.Stack
+	}
+
+	level := "error"
+	if report.Source == ErrorSourcePanic {
+		level = "fatal"
+	}
+
+	return sentryEvent{
+		EventID:     strings.ReplaceAll(uuid.New().String(), "-", ""),
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+		Level:       level,
+		Platform:    "go",
+		Logger:      "actionhero",
+		ServerName:  serverName,
+		Environment: cfg.Environment,
+		Message:     message,
+		Exception: &sentryExceptionList{Values: []sentryException{{
+			Type:  string(report.Type),
+			Value: message,
+		}}},
+		Tags:  tags,
+		Extra: extra,
+	}
+}
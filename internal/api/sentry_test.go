@@ -0,0 +1,142 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+func TestSentryReporter_SendsEvent(t *testing.T) {
+	type received struct {
+		path  string
+		auth  string
+		event sentryEvent
+	}
+	requests := make(chan received, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event sentryEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("Failed to decode event: %v", err)
+		}
+		requests <- received{path: r.URL.Path, auth: r.Header.Get("X-Sentry-Auth"), event: event}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	dsn := strings.Replace(server.URL, "http://", "http://publickey@", 1) + "/42"
+	logger := util.NewLogger(config.LoggerConfig{Level: "fatal"})
+	reporter, err := NewSentryReporter(config.SentryConfig{DSN: dsn, Environment: "test", SampleRate: 1}, "test-server", logger)
+	if err != nil {
+		t.Fatalf("Failed to create reporter: %v", err)
+	}
+
+	reporter.Report(context.Background(), ErrorReport{
+		Error:      errors.New("something broke"),
+		Type:       util.ErrorTypeConnectionActionRun,
+		Source:     ErrorSourceAction,
+		Action:     "user:create",
+		Connection: NewConnection("web", "127.0.0.1", "conn-id", nil),
+		Params: map[string]interface{}{
+			"email":    "mario@example.com",
+			"password": "its-a-me!",
+			"payment":  map[string]interface{}{"cardToken": "tok_123"},
+		},
+	})
+
+	select {
+	case req := <-requests:
+		if req.path != "/api/42/store/" {
+			t.Errorf("Expected store path, got %s", req.path)
+		}
+		if !strings.Contains(req.auth, "sentry_key=publickey") {
+			t.Errorf("Expected auth header with key, got %s", req.auth)
+		}
+		if req.event.Message != "something broke" {
+			t.Errorf("Expected message 'something broke', got %q", req.event.Message)
+		}
+		if req.event.Environment != "test" || req.event.ServerName != "test-server" {
+			t.Errorf("Unexpected environment/server: %s/%s", req.event.Environment, req.event.ServerName)
+		}
+		if req.event.Tags["action"] != "user:create" || req.event.Tags["connection_type"] != "web" {
+			t.Errorf("Unexpected tags: %v", req.event.Tags)
+		}
+		params, _ := req.event.Extra["params"].(map[string]interface{})
+		payment, _ := params["payment"].(map[string]interface{})
+		if params["email"] != "mario@example.com" || params["password"] != "*********" || payment["cardToken"] != "*******" {
+			t.Errorf("Expected secret params to be masked, got %v", params)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for Sentry event")
+	}
+}
+
+func TestSentryReporter_ZeroSampleRate(t *testing.T) {
+	requests := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- struct{}{}
+	}))
+	defer server.Close()
+
+	dsn := strings.Replace(server.URL, "http://", "http://publickey@", 1) + "/42"
+	logger := util.NewLogger(config.LoggerConfig{Level: "fatal"})
+	reporter, err := NewSentryReporter(config.SentryConfig{DSN: dsn, SampleRate: 0}, "", logger)
+	if err != nil {
+		t.Fatalf("Failed to create reporter: %v", err)
+	}
+
+	reporter.Report(context.Background(), ErrorReport{Error: errors.New("dropped")})
+
+	select {
+	case <-requests:
+		t.Error("Expected no event with a sample rate of 0")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestSentryReporter_BoundedQueue(t *testing.T) {
+	release := make(chan struct{})
+	var received atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		received.Add(1)
+	}))
+	defer server.Close()
+
+	dsn := strings.Replace(server.URL, "http://", "http://publickey@", 1) + "/42"
+	logger := util.NewLogger(config.LoggerConfig{Level: "fatal"})
+	reporter, err := NewSentryReporter(config.SentryConfig{DSN: dsn, SampleRate: 1}, "", logger)
+	if err != nil {
+		t.Fatalf("Failed to create reporter: %v", err)
+	}
+
+	// The worker holds one event while Sentry is stuck; the queue holds the rest
+	goroutines := runtime.NumGoroutine()
+	for i := 0; i < sentryQueueSize*3; i++ {
+		reporter.Report(context.Background(), ErrorReport{Error: errors.New("burst")})
+	}
+	if extra := runtime.NumGoroutine() - goroutines; extra > 5 {
+		t.Errorf("Expected reporting not to start a goroutine per error, %d more running", extra)
+	}
+	if reporter.Flush(50 * time.Millisecond) {
+		t.Error("Expected the flush to time out while Sentry is stuck")
+	}
+
+	close(release)
+	if !reporter.Flush(5 * time.Second) {
+		t.Fatal("Expected the queued events to be sent")
+	}
+	if sent := int(received.Load()); sent < sentryQueueSize || sent > sentryQueueSize+1 {
+		t.Errorf("Expected the events beyond the queue to be dropped, %d sent", sent)
+	}
+}
@@ -1,5 +1,7 @@
 package api
 
+import "crypto/tls"
+
 // Server is the interface that all servers must implement
 type Server interface {
 	// Name returns the unique name of the server
@@ -14,3 +16,32 @@ type Server interface {
 	// Stop stops the server gracefully
 	Stop() error
 }
+
+// ConnectionCounter is implemented by servers that hold long-lived connections
+type ConnectionCounter interface {
+	// ConnectionCount returns the number of currently open connections
+	ConnectionCount() int
+}
+
+// ServerAddresses is implemented by servers that listen on network addresses
+type ServerAddresses interface {
+	// Addresses returns where the server accepts connections (e.g., "http://localhost:8080")
+	Addresses() []string
+}
+
+// CertificateSource provides TLS certificates to servers, in place of
+// certificate files (e.g., an ACME manager's GetCertificate). Provide one
+// before the servers are initialized:
+//
+//	api.Provide[api.CertificateSource](a, manager.GetCertificate)
+type CertificateSource func(hello *tls.ClientHelloInfo) (*tls.Certificate, error)
+
+// Broadcaster sends a message to every connection subscribed to a channel.
+// The web server provides itself as the Broadcaster when it is initialized,
+// unless one was already provided, so actions can publish:
+//
+//	broadcaster, err := api.Resource[api.Broadcaster](ctx)
+//	err = broadcaster.Broadcast("chat:lobby", message)
+type Broadcaster interface {
+	Broadcast(channel string, data interface{}) error
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+)
+
+// ServerFactory creates a server for the API
+type ServerFactory func(a *API) Server
+
+var (
+	serverFactories   = make(map[string]ServerFactory)
+	serverFactoriesMu sync.RWMutex
+)
+
+// RegisterServerFactory registers how to create the server called name, so
+// RegisterConfiguredServers creates it unless server.<name>.enabled is false.
+// The web server registers itself as "web". Register servers in init(),
+// before the config is loaded, so their enabled setting is read.
+func RegisterServerFactory(name string, factory ServerFactory) error {
+	if name != config.WebServerName {
+		if err := config.RegisterServer(name); err != nil {
+			return err
+		}
+	}
+
+	serverFactoriesMu.Lock()
+	defer serverFactoriesMu.Unlock()
+	if _, exists := serverFactories[name]; exists {
+		return fmt.Errorf("server %q is already registered", name)
+	}
+	serverFactories[name] = factory
+	return nil
+}
+
+// RegisteredServerNames returns the names of the servers registered with
+// RegisterServerFactory, the web server first and the rest sorted
+func RegisteredServerNames() []string {
+	serverFactoriesMu.RLock()
+	defer serverFactoriesMu.RUnlock()
+	names := make([]string, 0, len(serverFactories))
+	for name := range serverFactories {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == config.WebServerName) != (names[j] == config.WebServerName) {
+			return names[i] == config.WebServerName
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// RegisterConfiguredServers creates and registers each server registered
+// with RegisterServerFactory that is enabled in the config (see
+// ServerConfig.IsEnabled)
+func (a *API) RegisterConfiguredServers() {
+	for _, name := range RegisteredServerNames() {
+		if a.Config != nil && !a.Config.Server.IsEnabled(name) {
+			a.Logger.Infof("Server %s is disabled", name)
+			continue
+		}
+
+		serverFactoriesMu.RLock()
+		factory := serverFactories[name]
+		serverFactoriesMu.RUnlock()
+		a.RegisterServer(factory(a))
+	}
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+func TestRegisterConfiguredServers(t *testing.T) {
+	for _, name := range []string{"registrya", "registryb"} {
+		if err := RegisterServerFactory(name, func(*API) Server { return &mockServer{name: name} }); err != nil {
+			t.Fatalf("Failed to register server: %v", err)
+		}
+	}
+	if err := RegisterServerFactory("registrya", func(*API) Server { return &mockServer{} }); err == nil {
+		t.Error("Expected registering a server twice to fail")
+	}
+
+	cfg := &config.Config{Server: config.ServerConfig{Enabled: map[string]bool{"registryb": false}}}
+	a := New(cfg, util.NewLogger(config.LoggerConfig{Level: "error"}))
+	a.RegisterConfiguredServers()
+
+	var names []string
+	for _, server := range a.GetServers() {
+		names = append(names, server.Name())
+	}
+	if len(names) != 1 || names[0] != "registrya" {
+		t.Errorf("Expected only the enabled server, got %v", names)
+	}
+}
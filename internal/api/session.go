@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+)
+
+// SessionCookie returns the cookie carrying a session ID, with the name,
+// lifetime, and attributes from the session config. Every transport that
+// sets the session cookie should use it, so the attributes are consistent.
+func SessionCookie(cfg config.SessionConfig, sessionID string) *http.Cookie {
+	return &http.Cookie{
+		Name:     cfg.CookieName,
+		Value:    sessionID,
+		Path:     cfg.Path,
+		Domain:   cfg.Domain,
+		MaxAge:   int(cfg.TTL.Seconds()),
+		Secure:   cfg.Secure,
+		HttpOnly: cfg.HTTPOnly,
+		SameSite: sameSite(cfg.SameSite),
+	}
+}
+
+// ExpiredSessionCookie returns a cookie that removes the session cookie
+// (e.g., on sign out). Its path and domain must match the session cookie's.
+func ExpiredSessionCookie(cfg config.SessionConfig) *http.Cookie {
+	cookie := SessionCookie(cfg, "")
+	cookie.MaxAge = -1
+	return cookie
+}
+
+// sameSite converts a session.samesite value to its cookie mode
+func sameSite(mode string) http.SameSite {
+	switch mode {
+	case config.SameSiteStrict:
+		return http.SameSiteStrictMode
+	case config.SameSiteNone:
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
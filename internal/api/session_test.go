@@ -0,0 +1,42 @@
+package api
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+)
+
+func TestSessionCookie(t *testing.T) {
+	cfg := config.DefaultSessionConfig()
+	cfg.TTL = time.Hour
+	cfg.SameSite = config.SameSiteNone
+	cfg.Secure = true
+	cfg.Domain = "example.com"
+	cfg.Path = "/app"
+
+	cookie := SessionCookie(cfg, "abc123")
+	header := cookie.String()
+	for _, attr := range []string{"actionhero=abc123", "Path=/app", "Domain=example.com", "Max-Age=3600", "HttpOnly", "Secure", "SameSite=None"} {
+		if !strings.Contains(header, attr) {
+			t.Errorf("Expected %q in the cookie, got %s", attr, header)
+		}
+	}
+
+	cfg = config.DefaultSessionConfig()
+	header = SessionCookie(cfg, "abc123").String()
+	if !strings.Contains(header, "SameSite=Lax") || strings.Contains(header, "Secure") {
+		t.Errorf("Expected a lax, non-secure cookie by default, got %s", header)
+	}
+}
+
+func TestExpiredSessionCookie(t *testing.T) {
+	cfg := config.DefaultSessionConfig()
+	cfg.Domain = "example.com"
+
+	header := ExpiredSessionCookie(cfg).String()
+	if !strings.Contains(header, "actionhero=;") || !strings.Contains(header, "Max-Age=0") || !strings.Contains(header, "Domain=example.com") {
+		t.Errorf("Expected a cookie removing the session, got %s", header)
+	}
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// startupStep initializes or starts one component
+type startupStep struct {
+	label string // e.g. "statsd" or "server web", as used in errors
+	after []int  // Steps that must succeed before this one runs
+	run   func() error
+}
+
+// initializerSteps builds the steps for ordered initializers. Each waits for
+// its dependencies and for every earlier initializer with a lower priority,
+// so running them in parallel keeps the serial order's guarantees.
+func initializerSteps(initializers []Initializer, run func(Initializer) error) []startupStep {
+	index := make(map[string]int, len(initializers))
+	steps := make([]startupStep, len(initializers))
+	for i, initializer := range initializers {
+		initializer := initializer
+		index[initializer.Name()] = i
+		steps[i] = startupStep{label: initializer.Name(), run: func() error { return run(initializer) }}
+
+		for j := 0; j < i; j++ {
+			if initializers[j].Priority() < initializer.Priority() {
+				steps[i].after = append(steps[i].after, j)
+			}
+		}
+		if deps, ok := initializer.(InitializerDependencies); ok {
+			for _, dep := range deps.DependsOn() {
+				if j, ok := index[dep]; ok {
+					steps[i].after = append(steps[i].after, j)
+				}
+			}
+		}
+	}
+	return steps
+}
+
+// serverSteps builds independent steps for servers
+func serverSteps(servers []Server, run func(Server) error) []startupStep {
+	steps := make([]startupStep, len(servers))
+	for i, server := range servers {
+		server := server
+		steps[i] = startupStep{label: "server " + server.Name(), run: func() error { return run(server) }}
+	}
+	return steps
+}
+
+// runStartupSteps runs steps (verb is "initialize" or "start"). Serially, the
+// first failure stops the run. With process.parallelstartup, steps run as
+// soon as the steps they wait for succeed, and every failure is reported
+// together. Either way, each step is bounded by process.startuptimeout.
+func (a *API) runStartupSteps(verb string, steps []startupStep) error {
+	var parallel bool
+	var timeout time.Duration
+	if a.Config != nil {
+		parallel = a.Config.Process.ParallelStartup
+		timeout = a.Config.Process.StartupTimeout
+	}
+
+	if !parallel {
+		for _, step := range steps {
+			if err := runStartupStep(verb, step, timeout); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	done := make([]chan struct{}, len(steps))
+	failed := make([]bool, len(steps))
+	errs := make([]error, len(steps))
+	for i := range steps {
+		done[i] = make(chan struct{})
+	}
+
+	var wg sync.WaitGroup
+	for i, step := range steps {
+		wg.Add(1)
+		go func(i int, step startupStep) {
+			defer wg.Done()
+			defer close(done[i])
+
+			for _, j := range step.after {
+				<-done[j]
+				if failed[j] {
+					// Skipped: the failure that caused it is reported
+					failed[i] = true
+					return
+				}
+			}
+			if err := runStartupStep(verb, step, timeout); err != nil {
+				failed[i] = true
+				errs[i] = err
+			}
+		}(i, step)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// runStartupStep runs a step, giving up on it after timeout (0 = no limit).
+// A step that times out keeps running in the background.
+func runStartupStep(verb string, step startupStep, timeout time.Duration) error {
+	var err error
+	if timeout <= 0 {
+		err = step.run()
+	} else {
+		result := make(chan error, 1)
+		go func() { result <- step.run() }()
+
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case err = <-result:
+		case <-timer.C:
+			err = fmt.Errorf("timed out after %s", timeout)
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to %s %s: %w", verb, step.label, err)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// timedInitializer sleeps while initializing and records when it ran
+type timedInitializer struct {
+	name     string
+	priority int
+	deps     []string
+	delay    time.Duration
+	err      error
+
+	mu       sync.Mutex
+	started  time.Time
+	finished time.Time
+}
+
+func (t *timedInitializer) Name() string        { return t.name }
+func (t *timedInitializer) Priority() int       { return t.priority }
+func (t *timedInitializer) DependsOn() []string { return t.deps }
+func (t *timedInitializer) Start(*API) error    { return nil }
+func (t *timedInitializer) Stop(*API) error     { return nil }
+
+func (t *timedInitializer) Initialize(*API) error {
+	t.mu.Lock()
+	t.started = time.Now()
+	t.mu.Unlock()
+
+	time.Sleep(t.delay)
+
+	t.mu.Lock()
+	t.finished = time.Now()
+	t.mu.Unlock()
+	return t.err
+}
+
+func (t *timedInitializer) ran() (started, finished time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.started, t.finished
+}
+
+func newStartupAPI(parallel bool, timeout time.Duration) *API {
+	cfg := &config.Config{Process: config.ProcessConfig{ParallelStartup: parallel, StartupTimeout: timeout}}
+	return New(cfg, util.NewLogger(config.LoggerConfig{Level: "error"}))
+}
+
+func TestParallelStartup_RunsIndependentInitializersTogether(t *testing.T) {
+	api := newStartupAPI(true, 0)
+	a := &timedInitializer{name: "a", priority: 10, delay: 50 * time.Millisecond}
+	b := &timedInitializer{name: "b", priority: 10, delay: 50 * time.Millisecond}
+	api.RegisterInitializer(a)
+	api.RegisterInitializer(b)
+
+	start := time.Now()
+	if err := api.Initialize(); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= 90*time.Millisecond {
+		t.Errorf("Expected a and b to initialize together, took %v", elapsed)
+	}
+}
+
+func TestParallelStartup_RespectsOrdering(t *testing.T) {
+	api := newStartupAPI(true, 0)
+	db := &timedInitializer{name: "db", priority: 100, delay: 30 * time.Millisecond}
+	cache := &timedInitializer{name: "cache", priority: 100, deps: []string{"db"}}
+	early := &timedInitializer{name: "early", priority: 1, delay: 30 * time.Millisecond}
+	for _, initializer := range []Initializer{db, cache, early} {
+		api.RegisterInitializer(initializer)
+	}
+
+	if err := api.Initialize(); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	_, earlyFinished := early.ran()
+	dbStarted, dbFinished := db.ran()
+	cacheStarted, _ := cache.ran()
+	if dbStarted.Before(earlyFinished) {
+		t.Error("Expected db to wait for the lower priority initializer")
+	}
+	if cacheStarted.Before(dbFinished) {
+		t.Error("Expected cache to wait for its dependency")
+	}
+}
+
+func TestParallelStartup_AggregatesErrors(t *testing.T) {
+	api := newStartupAPI(true, 0)
+	api.RegisterInitializer(&timedInitializer{name: "a", priority: 10, err: errors.New("a broke")})
+	api.RegisterInitializer(&timedInitializer{name: "b", priority: 10, err: errors.New("b broke")})
+	dependent := &timedInitializer{name: "c", priority: 10, deps: []string{"a"}}
+	api.RegisterInitializer(dependent)
+
+	err := api.Initialize()
+	if err == nil {
+		t.Fatal("Expected Initialize to fail")
+	}
+	for _, expected := range []string{"failed to initialize a: a broke", "failed to initialize b: b broke"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected error to contain %q, got %v", expected, err)
+		}
+	}
+	if started, _ := dependent.ran(); !started.IsZero() {
+		t.Error("Expected the dependent of a failed initializer to be skipped")
+	}
+}
+
+func TestStartupTimeout(t *testing.T) {
+	for _, parallel := range []bool{false, true} {
+		api := newStartupAPI(parallel, 10*time.Millisecond)
+		api.RegisterInitializer(&timedInitializer{name: "slow", delay: 200 * time.Millisecond})
+
+		start := time.Now()
+		err := api.Initialize()
+		if err == nil || !strings.Contains(err.Error(), "failed to initialize slow: timed out after 10ms") {
+			t.Errorf("Expected a timeout error (parallel=%v), got %v", parallel, err)
+		}
+		if elapsed := time.Since(start); elapsed >= 150*time.Millisecond {
+			t.Errorf("Expected Initialize to give up at the timeout (parallel=%v), took %v", parallel, elapsed)
+		}
+	}
+}
+
+func TestSerialStartup_StopsAtFirstError(t *testing.T) {
+	api := newStartupAPI(false, 0)
+	api.RegisterInitializer(&timedInitializer{name: "a", priority: 1, err: errors.New("a broke")})
+	later := &timedInitializer{name: "b", priority: 2}
+	api.RegisterInitializer(later)
+
+	if err := api.Initialize(); err == nil || err.Error() != "failed to initialize a: a broke" {
+		t.Errorf("Expected a's error, got %v", err)
+	}
+	if started, _ := later.ran(); !started.IsZero() {
+		t.Error("Expected later initializers not to run after a failure")
+	}
+}
@@ -0,0 +1,193 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// TaskQueue describes a task queue and how many tasks are in it
+type TaskQueue struct {
+	Name    string `json:"name"`
+	Pending int    `json:"pending"` // Tasks waiting to run
+	Failed  int    `json:"failed"`  // Tasks that failed and can be retried
+}
+
+// FailedTask is a task that failed, kept so it can be inspected and retried
+type FailedTask struct {
+	ID       string                 `json:"id"`
+	Queue    string                 `json:"queue"`
+	Task     string                 `json:"task"`
+	Params   map[string]interface{} `json:"params,omitempty"`
+	Error    string                 `json:"error"`
+	FailedAt time.Time              `json:"failedAt"`
+}
+
+// TaskQueues reports on task queues and retries failed tasks. A task backend
+// provides one so the admin dashboard can show its queues:
+//
+//	api.Provide[api.TaskQueues](a, queues)
+type TaskQueues interface {
+	Queues(ctx context.Context) ([]TaskQueue, error)
+	FailedTasks(ctx context.Context, limit int) ([]FailedTask, error)
+	RetryTask(ctx context.Context, id string) error
+}
+
+// ErrTasksDisabled is returned when enqueuing tasks while no task backend is
+// set (e.g., by the tasks initializer, when tasks.enabled is set)
+var ErrTasksDisabled = errors.New("tasks are not enabled (tasks.enabled)")
+
+// Task is a job queued to run an action in the background
+type Task struct {
+	ID         string                 `json:"id"`
+	Action     string                 `json:"action"`
+	Queue      string                 `json:"queue"`
+	Params     map[string]interface{} `json:"params,omitempty"`
+	EnqueuedAt time.Time              `json:"enqueuedAt"`
+	RunAt      time.Time              `json:"runAt,omitzero"` // When a delayed task is due (zero = right away)
+}
+
+// TaskBackend stores queued tasks until a worker runs them. The tasks
+// initializer sets one (see Tasks.SetBackend).
+type TaskBackend interface {
+	// Enqueue adds a task to the end of queue
+	Enqueue(ctx context.Context, action string, params map[string]interface{}, queue string) (*Task, error)
+	// EnqueueAt keeps a task until at, then adds it to the end of queue
+	EnqueueAt(ctx context.Context, at time.Time, action string, params map[string]interface{}, queue string) (*Task, error)
+}
+
+// Tasks queues actions to run as background tasks, from actions and
+// initializers alike:
+//
+//	task, err := api.APIFromContext(ctx).Tasks.Enqueue(ctx, "user:welcomeEmail", params, "")
+type Tasks struct {
+	api *API
+
+	mu      sync.RWMutex
+	backend TaskBackend
+}
+
+// SetBackend sets where tasks are queued (nil disables enqueuing)
+func (t *Tasks) SetBackend(backend TaskBackend) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.backend = backend
+}
+
+// Enqueue queues the task action to run with params (a map or a struct,
+// serialized as JSON) on queue. Without a queue, the action's ActionTask
+// queue is used, or else the first of tasks.queues. Errors are
+// util.TypedErrors: CONNECTION_ACTION_NOT_FOUND for an unknown action, and
+// TASK_ENQUEUE otherwise.
+func (t *Tasks) Enqueue(ctx context.Context, action string, params interface{}, queue string) (*Task, error) {
+	return t.enqueue(ctx, time.Time{}, action, params, queue)
+}
+
+// EnqueueIn queues the task action like Enqueue, to run once delay has
+// passed
+func (t *Tasks) EnqueueIn(ctx context.Context, delay time.Duration, action string, params interface{}, queue string) (*Task, error) {
+	return t.enqueue(ctx, time.Now().Add(delay), action, params, queue)
+}
+
+// EnqueueAt queues the task action like Enqueue, to run once at has passed.
+// Delayed tasks are kept by the backend, and moved to their queue when due
+// by a running task worker; a time already past queues the task right away.
+func (t *Tasks) EnqueueAt(ctx context.Context, at time.Time, action string, params interface{}, queue string) (*Task, error) {
+	return t.enqueue(ctx, at, action, params, queue)
+}
+
+// enqueue queues the task action, to run once at has passed
+func (t *Tasks) enqueue(ctx context.Context, at time.Time, action string, params interface{}, queue string) (*Task, error) {
+	descriptor, ok := t.api.GetActionDescriptor(action)
+	if !ok {
+		return nil, util.NewTypedError(util.ErrorTypeConnectionActionNotFound,
+			fmt.Sprintf("action not found: %s", action), util.WithKey(action))
+	}
+	if descriptor.Task == nil {
+		return nil, util.NewTypedError(util.ErrorTypeTaskEnqueue,
+			fmt.Sprintf("action %s is not a task (it has no ActionTask)", action), util.WithKey(action))
+	}
+
+	queues := t.api.Config.Tasks.Queues
+	if queue == "" {
+		queue = descriptor.Task.Queue
+	}
+	if queue == "" && len(queues) > 0 {
+		queue = queues[0]
+	}
+	if !slices.Contains(queues, queue) {
+		return nil, util.NewTypedError(util.ErrorTypeTaskEnqueue,
+			fmt.Sprintf("queue %q of task %s is not one of tasks.queues", queue, action),
+			util.WithKey(action), util.WithValue(queue))
+	}
+
+	taskParams, err := encodeTaskParams(params)
+	if err != nil {
+		return nil, util.NewTypedError(util.ErrorTypeTaskEnqueue,
+			fmt.Sprintf("params of task %s can't be serialized", action),
+			util.WithKey(action), util.WithOriginalError(err))
+	}
+
+	t.mu.RLock()
+	backend := t.backend
+	t.mu.RUnlock()
+	if backend == nil {
+		return nil, util.NewTypedError(util.ErrorTypeTaskEnqueue,
+			fmt.Sprintf("can't enqueue task %s", action),
+			util.WithKey(action), util.WithOriginalError(ErrTasksDisabled))
+	}
+
+	var task *Task
+	if !at.After(time.Now()) {
+		task, err = backend.Enqueue(ctx, action, taskParams, queue)
+	} else {
+		task, err = backend.EnqueueAt(ctx, at, action, taskParams, queue)
+	}
+	if err != nil {
+		return nil, util.NewTypedError(util.ErrorTypeTaskEnqueue,
+			fmt.Sprintf("failed to enqueue task %s", action),
+			util.WithKey(action), util.WithOriginalError(err))
+	}
+	t.api.Emit(ctx, Event{Name: EventTaskEnqueued, Action: action, Params: taskParams})
+	return task, nil
+}
+
+// Enqueue queues the task action to run with params (see Tasks.Enqueue)
+func (a *API) Enqueue(ctx context.Context, action string, params interface{}, queue string) (*Task, error) {
+	return a.Tasks.Enqueue(ctx, action, params, queue)
+}
+
+// EnqueueIn queues the task action to run once delay has passed (see
+// Tasks.EnqueueIn)
+func (a *API) EnqueueIn(ctx context.Context, delay time.Duration, action string, params interface{}, queue string) (*Task, error) {
+	return a.Tasks.EnqueueIn(ctx, delay, action, params, queue)
+}
+
+// EnqueueAt queues the task action to run once at has passed (see
+// Tasks.EnqueueAt)
+func (a *API) EnqueueAt(ctx context.Context, at time.Time, action string, params interface{}, queue string) (*Task, error) {
+	return a.Tasks.EnqueueAt(ctx, at, action, params, queue)
+}
+
+// encodeTaskParams serializes params to a JSON object, as the task will
+// receive them
+func encodeTaskParams(params interface{}) (map[string]interface{}, error) {
+	if params == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	var encoded map[string]interface{}
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, fmt.Errorf("params must be an object: %w", err)
+	}
+	return encoded, nil
+}
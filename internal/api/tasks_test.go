@@ -0,0 +1,156 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// fakeTaskBackend records the tasks queued on it
+type fakeTaskBackend struct {
+	tasks []*Task
+	err   error
+}
+
+func (b *fakeTaskBackend) Enqueue(_ context.Context, action string, params map[string]interface{}, queue string) (*Task, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	task := &Task{ID: "task-1", Action: action, Queue: queue, Params: params}
+	b.tasks = append(b.tasks, task)
+	return task, nil
+}
+
+func (b *fakeTaskBackend) EnqueueAt(ctx context.Context, at time.Time, action string, params map[string]interface{}, queue string) (*Task, error) {
+	task, err := b.Enqueue(ctx, action, params, queue)
+	if task != nil {
+		task.RunAt = at
+	}
+	return task, err
+}
+
+func TestTasks_Enqueue(t *testing.T) {
+	cfg := &config.Config{Tasks: config.TasksConfig{Queues: []string{"default", "email"}}}
+	apiInstance := New(cfg, util.NewLogger(config.LoggerConfig{Level: "error"}))
+	for _, action := range []Action{
+		NewAction("user:welcomeEmail").Task(&TaskConfig{Queue: "email"}).Handler(func(context.Context, interface{}, *Connection) (interface{}, error) { return nil, nil }),
+		NewAction("user:cleanup").Task(&TaskConfig{}).Handler(func(context.Context, interface{}, *Connection) (interface{}, error) { return nil, nil }),
+		NewAction("user:view").Handler(func(context.Context, interface{}, *Connection) (interface{}, error) { return nil, nil }),
+	} {
+		if err := apiInstance.RegisterAction(action); err != nil {
+			t.Fatalf("Failed to register action: %v", err)
+		}
+	}
+
+	errorType := func(err error) util.ErrorType {
+		var typedErr *util.TypedError
+		if !errors.As(err, &typedErr) {
+			t.Fatalf("Expected a TypedError, got %v", err)
+		}
+		return typedErr.Type
+	}
+	ctx := context.Background()
+
+	t.Run("disabled", func(t *testing.T) {
+		_, err := apiInstance.Tasks.Enqueue(ctx, "user:welcomeEmail", nil, "")
+		if errorType(err) != util.ErrorTypeTaskEnqueue || !errors.Is(err, ErrTasksDisabled) {
+			t.Errorf("Expected ErrTasksDisabled, got %v", err)
+		}
+	})
+
+	backend := &fakeTaskBackend{}
+	apiInstance.Tasks.SetBackend(backend)
+	var enqueued []string
+	apiInstance.On(EventTaskEnqueued, func(_ context.Context, event Event) {
+		enqueued = append(enqueued, event.Action)
+	})
+
+	t.Run("queues", func(t *testing.T) {
+		for _, tt := range []struct{ action, queue, want string }{
+			{"user:welcomeEmail", "", "email"},          // The action's queue
+			{"user:cleanup", "", "default"},             // The first configured queue
+			{"user:welcomeEmail", "default", "default"}, // The given queue
+		} {
+			task, err := apiInstance.Tasks.Enqueue(ctx, tt.action, nil, tt.queue)
+			if err != nil {
+				t.Fatalf("Failed to enqueue %s: %v", tt.action, err)
+			}
+			if task.Queue != tt.want {
+				t.Errorf("Expected %s on %s, got %s", tt.action, tt.want, task.Queue)
+			}
+		}
+		if len(enqueued) != 3 || enqueued[0] != "user:welcomeEmail" {
+			t.Errorf("Expected a task:enqueued event per task, got %v", enqueued)
+		}
+	})
+
+	t.Run("params are serialized", func(t *testing.T) {
+		type welcome struct {
+			UserID int    `json:"userId"`
+			Name   string `json:"name,omitempty"`
+		}
+		task, err := apiInstance.Tasks.Enqueue(ctx, "user:welcomeEmail", welcome{UserID: 7}, "")
+		if err != nil {
+			t.Fatalf("Failed to enqueue: %v", err)
+		}
+		if len(task.Params) != 1 || task.Params["userId"] != 7.0 {
+			t.Errorf("Expected the params as JSON, got %v", task.Params)
+		}
+	})
+
+	t.Run("delayed", func(t *testing.T) {
+		before := time.Now()
+		task, err := apiInstance.Tasks.EnqueueIn(ctx, time.Hour, "user:welcomeEmail", nil, "")
+		if err != nil {
+			t.Fatalf("Failed to enqueue: %v", err)
+		}
+		if task.Queue != "email" || task.RunAt.Before(before.Add(time.Hour)) || task.RunAt.After(time.Now().Add(time.Hour)) {
+			t.Errorf("Expected the task due in an hour on its queue, got %+v", task)
+		}
+
+		at := time.Now().Add(24 * time.Hour)
+		if task, err := apiInstance.EnqueueAt(ctx, at, "user:cleanup", nil, ""); err != nil || !task.RunAt.Equal(at) {
+			t.Errorf("Expected the task due at %v, got %+v (%v)", at, task, err)
+		}
+
+		// Times already past queue the task right away
+		if task, err := apiInstance.EnqueueAt(ctx, time.Now().Add(-time.Minute), "user:cleanup", nil, ""); err != nil || !task.RunAt.IsZero() {
+			t.Errorf("Expected the task queued right away, got %+v (%v)", task, err)
+		}
+
+		// Delayed tasks are checked like the others
+		if _, err := apiInstance.EnqueueIn(ctx, time.Hour, "user:view", nil, ""); errorType(err) != util.ErrorTypeTaskEnqueue {
+			t.Errorf("Expected a TASK_ENQUEUE error for an action that isn't a task, got %v", err)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, tt := range []struct {
+			name   string
+			action string
+			params interface{}
+			queue  string
+			want   util.ErrorType
+		}{
+			{"unknown action", "user:missing", nil, "", util.ErrorTypeConnectionActionNotFound},
+			{"not a task", "user:view", nil, "", util.ErrorTypeTaskEnqueue},
+			{"unknown queue", "user:cleanup", nil, "reports", util.ErrorTypeTaskEnqueue},
+			{"params not an object", "user:cleanup", []int{1}, "", util.ErrorTypeTaskEnqueue},
+			{"params not serializable", "user:cleanup", map[string]interface{}{"f": func() {}}, "", util.ErrorTypeTaskEnqueue},
+		} {
+			if _, err := apiInstance.Tasks.Enqueue(ctx, tt.action, tt.params, tt.queue); errorType(err) != tt.want {
+				t.Errorf("%s: expected %s, got %v", tt.name, tt.want, err)
+			}
+		}
+
+		backend.err = errors.New("connection refused")
+		_, err := apiInstance.Tasks.Enqueue(ctx, "user:cleanup", nil, "")
+		if errorType(err) != util.ErrorTypeTaskEnqueue || !errors.Is(err, backend.err) {
+			t.Errorf("Expected the backend's error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// ContextKeyTenant carries the Tenant an action runs for
+const ContextKeyTenant ContextKey = "tenant"
+
+// tenantIDPattern is what tenant IDs may look like, so they are safe in
+// cache keys and database and schema names
+var tenantIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,62}$`)
+
+// Tenant is the customer a request is for, in a multi-tenant app (see
+// tenancy). Actions read it with TenantFromContext, and keep tenants' data
+// apart with it.
+type Tenant struct {
+	ID       string `json:"id"`
+	Database string `json:"database,omitempty"` // The tenant's database ("" = the shared one)
+	Schema   string `json:"schema,omitempty"`   // The tenant's schema ("" = the shared one)
+}
+
+// CacheKey returns key scoped to the tenant, so tenants don't share entries
+func (t *Tenant) CacheKey(key string) string {
+	return "tenant:" + t.ID + ":" + key
+}
+
+// TenantResolver finds the tenant of a request, returning "" when it has none.
+// Set API.TenantResolver to replace the one configured by tenancy.source.
+type TenantResolver interface {
+	ResolveTenant(r *http.Request) (string, error)
+}
+
+// TenantResolverFunc adapts a function to a TenantResolver
+type TenantResolverFunc func(r *http.Request) (string, error)
+
+// ResolveTenant calls f
+func (f TenantResolverFunc) ResolveTenant(r *http.Request) (string, error) {
+	return f(r)
+}
+
+// WithTenant returns a copy of ctx carrying tenant
+func WithTenant(ctx context.Context, tenant *Tenant) context.Context {
+	return context.WithValue(ctx, ContextKeyTenant, tenant)
+}
+
+// TenantFromContext returns the tenant an action is running for, or nil
+func TenantFromContext(ctx context.Context) *Tenant {
+	if ctx == nil {
+		return nil
+	}
+	tenant, _ := ctx.Value(ContextKeyTenant).(*Tenant)
+	return tenant
+}
+
+// TenantCacheKey returns key scoped to the tenant of ctx, or key itself when
+// there is none
+func TenantCacheKey(ctx context.Context, key string) string {
+	if tenant := TenantFromContext(ctx); tenant != nil {
+		return tenant.CacheKey(key)
+	}
+	return key
+}
+
+// ResolveTenant returns the tenant of a request, read from the source
+// configured by tenancy.source (or by API.TenantResolver), or nil when the
+// request has none. Missing tenants when tenancy.required is set, invalid
+// tenant IDs, and tokens that fail verification are
+// CONNECTION_TENANT_REQUIRED errors.
+func (a *API) ResolveTenant(r *http.Request) (*Tenant, error) {
+	cfg := config.DefaultTenancyConfig()
+	if a.Config != nil {
+		cfg = a.Config.Tenancy
+	}
+
+	var id string
+	var err error
+	if a.TenantResolver != nil {
+		id, err = a.TenantResolver.ResolveTenant(r)
+	} else {
+		id, err = resolveConfiguredTenant(r, cfg)
+	}
+	if err != nil {
+		return nil, tenantError(err.Error())
+	}
+
+	if id == "" {
+		if cfg.Required {
+			return nil, tenantError("a tenant is required")
+		}
+		return nil, nil
+	}
+	if !tenantIDPattern.MatchString(id) {
+		return nil, tenantError(fmt.Sprintf("invalid tenant %q", id))
+	}
+	return a.NewTenant(id), nil
+}
+
+// NewTenant returns the tenant id, with the database and schema given to it
+// by tenancy.database and tenancy.schema
+func (a *API) NewTenant(id string) *Tenant {
+	tenant := &Tenant{ID: id}
+	if a.Config != nil {
+		tenant.Database = strings.ReplaceAll(a.Config.Tenancy.Database, "{tenant}", id)
+		tenant.Schema = strings.ReplaceAll(a.Config.Tenancy.Schema, "{tenant}", id)
+	}
+	return tenant
+}
+
+// resolveConfiguredTenant reads a request's tenant from cfg's source
+func resolveConfiguredTenant(r *http.Request, cfg config.TenancyConfig) (string, error) {
+	switch cfg.Source {
+	case config.TenantSourceHeader:
+		return strings.TrimSpace(r.Header.Get(cfg.Header)), nil
+	case config.TenantSourceSubdomain:
+		return tenantSubdomain(r.Host, cfg.BaseDomain), nil
+	case config.TenantSourceClaim:
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			return "", nil
+		}
+		return tokenClaim(strings.TrimSpace(token), cfg.TokenSecret, cfg.Claim, time.Now())
+	}
+	return "", fmt.Errorf("unknown tenant source %q", cfg.Source)
+}
+
+// tenantSubdomain returns the subdomain of baseDomain that host is (e.g.,
+// "acme" for acme.example.com), or "" when host isn't one
+func tenantSubdomain(host, baseDomain string) string {
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	suffix := "." + strings.TrimSuffix(strings.ToLower(baseDomain), ".")
+	subdomain, ok := strings.CutSuffix(host, suffix)
+	if !ok {
+		return ""
+	}
+	return subdomain
+}
+
+// tokenClaim verifies an HS256 JWT with secret, and returns its claim
+func tokenClaim(token, secret, claim string, now time.Time) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", errors.New("malformed bearer token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeTokenPart(parts[0], &header); err != nil || header.Alg != "HS256" {
+		return "", errors.New("bearer token must be signed with HS256")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", errors.New("malformed bearer token signature")
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return "", errors.New("invalid bearer token signature")
+	}
+
+	var claims map[string]interface{}
+	if err := decodeTokenPart(parts[1], &claims); err != nil {
+		return "", errors.New("malformed bearer token claims")
+	}
+	if exp, ok := claims["exp"].(float64); ok && now.Unix() >= int64(exp) {
+		return "", errors.New("bearer token has expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Unix() < int64(nbf) {
+		return "", errors.New("bearer token is not valid yet")
+	}
+
+	switch value := claims[claim].(type) {
+	case nil:
+		return "", nil
+	case string:
+		return value, nil
+	case float64:
+		return fmt.Sprintf("%.0f", value), nil
+	default:
+		return "", fmt.Errorf("bearer token claim %s must be a string", claim)
+	}
+}
+
+// decodeTokenPart decodes a base64url JSON part of a JWT into v
+func decodeTokenPart(part string, v interface{}) error {
+	decoded, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(decoded, v)
+}
+
+// tenantError is the error of a request without a valid tenant
+func tenantError(message string) error {
+	return util.NewTypedError(util.ErrorTypeConnectionTenantRequired, message)
+}
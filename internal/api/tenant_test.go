@@ -0,0 +1,164 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// signToken returns an HS256 JWT of claims signed with secret
+func signToken(t *testing.T, secret string, claims map[string]interface{}) string {
+	t.Helper()
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("Failed to encode claims: %v", err)
+	}
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func newTenancyAPI(tenancy config.TenancyConfig) *API {
+	tenancy.Enabled = true
+	return New(&config.Config{Tenancy: tenancy}, util.NewLogger(config.LoggerConfig{Level: "error"}))
+}
+
+func TestAPI_ResolveTenant(t *testing.T) {
+	base := config.DefaultTenancyConfig()
+	subdomain := base
+	subdomain.Source = config.TenantSourceSubdomain
+	subdomain.BaseDomain = "example.com"
+	claim := base
+	claim.Source = config.TenantSourceClaim
+	claim.TokenSecret = "s3cret"
+	optional := base
+	optional.Required = false
+
+	tests := []struct {
+		name    string
+		tenancy config.TenancyConfig
+		host    string
+		header  string
+		auth    string
+		want    string
+		wantErr bool
+	}{
+		{"header", base, "", "acme", "", "acme", false},
+		{"missing header", base, "", "", "", "", true},
+		{"missing header, optional", optional, "", "", "", "", false},
+		{"invalid ID", base, "", "acme/../other", "", "", true},
+		{"subdomain", subdomain, "Acme.Example.com:8080", "", "", "acme", false},
+		{"base domain", subdomain, "example.com", "", "", "", true},
+		{"other domain", subdomain, "acme.other.com", "", "", "", true},
+		{"claim", claim, "", "", "Bearer " + signToken(t, "s3cret", map[string]interface{}{"tenant": "acme"}), "acme", false},
+		{"wrong signature", claim, "", "", "Bearer " + signToken(t, "other", map[string]interface{}{"tenant": "acme"}), "", true},
+		{"expired token", claim, "", "", "Bearer " + signToken(t, "s3cret", map[string]interface{}{"tenant": "acme", "exp": time.Now().Add(-time.Minute).Unix()}), "", true},
+		{"no token", claim, "", "", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/status", nil)
+			if tt.host != "" {
+				req.Host = tt.host
+			}
+			if tt.header != "" {
+				req.Header.Set("X-Tenant-ID", tt.header)
+			}
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+
+			tenant, err := newTenancyAPI(tt.tenancy).ResolveTenant(req)
+			if tt.wantErr {
+				typedErr, ok := err.(*util.TypedError)
+				if !ok || typedErr.Type != util.ErrorTypeConnectionTenantRequired {
+					t.Fatalf("Expected a CONNECTION_TENANT_REQUIRED error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveTenant failed: %v", err)
+			}
+			got := ""
+			if tenant != nil {
+				got = tenant.ID
+			}
+			if got != tt.want {
+				t.Errorf("Expected tenant %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAPI_ResolveTenant_CustomResolver(t *testing.T) {
+	apiInstance := newTenancyAPI(config.TenancyConfig{
+		Database: "app_{tenant}",
+		Schema:   "tenant_{tenant}",
+	})
+	apiInstance.TenantResolver = TenantResolverFunc(func(r *http.Request) (string, error) {
+		return r.URL.Query().Get("org"), nil
+	})
+
+	tenant, err := apiInstance.ResolveTenant(httptest.NewRequest("GET", "/api/status?org=acme", nil))
+	if err != nil {
+		t.Fatalf("ResolveTenant failed: %v", err)
+	}
+	want := Tenant{ID: "acme", Database: "app_acme", Schema: "tenant_acme"}
+	if tenant == nil || *tenant != want {
+		t.Errorf("Expected %+v, got %+v", want, tenant)
+	}
+}
+
+func TestConnection_Act_Tenant(t *testing.T) {
+	apiInstance, action := setupCachedAction(t, &CacheConfig{TTL: time.Minute})
+	var seen *Tenant
+	apiInstance.On(EventActionComplete, func(ctx context.Context, _ Event) {
+		seen = TenantFromContext(ctx)
+	})
+
+	act := func(tenantID string) ActResult {
+		conn := NewConnection("web", "127.0.0.1", "tenant-conn", nil)
+		conn.SetTenant(apiInstance.NewTenant(tenantID))
+		return conn.Act(context.Background(), apiInstance, "test:cached", map[string]interface{}{"id": "1"}, "GET", "")
+	}
+
+	if result := act("acme"); result.Error != nil || result.Cached {
+		t.Fatalf("Expected an uncached success, got %+v", result)
+	}
+	if seen == nil || seen.ID != "acme" {
+		t.Errorf("Expected the action to run for acme, got %+v", seen)
+	}
+
+	// Responses cached for one tenant aren't served to another
+	if result := act("globex"); result.Cached {
+		t.Error("Expected another tenant to miss the cache")
+	}
+	if result := act("acme"); !result.Cached {
+		t.Error("Expected the tenant's own response to be cached")
+	}
+	if action.runs.Load() != 2 {
+		t.Errorf("Expected the action to run once per tenant, ran %d times", action.runs.Load())
+	}
+}
+
+func TestTenantCacheKey(t *testing.T) {
+	ctx := context.Background()
+	if got := TenantCacheKey(ctx, "users:1"); got != "users:1" {
+		t.Errorf("Expected the key unchanged without a tenant, got %q", got)
+	}
+	ctx = WithTenant(ctx, &Tenant{ID: "acme"})
+	if got := TenantCacheKey(ctx, "users:1"); got != "tenant:acme:users:1" {
+		t.Errorf("Expected the key scoped to the tenant, got %q", got)
+	}
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+)
+
+// ContextKeyDecodedInput carries an action's input already decoded from its
+// request (see WithDecodedInput)
+const ContextKeyDecodedInput ContextKey = "decodedInput"
+
+// InputDecoder is implemented by actions with a typed input (see
+// NewTypedAction), so servers can decode a request body straight into the
+// input instead of converting the params map when the action runs
+type InputDecoder interface {
+	// DecodeInput decodes a JSON body holding all of the action's params
+	// into a new input
+	DecodeInput(body []byte) (interface{}, error)
+}
+
+// decodedInput is an input decoded from the request params were parsed from
+type decodedInput struct {
+	params map[string]interface{}
+	input  interface{}
+}
+
+// WithDecodedInput returns a copy of ctx carrying input, decoded by the
+// action's InputDecoder from the request params were parsed from. The typed
+// action runs with it when it is passed that same params map; when the
+// params were replaced (e.g., merged with a connection's sticky params), it
+// converts them as usual.
+func WithDecodedInput(ctx context.Context, params map[string]interface{}, input interface{}) context.Context {
+	return context.WithValue(ctx, ContextKeyDecodedInput, decodedInput{params: params, input: input})
+}
+
+// TypedAction is an action with typed input and output. Embed BaseAction for
+// its configuration, then register it with NewTypedAction:
+//
+//	type MyAction struct {
+//	    api.BaseAction
+//	}
+//
+//	func (a *MyAction) Run(ctx context.Context, input MyInput, conn *api.Connection) (MyOutput, error) {
+//	    return MyOutput{...}, nil
+//	}
+//
+//	api.NewTypedAction[MyInput, MyOutput](&MyAction{BaseAction: api.BaseAction{ActionName: "my:action"}})
+type TypedAction[In, Out any] interface {
+	// Run executes the action with params already converted to In
+	Run(ctx context.Context, input In, conn *Connection) (Out, error)
+}
+
+// TypedActionAdapter adapts a TypedAction to the Action interface
+type TypedActionAdapter[In, Out any] struct {
+	BaseAction
+	action TypedAction[In, Out]
+}
+
+// NewTypedAction adapts a TypedAction to the Action interface, so it can be
+// registered like any other action. The typed action's BaseAction
+// configuration is used; when ActionInputs or ActionOutputs aren't set, they
+// default to In and Out when those are structs (documenting the action's
+// schemas), and when
+// ActionName isn't set, it defaults to the typed action's type name.
+func NewTypedAction[In, Out any](action TypedAction[In, Out]) *TypedActionAdapter[In, Out] {
+	adapter := &TypedActionAdapter[In, Out]{action: action}
+
+	if configured, ok := action.(configuredAction); ok {
+		adapter.BaseAction = configured.baseAction()
+	}
+	if adapter.ActionName == "" {
+		t := reflect.TypeOf(action)
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		adapter.ActionName = t.Name()
+	}
+
+	var input In
+	if adapter.ActionInputs == nil && isStruct(input) {
+		adapter.ActionInputs = input
+	}
+	var output Out
+	if adapter.ActionOutputs == nil && isStruct(output) {
+		adapter.ActionOutputs = output
+	}
+	return adapter
+}
+
+// isStruct returns whether value is a struct (only structs document a schema)
+func isStruct(value interface{}) bool {
+	return value != nil && reflect.TypeOf(value).Kind() == reflect.Struct
+}
+
+// Run converts params to In and runs the typed action. An input decoded
+// from the request for these params (see WithDecodedInput) is used as is.
+func (a *TypedActionAdapter[In, Out]) Run(ctx context.Context, params interface{}, conn *Connection) (interface{}, error) {
+	if input, ok := a.decodedInput(ctx, params); ok {
+		return a.action.Run(ctx, *input, conn)
+	}
+	var input In
+	if err := MarshalParams(params, &input); err != nil {
+		return nil, err
+	}
+	return a.action.Run(ctx, input, conn)
+}
+
+// DecodeInput decodes a JSON body into a new *In
+func (a *TypedActionAdapter[In, Out]) DecodeInput(body []byte) (interface{}, error) {
+	input := new(In)
+	if err := json.Unmarshal(body, input); err != nil {
+		return nil, err
+	}
+	return input, nil
+}
+
+// decodedInput returns the input in ctx decoded for params, if there is one
+func (a *TypedActionAdapter[In, Out]) decodedInput(ctx context.Context, params interface{}) (*In, bool) {
+	decoded, ok := ctx.Value(ContextKeyDecodedInput).(decodedInput)
+	if !ok {
+		return nil, false
+	}
+	paramsMap, ok := params.(map[string]interface{})
+	if !ok || paramsMap == nil || reflect.ValueOf(paramsMap).UnsafePointer() != reflect.ValueOf(decoded.params).UnsafePointer() {
+		return nil, false
+	}
+	input, ok := decoded.input.(*In)
+	return input, ok
+}
+
+// Unwrap returns the adapted typed action
+func (a *TypedActionAdapter[In, Out]) Unwrap() TypedAction[In, Out] {
+	return a.action
+}
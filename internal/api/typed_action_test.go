@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+type greetInput struct {
+	Name  string `json:"name"`
+	Times int    `json:"times"`
+}
+
+type greetOutput struct {
+	Greeting string `json:"greeting"`
+}
+
+type greetAction struct {
+	BaseAction
+}
+
+func (a *greetAction) Run(_ context.Context, input greetInput, _ *Connection) (greetOutput, error) {
+	if input.Name == "" {
+		return greetOutput{}, errors.New("name is required")
+	}
+	greeting := ""
+	for i := 0; i < input.Times; i++ {
+		greeting += "hello " + input.Name + " "
+	}
+	return greetOutput{Greeting: greeting}, nil
+}
+
+func TestNewTypedAction(t *testing.T) {
+	action := NewTypedAction[greetInput, greetOutput](&greetAction{
+		BaseAction: BaseAction{ActionName: "greet", ActionDescription: "Greets"},
+	})
+
+	if GetActionName(action) != "greet" || GetActionDescription(action) != "Greets" {
+		t.Errorf("Expected the typed action's config, got %s: %s", GetActionName(action), GetActionDescription(action))
+	}
+	if _, ok := GetActionInputs(action).(greetInput); !ok {
+		t.Errorf("Expected inputs to default to the input type, got %T", GetActionInputs(action))
+	}
+	if _, ok := GetActionOutputs(action).(greetOutput); !ok {
+		t.Errorf("Expected outputs to default to the output type, got %T", GetActionOutputs(action))
+	}
+
+	result, err := action.Run(context.Background(), map[string]interface{}{"name": "Mario", "times": 2}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	output, ok := result.(greetOutput)
+	if !ok || output.Greeting != "hello Mario hello Mario " {
+		t.Errorf("Expected typed output, got %#v", result)
+	}
+
+	if _, err := action.Run(context.Background(), map[string]interface{}{}, nil); err == nil {
+		t.Error("Expected the typed action's error")
+	}
+	if _, err := action.Run(context.Background(), map[string]interface{}{"times": "many"}, nil); err == nil {
+		t.Error("Expected an error for params that don't match the input type")
+	}
+}
+
+func TestNewTypedAction_DefaultName(t *testing.T) {
+	action := NewTypedAction[greetInput, greetOutput](&greetAction{})
+	if GetActionName(action) != "greetAction" {
+		t.Errorf("Expected the type name, got %s", GetActionName(action))
+	}
+
+	api := New(&config.Config{}, util.NewLogger(config.DefaultLoggerConfig()))
+	if err := api.RegisterAction(action); err != nil {
+		t.Fatalf("Expected the adapter to register like any action, got %v", err)
+	}
+}
+
+func TestTypedActionAdapter_DecodedInput(t *testing.T) {
+	action := NewTypedAction[greetInput, greetOutput](&greetAction{BaseAction: BaseAction{ActionName: "greet"}})
+
+	decoded, err := action.DecodeInput([]byte(`{"name": "Luigi", "times": 1}`))
+	if err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	if input, ok := decoded.(*greetInput); !ok || input.Name != "Luigi" || input.Times != 1 {
+		t.Fatalf("Expected the body as the input, got %#v", decoded)
+	}
+	if _, err := action.DecodeInput([]byte(`{"times": "many"}`)); err == nil {
+		t.Error("Expected an error for a body that doesn't match the input type")
+	}
+
+	// The decoded input is used for the params it was decoded with
+	params := map[string]interface{}{"name": "Mario", "times": 1}
+	ctx := WithDecodedInput(context.Background(), params, decoded)
+	result, err := action.Run(ctx, params, nil)
+	if err != nil || result.(greetOutput).Greeting != "hello Luigi " {
+		t.Errorf("Expected the decoded input to be used, got %#v (%v)", result, err)
+	}
+
+	// Other params are converted as usual
+	result, err = action.Run(ctx, map[string]interface{}{"name": "Mario", "times": 1}, nil)
+	if err != nil || result.(greetOutput).Greeting != "hello Mario " {
+		t.Errorf("Expected replaced params to be converted, got %#v (%v)", result, err)
+	}
+}
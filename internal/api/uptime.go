@@ -0,0 +1,32 @@
+package api
+
+// UptimeCheck is the latest result of checking a URL (see uptime), as shown
+// by the status action and metrics
+type UptimeCheck struct {
+	URL                 string `json:"url"`
+	Up                  bool   `json:"up"`
+	Status              int    `json:"status,omitempty"`   // HTTP status of the latest check (0 if it failed)
+	Error               string `json:"error,omitempty"`    // Why the latest check failed
+	LatencyMs           int64  `json:"latencyMs"`          // Duration of the latest check
+	CheckedAt           int64  `json:"checkedAt"`          // Unix time of the latest check (0 before the first)
+	LastUpAt            int64  `json:"lastUpAt,omitempty"` // Unix time the URL was last up
+	ConsecutiveFailures int    `json:"consecutiveFailures"`
+	Checks              int64  `json:"checks"`   // How many times the URL was checked
+	Failures            int64  `json:"failures"` // How many of those checks failed
+}
+
+// UptimeChecker reports the latest result of each uptime check. The uptime
+// initializer provides one (see Provide) when uptime.enabled is set.
+type UptimeChecker interface {
+	UptimeChecks() []UptimeCheck
+}
+
+// UptimeChecks returns the latest result of each uptime check, by URL, or
+// none when uptime checks are disabled
+func (a *API) UptimeChecks() []UptimeCheck {
+	checker, ok := Lookup[UptimeChecker](a)
+	if !ok {
+		return []UptimeCheck{}
+	}
+	return checker.UptimeChecks()
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+// errNoAPI is returned by Render outside of an action
+var errNoAPI = errors.New("connection has not run an action")
+
+// Render renders the view name (e.g., "users/show.html") with data, in the
+// configured layout, as an HTML response:
+//
+//	return conn.Render("users/show.html", user)
+//
+// The page is rendered in full before anything is sent, so a template error
+// becomes the action's error instead of a half-written page.
+func (c *Connection) Render(name string, data interface{}) (*RawResponse, error) {
+	c.mu.RLock()
+	a := c.api
+	c.mu.RUnlock()
+	if a == nil {
+		return nil, errNoAPI
+	}
+
+	var buf bytes.Buffer
+	if err := a.Views.Render(&buf, name, data); err != nil {
+		return nil, err
+	}
+	return &RawResponse{ContentType: "text/html; charset=utf-8", Body: buf.Bytes()}, nil
+}
+
+// loadViews loads the pages in views.directory into Views, skipping a
+// missing directory
+func (a *API) loadViews() error {
+	if a.Config == nil || a.Config.Views.Directory == "" || a.Views == nil {
+		return nil
+	}
+	loaded, err := a.Views.Load(a.Config.Views)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load views: %w", err)
+	}
+	a.Logger.Infof("Loaded %d views from %s", loaded, a.Config.Views.Directory)
+	return nil
+}
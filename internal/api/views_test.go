@@ -0,0 +1,59 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+func TestConnection_Render(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "layouts"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"layouts/main.html": `<body>{{template "content" .}}</body>`,
+		"hello.html":        `<h1>Hello {{.}}</h1>`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{Views: config.DefaultViewsConfig()}
+	cfg.Views.Directory = dir
+	a := New(cfg, util.NewLogger(config.LoggerConfig{Level: "fatal"}))
+	if err := a.RegisterAction(NewAction("hello").Handler(func(_ context.Context, params interface{}, conn *Connection) (interface{}, error) {
+		return conn.Render(params.(map[string]interface{})["view"].(string), "<Mario>")
+	})); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	if err := a.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	conn := NewConnection("test", "127.0.0.1", "conn-1", nil)
+	if _, err := conn.Render("hello.html", nil); !errors.Is(err, errNoAPI) {
+		t.Errorf("Expected an error before running an action, got %v", err)
+	}
+
+	result := conn.Act(context.Background(), a, "hello", map[string]interface{}{"view": "hello.html"}, "", "")
+	if result.Error != nil {
+		t.Fatalf("Failed to render: %v", result.Error)
+	}
+	raw, ok := result.Response.(*RawResponse)
+	if !ok || raw.ContentType != "text/html; charset=utf-8" || string(raw.Body) != "<body><h1>Hello &lt;Mario&gt;</h1></body>" {
+		t.Errorf("Unexpected response: %#v", result.Response)
+	}
+
+	result = conn.Act(context.Background(), a, "hello", map[string]interface{}{"view": "missing.html"}, "", "")
+	if result.Error == nil {
+		t.Error("Expected an error for a missing view")
+	}
+}
@@ -0,0 +1,241 @@
+// Package breaker provides circuit breakers, which stop calls to a
+// downstream dependency (a payments API, a database, ...) once it keeps
+// failing, so callers fail fast instead of waiting on it
+package breaker
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// ErrOpen is wrapped by the errors of calls refused by an open breaker
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State is the state of a breaker
+type State string
+
+// Breaker states
+const (
+	StateClosed   State = "closed"    // Calls go through
+	StateOpen     State = "open"      // Calls fail fast until the cooldown has passed
+	StateHalfOpen State = "half-open" // A few calls go through to test the dependency
+)
+
+// Settings are the thresholds of a breaker
+type Settings struct {
+	// Threshold is how many calls in a row may fail before the breaker
+	// opens (0 = never)
+	Threshold int
+	// Cooldown is how long the breaker stays open before it lets calls
+	// through to test the dependency
+	Cooldown time.Duration
+	// HalfOpenProbes is how many calls test the dependency after the
+	// cooldown: if they all succeed the breaker closes, if any fails it
+	// opens again (at least 1)
+	HalfOpenProbes int
+}
+
+// Stats is a snapshot of a breaker, as shown by the status action and metrics
+type Stats struct {
+	Name      string `json:"name"`
+	State     State  `json:"state"`
+	Failures  int    `json:"failures"`            // Failed calls in a row
+	Threshold int    `json:"threshold"`           // Failures in a row that open the breaker
+	Opened    int64  `json:"opened"`              // How many times the breaker opened
+	Rejected  int64  `json:"rejected"`            // Calls refused while open
+	OpenUntil int64  `json:"openUntil,omitempty"` // Unix time the cooldown ends, while open
+}
+
+// Breaker is the circuit breaker of one dependency. After Threshold failed
+// calls in a row it opens, and calls fail fast for Cooldown. Then
+// HalfOpenProbes calls are let through: if they succeed the breaker closes,
+// otherwise it opens again.
+//
+// Use Do to run a call through the breaker, or Allow and Success/Failure
+// when the call can't be wrapped in a function.
+type Breaker struct {
+	name     string
+	settings Settings
+	logger   *util.Logger
+
+	mu        sync.Mutex
+	state     State
+	failures  int
+	openUntil time.Time
+	probes    int // Probes let through since the breaker became half-open
+	succeeded int // Of those, how many succeeded
+	opened    int64
+	rejected  int64
+
+	now func() time.Time
+}
+
+// New creates a closed breaker. logger may be nil.
+func New(name string, settings Settings, logger *util.Logger) *Breaker {
+	if settings.HalfOpenProbes < 1 {
+		settings.HalfOpenProbes = 1
+	}
+	return &Breaker{
+		name:     name,
+		settings: settings,
+		logger:   logger,
+		state:    StateClosed,
+		now:      time.Now,
+	}
+}
+
+// Name returns the name of the breaker's dependency
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// Do runs fn if the breaker allows it, and records its outcome. When the
+// breaker is open fn isn't run, and the error wraps ErrOpen.
+func (b *Breaker) Do(fn func() error) error {
+	if err := b.Allow(); err != nil {
+		return err
+	}
+	err := fn()
+	if err != nil {
+		b.Failure()
+	} else {
+		b.Success()
+	}
+	return err
+}
+
+// Allow returns nil if a call may be made now, which must then be reported
+// with Success, Failure, or Cancel. While the breaker is open it returns a
+// DEPENDENCY_UNAVAILABLE error (a 503 for clients) that wraps ErrOpen, with
+// the rest of the cooldown as its RetryAfter.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen && !b.now().Before(b.openUntil) {
+		b.setState(StateHalfOpen)
+		b.probes, b.succeeded = 0, 0
+	}
+	switch b.state {
+	case StateClosed:
+		return nil
+	case StateHalfOpen:
+		if b.probes < b.settings.HalfOpenProbes {
+			b.probes++
+			return nil
+		}
+	}
+
+	b.rejected++
+	retryAfter := b.openUntil.Sub(b.now())
+	if retryAfter < 0 {
+		retryAfter = 0 // Half-open, waiting on the probes
+	}
+	return util.NewTypedError(util.ErrorTypeDependencyUnavailable,
+		fmt.Sprintf("%s is unavailable", b.name),
+		util.WithRetryAfter(retryAfter),
+		util.WithOriginalError(ErrOpen))
+}
+
+// Success records a call that succeeded
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	if b.state != StateHalfOpen {
+		return
+	}
+	b.succeeded++
+	if b.succeeded >= b.settings.HalfOpenProbes {
+		b.setState(StateClosed)
+	}
+}
+
+// Failure records a call that failed
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	switch b.state {
+	case StateHalfOpen:
+		b.open()
+	case StateClosed:
+		if b.settings.Threshold > 0 && b.failures >= b.settings.Threshold {
+			b.open()
+		}
+	}
+}
+
+// Cancel records a call that ended without an outcome (e.g., the caller
+// canceled it), so another call can test the dependency in its place
+func (b *Breaker) Cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateHalfOpen && b.probes > 0 {
+		b.probes--
+	}
+}
+
+// State returns the breaker's state
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen && !b.now().Before(b.openUntil) {
+		return StateHalfOpen // It will let the next call through
+	}
+	return b.state
+}
+
+// Stats returns a snapshot of the breaker
+func (b *Breaker) Stats() Stats {
+	state := b.State()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	stats := Stats{
+		Name:      b.name,
+		State:     state,
+		Failures:  b.failures,
+		Threshold: b.settings.Threshold,
+		Opened:    b.opened,
+		Rejected:  b.rejected,
+	}
+	if state == StateOpen {
+		stats.OpenUntil = b.openUntil.Unix()
+	}
+	return stats
+}
+
+// open opens the breaker for the cooldown. Called with b.mu held.
+func (b *Breaker) open() {
+	b.openUntil = b.now().Add(b.settings.Cooldown)
+	b.opened++
+	b.setState(StateOpen)
+}
+
+// setState changes the breaker's state, and logs it. Called with b.mu held.
+func (b *Breaker) setState(state State) {
+	previous := b.state
+	b.state = state
+	if b.logger == nil || previous == state {
+		return
+	}
+	switch state {
+	case StateOpen:
+		b.logger.Warnf("Circuit breaker %s opened after %d failed calls in a row; failing fast for %s",
+			b.name, b.failures, b.settings.Cooldown)
+	case StateHalfOpen:
+		b.logger.Infof("Circuit breaker %s is half-open; testing %s with %d calls",
+			b.name, b.name, b.settings.HalfOpenProbes)
+	case StateClosed:
+		b.logger.Infof("Circuit breaker %s closed", b.name)
+	}
+}
@@ -0,0 +1,146 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// testBreaker opens after 2 failures for a minute, and tests the dependency
+// with 2 calls; its clock only moves when the test moves it
+func testBreaker() (*Breaker, *time.Time) {
+	b := New("payments", Settings{Threshold: 2, Cooldown: time.Minute, HalfOpenProbes: 2}, nil)
+	now := time.Now()
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+var errDown = errors.New("payments are down")
+
+func TestBreaker_Opens(t *testing.T) {
+	b, _ := testBreaker()
+
+	calls := 0
+	fail := func() error { calls++; return errDown }
+	for i := 0; i < 2; i++ {
+		if err := b.Do(fail); !errors.Is(err, errDown) {
+			t.Fatalf("Expected the call's error, got %v", err)
+		}
+	}
+	if b.State() != StateOpen {
+		t.Fatalf("Expected the breaker to open, got %s", b.State())
+	}
+
+	err := b.Do(fail)
+	if !errors.Is(err, ErrOpen) {
+		t.Fatalf("Expected ErrOpen, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected the open breaker not to call, got %d calls", calls)
+	}
+	var typedErr *util.TypedError
+	if !errors.As(err, &typedErr) || typedErr.Type != util.ErrorTypeDependencyUnavailable {
+		t.Fatalf("Expected a DEPENDENCY_UNAVAILABLE error, got %v", err)
+	}
+	if typedErr.HTTPStatus() != 503 || typedErr.RetryAfter != time.Minute {
+		t.Errorf("Expected a 503 with a 1m Retry-After, got %d and %s", typedErr.HTTPStatus(), typedErr.RetryAfter)
+	}
+
+	stats := b.Stats()
+	if stats.Opened != 1 || stats.Rejected != 1 || stats.Failures != 2 || stats.OpenUntil == 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestBreaker_SuccessResetsFailures(t *testing.T) {
+	b, _ := testBreaker()
+	b.Failure()
+	b.Success()
+	b.Failure()
+	if b.State() != StateClosed {
+		t.Errorf("Expected failures in a row only to open the breaker, got %s", b.State())
+	}
+}
+
+func TestBreaker_HalfOpen(t *testing.T) {
+	b, now := testBreaker()
+	b.Failure()
+	b.Failure()
+
+	*now = now.Add(time.Minute)
+	if b.State() != StateHalfOpen {
+		t.Fatalf("Expected half-open after the cooldown, got %s", b.State())
+	}
+
+	// Two probes are let through, and no more until they report
+	for i := 0; i < 2; i++ {
+		if err := b.Allow(); err != nil {
+			t.Fatalf("Expected probe %d to be allowed, got %v", i, err)
+		}
+	}
+	if err := b.Allow(); !errors.Is(err, ErrOpen) {
+		t.Fatalf("Expected a third call to be refused, got %v", err)
+	}
+
+	// A canceled probe lets another call test the dependency
+	b.Cancel()
+	if err := b.Allow(); err != nil {
+		t.Fatalf("Expected a call in place of the canceled probe, got %v", err)
+	}
+
+	b.Success()
+	if b.State() != StateHalfOpen {
+		t.Fatalf("Expected to stay half-open until every probe succeeds, got %s", b.State())
+	}
+	b.Success()
+	if b.State() != StateClosed {
+		t.Fatalf("Expected the breaker to close, got %s", b.State())
+	}
+}
+
+func TestBreaker_FailedProbeReopens(t *testing.T) {
+	b, now := testBreaker()
+	b.Failure()
+	b.Failure()
+
+	*now = now.Add(time.Minute)
+	if err := b.Do(func() error { return errDown }); !errors.Is(err, errDown) {
+		t.Fatalf("Expected the probe to run, got %v", err)
+	}
+	if b.State() != StateOpen {
+		t.Fatalf("Expected a failed probe to open the breaker again, got %s", b.State())
+	}
+	if stats := b.Stats(); stats.Opened != 2 {
+		t.Errorf("Expected the breaker to have opened twice, got %d", stats.Opened)
+	}
+}
+
+func TestBreaker_NoThreshold(t *testing.T) {
+	b := New("cache", Settings{}, nil)
+	for i := 0; i < 100; i++ {
+		b.Failure()
+	}
+	if err := b.Allow(); err != nil {
+		t.Errorf("Expected a breaker without a threshold never to open, got %v", err)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry(Settings{Threshold: 1, Cooldown: time.Minute}, nil)
+	if r.Get("payments") != r.Get("payments") {
+		t.Error("Expected the same breaker for a name")
+	}
+
+	r.GetWithSettings("search", Settings{Threshold: 3, Cooldown: time.Second}).Failure()
+	r.Get("payments").Failure()
+
+	stats := r.Stats()
+	if len(stats) != 2 || stats[0].Name != "payments" || stats[1].Name != "search" {
+		t.Fatalf("Expected the breakers sorted by name, got %+v", stats)
+	}
+	if stats[0].State != StateOpen || stats[1].State != StateClosed || stats[1].Threshold != 3 {
+		t.Errorf("Expected each breaker to use its own settings, got %+v", stats)
+	}
+}
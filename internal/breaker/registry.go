@@ -0,0 +1,64 @@
+package breaker
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// Registry holds a breaker per dependency, by name
+type Registry struct {
+	defaults Settings
+	logger   *util.Logger
+
+	mu       sync.Mutex
+	breakers map[string]*Breaker
+}
+
+// NewRegistry creates a registry whose breakers use defaults unless they
+// are created with their own settings. logger may be nil.
+func NewRegistry(defaults Settings, logger *util.Logger) *Registry {
+	return &Registry{
+		defaults: defaults,
+		logger:   logger,
+		breakers: make(map[string]*Breaker),
+	}
+}
+
+// Get returns the breaker named name, creating it with the registry's
+// default settings on first use
+func (r *Registry) Get(name string) *Breaker {
+	return r.GetWithSettings(name, r.defaults)
+}
+
+// GetWithSettings returns the breaker named name, creating it with settings
+// on first use. A breaker keeps the settings it was created with.
+func (r *Registry) GetWithSettings(name string, settings Settings) *Breaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, ok := r.breakers[name]
+	if !ok {
+		b = New(name, settings, r.logger)
+		r.breakers[name] = b
+	}
+	return b
+}
+
+// Stats returns a snapshot of every breaker, by name
+func (r *Registry) Stats() []Stats {
+	r.mu.Lock()
+	breakers := make([]*Breaker, 0, len(r.breakers))
+	for _, b := range r.breakers {
+		breakers = append(breakers, b)
+	}
+	r.mu.Unlock()
+
+	stats := make([]Stats, 0, len(breakers))
+	for _, b := range breakers {
+		stats = append(stats, b.Stats())
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
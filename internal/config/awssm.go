@@ -0,0 +1,96 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/awssig"
+)
+
+// awsSecretsProvider reads secrets from AWS Secrets Manager. Credentials come
+// from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, and AWS_SESSION_TOKEN.
+type awsSecretsProvider struct {
+	region      string
+	endpoint    string
+	credentials awssig.Credentials
+	client      *http.Client
+}
+
+// newAWSSecretsProvider creates a Secrets Manager provider, falling back to
+// AWS_REGION (or AWS_DEFAULT_REGION) for the region
+func newAWSSecretsProvider(cfg SecretsConfig) (SecretProvider, error) {
+	p := &awsSecretsProvider{
+		region:      firstNonEmpty(cfg.AWSRegion, os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION")),
+		endpoint:    cfg.AWSEndpoint,
+		credentials: awssig.CredentialsFromEnv(),
+		client:      &http.Client{},
+	}
+	if p.region == "" {
+		return nil, errors.New("aws region is not set (secrets.awsregion or AWS_REGION)")
+	}
+	if !p.credentials.Valid() {
+		return nil, errors.New("aws credentials are not set (AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY)")
+	}
+	if p.endpoint == "" {
+		p.endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", p.region)
+	}
+	p.endpoint = strings.TrimRight(p.endpoint, "/")
+	return p, nil
+}
+
+// Fetch reads the current version of the secret named (or ARN) path. With a
+// key, the secret string is parsed as a JSON object and that field returned.
+func (p *awsSecretsProvider) Fetch(ctx context.Context, path, key string) (string, error) {
+	body, err := json.Marshal(map[string]string{"SecretId": path})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	awssig.Sign(req, body, p.credentials, p.region, "secretsmanager", time.Now())
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("secrets manager request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		var awsErr struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		_ = json.Unmarshal(respBody, &awsErr)
+		return "", fmt.Errorf("secrets manager returned %d: %s %s", resp.StatusCode, awsErr.Type, awsErr.Message)
+	}
+
+	var payload struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return "", fmt.Errorf("invalid secrets manager response: %w", err)
+	}
+
+	if key == "" {
+		return payload.SecretString, nil
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(payload.SecretString), &data); err != nil {
+		return "", fmt.Errorf("secret is not a JSON object, so key %q can't be selected", key)
+	}
+	return secretField(data, key)
+}
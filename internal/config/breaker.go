@@ -0,0 +1,26 @@
+package config
+
+import "time"
+
+// BreakerConfig holds the default settings of the circuit breakers actions
+// use to fail fast when a downstream dependency is down (see API.Breaker)
+type BreakerConfig struct {
+	// Threshold is how many calls in a row to a dependency may fail before
+	// calls to it fail fast (0 = never)
+	Threshold int
+	// Cooldown is how long calls to a dependency fail fast before some are
+	// let through to test it
+	Cooldown time.Duration
+	// HalfOpenProbes is how many calls test a dependency after the
+	// cooldown; the breaker closes once they all succeed
+	HalfOpenProbes int
+}
+
+// DefaultBreakerConfig returns default circuit breaker configuration
+func DefaultBreakerConfig() BreakerConfig {
+	return BreakerConfig{
+		Threshold:      5,
+		Cooldown:       30 * time.Second,
+		HalfOpenProbes: 1,
+	}
+}
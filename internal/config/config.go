@@ -3,8 +3,13 @@ package config
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
@@ -12,35 +17,301 @@ import (
 
 // Config holds all configuration for the application
 type Config struct {
-	Process  ProcessConfig
-	Logger   LoggerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	Session  SessionConfig
-	Server   ServerConfig
-	Tasks    TasksConfig
+	Process    ProcessConfig
+	Logger     LoggerConfig
+	Database   DatabaseConfig
+	Redis      RedisConfig
+	Session    SessionConfig
+	Server     ServerConfig
+	Tasks      TasksConfig
+	Sentry     SentryConfig
+	StatsD     StatsDConfig
+	Mail       MailConfig
+	Storage    StorageConfig
+	HTTPClient HTTPClientConfig
+	Breaker    BreakerConfig
+	Tenancy    TenancyConfig
+	GeoIP      GeoIPConfig
+	Uptime     UptimeConfig
+	I18n       I18nConfig
+	Views      ViewsConfig
+	OpenAPI    OpenAPIConfig
+	Secrets    SecretsConfig
+
+	// Sections holds the sections registered with RegisterSection, by name
+	// (read them with GetSection)
+	Sections map[string]interface{}
+
+	// Sources records where each setting's value came from, by key
+	// (set by Load; nil for configs built in code)
+	Sources map[string]Source
+
+	// Files lists the config files Load read, in merge order (later files win)
+	Files []string
+
+	// loadOptions are the options the config was loaded with, so sections
+	// registered later can be loaded from the same sources
+	loadOptions []LoadOption
 }
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
 	Web WebServerConfig
+
+	// Enabled holds server.<name>.enabled of each server registered with
+	// RegisterServer, by name (see IsEnabled)
+	Enabled map[string]bool `mapstructure:"-"`
 }
 
 // ProcessConfig holds process configuration
 type ProcessConfig struct {
-	Name string
+	Name          string
+	PidFile       string        // Pid file written in daemon mode
+	LogFile       string        // Log file used in daemon mode
+	WatchConfig   bool          // Reload config and .env files when they change
+	ActionTimeout time.Duration // Default action timeout (0 = none; a bare number is milliseconds)
+
+	// ParallelStartup initializes and starts independent initializers and
+	// servers concurrently, reporting every failure together
+	ParallelStartup bool
+	// StartupTimeout bounds each component's Initialize and Start (0 = none;
+	// a bare number is milliseconds)
+	StartupTimeout time.Duration
+
+	// ErrorDisclosure controls how much of an error is sent to clients:
+	// ErrorDisclosureDebug, ErrorDisclosureStandard, or ErrorDisclosureProduction
+	ErrorDisclosure string
 }
 
+// Error disclosure modes (process.errordisclosure)
+const (
+	// ErrorDisclosureDebug sends messages, stack traces, and original errors
+	ErrorDisclosureDebug = "debug"
+	// ErrorDisclosureStandard sends messages without internal details
+	ErrorDisclosureStandard = "standard"
+	// ErrorDisclosureProduction sends a generic message and the request ID
+	// for server errors, instead of their messages
+	ErrorDisclosureProduction = "production"
+)
+
 // DefaultProcessConfig returns default process configuration
 func DefaultProcessConfig() ProcessConfig {
 	return ProcessConfig{
-		Name: "actionhero",
+		Name:            "actionhero",
+		PidFile:         "./actionhero.pid",
+		LogFile:         "./log/actionhero.log",
+		WatchConfig:     false,
+		ActionTimeout:   0,
+		ParallelStartup: false,
+		StartupTimeout:  0,
+		ErrorDisclosure: ErrorDisclosureStandard,
+	}
+}
+
+// DefaultEnvPrefix is the prefix for environment variable overrides
+// (e.g., ACTIONHERO_SERVER_WEB_PORT)
+const DefaultEnvPrefix = "ACTIONHERO"
+
+// DefaultConfigPaths are searched, in order, for config files
+var DefaultConfigPaths = []string{".", "./config", "$HOME/.actionhero"}
+
+// DefaultConfigDir is the directory of per-environment config files
+// (default.yaml, production.yaml, local.yaml, ...)
+const DefaultConfigDir = "config"
+
+// LoadOptions control where Load looks for configuration
+type LoadOptions struct {
+	ConfigDir  string            // Directory of per-environment config files; used when it has any
+	Paths      []string          // Directories searched, in order, for config files when ConfigDir has none
+	EnvPrefix  string            // Prefix for environment variable overrides
+	ConfigFile string            // Base config file to read instead of searching Paths for one
+	Overrides  map[string]string // Values by key (e.g., "server.web.port"), overriding every other source
+}
+
+// LoadOption is a function that modifies LoadOptions
+type LoadOption func(*LoadOptions)
+
+// WithPaths sets the directories searched for config files
+func WithPaths(paths ...string) LoadOption {
+	return func(o *LoadOptions) {
+		o.Paths = paths
+	}
+}
+
+// WithConfigDir sets the directory of per-environment config files
+func WithConfigDir(dir string) LoadOption {
+	return func(o *LoadOptions) {
+		o.ConfigDir = dir
+	}
+}
+
+// WithEnvPrefix sets the prefix for environment variable overrides
+func WithEnvPrefix(prefix string) LoadOption {
+	return func(o *LoadOptions) {
+		o.EnvPrefix = prefix
 	}
 }
 
-// Load loads configuration from files and environment variables
-func Load() (*Config, error) {
-	cfg := &Config{
+// WithConfigFile reads the base config from path (which must exist) instead
+// of searching for config.{yaml,yml,toml,json}. When path is a directory, it
+// is read like the config directory.
+func WithConfigFile(path string) LoadOption {
+	return func(o *LoadOptions) {
+		o.ConfigFile = path
+	}
+}
+
+// WithOverrides sets config values by key (e.g., "server.web.port": "9000"),
+// taking precedence over config files and environment variables
+func WithOverrides(overrides map[string]string) LoadOption {
+	return func(o *LoadOptions) {
+		o.Overrides = overrides
+	}
+}
+
+// newLoadOptions applies opts on top of the defaults
+func newLoadOptions(opts []LoadOption) LoadOptions {
+	options := LoadOptions{
+		ConfigDir: DefaultConfigDir,
+		Paths:     DefaultConfigPaths,
+		EnvPrefix: DefaultEnvPrefix,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// Load loads configuration from files and environment variables, then
+// validates it. All validation problems are returned together.
+func Load(opts ...LoadOption) (*Config, error) {
+	cfg, err := LoadWithoutValidation(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+
+	return cfg, nil
+}
+
+// LoadWithoutValidation loads configuration like Load, but returns it even
+// if it is invalid (e.g., so `config validate` can report every problem).
+// Each call uses its own viper instance, so loads never share state.
+func LoadWithoutValidation(opts ...LoadOption) (*Config, error) {
+	options := newLoadOptions(opts)
+
+	cfg := defaultConfig()
+
+	// Load .env file (if it exists) - this loads variables into the environment
+	// Try multiple locations: .env, .env.local, .env.{NODE_ENV}
+	env := Environment()
+
+	envFiles := []string{".env"}
+	if env != "" {
+		envFiles = append(envFiles, fmt.Sprintf(".env.%s", env))
+	}
+	envFiles = append(envFiles, ".env.local")
+
+	// Load .env files (missing files are ignored)
+	loadEnvFiles(envFiles)
+
+	v := viper.New()
+	prov := &provenance{envPrefix: options.EnvPrefix}
+
+	// Environment variables
+	v.SetEnvPrefix(options.EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	// Set defaults
+	setDefaults(v)
+	setSectionDefaults(v)
+	setServerDefaults(v)
+
+	// Read config files (optional, unless one was given explicitly), merging
+	// each over the ones before
+	files, err := configFiles(options, env)
+	if err != nil {
+		return nil, err
+	}
+	for i, path := range files {
+		read := v.MergeConfig
+		if i == 0 {
+			read = v.ReadConfig
+		}
+		if err := readConfigFile(v, path, read); err != nil {
+			return nil, fmt.Errorf("error reading config file: %w", err)
+		}
+		prov.addFile(path)
+	}
+	cfg.Files = files
+	cfg.loadOptions = opts
+
+	// Overrides win over every other source
+	if err := applyOverrides(v, options.Overrides); err != nil {
+		return nil, err
+	}
+	prov.overrides = make(map[string]bool, len(options.Overrides))
+	for key := range options.Overrides {
+		prov.overrides[strings.ToLower(strings.TrimSpace(key))] = true
+	}
+	cfg.Sources = prov.sources(v)
+
+	// Durations may be bare numbers in their legacy unit or strings like "30s"
+	if err := normalizeDurations(v, options.EnvPrefix); err != nil {
+		return nil, fmt.Errorf("error reading config: %w", err)
+	}
+
+	// Unmarshal into config struct
+	if err := v.Unmarshal(cfg, viper.DecodeHook(decodeHooks)); err != nil {
+		return nil, fmt.Errorf("error unmarshaling config: %w", err)
+	}
+	if err := loadSections(v, cfg); err != nil {
+		return nil, err
+	}
+	loadServers(v, cfg)
+
+	// Replace secret references (e.g., vault://secret/db#password) with their values
+	if err := resolveSecrets(cfg); err != nil {
+		return nil, fmt.Errorf("error resolving secrets:\n%w", err)
+	}
+
+	return cfg, nil
+}
+
+// applyOverrides sets each override, rejecting keys that aren't settings
+func applyOverrides(v *viper.Viper, overrides map[string]string) error {
+	if len(overrides) == 0 {
+		return nil
+	}
+
+	known := make(map[string]bool)
+	for _, key := range v.AllKeys() {
+		known[key] = true
+	}
+
+	keys := make([]string, 0, len(overrides))
+	for key := range overrides {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		normalized := strings.ToLower(strings.TrimSpace(key))
+		if !known[normalized] {
+			return fmt.Errorf("unknown config key %q", key)
+		}
+		v.Set(normalized, overrides[key])
+	}
+	return nil
+}
+
+// defaultConfig returns a config holding each section's default values
+func defaultConfig() *Config {
+	return &Config{
 		Process:  DefaultProcessConfig(),
 		Logger:   DefaultLoggerConfig(),
 		Database: DefaultDatabaseConfig(),
@@ -49,115 +320,396 @@ func Load() (*Config, error) {
 		Server: ServerConfig{
 			Web: DefaultWebServerConfig(),
 		},
-		Tasks: DefaultTasksConfig(),
+		Tasks:      DefaultTasksConfig(),
+		Sentry:     DefaultSentryConfig(),
+		StatsD:     DefaultStatsDConfig(),
+		Mail:       DefaultMailConfig(),
+		Storage:    DefaultStorageConfig(),
+		HTTPClient: DefaultHTTPClientConfig(),
+		Breaker:    DefaultBreakerConfig(),
+		Tenancy:    DefaultTenancyConfig(),
+		GeoIP:      DefaultGeoIPConfig(),
+		Uptime:     DefaultUptimeConfig(),
+		I18n:       DefaultI18nConfig(),
+		Views:      DefaultViewsConfig(),
+		OpenAPI:    DefaultOpenAPIConfig(),
+		Secrets:    DefaultSecretsConfig(),
 	}
+}
 
-	// Load .env file (if it exists) - this loads variables into the environment
-	// Try multiple locations: .env, .env.local, .env.{NODE_ENV}
-	env := os.Getenv("NODE_ENV")
+// Environment returns the current environment, from NODE_ENV or GO_ENV
+// (e.g., "production"), or "" when neither is set
+func Environment() string {
+	if env := os.Getenv("NODE_ENV"); env != "" {
+		return env
+	}
+	return os.Getenv("GO_ENV")
+}
+
+// ConfigDirOrder returns the names of the files read from the config
+// directory for env, in merge order: later files override earlier ones
+func ConfigDirOrder(env string) []string {
 	if env == "" {
-		env = os.Getenv("GO_ENV")
+		return []string{"default", "local"}
 	}
+	return []string{"default", env, "local", "local-" + env}
+}
 
-	envFiles := []string{".env"}
+// configFiles returns the config files to read, in merge order. A config
+// directory with any of the ConfigDirOrder files replaces the single config
+// file and its environment-specific (config.{env}.yaml) override.
+func configFiles(options LoadOptions, env string) ([]string, error) {
+	if options.ConfigFile != "" {
+		info, err := os.Stat(options.ConfigFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading config file: %w", err)
+		}
+		if info.IsDir() {
+			return configDirFiles(options.ConfigFile, env), nil
+		}
+	} else if options.ConfigDir != "" {
+		if files := configDirFiles(os.ExpandEnv(options.ConfigDir), env); len(files) > 0 {
+			return files, nil
+		}
+	}
+
+	var files []string
+	if options.ConfigFile != "" {
+		files = append(files, options.ConfigFile)
+	} else if path, ok := findConfigFile(options.Paths, "config"); ok {
+		files = append(files, path)
+	}
 	if env != "" {
-		envFiles = append(envFiles, fmt.Sprintf(".env.%s", env))
+		if path, ok := findConfigFile(options.Paths, fmt.Sprintf("config.%s", env)); ok {
+			files = append(files, path)
+		}
 	}
-	envFiles = append(envFiles, ".env.local")
+	return files, nil
+}
 
-	// Load .env files (ignore errors if files don't exist)
-	for _, envFile := range envFiles {
-		_ = godotenv.Load(envFile)
+// configDirFiles returns the ConfigDirOrder files that exist in dir
+func configDirFiles(dir, env string) []string {
+	var files []string
+	for _, name := range ConfigDirOrder(env) {
+		if path, ok := findConfigFile([]string{dir}, name); ok {
+			files = append(files, path)
+		}
 	}
+	return files
+}
 
-	// Set up viper
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath(".")
-	viper.AddConfigPath("./config")
-	viper.AddConfigPath("$HOME/.actionhero")
+// configExtensions are the supported config file formats, in order of preference
+var configExtensions = []string{"yaml", "yml", "toml", "json"}
 
-	// Environment variables
-	viper.SetEnvPrefix("ACTIONHERO")
-	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-	viper.AutomaticEnv()
+// findConfigFile returns the first config file named name (with any supported
+// extension) in paths
+func findConfigFile(paths []string, name string) (string, bool) {
+	for _, dir := range paths {
+		for _, ext := range configExtensions {
+			path := filepath.Join(os.ExpandEnv(dir), name+"."+ext)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path, true
+			}
+		}
+	}
+	return "", false
+}
 
-	// Set defaults
-	setDefaults()
+// readConfigFile reads a config file with read (v.ReadConfig or v.MergeConfig),
+// detecting the format from its extension
+func readConfigFile(v *viper.Viper, path string, read func(io.Reader) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
 
-	// Read config file (optional)
-	if err := viper.ReadInConfig(); err != nil {
-		// Config file not found is OK, we'll use defaults and env vars
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			return nil, fmt.Errorf("error reading config file: %w", err)
-		}
+	v.SetConfigType(strings.TrimPrefix(filepath.Ext(path), "."))
+	if err := read(f); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
 	}
+	return nil
+}
 
-	// Override with environment-specific config if NODE_ENV is set
-	if env != "" {
-		viper.SetConfigName(fmt.Sprintf("config.%s", env))
-		if err := viper.MergeInConfig(); err != nil {
-			// Environment-specific config not found is OK
-			if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-				return nil, fmt.Errorf("error reading environment config file: %w", err)
+// dotenvValues records the variables set from .env files (and the value set),
+// so reloading can update or remove them without clobbering the real
+// environment. dotenvFiles records which file set each one.
+var (
+	dotenvValues = make(map[string]string)
+	dotenvFiles  = make(map[string]string)
+	dotenvMu     sync.Mutex
+)
+
+// dotenvFile returns the .env file that set an environment variable, if any
+func dotenvFile(key string) string {
+	dotenvMu.Lock()
+	defer dotenvMu.Unlock()
+	if value, ok := dotenvValues[key]; !ok || os.Getenv(key) != value {
+		return ""
+	}
+	return dotenvFiles[key]
+}
+
+// loadEnvFiles sets environment variables from .env files. Earlier files take
+// precedence, and variables already set in the real environment are never
+// overridden. Variables previously set from a .env file are updated, or unset
+// if they have been removed from every file.
+func loadEnvFiles(files []string) {
+	dotenvMu.Lock()
+	defer dotenvMu.Unlock()
+
+	values := make(map[string]string)
+	sources := make(map[string]string)
+	for _, file := range files {
+		fileValues, err := godotenv.Read(file)
+		if err != nil {
+			continue
+		}
+		for key, value := range fileValues {
+			if _, exists := values[key]; !exists {
+				values[key] = value
+				sources[key] = file
 			}
 		}
 	}
 
-	// Unmarshal into config struct
-	if err := viper.Unmarshal(cfg); err != nil {
-		return nil, fmt.Errorf("error unmarshaling config: %w", err)
+	for key, value := range values {
+		if current, exists := os.LookupEnv(key); exists {
+			if previous, fromFile := dotenvValues[key]; !fromFile || previous != current {
+				// Set by the real environment, which always wins
+				delete(dotenvValues, key)
+				delete(dotenvFiles, key)
+				continue
+			}
+		}
+		_ = os.Setenv(key, value)
+		dotenvValues[key] = value
+		dotenvFiles[key] = sources[key]
 	}
 
-	return cfg, nil
+	for key, previous := range dotenvValues {
+		if _, stillSet := values[key]; stillSet {
+			continue
+		}
+		if current, exists := os.LookupEnv(key); exists && current == previous {
+			_ = os.Unsetenv(key)
+		}
+		delete(dotenvValues, key)
+		delete(dotenvFiles, key)
+	}
 }
 
-// setDefaults sets default values in viper
-func setDefaults() {
+// setDefaults sets default values in v
+func setDefaults(v *viper.Viper) {
 	// Process
-	viper.SetDefault("process.name", "actionhero")
+	v.SetDefault("process.name", "actionhero")
+	v.SetDefault("process.pidfile", "./actionhero.pid")
+	v.SetDefault("process.logfile", "./log/actionhero.log")
+	v.SetDefault("process.watchconfig", false)
+	v.SetDefault("process.actiontimeout", time.Duration(0))
+	v.SetDefault("process.parallelstartup", false)
+	v.SetDefault("process.startuptimeout", time.Duration(0))
+	v.SetDefault("process.errordisclosure", ErrorDisclosureStandard)
 
 	// Logger
-	viper.SetDefault("logger.level", "info")
-	viper.SetDefault("logger.colorize", true)
-	viper.SetDefault("logger.timestamp", true)
+	v.SetDefault("logger.level", "info")
+	v.SetDefault("logger.colorize", true)
+	v.SetDefault("logger.timestamp", true)
+	v.SetDefault("logger.format", "")
+	v.SetDefault("logger.slowaction", time.Second)
+	v.SetDefault("logger.errorsamplefirst", 100)
+	v.SetDefault("logger.errorsamplethereafter", 100)
+	v.SetDefault("logger.errorsamplewindow", time.Second)
+	v.SetDefault("logger.asyncbuffer", 0)
+	v.SetDefault("logger.file", "")
+	v.SetDefault("logger.stdout", true)
+	v.SetDefault("logger.filemaxsize", "100MB")
+	v.SetDefault("logger.filemaxage", time.Duration(0))
+	v.SetDefault("logger.filemaxbackups", 10)
+	v.SetDefault("logger.filecompress", false)
+	v.SetDefault("logger.syslog", "")
+	v.SetDefault("logger.syslogtag", "actionhero")
+	v.SetDefault("logger.syslogfacility", "local0")
+	v.SetDefault("logger.journald", false)
+	v.SetDefault("logger.hooks", "")
 
 	// Database
-	viper.SetDefault("database.type", "postgres")
-	viper.SetDefault("database.host", "localhost")
-	viper.SetDefault("database.port", 5432)
-	viper.SetDefault("database.user", "postgres")
-	viper.SetDefault("database.password", "")
-	viper.SetDefault("database.database", "actionhero")
-	viper.SetDefault("database.sslmode", "disable")
+	v.SetDefault("database.type", "postgres")
+	v.SetDefault("database.host", "localhost")
+	v.SetDefault("database.port", 5432)
+	v.SetDefault("database.user", "postgres")
+	v.SetDefault("database.password", "")
+	v.SetDefault("database.database", "actionhero")
+	v.SetDefault("database.sslmode", "disable")
+	v.SetDefault("database.fixtures", "fixtures")
+	v.SetDefault("database.seedonstart", false)
 
 	// Redis
-	viper.SetDefault("redis.host", "localhost")
-	viper.SetDefault("redis.port", 6379)
-	viper.SetDefault("redis.password", "")
-	viper.SetDefault("redis.db", 0)
+	v.SetDefault("redis.host", "localhost")
+	v.SetDefault("redis.port", 6379)
+	v.SetDefault("redis.password", "")
+	v.SetDefault("redis.db", 0)
 
 	// Session
-	viper.SetDefault("session.cookiename", "actionhero")
-	viper.SetDefault("session.ttl", 86400)
+	v.SetDefault("session.cookiename", "actionhero")
+	v.SetDefault("session.ttl", 24*time.Hour)
+	v.SetDefault("session.samesite", SameSiteLax)
+	v.SetDefault("session.secure", false)
+	v.SetDefault("session.httponly", true)
+	v.SetDefault("session.domain", "")
+	v.SetDefault("session.path", "/")
 
 	// Server
-	viper.SetDefault("server.web.enabled", true)
-	viper.SetDefault("server.web.host", "0.0.0.0")
-	viper.SetDefault("server.web.port", 8080)
-	viper.SetDefault("server.web.apiroute", "/api")
-	viper.SetDefault("server.web.allowedorigins", "*")
-	viper.SetDefault("server.web.allowedmethods", "GET,POST,PUT,DELETE,PATCH,OPTIONS")
-	viper.SetDefault("server.web.allowedheaders", "Content-Type,Authorization")
-	viper.SetDefault("server.web.staticfilesenabled", false)
-	viper.SetDefault("server.web.staticfilesroute", "/public")
-	viper.SetDefault("server.web.staticfilesdirectory", "./public")
+	v.SetDefault("server.web.enabled", true)
+	v.SetDefault("server.web.host", "0.0.0.0")
+	v.SetDefault("server.web.port", 8080)
+	v.SetDefault("server.web.apiroute", "/api")
+	v.SetDefault("server.web.allowedorigins", "*")
+	v.SetDefault("server.web.allowedmethods", "GET,POST,PUT,DELETE,PATCH,OPTIONS")
+	v.SetDefault("server.web.allowedheaders", "Content-Type,Authorization")
+	v.SetDefault("server.web.staticfilesenabled", false)
+	v.SetDefault("server.web.staticfilesroute", "/public")
+	v.SetDefault("server.web.staticfilesdirectory", "./public")
+	v.SetDefault("server.web.metricsenabled", true)
+	v.SetDefault("server.web.metricsroute", "/metrics")
+	v.SetDefault("server.web.debugenabled", false)
+	v.SetDefault("server.web.debugroute", "/debug")
+	v.SetDefault("server.web.debughost", "127.0.0.1")
+	v.SetDefault("server.web.debugport", 6060)
+	v.SetDefault("server.web.openapiversion", OpenAPIVersion30)
+	v.SetDefault("server.web.validaterequests", false)
+	v.SetDefault("server.web.maxbodysize", "10MB")
+	v.SetDefault("server.web.idletimeout", time.Duration(0))
+	v.SetDefault("server.web.reapinterval", 30*time.Second)
+	v.SetDefault("server.web.messagerate", 0)
+	v.SetDefault("server.web.messageburst", 20)
+	v.SetDefault("server.web.messageratewarnings", 5)
+	v.SetDefault("server.web.maxactionsinflight", 10)
+	v.SetDefault("server.web.orderedresponses", false)
+	v.SetDefault("server.web.broadcastworkers", 0)
+	v.SetDefault("server.web.routecachesize", 1000)
+	v.SetDefault("server.web.allowedips", "")
+	v.SetDefault("server.web.deniedips", "")
+	v.SetDefault("server.web.metricsallowedips", "")
+	v.SetDefault("server.web.debugallowedips", "")
+	v.SetDefault("server.web.adminenabled", false)
+	v.SetDefault("server.web.adminroute", "/admin")
+	v.SetDefault("server.web.adminuser", "admin")
+	v.SetDefault("server.web.adminpassword", "")
+	v.SetDefault("server.web.adminallowedips", "")
+	v.SetDefault("server.web.tlscertfile", "")
+	v.SetDefault("server.web.tlskeyfile", "")
 
 	// Tasks
-	viper.SetDefault("tasks.enabled", true)
-	viper.SetDefault("tasks.taskprocessors", 1)
-	viper.SetDefault("tasks.queues", []string{"default"})
-	viper.SetDefault("tasks.timeout", 10000)
-	viper.SetDefault("tasks.stuckworkertimeout", 60000)
-	viper.SetDefault("tasks.retrystuckjobs", false)
+	v.SetDefault("tasks.enabled", true)
+	v.SetDefault("tasks.taskprocessors", 1)
+	v.SetDefault("tasks.queues", []string{"default"})
+	v.SetDefault("tasks.timeout", 10*time.Second)
+	v.SetDefault("tasks.stuckworkertimeout", 60*time.Second)
+	v.SetDefault("tasks.retrystuckjobs", false)
+
+	// Sentry
+	v.SetDefault("sentry.dsn", "")
+	v.SetDefault("sentry.environment", "development")
+	v.SetDefault("sentry.samplerate", 1.0)
+
+	// StatsD
+	v.SetDefault("statsd.enabled", false)
+	v.SetDefault("statsd.host", "127.0.0.1")
+	v.SetDefault("statsd.port", 8125)
+	v.SetDefault("statsd.prefix", "actionhero.")
+	v.SetDefault("statsd.tags", "")
+
+	// Mail
+	v.SetDefault("mail.enabled", false)
+	v.SetDefault("mail.transport", "smtp")
+	v.SetDefault("mail.from", "")
+	v.SetDefault("mail.templates", "templates/mail")
+	v.SetDefault("mail.async", false)
+	v.SetDefault("mail.queuesize", 100)
+	v.SetDefault("mail.smtphost", "localhost")
+	v.SetDefault("mail.smtpport", 587)
+	v.SetDefault("mail.smtpuser", "")
+	v.SetDefault("mail.smtppassword", "")
+	v.SetDefault("mail.sesregion", "")
+	v.SetDefault("mail.sesendpoint", "")
+
+	// Storage
+	v.SetDefault("storage.enabled", false)
+	v.SetDefault("storage.backend", "local")
+	v.SetDefault("storage.directory", "storage")
+	v.SetDefault("storage.route", "/files")
+	v.SetDefault("storage.publicurl", "")
+	v.SetDefault("storage.urlsecret", "")
+	v.SetDefault("storage.s3bucket", "")
+	v.SetDefault("storage.s3region", "")
+	v.SetDefault("storage.s3endpoint", "")
+	v.SetDefault("storage.s3pathstyle", false)
+
+	// HTTP client
+	v.SetDefault("httpclient.timeout", 30*time.Second)
+	v.SetDefault("httpclient.retries", 2)
+	v.SetDefault("httpclient.retrybackoff", 100*time.Millisecond)
+	v.SetDefault("httpclient.retrymaxbackoff", 5*time.Second)
+	v.SetDefault("httpclient.breakerthreshold", 5)
+	v.SetDefault("httpclient.breakercooldown", 30*time.Second)
+	v.SetDefault("httpclient.useragent", "")
+
+	// Circuit breakers
+	v.SetDefault("breaker.threshold", 5)
+	v.SetDefault("breaker.cooldown", 30*time.Second)
+	v.SetDefault("breaker.halfopenprobes", 1)
+
+	// Multi-tenancy
+	v.SetDefault("tenancy.enabled", false)
+	v.SetDefault("tenancy.source", TenantSourceHeader)
+	v.SetDefault("tenancy.header", "X-Tenant-ID")
+	v.SetDefault("tenancy.basedomain", "")
+	v.SetDefault("tenancy.claim", "tenant")
+	v.SetDefault("tenancy.tokensecret", "")
+	v.SetDefault("tenancy.required", true)
+	v.SetDefault("tenancy.database", "")
+	v.SetDefault("tenancy.schema", "")
+
+	// GeoIP
+	v.SetDefault("geoip.enabled", false)
+	v.SetDefault("geoip.database", "GeoLite2-City.mmdb")
+
+	// Uptime
+	v.SetDefault("uptime.enabled", false)
+	v.SetDefault("uptime.urls", "")
+	v.SetDefault("uptime.interval", "1m")
+	v.SetDefault("uptime.timeout", "10s")
+
+	// I18n
+	v.SetDefault("i18n.directory", "locales")
+	v.SetDefault("i18n.defaultlocale", "en")
+	v.SetDefault("i18n.localeparam", "locale")
+
+	// Views
+	v.SetDefault("views.directory", "views")
+	v.SetDefault("views.layout", "layouts/main.html")
+	v.SetDefault("views.reload", false)
+
+	// OpenAPI
+	v.SetDefault("openapi.title", "")
+	v.SetDefault("openapi.version", "1.0.0")
+	v.SetDefault("openapi.description", "Go ActionHero API Server")
+	v.SetDefault("openapi.contactname", "")
+	v.SetDefault("openapi.contactemail", "")
+	v.SetDefault("openapi.contacturl", "")
+	v.SetDefault("openapi.license", "MIT")
+	v.SetDefault("openapi.licenseurl", "")
+	v.SetDefault("openapi.servers", "")
+
+	// Secrets
+	v.SetDefault("secrets.encryptionkey", "")
+	v.SetDefault("secrets.vaultaddress", "")
+	v.SetDefault("secrets.vaulttoken", "")
+	v.SetDefault("secrets.vaultnamespace", "")
+	v.SetDefault("secrets.awsregion", "")
+	v.SetDefault("secrets.awsendpoint", "")
+	v.SetDefault("secrets.cachettl", 5*time.Minute)
+	v.SetDefault("secrets.refresh", time.Duration(0))
+	v.SetDefault("secrets.timeout", 5*time.Second)
 }
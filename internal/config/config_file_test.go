@@ -0,0 +1,205 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+}
+
+func TestLoad_ConfigFileFormats(t *testing.T) {
+	tests := []struct {
+		file    string
+		content string
+	}{
+		{"config.yaml", "process:\n  name: from-yaml-file\nserver:\n  web:\n    port: 9001\n"},
+		{"config.toml", "[process]\nname = \"from-toml-file\"\n\n[server.web]\nport = 9001\n"},
+		{"config.json", `{"process": {"name": "from-json-file"}, "server": {"web": {"port": 9001}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			os.Clearenv()
+			writeConfigFile(t, tt.file, tt.content)
+
+			cfg, err := Load()
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if cfg.Server.Web.Port != 9001 {
+				t.Errorf("Expected port 9001 from %s, got %d", tt.file, cfg.Server.Web.Port)
+			}
+			if cfg.Process.Name == defaultProcessName {
+				t.Errorf("Expected process name from %s, got the default", tt.file)
+			}
+		})
+	}
+}
+
+func TestLoad_EnvironmentConfigFileMixedFormats(t *testing.T) {
+	t.Chdir(t.TempDir())
+	os.Clearenv()
+	_ = os.Setenv("GO_ENV", "staging")
+
+	writeConfigFile(t, "config.yaml", "process:\n  name: base\nserver:\n  web:\n    port: 9001\n")
+	writeConfigFile(t, "config.staging.toml", "[server.web]\nport = 9002\n")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Process.Name != "base" {
+		t.Errorf("Expected base process name to be kept, got %s", cfg.Process.Name)
+	}
+	if cfg.Server.Web.Port != 9002 {
+		t.Errorf("Expected environment config to override port, got %d", cfg.Server.Web.Port)
+	}
+}
+
+func TestLoad_ConfigFileRemoved(t *testing.T) {
+	t.Chdir(t.TempDir())
+	os.Clearenv()
+
+	writeConfigFile(t, "config.json", `{"server": {"web": {"port": 9001}}}`)
+	if _, err := Load(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Reloading after the file is removed falls back to defaults
+	_ = os.Remove("config.json")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Server.Web.Port != 8080 {
+		t.Errorf("Expected default port after removing config file, got %d", cfg.Server.Web.Port)
+	}
+}
+
+func TestLoad_InvalidConfigFile(t *testing.T) {
+	t.Chdir(t.TempDir())
+	os.Clearenv()
+
+	writeConfigFile(t, "config.toml", "this is = = not toml")
+	if _, err := Load(); err == nil {
+		t.Error("Expected an error for an invalid config file")
+	}
+}
+
+func TestLoad_WithConfigFile(t *testing.T) {
+	t.Chdir(t.TempDir())
+	os.Clearenv()
+
+	writeConfigFile(t, "config.yaml", "process:\n  name: searched\n")
+	writeConfigFile(t, "custom.json", `{"process": {"name": "explicit"}}`)
+
+	cfg, err := Load(WithConfigFile("custom.json"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Process.Name != "explicit" {
+		t.Errorf("Expected process name from the explicit file, got %s", cfg.Process.Name)
+	}
+
+	if _, err := Load(WithConfigFile("missing.yaml")); err == nil {
+		t.Error("Expected a missing explicit config file to fail")
+	}
+}
+
+func TestLoad_ConfigDir(t *testing.T) {
+	t.Chdir(t.TempDir())
+	os.Clearenv()
+	defer os.Clearenv()
+	_ = os.Setenv("GO_ENV", "production")
+
+	if err := os.Mkdir("config", 0755); err != nil {
+		t.Fatalf("Failed to create config dir: %v", err)
+	}
+	writeConfigFile(t, "config/default.yaml", "process:\n  name: base\nlogger:\n  level: debug\nserver:\n  web:\n    port: 9001\n")
+	writeConfigFile(t, "config/production.toml", "[logger]\nlevel = \"warn\"\n[server.web]\nport = 9002\n")
+	writeConfigFile(t, "config/test.yaml", "server:\n  web:\n    port: 9999\n")
+	writeConfigFile(t, "config/local.yaml", "server:\n  web:\n    port: 9003\n")
+	writeConfigFile(t, "config/local-production.json", `{"logger": {"level": "error"}}`)
+
+	// The single-file convention is ignored when the config dir has files
+	writeConfigFile(t, "config.yaml", "process:\n  name: legacy\n")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Process.Name != "base" {
+		t.Errorf("Expected process name from default.yaml, got %s", cfg.Process.Name)
+	}
+	if cfg.Server.Web.Port != 9003 {
+		t.Errorf("Expected port from local.yaml, got %d", cfg.Server.Web.Port)
+	}
+	if cfg.Logger.Level != "error" {
+		t.Errorf("Expected level from local-production.json, got %s", cfg.Logger.Level)
+	}
+
+	expected := []string{"config/default.yaml", "config/production.toml", "config/local.yaml", "config/local-production.json"}
+	if strings.Join(cfg.Files, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected files %v in merge order, got %v", expected, cfg.Files)
+	}
+	if source := cfg.Sources["logger.level"]; source.Name != "config/local-production.json" {
+		t.Errorf("Expected logger.level from local-production.json, got %s", source)
+	}
+}
+
+func TestLoad_ConfigDirFallsBackToConfigFile(t *testing.T) {
+	t.Chdir(t.TempDir())
+	os.Clearenv()
+
+	writeConfigFile(t, "config.yaml", "process:\n  name: legacy\n")
+	cfg, err := Load(WithConfigDir("missing"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Process.Name != "legacy" || len(cfg.Files) != 1 {
+		t.Errorf("Expected config.yaml to be read, got %s from %v", cfg.Process.Name, cfg.Files)
+	}
+}
+
+func TestLoad_WithOverrides(t *testing.T) {
+	t.Chdir(t.TempDir())
+	os.Clearenv()
+	defer os.Clearenv()
+
+	writeConfigFile(t, "config.yaml", "server:\n  web:\n    port: 9001\n")
+	_ = os.Setenv("ACTIONHERO_LOGGER_LEVEL", "warn")
+
+	cfg, err := Load(WithOverrides(map[string]string{
+		"server.web.port": "9002",
+		"Logger.Level":    "debug",
+		"tasks.timeout":   "45s",
+		"tasks.queues":    "high,low",
+	}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Server.Web.Port != 9002 {
+		t.Errorf("Expected override to beat the config file, got port %d", cfg.Server.Web.Port)
+	}
+	if cfg.Logger.Level != "debug" {
+		t.Errorf("Expected override to beat the environment, got level %s", cfg.Logger.Level)
+	}
+	if cfg.Tasks.Timeout != 45*time.Second {
+		t.Errorf("Expected tasks timeout 45s, got %s", cfg.Tasks.Timeout)
+	}
+	if len(cfg.Tasks.Queues) != 2 || cfg.Tasks.Queues[1] != "low" {
+		t.Errorf("Expected queues [high low], got %v", cfg.Tasks.Queues)
+	}
+
+	if _, err := Load(WithOverrides(map[string]string{"server.web.prot": "9000"})); err == nil {
+		t.Error("Expected an unknown key to fail")
+	}
+}
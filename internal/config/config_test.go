@@ -2,7 +2,9 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 const (
@@ -73,6 +75,56 @@ func TestLoad_EnvironmentVariables(t *testing.T) {
 	}
 }
 
+func TestLoad_WithEnvPrefix(t *testing.T) {
+	os.Clearenv()
+	_ = os.Setenv("MYAPP_PROCESS_NAME", "prefixed-app")
+	_ = os.Setenv("ACTIONHERO_PROCESS_NAME", "ignored-app")
+	defer os.Clearenv()
+
+	cfg, err := Load(WithEnvPrefix("MYAPP"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if cfg.Process.Name != "prefixed-app" {
+		t.Errorf("Expected process name 'prefixed-app', got %v", cfg.Process.Name)
+	}
+}
+
+func TestLoad_IndependentInstances(t *testing.T) {
+	t.Chdir(t.TempDir())
+	os.Clearenv()
+
+	// Two config directories, each loaded with its own paths
+	for _, dir := range []string{"one", "two"} {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+		writeConfigFile(t, filepath.Join(dir, "config.yaml"), "process:\n  name: app-"+dir+"\n")
+	}
+
+	one, err := Load(WithPaths("one"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	two, err := Load(WithPaths("two"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defaults, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if one.Process.Name != "app-one" || two.Process.Name != "app-two" {
+		t.Errorf("Expected app-one and app-two, got %s and %s", one.Process.Name, two.Process.Name)
+	}
+	// Values from earlier loads must not leak into later ones
+	if defaults.Process.Name != defaultProcessName {
+		t.Errorf("Expected default process name, got %s", defaults.Process.Name)
+	}
+}
+
 func TestDefaultConfigs(t *testing.T) {
 	tests := []struct {
 		name string
@@ -130,8 +182,8 @@ func TestDefaultConfigs(t *testing.T) {
 				if cfg.CookieName != defaultProcessName {
 					t.Errorf("Expected cookie name '%s', got %v", defaultProcessName, cfg.CookieName)
 				}
-				if cfg.TTL != 86400 {
-					t.Errorf("Expected TTL 86400, got %v", cfg.TTL)
+				if cfg.TTL != 24*time.Hour {
+					t.Errorf("Expected TTL 24h, got %v", cfg.TTL)
 				}
 			},
 		},
@@ -9,6 +9,9 @@ type DatabaseConfig struct {
 	Password string
 	Database string
 	SSLMode  string
+
+	Fixtures    string // File or directory of fixtures loaded by "actionhero db seed"
+	SeedOnStart bool   // Load the fixtures when the API starts (e.g., for demos)
 }
 
 // DefaultDatabaseConfig returns default database configuration
@@ -21,5 +24,8 @@ func DefaultDatabaseConfig() DatabaseConfig {
 		Password: "",
 		Database: "actionhero",
 		SSLMode:  "disable",
+
+		Fixtures:    "fixtures",
+		SeedOnStart: false,
 	}
 }
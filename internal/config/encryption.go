@@ -0,0 +1,114 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// encryptedPrefix marks a config value encrypted with EncryptValue
+const encryptedPrefix = "enc:"
+
+// encryptionKeySize is the key size for AES-256
+const encryptionKeySize = 32
+
+// GenerateEncryptionKey returns a new random key for secrets.encryptionkey
+func GenerateEncryptionKey() (string, error) {
+	key := make([]byte, encryptionKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate encryption key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// EncryptValue encrypts plaintext with the key in cfg.EncryptionKey, returning
+// an "enc:..." value that is decrypted when config is loaded
+func EncryptValue(cfg SecretsConfig, plaintext string) (string, error) {
+	r := &secretResolver{cfg: cfg, providers: make(map[string]SecretProvider)}
+	key, err := r.encryptionKey()
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// isEncrypted returns whether a config value was encrypted with EncryptValue
+func isEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+// decryptValue decrypts an "enc:..." value with key
+func decryptValue(key []byte, value string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil {
+		return "", errors.New("encrypted value is not valid base64")
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("encrypted value is too short")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", errors.New("failed to decrypt value (wrong secrets.encryptionkey?)")
+	}
+	return string(plaintext), nil
+}
+
+// newGCM returns an AES-GCM cipher for key
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptionKey returns the decoded secrets.encryptionkey, resolving it first
+// when it's a secret reference (e.g., a key kept in Vault or Secrets Manager)
+func (r *secretResolver) encryptionKey() ([]byte, error) {
+	if r.key != nil || r.keyErr != nil {
+		return r.key, r.keyErr
+	}
+	r.key, r.keyErr = r.loadEncryptionKey()
+	return r.key, r.keyErr
+}
+
+// loadEncryptionKey decodes (and resolves) secrets.encryptionkey
+func (r *secretResolver) loadEncryptionKey() ([]byte, error) {
+	encoded := strings.TrimSpace(r.cfg.EncryptionKey)
+	if encoded == "" {
+		return nil, errors.New("secrets.encryptionkey is not set (set ACTIONHERO_SECRETS_ENCRYPTIONKEY)")
+	}
+	if ref, ok := ParseSecretRef(encoded); ok {
+		value, err := r.resolve(ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve secrets.encryptionkey from %s: %w", ref, err)
+		}
+		encoded = strings.TrimSpace(value)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) != encryptionKeySize {
+		return nil, fmt.Errorf("secrets.encryptionkey must be %d base64-encoded bytes (generate one with `actionhero config encrypt --generate-key`)", encryptionKeySize)
+	}
+	return key, nil
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestEncryptValue_RoundTrip(t *testing.T) {
+	key, err := GenerateEncryptionKey()
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	encrypted, err := EncryptValue(SecretsConfig{EncryptionKey: key}, "s3cret")
+	if err != nil {
+		t.Fatalf("Failed to encrypt: %v", err)
+	}
+	if !strings.HasPrefix(encrypted, "enc:") || strings.Contains(encrypted, "s3cret") {
+		t.Fatalf("Expected an opaque enc: value, got %s", encrypted)
+	}
+
+	other, _ := EncryptValue(SecretsConfig{EncryptionKey: key}, "s3cret")
+	if other == encrypted {
+		t.Error("Expected each encryption to use a new nonce")
+	}
+
+	r := &secretResolver{cfg: SecretsConfig{EncryptionKey: key}}
+	decoded, _ := r.encryptionKey()
+	plaintext, err := decryptValue(decoded, encrypted)
+	if err != nil || plaintext != "s3cret" {
+		t.Errorf("Expected s3cret, got %q (%v)", plaintext, err)
+	}
+
+	wrongKey, _ := GenerateEncryptionKey()
+	r = &secretResolver{cfg: SecretsConfig{EncryptionKey: wrongKey}}
+	decoded, _ = r.encryptionKey()
+	if _, err := decryptValue(decoded, encrypted); err == nil {
+		t.Error("Expected decrypting with the wrong key to fail")
+	}
+}
+
+func TestLoad_DecryptsValues(t *testing.T) {
+	resetSecretCache(t)
+	t.Chdir(t.TempDir())
+	os.Clearenv()
+	defer os.Clearenv()
+
+	key, _ := GenerateEncryptionKey()
+	encrypted, err := EncryptValue(SecretsConfig{EncryptionKey: key}, "db-password")
+	if err != nil {
+		t.Fatalf("Failed to encrypt: %v", err)
+	}
+	writeConfigFile(t, "config.yaml", "database:\n  password: \""+encrypted+"\"\n")
+
+	// Without the key, loading fails
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "database.password: secrets.encryptionkey is not set") {
+		t.Errorf("Expected missing key error, got %v", err)
+	}
+
+	// The key can come from a secret provider
+	RegisterSecretProvider("testkey", func(SecretsConfig) (SecretProvider, error) {
+		return staticSecretProvider{"config-key": key}, nil
+	})
+	_ = os.Setenv("ACTIONHERO_SECRETS_ENCRYPTIONKEY", "testkey://config-key")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Database.Password != "db-password" {
+		t.Errorf("Expected decrypted password, got %s", cfg.Database.Password)
+	}
+}
+
+func TestLoad_InvalidEncryptionKey(t *testing.T) {
+	os.Clearenv()
+	defer os.Clearenv()
+
+	_ = os.Setenv("ACTIONHERO_SECRETS_ENCRYPTIONKEY", "too-short")
+	_ = os.Setenv("ACTIONHERO_REDIS_PASSWORD", "enc:AAAA")
+
+	_, err := Load()
+	if err == nil || !strings.Contains(err.Error(), "must be 32 base64-encoded bytes") {
+		t.Errorf("Expected invalid key error, got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// EnvVar documents the environment variable that sets a config setting
+type EnvVar struct {
+	Name    string `json:"name"`    // e.g., ACTIONHERO_SERVER_WEB_PORT
+	Key     string `json:"key"`     // e.g., server.web.port
+	Type    string `json:"type"`    // e.g., string, int, bool, duration, size, or list
+	Default string `json:"default"` // The default value, as it would be written in the variable
+}
+
+// EnvVarName returns the environment variable (with prefix, e.g.,
+// DefaultEnvPrefix) that sets key
+func EnvVarName(prefix, key string) string {
+	return prefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// EnvVars documents the environment variable of every setting, named with
+// prefix and sorted by name, including registered sections and servers. It
+// reflects over Config and the loaded defaults, so it stays in step with
+// what Load reads.
+func EnvVars(prefix string) ([]EnvVar, error) {
+	cfg, err := defaults()
+	if err != nil {
+		return nil, err
+	}
+
+	var vars []EnvVar
+	for _, key := range cfg.Keys() {
+		value, ok := cfg.Get(key)
+		if !ok {
+			continue
+		}
+		vars = append(vars, EnvVar{
+			Name:    EnvVarName(prefix, key),
+			Key:     key,
+			Type:    envVarType(reflect.TypeOf(value)),
+			Default: envVarValue(value),
+		})
+	}
+	for name, enabled := range cfg.Server.Enabled {
+		key := "server." + name + ".enabled"
+		vars = append(vars, EnvVar{Name: EnvVarName(prefix, key), Key: key, Type: "bool", Default: envVarValue(enabled)})
+	}
+
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	return vars, nil
+}
+
+// defaults returns the config as loaded from defaults alone, without config
+// files, environment variables, or overrides
+func defaults() (*Config, error) {
+	cfg := defaultConfig()
+	v := viper.New()
+	setDefaults(v)
+	setSectionDefaults(v)
+	setServerDefaults(v)
+	if err := normalizeDurations(v, DefaultEnvPrefix); err != nil {
+		return nil, fmt.Errorf("error reading config: %w", err)
+	}
+	if err := v.Unmarshal(cfg, viper.DecodeHook(decodeHooks)); err != nil {
+		return nil, fmt.Errorf("error unmarshaling config: %w", err)
+	}
+	if err := loadSections(v, cfg); err != nil {
+		return nil, err
+	}
+	loadServers(v, cfg)
+	return cfg, nil
+}
+
+// envVarType names the type of values a setting takes
+func envVarType(t reflect.Type) string {
+	switch t {
+	case reflect.TypeOf(time.Duration(0)):
+		return "duration"
+	case reflect.TypeOf(ByteSize(0)):
+		return "size"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "int"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	case reflect.String:
+		return "string"
+	case reflect.Slice:
+		return "list" // Comma-separated
+	default:
+		return t.String()
+	}
+}
+
+// envVarValue formats a value as it would be written in an environment
+// variable: durations like "30s", sizes like "100MB", and lists
+// comma-separated
+func envVarValue(value interface{}) string {
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Slice {
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = fmt.Sprint(v.Index(i).Interface())
+		}
+		return strings.Join(items, ",")
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestEnvVars(t *testing.T) {
+	t.Chdir(t.TempDir())
+	os.Clearenv()
+
+	if err := RegisterSection("envdocs", &pluginConfig{}, pluginConfig{Retries: 3, Timeout: time.Second, Tags: []string{"a", "b"}}); err != nil {
+		t.Fatalf("Failed to register section: %v", err)
+	}
+	if err := RegisterServer("envdocsserver"); err != nil {
+		t.Fatalf("Failed to register server: %v", err)
+	}
+	// Defaults don't depend on the environment
+	_ = os.Setenv("ACTIONHERO_SERVER_WEB_PORT", "9999")
+
+	vars, err := EnvVars("MYAPP")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	byName := make(map[string]EnvVar, len(vars))
+	for i, v := range vars {
+		byName[v.Name] = v
+		if i > 0 && vars[i-1].Name >= v.Name {
+			t.Errorf("Expected variables sorted by name, got %s before %s", vars[i-1].Name, v.Name)
+		}
+	}
+
+	for _, want := range []EnvVar{
+		{Name: "MYAPP_SERVER_WEB_PORT", Key: "server.web.port", Type: "int", Default: "8080"},
+		{Name: "MYAPP_SERVER_WEB_ENABLED", Key: "server.web.enabled", Type: "bool", Default: "true"},
+		{Name: "MYAPP_TASKS_QUEUES", Key: "tasks.queues", Type: "list", Default: "default"},
+		{Name: "MYAPP_TASKS_TIMEOUT", Key: "tasks.timeout", Type: "duration", Default: "10s"},
+		{Name: "MYAPP_SERVER_WEB_MAXBODYSIZE", Key: "server.web.maxbodysize", Type: "size", Default: "10MB"},
+		{Name: "MYAPP_ENVDOCS_RETRIES", Key: "envdocs.retries", Type: "int", Default: "3"},
+		{Name: "MYAPP_ENVDOCS_TAGS", Key: "envdocs.tags", Type: "list", Default: "a,b"},
+		{Name: "MYAPP_ENVDOCS_LIMITS_BURST", Key: "envdocs.limits.burst", Type: "int", Default: "0"},
+		{Name: "MYAPP_SERVER_ENVDOCSSERVER_ENABLED", Key: "server.envdocsserver.enabled", Type: "bool", Default: "true"},
+	} {
+		if got := byName[want.Name]; got != want {
+			t.Errorf("Expected %+v, got %+v", want, got)
+		}
+	}
+	if _, ok := byName["MYAPP_SERVER_ENABLED"]; ok {
+		t.Error("Expected no variable for the server.enabled map")
+	}
+
+	// Every setting Load reads is documented
+	cfg, err := LoadWithoutValidation()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for key := range cfg.Sources {
+		if _, ok := byName[EnvVarName("MYAPP", key)]; !ok {
+			t.Errorf("Expected %s to be documented", key)
+		}
+	}
+}
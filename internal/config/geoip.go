@@ -0,0 +1,16 @@
+package config
+
+// GeoIPConfig holds configuration for locating clients by IP with a MaxMind
+// database (see API.GeoIP)
+type GeoIPConfig struct {
+	Enabled  bool
+	Database string // Path of a MaxMind DB file (e.g., GeoLite2-City.mmdb or GeoLite2-Country.mmdb)
+}
+
+// DefaultGeoIPConfig returns default GeoIP configuration
+func DefaultGeoIPConfig() GeoIPConfig {
+	return GeoIPConfig{
+		Enabled:  false,
+		Database: "GeoLite2-City.mmdb",
+	}
+}
@@ -0,0 +1,32 @@
+package config
+
+import "time"
+
+// HTTPClientConfig holds configuration for the outbound HTTP client actions
+// use to call other services (see API.HTTPClient)
+type HTTPClientConfig struct {
+	Timeout         time.Duration // Limit for a whole request, including retries (0 = none)
+	Retries         int           // Retries of failed idempotent requests (0 = never retry)
+	RetryBackoff    time.Duration // Wait before the first retry, doubled for each one after
+	RetryMaxBackoff time.Duration // Longest wait between retries (including Retry-After)
+	// BreakerThreshold is how many requests in a row to a host may fail
+	// before requests to it fail fast (0 = never)
+	BreakerThreshold int
+	// BreakerCooldown is how long requests to a host fail fast before one is
+	// let through to test it
+	BreakerCooldown time.Duration
+	UserAgent       string // User-Agent of requests that don't set one ("" = Go's default)
+}
+
+// DefaultHTTPClientConfig returns default HTTP client configuration
+func DefaultHTTPClientConfig() HTTPClientConfig {
+	return HTTPClientConfig{
+		Timeout:          30 * time.Second,
+		Retries:          2,
+		RetryBackoff:     100 * time.Millisecond,
+		RetryMaxBackoff:  5 * time.Second,
+		BreakerThreshold: 5,
+		BreakerCooldown:  30 * time.Second,
+		UserAgent:        "",
+	}
+}
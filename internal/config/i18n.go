@@ -0,0 +1,18 @@
+package config
+
+// I18nConfig holds configuration for translating messages (api.T and error
+// messages)
+type I18nConfig struct {
+	Directory     string // Message catalogs, one per locale (e.g., fr.yaml, pt-BR.json); skipped when missing
+	DefaultLocale string // Tried after the client's locales; empty for none
+	LocaleParam   string // Param (or sticky param) that picks the locale before Accept-Language; empty to disable
+}
+
+// DefaultI18nConfig returns default i18n configuration
+func DefaultI18nConfig() I18nConfig {
+	return I18nConfig{
+		Directory:     "locales",
+		DefaultLocale: "en",
+		LocaleParam:   "locale",
+	}
+}
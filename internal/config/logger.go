@@ -1,17 +1,84 @@
 package config
 
+import "time"
+
 // LoggerConfig holds logger configuration
 type LoggerConfig struct {
 	Level     string // debug, info, warn, error, fatal
 	Colorize  bool   // Enable colored output
 	Timestamp bool   // Include timestamps in logs
+	// Format is text, json, logfmt, or a registered formatter ("" = text
+	// when colorizing, json otherwise)
+	Format string
+	// SlowAction logs a warning for actions slower than this (0 = disabled)
+	SlowAction time.Duration
+	// ErrorSampleFirst logs the first N identical errors per window (0 = no sampling)
+	ErrorSampleFirst int
+	// ErrorSampleThereafter logs every Nth identical error after the first ones (0 = none)
+	ErrorSampleThereafter int
+	// ErrorSampleWindow is the sampling window; suppressed counts are summarized at its end
+	ErrorSampleWindow time.Duration
+	// AsyncBuffer writes stdout and file logs in the background, buffering up to
+	// this many entries; entries that don't fit are dropped (0 = synchronous)
+	AsyncBuffer int
+
+	// File also writes logs, as JSON lines, to this file ("" = stdout only)
+	File string
+	// Stdout keeps writing to stdout when File, Syslog, or Journald is set
+	Stdout bool
+	// FileMaxSize rotates the file when it would grow past this size (0 = no limit)
+	FileMaxSize ByteSize
+	// FileMaxAge rotates the file when it has been written to for this long (0 = no limit)
+	FileMaxAge time.Duration
+	// FileMaxBackups is how many rotated files to keep (0 = keep all)
+	FileMaxBackups int
+	// FileCompress gzips rotated files
+	FileCompress bool
+
+	// Syslog also sends logs to syslog: "local" for the local daemon, or
+	// udp://host:port or tcp://host:port for a remote one ("" = disabled)
+	Syslog string
+	// SyslogTag identifies the process in syslog and journald
+	SyslogTag string
+	// SyslogFacility is the syslog facility (e.g., "daemon", "local0")
+	SyslogFacility string
+	// Journald also sends logs, with their fields, to systemd-journald
+	Journald bool
+
+	// Hooks enables registered log hooks by name, comma-separated (e.g., "loki,slack")
+	Hooks string
+}
+
+// SyslogFacilities lists the syslog facility names, indexed by facility code
+// (codes 12-15 are unnamed)
+var SyslogFacilities = []string{
+	"kern", "user", "mail", "daemon", "auth", "syslog", "lpr", "news",
+	"uucp", "cron", "authpriv", "ftp", "", "", "", "",
+	"local0", "local1", "local2", "local3", "local4", "local5", "local6", "local7",
 }
 
 // DefaultLoggerConfig returns default logger configuration
 func DefaultLoggerConfig() LoggerConfig {
 	return LoggerConfig{
-		Level:     "info",
-		Colorize:  true,
-		Timestamp: true,
+		Level:                 "info",
+		Colorize:              true,
+		Timestamp:             true,
+		Format:                "",
+		SlowAction:            time.Second,
+		ErrorSampleFirst:      100,
+		ErrorSampleThereafter: 100,
+		ErrorSampleWindow:     time.Second,
+		AsyncBuffer:           0,
+		File:                  "",
+		Stdout:                true,
+		FileMaxSize:           100 * Megabyte,
+		FileMaxAge:            0,
+		FileMaxBackups:        10,
+		FileCompress:          false,
+		Syslog:                "",
+		SyslogTag:             "actionhero",
+		SyslogFacility:        "local0",
+		Journald:              false,
+		Hooks:                 "",
 	}
 }
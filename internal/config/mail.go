@@ -0,0 +1,39 @@
+package config
+
+// MailConfig holds configuration for the mailer (api.Mail)
+type MailConfig struct {
+	Enabled   bool
+	Transport string // smtp or ses
+	From      string // Default sender (e.g., "My App <noreply@example.com>")
+	Templates string // Directory of templates: <name>.subject.tmpl, <name>.txt.tmpl, and/or <name>.html.tmpl
+	Async     bool   // Deliver from a background queue instead of during Send
+	QueueSize int    // Messages queued for background delivery before Send fails
+
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUser     string // Authenticates with PLAIN when set
+	SMTPPassword string
+
+	SESRegion   string // Falls back to AWS_REGION; credentials come from AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
+	SESEndpoint string // Overrides the SES endpoint (e.g., for a local emulator)
+}
+
+// DefaultMailConfig returns default mail configuration
+func DefaultMailConfig() MailConfig {
+	return MailConfig{
+		Enabled:   false,
+		Transport: "smtp",
+		From:      "",
+		Templates: "templates/mail",
+		Async:     false,
+		QueueSize: 100,
+
+		SMTPHost:     "localhost",
+		SMTPPort:     587,
+		SMTPUser:     "",
+		SMTPPassword: "",
+
+		SESRegion:   "",
+		SESEndpoint: "",
+	}
+}
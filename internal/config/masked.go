@@ -0,0 +1,57 @@
+package config
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// sensitiveFieldPattern matches the names of settings that hold secrets
+var sensitiveFieldPattern = regexp.MustCompile(`(?i)password|secret|token|key|dsn`)
+
+// IsSensitive reports whether a setting holds a secret, by its field name or
+// key (e.g., "SMTPPassword" or "mail.smtppassword")
+func IsSensitive(name string) bool {
+	return sensitiveFieldPattern.MatchString(name[strings.LastIndex(name, ".")+1:])
+}
+
+// Mask hides a secret, keeping its length ("(empty)" when there is none)
+func Mask(secret string) string {
+	if secret == "" {
+		return "(empty)"
+	}
+	return strings.Repeat("*", len(secret))
+}
+
+// SourcedValue is a setting's value and where it came from
+type SourcedValue struct {
+	Value  interface{} `json:"value"`
+	Source string      `json:"source"`
+}
+
+// MaskedValues returns every setting by key (see Keys) with where it came
+// from. Secrets are masked, and durations and sizes are formatted, so the
+// values can be shown to operators or encoded as JSON.
+func (c *Config) MaskedValues() map[string]SourcedValue {
+	values := make(map[string]SourcedValue)
+	for _, key := range c.Keys() {
+		value, _ := c.Get(key)
+		switch v := value.(type) {
+		case string:
+			if IsSensitive(key) && v != "" {
+				value = Mask(v)
+			}
+		case time.Duration:
+			value = v.String()
+		case ByteSize:
+			value = v.String()
+		}
+
+		source := Source{Kind: SourceDefault}
+		if s, ok := c.Sources[key]; ok {
+			source = s
+		}
+		values[key] = SourcedValue{Value: value, Source: source.String()}
+	}
+	return values
+}
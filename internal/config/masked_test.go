@@ -0,0 +1,71 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		want     string
+	}{
+		{
+			name:     "empty password",
+			password: "",
+			want:     "(empty)",
+		},
+		{
+			name:     "short password",
+			password: "abc",
+			want:     "***",
+		},
+		{
+			name:     "long password",
+			password: "verylongpassword123",
+			want:     strings.Repeat("*", len("verylongpassword123")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Mask(tt.password)
+			if got != tt.want {
+				t.Errorf("Mask(%q) = %q, want %q", tt.password, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsSensitive(t *testing.T) {
+	for _, name := range []string{"SMTPPassword", "mail.smtppassword", "secrets.encryptionkey", "sentry.dsn", "VaultToken"} {
+		if !IsSensitive(name) {
+			t.Errorf("Expected %s to be sensitive", name)
+		}
+	}
+	for _, name := range []string{"server.web.port", "Host", "keyless.host"} {
+		if IsSensitive(name) {
+			t.Errorf("Expected %s not to be sensitive", name)
+		}
+	}
+}
+
+func TestConfig_MaskedValues(t *testing.T) {
+	cfg := validConfig()
+	cfg.Database.Password = "hunter2"
+	cfg.Server.Web.ReapInterval = 30 * time.Second
+	cfg.Sources = map[string]Source{"database.password": {Kind: SourceEnv, Name: "ACTIONHERO_DATABASE_PASSWORD"}}
+
+	values := cfg.MaskedValues()
+	if got := values["database.password"]; got.Value != "*******" || !strings.Contains(got.Source, "ACTIONHERO_DATABASE_PASSWORD") {
+		t.Errorf("Expected a masked password from the env, got %+v", got)
+	}
+	if got := values["server.web.reapinterval"]; got.Value != "30s" {
+		t.Errorf("Expected a formatted duration, got %+v", got)
+	}
+	if got := values["server.web.port"]; got.Value != cfg.Server.Web.Port || got.Source != (Source{Kind: SourceDefault}).String() {
+		t.Errorf("Expected the port from the defaults, got %+v", got)
+	}
+}
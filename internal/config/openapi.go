@@ -0,0 +1,42 @@
+package config
+
+import "strings"
+
+// OpenAPIConfig holds the info and servers sections of the swagger document
+type OpenAPIConfig struct {
+	Title        string // API title (default: the process name)
+	Version      string // API version (not the OpenAPI spec version)
+	Description  string
+	ContactName  string
+	ContactEmail string
+	ContactURL   string
+	License      string // License name (e.g., "MIT")
+	LicenseURL   string
+	Servers      string // Comma-separated server URLs (default: the web server's host and port)
+}
+
+// DefaultOpenAPIConfig returns default OpenAPI configuration
+func DefaultOpenAPIConfig() OpenAPIConfig {
+	return OpenAPIConfig{
+		Title:        "",
+		Version:      "1.0.0",
+		Description:  "Go ActionHero API Server",
+		ContactName:  "",
+		ContactEmail: "",
+		ContactURL:   "",
+		License:      "MIT",
+		LicenseURL:   "",
+		Servers:      "",
+	}
+}
+
+// ServerURLs returns the configured server URLs
+func (c OpenAPIConfig) ServerURLs() []string {
+	var urls []string
+	for _, url := range strings.Split(c.Servers, ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// SourceKind is where a config value came from
+type SourceKind string
+
+// Config value sources, from lowest to highest precedence
+const (
+	SourceDefault  SourceKind = "default"
+	SourceFile     SourceKind = "file"
+	SourceEnv      SourceKind = "env"
+	SourceOverride SourceKind = "override"
+)
+
+// Source describes where a config value came from
+type Source struct {
+	Kind SourceKind
+	Name string // Config file path or environment variable name (empty for defaults and overrides)
+	From string // For env vars set by a .env file, that file
+}
+
+// String describes the source (e.g., "env ACTIONHERO_SERVER_WEB_PORT (from .env)")
+func (s Source) String() string {
+	description := string(s.Kind)
+	if s.Name != "" {
+		description += " " + s.Name
+	}
+	if s.From != "" {
+		description += " (from " + s.From + ")"
+	}
+	return description
+}
+
+// provenance tracks which sources set each key during a load
+type provenance struct {
+	envPrefix string
+	files     []fileKeys // In the order read; later files win
+	overrides map[string]bool
+}
+
+// fileKeys is a config file and the keys it sets
+type fileKeys struct {
+	path string
+	keys map[string]bool
+}
+
+// addFile records the keys set by a config file
+func (p *provenance) addFile(path string) {
+	v := viper.New()
+	if err := readConfigFile(v, path, v.ReadConfig); err != nil {
+		return
+	}
+	keys := make(map[string]bool)
+	for _, key := range v.AllKeys() {
+		keys[key] = true
+	}
+	p.files = append(p.files, fileKeys{path: path, keys: keys})
+}
+
+// source returns where the value for key came from, following viper's
+// precedence: overrides, then env vars, then config files, then defaults
+func (p *provenance) source(key string) Source {
+	if p.overrides[key] {
+		return Source{Kind: SourceOverride}
+	}
+
+	envVar := EnvVarName(p.envPrefix, key)
+	if value, ok := os.LookupEnv(envVar); ok && value != "" {
+		return Source{Kind: SourceEnv, Name: envVar, From: dotenvFile(envVar)}
+	}
+
+	for i := len(p.files) - 1; i >= 0; i-- {
+		if p.files[i].keys[key] {
+			return Source{Kind: SourceFile, Name: p.files[i].path}
+		}
+	}
+
+	return Source{Kind: SourceDefault}
+}
+
+// sources returns the source of every key in v. Renamed keys report the
+// source of the old key when it was used.
+func (p *provenance) sources(v *viper.Viper) map[string]Source {
+	sources := make(map[string]Source)
+	for _, key := range v.AllKeys() {
+		if _, renamed := renamedDurationKeys[key]; renamed {
+			continue
+		}
+		sources[key] = p.source(key)
+	}
+
+	for oldKey, newKey := range renamedDurationKeys {
+		if sources[newKey].Kind == SourceDefault {
+			if old := p.source(oldKey); old.Kind != SourceDefault {
+				sources[newKey] = old
+			}
+		}
+	}
+	return sources
+}
+
+// Keys returns every setting key in the config, sorted (including registered sections)
+func (c *Config) Keys() []string {
+	var keys []string
+	collectKeys(reflect.ValueOf(*c), "", &keys)
+	for _, name := range sortedKeys(c.Sections) {
+		collectKeys(reflect.ValueOf(c.Sections[name]).Elem(), name, &keys)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// collectKeys appends the key of each leaf field of a struct
+func collectKeys(v reflect.Value, prefix string, keys *[]string) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		key := strings.ToLower(field.Name)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if !field.IsExported() || nonSettingFields[key] || field.Tag.Get("mapstructure") == "-" {
+			continue
+		}
+		if field.Type.Kind() == reflect.Struct {
+			collectKeys(v.Field(i), key, keys)
+			continue
+		}
+		*keys = append(*keys, key)
+	}
+}
+
+// Get returns the value of a setting by key (e.g., "server.web.port"),
+// including keys of registered sections
+func (c *Config) Get(key string) (interface{}, bool) {
+	name, rest, _ := strings.Cut(key, ".")
+	if section, ok := c.Sections[name]; ok && rest != "" {
+		field := fieldByKey(reflect.ValueOf(section).Elem(), rest)
+		if field.IsValid() {
+			return field.Interface(), true
+		}
+		return nil, false
+	}
+
+	if nonSettingFields[name] {
+		return nil, false
+	}
+	field := fieldByKey(reflect.ValueOf(c).Elem(), key)
+	if !field.IsValid() || field.Kind() == reflect.Struct {
+		return nil, false
+	}
+	return field.Interface(), true
+}
+
+// nonSettingFields are Config fields that hold load metadata rather than settings
+var nonSettingFields = map[string]bool{
+	"files":    true,
+	"sections": true,
+	"sources":  true,
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestLoad_Sources(t *testing.T) {
+	t.Chdir(t.TempDir())
+	os.Clearenv()
+	defer os.Clearenv()
+
+	writeConfigFile(t, "config.yaml", "database:\n  host: db.from.file\nlogger:\n  slowactionms: 250\n")
+	writeConfigFile(t, ".env", "ACTIONHERO_REDIS_HOST=redis.from.dotenv\n")
+	_ = os.Setenv("ACTIONHERO_PROCESS_NAME", "from-env")
+
+	cfg, err := Load(WithOverrides(map[string]string{"server.web.port": "9123"}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		key      string
+		expected Source
+	}{
+		{"logger.level", Source{Kind: SourceDefault}},
+		{"database.host", Source{Kind: SourceFile, Name: "config.yaml"}},
+		{"logger.slowaction", Source{Kind: SourceFile, Name: "config.yaml"}},
+		{"process.name", Source{Kind: SourceEnv, Name: "ACTIONHERO_PROCESS_NAME"}},
+		{"redis.host", Source{Kind: SourceEnv, Name: "ACTIONHERO_REDIS_HOST", From: ".env"}},
+		{"server.web.port", Source{Kind: SourceOverride}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := cfg.Sources[tt.key]; got != tt.expected {
+				t.Errorf("Expected source %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestConfig_KeysAndGet(t *testing.T) {
+	cfg := &Config{}
+	cfg.Server.Web.Port = 8080
+
+	keys := cfg.Keys()
+	found := false
+	for _, key := range keys {
+		if key == "server.web.port" {
+			found = true
+		}
+		if key == "sources" || key == "sections" {
+			t.Errorf("Expected %s not to be listed as a setting", key)
+		}
+	}
+	if !found {
+		t.Error("Expected server.web.port in keys")
+	}
+
+	if value, ok := cfg.Get("server.web.port"); !ok || value != 8080 {
+		t.Errorf("Expected 8080, got %v (%v)", value, ok)
+	}
+	if _, ok := cfg.Get("server.web"); ok {
+		t.Error("Expected no value for a group of settings")
+	}
+	if _, ok := cfg.Get("server.web.nope"); ok {
+		t.Error("Expected no value for an unknown key")
+	}
+}
@@ -0,0 +1,276 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadableKeys lists the settings that can be changed without a restart.
+// Everything else is reported as requiring a restart.
+var reloadableKeys = map[string]bool{
+	"logger.level":                 true,
+	"logger.slowaction":            true,
+	"process.actiontimeout":        true,
+	"process.errordisclosure":      true,
+	"server.web.allowedorigins":    true,
+	"server.web.allowedmethods":    true,
+	"server.web.allowedheaders":    true,
+	"server.web.allowedips":        true,
+	"server.web.deniedips":         true,
+	"server.web.metricsallowedips": true,
+	"server.web.debugallowedips":   true,
+	"server.web.adminallowedips":   true,
+	// WebSocket rate limits apply to connections opened after the reload
+	"server.web.messagerate":         true,
+	"server.web.messageburst":        true,
+	"server.web.messageratewarnings": true,
+}
+
+// Change describes a single setting that differs between two configurations
+type Change struct {
+	Key             string // Config key (e.g., "logger.level")
+	Old             interface{}
+	New             interface{}
+	RequiresRestart bool // The running process can't apply this change
+}
+
+// IsReloadable returns whether the setting can be changed without a restart
+func IsReloadable(key string) bool {
+	return reloadableKeys[key]
+}
+
+// Diff returns every setting that differs between old and next, keyed like
+// viper (lowercase, dot-separated), in field order. Registered sections
+// follow, by name.
+func Diff(old, next *Config) []Change {
+	var changes []Change
+	diffValues(reflect.ValueOf(*old), reflect.ValueOf(*next), "", &changes)
+
+	names := sortedKeys(next.Sections)
+	for _, name := range names {
+		oldSection, ok := old.Sections[name]
+		if !ok {
+			continue
+		}
+		oldValue, nextValue := reflect.ValueOf(oldSection).Elem(), reflect.ValueOf(next.Sections[name]).Elem()
+		if oldValue.Type() == nextValue.Type() {
+			diffValues(oldValue, nextValue, name, &changes)
+		}
+	}
+	return changes
+}
+
+// diffValues walks two values of the same struct type, collecting changed leaves
+func diffValues(old, next reflect.Value, prefix string, changes *[]Change) {
+	for i := 0; i < old.NumField(); i++ {
+		field := old.Type().Field(i)
+		key := strings.ToLower(field.Name)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if !field.IsExported() || nonSettingFields[key] {
+			continue
+		}
+
+		oldField, nextField := old.Field(i), next.Field(i)
+		if field.Type.Kind() == reflect.Struct {
+			diffValues(oldField, nextField, key, changes)
+			continue
+		}
+
+		if !reflect.DeepEqual(oldField.Interface(), nextField.Interface()) {
+			*changes = append(*changes, Change{
+				Key:             key,
+				Old:             oldField.Interface(),
+				New:             nextField.Interface(),
+				RequiresRestart: !IsReloadable(key),
+			})
+		}
+	}
+}
+
+// ApplyReloadable copies the reloadable changes into cfg, leaving settings
+// that require a restart untouched
+func ApplyReloadable(cfg *Config, changes []Change) {
+	for _, change := range changes {
+		if change.RequiresRestart {
+			continue
+		}
+		if field := fieldByKey(reflect.ValueOf(cfg).Elem(), change.Key); field.IsValid() && field.CanSet() {
+			field.Set(reflect.ValueOf(change.New))
+		}
+	}
+}
+
+// fieldByKey finds the struct field for a dot-separated, case-insensitive key
+func fieldByKey(v reflect.Value, key string) reflect.Value {
+	for _, part := range strings.Split(key, ".") {
+		v = v.FieldByNameFunc(func(name string) bool { return strings.EqualFold(name, part) })
+		if !v.IsValid() {
+			return v
+		}
+	}
+	return v
+}
+
+// configFilePattern matches the files Load reads: config(.env).{yaml,yml,toml,json} and .env(.*)
+var configFilePattern = regexp.MustCompile(`^(config(\.[\w-]+)?\.(ya?ml|json|toml)|\.env(\.[\w-]+)?)$`)
+
+// configExtensionPattern matches files in a config directory that Load may read
+var configExtensionPattern = regexp.MustCompile(`\.(ya?ml|json|toml)$`)
+
+// Watcher reloads configuration when config or .env files change
+type Watcher struct {
+	watcher    *fsnotify.Watcher
+	debounce   time.Duration
+	onReload   func(*Config, error)
+	opts       []LoadOption
+	configFile string        // Explicit config file (WithConfigFile), matched by path
+	configDirs []string      // Config directories, whose config-format files are all watched
+	refresh    time.Duration // Reload on this interval to pick up rotated secrets
+	done       chan struct{}
+}
+
+// Watch watches the config search paths and calls onReload with the newly
+// loaded config (or the error loading it) after each burst of changes.
+// opts are passed to Load on every reload. When secrets.refresh is set,
+// config is also reloaded on that interval, so rotated secrets are picked up.
+func Watch(onReload func(*Config, error), opts ...LoadOption) (*Watcher, error) {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config watcher: %w", err)
+	}
+
+	// Watch directories rather than files, so editors that replace files
+	// (write to a temp file, then rename) and newly created files are seen
+	options := newLoadOptions(opts)
+	dirs := append([]string{"."}, options.Paths...)
+	if options.ConfigDir != "" {
+		dirs = append(dirs, options.ConfigDir)
+	}
+	if options.ConfigFile != "" {
+		dirs = append(dirs, filepath.Dir(options.ConfigFile), options.ConfigFile)
+	}
+	watched := make(map[string]bool)
+	for _, dir := range dirs {
+		dir = filepath.Clean(os.ExpandEnv(dir))
+		if watched[dir] {
+			continue
+		}
+		watched[dir] = true
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			if err := fsWatcher.Add(dir); err != nil {
+				_ = fsWatcher.Close()
+				return nil, fmt.Errorf("failed to watch %s: %w", dir, err)
+			}
+		}
+	}
+
+	w := &Watcher{
+		watcher:  fsWatcher,
+		debounce: 250 * time.Millisecond,
+		onReload: onReload,
+		opts:     opts,
+		done:     make(chan struct{}),
+	}
+	if options.ConfigDir != "" {
+		w.configDirs = append(w.configDirs, absPath(os.ExpandEnv(options.ConfigDir)))
+	}
+	if options.ConfigFile != "" {
+		w.configFile = absPath(options.ConfigFile)
+		w.configDirs = append(w.configDirs, w.configFile)
+	}
+	if cfg, err := LoadWithoutValidation(opts...); err == nil {
+		w.refresh = cfg.Secrets.Refresh
+	}
+	go w.run()
+	return w, nil
+}
+
+// run reloads the config after changes settle, until the watcher is closed
+func (w *Watcher) run() {
+	defer close(w.done)
+
+	var debounce, refresh <-chan time.Time
+	if w.refresh > 0 {
+		ticker := time.NewTicker(w.refresh)
+		defer ticker.Stop()
+		refresh = ticker.C
+	}
+
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if w.isConfigFile(event.Name) &&
+				event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) != 0 {
+				debounce = time.After(w.debounce)
+			}
+
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			w.onReload(nil, fmt.Errorf("config watcher error: %w", err))
+
+		case <-debounce:
+			debounce = nil
+			w.reload()
+
+		case <-refresh:
+			w.reload()
+		}
+	}
+}
+
+// isConfigFile returns whether a changed file is one Load reads
+func (w *Watcher) isConfigFile(name string) bool {
+	if configFilePattern.MatchString(filepath.Base(name)) {
+		return true
+	}
+	path := absPath(name)
+	if w.configFile != "" && path == w.configFile {
+		return true
+	}
+	for _, dir := range w.configDirs {
+		if filepath.Dir(path) == dir && configExtensionPattern.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// absPath returns the absolute form of path, or path when that fails
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// reload loads the config and passes it (or the error) to onReload
+func (w *Watcher) reload() {
+	cfg, err := Load(w.opts...)
+	if err != nil {
+		w.onReload(nil, err)
+		return
+	}
+	w.onReload(cfg, nil)
+}
+
+// Close stops watching for changes
+func (w *Watcher) Close() error {
+	err := w.watcher.Close()
+	<-w.done
+	return err
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	old := validConfig()
+	next := validConfig()
+	next.Logger.Level = "debug"
+	next.Server.Web.AllowedOrigins = "https://example.com"
+	next.Server.Web.Port = 9090
+
+	changes := Diff(old, next)
+	if len(changes) != 3 {
+		t.Fatalf("Expected 3 changes, got %d: %v", len(changes), changes)
+	}
+
+	byKey := make(map[string]Change)
+	for _, change := range changes {
+		byKey[change.Key] = change
+	}
+	if c := byKey["logger.level"]; c.Old != "info" || c.New != "debug" || c.RequiresRestart {
+		t.Errorf("Unexpected logger.level change: %+v", c)
+	}
+	if c := byKey["server.web.allowedorigins"]; c.RequiresRestart {
+		t.Errorf("Expected CORS origins to be reloadable: %+v", c)
+	}
+	if c := byKey["server.web.port"]; !c.RequiresRestart {
+		t.Errorf("Expected port change to require a restart: %+v", c)
+	}
+
+	if changes := Diff(old, validConfig()); len(changes) != 0 {
+		t.Errorf("Expected no changes for identical configs, got %v", changes)
+	}
+}
+
+func TestApplyReloadable(t *testing.T) {
+	cfg := validConfig()
+	next := validConfig()
+	next.Logger.Level = "warn"
+	next.Server.Web.Port = 9090
+
+	ApplyReloadable(cfg, Diff(cfg, next))
+
+	if cfg.Logger.Level != "warn" {
+		t.Errorf("Expected reloadable level to be applied, got %s", cfg.Logger.Level)
+	}
+	if cfg.Server.Web.Port != 8080 {
+		t.Errorf("Expected port to be left unchanged, got %d", cfg.Server.Web.Port)
+	}
+}
+
+func TestLoadEnvFiles_Reload(t *testing.T) {
+	dir := t.TempDir()
+	envFile := dir + "/.env"
+	const key = "ACTIONHERO_TEST_RELOAD_VALUE"
+	t.Cleanup(func() { _ = os.Unsetenv(key) })
+
+	if err := os.WriteFile(envFile, []byte(key+"=one\n"), 0644); err != nil {
+		t.Fatalf("Failed to write .env: %v", err)
+	}
+	loadEnvFiles([]string{envFile})
+	if got := os.Getenv(key); got != "one" {
+		t.Fatalf("Expected %s=one, got %q", key, got)
+	}
+
+	// Values set from a .env file are updated on reload
+	if err := os.WriteFile(envFile, []byte(key+"=two\n"), 0644); err != nil {
+		t.Fatalf("Failed to write .env: %v", err)
+	}
+	loadEnvFiles([]string{envFile})
+	if got := os.Getenv(key); got != "two" {
+		t.Errorf("Expected %s=two after reload, got %q", key, got)
+	}
+
+	// ...and unset when removed from the file
+	if err := os.WriteFile(envFile, []byte(""), 0644); err != nil {
+		t.Fatalf("Failed to write .env: %v", err)
+	}
+	loadEnvFiles([]string{envFile})
+	if _, exists := os.LookupEnv(key); exists {
+		t.Error("Expected variable to be unset after removal from .env")
+	}
+
+	// The real environment always wins
+	_ = os.Setenv(key, "real")
+	if err := os.WriteFile(envFile, []byte(key+"=three\n"), 0644); err != nil {
+		t.Fatalf("Failed to write .env: %v", err)
+	}
+	loadEnvFiles([]string{envFile})
+	if got := os.Getenv(key); got != "real" {
+		t.Errorf("Expected real environment value to win, got %q", got)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	t.Chdir(t.TempDir())
+	os.Clearenv()
+	t.Cleanup(func() { _ = os.Unsetenv("ACTIONHERO_LOGGER_LEVEL") })
+
+	reloaded := make(chan *Config, 1)
+	watcher, err := Watch(func(cfg *Config, err error) {
+		if err != nil {
+			t.Errorf("Unexpected reload error: %v", err)
+			return
+		}
+		reloaded <- cfg
+	})
+	if err != nil {
+		t.Fatalf("Failed to watch: %v", err)
+	}
+	defer func() { _ = watcher.Close() }()
+
+	// Unrelated files are ignored
+	if err := os.WriteFile("notes.txt", []byte("hello"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := os.WriteFile(".env", []byte("ACTIONHERO_LOGGER_LEVEL=debug\n"), 0644); err != nil {
+		t.Fatalf("Failed to write .env: %v", err)
+	}
+
+	select {
+	case cfg := <-reloaded:
+		if cfg.Logger.Level != "debug" {
+			t.Errorf("Expected reloaded level 'debug', got %s", cfg.Logger.Level)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for config reload")
+	}
+}
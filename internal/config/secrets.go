@@ -0,0 +1,252 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SecretsConfig holds configuration for resolving secret references in config
+// values (e.g., database.password: "vault://secret/db#password") and
+// decrypting encrypted values ("enc:...")
+type SecretsConfig struct {
+	EncryptionKey  string        // Base64 AES-256 key for "enc:" values, or a secret reference to one
+	VaultAddress   string        // Vault server URL (falls back to VAULT_ADDR)
+	VaultToken     string        // Vault token (falls back to VAULT_TOKEN)
+	VaultNamespace string        // Vault Enterprise namespace (optional)
+	AWSRegion      string        // AWS Secrets Manager region (falls back to AWS_REGION)
+	AWSEndpoint    string        // Override the Secrets Manager endpoint (e.g., LocalStack)
+	CacheTTL       time.Duration // How long resolved secrets are reused; 0 disables caching
+	Refresh        time.Duration // How often a config watcher re-resolves secrets to pick up rotations; 0 disables
+	Timeout        time.Duration // Timeout for each secret lookup; 0 disables the timeout
+}
+
+// DefaultSecretsConfig returns default secrets configuration
+func DefaultSecretsConfig() SecretsConfig {
+	return SecretsConfig{
+		EncryptionKey:  "",
+		VaultAddress:   "",
+		VaultToken:     "",
+		VaultNamespace: "",
+		AWSRegion:      "",
+		AWSEndpoint:    "",
+		CacheTTL:       5 * time.Minute,
+		Refresh:        0,
+		Timeout:        5 * time.Second,
+	}
+}
+
+// SecretProvider fetches secrets for one reference scheme
+type SecretProvider interface {
+	// Fetch returns the secret stored at path. key selects one field of a
+	// structured secret; an empty key returns the whole secret.
+	Fetch(ctx context.Context, path, key string) (string, error)
+}
+
+// SecretProviderFactory creates a provider from the secrets configuration
+type SecretProviderFactory func(cfg SecretsConfig) (SecretProvider, error)
+
+var (
+	secretProviders = map[string]SecretProviderFactory{
+		"vault": newVaultProvider,
+		"awssm": newAWSSecretsProvider,
+	}
+	secretProvidersMu sync.RWMutex
+)
+
+// RegisterSecretProvider adds (or replaces) the provider for "scheme://" references
+func RegisterSecretProvider(scheme string, factory SecretProviderFactory) {
+	secretProvidersMu.Lock()
+	defer secretProvidersMu.Unlock()
+	secretProviders[scheme] = factory
+}
+
+// secretFactory returns the provider factory registered for scheme
+func secretFactory(scheme string) (SecretProviderFactory, bool) {
+	secretProvidersMu.RLock()
+	defer secretProvidersMu.RUnlock()
+	factory, ok := secretProviders[scheme]
+	return factory, ok
+}
+
+// SecretRef is a parsed secret reference: scheme://path#key
+type SecretRef struct {
+	Scheme string
+	Path   string
+	Key    string // Optional field within the secret
+}
+
+// String returns the reference as written in config
+func (r SecretRef) String() string {
+	if r.Key == "" {
+		return r.Scheme + "://" + r.Path
+	}
+	return r.Scheme + "://" + r.Path + "#" + r.Key
+}
+
+// ParseSecretRef parses a config value as a secret reference. Values whose
+// scheme has no registered provider are not references.
+func ParseSecretRef(value string) (SecretRef, bool) {
+	scheme, rest, ok := strings.Cut(value, "://")
+	if !ok || rest == "" {
+		return SecretRef{}, false
+	}
+	if _, registered := secretFactory(scheme); !registered {
+		return SecretRef{}, false
+	}
+	path, key, _ := strings.Cut(rest, "#")
+	return SecretRef{Scheme: scheme, Path: path, Key: key}, true
+}
+
+// cachedSecret is a resolved secret and when it must be fetched again
+type cachedSecret struct {
+	value   string
+	expires time.Time
+}
+
+// secretCache holds resolved secrets across loads, so reloading config doesn't
+// hit the secrets backend until the cached value expires
+var (
+	secretCache   = make(map[string]cachedSecret)
+	secretCacheMu sync.Mutex
+)
+
+// resolveSecrets replaces every secret reference and encrypted value in cfg,
+// including registered sections, with its value. The secrets section itself is
+// never resolved.
+func resolveSecrets(cfg *Config) error {
+	r := &secretResolver{
+		cfg:       cfg.Secrets,
+		providers: make(map[string]SecretProvider),
+	}
+
+	v := reflect.ValueOf(cfg).Elem()
+	var errs []error
+	for i := 0; i < v.NumField(); i++ {
+		key := strings.ToLower(v.Type().Field(i).Name)
+		if !v.Type().Field(i).IsExported() || key == "secrets" || nonSettingFields[key] {
+			continue
+		}
+		r.resolveValue(v.Field(i), key, &errs)
+	}
+	for _, name := range sortedKeys(cfg.Sections) {
+		r.resolveValue(reflect.ValueOf(cfg.Sections[name]).Elem(), name, &errs)
+	}
+	return errors.Join(errs...)
+}
+
+// secretResolver resolves references during a single load
+type secretResolver struct {
+	cfg       SecretsConfig
+	providers map[string]SecretProvider // Created on first use, per scheme
+	key       []byte                    // Decoded encryption key, on first use
+	keyErr    error
+}
+
+// resolveValue walks a config value, resolving string fields in place
+func (r *secretResolver) resolveValue(v reflect.Value, key string, errs *[]error) {
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				r.resolveValue(v.Field(i), key+"."+strings.ToLower(v.Type().Field(i).Name), errs)
+			}
+		}
+	case reflect.String:
+		if isEncrypted(v.String()) {
+			encryptionKey, err := r.encryptionKey()
+			if err == nil {
+				var value string
+				if value, err = decryptValue(encryptionKey, v.String()); err == nil {
+					v.SetString(value)
+					return
+				}
+			}
+			*errs = append(*errs, fmt.Errorf("%s: %w", key, err))
+			return
+		}
+
+		ref, ok := ParseSecretRef(v.String())
+		if !ok {
+			return
+		}
+		value, err := r.resolve(ref)
+		if err != nil {
+			*errs = append(*errs, fmt.Errorf("%s: failed to resolve %s: %w", key, ref, err))
+			return
+		}
+		v.SetString(value)
+	}
+}
+
+// resolve returns the value for ref, from the cache when it hasn't expired
+func (r *secretResolver) resolve(ref SecretRef) (string, error) {
+	cacheKey := ref.String()
+	ttl := r.cfg.CacheTTL
+
+	if ttl > 0 {
+		secretCacheMu.Lock()
+		cached, ok := secretCache[cacheKey]
+		secretCacheMu.Unlock()
+		if ok && time.Now().Before(cached.expires) {
+			return cached.value, nil
+		}
+	}
+
+	provider, err := r.provider(ref.Scheme)
+	if err != nil {
+		return "", err
+	}
+
+	ctx := context.Background()
+	if r.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.cfg.Timeout)
+		defer cancel()
+	}
+
+	value, err := provider.Fetch(ctx, ref.Path, ref.Key)
+	if err != nil {
+		return "", err
+	}
+
+	if ttl > 0 {
+		secretCacheMu.Lock()
+		secretCache[cacheKey] = cachedSecret{value: value, expires: time.Now().Add(ttl)}
+		secretCacheMu.Unlock()
+	}
+	return value, nil
+}
+
+// provider returns the provider for scheme, creating it on first use
+func (r *secretResolver) provider(scheme string) (SecretProvider, error) {
+	if provider, ok := r.providers[scheme]; ok {
+		return provider, nil
+	}
+	factory, ok := secretFactory(scheme)
+	if !ok {
+		return nil, fmt.Errorf("no secret provider registered for %s://", scheme)
+	}
+	provider, err := factory(r.cfg)
+	if err != nil {
+		return nil, err
+	}
+	r.providers[scheme] = provider
+	return provider, nil
+}
+
+// secretField returns one field of a structured (JSON object) secret
+func secretField(data map[string]interface{}, key string) (string, error) {
+	value, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("key %q not found in secret", key)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(value), nil
+}
@@ -0,0 +1,191 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// resetSecretCache clears secrets cached by earlier tests
+func resetSecretCache(t *testing.T) {
+	t.Helper()
+	secretCacheMu.Lock()
+	secretCache = make(map[string]cachedSecret)
+	secretCacheMu.Unlock()
+}
+
+// newVaultServer serves a KV v2 secret at secret/data/db, counting requests
+func newVaultServer(t *testing.T, requests *int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		if r.Header.Get("X-Vault-Token") != "test-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/v1/secret/data/db" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"data":     map[string]interface{}{"password": "s3cret", "port": 5433},
+				"metadata": map[string]interface{}{"version": 2},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestParseSecretRef(t *testing.T) {
+	ref, ok := ParseSecretRef("vault://secret/db#password")
+	if !ok || ref.Scheme != "vault" || ref.Path != "secret/db" || ref.Key != "password" {
+		t.Errorf("Unexpected ref: %+v", ref)
+	}
+
+	ref, ok = ParseSecretRef("awssm://arn:aws:secretsmanager:us-east-1:123:secret:prod/db")
+	if !ok || ref.Path != "arn:aws:secretsmanager:us-east-1:123:secret:prod/db" || ref.Key != "" {
+		t.Errorf("Unexpected ref: %+v", ref)
+	}
+
+	for _, value := range []string{"plain", "https://example.com", "vault://", ""} {
+		if _, ok := ParseSecretRef(value); ok {
+			t.Errorf("Expected %q not to be a secret reference", value)
+		}
+	}
+}
+
+func TestLoad_ResolvesVaultSecrets(t *testing.T) {
+	resetSecretCache(t)
+	os.Clearenv()
+	defer os.Clearenv()
+
+	var requests int32
+	server := newVaultServer(t, &requests)
+	_ = os.Setenv("ACTIONHERO_SECRETS_VAULTADDRESS", server.URL)
+	_ = os.Setenv("ACTIONHERO_SECRETS_VAULTTOKEN", "test-token")
+	_ = os.Setenv("ACTIONHERO_DATABASE_PASSWORD", "vault://secret/db#password")
+	_ = os.Setenv("ACTIONHERO_REDIS_PASSWORD", "vault://secret/data/db#password")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Database.Password != "s3cret" || cfg.Redis.Password != "s3cret" {
+		t.Errorf("Expected resolved passwords, got %q and %q", cfg.Database.Password, cfg.Redis.Password)
+	}
+
+	// Cached values are reused by later loads
+	before := atomic.LoadInt32(&requests)
+	if _, err := Load(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if after := atomic.LoadInt32(&requests); after != before {
+		t.Errorf("Expected cached secrets to be reused, got %d new requests", after-before)
+	}
+
+	// With caching disabled, every load fetches again (picking up rotations)
+	_ = os.Setenv("ACTIONHERO_SECRETS_CACHETTL", "0")
+	if _, err := Load(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if after := atomic.LoadInt32(&requests); after == before {
+		t.Error("Expected secrets to be fetched again with caching disabled")
+	}
+}
+
+func TestLoad_SecretErrors(t *testing.T) {
+	resetSecretCache(t)
+	os.Clearenv()
+	defer os.Clearenv()
+
+	var requests int32
+	server := newVaultServer(t, &requests)
+	_ = os.Setenv("ACTIONHERO_SECRETS_VAULTADDRESS", server.URL)
+	_ = os.Setenv("ACTIONHERO_SECRETS_VAULTTOKEN", "test-token")
+	_ = os.Setenv("ACTIONHERO_DATABASE_PASSWORD", "vault://secret/db#missing")
+	_ = os.Setenv("ACTIONHERO_REDIS_PASSWORD", "vault://secret/other#password")
+
+	_, err := Load()
+	if err == nil {
+		t.Fatal("Expected unresolvable secrets to fail loading")
+	}
+	for _, expected := range []string{"database.password", `key "missing" not found`, "redis.password", "secret not found"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected error to mention %q, got: %v", expected, err)
+		}
+	}
+}
+
+type staticSecretProvider map[string]string
+
+func (p staticSecretProvider) Fetch(_ context.Context, path, _ string) (string, error) {
+	return p[path], nil
+}
+
+func TestRegisterSecretProvider(t *testing.T) {
+	resetSecretCache(t)
+	os.Clearenv()
+	defer os.Clearenv()
+
+	RegisterSecretProvider("test", func(SecretsConfig) (SecretProvider, error) {
+		return staticSecretProvider{"session": "cookie-from-provider"}, nil
+	})
+	_ = os.Setenv("ACTIONHERO_SESSION_COOKIENAME", "test://session")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Session.CookieName != "cookie-from-provider" {
+		t.Errorf("Expected value from custom provider, got %s", cfg.Session.CookieName)
+	}
+}
+
+func TestAWSSecretsProvider(t *testing.T) {
+	os.Clearenv()
+	defer os.Clearenv()
+	_ = os.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	_ = os.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" {
+			t.Errorf("Unexpected target %s", r.Header.Get("X-Amz-Target"))
+		}
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") {
+			t.Errorf("Expected a SigV4 signature, got %s", r.Header.Get("Authorization"))
+		}
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["SecretId"] != "prod/db" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"ResourceNotFoundException","message":"not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"SecretString":"{\"password\":\"from-aws\"}"}`))
+	}))
+	defer server.Close()
+
+	provider, err := newAWSSecretsProvider(SecretsConfig{AWSRegion: "us-east-1", AWSEndpoint: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	value, err := provider.Fetch(context.Background(), "prod/db", "password")
+	if err != nil || value != "from-aws" {
+		t.Errorf("Expected from-aws, got %q (%v)", value, err)
+	}
+	value, err = provider.Fetch(context.Background(), "prod/db", "")
+	if err != nil || value != `{"password":"from-aws"}` {
+		t.Errorf("Expected whole secret string, got %q (%v)", value, err)
+	}
+	if _, err := provider.Fetch(context.Background(), "prod/missing", ""); err == nil || !strings.Contains(err.Error(), "ResourceNotFoundException") {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
@@ -0,0 +1,252 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/viper"
+)
+
+// section is a config block registered by a plugin or app code
+type section struct {
+	typ      reflect.Type // The section's struct type
+	defaults reflect.Value
+}
+
+var (
+	sections   = make(map[string]section)
+	sectionsMu sync.RWMutex
+)
+
+// sectionNamePattern restricts section names to what works as both a config
+// file key and part of an environment variable name
+var sectionNamePattern = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+// RegisterSection registers a strongly-typed config block for a plugin or app
+// code. target is a pointer to the section's struct (e.g., &MyConfig{}), and
+// defaults is a MyConfig (or *MyConfig) holding default values; when defaults
+// is nil, target's current values are used. Every field can then be set in a
+// config file under the section name or with an environment variable
+// (e.g., ACTIONHERO_MYPLUGIN_TIMEOUT). Each loaded Config gets its own copy,
+// read with GetSection. A section that implements Validate() error is
+// validated with the rest of the config.
+func RegisterSection(name string, target interface{}, defaults interface{}) error {
+	if !sectionNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid config section name %q: use lowercase letters and digits", name)
+	}
+	if _, builtIn := reflect.TypeOf(Config{}).FieldByNameFunc(func(field string) bool {
+		return strings.EqualFold(field, name)
+	}); builtIn {
+		return fmt.Errorf("config section %q is built in", name)
+	}
+
+	ptr := reflect.ValueOf(target)
+	if ptr.Kind() != reflect.Pointer || ptr.IsNil() || ptr.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config section %q: target must be a pointer to a struct, got %T", name, target)
+	}
+	typ := ptr.Elem().Type()
+
+	defaultsValue := ptr.Elem()
+	if defaults != nil {
+		defaultsValue = reflect.Indirect(reflect.ValueOf(defaults))
+		if defaultsValue.Type() != typ {
+			return fmt.Errorf("config section %q: defaults must be a %s, got %T", name, typ, defaults)
+		}
+	}
+
+	// Copy the defaults, so later changes to them don't affect loading
+	copied := reflect.New(typ).Elem()
+	copied.Set(defaultsValue)
+
+	sectionsMu.Lock()
+	defer sectionsMu.Unlock()
+	if _, exists := sections[name]; exists {
+		return fmt.Errorf("config section %q is already registered", name)
+	}
+	sections[name] = section{typ: typ, defaults: copied}
+	return nil
+}
+
+// IsSectionRegistered returns whether a section with name is registered
+func IsSectionRegistered(name string) bool {
+	_, ok := lookupSection(name)
+	return ok
+}
+
+// LoadSection loads a section registered after cfg was loaded (e.g., by a
+// plugin), reading the same sources with the same options as cfg, and
+// validates it
+func LoadSection(cfg *Config, name string) error {
+	if !IsSectionRegistered(name) {
+		return fmt.Errorf("config section %q is not registered", name)
+	}
+
+	next, err := LoadWithoutValidation(cfg.loadOptions...)
+	if err != nil {
+		return err
+	}
+	section := next.Sections[name]
+	if validator, ok := section.(interface{ Validate() error }); ok {
+		if err := validator.Validate(); err != nil {
+			return fmt.Errorf("invalid configuration:\n%s: %w", name, err)
+		}
+	}
+
+	if cfg.Sections == nil {
+		cfg.Sections = make(map[string]interface{})
+	}
+	cfg.Sections[name] = section
+	for key, source := range next.Sources {
+		if strings.HasPrefix(key, name+".") {
+			if cfg.Sources == nil {
+				cfg.Sources = make(map[string]Source)
+			}
+			cfg.Sources[key] = source
+		}
+	}
+	return nil
+}
+
+// GetSection returns the loaded values of a registered section, or nil if no
+// section with that name and type was registered when cfg was loaded
+func GetSection[T any](cfg *Config, name string) *T {
+	value, _ := cfg.Sections[name].(*T)
+	return value
+}
+
+// registeredSections returns the registered sections, sorted by name
+func registeredSections() []string {
+	sectionsMu.RLock()
+	defer sectionsMu.RUnlock()
+	names := make([]string, 0, len(sections))
+	for name := range sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupSection returns a registered section
+func lookupSection(name string) (section, bool) {
+	sectionsMu.RLock()
+	defer sectionsMu.RUnlock()
+	s, ok := sections[name]
+	return s, ok
+}
+
+// setSectionDefaults sets viper defaults for every field of the registered
+// sections, so environment variables can override them
+func setSectionDefaults(v *viper.Viper) {
+	for _, name := range registeredSections() {
+		s, _ := lookupSection(name)
+		setStructDefaults(v, name, s.defaults)
+	}
+}
+
+// setStructDefaults sets a viper default for each leaf field of a struct
+func setStructDefaults(v *viper.Viper, prefix string, value reflect.Value) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key := prefix + "." + strings.ToLower(field.Name)
+		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeOf(time.Time{}) {
+			setStructDefaults(v, key, value.Field(i))
+			continue
+		}
+		v.SetDefault(key, value.Field(i).Interface())
+	}
+}
+
+// sectionDurationKeys returns the keys of duration fields in registered
+// sections. A bare number for these is milliseconds.
+func sectionDurationKeys() []string {
+	var keys []string
+	for _, name := range registeredSections() {
+		s, _ := lookupSection(name)
+		collectDurationKeys(s.typ, name, &keys)
+	}
+	return keys
+}
+
+// collectDurationKeys appends the keys of time.Duration fields in a struct type
+func collectDurationKeys(typ reflect.Type, prefix string, keys *[]string) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		key := prefix + "." + strings.ToLower(field.Name)
+		switch {
+		case field.Type == reflect.TypeOf(time.Duration(0)):
+			*keys = append(*keys, key)
+		case field.Type.Kind() == reflect.Struct:
+			collectDurationKeys(field.Type, key, keys)
+		}
+	}
+}
+
+// loadSections decodes each registered section into cfg.Sections
+func loadSections(v *viper.Viper, cfg *Config) error {
+	names := registeredSections()
+	if len(names) == 0 {
+		return nil
+	}
+
+	cfg.Sections = make(map[string]interface{}, len(names))
+	for _, name := range names {
+		s, _ := lookupSection(name)
+		ptr := reflect.New(s.typ)
+		ptr.Elem().Set(s.defaults)
+
+		// Collect each key with Get, rather than UnmarshalKey, so environment
+		// variables override nested keys
+		settings := make(map[string]interface{})
+		for _, key := range v.AllKeys() {
+			if rest, ok := strings.CutPrefix(key, name+"."); ok {
+				setNested(settings, strings.Split(rest, "."), v.Get(key))
+			}
+		}
+
+		decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       decodeHooks,
+			WeaklyTypedInput: true,
+			Result:           ptr.Interface(),
+		})
+		if err != nil {
+			return err
+		}
+		if err := decoder.Decode(settings); err != nil {
+			return fmt.Errorf("error unmarshaling config section %s: %w", name, err)
+		}
+		cfg.Sections[name] = ptr.Interface()
+	}
+	return nil
+}
+
+// setNested sets value in a nested map at path, creating intermediate maps
+func setNested(m map[string]interface{}, path []string, value interface{}) {
+	for _, part := range path[:len(path)-1] {
+		child, ok := m[part].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			m[part] = child
+		}
+		m = child
+	}
+	m[path[len(path)-1]] = value
+}
+
+// sortedKeys returns the keys of a sections map in order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+type pluginConfig struct {
+	Endpoint string
+	Retries  int
+	Timeout  time.Duration
+	Tags     []string
+	Limits   struct {
+		Burst int
+	}
+}
+
+func (c *pluginConfig) Validate() error {
+	if c.Retries < 0 {
+		return errors.New("retries must not be negative")
+	}
+	return nil
+}
+
+func TestRegisterSection_Errors(t *testing.T) {
+	if err := RegisterSection("sectionerrors", &pluginConfig{}, nil); err != nil {
+		t.Fatalf("Failed to register section: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		section  string
+		target   interface{}
+		defaults interface{}
+	}{
+		{"invalid name", "my-plugin", &pluginConfig{}, nil},
+		{"built in", "logger", &pluginConfig{}, nil},
+		{"reserved", "sections", &pluginConfig{}, nil},
+		{"not a pointer", "sectionvalue", pluginConfig{}, nil},
+		{"not a struct", "sectionstring", new(string), nil},
+		{"mismatched defaults", "sectiondefaults", &pluginConfig{}, LoggerConfig{}},
+		{"duplicate", "sectionerrors", &pluginConfig{}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RegisterSection(tt.section, tt.target, tt.defaults); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestLoad_Sections(t *testing.T) {
+	t.Chdir(t.TempDir())
+	os.Clearenv()
+
+	defaults := pluginConfig{Endpoint: "http://localhost:9000", Retries: 3, Timeout: time.Second}
+	defaults.Limits.Burst = 10
+	if err := RegisterSection("sectionload", &pluginConfig{}, defaults); err != nil {
+		t.Fatalf("Failed to register section: %v", err)
+	}
+
+	writeConfigFile(t, "config.yaml", "sectionload:\n  retries: 5\n  tags: [a, b]\n")
+	_ = os.Setenv("ACTIONHERO_SECTIONLOAD_ENDPOINT", "https://plugin.example.com")
+	_ = os.Setenv("ACTIONHERO_SECTIONLOAD_TIMEOUT", "2500") // bare number: milliseconds
+	_ = os.Setenv("ACTIONHERO_SECTIONLOAD_LIMITS_BURST", "20")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	section := GetSection[pluginConfig](cfg, "sectionload")
+	if section == nil {
+		t.Fatal("Expected the section to be loaded")
+	}
+	if section.Endpoint != "https://plugin.example.com" {
+		t.Errorf("Expected endpoint from env, got %s", section.Endpoint)
+	}
+	if section.Retries != 5 {
+		t.Errorf("Expected retries from config file, got %d", section.Retries)
+	}
+	if section.Timeout != 2500*time.Millisecond {
+		t.Errorf("Expected timeout 2.5s, got %s", section.Timeout)
+	}
+	if strings.Join(section.Tags, ",") != "a,b" {
+		t.Errorf("Expected tags a,b, got %v", section.Tags)
+	}
+	if section.Limits.Burst != 20 {
+		t.Errorf("Expected nested burst from env, got %d", section.Limits.Burst)
+	}
+
+	if GetSection[LoggerConfig](cfg, "sectionload") != nil {
+		t.Error("Expected nil for a mismatched type")
+	}
+
+	// Each load gets its own copy
+	_ = os.Setenv("ACTIONHERO_SECTIONLOAD_RETRIES", "7")
+	next, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if section.Retries != 5 || GetSection[pluginConfig](next, "sectionload").Retries != 7 {
+		t.Error("Expected loads not to share section values")
+	}
+
+	changes := Diff(cfg, next)
+	if len(changes) != 1 || changes[0].Key != "sectionload.retries" || !changes[0].RequiresRestart {
+		t.Errorf("Expected one restart-requiring change to sectionload.retries, got %+v", changes)
+	}
+}
+
+func TestLoad_SectionValidation(t *testing.T) {
+	os.Clearenv()
+	defer os.Clearenv()
+
+	if err := RegisterSection("sectionvalidate", &pluginConfig{Retries: 1}, nil); err != nil {
+		t.Fatalf("Failed to register section: %v", err)
+	}
+	_ = os.Setenv("ACTIONHERO_SECTIONVALIDATE_RETRIES", "-1")
+
+	_, err := Load()
+	if err == nil || !strings.Contains(err.Error(), "sectionvalidate: retries must not be negative") {
+		t.Errorf("Expected section validation error, got %v", err)
+	}
+}
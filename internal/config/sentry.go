@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// SentryConfig holds configuration for the built-in Sentry error reporter
+type SentryConfig struct {
+	DSN         string  // Sentry DSN (empty = disabled)
+	Environment string  // Environment tag sent with each event
+	SampleRate  float64 // Fraction of errors to send (0-1)
+}
+
+// DefaultSentryConfig returns default Sentry configuration
+func DefaultSentryConfig() SentryConfig {
+	return SentryConfig{
+		DSN:         "",
+		Environment: "development",
+		SampleRate:  1.0,
+	}
+}
+
+// SentryDSN is a parsed Sentry DSN
+type SentryDSN struct {
+	PublicKey string
+	StoreURL  string // Endpoint events are posted to
+}
+
+// ParseSentryDSN parses a DSN of the form https://<key>@<host>/<project>
+func ParseSentryDSN(dsn string) (*SentryDSN, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DSN: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid DSN: scheme must be http or https")
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return nil, fmt.Errorf("invalid DSN: missing public key")
+	}
+
+	path := strings.Trim(u.Path, "/")
+	if path == "" {
+		return nil, fmt.Errorf("invalid DSN: missing project id")
+	}
+
+	// Any path before the project id is a prefix for the API
+	prefix := ""
+	projectID := path
+	if i := strings.LastIndex(path, "/"); i != -1 {
+		prefix = "/" + path[:i]
+		projectID = path[i+1:]
+	}
+
+	return &SentryDSN{
+		PublicKey: u.User.Username(),
+		StoreURL:  fmt.Sprintf("%s://%s%s/api/%s/store/", u.Scheme, u.Host, prefix, projectID),
+	}, nil
+}
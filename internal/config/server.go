@@ -1,5 +1,18 @@
 package config
 
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+)
+
+// Supported OpenAPI versions for the swagger document
+const (
+	OpenAPIVersion30 = "3.0.0"
+	OpenAPIVersion31 = "3.1.0"
+)
+
 // WebServerConfig holds web server configuration
 type WebServerConfig struct {
 	Enabled              bool
@@ -12,6 +25,64 @@ type WebServerConfig struct {
 	StaticFilesEnabled   bool
 	StaticFilesRoute     string
 	StaticFilesDirectory string
+	MetricsEnabled       bool     // Expose per-action latency and error metrics
+	MetricsRoute         string   // Route for the metrics endpoint
+	DebugEnabled         bool     // Expose pprof and runtime debug endpoints
+	DebugRoute           string   // Route prefix for debug endpoints
+	DebugHost            string   // Host for the internal debug listener
+	DebugPort            int      // Port for the internal debug listener (0 = serve on the web server)
+	OpenAPIVersion       string   // OpenAPI version of the swagger document (3.0.0 or 3.1.0)
+	ValidateRequests     bool     // Reject requests that do not match the action's OpenAPI schema
+	MaxBodySize          ByteSize // Largest accepted request body (e.g., "10MB"; 0 = unlimited)
+	// IdleTimeout closes WebSocket connections that haven't sent a message
+	// for this long (0 = never)
+	IdleTimeout time.Duration
+	// ReapInterval is how often idle connections and expired sessions are swept
+	ReapInterval time.Duration
+	// MessageRate limits each WebSocket connection to this many messages per
+	// second on average (0 = unlimited)
+	MessageRate float64
+	// MessageBurst is how many messages a connection may send at once before
+	// MessageRate applies
+	MessageBurst int
+	// MessageRateWarnings is how many rate-limited messages are rejected with
+	// a warning before the connection is closed
+	MessageRateWarnings int
+	// MaxActionsInFlight is how many actions each WebSocket connection may
+	// run at once. Further action messages wait for one to finish (1 = one at
+	// a time).
+	MaxActionsInFlight int
+	// OrderedResponses sends each WebSocket connection's action responses in
+	// the order of its requests, rather than as each action finishes
+	OrderedResponses bool
+	// BroadcastWorkers is how many workers deliver broadcasts. Channels are
+	// spread across the workers, each with its own subscriber index (0 = one
+	// per CPU).
+	BroadcastWorkers int
+	// RouteCacheSize is how many recently matched (method, path) routes are
+	// remembered, so repeated requests skip pattern matching (0 = disabled)
+	RouteCacheSize int
+	// AllowedIPs only accepts requests and WebSocket connections from these
+	// comma-separated IPs and CIDR ranges ("" = any IP)
+	AllowedIPs string
+	// DeniedIPs rejects requests from these IPs and CIDR ranges, even if allowed
+	DeniedIPs string
+	// MetricsAllowedIPs replaces AllowedIPs for the metrics endpoint ("" = use AllowedIPs)
+	MetricsAllowedIPs string
+	// DebugAllowedIPs replaces AllowedIPs for the debug endpoints ("" = use AllowedIPs)
+	DebugAllowedIPs string
+	// AdminEnabled serves the admin dashboard under AdminRoute, behind HTTP
+	// basic auth with AdminUser and AdminPassword
+	AdminEnabled  bool
+	AdminRoute    string
+	AdminUser     string
+	AdminPassword string
+	// AdminAllowedIPs replaces AllowedIPs for the admin dashboard ("" = use AllowedIPs)
+	AdminAllowedIPs string
+	// TLSCertFile and TLSKeyFile serve HTTPS and WSS with this certificate,
+	// reloaded when the files change ("" = plain HTTP)
+	TLSCertFile string
+	TLSKeyFile  string
 }
 
 // DefaultWebServerConfig returns default web server configuration
@@ -27,5 +98,61 @@ func DefaultWebServerConfig() WebServerConfig {
 		StaticFilesEnabled:   false,
 		StaticFilesRoute:     "/public",
 		StaticFilesDirectory: "./public",
+		MetricsEnabled:       true,
+		MetricsRoute:         "/metrics",
+		DebugEnabled:         false,
+		DebugRoute:           "/debug",
+		DebugHost:            "127.0.0.1",
+		DebugPort:            6060,
+		OpenAPIVersion:       OpenAPIVersion30,
+		ValidateRequests:     false,
+		MaxBodySize:          10 * Megabyte,
+		IdleTimeout:          0,
+		ReapInterval:         30 * time.Second,
+		MessageRate:          0,
+		MessageBurst:         20,
+		MessageRateWarnings:  5,
+		MaxActionsInFlight:   10,
+		OrderedResponses:     false,
+		BroadcastWorkers:     0,
+		RouteCacheSize:       1000,
+		AllowedIPs:           "",
+		DeniedIPs:            "",
+		MetricsAllowedIPs:    "",
+		DebugAllowedIPs:      "",
+		AdminEnabled:         false,
+		AdminRoute:           "/admin",
+		AdminUser:            "admin",
+		AdminPassword:        "",
+		AdminAllowedIPs:      "",
+		TLSCertFile:          "",
+		TLSKeyFile:           "",
+	}
+}
+
+// ParseIPList parses a comma-separated list of IPs and CIDR ranges (e.g.,
+// "10.0.0.0/8, 127.0.0.1, ::1"). A single IP is a range of one address.
+func ParseIPList(value string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CIDR range %q", entry)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IP %q", entry)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
 	}
+	return prefixes, nil
 }
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+// WebServerName is the name of the built-in web server
+const WebServerName = "web"
+
+var (
+	serverNames   = make(map[string]bool)
+	serverNamesMu sync.RWMutex
+)
+
+// RegisterServer registers the name of a server besides the web server, so
+// server.<name>.enabled (default true) can be set in a config file, with an
+// environment variable (e.g., ACTIONHERO_SERVER_SOCKET_ENABLED), or with
+// --set. Register servers before loading the config.
+func RegisterServer(name string) error {
+	if !sectionNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid server name %q: use lowercase letters and digits", name)
+	}
+	if name == WebServerName {
+		return fmt.Errorf("server %q is built in", name)
+	}
+
+	serverNamesMu.Lock()
+	defer serverNamesMu.Unlock()
+	serverNames[name] = true
+	return nil
+}
+
+// registeredServers returns the names of the servers registered with
+// RegisterServer, sorted
+func registeredServers() []string {
+	serverNamesMu.RLock()
+	defer serverNamesMu.RUnlock()
+	names := make([]string, 0, len(serverNames))
+	for name := range serverNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// setServerDefaults enables each registered server by default
+func setServerDefaults(v *viper.Viper) {
+	for _, name := range registeredServers() {
+		v.SetDefault("server."+name+".enabled", true)
+	}
+}
+
+// loadServers reads whether each registered server is enabled
+func loadServers(v *viper.Viper, cfg *Config) {
+	names := registeredServers()
+	if len(names) == 0 {
+		return
+	}
+	cfg.Server.Enabled = make(map[string]bool, len(names))
+	for _, name := range names {
+		cfg.Server.Enabled[name] = v.GetBool("server." + name + ".enabled")
+	}
+}
+
+// IsEnabled returns whether the server called name runs: server.web.enabled
+// for the web server, and server.<name>.enabled for the others (servers
+// that weren't registered with RegisterServer are enabled)
+func (c ServerConfig) IsEnabled(name string) bool {
+	if name == WebServerName {
+		return c.Web.Enabled
+	}
+	enabled, ok := c.Enabled[name]
+	return !ok || enabled
+}
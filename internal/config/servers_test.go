@@ -0,0 +1,53 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestRegisterServer_Errors(t *testing.T) {
+	for _, name := range []string{"", "web", "Socket", "my-server"} {
+		if err := RegisterServer(name); err == nil {
+			t.Errorf("Expected registering server %q to fail", name)
+		}
+	}
+}
+
+func TestLoad_Servers(t *testing.T) {
+	t.Chdir(t.TempDir())
+	os.Clearenv()
+
+	for _, name := range []string{"serverloada", "serverloadb", "serverloadc"} {
+		if err := RegisterServer(name); err != nil {
+			t.Fatalf("Failed to register server: %v", err)
+		}
+	}
+
+	writeConfigFile(t, "config.yaml", "server:\n  web:\n    enabled: false\n  serverloadb:\n    enabled: false\n")
+	_ = os.Setenv("ACTIONHERO_SERVER_SERVERLOADC_ENABLED", "false")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for name, want := range map[string]bool{
+		WebServerName: false, // From the config file
+		"serverloada": true,  // Default
+		"serverloadb": false, // From the config file
+		"serverloadc": false, // From the environment
+		"unknown":     true,  // Not registered
+	} {
+		if got := cfg.Server.IsEnabled(name); got != want {
+			t.Errorf("Expected server %s enabled=%v, got %v", name, want, got)
+		}
+	}
+
+	cfg, err = Load(WithOverrides(map[string]string{"server.serverloadb.enabled": "true"}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !cfg.Server.IsEnabled("serverloadb") {
+		t.Error("Expected --set to enable the server")
+	}
+}
@@ -1,15 +1,37 @@
 package config
 
+import "time"
+
+// Cookie SameSite modes (session.samesite)
+const (
+	SameSiteLax    = "lax"
+	SameSiteStrict = "strict"
+	SameSiteNone   = "none"
+)
+
+// validSameSiteModes lists the accepted session.samesite values
+var validSameSiteModes = []string{SameSiteLax, SameSiteStrict, SameSiteNone}
+
 // SessionConfig holds session configuration
 type SessionConfig struct {
 	CookieName string
-	TTL        int // Time to live in seconds
+	TTL        time.Duration // Time to live (a bare number is seconds)
+	SameSite   string        // Cookie SameSite mode: lax, strict, or none (none requires Secure)
+	Secure     bool          // Only send the cookie over HTTPS
+	HTTPOnly   bool          // Hide the cookie from JavaScript
+	Domain     string        // Cookie domain ("" = the request's host only)
+	Path       string        // Cookie path
 }
 
 // DefaultSessionConfig returns default session configuration
 func DefaultSessionConfig() SessionConfig {
 	return SessionConfig{
 		CookieName: "actionhero",
-		TTL:        86400, // 24 hours
+		TTL:        24 * time.Hour,
+		SameSite:   SameSiteLax,
+		Secure:     false,
+		HTTPOnly:   true,
+		Domain:     "",
+		Path:       "/",
 	}
 }
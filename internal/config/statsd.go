@@ -0,0 +1,21 @@
+package config
+
+// StatsDConfig holds configuration for the StatsD/Datadog metrics emitter
+type StatsDConfig struct {
+	Enabled bool
+	Host    string
+	Port    int
+	Prefix  string // Prepended to every metric name (e.g., "actionhero.")
+	Tags    string // Comma-separated Datadog tags added to every metric (e.g., "env:prod,service:api")
+}
+
+// DefaultStatsDConfig returns default StatsD configuration
+func DefaultStatsDConfig() StatsDConfig {
+	return StatsDConfig{
+		Enabled: false,
+		Host:    "127.0.0.1",
+		Port:    8125,
+		Prefix:  "actionhero.",
+		Tags:    "",
+	}
+}
@@ -0,0 +1,35 @@
+package config
+
+// StorageConfig holds configuration for file storage (api.Files)
+type StorageConfig struct {
+	Enabled bool
+	Backend string // local or s3
+
+	Directory string // Root directory of the local backend
+	Route     string // Web route serving signed URLs of local files
+	PublicURL string // Prefixed to signed URLs of local files (e.g., https://api.example.com)
+	URLSecret string // Signs URLs of local files; random per process when empty
+
+	S3Bucket    string
+	S3Region    string // Falls back to AWS_REGION; credentials come from AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
+	S3Endpoint  string // Overrides the S3 endpoint (e.g., for MinIO or R2)
+	S3PathStyle bool   // Address the bucket in the path instead of the host name (e.g., for MinIO)
+}
+
+// DefaultStorageConfig returns default storage configuration
+func DefaultStorageConfig() StorageConfig {
+	return StorageConfig{
+		Enabled: false,
+		Backend: "local",
+
+		Directory: "storage",
+		Route:     "/files",
+		PublicURL: "",
+		URLSecret: "",
+
+		S3Bucket:    "",
+		S3Region:    "",
+		S3Endpoint:  "",
+		S3PathStyle: false,
+	}
+}
@@ -1,12 +1,14 @@
 package config
 
+import "time"
+
 // TasksConfig holds background task configuration
 type TasksConfig struct {
 	Enabled            bool
 	TaskProcessors     int
 	Queues             []string
-	Timeout            int // Timeout in milliseconds
-	StuckWorkerTimeout int // Stuck worker timeout in milliseconds
+	Timeout            time.Duration // Task timeout (a bare number is milliseconds)
+	StuckWorkerTimeout time.Duration // Stuck worker timeout (a bare number is milliseconds)
 	RetryStuckJobs     bool
 }
 
@@ -16,8 +18,8 @@ func DefaultTasksConfig() TasksConfig {
 		Enabled:            true,
 		TaskProcessors:     1,
 		Queues:             []string{"default"},
-		Timeout:            10000, // 10 seconds
-		StuckWorkerTimeout: 60000, // 60 seconds
+		Timeout:            10 * time.Second,
+		StuckWorkerTimeout: 60 * time.Second,
 		RetryStuckJobs:     false,
 	}
 }
@@ -0,0 +1,41 @@
+package config
+
+// Tenant sources: where the web server reads a request's tenant from
+const (
+	TenantSourceHeader    = "header"    // A request header (Header)
+	TenantSourceSubdomain = "subdomain" // The subdomain of BaseDomain the request was sent to
+	TenantSourceClaim     = "claim"     // A claim of the request's bearer token (an HS256 JWT signed with TokenSecret)
+)
+
+// TenancyConfig holds configuration for multi-tenancy: which tenant each
+// request is for, and how tenants are kept apart (see API.ResolveTenant)
+type TenancyConfig struct {
+	Enabled     bool
+	Source      string // TenantSourceHeader, TenantSourceSubdomain, or TenantSourceClaim
+	Header      string // Header carrying the tenant, for the header source
+	BaseDomain  string // Domain tenants are subdomains of (e.g., "example.com"), for the subdomain source
+	Claim       string // Bearer token claim carrying the tenant, for the claim source
+	TokenSecret string // HS256 secret bearer tokens are verified with, for the claim source
+	Required    bool   // Reject requests without a tenant (otherwise they run without one)
+	// Database is the database of each tenant, with {tenant} replaced by its
+	// ID (e.g., "app_{tenant}"; "" = tenants share the database)
+	Database string
+	// Schema is the schema of each tenant, with {tenant} replaced by its ID
+	// (e.g., "tenant_{tenant}"; "" = tenants share the schema)
+	Schema string
+}
+
+// DefaultTenancyConfig returns default multi-tenancy configuration
+func DefaultTenancyConfig() TenancyConfig {
+	return TenancyConfig{
+		Enabled:     false,
+		Source:      TenantSourceHeader,
+		Header:      "X-Tenant-ID",
+		BaseDomain:  "",
+		Claim:       "tenant",
+		TokenSecret: "",
+		Required:    true,
+		Database:    "",
+		Schema:      "",
+	}
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/viper"
+)
+
+// ByteSize is a size in bytes, configured as a number of bytes or with a unit
+// (e.g., "512KB", "10MB", "1GiB")
+type ByteSize int64
+
+// Byte size units. KB/MB/GB are decimal; KiB/MiB/GiB are binary.
+const (
+	Byte     ByteSize = 1
+	Kilobyte          = 1000 * Byte
+	Megabyte          = 1000 * Kilobyte
+	Gigabyte          = 1000 * Megabyte
+	Kibibyte          = 1024 * Byte
+	Mebibyte          = 1024 * Kibibyte
+	Gibibyte          = 1024 * Mebibyte
+)
+
+// byteSizeUnits maps unit suffixes (lowercase) to their size
+var byteSizeUnits = map[string]ByteSize{
+	"":    Byte,
+	"b":   Byte,
+	"kb":  Kilobyte,
+	"mb":  Megabyte,
+	"gb":  Gigabyte,
+	"kib": Kibibyte,
+	"mib": Mebibyte,
+	"gib": Gibibyte,
+}
+
+// ParseByteSize parses a size like "10MB", "1.5GiB", or "4096"
+func ParseByteSize(s string) (ByteSize, error) {
+	trimmed := strings.TrimSpace(s)
+	i := strings.IndexFunc(trimmed, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i == -1 {
+		i = len(trimmed)
+	}
+
+	n, err := strconv.ParseFloat(trimmed[:i], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	unit, ok := byteSizeUnits[strings.ToLower(strings.TrimSpace(trimmed[i:]))]
+	if !ok {
+		return 0, fmt.Errorf("invalid size %q: unknown unit %q (use B, KB, MB, GB, KiB, MiB, or GiB)", s, trimmed[i:])
+	}
+	return ByteSize(n * float64(unit)), nil
+}
+
+// String formats the size with the largest unit that divides it evenly
+func (b ByteSize) String() string {
+	for _, unit := range []struct {
+		size ByteSize
+		name string
+	}{{Gibibyte, "GiB"}, {Mebibyte, "MiB"}, {Kibibyte, "KiB"}, {Gigabyte, "GB"}, {Megabyte, "MB"}, {Kilobyte, "KB"}} {
+		if b != 0 && b%unit.size == 0 {
+			return fmt.Sprintf("%d%s", b/unit.size, unit.name)
+		}
+	}
+	return fmt.Sprintf("%dB", int64(b))
+}
+
+// durationUnits lists the duration settings and the unit of a bare number,
+// which keeps integer values written before durations were supported working
+var durationUnits = map[string]time.Duration{
+	"process.actiontimeout":    time.Millisecond,
+	"process.startuptimeout":   time.Millisecond,
+	"logger.slowaction":        time.Millisecond,
+	"logger.errorsamplewindow": time.Millisecond,
+	"logger.filemaxage":        time.Millisecond,
+	"server.web.idletimeout":   time.Millisecond,
+	"server.web.reapinterval":  time.Millisecond,
+	"session.ttl":              time.Second,
+	"tasks.timeout":            time.Millisecond,
+	"tasks.stuckworkertimeout": time.Millisecond,
+	"secrets.cachettl":         time.Millisecond,
+	"secrets.refresh":          time.Millisecond,
+	"secrets.timeout":          time.Millisecond,
+}
+
+// renamedDurationKeys maps settings that used to be integer milliseconds to
+// the duration settings that replaced them. The old keys are still read.
+var renamedDurationKeys = map[string]string{
+	"logger.slowactionms":        "logger.slowaction",
+	"logger.errorsamplewindowms": "logger.errorsamplewindow",
+}
+
+// normalizeDurations rewrites bare numbers for duration settings (from any
+// source) as durations in the setting's unit, and applies renamed keys.
+// Duration fields of registered sections use milliseconds.
+func normalizeDurations(v *viper.Viper, envPrefix string) error {
+	for oldKey, newKey := range renamedDurationKeys {
+		if v.IsSet(oldKey) && !isExplicitlySet(v, envPrefix, newKey) {
+			v.Set(newKey, v.Get(oldKey))
+		}
+	}
+
+	units := make(map[string]time.Duration, len(durationUnits))
+	for key, unit := range durationUnits {
+		units[key] = unit
+	}
+	for _, key := range sectionDurationKeys() {
+		units[key] = time.Millisecond
+	}
+
+	for key, unit := range units {
+		d, err := parseDuration(v.Get(key), unit)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		v.Set(key, d)
+	}
+	return nil
+}
+
+// isExplicitlySet returns whether key is set by a config file or environment
+// variable, rather than by its default
+func isExplicitlySet(v *viper.Viper, envPrefix, key string) bool {
+	if v.InConfig(key) {
+		return true
+	}
+	_, ok := os.LookupEnv(EnvVarName(envPrefix, key))
+	return ok
+}
+
+// parseDuration converts a config value to a duration: numbers (and numeric
+// strings) are counted in unit, and other strings are parsed like "30s" or "24h"
+func parseDuration(value interface{}, unit time.Duration) (time.Duration, error) {
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case time.Duration:
+		return v, nil
+	case int:
+		return time.Duration(v) * unit, nil
+	case int64:
+		return time.Duration(v) * unit, nil
+	case float64:
+		return time.Duration(v * float64(unit)), nil
+	case string:
+		s := strings.TrimSpace(v)
+		if n, err := strconv.ParseFloat(s, 64); err == nil {
+			return time.Duration(n * float64(unit)), nil
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q (use a unit, e.g. \"500ms\", \"30s\", or \"24h\")", v)
+		}
+		return d, nil
+	default:
+		return 0, fmt.Errorf("invalid duration %v", value)
+	}
+}
+
+// byteSizeHook decodes numbers and size strings into ByteSize fields
+func byteSizeHook(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	if to != reflect.TypeOf(ByteSize(0)) {
+		return data, nil
+	}
+	if s, ok := data.(string); ok {
+		return ParseByteSize(s)
+	}
+	return data, nil
+}
+
+// decodeHooks are used when unmarshaling config. They extend viper's default
+// hooks with byte sizes.
+var decodeHooks = mapstructure.ComposeDecodeHookFunc(
+	byteSizeHook,
+	mapstructure.StringToTimeDurationHookFunc(),
+	mapstructure.StringToSliceHookFunc(","),
+)
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected ByteSize
+	}{
+		{"4096", 4096},
+		{"512B", 512},
+		{"10MB", 10 * Megabyte},
+		{"10 mb", 10 * Megabyte},
+		{"1.5KB", 1500},
+		{"1GiB", Gibibyte},
+		{"64KiB", 64 * Kibibyte},
+	}
+
+	for _, tt := range tests {
+		size, err := ParseByteSize(tt.input)
+		if err != nil {
+			t.Errorf("ParseByteSize(%q): unexpected error %v", tt.input, err)
+			continue
+		}
+		if size != tt.expected {
+			t.Errorf("ParseByteSize(%q) = %d, expected %d", tt.input, size, tt.expected)
+		}
+	}
+
+	for _, input := range []string{"", "MB", "10XB", "ten"} {
+		if _, err := ParseByteSize(input); err == nil {
+			t.Errorf("ParseByteSize(%q): expected an error", input)
+		}
+	}
+}
+
+func TestByteSize_String(t *testing.T) {
+	tests := map[ByteSize]string{
+		0:               "0B",
+		100:             "100B",
+		10 * Megabyte:   "10MB",
+		64 * Kibibyte:   "64KiB",
+		2 * Gibibyte:    "2GiB",
+		1500 * Kibibyte: "1500KiB",
+	}
+	for size, expected := range tests {
+		if size.String() != expected {
+			t.Errorf("Expected %s, got %s", expected, size.String())
+		}
+	}
+}
+
+func TestLoad_Durations(t *testing.T) {
+	os.Clearenv()
+	defer os.Clearenv()
+
+	_ = os.Setenv("ACTIONHERO_TASKS_TIMEOUT", "30s")
+	_ = os.Setenv("ACTIONHERO_TASKS_STUCKWORKERTIMEOUT", "90000") // bare number: milliseconds
+	_ = os.Setenv("ACTIONHERO_SESSION_TTL", "3600")               // bare number: seconds
+	_ = os.Setenv("ACTIONHERO_SERVER_WEB_MAXBODYSIZE", "1MiB")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if cfg.Tasks.Timeout != 30*time.Second {
+		t.Errorf("Expected tasks timeout 30s, got %s", cfg.Tasks.Timeout)
+	}
+	if cfg.Tasks.StuckWorkerTimeout != 90*time.Second {
+		t.Errorf("Expected stuck worker timeout 90s, got %s", cfg.Tasks.StuckWorkerTimeout)
+	}
+	if cfg.Session.TTL != time.Hour {
+		t.Errorf("Expected session TTL 1h, got %s", cfg.Session.TTL)
+	}
+	if cfg.Server.Web.MaxBodySize != Mebibyte {
+		t.Errorf("Expected max body size 1MiB, got %s", cfg.Server.Web.MaxBodySize)
+	}
+}
+
+func TestLoad_DurationsFromConfigFile(t *testing.T) {
+	t.Chdir(t.TempDir())
+	os.Clearenv()
+
+	writeConfigFile(t, "config.yaml", "logger:\n  slowaction: 250ms\nsession:\n  ttl: 2h\ntasks:\n  timeout: 5000\n")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Logger.SlowAction != 250*time.Millisecond {
+		t.Errorf("Expected slow action 250ms, got %s", cfg.Logger.SlowAction)
+	}
+	if cfg.Session.TTL != 2*time.Hour {
+		t.Errorf("Expected session TTL 2h, got %s", cfg.Session.TTL)
+	}
+	if cfg.Tasks.Timeout != 5*time.Second {
+		t.Errorf("Expected tasks timeout 5s, got %s", cfg.Tasks.Timeout)
+	}
+}
+
+func TestLoad_RenamedDurationKeys(t *testing.T) {
+	os.Clearenv()
+	defer os.Clearenv()
+
+	_ = os.Setenv("ACTIONHERO_LOGGER_SLOWACTIONMS", "2500")
+	_ = os.Setenv("ACTIONHERO_LOGGER_ERRORSAMPLEWINDOWMS", "5000")
+	_ = os.Setenv("ACTIONHERO_LOGGER_ERRORSAMPLEWINDOW", "1m") // the new key wins
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Logger.SlowAction != 2500*time.Millisecond {
+		t.Errorf("Expected slow action 2.5s from the old key, got %s", cfg.Logger.SlowAction)
+	}
+	if cfg.Logger.ErrorSampleWindow != time.Minute {
+		t.Errorf("Expected error sample window 1m from the new key, got %s", cfg.Logger.ErrorSampleWindow)
+	}
+}
+
+func TestLoad_InvalidDuration(t *testing.T) {
+	os.Clearenv()
+	defer os.Clearenv()
+
+	_ = os.Setenv("ACTIONHERO_TASKS_TIMEOUT", "soon")
+
+	if _, err := Load(); err == nil {
+		t.Error("Expected an invalid duration to fail loading")
+	}
+}
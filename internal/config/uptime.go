@@ -0,0 +1,37 @@
+package config
+
+import (
+	"strings"
+	"time"
+)
+
+// UptimeConfig holds configuration for uptime checks: URLs the API requests
+// periodically to monitor itself and its dependencies, reported by the
+// status action and metrics
+type UptimeConfig struct {
+	Enabled  bool
+	URLs     string        // Comma-separated http(s) URLs to check
+	Interval time.Duration // Time between checks of each URL
+	Timeout  time.Duration // Limit for each check (at most Interval)
+}
+
+// DefaultUptimeConfig returns default uptime check configuration
+func DefaultUptimeConfig() UptimeConfig {
+	return UptimeConfig{
+		Enabled:  false,
+		URLs:     "",
+		Interval: time.Minute,
+		Timeout:  10 * time.Second,
+	}
+}
+
+// CheckURLs returns the configured URLs
+func (c UptimeConfig) CheckURLs() []string {
+	var urls []string
+	for _, url := range strings.Split(c.URLs, ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
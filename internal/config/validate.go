@@ -0,0 +1,467 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// validLogLevels lists the logger levels understood by the logger
+var validLogLevels = []string{"trace", "debug", "info", "warn", "warning", "error", "fatal", "panic"}
+
+// validErrorDisclosures lists the process.errordisclosure modes
+var validErrorDisclosures = []string{ErrorDisclosureDebug, ErrorDisclosureStandard, ErrorDisclosureProduction}
+
+// validMailTransports lists the mail.transport values
+var validMailTransports = []string{"smtp", "ses"}
+
+// localePattern matches locale tags (e.g., "en", "pt-BR", or "zh_Hant")
+var localePattern = regexp.MustCompile(`^[A-Za-z]{2,8}([-_][A-Za-z0-9]{1,8})*$`)
+
+// validStorageBackends lists the storage.backend values
+var validStorageBackends = []string{"local", "s3"}
+
+// ValidationError describes a single invalid configuration value
+type ValidationError struct {
+	Key     string // Config key (e.g., "server.web.port")
+	Value   interface{}
+	Message string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s (got %v); set %s in a config file or %s", e.Key, e.Message, e.Value, e.Key, e.EnvVar())
+}
+
+// EnvVar returns the environment variable that sets this key
+func (e *ValidationError) EnvVar() string {
+	return "ACTIONHERO_" + strings.ToUpper(strings.ReplaceAll(e.Key, ".", "_"))
+}
+
+// Validate checks the configuration for invalid types and ranges.
+// All problems are collected and returned together (joined with errors.Join),
+// so a single run reports everything that needs fixing.
+func (c *Config) Validate() error {
+	var errs []error
+
+	add := func(key string, value interface{}, message string) {
+		errs = append(errs, &ValidationError{Key: key, Value: value, Message: message})
+	}
+
+	// Process
+	if strings.TrimSpace(c.Process.Name) == "" {
+		add("process.name", c.Process.Name, "must not be empty")
+	}
+	if c.Process.ActionTimeout < 0 {
+		add("process.actiontimeout", c.Process.ActionTimeout, "must not be negative (0 disables the default action timeout)")
+	}
+	if c.Process.StartupTimeout < 0 {
+		add("process.startuptimeout", c.Process.StartupTimeout, "must not be negative (0 disables startup timeouts)")
+	}
+	if !slices.Contains(validErrorDisclosures, c.Process.ErrorDisclosure) {
+		add("process.errordisclosure", c.Process.ErrorDisclosure, fmt.Sprintf("must be one of %s", strings.Join(validErrorDisclosures, ", ")))
+	}
+
+	// Logger
+	if !isValidLogLevel(c.Logger.Level) {
+		add("logger.level", c.Logger.Level, fmt.Sprintf("must be one of %s", strings.Join(validLogLevels, ", ")))
+	}
+
+	if c.Logger.SlowAction < 0 {
+		add("logger.slowaction", c.Logger.SlowAction, "must not be negative (0 disables slow action warnings)")
+	}
+
+	if c.Logger.ErrorSampleFirst < 0 {
+		add("logger.errorsamplefirst", c.Logger.ErrorSampleFirst, "must not be negative (0 disables error sampling)")
+	}
+	if c.Logger.ErrorSampleThereafter < 0 {
+		add("logger.errorsamplethereafter", c.Logger.ErrorSampleThereafter, "must not be negative")
+	}
+	if c.Logger.ErrorSampleFirst > 0 && c.Logger.ErrorSampleWindow <= 0 {
+		add("logger.errorsamplewindow", c.Logger.ErrorSampleWindow, "must be greater than 0 when error sampling is enabled")
+	}
+	if c.Logger.AsyncBuffer < 0 {
+		add("logger.asyncbuffer", c.Logger.AsyncBuffer, "must not be negative (0 logs synchronously)")
+	}
+	if c.Logger.FileMaxSize < 0 {
+		add("logger.filemaxsize", c.Logger.FileMaxSize, "must not be negative (0 disables size-based rotation)")
+	}
+	if c.Logger.FileMaxAge < 0 {
+		add("logger.filemaxage", c.Logger.FileMaxAge, "must not be negative (0 disables age-based rotation)")
+	}
+	if c.Logger.FileMaxBackups < 0 {
+		add("logger.filemaxbackups", c.Logger.FileMaxBackups, "must not be negative (0 keeps every rotated file)")
+	}
+	if c.Logger.Syslog != "" && c.Logger.Syslog != "local" && !isValidSyslogURL(c.Logger.Syslog) {
+		add("logger.syslog", c.Logger.Syslog, "must be \"local\", udp://host:port, or tcp://host:port")
+	}
+	if c.Logger.Syslog != "" && !isValidSyslogFacility(c.Logger.SyslogFacility) {
+		add("logger.syslogfacility", c.Logger.SyslogFacility, "must be a syslog facility (e.g., daemon, local0)")
+	}
+
+	// Ports
+	if !isValidPort(c.Database.Port) {
+		add("database.port", c.Database.Port, "must be between 1 and 65535")
+	}
+	if c.Database.SeedOnStart && strings.TrimSpace(c.Database.Fixtures) == "" {
+		add("database.fixtures", c.Database.Fixtures, "must not be empty when database.seedonstart is set")
+	}
+	if !isValidPort(c.Redis.Port) {
+		add("redis.port", c.Redis.Port, "must be between 1 and 65535")
+	}
+	if !isValidPort(c.Server.Web.Port) {
+		add("server.web.port", c.Server.Web.Port, "must be between 1 and 65535")
+	}
+
+	if c.StatsD.Enabled && !isValidPort(c.StatsD.Port) {
+		add("statsd.port", c.StatsD.Port, "must be between 1 and 65535")
+	}
+	if c.Server.Web.DebugEnabled && c.Server.Web.DebugPort != 0 && !isValidPort(c.Server.Web.DebugPort) {
+		add("server.web.debugport", c.Server.Web.DebugPort, "must be between 1 and 65535, or 0 to use the web server")
+	}
+
+	// Routes
+	if !isValidRoute(c.Server.Web.APIRoute) {
+		add("server.web.apiroute", c.Server.Web.APIRoute, "must start with /")
+	}
+	if c.Server.Web.StaticFilesEnabled {
+		if !isValidRoute(c.Server.Web.StaticFilesRoute) {
+			add("server.web.staticfilesroute", c.Server.Web.StaticFilesRoute, "must start with / when static files are enabled")
+		}
+		if strings.TrimSpace(c.Server.Web.StaticFilesDirectory) == "" {
+			add("server.web.staticfilesdirectory", c.Server.Web.StaticFilesDirectory, "must not be empty when static files are enabled")
+		}
+	}
+	if c.Server.Web.MetricsEnabled && !isValidRoute(c.Server.Web.MetricsRoute) {
+		add("server.web.metricsroute", c.Server.Web.MetricsRoute, "must start with / when metrics are enabled")
+	}
+	if c.Server.Web.DebugEnabled && !isValidRoute(c.Server.Web.DebugRoute) {
+		add("server.web.debugroute", c.Server.Web.DebugRoute, "must start with / when debug endpoints are enabled")
+	}
+	if c.Server.Web.AdminEnabled {
+		if !isValidRoute(c.Server.Web.AdminRoute) || c.Server.Web.AdminRoute == "/" {
+			add("server.web.adminroute", c.Server.Web.AdminRoute, "must start with / and not be / when the admin dashboard is enabled")
+		}
+		if strings.TrimSpace(c.Server.Web.AdminUser) == "" {
+			add("server.web.adminuser", c.Server.Web.AdminUser, "must not be empty when the admin dashboard is enabled")
+		}
+		if c.Server.Web.AdminPassword == "" {
+			add("server.web.adminpassword", "", "must be set when the admin dashboard is enabled")
+		}
+	}
+
+	// Swagger
+	if c.Server.Web.OpenAPIVersion != OpenAPIVersion30 && c.Server.Web.OpenAPIVersion != OpenAPIVersion31 {
+		add("server.web.openapiversion", c.Server.Web.OpenAPIVersion,
+			fmt.Sprintf("must be %s or %s", OpenAPIVersion30, OpenAPIVersion31))
+	}
+
+	for _, server := range c.OpenAPI.ServerURLs() {
+		if !isValidHTTPURL(server) {
+			add("openapi.servers", server, "must be http(s) URLs")
+		}
+	}
+	if c.OpenAPI.ContactURL != "" && !isValidHTTPURL(c.OpenAPI.ContactURL) {
+		add("openapi.contacturl", c.OpenAPI.ContactURL, "must be an http(s) URL")
+	}
+	if c.OpenAPI.LicenseURL != "" && !isValidHTTPURL(c.OpenAPI.LicenseURL) {
+		add("openapi.licenseurl", c.OpenAPI.LicenseURL, "must be an http(s) URL")
+	}
+
+	// Redis
+	if c.Redis.DB < 0 {
+		add("redis.db", c.Redis.DB, "must not be negative")
+	}
+
+	// Durations
+	if c.Server.Web.MaxBodySize < 0 {
+		add("server.web.maxbodysize", c.Server.Web.MaxBodySize, "must not be negative (0 disables the limit)")
+	}
+	if c.Server.Web.IdleTimeout < 0 {
+		add("server.web.idletimeout", c.Server.Web.IdleTimeout, "must not be negative (0 never closes idle connections)")
+	}
+	if c.Server.Web.ReapInterval <= 0 {
+		add("server.web.reapinterval", c.Server.Web.ReapInterval, "must be greater than 0")
+	}
+	if c.Server.Web.MessageRate < 0 {
+		add("server.web.messagerate", c.Server.Web.MessageRate, "must not be negative (0 disables the limit)")
+	}
+	if c.Server.Web.MessageRate > 0 && c.Server.Web.MessageBurst < 1 {
+		add("server.web.messageburst", c.Server.Web.MessageBurst, "must be at least 1 when the message rate is limited")
+	}
+	if c.Server.Web.BroadcastWorkers < 0 {
+		add("server.web.broadcastworkers", c.Server.Web.BroadcastWorkers, "must not be negative (0 uses one worker per CPU)")
+	}
+	if c.Server.Web.RouteCacheSize < 0 {
+		add("server.web.routecachesize", c.Server.Web.RouteCacheSize, "must not be negative (0 disables the cache)")
+	}
+	for _, list := range []struct{ key, value string }{
+		{"server.web.allowedips", c.Server.Web.AllowedIPs},
+		{"server.web.deniedips", c.Server.Web.DeniedIPs},
+		{"server.web.metricsallowedips", c.Server.Web.MetricsAllowedIPs},
+		{"server.web.debugallowedips", c.Server.Web.DebugAllowedIPs},
+		{"server.web.adminallowedips", c.Server.Web.AdminAllowedIPs},
+	} {
+		if _, err := ParseIPList(list.value); err != nil {
+			add(list.key, list.value, fmt.Sprintf("must be comma-separated IPs or CIDR ranges (%v)", err))
+		}
+	}
+	if c.Server.Web.TLSCertFile != "" && c.Server.Web.TLSKeyFile == "" {
+		add("server.web.tlskeyfile", c.Server.Web.TLSKeyFile, "must be set when server.web.tlscertfile is")
+	}
+	if c.Server.Web.TLSKeyFile != "" && c.Server.Web.TLSCertFile == "" {
+		add("server.web.tlscertfile", c.Server.Web.TLSCertFile, "must be set when server.web.tlskeyfile is")
+	}
+	if c.Server.Web.MessageRateWarnings < 0 {
+		add("server.web.messageratewarnings", c.Server.Web.MessageRateWarnings, "must not be negative (0 disconnects without warning)")
+	}
+	if c.Server.Web.MaxActionsInFlight < 1 {
+		add("server.web.maxactionsinflight", c.Server.Web.MaxActionsInFlight, "must be at least 1")
+	}
+
+	if c.Session.TTL <= 0 {
+		add("session.ttl", c.Session.TTL, "must be greater than 0")
+	}
+	if !slices.Contains(validSameSiteModes, c.Session.SameSite) {
+		add("session.samesite", c.Session.SameSite, fmt.Sprintf("must be one of %s", strings.Join(validSameSiteModes, ", ")))
+	} else if c.Session.SameSite == SameSiteNone && !c.Session.Secure {
+		add("session.secure", c.Session.Secure, "must be true when session.samesite is none (browsers reject the cookie otherwise)")
+	}
+	if !isValidRoute(c.Session.Path) {
+		add("session.path", c.Session.Path, "must start with /")
+	}
+	if c.Tasks.Timeout <= 0 {
+		add("tasks.timeout", c.Tasks.Timeout, "must be greater than 0")
+	}
+	if c.Tasks.StuckWorkerTimeout <= 0 {
+		add("tasks.stuckworkertimeout", c.Tasks.StuckWorkerTimeout, "must be greater than 0")
+	} else if c.Tasks.StuckWorkerTimeout <= c.Tasks.Timeout {
+		// Jobs still running would be taken back as stuck
+		add("tasks.stuckworkertimeout", c.Tasks.StuckWorkerTimeout, "must be greater than tasks.timeout")
+	}
+
+	// Tasks
+	if c.Tasks.TaskProcessors < 0 {
+		add("tasks.taskprocessors", c.Tasks.TaskProcessors, "must not be negative")
+	}
+	if c.Tasks.Enabled && len(c.Tasks.Queues) == 0 {
+		add("tasks.queues", c.Tasks.Queues, "must list at least one queue when tasks are enabled")
+	}
+
+	// Secrets
+	if c.Secrets.CacheTTL < 0 {
+		add("secrets.cachettl", c.Secrets.CacheTTL, "must not be negative (0 disables caching)")
+	}
+	if c.Secrets.Refresh < 0 {
+		add("secrets.refresh", c.Secrets.Refresh, "must not be negative (0 disables refreshing)")
+	}
+	if c.Secrets.Timeout < 0 {
+		add("secrets.timeout", c.Secrets.Timeout, "must not be negative (0 disables the timeout)")
+	}
+
+	// StatsD
+	if c.StatsD.Enabled && strings.TrimSpace(c.StatsD.Host) == "" {
+		add("statsd.host", c.StatsD.Host, "must not be empty when statsd is enabled")
+	}
+
+	// Mail
+	if c.Mail.QueueSize < 0 {
+		add("mail.queuesize", c.Mail.QueueSize, "must not be negative")
+	}
+	if c.Mail.Enabled {
+		if !slices.Contains(validMailTransports, c.Mail.Transport) {
+			add("mail.transport", c.Mail.Transport, fmt.Sprintf("must be one of %s", strings.Join(validMailTransports, ", ")))
+		}
+		if _, err := mail.ParseAddress(c.Mail.From); err != nil {
+			add("mail.from", c.Mail.From, "must be an email address when mail is enabled")
+		}
+		if c.Mail.Transport == "smtp" && !isValidPort(c.Mail.SMTPPort) {
+			add("mail.smtpport", c.Mail.SMTPPort, "must be between 1 and 65535")
+		}
+		if c.Mail.Async && c.Mail.QueueSize == 0 {
+			add("mail.queuesize", c.Mail.QueueSize, "must be positive when mail.async is set")
+		}
+	}
+
+	// Storage
+	if c.Storage.Enabled {
+		if !slices.Contains(validStorageBackends, c.Storage.Backend) {
+			add("storage.backend", c.Storage.Backend, fmt.Sprintf("must be one of %s", strings.Join(validStorageBackends, ", ")))
+		}
+		if c.Storage.Backend == "local" {
+			if strings.TrimSpace(c.Storage.Directory) == "" {
+				add("storage.directory", c.Storage.Directory, "must not be empty with the local backend")
+			}
+			if !isValidRoute(c.Storage.Route) {
+				add("storage.route", c.Storage.Route, "must start with / with the local backend")
+			}
+			if c.Storage.PublicURL != "" && !isValidHTTPURL(c.Storage.PublicURL) {
+				add("storage.publicurl", c.Storage.PublicURL, "must be an absolute http or https URL")
+			}
+		}
+		if c.Storage.Backend == "s3" && strings.TrimSpace(c.Storage.S3Bucket) == "" {
+			add("storage.s3bucket", c.Storage.S3Bucket, "must not be empty with the s3 backend")
+		}
+		if c.Storage.S3Endpoint != "" && !isValidHTTPURL(c.Storage.S3Endpoint) {
+			add("storage.s3endpoint", c.Storage.S3Endpoint, "must be an absolute http or https URL")
+		}
+	}
+
+	// HTTP client
+	if c.HTTPClient.Timeout < 0 {
+		add("httpclient.timeout", c.HTTPClient.Timeout, "must not be negative (0 disables the timeout)")
+	}
+	if c.HTTPClient.Retries < 0 {
+		add("httpclient.retries", c.HTTPClient.Retries, "must not be negative (0 never retries)")
+	}
+	if c.HTTPClient.Retries > 0 && c.HTTPClient.RetryBackoff <= 0 {
+		add("httpclient.retrybackoff", c.HTTPClient.RetryBackoff, "must be greater than 0 when requests are retried")
+	}
+	if c.HTTPClient.Retries > 0 && c.HTTPClient.RetryMaxBackoff < c.HTTPClient.RetryBackoff {
+		add("httpclient.retrymaxbackoff", c.HTTPClient.RetryMaxBackoff, "must be at least httpclient.retrybackoff")
+	}
+	if c.HTTPClient.BreakerThreshold < 0 {
+		add("httpclient.breakerthreshold", c.HTTPClient.BreakerThreshold, "must not be negative (0 disables the circuit breaker)")
+	}
+	if c.HTTPClient.BreakerThreshold > 0 && c.HTTPClient.BreakerCooldown <= 0 {
+		add("httpclient.breakercooldown", c.HTTPClient.BreakerCooldown, "must be greater than 0 when the circuit breaker is enabled")
+	}
+
+	if c.Breaker.Threshold < 0 {
+		add("breaker.threshold", c.Breaker.Threshold, "must not be negative (0 never opens breakers)")
+	}
+	if c.Breaker.Threshold > 0 && c.Breaker.Cooldown <= 0 {
+		add("breaker.cooldown", c.Breaker.Cooldown, "must be greater than 0 when breakers can open")
+	}
+	if c.Breaker.HalfOpenProbes < 1 {
+		add("breaker.halfopenprobes", c.Breaker.HalfOpenProbes, "must be at least 1")
+	}
+
+	if c.Tenancy.Enabled {
+		switch c.Tenancy.Source {
+		case TenantSourceHeader:
+			if c.Tenancy.Header == "" {
+				add("tenancy.header", c.Tenancy.Header, "is required for the header source")
+			}
+		case TenantSourceSubdomain:
+			if c.Tenancy.BaseDomain == "" {
+				add("tenancy.basedomain", c.Tenancy.BaseDomain, "is required for the subdomain source")
+			}
+		case TenantSourceClaim:
+			if c.Tenancy.Claim == "" {
+				add("tenancy.claim", c.Tenancy.Claim, "is required for the claim source")
+			}
+			if c.Tenancy.TokenSecret == "" {
+				add("tenancy.tokensecret", c.Tenancy.TokenSecret, "is required to verify tokens for the claim source")
+			}
+		default:
+			add("tenancy.source", c.Tenancy.Source, fmt.Sprintf("must be one of: %s, %s, %s",
+				TenantSourceHeader, TenantSourceSubdomain, TenantSourceClaim))
+		}
+	}
+	if c.Tenancy.Database != "" && !strings.Contains(c.Tenancy.Database, "{tenant}") {
+		add("tenancy.database", c.Tenancy.Database, "must contain {tenant}")
+	}
+	if c.Tenancy.Schema != "" && !strings.Contains(c.Tenancy.Schema, "{tenant}") {
+		add("tenancy.schema", c.Tenancy.Schema, "must contain {tenant}")
+	}
+
+	if c.GeoIP.Enabled && c.GeoIP.Database == "" {
+		add("geoip.database", c.GeoIP.Database, "is required when geoip is enabled")
+	}
+
+	// Uptime
+	if c.Uptime.Enabled && len(c.Uptime.CheckURLs()) == 0 {
+		add("uptime.urls", c.Uptime.URLs, "are required when uptime is enabled")
+	}
+	for _, url := range c.Uptime.CheckURLs() {
+		if !isValidHTTPURL(url) {
+			add("uptime.urls", url, "must be http(s) URLs")
+		}
+	}
+	if c.Uptime.Interval <= 0 {
+		add("uptime.interval", c.Uptime.Interval, "must be positive")
+	}
+	if c.Uptime.Timeout <= 0 || c.Uptime.Timeout > c.Uptime.Interval {
+		add("uptime.timeout", c.Uptime.Timeout, "must be positive and at most uptime.interval")
+	}
+
+	// I18n
+	if c.I18n.DefaultLocale != "" && !localePattern.MatchString(c.I18n.DefaultLocale) {
+		add("i18n.defaultlocale", c.I18n.DefaultLocale, "must be a locale (e.g., en or pt-BR), or empty for none")
+	}
+
+	// Views
+	if c.Views.Layout != "" && !filepath.IsLocal(filepath.FromSlash(c.Views.Layout)) {
+		add("views.layout", c.Views.Layout, "must be a path inside views.directory, or empty for none")
+	}
+
+	// Sentry
+	if c.Sentry.DSN != "" {
+		if _, err := ParseSentryDSN(c.Sentry.DSN); err != nil {
+			add("sentry.dsn", "(hidden)", err.Error())
+		}
+	}
+	if c.Sentry.SampleRate < 0 || c.Sentry.SampleRate > 1 {
+		add("sentry.samplerate", c.Sentry.SampleRate, "must be between 0 and 1")
+	}
+
+	// Registered sections validate themselves
+	for _, name := range sortedKeys(c.Sections) {
+		if validator, ok := c.Sections[name].(interface{ Validate() error }); ok {
+			if err := validator.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// isValidPort returns whether the port is in the valid TCP range
+func isValidPort(port int) bool {
+	return port >= 1 && port <= 65535
+}
+
+// isValidRoute returns whether the route is an absolute URL path
+func isValidRoute(route string) bool {
+	return strings.HasPrefix(route, "/")
+}
+
+// isValidHTTPURL returns whether the value is an absolute http or https URL
+func isValidHTTPURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// isValidSyslogURL returns whether the value is a udp:// or tcp:// address with a port
+func isValidSyslogURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "udp" || u.Scheme == "tcp") && u.Hostname() != "" && u.Port() != ""
+}
+
+// isValidSyslogFacility returns whether the name is a syslog facility
+func isValidSyslogFacility(name string) bool {
+	for _, facility := range SyslogFacilities {
+		if facility != "" && strings.EqualFold(facility, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidLogLevel returns whether the level is understood by the logger
+func isValidLogLevel(level string) bool {
+	level = strings.ToLower(level)
+	for _, valid := range validLogLevels {
+		if level == valid {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,260 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func validConfig() *Config {
+	return &Config{
+		Process:    DefaultProcessConfig(),
+		Logger:     DefaultLoggerConfig(),
+		Database:   DefaultDatabaseConfig(),
+		Redis:      DefaultRedisConfig(),
+		Session:    DefaultSessionConfig(),
+		Server:     ServerConfig{Web: DefaultWebServerConfig()},
+		Tasks:      DefaultTasksConfig(),
+		Sentry:     DefaultSentryConfig(),
+		StatsD:     DefaultStatsDConfig(),
+		Mail:       DefaultMailConfig(),
+		Storage:    DefaultStorageConfig(),
+		HTTPClient: DefaultHTTPClientConfig(),
+		Breaker:    DefaultBreakerConfig(),
+		Tenancy:    DefaultTenancyConfig(),
+		GeoIP:      DefaultGeoIPConfig(),
+		Uptime:     DefaultUptimeConfig(),
+		I18n:       DefaultI18nConfig(),
+		Views:      DefaultViewsConfig(),
+		OpenAPI:    DefaultOpenAPIConfig(),
+	}
+}
+
+func TestValidate_Defaults(t *testing.T) {
+	if err := validConfig().Validate(); err != nil {
+		t.Errorf("Expected default config to be valid, got %v", err)
+	}
+}
+
+func TestValidate_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*Config)
+		key    string
+	}{
+		{"process name", func(c *Config) { c.Process.Name = " " }, "process.name"},
+		{"log level", func(c *Config) { c.Logger.Level = "loud" }, "logger.level"},
+		{"log file max size", func(c *Config) { c.Logger.FileMaxSize = -1 }, "logger.filemaxsize"},
+		{"log file max age", func(c *Config) { c.Logger.FileMaxAge = -1 }, "logger.filemaxage"},
+		{"log async buffer", func(c *Config) { c.Logger.AsyncBuffer = -1 }, "logger.asyncbuffer"},
+		{"log file max backups", func(c *Config) { c.Logger.FileMaxBackups = -1 }, "logger.filemaxbackups"},
+		{"syslog address", func(c *Config) { c.Logger.Syslog = "syslog.example.com" }, "logger.syslog"},
+		{"syslog facility", func(c *Config) { c.Logger.Syslog = "local"; c.Logger.SyslogFacility = "local9" }, "logger.syslogfacility"},
+		{"error sample first", func(c *Config) { c.Logger.ErrorSampleFirst = -1 }, "logger.errorsamplefirst"},
+		{"error sample window", func(c *Config) { c.Logger.ErrorSampleWindow = 0 }, "logger.errorsamplewindow"},
+		{"slow action threshold", func(c *Config) { c.Logger.SlowAction = -1 }, "logger.slowaction"},
+		{"action timeout", func(c *Config) { c.Process.ActionTimeout = -1 }, "process.actiontimeout"},
+		{"startup timeout", func(c *Config) { c.Process.StartupTimeout = -1 }, "process.startuptimeout"},
+		{"error disclosure", func(c *Config) { c.Process.ErrorDisclosure = "verbose" }, "process.errordisclosure"},
+		{"web port too high", func(c *Config) { c.Server.Web.Port = 70000 }, "server.web.port"},
+		{"web port zero", func(c *Config) { c.Server.Web.Port = 0 }, "server.web.port"},
+		{"openapi version", func(c *Config) { c.Server.Web.OpenAPIVersion = "2.0" }, "server.web.openapiversion"},
+		{"openapi servers", func(c *Config) { c.OpenAPI.Servers = "https://api.example.com,ftp://files" }, "openapi.servers"},
+		{"openapi license url", func(c *Config) { c.OpenAPI.LicenseURL = "not a url" }, "openapi.licenseurl"},
+		{"api route", func(c *Config) { c.Server.Web.APIRoute = "api" }, "server.web.apiroute"},
+		{"static files route", func(c *Config) { c.Server.Web.StaticFilesEnabled = true; c.Server.Web.StaticFilesRoute = "" }, "server.web.staticfilesroute"},
+		{"static files directory", func(c *Config) { c.Server.Web.StaticFilesEnabled = true; c.Server.Web.StaticFilesDirectory = "" }, "server.web.staticfilesdirectory"},
+		{"metrics route", func(c *Config) { c.Server.Web.MetricsRoute = "metrics" }, "server.web.metricsroute"},
+		{"task queues", func(c *Config) { c.Tasks.Queues = nil }, "tasks.queues"},
+		{"secrets cache ttl", func(c *Config) { c.Secrets.CacheTTL = -1 }, "secrets.cachettl"},
+		{"max body size", func(c *Config) { c.Server.Web.MaxBodySize = -1 }, "server.web.maxbodysize"},
+		{"idle timeout", func(c *Config) { c.Server.Web.IdleTimeout = -time.Second }, "server.web.idletimeout"},
+		{"reap interval", func(c *Config) { c.Server.Web.ReapInterval = 0 }, "server.web.reapinterval"},
+		{"message rate", func(c *Config) { c.Server.Web.MessageRate = -1 }, "server.web.messagerate"},
+		{"message burst", func(c *Config) { c.Server.Web.MessageRate = 10; c.Server.Web.MessageBurst = 0 }, "server.web.messageburst"},
+		{"allowed ips", func(c *Config) { c.Server.Web.AllowedIPs = "10.0.0.0/8, nope" }, "server.web.allowedips"},
+		{"denied ips", func(c *Config) { c.Server.Web.DeniedIPs = "10.0.0.0/33" }, "server.web.deniedips"},
+		{"admin route", func(c *Config) {
+			c.Server.Web.AdminEnabled = true
+			c.Server.Web.AdminPassword = "secret"
+			c.Server.Web.AdminRoute = "/"
+		}, "server.web.adminroute"},
+		{"admin password", func(c *Config) { c.Server.Web.AdminEnabled = true }, "server.web.adminpassword"},
+		{"admin allowed ips", func(c *Config) { c.Server.Web.AdminAllowedIPs = "localhost" }, "server.web.adminallowedips"},
+		{"tls key file", func(c *Config) { c.Server.Web.TLSCertFile = "cert.pem" }, "server.web.tlskeyfile"},
+		{"tls cert file", func(c *Config) { c.Server.Web.TLSKeyFile = "key.pem" }, "server.web.tlscertfile"},
+		{"session samesite", func(c *Config) { c.Session.SameSite = "sometimes" }, "session.samesite"},
+		{"session samesite none without secure", func(c *Config) { c.Session.SameSite = SameSiteNone }, "session.secure"},
+		{"session path", func(c *Config) { c.Session.Path = "api" }, "session.path"},
+		{"message rate warnings", func(c *Config) { c.Server.Web.MessageRateWarnings = -1 }, "server.web.messageratewarnings"},
+		{"max actions in flight", func(c *Config) { c.Server.Web.MaxActionsInFlight = 0 }, "server.web.maxactionsinflight"},
+		{"broadcast workers", func(c *Config) { c.Server.Web.BroadcastWorkers = -1 }, "server.web.broadcastworkers"},
+		{"route cache size", func(c *Config) { c.Server.Web.RouteCacheSize = -1 }, "server.web.routecachesize"},
+		{"statsd host", func(c *Config) { c.StatsD.Enabled = true; c.StatsD.Host = "" }, "statsd.host"},
+		{"redis port", func(c *Config) { c.Redis.Port = -1 }, "redis.port"},
+		{"database port", func(c *Config) { c.Database.Port = 0 }, "database.port"},
+		{"database fixtures", func(c *Config) { c.Database.SeedOnStart = true; c.Database.Fixtures = "" }, "database.fixtures"},
+		{"redis db", func(c *Config) { c.Redis.DB = -1 }, "redis.db"},
+		{"session ttl", func(c *Config) { c.Session.TTL = 0 }, "session.ttl"},
+		{"tasks timeout", func(c *Config) { c.Tasks.Timeout = -5 }, "tasks.timeout"},
+		{"stuck worker timeout within the task timeout", func(c *Config) { c.Tasks.StuckWorkerTimeout = c.Tasks.Timeout }, "tasks.stuckworkertimeout"},
+		{"task processors", func(c *Config) { c.Tasks.TaskProcessors = -1 }, "tasks.taskprocessors"},
+		{"statsd port", func(c *Config) { c.StatsD.Enabled = true; c.StatsD.Port = 0 }, "statsd.port"},
+		{"mail transport", func(c *Config) { c.Mail.Enabled = true; c.Mail.From = "app@example.com"; c.Mail.Transport = "pigeon" }, "mail.transport"},
+		{"mail from", func(c *Config) { c.Mail.Enabled = true; c.Mail.From = "not an address" }, "mail.from"},
+		{"mail smtp port", func(c *Config) { c.Mail.Enabled = true; c.Mail.From = "app@example.com"; c.Mail.SMTPPort = 0 }, "mail.smtpport"},
+		{"mail queue size", func(c *Config) { c.Mail.QueueSize = -1 }, "mail.queuesize"},
+		{"mail async queue size", func(c *Config) {
+			c.Mail.Enabled = true
+			c.Mail.From = "app@example.com"
+			c.Mail.Async = true
+			c.Mail.QueueSize = 0
+		}, "mail.queuesize"},
+		{"http client timeout", func(c *Config) { c.HTTPClient.Timeout = -1 }, "httpclient.timeout"},
+		{"http client retries", func(c *Config) { c.HTTPClient.Retries = -1 }, "httpclient.retries"},
+		{"http client retry backoff", func(c *Config) { c.HTTPClient.RetryBackoff = 0 }, "httpclient.retrybackoff"},
+		{"http client retry max backoff", func(c *Config) { c.HTTPClient.RetryMaxBackoff = time.Millisecond }, "httpclient.retrymaxbackoff"},
+		{"http client breaker cooldown", func(c *Config) { c.HTTPClient.BreakerCooldown = 0 }, "httpclient.breakercooldown"},
+		{"breaker threshold", func(c *Config) { c.Breaker.Threshold = -1 }, "breaker.threshold"},
+		{"breaker cooldown", func(c *Config) { c.Breaker.Cooldown = 0 }, "breaker.cooldown"},
+		{"breaker half-open probes", func(c *Config) { c.Breaker.HalfOpenProbes = 0 }, "breaker.halfopenprobes"},
+		{"tenancy source", func(c *Config) { c.Tenancy.Enabled = true; c.Tenancy.Source = "cookie" }, "tenancy.source"},
+		{"tenancy header", func(c *Config) { c.Tenancy.Enabled = true; c.Tenancy.Header = "" }, "tenancy.header"},
+		{"tenancy base domain", func(c *Config) { c.Tenancy.Enabled = true; c.Tenancy.Source = TenantSourceSubdomain }, "tenancy.basedomain"},
+		{"tenancy token secret", func(c *Config) { c.Tenancy.Enabled = true; c.Tenancy.Source = TenantSourceClaim }, "tenancy.tokensecret"},
+		{"tenancy database", func(c *Config) { c.Tenancy.Database = "app" }, "tenancy.database"},
+		{"tenancy schema", func(c *Config) { c.Tenancy.Schema = "tenant" }, "tenancy.schema"},
+		{"geoip database", func(c *Config) { c.GeoIP.Enabled = true; c.GeoIP.Database = "" }, "geoip.database"},
+		{"uptime urls required", func(c *Config) { c.Uptime.Enabled = true }, "uptime.urls"},
+		{"uptime urls", func(c *Config) { c.Uptime.URLs = "http://localhost:8080/api/status, localhost:5432" }, "uptime.urls"},
+		{"uptime interval", func(c *Config) { c.Uptime.Interval = 0 }, "uptime.interval"},
+		{"uptime timeout", func(c *Config) { c.Uptime.Timeout = 2 * time.Minute }, "uptime.timeout"},
+		{"storage backend", func(c *Config) { c.Storage.Enabled = true; c.Storage.Backend = "ftp" }, "storage.backend"},
+		{"storage directory", func(c *Config) { c.Storage.Enabled = true; c.Storage.Directory = " " }, "storage.directory"},
+		{"storage route", func(c *Config) { c.Storage.Enabled = true; c.Storage.Route = "files" }, "storage.route"},
+		{"storage public url", func(c *Config) { c.Storage.Enabled = true; c.Storage.PublicURL = "example.com" }, "storage.publicurl"},
+		{"storage s3 bucket", func(c *Config) { c.Storage.Enabled = true; c.Storage.Backend = "s3" }, "storage.s3bucket"},
+		{"storage s3 endpoint", func(c *Config) {
+			c.Storage.Enabled = true
+			c.Storage.Backend = "s3"
+			c.Storage.S3Bucket = "uploads"
+			c.Storage.S3Endpoint = "minio:9000"
+		}, "storage.s3endpoint"},
+		{"i18n default locale", func(c *Config) { c.I18n.DefaultLocale = "en;q=1" }, "i18n.defaultlocale"},
+		{"views layout", func(c *Config) { c.Views.Layout = "../layouts/main.html" }, "views.layout"},
+		{"sentry dsn", func(c *Config) { c.Sentry.DSN = "not-a-dsn" }, "sentry.dsn"},
+		{"sentry sample rate", func(c *Config) { c.Sentry.SampleRate = 1.5 }, "sentry.samplerate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.mutate(cfg)
+
+			err := cfg.Validate()
+			if err == nil {
+				t.Fatal("Expected validation error")
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected a ValidationError, got %T", err)
+			}
+			if validationErr.Key != tt.key {
+				t.Errorf("Expected key '%s', got '%s'", tt.key, validationErr.Key)
+			}
+		})
+	}
+}
+
+func TestValidate_AggregatesErrors(t *testing.T) {
+	cfg := validConfig()
+	cfg.Logger.Level = "loud"
+	cfg.Server.Web.Port = 0
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Expected validation error")
+	}
+
+	message := err.Error()
+	if !strings.Contains(message, "logger.level") || !strings.Contains(message, "server.web.port") {
+		t.Errorf("Expected both problems to be reported, got: %s", message)
+	}
+}
+
+func TestValidationError_Actionable(t *testing.T) {
+	err := &ValidationError{Key: "server.web.port", Value: 70000, Message: "must be between 1 and 65535"}
+
+	if err.EnvVar() != "ACTIONHERO_SERVER_WEB_PORT" {
+		t.Errorf("Expected env var ACTIONHERO_SERVER_WEB_PORT, got %s", err.EnvVar())
+	}
+	if !strings.Contains(err.Error(), "ACTIONHERO_SERVER_WEB_PORT") || !strings.Contains(err.Error(), "got 70000") {
+		t.Errorf("Expected message to say how to fix the value, got: %s", err.Error())
+	}
+}
+
+func TestLoad_Validates(t *testing.T) {
+	t.Chdir(t.TempDir())
+	os.Clearenv()
+	_ = os.Setenv("ACTIONHERO_SERVER_WEB_PORT", "70000")
+	_ = os.Setenv("ACTIONHERO_LOGGER_LEVEL", "loud")
+	defer os.Clearenv()
+
+	cfg, err := Load()
+	if err == nil {
+		t.Fatal("Expected Load to fail validation")
+	}
+	if cfg != nil {
+		t.Error("Expected no config when validation fails")
+	}
+	if !strings.Contains(err.Error(), "server.web.port") || !strings.Contains(err.Error(), "logger.level") {
+		t.Errorf("Expected all problems to be reported, got: %s", err.Error())
+	}
+
+	// LoadWithoutValidation still returns the config for inspection
+	cfg, err = LoadWithoutValidation()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Server.Web.Port != 70000 {
+		t.Errorf("Expected the invalid port to be loaded, got %d", cfg.Server.Web.Port)
+	}
+}
+
+func TestParseSentryDSN(t *testing.T) {
+	tests := []struct {
+		dsn      string
+		storeURL string
+		wantErr  bool
+	}{
+		{"https://abc123@o1.ingest.sentry.io/42", "https://o1.ingest.sentry.io/api/42/store/", false},
+		{"http://abc123@localhost:9000/prefix/7", "http://localhost:9000/prefix/api/7/store/", false},
+		{"https://o1.ingest.sentry.io/42", "", true},
+		{"https://abc123@o1.ingest.sentry.io", "", true},
+		{"ftp://abc123@host/1", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dsn, func(t *testing.T) {
+			dsn, err := ParseSentryDSN(tt.dsn)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if dsn.PublicKey != "abc123" {
+				t.Errorf("Expected key 'abc123', got %q", dsn.PublicKey)
+			}
+			if dsn.StoreURL != tt.storeURL {
+				t.Errorf("Expected store URL %q, got %q", tt.storeURL, dsn.StoreURL)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// errVaultNotFound is returned when Vault has no secret at a path
+var errVaultNotFound = errors.New("secret not found")
+
+// vaultProvider reads secrets from HashiCorp Vault's KV engine (v1 or v2)
+// over its HTTP API
+type vaultProvider struct {
+	address   string
+	token     string
+	namespace string
+	client    *http.Client
+}
+
+// newVaultProvider creates a Vault provider, falling back to the standard
+// VAULT_ADDR, VAULT_TOKEN, and VAULT_NAMESPACE environment variables
+func newVaultProvider(cfg SecretsConfig) (SecretProvider, error) {
+	p := &vaultProvider{
+		address:   firstNonEmpty(cfg.VaultAddress, os.Getenv("VAULT_ADDR")),
+		token:     firstNonEmpty(cfg.VaultToken, os.Getenv("VAULT_TOKEN")),
+		namespace: firstNonEmpty(cfg.VaultNamespace, os.Getenv("VAULT_NAMESPACE")),
+		client:    &http.Client{},
+	}
+	if p.address == "" {
+		return nil, errors.New("vault address is not set (secrets.vaultaddress or VAULT_ADDR)")
+	}
+	if p.token == "" {
+		return nil, errors.New("vault token is not set (secrets.vaulttoken or VAULT_TOKEN)")
+	}
+	p.address = strings.TrimRight(p.address, "/")
+	return p, nil
+}
+
+// Fetch reads the secret at path. KV v2 paths may be written with or without
+// the "data/" segment (e.g., secret/db or secret/data/db), like `vault kv get`.
+func (p *vaultProvider) Fetch(ctx context.Context, path, key string) (string, error) {
+	path = strings.Trim(path, "/")
+
+	data, err := p.read(ctx, path)
+	if errors.Is(err, errVaultNotFound) {
+		if mount, rest, ok := strings.Cut(path, "/"); ok && !strings.HasPrefix(rest, "data/") {
+			data, err = p.read(ctx, mount+"/data/"+rest)
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if key == "" {
+		encoded, err := json.Marshal(data)
+		if err != nil {
+			return "", err
+		}
+		return string(encoded), nil
+	}
+	return secretField(data, key)
+}
+
+// read returns the key/value data stored at path
+func (p *vaultProvider) read(ctx context.Context, path string) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.address+"/v1/"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vault request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errVaultNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("vault returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var payload struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("invalid vault response: %w", err)
+	}
+
+	// KV v2 nests the secret under data.data, alongside data.metadata
+	if nested, ok := payload.Data["data"].(map[string]interface{}); ok {
+		if _, versioned := payload.Data["metadata"]; versioned {
+			return nested, nil
+		}
+	}
+	return payload.Data, nil
+}
+
+// firstNonEmpty returns the first non-empty value
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
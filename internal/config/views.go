@@ -0,0 +1,18 @@
+package config
+
+// ViewsConfig holds configuration for server-rendered HTML views
+// (Connection.Render)
+type ViewsConfig struct {
+	Directory string // Pages, with layouts in layouts/ and partials in partials/; skipped when missing
+	Layout    string // Layout pages render in, relative to Directory; skipped when the file doesn't exist
+	Reload    bool   // Re-read the templates on every render (for development)
+}
+
+// DefaultViewsConfig returns default views configuration
+func DefaultViewsConfig() ViewsConfig {
+	return ViewsConfig{
+		Directory: "views",
+		Layout:    "layouts/main.html",
+		Reload:    false,
+	}
+}
@@ -0,0 +1,230 @@
+// Package fixtures loads seed data from YAML or JSON files into an API's
+// database and cache, for repeatable test and demo data:
+//
+//	# fixtures/01_users.yaml
+//	tables:
+//	  users:
+//	    - {id: 1, name: Mario, email: mario@example.com}
+//	cache:
+//	  "feature:chat": enabled
+//	  "limits:free": {requests: 100}
+//
+// Fixtures are loaded by "actionhero db seed", when the API starts with
+// database.seedonstart, or in tests with actiontest.WithFixtures.
+package fixtures
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+	"go.yaml.in/yaml/v3"
+)
+
+// Seeder inserts fixture rows into a database table. The framework has no
+// database driver of its own, so apps provide a Seeder (e.g., one wrapping
+// their *sql.DB) as a resource, from the initializer that opens the database:
+//
+//	api.Provide[fixtures.Seeder](a, fixtures.SeederFunc(func(ctx context.Context, table string, rows []map[string]interface{}) error {
+//	    ... // INSERT each row into table
+//	}))
+type Seeder interface {
+	Seed(ctx context.Context, table string, rows []map[string]interface{}) error
+}
+
+// SeederFunc adapts a function to a Seeder
+type SeederFunc func(ctx context.Context, table string, rows []map[string]interface{}) error
+
+// Seed calls f
+func (f SeederFunc) Seed(ctx context.Context, table string, rows []map[string]interface{}) error {
+	return f(ctx, table, rows)
+}
+
+// Fixtures is seed data, merged from one or more files
+type Fixtures struct {
+	// Tables holds the rows to insert, by table
+	Tables map[string][]map[string]interface{} `json:"tables" yaml:"tables"`
+
+	// Cache holds the values to store in the API's cache, by key. Strings
+	// are stored as is and other values as JSON.
+	Cache map[string]interface{} `json:"cache" yaml:"cache"`
+
+	// tables lists the tables in the order Load found them: by file, then by
+	// name within a file (so tables other rows refer to go in earlier files)
+	tables []string
+}
+
+// order returns the tables in the order they are seeded: as loaded, then
+// any added in code, by name
+func (f *Fixtures) order() []string {
+	ordered := make([]string, 0, len(f.Tables))
+	seen := make(map[string]bool, len(f.Tables))
+	for _, table := range f.tables {
+		if _, ok := f.Tables[table]; ok && !seen[table] {
+			ordered = append(ordered, table)
+			seen[table] = true
+		}
+	}
+	added := make([]string, 0)
+	for table := range f.Tables {
+		if !seen[table] {
+			added = append(added, table)
+		}
+	}
+	sort.Strings(added)
+	return append(ordered, added...)
+}
+
+// Load reads fixtures from a YAML (.yaml, .yml) or JSON (.json) file, or from
+// every such file in a directory, in name order. Rows for the same table are
+// appended; later cache values replace earlier ones.
+func Load(path string) (*Fixtures, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures: %w", err)
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixtures: %w", err)
+		}
+		files = files[:0]
+		for _, entry := range entries {
+			if !entry.IsDir() && isFixtureFile(entry.Name()) {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+		sort.Strings(files)
+	}
+
+	fixtures := &Fixtures{
+		Tables: make(map[string][]map[string]interface{}),
+		Cache:  make(map[string]interface{}),
+	}
+	for _, file := range files {
+		loaded, err := loadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		fixtures.merge(loaded)
+	}
+	return fixtures, nil
+}
+
+// isFixtureFile reports whether a file name has a fixture extension
+func isFixtureFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// loadFile decodes one fixtures file, rejecting unknown top-level keys
+func loadFile(file string) (*Fixtures, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures: %w", err)
+	}
+
+	var fixtures Fixtures
+	if strings.ToLower(filepath.Ext(file)) == ".json" {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&fixtures)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err = decoder.Decode(&fixtures); errors.Is(err, io.EOF) {
+			err = nil // An empty file
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid fixtures file %s: %w", file, err)
+	}
+	return &fixtures, nil
+}
+
+// merge adds other's rows and cache values
+func (f *Fixtures) merge(other *Fixtures) {
+	names := make([]string, 0, len(other.Tables))
+	for table := range other.Tables {
+		names = append(names, table)
+	}
+	sort.Strings(names)
+
+	for _, table := range names {
+		if _, ok := f.Tables[table]; !ok {
+			f.tables = append(f.tables, table)
+		}
+		f.Tables[table] = append(f.Tables[table], other.Tables[table]...)
+	}
+	for key, value := range other.Cache {
+		f.Cache[key] = value
+	}
+}
+
+// Apply stores the cache values in the API's cache, then inserts the rows
+// with the API's Seeder, table by table. Tables need a Seeder to be provided.
+func Apply(ctx context.Context, a *api.API, fixtures *Fixtures) error {
+	tables := fixtures.order()
+	rows := 0
+	for _, table := range tables {
+		rows += len(fixtures.Tables[table])
+	}
+
+	for key, value := range fixtures.Cache {
+		data, err := cacheValue(value)
+		if err != nil {
+			return fmt.Errorf("failed to seed cache key %s: %w", key, err)
+		}
+		if err := a.Cache.Set(ctx, key, data, 0); err != nil {
+			return fmt.Errorf("failed to seed cache key %s: %w", key, err)
+		}
+	}
+
+	if rows > 0 {
+		seeder, ok := api.Lookup[Seeder](a)
+		if !ok {
+			return fmt.Errorf("fixtures have %d rows to insert but no fixtures.Seeder is provided", rows)
+		}
+		for _, table := range tables {
+			if len(fixtures.Tables[table]) == 0 {
+				continue
+			}
+			if err := seeder.Seed(ctx, table, fixtures.Tables[table]); err != nil {
+				return fmt.Errorf("failed to seed table %s: %w", table, err)
+			}
+		}
+	}
+
+	a.Logger.Infof("Seeded %d rows into %d tables and %d cache entries", rows, len(tables), len(fixtures.Cache))
+	return nil
+}
+
+// cacheValue encodes a fixture value for the cache
+func cacheValue(value interface{}) ([]byte, error) {
+	if s, ok := value.(string); ok {
+		return []byte(s), nil
+	}
+	return json.Marshal(value)
+}
+
+// Seed loads the fixtures at path and applies them to the API
+func Seed(ctx context.Context, a *api.API, path string) error {
+	fixtures, err := Load(path)
+	if err != nil {
+		return err
+	}
+	return Apply(ctx, a, fixtures)
+}
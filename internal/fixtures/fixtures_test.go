@@ -0,0 +1,142 @@
+package fixtures
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// writeFixtures writes fixture files to a new directory
+func writeFixtures(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+// recordingSeeder records the tables it seeds, in order
+type recordingSeeder struct {
+	tables []string
+	rows   map[string][]map[string]interface{}
+}
+
+func (s *recordingSeeder) Seed(_ context.Context, table string, rows []map[string]interface{}) error {
+	s.tables = append(s.tables, table)
+	s.rows[table] = append(s.rows[table], rows...)
+	return nil
+}
+
+func newTestAPI(cfg *config.Config) *api.API {
+	return api.New(cfg, util.NewLogger(config.LoggerConfig{Level: "fatal"}))
+}
+
+func TestLoad(t *testing.T) {
+	dir := writeFixtures(t, map[string]string{
+		"01_users.yaml": "tables:\n  users:\n    - {id: 1, name: Mario}\ncache:\n  greeting: hello\n",
+		"02_posts.json": `{"tables": {"posts": [{"id": 1, "userId": 1}], "users": [{"id": 2, "name": "Luigi"}]}, "cache": {"greeting": "hi"}}`,
+		"empty.yml":     "",
+		"README.md":     "not fixtures",
+	})
+
+	fixtures, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+	if users := fixtures.Tables["users"]; len(users) != 2 || users[0]["name"] != "Mario" || users[1]["name"] != "Luigi" {
+		t.Errorf("Expected users from both files in order, got %v", users)
+	}
+	if order := fixtures.order(); !reflect.DeepEqual(order, []string{"users", "posts"}) {
+		t.Errorf("Expected tables in file order, got %v", order)
+	}
+	if fixtures.Cache["greeting"] != "hi" {
+		t.Errorf("Expected the later cache value, got %v", fixtures.Cache["greeting"])
+	}
+
+	single, err := Load(filepath.Join(dir, "01_users.yaml"))
+	if err != nil || len(single.Tables["users"]) != 1 {
+		t.Errorf("Expected to load a single file, got %v, %v", single, err)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "missing")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected a not-exist error, got %v", err)
+	}
+
+	dir := writeFixtures(t, map[string]string{"bad.yaml": "table:\n  users: []\n"})
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "bad.yaml") {
+		t.Errorf("Expected an error naming the file with an unknown key, got %v", err)
+	}
+}
+
+func TestApply(t *testing.T) {
+	a := newTestAPI(&config.Config{})
+	seeder := &recordingSeeder{rows: map[string][]map[string]interface{}{}}
+	api.Provide[Seeder](a, seeder)
+
+	dir := writeFixtures(t, map[string]string{
+		"01.yaml": "tables:\n  users:\n    - {id: 1}\ncache:\n  greeting: hello\n  limits: {requests: 100}\n",
+		"02.yaml": "tables:\n  posts:\n    - {id: 1, userId: 1}\n",
+	})
+	if err := Seed(context.Background(), a, dir); err != nil {
+		t.Fatalf("Failed to seed: %v", err)
+	}
+
+	if !reflect.DeepEqual(seeder.tables, []string{"users", "posts"}) {
+		t.Errorf("Expected users then posts to be seeded, got %v", seeder.tables)
+	}
+	for key, want := range map[string]string{"greeting": "hello", "limits": `{"requests":100}`} {
+		if got, ok, _ := a.Cache.Get(context.Background(), key); !ok || string(got) != want {
+			t.Errorf("Expected cache %s = %s, got %s", key, want, got)
+		}
+	}
+}
+
+func TestApply_NoSeeder(t *testing.T) {
+	a := newTestAPI(&config.Config{})
+
+	err := Apply(context.Background(), a, &Fixtures{Tables: map[string][]map[string]interface{}{"users": {{"id": 1}}}})
+	if err == nil || !strings.Contains(err.Error(), "no fixtures.Seeder") {
+		t.Errorf("Expected an error without a seeder, got %v", err)
+	}
+
+	// Cache-only fixtures don't need one
+	if err := Apply(context.Background(), a, &Fixtures{Cache: map[string]interface{}{"greeting": "hello"}}); err != nil {
+		t.Errorf("Expected cache fixtures to load without a seeder, got %v", err)
+	}
+}
+
+func TestInitializer_SeedOnStart(t *testing.T) {
+	dir := writeFixtures(t, map[string]string{"cache.yaml": "cache:\n  greeting: hello\n"})
+	a := newTestAPI(&config.Config{Database: config.DatabaseConfig{Fixtures: dir}})
+	initializer := NewInitializer()
+
+	if err := initializer.Start(a); err != nil {
+		t.Fatalf("Failed to start: %v", err)
+	}
+	if _, ok, _ := a.Cache.Get(context.Background(), "greeting"); ok {
+		t.Error("Expected no seeding without database.seedonstart")
+	}
+
+	a.Config.Database.SeedOnStart = true
+	if err := initializer.Start(a); err != nil {
+		t.Fatalf("Failed to start: %v", err)
+	}
+	if got, ok, _ := a.Cache.Get(context.Background(), "greeting"); !ok || string(got) != "hello" {
+		t.Errorf("Expected the fixtures to be seeded on start, got %s", got)
+	}
+}
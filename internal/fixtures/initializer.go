@@ -0,0 +1,45 @@
+package fixtures
+
+import (
+	"context"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+)
+
+// Initializer seeds the database.fixtures when the API starts, if
+// database.seedonstart is set. It starts after the other initializers, so
+// the app's database (and its Seeder) is ready.
+type Initializer struct{}
+
+// NewInitializer creates the fixtures initializer
+func NewInitializer() *Initializer {
+	return &Initializer{}
+}
+
+// Name returns the initializer name
+func (i *Initializer) Name() string {
+	return "fixtures"
+}
+
+// Priority returns the initialization priority
+func (i *Initializer) Priority() int {
+	return 1000
+}
+
+// Initialize does nothing; fixtures are seeded on start
+func (i *Initializer) Initialize(_ *api.API) error {
+	return nil
+}
+
+// Start seeds the fixtures when database.seedonstart is set
+func (i *Initializer) Start(a *api.API) error {
+	if !a.Config.Database.SeedOnStart {
+		return nil
+	}
+	return Seed(context.Background(), a, a.Config.Database.Fixtures)
+}
+
+// Stop does nothing
+func (i *Initializer) Stop(_ *api.API) error {
+	return nil
+}
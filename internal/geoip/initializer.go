@@ -0,0 +1,53 @@
+package geoip
+
+import (
+	"fmt"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+)
+
+// Initializer sets the API's GeoIP when geoip.enabled is set
+type Initializer struct{}
+
+// NewInitializer creates the GeoIP initializer
+func NewInitializer() *Initializer {
+	return &Initializer{}
+}
+
+// Name returns the initializer name
+func (i *Initializer) Name() string {
+	return "geoip"
+}
+
+// Priority returns the initialization priority
+func (i *Initializer) Priority() int {
+	return 100
+}
+
+// Initialize opens the database configured by geoip.database
+func (i *Initializer) Initialize(a *api.API) error {
+	cfg := a.Config.GeoIP
+	if !cfg.Enabled {
+		return nil
+	}
+
+	reader, err := Open(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to open geoip.database: %w", err)
+	}
+	a.GeoIP = reader
+
+	metadata := reader.Metadata()
+	a.Logger.Infof("GeoIP enabled: %s (%s, IPv%d)", cfg.Database, metadata.DatabaseType, metadata.IPVersion)
+	return nil
+}
+
+// Start does nothing
+func (i *Initializer) Start(_ *api.API) error {
+	return nil
+}
+
+// Stop does nothing
+func (i *Initializer) Stop(_ *api.API) error {
+	return nil
+}
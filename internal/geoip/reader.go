@@ -0,0 +1,397 @@
+// Package geoip locates client IPs with a MaxMind DB file (e.g.,
+// GeoLite2-City.mmdb), for the API's GeoIP
+package geoip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net/netip"
+	"os"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+)
+
+// metadataMarker starts the metadata at the end of a MaxMind DB file
+var metadataMarker = []byte("\xab\xcd\xefMaxMind.com")
+
+// dataSectionSeparator is the size of the zeros between the search tree and
+// the data section
+const dataSectionSeparator = 16
+
+// Data types of the MaxMind DB format
+const (
+	typeExtended  = 0
+	typePointer   = 1
+	typeString    = 2
+	typeDouble    = 3
+	typeBytes     = 4
+	typeUint16    = 5
+	typeUint32    = 6
+	typeMap       = 7
+	typeInt32     = 8
+	typeUint64    = 9
+	typeUint128   = 10
+	typeArray     = 11
+	typeContainer = 12
+	typeEnd       = 13
+	typeBool      = 14
+	typeFloat     = 15
+)
+
+// Metadata describes a MaxMind DB file
+type Metadata struct {
+	DatabaseType string
+	IPVersion    int
+	NodeCount    uint
+	RecordSize   uint
+	BuildEpoch   uint64
+}
+
+// Reader looks up IPs in a MaxMind DB file held in memory. It is safe for
+// concurrent use.
+type Reader struct {
+	buf       []byte
+	tree      []byte
+	data      []byte
+	metadata  Metadata
+	ipv4Start uint // Node of ::/96 in IPv6 trees, where IPv4 lookups start
+}
+
+// Open reads the MaxMind DB file at path
+func Open(path string) (*Reader, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return New(buf)
+}
+
+// New reads a MaxMind DB file's contents
+func New(buf []byte) (*Reader, error) {
+	start := bytes.LastIndex(buf, metadataMarker)
+	if start < 0 {
+		return nil, errors.New("not a MaxMind DB file: metadata not found")
+	}
+	metadataStart := start + len(metadataMarker)
+	raw, _, err := (&decoder{buf: buf[metadataStart:]}).decode(0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MaxMind DB metadata: %w", err)
+	}
+	fields, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid MaxMind DB metadata: not a map")
+	}
+
+	metadata := Metadata{
+		DatabaseType: stringValue(fields["database_type"]),
+		IPVersion:    int(uintValue(fields["ip_version"])),
+		NodeCount:    uint(uintValue(fields["node_count"])),
+		RecordSize:   uint(uintValue(fields["record_size"])),
+		BuildEpoch:   uintValue(fields["build_epoch"]),
+	}
+	switch metadata.RecordSize {
+	case 24, 28, 32:
+	default:
+		return nil, fmt.Errorf("unsupported MaxMind DB record size %d", metadata.RecordSize)
+	}
+	if metadata.IPVersion != 4 && metadata.IPVersion != 6 {
+		return nil, fmt.Errorf("unsupported MaxMind DB IP version %d", metadata.IPVersion)
+	}
+
+	treeSize := metadata.NodeCount * metadata.RecordSize / 4
+	if treeSize+dataSectionSeparator > uint(start) {
+		return nil, errors.New("invalid MaxMind DB file: search tree is larger than the file")
+	}
+	r := &Reader{
+		buf:      buf,
+		tree:     buf[:treeSize],
+		data:     buf[treeSize+dataSectionSeparator : start],
+		metadata: metadata,
+	}
+
+	if metadata.IPVersion == 6 {
+		node := uint(0)
+		for i := 0; i < 96 && node < metadata.NodeCount; i++ {
+			node = r.record(node, 0)
+		}
+		r.ipv4Start = node
+	}
+	return r, nil
+}
+
+// Metadata returns the description of the file
+func (r *Reader) Metadata() Metadata {
+	return r.metadata
+}
+
+// Lookup returns the record of ip (nil when the file has none)
+func (r *Reader) Lookup(ip netip.Addr) (interface{}, error) {
+	ip = ip.Unmap()
+	node := uint(0)
+	bits := 128
+	var address [16]byte
+	switch {
+	case ip.Is4():
+		if r.metadata.IPVersion == 6 {
+			node = r.ipv4Start
+		}
+		bits = 32
+		v4 := ip.As4()
+		copy(address[:], v4[:])
+	case ip.Is6():
+		if r.metadata.IPVersion == 4 {
+			return nil, nil // IPv4-only file
+		}
+		address = ip.As16()
+	default:
+		return nil, errors.New("invalid IP address")
+	}
+
+	for i := 0; i < bits && node < r.metadata.NodeCount; i++ {
+		bit := (address[i/8] >> (7 - uint(i%8))) & 1
+		node = r.record(node, uint(bit))
+	}
+
+	switch {
+	case node == r.metadata.NodeCount:
+		return nil, nil // Not found
+	case node < r.metadata.NodeCount:
+		return nil, errors.New("invalid MaxMind DB file: search tree is deeper than the address")
+	}
+	offset := node - r.metadata.NodeCount - dataSectionSeparator
+	if offset >= uint(len(r.data)) {
+		return nil, errors.New("invalid MaxMind DB file: record points past the data section")
+	}
+	value, _, err := (&decoder{buf: r.data}).decode(offset)
+	return value, err
+}
+
+// Locate returns where ip is, and whether it was found. It implements
+// api.GeoLocator for City and Country databases.
+func (r *Reader) Locate(ip string) (api.GeoLocation, bool) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return api.GeoLocation{}, false
+	}
+	record, err := r.Lookup(addr)
+	if err != nil || record == nil {
+		return api.GeoLocation{}, false
+	}
+	fields, _ := record.(map[string]interface{})
+
+	var location api.GeoLocation
+	if country, ok := fields["country"].(map[string]interface{}); ok {
+		location.Country = stringValue(country["iso_code"])
+		location.CountryName = englishName(country)
+	}
+	if subdivisions, ok := fields["subdivisions"].([]interface{}); ok && len(subdivisions) > 0 {
+		if region, ok := subdivisions[0].(map[string]interface{}); ok {
+			location.Region = stringValue(region["iso_code"])
+			location.RegionName = englishName(region)
+		}
+	}
+	if city, ok := fields["city"].(map[string]interface{}); ok {
+		location.City = englishName(city)
+	}
+	return location, !location.IsZero()
+}
+
+// record returns the left (0) or right (1) record of a search tree node
+func (r *Reader) record(node, side uint) uint {
+	switch r.metadata.RecordSize {
+	case 24:
+		b := r.tree[node*6+side*3:]
+		return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+	case 28:
+		b := r.tree[node*7:]
+		if side == 0 {
+			return uint(b[3]&0xf0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+		}
+		return uint(b[3]&0x0f)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6])
+	default:
+		return uint(binary.BigEndian.Uint32(r.tree[node*8+side*4:]))
+	}
+}
+
+// decoder decodes values of the MaxMind DB data section
+type decoder struct {
+	buf []byte
+}
+
+// decode returns the value at offset, and the offset after it
+func (d *decoder) decode(offset uint) (interface{}, uint, error) {
+	typ, size, offset, err := d.control(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if typ == typePointer {
+		target, next, err := d.pointer(size, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, _, err := d.decode(target)
+		return value, next, err
+	}
+
+	switch typ {
+	case typeMap:
+		values := make(map[string]interface{}, size)
+		for i := uint(0); i < size; i++ {
+			var key, value interface{}
+			if key, offset, err = d.decode(offset); err != nil {
+				return nil, 0, err
+			}
+			if value, offset, err = d.decode(offset); err != nil {
+				return nil, 0, err
+			}
+			name, ok := key.(string)
+			if !ok {
+				return nil, 0, errors.New("map key is not a string")
+			}
+			values[name] = value
+		}
+		return values, offset, nil
+	case typeArray:
+		values := make([]interface{}, 0, size)
+		for i := uint(0); i < size; i++ {
+			var value interface{}
+			if value, offset, err = d.decode(offset); err != nil {
+				return nil, 0, err
+			}
+			values = append(values, value)
+		}
+		return values, offset, nil
+	case typeBool:
+		return size != 0, offset, nil
+	case typeContainer, typeEnd:
+		return nil, offset, nil
+	}
+
+	end := offset + size
+	if end > uint(len(d.buf)) || end < offset {
+		return nil, 0, errors.New("value runs past the end of the data")
+	}
+	b := d.buf[offset:end]
+	switch typ {
+	case typeString:
+		return string(b), end, nil
+	case typeBytes:
+		return append([]byte(nil), b...), end, nil
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, fmt.Errorf("invalid double size %d", size)
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), end, nil
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, fmt.Errorf("invalid float size %d", size)
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), end, nil
+	case typeUint16, typeUint32, typeUint64:
+		if size > 8 {
+			return nil, 0, fmt.Errorf("invalid unsigned integer size %d", size)
+		}
+		return uintBytes(b), end, nil
+	case typeInt32:
+		if size > 4 {
+			return nil, 0, fmt.Errorf("invalid int32 size %d", size)
+		}
+		return int64(int32(uintBytes(b))), end, nil
+	case typeUint128:
+		return new(big.Int).SetBytes(b), end, nil
+	}
+	return nil, 0, fmt.Errorf("unknown data type %d", typ)
+}
+
+// control reads the control byte(s) at offset: the value's type, its size
+// (for pointers, the size bits), and where its payload starts
+func (d *decoder) control(offset uint) (typ int, size uint, next uint, err error) {
+	if offset >= uint(len(d.buf)) {
+		return 0, 0, 0, errors.New("value runs past the end of the data")
+	}
+	ctrl := d.buf[offset]
+	offset++
+	typ = int(ctrl >> 5)
+	if typ == typeExtended {
+		if offset >= uint(len(d.buf)) {
+			return 0, 0, 0, errors.New("value runs past the end of the data")
+		}
+		typ = 7 + int(d.buf[offset])
+		offset++
+	}
+	if typ == typePointer {
+		return typ, uint(ctrl & 0x1f), offset, nil
+	}
+
+	size = uint(ctrl & 0x1f)
+	if size >= 29 {
+		extra := size - 28
+		if offset+extra > uint(len(d.buf)) {
+			return 0, 0, 0, errors.New("value runs past the end of the data")
+		}
+		n := uintBytes(d.buf[offset : offset+extra])
+		offset += extra
+		switch extra {
+		case 1:
+			size = 29 + uint(n)
+		case 2:
+			size = 285 + uint(n)
+		default:
+			size = 65821 + uint(n)
+		}
+	}
+	return typ, size, offset, nil
+}
+
+// pointer reads a pointer's target, from its size bits and the bytes at offset
+func (d *decoder) pointer(bits uint, offset uint) (target uint, next uint, err error) {
+	length := (bits >> 3 & 0x3) + 1
+	if offset+length > uint(len(d.buf)) {
+		return 0, 0, errors.New("pointer runs past the end of the data")
+	}
+	n := uint(uintBytes(d.buf[offset : offset+length]))
+	value := bits & 0x7
+	switch length {
+	case 1:
+		target = value<<8 | n
+	case 2:
+		target = (value<<16 | n) + 2048
+	case 3:
+		target = (value<<24 | n) + 526336
+	default:
+		target = n
+	}
+	return target, offset + length, nil
+}
+
+// uintBytes decodes a big-endian unsigned integer of up to 8 bytes
+func uintBytes(b []byte) uint64 {
+	var n uint64
+	for _, c := range b {
+		n = n<<8 | uint64(c)
+	}
+	return n
+}
+
+// uintValue returns a decoded unsigned integer, or 0
+func uintValue(value interface{}) uint64 {
+	n, _ := value.(uint64)
+	return n
+}
+
+// stringValue returns a decoded string, or ""
+func stringValue(value interface{}) string {
+	s, _ := value.(string)
+	return s
+}
+
+// englishName returns the English name of a country, subdivision, or city
+func englishName(record map[string]interface{}) string {
+	names, _ := record["names"].(map[string]interface{})
+	return stringValue(names["en"])
+}
@@ -0,0 +1,246 @@
+package geoip
+
+import (
+	"encoding/binary"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// encode encodes a value in the MaxMind DB data format
+func encode(value interface{}) []byte {
+	switch v := value.(type) {
+	case string:
+		return append(control(typeString, len(v)), v...)
+	case int:
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], uint32(v))
+		return append(control(typeUint32, 4), b[:]...)
+	case bool:
+		if v {
+			return control(typeBool, 1)
+		}
+		return control(typeBool, 0)
+	case []interface{}:
+		out := control(typeArray, len(v))
+		for _, item := range v {
+			out = append(out, encode(item)...)
+		}
+		return out
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		out := control(typeMap, len(v))
+		for _, key := range keys {
+			out = append(out, encode(key)...)
+			out = append(out, encode(v[key])...)
+		}
+		return out
+	case []byte: // Already encoded (e.g., a pointer)
+		return v
+	}
+	panic("unsupported value")
+}
+
+// control encodes a control byte for typ and size
+func control(typ, size int) []byte {
+	var sizeBits byte
+	var extra []byte
+	switch {
+	case size < 29:
+		sizeBits = byte(size)
+	case size < 285:
+		sizeBits, extra = 29, []byte{byte(size - 29)}
+	default:
+		sizeBits, extra = 30, []byte{byte((size - 285) >> 8), byte(size - 285)}
+	}
+	out := []byte{byte(typ)<<5 | sizeBits}
+	if typ > 7 {
+		out = []byte{sizeBits, byte(typ - 7)}
+	}
+	return append(out, extra...)
+}
+
+// pointer encodes a pointer to offset in the data section (below 2048)
+func pointer(offset int) []byte {
+	return []byte{typePointer<<5 | byte(offset>>8), byte(offset)}
+}
+
+type testNetwork struct {
+	prefix string
+	record interface{}
+}
+
+// buildDatabase writes a MaxMind DB file of networks, with an IPv4 tree or
+// an IPv6 tree with IPv4 at ::/96
+func buildDatabase(t *testing.T, ipVersion, recordSize int, networks []testNetwork) []byte {
+	t.Helper()
+	const empty, child, data = 0, 1, 2
+	type record struct{ kind, value int }
+	nodes := [][2]record{{}}
+
+	var section []byte
+	for _, network := range networks {
+		prefix := netip.MustParsePrefix(network.prefix)
+		offset := len(section)
+		section = append(section, encode(network.record)...)
+
+		addr, bits := prefix.Addr().AsSlice(), prefix.Bits()
+		if ipVersion == 6 && prefix.Addr().Is4() {
+			addr, bits = append(make([]byte, 12), addr...), bits+96
+		}
+		node := 0
+		for i := 0; i < bits; i++ {
+			side := (addr[i/8] >> (7 - uint(i%8))) & 1
+			if i == bits-1 {
+				nodes[node][side] = record{data, offset}
+				break
+			}
+			if nodes[node][side].kind != child {
+				nodes = append(nodes, [2]record{})
+				nodes[node][side] = record{child, len(nodes) - 1}
+			}
+			node = nodes[node][side].value
+		}
+	}
+
+	nodeCount := len(nodes)
+	var tree []byte
+	for _, node := range nodes {
+		var values [2]uint32
+		for side, rec := range node {
+			switch rec.kind {
+			case empty:
+				values[side] = uint32(nodeCount)
+			case child:
+				values[side] = uint32(rec.value)
+			case data:
+				values[side] = uint32(nodeCount + dataSectionSeparator + rec.value)
+			}
+		}
+		switch recordSize {
+		case 24:
+			tree = append(tree, byte(values[0]>>16), byte(values[0]>>8), byte(values[0]),
+				byte(values[1]>>16), byte(values[1]>>8), byte(values[1]))
+		case 28:
+			tree = append(tree, byte(values[0]>>16), byte(values[0]>>8), byte(values[0]),
+				byte(values[0]>>20)&0xf0|byte(values[1]>>24)&0x0f,
+				byte(values[1]>>16), byte(values[1]>>8), byte(values[1]))
+		case 32:
+			tree = binary.BigEndian.AppendUint32(tree, values[0])
+			tree = binary.BigEndian.AppendUint32(tree, values[1])
+		}
+	}
+
+	file := append(tree, make([]byte, dataSectionSeparator)...)
+	file = append(file, section...)
+	file = append(file, metadataMarker...)
+	return append(file, encode(map[string]interface{}{
+		"binary_format_major_version": 2,
+		"database_type":               "Test-City",
+		"ip_version":                  ipVersion,
+		"node_count":                  nodeCount,
+		"record_size":                 recordSize,
+	})...)
+}
+
+func names(en string) map[string]interface{} {
+	return map[string]interface{}{"en": en, "fr": en + " (fr)"}
+}
+
+var testNetworks = []testNetwork{
+	{"81.2.69.0/24", map[string]interface{}{
+		"city":         map[string]interface{}{"names": names("London")},
+		"country":      map[string]interface{}{"iso_code": "GB", "names": names("United Kingdom")},
+		"subdivisions": []interface{}{map[string]interface{}{"iso_code": "ENG", "names": names("England")}},
+		"is_anycast":   false,
+	}},
+	// Points at the first record, as MaxMind DB files share repeated data
+	{"81.2.70.0/23", pointer(0)},
+	{"2.125.160.0/20", map[string]interface{}{
+		"country": map[string]interface{}{"iso_code": "GB", "names": names("United Kingdom")},
+	}},
+}
+
+func TestReader_Locate(t *testing.T) {
+	tests := []struct {
+		ipVersion, recordSize int
+	}{{4, 24}, {4, 28}, {6, 28}, {6, 32}}
+	for _, tt := range tests {
+		reader, err := New(buildDatabase(t, tt.ipVersion, tt.recordSize, testNetworks))
+		if err != nil {
+			t.Fatalf("IPv%d/%d: New failed: %v", tt.ipVersion, tt.recordSize, err)
+		}
+
+		london := api.GeoLocation{Country: "GB", CountryName: "United Kingdom", Region: "ENG", RegionName: "England", City: "London"}
+		for ip, want := range map[string]api.GeoLocation{
+			"81.2.69.142":        london,
+			"81.2.71.1":          london,
+			"::ffff:81.2.69.142": london,
+			"2.125.175.1":        {Country: "GB", CountryName: "United Kingdom"},
+		} {
+			got, ok := reader.Locate(ip)
+			if !ok || got != want {
+				t.Errorf("IPv%d/%d: Expected %s to be in %+v, got %+v (found: %v)", tt.ipVersion, tt.recordSize, ip, want, got, ok)
+			}
+		}
+		for _, ip := range []string{"81.2.72.1", "10.0.0.1", "2001:db8::1", "not an ip"} {
+			if got, ok := reader.Locate(ip); ok {
+				t.Errorf("IPv%d/%d: Expected %s not to be found, got %+v", tt.ipVersion, tt.recordSize, ip, got)
+			}
+		}
+	}
+}
+
+func TestReader_Metadata(t *testing.T) {
+	reader, err := New(buildDatabase(t, 6, 24, testNetworks))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	metadata := reader.Metadata()
+	if metadata.DatabaseType != "Test-City" || metadata.IPVersion != 6 || metadata.RecordSize != 24 {
+		t.Errorf("Unexpected metadata: %+v", metadata)
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	if _, err := New([]byte("not a database")); err == nil {
+		t.Error("Expected an error for a file without metadata")
+	}
+	file := buildDatabase(t, 4, 24, testNetworks)
+	if _, err := New(file[len(file)-60:]); err == nil {
+		t.Error("Expected an error for a truncated file")
+	}
+}
+
+func TestInitializer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.mmdb")
+	if err := os.WriteFile(path, buildDatabase(t, 6, 28, testNetworks), 0o600); err != nil {
+		t.Fatalf("Failed to write the database: %v", err)
+	}
+
+	cfg := &config.Config{GeoIP: config.GeoIPConfig{Enabled: true, Database: path}}
+	apiInstance := api.New(cfg, util.NewLogger(config.LoggerConfig{Level: "error"}))
+	if err := NewInitializer().Initialize(apiInstance); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	info := apiInstance.LocateClient(api.ClientInfo{RemoteIP: "81.2.69.142"})
+	if info.Location.Country != "GB" || info.Location.Region != "ENG" {
+		t.Errorf("Expected the client to be located in GB/ENG, got %+v", info.Location)
+	}
+
+	cfg.GeoIP.Database = filepath.Join(t.TempDir(), "missing.mmdb")
+	if err := NewInitializer().Initialize(apiInstance); err == nil {
+		t.Error("Expected an error for a missing database")
+	}
+}
@@ -0,0 +1,236 @@
+// Package httpclient provides the outbound HTTP client actions use to call
+// other services, with retries, per-host circuit breaking (see package
+// breaker), request ID propagation, and metrics
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/breaker"
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// requestIDHeader carries the request ID of the action making the request
+const requestIDHeader = "X-Request-ID"
+
+// ErrCircuitOpen is wrapped by the errors of requests to a host whose circuit
+// breaker is open, after too many failed requests in a row
+var ErrCircuitOpen = breaker.ErrOpen
+
+// Recorder records the latency and outcome of requests (e.g., *api.Metrics)
+type Recorder interface {
+	Record(name string, duration time.Duration, err error)
+}
+
+// Transport is an http.RoundTripper that adds the client's resilience and
+// observability to another one
+type Transport struct {
+	base     http.RoundTripper
+	cfg      config.HTTPClientConfig
+	metrics  Recorder
+	logger   *util.Logger
+	breakers *breaker.Registry
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// New creates an HTTP client with the configured timeout, whose requests go
+// through a Transport over http.DefaultTransport. metrics, breakers, and
+// logger may be nil.
+func New(cfg config.HTTPClientConfig, metrics Recorder, breakers *breaker.Registry, logger *util.Logger) *http.Client {
+	return &http.Client{
+		Timeout:   cfg.Timeout,
+		Transport: NewTransport(http.DefaultTransport, cfg, metrics, breakers, logger),
+	}
+}
+
+// NewTransport wraps base (http.DefaultTransport when nil) with retries,
+// circuit breaking, request ID propagation, and metrics. The breaker of each
+// host is kept in breakers as "http:<host>" (in a registry of its own when
+// nil).
+func NewTransport(base http.RoundTripper, cfg config.HTTPClientConfig, metrics Recorder, breakers *breaker.Registry, logger *util.Logger) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if breakers == nil {
+		breakers = breaker.NewRegistry(breaker.Settings{}, logger)
+	}
+	return &Transport{
+		base:     base,
+		cfg:      cfg,
+		metrics:  metrics,
+		logger:   logger,
+		breakers: breakers,
+		now:      time.Now,
+		sleep:    sleepContext,
+	}
+}
+
+// RoundTrip sends the request, retrying it when it is safe to
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	req = req.Clone(ctx) // RoundTrippers must not modify the caller's request
+	if req.Header.Get(requestIDHeader) == "" {
+		if requestID := util.RequestIDFromContext(ctx); requestID != "" {
+			req.Header.Set(requestIDHeader, requestID)
+		}
+	}
+	if t.cfg.UserAgent != "" && req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", t.cfg.UserAgent)
+	}
+
+	started := t.now()
+	resp, err := t.send(ctx, req)
+	if t.metrics != nil {
+		t.metrics.Record(req.Method+" "+req.URL.Host, t.now().Sub(started), outcome(resp, err))
+	}
+	return resp, err
+}
+
+// send sends the request, and retries it while it fails in a retryable way
+func (t *Transport) send(ctx context.Context, req *http.Request) (*http.Response, error) {
+	retryable := isRetryable(req)
+	for attempt := 0; ; attempt++ {
+		resp, err := t.attempt(req)
+		if !retryable || attempt >= t.cfg.Retries || !shouldRetry(ctx, resp, err) {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp)
+		if resp != nil {
+			// Drained so the connection can be reused
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			_ = resp.Body.Close()
+		}
+		if t.logger != nil {
+			t.logger.Debugf("Retrying %s %s in %s (attempt %d of %d): %v",
+				req.Method, req.URL.Redacted(), wait, attempt+2, t.cfg.Retries+1, outcome(resp, err))
+		}
+		if err := t.sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to rewind request body: %w", err)
+			}
+			req.Body = body
+		}
+	}
+}
+
+// attempt sends the request once, through the host's circuit breaker
+func (t *Transport) attempt(req *http.Request) (*http.Response, error) {
+	if t.cfg.BreakerThreshold <= 0 {
+		return t.base.RoundTrip(req)
+	}
+
+	hostBreaker := t.breakers.GetWithSettings("http:"+req.URL.Host, breaker.Settings{
+		Threshold: t.cfg.BreakerThreshold,
+		Cooldown:  t.cfg.BreakerCooldown,
+	})
+	if err := hostBreaker.Allow(); err != nil {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	switch {
+	case err != nil && req.Context().Err() != nil:
+		// Canceled by the caller, which says nothing about the host
+		hostBreaker.Cancel()
+	case err != nil || resp.StatusCode >= http.StatusInternalServerError:
+		hostBreaker.Failure()
+	default:
+		hostBreaker.Success()
+	}
+	return resp, err
+}
+
+// backoff returns how long to wait before retry number attempt+1: the
+// response's Retry-After, or an exponential backoff with jitter, capped at
+// RetryMaxBackoff
+func (t *Transport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return min(time.Duration(seconds)*time.Second, t.cfg.RetryMaxBackoff)
+		}
+	}
+
+	wait := t.cfg.RetryBackoff << attempt
+	if wait <= 0 || wait > t.cfg.RetryMaxBackoff {
+		wait = t.cfg.RetryMaxBackoff
+	}
+	// Half fixed, half random, so clients that failed together don't retry together
+	half := wait / 2
+	if half <= 0 {
+		return wait
+	}
+	return half + rand.N(half+1)
+}
+
+// isRetryable returns whether the request can be sent again: it is
+// idempotent (by method, or with an Idempotency-Key header), and its body
+// can be rewound
+func isRetryable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+// shouldRetry returns whether an attempt failed in a way that may succeed
+// when retried: a network error, throttling, or an unavailable upstream
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// outcome returns the error a request is recorded with: its error, or one
+// for a server error response
+func outcome(resp *http.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	if resp != nil && resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("server error: %s", resp.Status)
+	}
+	return nil
+}
+
+// sleepContext waits for d, or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
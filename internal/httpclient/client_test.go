@@ -0,0 +1,298 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/breaker"
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// testConfig retries twice without waiting long, and opens the breaker after
+// three failures
+func testConfig() config.HTTPClientConfig {
+	cfg := config.DefaultHTTPClientConfig()
+	cfg.RetryBackoff = time.Millisecond
+	cfg.RetryMaxBackoff = 2 * time.Millisecond
+	cfg.BreakerThreshold = 3
+	cfg.BreakerCooldown = time.Minute
+	return cfg
+}
+
+// recorder is a Recorder that keeps what it records
+type recorder struct {
+	mu      sync.Mutex
+	names   []string
+	errored []bool
+}
+
+func (r *recorder) Record(name string, _ time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.names = append(r.names, name)
+	r.errored = append(r.errored, err != nil)
+}
+
+// statusServer responds with the statuses in order, then with 200
+func statusServer(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+	var calls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		if int(n) <= len(statuses) {
+			w.WriteHeader(statuses[n-1])
+			return
+		}
+		_, _ = w.Write(append([]byte("ok:"), body...))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestClient_RetriesIdempotentRequests(t *testing.T) {
+	server, calls := statusServer(t, http.StatusServiceUnavailable, http.StatusBadGateway)
+	metrics := &recorder{}
+	client := New(testConfig(), metrics, nil, nil)
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200 after retries, got %d", resp.StatusCode)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls.Load())
+	}
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	if len(metrics.names) != 1 || metrics.names[0] != "GET "+host || metrics.errored[0] {
+		t.Errorf("Expected one successful request recorded for GET %s, got %v %v", host, metrics.names, metrics.errored)
+	}
+}
+
+func TestClient_GivesUpAfterRetries(t *testing.T) {
+	server, calls := statusServer(t, 503, 503, 503, 503)
+	metrics := &recorder{}
+	client := New(testConfig(), metrics, nil, nil)
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected the last status, got %d", resp.StatusCode)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls.Load())
+	}
+	if len(metrics.errored) != 1 || !metrics.errored[0] {
+		t.Errorf("Expected the request to be recorded as an error, got %v", metrics.errored)
+	}
+}
+
+func TestClient_RetriesRewindBody(t *testing.T) {
+	server, calls := statusServer(t, http.StatusServiceUnavailable)
+	client := New(testConfig(), nil, nil, nil)
+
+	req, _ := http.NewRequest("PUT", server.URL, strings.NewReader("payload"))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "ok:payload" {
+		t.Errorf("Expected the body to be sent again, got %q", body)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected 2 attempts, got %d", calls.Load())
+	}
+}
+
+func TestClient_DoesNotRetryUnsafeRequests(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		header  string
+		retried bool
+	}{
+		{"POST", "POST", "", false},
+		{"POST with an idempotency key", "POST", "abc123", true},
+		{"PATCH", "PATCH", "", false},
+		{"DELETE", "DELETE", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := statusServer(t, http.StatusServiceUnavailable)
+			client := New(testConfig(), nil, nil, nil)
+
+			req, _ := http.NewRequest(tt.method, server.URL, strings.NewReader("{}"))
+			if tt.header != "" {
+				req.Header.Set("Idempotency-Key", tt.header)
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("Do failed: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			want := int64(1)
+			if tt.retried {
+				want = 2
+			}
+			if calls.Load() != want {
+				t.Errorf("Expected %d attempts, got %d", want, calls.Load())
+			}
+		})
+	}
+}
+
+func TestClient_DoesNotRetryClientErrors(t *testing.T) {
+	server, calls := statusServer(t, http.StatusNotFound)
+	client := New(testConfig(), nil, nil, nil)
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound || calls.Load() != 1 {
+		t.Errorf("Expected one 404, got %d after %d attempts", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestClient_CircuitBreaker(t *testing.T) {
+	server, calls := statusServer(t, 500, 500, 500, 500, 500, 500)
+	cfg := testConfig()
+	cfg.Retries = 0
+	cfg.BreakerCooldown = 20 * time.Millisecond
+	breakers := breaker.NewRegistry(breaker.Settings{}, nil)
+	client := New(cfg, nil, breakers, nil)
+
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Get %d failed: %v", i, err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	// Open: fails fast without calling the server
+	_, err := client.Get(server.URL)
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected ErrCircuitOpen, got %v", err)
+	}
+	var typedErr *util.TypedError
+	if !errors.As(err, &typedErr) || typedErr.Type != util.ErrorTypeDependencyUnavailable {
+		t.Errorf("Expected a DEPENDENCY_UNAVAILABLE error, got %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("Expected the open breaker to skip the server, got %d calls", calls.Load())
+	}
+	host := strings.TrimPrefix(server.URL, "http://")
+	if stats := breakers.Stats(); len(stats) != 1 || stats[0].Name != "http:"+host || stats[0].State != breaker.StateOpen {
+		t.Errorf("Expected the host's breaker to be open in the registry, got %+v", stats)
+	}
+
+	// After the cooldown one request tests the host; it fails, so the
+	// breaker opens again
+	time.Sleep(cfg.BreakerCooldown)
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Expected the probe to be sent, got %v", err)
+	}
+	_ = resp.Body.Close()
+	if _, err := client.Get(server.URL); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected the breaker to open again, got %v", err)
+	}
+
+	// The next probe succeeds and closes the breaker
+	failedCalls := calls.Load()
+	time.Sleep(cfg.BreakerCooldown)
+	calls.Store(100) // Past the failing statuses
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Expected the breaker to close, got %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+	if failedCalls != 4 {
+		t.Errorf("Expected 4 calls before the breaker closed, got %d", failedCalls)
+	}
+}
+
+func TestClient_PropagatesRequestID(t *testing.T) {
+	var got, userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("X-Request-ID")
+		userAgent = r.Header.Get("User-Agent")
+	}))
+	defer server.Close()
+
+	cfg := testConfig()
+	cfg.UserAgent = "my-app/1.0"
+	client := New(cfg, nil, nil, nil)
+
+	ctx := util.WithRequestID(context.Background(), "req-123")
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if got != "req-123" {
+		t.Errorf("Expected X-Request-ID req-123, got %q", got)
+	}
+	if userAgent != "my-app/1.0" {
+		t.Errorf("Expected the configured user agent, got %q", userAgent)
+	}
+	if req.Header.Get("X-Request-ID") != "" {
+		t.Error("Expected the caller's request to be left unchanged")
+	}
+}
+
+func TestClient_RetryAfter(t *testing.T) {
+	transport := NewTransport(nil, testConfig(), nil, nil, nil)
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"1"}}}
+
+	// Capped at RetryMaxBackoff
+	if wait := transport.backoff(0, resp); wait != 2*time.Millisecond {
+		t.Errorf("Expected Retry-After capped at 2ms, got %s", wait)
+	}
+	if wait := transport.backoff(5, nil); wait < time.Millisecond || wait > 2*time.Millisecond {
+		t.Errorf("Expected a backoff between 1ms and 2ms, got %s", wait)
+	}
+}
+
+func TestClient_StopsRetryingWhenCanceled(t *testing.T) {
+	server, calls := statusServer(t, 503, 503, 503)
+	cfg := testConfig()
+	cfg.RetryBackoff = time.Hour
+	cfg.RetryMaxBackoff = time.Hour
+	client := New(cfg, nil, nil, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+	if _, err := client.Do(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the deadline to stop the retries, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 attempt, got %d", calls.Load())
+	}
+}
@@ -0,0 +1,203 @@
+// Package i18n translates user-facing messages, such as errors, into the
+// client's language
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Catalog holds translated messages by locale and key. Keys are error types
+// (e.g., "CONNECTION_ACTION_NOT_FOUND"), validation rules prefixed with
+// "validation." (e.g., "validation.minLength"), or the app's own keys (e.g.,
+// "greeting"). Messages may contain {placeholders}, which Translate fills in.
+type Catalog struct {
+	mu       sync.RWMutex
+	messages map[string]map[string]string // locale -> key -> message
+}
+
+// NewCatalog creates an empty catalog
+func NewCatalog() *Catalog {
+	return &Catalog{messages: make(map[string]map[string]string)}
+}
+
+// Add adds messages for a locale (e.g., "fr" or "pt-BR"), replacing any
+// existing messages with the same keys
+func (c *Catalog) Add(locale string, messages map[string]string) {
+	locale = normalize(locale)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.messages[locale] == nil {
+		c.messages[locale] = make(map[string]string)
+	}
+	for key, message := range messages {
+		c.messages[locale][key] = message
+	}
+}
+
+// LoadDir adds the messages of each catalog file in dir, named after its
+// locale: fr.yaml, fr.yml, or fr.json. Nested keys are joined with dots, so
+// {"validation": {"minLength": "..."}} adds "validation.minLength". It
+// returns the number of files loaded.
+func (c *Catalog) LoadDir(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	loaded := 0
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return loaded, err
+		}
+		// JSON is YAML, so one decoder reads both
+		var tree map[string]interface{}
+		if err := yaml.Unmarshal(data, &tree); err != nil {
+			return loaded, fmt.Errorf("invalid catalog %s: %w", path, err)
+		}
+		messages := make(map[string]string)
+		if err := flatten("", tree, messages); err != nil {
+			return loaded, fmt.Errorf("invalid catalog %s: %w", path, err)
+		}
+		c.Add(strings.TrimSuffix(entry.Name(), ext), messages)
+		loaded++
+	}
+	return loaded, nil
+}
+
+// flatten adds the messages in tree to messages, joining nested keys with
+// dots after prefix
+func flatten(prefix string, tree map[string]interface{}, messages map[string]string) error {
+	for key, value := range tree {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch value := value.(type) {
+		case map[string]interface{}:
+			if err := flatten(key, value, messages); err != nil {
+				return err
+			}
+		case string:
+			messages[key] = value
+		case nil:
+			return fmt.Errorf("%s: message is empty", key)
+		case []interface{}:
+			return fmt.Errorf("%s: message must be a string, not a list", key)
+		default:
+			messages[key] = fmt.Sprint(value)
+		}
+	}
+	return nil
+}
+
+// Locales returns the locales with messages, sorted
+func (c *Catalog) Locales() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	locales := make([]string, 0, len(c.messages))
+	for locale := range c.messages {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Translate returns the message for key in the first of the preferred
+// locales that has one, trying each locale's base language too ("pt-BR",
+// then "pt"). Each {name} in the message is replaced with vars[name]. It
+// reports false when no preferred locale has the key.
+func (c *Catalog) Translate(locales []string, key string, vars map[string]string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, locale := range locales {
+		locale = normalize(locale)
+		candidates := []string{locale}
+		if base, _, found := strings.Cut(locale, "-"); found {
+			candidates = append(candidates, base)
+		}
+		for _, candidate := range candidates {
+			if message, ok := c.messages[candidate][key]; ok {
+				return fill(message, vars), true
+			}
+		}
+	}
+	return "", false
+}
+
+// fill replaces each {name} in message with vars[name]
+func fill(message string, vars map[string]string) string {
+	if len(vars) == 0 {
+		return message
+	}
+	pairs := make([]string, 0, len(vars)*2)
+	for name, value := range vars {
+		pairs = append(pairs, "{"+name+"}", value)
+	}
+	return strings.NewReplacer(pairs...).Replace(message)
+}
+
+// normalize lowercases a locale's language and uppercases its region, so
+// "pt_br", "PT-br", and "pt-BR" are the same locale
+func normalize(locale string) string {
+	locale = strings.ReplaceAll(strings.TrimSpace(locale), "_", "-")
+	language, region, found := strings.Cut(locale, "-")
+	if !found {
+		return strings.ToLower(language)
+	}
+	return strings.ToLower(language) + "-" + strings.ToUpper(region)
+}
+
+// ParseAcceptLanguage returns the locales in an Accept-Language header, most
+// preferred first (e.g., "fr-CH, fr;q=0.9, en;q=0.8" gives fr-CH, fr, en).
+// Wildcards and locales with q=0 are skipped.
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+
+	var entries []weighted
+	for _, part := range strings.Split(header, ",") {
+		locale, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		locale = strings.TrimSpace(locale)
+		if locale == "" || locale == "*" {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		entries = append(entries, weighted{locale: normalize(locale), q: q})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].q > entries[j].q })
+	locales := make([]string, len(entries))
+	for i, entry := range entries {
+		locales[i] = entry.locale
+	}
+	return locales
+}
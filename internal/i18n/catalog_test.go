@@ -0,0 +1,112 @@
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCatalog_Translate(t *testing.T) {
+	catalog := NewCatalog()
+	catalog.Add("fr", map[string]string{
+		"CONNECTION_ACTION_NOT_FOUND": "Action introuvable",
+		"validation.minLength":        "doit contenir au moins {limit} caractères",
+	})
+	catalog.Add("pt_br", map[string]string{"CONNECTION_ACTION_NOT_FOUND": "Ação não encontrada"})
+
+	tests := []struct {
+		name    string
+		locales []string
+		key     string
+		vars    map[string]string
+		want    string
+		found   bool
+	}{
+		{"exact locale", []string{"fr"}, "CONNECTION_ACTION_NOT_FOUND", nil, "Action introuvable", true},
+		{"base language", []string{"fr-CH"}, "CONNECTION_ACTION_NOT_FOUND", nil, "Action introuvable", true},
+		{"normalized region", []string{"PT-br"}, "CONNECTION_ACTION_NOT_FOUND", nil, "Ação não encontrada", true},
+		{"first match wins", []string{"de", "pt-BR", "fr"}, "CONNECTION_ACTION_NOT_FOUND", nil, "Ação não encontrada", true},
+		{"placeholders", []string{"fr"}, "validation.minLength", map[string]string{"limit": "3"}, "doit contenir au moins 3 caractères", true},
+		{"missing key", []string{"fr"}, "CONNECTION_ACTION_RUN", nil, "", false},
+		{"missing locale", []string{"de"}, "CONNECTION_ACTION_NOT_FOUND", nil, "", false},
+		{"no locales", nil, "CONNECTION_ACTION_NOT_FOUND", nil, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := catalog.Translate(tt.locales, tt.key, tt.vars)
+			if got != tt.want || found != tt.found {
+				t.Errorf("Translate(%v, %q) = %q, %v; want %q, %v", tt.locales, tt.key, got, found, tt.want, tt.found)
+			}
+		})
+	}
+
+	if locales := catalog.Locales(); !reflect.DeepEqual(locales, []string{"fr", "pt-BR"}) {
+		t.Errorf("Expected locales [fr pt-BR], got %v", locales)
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{"", []string{}},
+		{"fr", []string{"fr"}},
+		{"fr-CH, fr;q=0.9, en;q=0.8, de;q=0.7, *;q=0.5", []string{"fr-CH", "fr", "en", "de"}},
+		{"en;q=0.5, pt-br", []string{"pt-BR", "en"}},
+		{"en;q=0, fr", []string{"fr"}},
+		{"en;q=abc, fr", []string{"fr"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := ParseAcceptLanguage(tt.header); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseAcceptLanguage(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalog_LoadDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"fr.yaml":    "greeting: Bonjour {name}\nvalidation:\n  minLength: trop court\n",
+		"pt_BR.json": `{"greeting": "Olá {name}", "count": 3}`,
+		"README.md":  "not a catalog",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	catalog := NewCatalog()
+	loaded, err := catalog.LoadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	if loaded != 2 || !reflect.DeepEqual(catalog.Locales(), []string{"fr", "pt-BR"}) {
+		t.Errorf("Unexpected catalogs: %d %v", loaded, catalog.Locales())
+	}
+	if got, _ := catalog.Translate([]string{"fr"}, "validation.minLength", nil); got != "trop court" {
+		t.Errorf("Expected nested keys joined with dots, got %q", got)
+	}
+	if got, _ := catalog.Translate([]string{"pt-BR"}, "greeting", map[string]string{"name": "Ana"}); got != "Olá Ana" {
+		t.Errorf("Unexpected JSON message: %q", got)
+	}
+	if got, _ := catalog.Translate([]string{"pt-BR"}, "count", nil); got != "3" {
+		t.Errorf("Expected scalars as strings, got %q", got)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "de.yaml"), []byte("greeting: [Hallo]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewCatalog().LoadDir(dir); err == nil {
+		t.Error("Expected an error for a list message")
+	}
+	if _, err := NewCatalog().LoadDir(filepath.Join(dir, "missing")); !os.IsNotExist(err) {
+		t.Errorf("Expected a not-exist error, got %v", err)
+	}
+}
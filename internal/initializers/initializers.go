@@ -0,0 +1,26 @@
+// Package initializers registers the initializers every API starts with, so
+// the root package's New and the actionhero CLI build the same API
+package initializers
+
+import (
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/evantahler/go-actionhero/internal/fixtures"
+	"github.com/evantahler/go-actionhero/internal/geoip"
+	"github.com/evantahler/go-actionhero/internal/mail"
+	"github.com/evantahler/go-actionhero/internal/statsd"
+	"github.com/evantahler/go-actionhero/internal/storage"
+	"github.com/evantahler/go-actionhero/internal/tasks"
+	"github.com/evantahler/go-actionhero/internal/uptime"
+)
+
+// Register registers the built-in initializers with a. Each one does nothing
+// unless its config section enables it.
+func Register(a *api.API) {
+	a.RegisterInitializer(statsd.NewInitializer())
+	a.RegisterInitializer(mail.NewInitializer())
+	a.RegisterInitializer(storage.NewInitializer())
+	a.RegisterInitializer(fixtures.NewInitializer())
+	a.RegisterInitializer(geoip.NewInitializer())
+	a.RegisterInitializer(uptime.NewInitializer())
+	a.RegisterInitializer(tasks.NewInitializer())
+}
@@ -0,0 +1,24 @@
+package initializers
+
+import (
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+func TestRegister(t *testing.T) {
+	a := api.New(&config.Config{}, util.NewLogger(config.LoggerConfig{Level: "error"}))
+	Register(a)
+
+	names := make(map[string]bool)
+	for _, initializer := range a.GetInitializers() {
+		names[initializer.Name()] = true
+	}
+	for _, name := range []string{"statsd", "mail", "storage", "fixtures", "geoip", "uptime", "tasks"} {
+		if !names[name] {
+			t.Errorf("Expected the %s initializer to be registered, got %v", name, names)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package mail
+
+import (
+	"fmt"
+	"net/mail"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+)
+
+// Initializer sets the API's Mail when mail.enabled is set
+type Initializer struct {
+	mailer *Mailer
+}
+
+// NewInitializer creates the mail initializer
+func NewInitializer() *Initializer {
+	return &Initializer{}
+}
+
+// Name returns the initializer name
+func (i *Initializer) Name() string {
+	return "mail"
+}
+
+// Priority returns the initialization priority
+func (i *Initializer) Priority() int {
+	return 100
+}
+
+// Initialize loads the mail templates and sets up the transport: a provided
+// Transport, or the one configured by mail.transport
+func (i *Initializer) Initialize(a *api.API) error {
+	cfg := a.Config.Mail
+	if !cfg.Enabled {
+		return nil
+	}
+
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return fmt.Errorf("invalid mail.from: %w", err)
+	}
+	templates, err := loadTemplates(cfg.Templates)
+	if err != nil {
+		return err
+	}
+	transportName := "provided transport"
+	transport, ok := api.Lookup[Transport](a)
+	if !ok {
+		if transport, err = newTransport(cfg); err != nil {
+			return err
+		}
+		transportName = cfg.Transport
+	}
+
+	i.mailer = &Mailer{from: from, templates: templates, transport: transport, logger: a.Logger}
+	if cfg.Async {
+		i.mailer.queue = make(chan delivery, cfg.QueueSize)
+	}
+	a.Mail = i.mailer
+
+	a.Logger.Infof("Mail enabled: %s with %d templates", transportName, len(templates))
+	return nil
+}
+
+// Start starts delivering queued mail (with mail.async)
+func (i *Initializer) Start(_ *api.API) error {
+	if i.mailer != nil {
+		i.mailer.start()
+	}
+	return nil
+}
+
+// Stop delivers the queued mail, then stops
+func (i *Initializer) Stop(_ *api.API) error {
+	if i.mailer != nil {
+		i.mailer.stop()
+	}
+	return nil
+}
@@ -0,0 +1,399 @@
+package mail
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// writeTemplates writes mail templates to a new directory
+func writeTemplates(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+// welcomeTemplates are a template with both bodies
+var welcomeTemplates = map[string]string{
+	"welcome.subject.tmpl": "Welcome,\n{{.Name}}!\n",
+	"welcome.txt.tmpl":     "Hi {{.Name}}, thanks for joining.",
+	"welcome.html.tmpl":    "<p>Hi {{.Name}}, thanks for joining.</p>",
+}
+
+// sentMail is a message captured by a recordingTransport
+type sentMail struct {
+	from    string
+	to      []string
+	message *mail.Message
+}
+
+// recordingTransport captures delivered messages
+type recordingTransport struct {
+	mu   sync.Mutex
+	sent []sentMail
+	err  error
+}
+
+func (r *recordingTransport) Deliver(_ context.Context, from string, to []string, message []byte) error {
+	msg, err := mail.ReadMessage(strings.NewReader(string(message)))
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sent = append(r.sent, sentMail{from: from, to: to, message: msg})
+	return r.err
+}
+
+func (r *recordingTransport) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.sent)
+}
+
+// newTestAPI creates an API with mail enabled for the templates in dir
+func newTestAPI(dir string) *api.API {
+	cfg := &config.Config{Mail: config.DefaultMailConfig()}
+	cfg.Mail.Enabled = true
+	cfg.Mail.From = "My App <app@example.com>"
+	cfg.Mail.Templates = dir
+	return api.New(cfg, util.NewLogger(config.LoggerConfig{Level: "fatal"}))
+}
+
+// parts returns the bodies of a multipart message, by content type
+func parts(t *testing.T, msg *mail.Message) map[string]string {
+	t.Helper()
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Expected a multipart/alternative message, got %q (%v)", mediaType, err)
+	}
+	bodies := make(map[string]string)
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return bodies
+		}
+		if err != nil {
+			t.Fatalf("Failed to read part: %v", err)
+		}
+		body, _ := io.ReadAll(part) // Decodes quoted-printable
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		bodies[contentType] = string(body)
+	}
+}
+
+func TestLoadTemplates_Errors(t *testing.T) {
+	if _, err := loadTemplates(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("Expected an error for a missing templates directory")
+	}
+
+	dir := writeTemplates(t, map[string]string{"welcome.txt.tmpl": "hi"})
+	if _, err := loadTemplates(dir); err == nil || !strings.Contains(err.Error(), "welcome.subject.tmpl") {
+		t.Errorf("Expected an error for a missing subject, got %v", err)
+	}
+
+	dir = writeTemplates(t, map[string]string{"welcome.subject.tmpl": "hi"})
+	if _, err := loadTemplates(dir); err == nil || !strings.Contains(err.Error(), "no body") {
+		t.Errorf("Expected an error for a missing body, got %v", err)
+	}
+
+	dir = writeTemplates(t, map[string]string{"welcome.subject.tmpl": "{{.Name", "welcome.txt.tmpl": "hi"})
+	if _, err := loadTemplates(dir); err == nil {
+		t.Error("Expected an error for an invalid template")
+	}
+}
+
+func TestMailer_Send(t *testing.T) {
+	a := newTestAPI(writeTemplates(t, welcomeTemplates))
+	transport := &recordingTransport{}
+	api.Provide[Transport](a, transport)
+
+	initializer := NewInitializer()
+	if err := initializer.Initialize(a); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	data := map[string]string{"Name": "Mario <script>"}
+	if err := a.Mail.Send(context.Background(), "Mario <mario@example.com>, luigi@example.com", "welcome", data); err != nil {
+		t.Fatalf("Failed to send: %v", err)
+	}
+	if transport.count() != 1 {
+		t.Fatalf("Expected one message, got %d", transport.count())
+	}
+
+	sent := transport.sent[0]
+	if sent.from != "app@example.com" || strings.Join(sent.to, ",") != "mario@example.com,luigi@example.com" {
+		t.Errorf("Unexpected envelope: %s -> %v", sent.from, sent.to)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(sent.message.Header.Get("Subject"))
+	if subject != "Welcome, Mario <script>!" {
+		t.Errorf("Expected a one-line subject, got %q", subject)
+	}
+	if from := sent.message.Header.Get("From"); from != `"My App" <app@example.com>` {
+		t.Errorf("Unexpected From header %q", from)
+	}
+
+	bodies := parts(t, sent.message)
+	if bodies["text/plain"] != "Hi Mario <script>, thanks for joining." {
+		t.Errorf("Unexpected text body %q", bodies["text/plain"])
+	}
+	if bodies["text/html"] != "<p>Hi Mario &lt;script&gt;, thanks for joining.</p>" {
+		t.Errorf("Expected an escaped HTML body, got %q", bodies["text/html"])
+	}
+
+	if err := a.Mail.Send(context.Background(), "mario@example.com", "missing", nil); err == nil {
+		t.Error("Expected an error for a missing template")
+	}
+	if err := a.Mail.Send(context.Background(), "not an address", "welcome", data); err == nil {
+		t.Error("Expected an error for invalid recipients")
+	}
+
+	transport.err = errors.New("connection refused")
+	if err := a.Mail.Send(context.Background(), "mario@example.com", "welcome", data); err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("Expected the delivery error, got %v", err)
+	}
+}
+
+func TestMailer_SinglePart(t *testing.T) {
+	a := newTestAPI(writeTemplates(t, map[string]string{
+		"reset.subject.tmpl": "Reset your password",
+		"reset.html.tmpl":    "<a href=\"{{.}}\">Reset</a>",
+	}))
+	transport := &recordingTransport{}
+	api.Provide[Transport](a, transport)
+	if err := NewInitializer().Initialize(a); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	if err := a.Mail.Send(context.Background(), "mario@example.com", "reset", "https://example.com/reset"); err != nil {
+		t.Fatalf("Failed to send: %v", err)
+	}
+	msg := transport.sent[0].message
+	if contentType := msg.Header.Get("Content-Type"); contentType != "text/html; charset=utf-8" {
+		t.Errorf("Expected an HTML message, got %q", contentType)
+	}
+}
+
+func TestMailer_Async(t *testing.T) {
+	a := newTestAPI(writeTemplates(t, welcomeTemplates))
+	a.Config.Mail.Async = true
+	a.Config.Mail.QueueSize = 2
+
+	release := make(chan struct{})
+	transport := &recordingTransport{}
+	api.Provide[Transport](a, TransportFunc(func(ctx context.Context, from string, to []string, message []byte) error {
+		<-release
+		return transport.Deliver(ctx, from, to, message)
+	}))
+
+	initializer := NewInitializer()
+	if err := initializer.Initialize(a); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	// Queued before the worker starts
+	data := map[string]string{"Name": "Mario"}
+	for i := 0; i < 2; i++ {
+		if err := a.Mail.Send(context.Background(), "mario@example.com", "welcome", data); err != nil {
+			t.Fatalf("Failed to queue: %v", err)
+		}
+	}
+	if err := a.Mail.Send(context.Background(), "mario@example.com", "welcome", data); err == nil || !strings.Contains(err.Error(), "queue is full") {
+		t.Errorf("Expected a full queue, got %v", err)
+	}
+
+	if err := initializer.Start(a); err != nil {
+		t.Fatalf("Failed to start: %v", err)
+	}
+	close(release)
+	if err := initializer.Stop(a); err != nil {
+		t.Fatalf("Failed to stop: %v", err)
+	}
+	if transport.count() != 2 {
+		t.Errorf("Expected the queued messages to be delivered on stop, got %d", transport.count())
+	}
+	if err := a.Mail.Send(context.Background(), "mario@example.com", "welcome", data); err == nil {
+		t.Error("Expected an error sending after stop")
+	}
+}
+
+func TestInitializer_Disabled(t *testing.T) {
+	a := api.New(&config.Config{Mail: config.DefaultMailConfig()}, util.NewLogger(config.LoggerConfig{Level: "fatal"}))
+	if err := NewInitializer().Initialize(a); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	if err := a.Mail.Send(context.Background(), "mario@example.com", "welcome", nil); !errors.Is(err, api.ErrMailDisabled) {
+		t.Errorf("Expected ErrMailDisabled, got %v", err)
+	}
+}
+
+// fakeSMTPServer accepts one SMTP session and returns the message sent
+func fakeSMTPServer(t *testing.T) (string, <-chan string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		text := textproto.NewConn(conn)
+
+		_ = text.PrintfLine("220 localhost ready")
+		var envelope []string
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+			command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+			switch command {
+			case "EHLO", "HELO":
+				_ = text.PrintfLine("250 localhost")
+			case "MAIL", "RCPT":
+				envelope = append(envelope, line)
+				_ = text.PrintfLine("250 OK")
+			case "DATA":
+				_ = text.PrintfLine("354 go ahead")
+				data, _ := text.ReadDotBytes()
+				received <- strings.Join(envelope, "\n") + "\n\n" + string(data)
+				_ = text.PrintfLine("250 queued")
+			case "QUIT":
+				_ = text.PrintfLine("221 bye")
+				return
+			default:
+				_ = text.PrintfLine("502 not implemented")
+			}
+		}
+	}()
+	return listener.Addr().String(), received
+}
+
+func TestSMTPTransport(t *testing.T) {
+	addr, received := fakeSMTPServer(t)
+	host, port, _ := net.SplitHostPort(addr)
+
+	cfg := config.DefaultMailConfig()
+	cfg.SMTPHost = host
+	cfg.SMTPPort, _ = net.LookupPort("tcp", port)
+	transport, err := newTransport(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create transport: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	message := "Subject: hi\r\n\r\nhello\r\n"
+	if err := transport.Deliver(ctx, "app@example.com", []string{"mario@example.com"}, []byte(message)); err != nil {
+		t.Fatalf("Failed to deliver: %v", err)
+	}
+
+	got := <-received
+	if !strings.Contains(got, "MAIL FROM:<app@example.com>") || !strings.Contains(got, "RCPT TO:<mario@example.com>") {
+		t.Errorf("Unexpected envelope: %q", got)
+	}
+	if !strings.HasSuffix(got, "\n\nSubject: hi\n\nhello\n") {
+		t.Errorf("Unexpected message: %q", got)
+	}
+}
+
+func TestSESTransport(t *testing.T) {
+	var request struct {
+		FromEmailAddress string
+		Destination      struct{ ToAddresses []string }
+		Content          struct{ Raw struct{ Data string } }
+	}
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/email/outbound-emails" {
+			http.NotFound(w, r)
+			return
+		}
+		authorization = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.FromEmailAddress == "blocked@example.com" {
+			w.Header().Set("X-Amzn-ErrorType", "MessageRejected")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message":"Email address is not verified."}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"MessageId":"1"}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	cfg := config.DefaultMailConfig()
+	cfg.Transport = "ses"
+	cfg.SESRegion = "eu-west-1"
+	cfg.SESEndpoint = server.URL
+	transport, err := newTransport(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create transport: %v", err)
+	}
+
+	message := "Subject: hi\r\n\r\nhello\r\n"
+	if err := transport.Deliver(context.Background(), "app@example.com", []string{"mario@example.com"}, []byte(message)); err != nil {
+		t.Fatalf("Failed to deliver: %v", err)
+	}
+	raw, _ := base64.StdEncoding.DecodeString(request.Content.Raw.Data)
+	if string(raw) != message || request.Destination.ToAddresses[0] != "mario@example.com" {
+		t.Errorf("Unexpected request: %+v", request)
+	}
+	if !strings.Contains(authorization, "/eu-west-1/ses/aws4_request") {
+		t.Errorf("Expected a signed SES request, got %q", authorization)
+	}
+
+	err = transport.Deliver(context.Background(), "blocked@example.com", []string{"mario@example.com"}, []byte(message))
+	if err == nil || !strings.Contains(err.Error(), "MessageRejected Email address is not verified.") {
+		t.Errorf("Expected the SES error, got %v", err)
+	}
+}
+
+func TestSESTransport_MissingSettings(t *testing.T) {
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	cfg := config.DefaultMailConfig()
+	cfg.Transport = "ses"
+	if _, err := newTransport(cfg); err == nil || !strings.Contains(err.Error(), "region") {
+		t.Errorf("Expected a missing region error, got %v", err)
+	}
+	cfg.SESRegion = "us-east-1"
+	if _, err := newTransport(cfg); err == nil || !strings.Contains(err.Error(), "credentials") {
+		t.Errorf("Expected a missing credentials error, got %v", err)
+	}
+}
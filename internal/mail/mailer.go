@@ -0,0 +1,119 @@
+// Package mail sends email rendered from Go templates over SMTP or Amazon
+// SES. The mail initializer sets the API's Mail when mail.enabled is set:
+//
+//	err := api.APIFromContext(ctx).Mail.Send(ctx, user.Email, "welcome", user)
+//
+// renders templates/mail/welcome.subject.tmpl and welcome.txt.tmpl and/or
+// welcome.html.tmpl (mail.templates) with user, then delivers the message
+// during Send or, with mail.async, from a background queue.
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"sync"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// Mailer renders templates and delivers messages with a Transport. It
+// implements api.Mailer.
+type Mailer struct {
+	from      *mail.Address
+	templates map[string]*template
+	transport Transport
+	logger    *util.Logger
+
+	// Background delivery (mail.async); queue is nil when sending inline
+	queue  chan delivery
+	closed bool
+	mu     sync.RWMutex
+	wg     sync.WaitGroup
+}
+
+// delivery is a message queued for background delivery
+type delivery struct {
+	from    string
+	to      []string
+	message []byte
+}
+
+// Send renders the named template with data and delivers it to to (one or
+// more comma-separated addresses). With a queue, it returns once the message
+// is queued; delivery errors are logged.
+func (m *Mailer) Send(ctx context.Context, to, name string, data interface{}) error {
+	recipients, err := mail.ParseAddressList(to)
+	if err != nil {
+		return fmt.Errorf("invalid recipients %q: %w", to, err)
+	}
+	t, ok := m.templates[name]
+	if !ok {
+		return fmt.Errorf("mail template %q not found", name)
+	}
+	subject, text, html, err := t.render(data)
+	if err != nil {
+		return fmt.Errorf("failed to render mail template %s: %w", name, err)
+	}
+
+	msg := &message{from: m.from, to: recipients, subject: subject, text: text, html: html}
+	encoded, err := msg.bytes(time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to encode mail: %w", err)
+	}
+	d := delivery{from: m.from.Address, to: msg.recipients(), message: encoded}
+
+	if m.queue == nil {
+		if err := m.transport.Deliver(ctx, d.from, d.to, d.message); err != nil {
+			return fmt.Errorf("failed to send mail: %w", err)
+		}
+		m.logger.Debugf("Sent mail %s to %v", name, d.to)
+		return nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return errors.New("mailer is stopped")
+	}
+	select {
+	case m.queue <- d:
+		return nil
+	default:
+		return fmt.Errorf("mail queue is full (mail.queuesize %d)", cap(m.queue))
+	}
+}
+
+// start delivers queued messages in the background, until stop
+func (m *Mailer) start() {
+	if m.queue == nil {
+		return
+	}
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		for d := range m.queue {
+			ctx, cancel := context.WithTimeout(context.Background(), deliveryTimeout)
+			if err := m.transport.Deliver(ctx, d.from, d.to, d.message); err != nil {
+				m.logger.Errorf("Failed to send mail to %v: %v", d.to, err)
+			}
+			cancel()
+		}
+	}()
+}
+
+// stop stops queueing messages and waits for the queued ones to be delivered
+func (m *Mailer) stop() {
+	if m.queue == nil {
+		return
+	}
+	m.mu.Lock()
+	if !m.closed {
+		m.closed = true
+		close(m.queue)
+	}
+	m.mu.Unlock()
+	m.wg.Wait()
+}
@@ -0,0 +1,105 @@
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// message is a rendered email
+type message struct {
+	from    *mail.Address
+	to      []*mail.Address
+	subject string
+	text    string // Plain text body ("" = none)
+	html    string // HTML body ("" = none)
+}
+
+// recipients returns the addresses to deliver the message to
+func (m *message) recipients() []string {
+	addresses := make([]string, len(m.to))
+	for i, to := range m.to {
+		addresses[i] = to.Address
+	}
+	return addresses
+}
+
+// bytes encodes the message as MIME, with a multipart/alternative body when
+// it has both a text and an HTML body
+func (m *message) bytes(now time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+
+	to := make([]string, len(m.to))
+	for i, address := range m.to {
+		to[i] = address.String()
+	}
+	domain := m.from.Address[strings.LastIndex(m.from.Address, "@")+1:]
+
+	header := func(name, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", name, value)
+	}
+	header("From", m.from.String())
+	header("To", strings.Join(to, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", m.subject))
+	header("Date", now.Format(time.RFC1123Z))
+	header("Message-ID", fmt.Sprintf("<%s@%s>", uuid.New().String(), domain))
+	header("MIME-Version", "1.0")
+
+	if m.text == "" || m.html == "" {
+		contentType, body := "text/plain; charset=utf-8", m.text
+		if m.html != "" {
+			contentType, body = "text/html; charset=utf-8", m.html
+		}
+		header("Content-Type", contentType)
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := writeQuotedPrintable(&buf, body); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	var parts bytes.Buffer
+	writer := multipart.NewWriter(&parts)
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", m.text},
+		{"text/html; charset=utf-8", m.html},
+	} {
+		w, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(w, part.body); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	header("Content-Type", mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": writer.Boundary()}))
+	buf.WriteString("\r\n")
+	buf.Write(parts.Bytes())
+	return buf.Bytes(), nil
+}
+
+// writeQuotedPrintable writes body to w, quoted-printable encoded
+func writeQuotedPrintable(w io.Writer, body string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
@@ -0,0 +1,108 @@
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Template file suffixes. A template named "welcome" is made of
+// welcome.subject.tmpl and welcome.txt.tmpl and/or welcome.html.tmpl.
+const (
+	subjectSuffix = ".subject.tmpl"
+	textSuffix    = ".txt.tmpl"
+	htmlSuffix    = ".html.tmpl"
+)
+
+// template renders one kind of email
+type template struct {
+	subject *texttemplate.Template
+	text    *texttemplate.Template // nil without a text body
+	html    *htmltemplate.Template // nil without an HTML body
+}
+
+// loadTemplates parses the templates in dir, by name. Each needs a subject
+// and at least one body.
+func loadTemplates(dir string) (map[string]*template, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mail templates: %w", err)
+	}
+
+	templates := make(map[string]*template)
+	get := func(name string) *template {
+		if templates[name] == nil {
+			templates[name] = &template{}
+		}
+		return templates[name]
+	}
+	for _, entry := range entries {
+		file := entry.Name()
+		if entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, file)
+
+		switch {
+		case strings.HasSuffix(file, subjectSuffix):
+			name := strings.TrimSuffix(file, subjectSuffix)
+			get(name).subject, err = texttemplate.ParseFiles(path)
+		case strings.HasSuffix(file, textSuffix):
+			name := strings.TrimSuffix(file, textSuffix)
+			get(name).text, err = texttemplate.ParseFiles(path)
+		case strings.HasSuffix(file, htmlSuffix):
+			name := strings.TrimSuffix(file, htmlSuffix)
+			get(name).html, err = htmltemplate.ParseFiles(path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid mail template %s: %w", path, err)
+		}
+	}
+
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		t := templates[name]
+		if t.subject == nil {
+			return nil, fmt.Errorf("mail template %s has no %s%s", name, name, subjectSuffix)
+		}
+		if t.text == nil && t.html == nil {
+			return nil, fmt.Errorf("mail template %s has no body (%s%s or %s%s)", name, name, textSuffix, name, htmlSuffix)
+		}
+	}
+	return templates, nil
+}
+
+// render fills the template's subject and bodies in with data
+func (t *template) render(data interface{}) (subject, text, html string, err error) {
+	var buf bytes.Buffer
+	if err := t.subject.Execute(&buf, data); err != nil {
+		return "", "", "", err
+	}
+	// Subjects are one line
+	subject = strings.Join(strings.Fields(buf.String()), " ")
+
+	if t.text != nil {
+		buf.Reset()
+		if err := t.text.Execute(&buf, data); err != nil {
+			return "", "", "", err
+		}
+		text = buf.String()
+	}
+	if t.html != nil {
+		buf.Reset()
+		if err := t.html.Execute(&buf, data); err != nil {
+			return "", "", "", err
+		}
+		html = buf.String()
+	}
+	return subject, text, html, nil
+}
@@ -0,0 +1,194 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/awssig"
+	"github.com/evantahler/go-actionhero/internal/config"
+)
+
+// Transport delivers encoded messages. The mail initializer uses the one
+// configured by mail.transport, unless one is provided as a resource (e.g.,
+// for another email provider, or to capture mail in tests):
+//
+//	api.Provide[mail.Transport](a, myTransport)
+type Transport interface {
+	Deliver(ctx context.Context, from string, to []string, message []byte) error
+}
+
+// TransportFunc adapts a function to a Transport
+type TransportFunc func(ctx context.Context, from string, to []string, message []byte) error
+
+// Deliver calls f
+func (f TransportFunc) Deliver(ctx context.Context, from string, to []string, message []byte) error {
+	return f(ctx, from, to, message)
+}
+
+// deliveryTimeout bounds a delivery when the context has no deadline
+const deliveryTimeout = 30 * time.Second
+
+// newTransport creates the transport configured by mail.transport
+func newTransport(cfg config.MailConfig) (Transport, error) {
+	switch cfg.Transport {
+	case "ses":
+		return newSESTransport(cfg)
+	default:
+		return &smtpTransport{
+			host:     cfg.SMTPHost,
+			addr:     net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(cfg.SMTPPort)),
+			user:     cfg.SMTPUser,
+			password: cfg.SMTPPassword,
+		}, nil
+	}
+}
+
+// smtpTransport delivers messages to an SMTP server, upgrading to TLS when
+// the server supports STARTTLS
+type smtpTransport struct {
+	host     string
+	addr     string
+	user     string // PLAIN authentication when set
+	password string
+}
+
+// Deliver sends a message over one SMTP session
+func (t *smtpTransport) Deliver(ctx context.Context, from string, to []string, message []byte) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", t.addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(deliveryTimeout)
+	}
+	_ = conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, t.host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("smtp handshake failed: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: t.host, MinVersion: tls.VersionTLS12}); err != nil {
+			return fmt.Errorf("smtp starttls failed: %w", err)
+		}
+	}
+	if t.user != "" {
+		if err := client.Auth(smtp.PlainAuth("", t.user, t.password, t.host)); err != nil {
+			return fmt.Errorf("smtp authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return fmt.Errorf("smtp server rejected sender: %w", err)
+	}
+	for _, recipient := range to {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("smtp server rejected recipient %s: %w", recipient, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data failed: %w", err)
+	}
+	if _, err := w.Write(message); err != nil {
+		return fmt.Errorf("smtp data failed: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp server rejected message: %w", err)
+	}
+	return client.Quit()
+}
+
+// sesTransport delivers messages with the Amazon SES v2 SendEmail API.
+// Credentials come from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, and
+// AWS_SESSION_TOKEN.
+type sesTransport struct {
+	region      string
+	endpoint    string
+	credentials awssig.Credentials
+	client      *http.Client
+}
+
+// newSESTransport creates an SES transport, falling back to AWS_REGION (or
+// AWS_DEFAULT_REGION) for the region
+func newSESTransport(cfg config.MailConfig) (*sesTransport, error) {
+	t := &sesTransport{
+		region:      cfg.SESRegion,
+		endpoint:    cfg.SESEndpoint,
+		credentials: awssig.CredentialsFromEnv(),
+		client:      &http.Client{Timeout: deliveryTimeout},
+	}
+	if t.region == "" {
+		t.region = os.Getenv("AWS_REGION")
+	}
+	if t.region == "" {
+		t.region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if t.region == "" {
+		return nil, errors.New("aws region is not set (mail.sesregion or AWS_REGION)")
+	}
+	if !t.credentials.Valid() {
+		return nil, errors.New("aws credentials are not set (AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY)")
+	}
+	if t.endpoint == "" {
+		t.endpoint = fmt.Sprintf("https://email.%s.amazonaws.com", t.region)
+	}
+	t.endpoint = strings.TrimRight(t.endpoint, "/")
+	return t, nil
+}
+
+// Deliver sends a raw message
+func (t *sesTransport) Deliver(ctx context.Context, from string, to []string, message []byte) error {
+	var request struct {
+		FromEmailAddress string
+		Destination      struct{ ToAddresses []string }
+		Content          struct{ Raw struct{ Data []byte } } // Base64 encoded
+	}
+	request.FromEmailAddress = from
+	request.Destination.ToAddresses = to
+	request.Content.Raw.Data = message
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint+"/v2/email/outbound-emails", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	awssig.Sign(req, body, t.credentials, t.region, "ses", time.Now())
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ses request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		var sesErr struct {
+			Message string `json:"message"`
+		}
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		_ = json.Unmarshal(respBody, &sesErr)
+		return fmt.Errorf("ses returned %d: %s %s", resp.StatusCode, resp.Header.Get("X-Amzn-ErrorType"), sesErr.Message)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package openapi
+
+import (
+	"strings"
+	"testing"
+)
+
+type address struct {
+	City string `json:"city" validate:"required"`
+}
+
+type signupInput struct {
+	Name    string            `json:"name" validate:"required,min=3,max=10"`
+	Email   string            `json:"email" validate:"required,email"`
+	Age     int               `json:"age" validate:"gte=18"`
+	Role    string            `json:"role" validate:"oneof=admin user"`
+	Tags    []string          `json:"tags" validate:"max=2,dive,alpha"`
+	Labels  map[string]string `json:"labels"`
+	Address *address          `json:"address"`
+	Website string            `json:"website" validate:"url"`
+}
+
+func TestSchemaFromStruct(t *testing.T) {
+	schema := SchemaFromStruct(signupInput{})
+
+	if schema["type"] != "object" {
+		t.Errorf("Expected object schema, got %v", schema["type"])
+	}
+	required := strings.Join(schema["required"].([]string), ",")
+	if required != "name,email" {
+		t.Errorf("Expected required name,email, got %s", required)
+	}
+
+	properties := schema["properties"].(map[string]interface{})
+	name := properties["name"].(map[string]interface{})
+	if name["minLength"] != 3 || name["maxLength"] != 10 {
+		t.Errorf("Unexpected name constraints: %v", name)
+	}
+	address := properties["address"].(map[string]interface{})
+	if address["nullable"] != true || address["properties"] == nil {
+		t.Errorf("Expected nullable nested address schema, got %v", address)
+	}
+}
+
+func TestQueryParameters(t *testing.T) {
+	pathParams := []map[string]interface{}{{"name": "name", "in": "path"}}
+	params := QueryParameters(signupInput{}, pathParams)
+
+	for _, param := range params {
+		if param["name"] == "name" {
+			t.Error("Expected path parameters to be skipped")
+		}
+		if param["in"] != "query" {
+			t.Errorf("Expected query parameter, got %v", param["in"])
+		}
+	}
+	if len(params) != 7 {
+		t.Errorf("Expected 7 query parameters, got %d", len(params))
+	}
+}
+
+func TestValidate_Valid(t *testing.T) {
+	schema := SchemaFromStruct(signupInput{})
+
+	// Query and form values arrive as strings
+	errs := Validate(schema, map[string]interface{}{
+		"name":    "Mario",
+		"email":   "mario@example.com",
+		"age":     "30",
+		"role":    "admin",
+		"tags":    []string{"plumber"},
+		"labels":  map[string]interface{}{"team": "red"},
+		"address": map[string]interface{}{"city": "Brooklyn"},
+		"website": "https://example.com",
+		"extra":   "ignored",
+	})
+	if len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+}
+
+func TestValidate_Invalid(t *testing.T) {
+	schema := SchemaFromStruct(signupInput{})
+
+	errs := Validate(schema, map[string]interface{}{
+		"name":    "Al",
+		"age":     float64(12),
+		"role":    "owner",
+		"tags":    []interface{}{"ok", "n0t-alpha", "three"},
+		"address": map[string]interface{}{},
+		"website": "not a url",
+	})
+
+	// Pointer -> failed rule
+	expected := map[string]string{
+		"/name":         "minLength",
+		"/email":        "required",
+		"/age":          "minimum",
+		"/role":         "enum",
+		"/tags":         "maxItems",
+		"/tags/1":       "pattern",
+		"/address/city": "required",
+		"/website":      "format",
+	}
+	found := make(map[string]bool)
+	for _, err := range errs {
+		rule, ok := expected[err.Pointer]
+		if !ok {
+			t.Errorf("Unexpected error: %v", err)
+		} else if err.Rule != rule {
+			t.Errorf("Expected rule %s at %s, got %s", rule, err.Pointer, err.Rule)
+		}
+		found[err.Pointer] = true
+	}
+	for pointer := range expected {
+		if !found[pointer] {
+			t.Errorf("Expected an error at %s", pointer)
+		}
+	}
+}
+
+func TestValidate_Types(t *testing.T) {
+	schema := SchemaFromStruct(signupInput{})
+
+	errs := Validate(schema, map[string]interface{}{
+		"name":  "Mario",
+		"email": "mario@example.com",
+		"age":   "thirty",
+	})
+	if len(errs) != 1 || errs[0].Pointer != "/age" || !strings.Contains(errs[0].Message, "integer") {
+		t.Errorf("Expected a type error for age, got %v", errs)
+	}
+
+	errs = Validate(schema, map[string]interface{}{
+		"name":  "Mario",
+		"email": "mario@example.com",
+		"age":   18.5,
+	})
+	if len(errs) != 1 || errs[0].Pointer != "/age" {
+		t.Errorf("Expected fractional age to be rejected, got %v", errs)
+	}
+}
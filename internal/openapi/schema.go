@@ -0,0 +1,360 @@
+// Package openapi builds OpenAPI schemas from action input and output structs,
+// and validates request params against them
+package openapi
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSchemaDepth bounds recursion into nested types (and breaks cycles)
+const maxSchemaDepth = 8
+
+// SchemaFromStruct builds an OpenAPI object schema from a Go struct, using
+// json tags for property names and validate tags for constraints
+func SchemaFromStruct(input interface{}) map[string]interface{} {
+	return buildObjectSchema(reflect.TypeOf(input), 0)
+}
+
+// buildObjectSchema builds an OpenAPI object schema from a struct type,
+// including nested structs, slices, and maps
+func buildObjectSchema(inputType reflect.Type, depth int) map[string]interface{} {
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": make(map[string]interface{}),
+	}
+
+	required := make([]string, 0)
+	properties := schema["properties"].(map[string]interface{})
+
+	for inputType.Kind() == reflect.Ptr {
+		inputType = inputType.Elem()
+	}
+
+	if inputType.Kind() != reflect.Struct || depth > maxSchemaDepth {
+		return schema
+	}
+
+	for i := 0; i < inputType.NumField(); i++ {
+		field := inputType.Field(i)
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "" || jsonTag == "-" {
+			continue
+		}
+
+		// Parse json tag (might have options like "name,omitempty")
+		fieldName := strings.Split(jsonTag, ",")[0]
+
+		fieldSchema, isRequired := buildFieldSchema(field, depth+1)
+		if isRequired {
+			required = append(required, fieldName)
+		}
+
+		properties[fieldName] = fieldSchema
+	}
+
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return schema
+}
+
+// buildFieldSchema builds the OpenAPI schema for a struct field, applying
+// constraints from its validate tag, and reports whether the field is required
+func buildFieldSchema(field reflect.StructField, depth int) (map[string]interface{}, bool) {
+	fieldSchema := buildTypeSchema(field.Type, depth)
+	if field.Type.Kind() == reflect.Ptr {
+		fieldSchema["nullable"] = true
+	}
+
+	// Rules after "dive" apply to the elements of a slice, array, or map
+	rules := strings.Split(field.Tag.Get("validate"), ",")
+	var elementRules []string
+	for i, rule := range rules {
+		if rule == "dive" {
+			rules, elementRules = rules[:i], rules[i+1:]
+			break
+		}
+	}
+
+	required := false
+	for _, rule := range rules {
+		if rule == "required" {
+			required = true
+		}
+	}
+
+	applyValidateRules(fieldSchema, field.Type, rules)
+	if len(elementRules) > 0 {
+		elemType := field.Type
+		for elemType.Kind() == reflect.Ptr {
+			elemType = elemType.Elem()
+		}
+		if elemSchema, ok := fieldSchema["items"].(map[string]interface{}); ok {
+			applyValidateRules(elemSchema, elemType.Elem(), elementRules)
+		} else if elemSchema, ok := fieldSchema["additionalProperties"].(map[string]interface{}); ok {
+			applyValidateRules(elemSchema, elemType.Elem(), elementRules)
+		}
+	}
+
+	return fieldSchema, required
+}
+
+// validateFormats maps validate tags to OpenAPI string formats
+var validateFormats = map[string]string{
+	"email":    "email",
+	"url":      "uri",
+	"uri":      "uri",
+	"uuid":     "uuid",
+	"uuid4":    "uuid",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+	"hostname": "hostname",
+}
+
+// validatePatterns maps validate tags to equivalent regular expressions
+var validatePatterns = map[string]string{
+	"alpha":       `^[a-zA-Z]+$`,
+	"alphanum":    `^[a-zA-Z0-9]+$`,
+	"numeric":     `^[-+]?[0-9]+(?:\.[0-9]+)?$`,
+	"number":      `^[0-9]+$`,
+	"hexadecimal": `^(0[xX])?[0-9a-fA-F]+$`,
+	"lowercase":   `^[^A-Z]*$`,
+	"uppercase":   `^[^a-z]*$`,
+}
+
+// applyValidateRules translates validate tag rules (e.g., "min=3", "oneof=a b")
+// into JSON Schema keywords appropriate for the field's type
+func applyValidateRules(schema map[string]interface{}, t reflect.Type, rules []string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	for _, rule := range rules {
+		name, value, _ := strings.Cut(rule, "=")
+
+		if format, ok := validateFormats[name]; ok && t.Kind() == reflect.String {
+			schema["format"] = format
+			continue
+		}
+		if pattern, ok := validatePatterns[name]; ok && t.Kind() == reflect.String {
+			schema["pattern"] = pattern
+			continue
+		}
+
+		switch name {
+		case "min", "gte":
+			setLowerBound(schema, t, value, false)
+		case "gt":
+			setLowerBound(schema, t, value, true)
+		case "max", "lte":
+			setUpperBound(schema, t, value, false)
+		case "lt":
+			setUpperBound(schema, t, value, true)
+		case "len":
+			setLowerBound(schema, t, value, false)
+			setUpperBound(schema, t, value, false)
+		case "oneof":
+			var enum []interface{}
+			for _, option := range strings.Fields(value) {
+				if typed, ok := parseSchemaValue(t, option); ok {
+					enum = append(enum, typed)
+				}
+			}
+			if len(enum) > 0 {
+				schema["enum"] = enum
+			}
+		case "startswith":
+			schema["pattern"] = "^" + regexp.QuoteMeta(value)
+		case "endswith":
+			schema["pattern"] = regexp.QuoteMeta(value) + "$"
+		case "contains":
+			schema["pattern"] = regexp.QuoteMeta(value)
+		}
+	}
+}
+
+// lengthKeywords returns the min/max keywords used to bound a type's length,
+// or empty strings if the type is bounded by value instead
+func lengthKeywords(t reflect.Type) (string, string) {
+	switch t.Kind() {
+	case reflect.String:
+		return "minLength", "maxLength"
+	case reflect.Array, reflect.Slice:
+		return "minItems", "maxItems"
+	case reflect.Map:
+		return "minProperties", "maxProperties"
+	default:
+		return "", ""
+	}
+}
+
+// setLowerBound sets minLength/minItems/minimum from a validate rule value
+func setLowerBound(schema map[string]interface{}, t reflect.Type, value string, exclusive bool) {
+	if minKeyword, _ := lengthKeywords(t); minKeyword != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			if exclusive {
+				n++
+			}
+			schema[minKeyword] = n
+		}
+		return
+	}
+
+	if jsonType := getJSONType(t); jsonType != "integer" && jsonType != "number" {
+		return
+	}
+	if n, ok := parseSchemaValue(t, value); ok {
+		schema["minimum"] = n
+		if exclusive {
+			schema["exclusiveMinimum"] = true
+		}
+	}
+}
+
+// setUpperBound sets maxLength/maxItems/maximum from a validate rule value
+func setUpperBound(schema map[string]interface{}, t reflect.Type, value string, exclusive bool) {
+	if _, maxKeyword := lengthKeywords(t); maxKeyword != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			if exclusive {
+				n--
+			}
+			schema[maxKeyword] = n
+		}
+		return
+	}
+
+	if jsonType := getJSONType(t); jsonType != "integer" && jsonType != "number" {
+		return
+	}
+	if n, ok := parseSchemaValue(t, value); ok {
+		schema["maximum"] = n
+		if exclusive {
+			schema["exclusiveMaximum"] = true
+		}
+	}
+}
+
+// parseSchemaValue parses a validate rule value as the field's JSON type
+func parseSchemaValue(t reflect.Type, value string) (interface{}, bool) {
+	switch getJSONType(t) {
+	case "integer":
+		n, err := strconv.ParseInt(value, 10, 64)
+		return n, err == nil
+	case "number":
+		n, err := strconv.ParseFloat(value, 64)
+		return n, err == nil
+	case "boolean":
+		b, err := strconv.ParseBool(value)
+		return b, err == nil
+	default:
+		return value, true
+	}
+}
+
+// QueryParameters builds `in: query` parameters from an input struct,
+// skipping fields that are already supplied as path parameters
+func QueryParameters(input interface{}, pathParams []map[string]interface{}) []map[string]interface{} {
+	inputType := reflect.TypeOf(input)
+	for inputType.Kind() == reflect.Ptr {
+		inputType = inputType.Elem()
+	}
+	if inputType.Kind() != reflect.Struct {
+		return nil
+	}
+
+	inPath := make(map[string]bool, len(pathParams))
+	for _, param := range pathParams {
+		inPath[param["name"].(string)] = true
+	}
+
+	params := make([]map[string]interface{}, 0, inputType.NumField())
+	for i := 0; i < inputType.NumField(); i++ {
+		field := inputType.Field(i)
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "" || jsonTag == "-" {
+			continue
+		}
+
+		fieldName := strings.Split(jsonTag, ",")[0]
+		if inPath[fieldName] {
+			continue
+		}
+
+		fieldSchema, required := buildFieldSchema(field, 1)
+		param := map[string]interface{}{
+			"name":     fieldName,
+			"in":       "query",
+			"required": required,
+			"schema":   fieldSchema,
+		}
+		if fieldSchema["type"] == "array" {
+			// Repeated keys (?tag=a&tag=b) are collected into a list
+			param["style"] = "form"
+			param["explode"] = true
+		}
+		params = append(params, param)
+	}
+
+	return params
+}
+
+// buildTypeSchema builds the OpenAPI schema for a single Go type
+func buildTypeSchema(t reflect.Type, depth int) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == reflect.TypeOf(time.Time{}) {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	schema := map[string]interface{}{
+		"type": getJSONType(t),
+	}
+	if depth > maxSchemaDepth {
+		return schema
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return buildObjectSchema(t, depth)
+	case reflect.Array, reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// []byte is encoded as a base64 string
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		schema["items"] = buildTypeSchema(t.Elem(), depth+1)
+	case reflect.Map:
+		if t.Elem().Kind() != reflect.Interface {
+			schema["additionalProperties"] = buildTypeSchema(t.Elem(), depth+1)
+		}
+	}
+
+	return schema
+}
+
+// getJSONType converts Go type to JSON schema type
+func getJSONType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Array, reflect.Slice:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	default:
+		return "string"
+	}
+}
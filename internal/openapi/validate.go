@@ -0,0 +1,283 @@
+package openapi
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ValidationError describes a request value that does not match its schema
+type ValidationError struct {
+	Pointer string      `json:"pointer"` // JSON pointer to the invalid value (e.g., "/tags/0")
+	Rule    string      `json:"rule"`    // Schema keyword that failed (e.g., "minLength")
+	Message string      `json:"message"`
+	Limit   interface{} `json:"-"` // The keyword's value (e.g., 3 for minLength), for translating Message
+}
+
+// Error implements the error interface
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pointer, e.Message)
+}
+
+// Validate checks request params against a schema built by SchemaFromStruct and
+// returns every mismatch. Query, path, and form values always arrive as strings,
+// so strings are accepted for numeric and boolean schemas when they parse.
+func Validate(schema map[string]interface{}, params map[string]interface{}) []ValidationError {
+	var errs []ValidationError
+	validateValue(schema, params, "", &errs)
+	return errs
+}
+
+// validateValue validates a single value, appending any errors
+func validateValue(schema map[string]interface{}, value interface{}, pointer string, errs *[]ValidationError) {
+	add := func(rule string, limit interface{}, format string) {
+		p := pointer
+		if p == "" {
+			p = "/"
+		}
+		message := format
+		if limit != nil {
+			message = fmt.Sprintf(format, limit)
+		}
+		*errs = append(*errs, ValidationError{Pointer: p, Rule: rule, Message: message, Limit: limit})
+	}
+
+	if value == nil {
+		if nullable, _ := schema["nullable"].(bool); !nullable && pointer != "" {
+			add("nullable", nil, "must not be null")
+		}
+		return
+	}
+
+	schemaType, _ := schema["type"].(string)
+	value, ok := coerce(schemaType, value)
+	if !ok {
+		add("type", schemaType, "must be of type %s")
+		return
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && !inEnum(enum, value) {
+		add("enum", enum, "must be one of %v")
+	}
+
+	switch v := value.(type) {
+	case string:
+		validateString(schema, v, add)
+	case float64:
+		validateNumber(schema, v, add)
+	case []interface{}:
+		if n, ok := schema["minItems"].(int); ok && len(v) < n {
+			add("minItems", n, "must have at least %d items")
+		}
+		if n, ok := schema["maxItems"].(int); ok && len(v) > n {
+			add("maxItems", n, "must have at most %d items")
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				validateValue(items, item, pointer+"/"+strconv.Itoa(i), errs)
+			}
+		}
+	case map[string]interface{}:
+		validateObject(schema, v, pointer, add, errs)
+	}
+}
+
+// validateObject validates required properties and each property's value
+func validateObject(schema map[string]interface{}, obj map[string]interface{}, pointer string,
+	add func(string, interface{}, string), errs *[]ValidationError) {
+	if n, ok := schema["minProperties"].(int); ok && len(obj) < n {
+		add("minProperties", n, "must have at least %d properties")
+	}
+	if n, ok := schema["maxProperties"].(int); ok && len(obj) > n {
+		add("maxProperties", n, "must have at most %d properties")
+	}
+
+	if required, ok := schema["required"].([]string); ok {
+		for _, name := range required {
+			if _, ok := obj[name]; !ok {
+				*errs = append(*errs, ValidationError{Pointer: pointer + "/" + escapePointer(name), Rule: "required", Message: "is required"})
+			}
+		}
+	}
+
+	// Iterate in key order so errors are reported deterministically
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	additional, _ := schema["additionalProperties"].(map[string]interface{})
+	for _, key := range keys {
+		propertySchema, ok := properties[key].(map[string]interface{})
+		if !ok {
+			propertySchema = additional
+		}
+		if propertySchema != nil {
+			validateValue(propertySchema, obj[key], pointer+"/"+escapePointer(key), errs)
+		}
+	}
+}
+
+// validateString applies string keywords (length, pattern, format)
+func validateString(schema map[string]interface{}, s string, add func(string, interface{}, string)) {
+	length := len([]rune(s))
+	if n, ok := schema["minLength"].(int); ok && length < n {
+		add("minLength", n, "must be at least %d characters")
+	}
+	if n, ok := schema["maxLength"].(int); ok && length > n {
+		add("maxLength", n, "must be at most %d characters")
+	}
+
+	if pattern, ok := schema["pattern"].(string); ok {
+		if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(s) {
+			add("pattern", pattern, "must match pattern %s")
+		}
+	}
+
+	if format, ok := schema["format"].(string); ok && !matchesFormat(format, s) {
+		add("format", format, "must be a valid %s")
+	}
+}
+
+// validateNumber applies numeric bounds, including 3.0-style boolean exclusive flags
+func validateNumber(schema map[string]interface{}, n float64, add func(string, interface{}, string)) {
+	if minimum, ok := toFloat(schema["minimum"]); ok {
+		if exclusive, _ := schema["exclusiveMinimum"].(bool); exclusive && n <= minimum {
+			add("exclusiveMinimum", schema["minimum"], "must be greater than %v")
+		} else if n < minimum {
+			add("minimum", schema["minimum"], "must be at least %v")
+		}
+	}
+	if maximum, ok := toFloat(schema["maximum"]); ok {
+		if exclusive, _ := schema["exclusiveMaximum"].(bool); exclusive && n >= maximum {
+			add("exclusiveMaximum", schema["maximum"], "must be less than %v")
+		} else if n > maximum {
+			add("maximum", schema["maximum"], "must be at most %v")
+		}
+	}
+}
+
+// coerce converts a decoded value to the Go type used for the schema type,
+// reporting whether the value is compatible
+func coerce(schemaType string, value interface{}) (interface{}, bool) {
+	switch schemaType {
+	case "string":
+		s, ok := value.(string)
+		return s, ok
+	case "integer", "number":
+		n, ok := toFloat(value)
+		if !ok {
+			if s, isString := value.(string); isString {
+				parsed, err := strconv.ParseFloat(s, 64)
+				n, ok = parsed, err == nil
+			}
+		}
+		if ok && schemaType == "integer" && n != float64(int64(n)) {
+			return nil, false
+		}
+		return n, ok
+	case "boolean":
+		switch v := value.(type) {
+		case bool:
+			return v, true
+		case string:
+			b, err := strconv.ParseBool(v)
+			return b, err == nil
+		}
+		return nil, false
+	case "array":
+		switch v := value.(type) {
+		case []interface{}:
+			return v, true
+		case []string:
+			items := make([]interface{}, len(v))
+			for i, item := range v {
+				items[i] = item
+			}
+			return items, true
+		case string:
+			// A single repeated query key arrives as a plain string
+			return []interface{}{v}, true
+		}
+		return nil, false
+	case "object":
+		v, ok := value.(map[string]interface{})
+		return v, ok
+	default:
+		return value, true
+	}
+}
+
+// toFloat converts any numeric value to float64
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// inEnum returns whether the value equals one of the enum options
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, option := range enum {
+		if n, ok := toFloat(option); ok {
+			if v, ok := value.(float64); ok && v == n {
+				return true
+			}
+			continue
+		}
+		if option == value {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesFormat checks the string formats produced by SchemaFromStruct
+func matchesFormat(format, s string) bool {
+	switch format {
+	case "email":
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	case "uri":
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != "" && (u.Host != "" || u.Opaque != "")
+	case "uuid":
+		return uuid.Validate(s) == nil
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	case "ipv4":
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() != nil
+	case "ipv6":
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() == nil
+	default:
+		return true
+	}
+}
+
+// escapePointer escapes a key for use in a JSON pointer (RFC 6901)
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
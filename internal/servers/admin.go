@@ -0,0 +1,301 @@
+package servers
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	_ "embed"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/evantahler/go-actionhero/internal/config"
+)
+
+// adminErrorLimit is how many recent errors the admin dashboard keeps
+const adminErrorLimit = 100
+
+// adminFailedTaskLimit is how many failed tasks the admin dashboard lists
+const adminFailedTaskLimit = 100
+
+//go:embed admin/index.html
+var adminPage []byte
+
+// adminError is a reported error, as listed by the admin dashboard
+type adminError struct {
+	Time         time.Time `json:"time"`
+	Type         string    `json:"type"`
+	Source       string    `json:"source"`
+	Action       string    `json:"action,omitempty"`
+	ConnectionID string    `json:"connectionId,omitempty"`
+	RequestID    string    `json:"requestId,omitempty"`
+	Message      string    `json:"message"`
+}
+
+// errorLog keeps the most recent reported errors, oldest first
+type errorLog struct {
+	mu      sync.Mutex
+	entries []adminError
+	next    int // Where the next entry goes once the log is full
+}
+
+// report is an api.ErrorReporter that records the error
+func (l *errorLog) report(ctx context.Context, report api.ErrorReport) {
+	entry := adminError{
+		Time:      time.Now(),
+		Type:      string(report.Type),
+		Source:    report.Source,
+		Action:    report.Action,
+		RequestID: report.RequestID,
+	}
+	if report.Error != nil {
+		entry.Message = report.Error.Error()
+	}
+	if report.Connection != nil {
+		entry.ConnectionID = report.Connection.ID
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.entries) < adminErrorLimit {
+		l.entries = append(l.entries, entry)
+		return
+	}
+	l.entries[l.next] = entry
+	l.next = (l.next + 1) % adminErrorLimit
+}
+
+// recent returns the recorded errors, newest first
+func (l *errorLog) recent() []adminError {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	recent := make([]adminError, 0, len(l.entries))
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		recent = append(recent, l.entries[(l.next+i)%len(l.entries)])
+	}
+	return recent
+}
+
+// adminAction is an action and its route, as listed by the admin dashboard
+type adminAction struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Method      string `json:"method,omitempty"`
+	Route       string `json:"route,omitempty"`
+	Queue       string `json:"queue,omitempty"`
+	Deprecated  bool   `json:"deprecated,omitempty"`
+}
+
+// adminConnection is an open WebSocket connection, as listed by the admin
+// dashboard
+type adminConnection struct {
+	ID         string   `json:"id"`
+	Type       string   `json:"type"`
+	RemoteIP   string   `json:"remoteIp"`
+	UserAgent  string   `json:"userAgent,omitempty"`
+	Country    string   `json:"country,omitempty"`
+	Channels   []string `json:"channels"`
+	IdleFor    string   `json:"idleFor"`
+	HasSession bool     `json:"hasSession"`
+}
+
+// newAdminHandler returns the admin dashboard, served under route: the page
+// itself and the JSON endpoints it reads, behind HTTP basic auth
+func (ws *WebServer) newAdminHandler(route string) http.Handler {
+	route = strings.TrimSuffix(route, "/")
+	mux := http.NewServeMux()
+
+	mux.HandleFunc(route, func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, route+"/", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc(route+"/{$}", ws.handleAdminPage)
+	mux.HandleFunc("GET "+route+"/api/overview", ws.handleAdminOverview)
+	mux.HandleFunc("GET "+route+"/api/actions", ws.handleAdminActions)
+	mux.HandleFunc("GET "+route+"/api/connections", ws.handleAdminConnections)
+	mux.HandleFunc("GET "+route+"/api/config", ws.handleAdminConfig)
+	mux.HandleFunc("GET "+route+"/api/errors", ws.handleAdminErrors)
+	mux.HandleFunc("GET "+route+"/api/tasks", ws.handleAdminTasks)
+	mux.HandleFunc("POST "+route+"/api/tasks/retry", ws.handleAdminRetryTask)
+
+	return ws.adminAuth(mux)
+}
+
+// adminAuth requires the admin user and password, and rejects cross-site
+// requests that change anything
+func (ws *WebServer) adminAuth(next http.Handler) http.Handler {
+	wantUser := sha256.Sum256([]byte(ws.config.AdminUser))
+	wantPassword := sha256.Sum256([]byte(ws.config.AdminPassword))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		gotUser := sha256.Sum256([]byte(user))
+		gotPassword := sha256.Sum256([]byte(password))
+		userOK := subtle.ConstantTimeCompare(gotUser[:], wantUser[:]) == 1
+		passwordOK := subtle.ConstantTimeCompare(gotPassword[:], wantPassword[:]) == 1
+		if !ok || !userOK || !passwordOK {
+			w.Header().Set("WWW-Authenticate", `Basic realm="actionhero admin", charset="UTF-8"`)
+			ws.sendError(w, http.StatusUnauthorized, "UNAUTHORIZED", "admin credentials are required", "")
+			return
+		}
+
+		if r.Method != http.MethodGet && r.Method != http.MethodHead && !isSameOrigin(r) {
+			ws.sendError(w, http.StatusForbidden, "CROSS_ORIGIN_REQUEST", "cross-origin admin requests are not allowed", "")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isSameOrigin returns whether a request came from a page of the same host.
+// Requests without an Origin header (e.g., from curl) are accepted.
+func isSameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+// handleAdminPage serves the dashboard page
+func (ws *WebServer) handleAdminPage(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Security-Policy", "default-src 'self'; script-src 'unsafe-inline'; style-src 'unsafe-inline'")
+	_, _ = w.Write(adminPage)
+}
+
+// handleAdminOverview reports on the process, its connections, and its
+// per-action metrics
+func (ws *WebServer) handleAdminOverview(w http.ResponseWriter, _ *http.Request) {
+	startedAt := ws.api.StartedAt()
+	uptime := time.Duration(0)
+	if !startedAt.IsZero() {
+		uptime = time.Since(startedAt).Round(time.Second)
+	}
+
+	ws.sendSuccess(w, map[string]interface{}{
+		"process":      ws.api.Config.Process.Name,
+		"environment":  config.Environment(),
+		"startedAt":    startedAt,
+		"uptime":       uptime.String(),
+		"addresses":    ws.Addresses(),
+		"actions":      len(ws.api.GetActionDescriptors()),
+		"connections":  ws.ConnectionCount(),
+		"metrics":      ws.api.Metrics.Snapshot(),
+		"http":         ws.api.HTTPMetrics.Snapshot(),
+		"breakers":     ws.api.BreakerStats(),
+		"uptimeChecks": ws.api.UptimeChecks(),
+	})
+}
+
+// handleAdminActions lists the registered actions and their routes
+func (ws *WebServer) handleAdminActions(w http.ResponseWriter, _ *http.Request) {
+	descriptors := ws.api.GetActionDescriptors()
+	actions := make([]adminAction, 0, len(descriptors))
+	for _, descriptor := range descriptors {
+		action := adminAction{
+			Name:        descriptor.Name,
+			Description: descriptor.Description,
+			Deprecated:  descriptor.Deprecation != nil,
+		}
+		if descriptor.Web != nil {
+			action.Method = string(descriptor.Web.Method)
+			action.Route = ws.config.APIRoute + descriptor.Web.Route
+		}
+		if descriptor.Task != nil {
+			action.Queue = descriptor.Task.Queue
+		}
+		actions = append(actions, action)
+	}
+	sort.Slice(actions, func(i, j int) bool { return actions[i].Name < actions[j].Name })
+
+	ws.sendSuccess(w, actions)
+}
+
+// handleAdminConnections lists the open WebSocket connections
+func (ws *WebServer) handleAdminConnections(w http.ResponseWriter, _ *http.Request) {
+	ws.connectionsMu.RLock()
+	connections := make([]adminConnection, 0, len(ws.connections))
+	for _, wsConn := range ws.connections {
+		conn := wsConn.connection
+		info := conn.ClientInfo()
+		connections = append(connections, adminConnection{
+			ID:         conn.ID,
+			Type:       conn.Type,
+			RemoteIP:   info.RemoteIP,
+			UserAgent:  info.UserAgent,
+			Country:    info.Location.Country,
+			Channels:   conn.Channels(),
+			IdleFor:    conn.IdleFor().Round(time.Second).String(),
+			HasSession: conn.IsSessionLoaded(),
+		})
+	}
+	ws.connectionsMu.RUnlock()
+	sort.Slice(connections, func(i, j int) bool { return connections[i].ID < connections[j].ID })
+
+	ws.sendSuccess(w, connections)
+}
+
+// handleAdminConfig lists the settings and where they came from, with
+// secrets masked
+func (ws *WebServer) handleAdminConfig(w http.ResponseWriter, _ *http.Request) {
+	ws.sendSuccess(w, ws.api.CurrentConfig().MaskedValues())
+}
+
+// handleAdminErrors lists the most recent reported errors
+func (ws *WebServer) handleAdminErrors(w http.ResponseWriter, _ *http.Request) {
+	ws.sendSuccess(w, ws.errors.recent())
+}
+
+// handleAdminTasks lists the task queues and failed tasks, when a task
+// backend provides api.TaskQueues
+func (ws *WebServer) handleAdminTasks(w http.ResponseWriter, r *http.Request) {
+	queues, ok := api.Lookup[api.TaskQueues](ws.api)
+	if !ok {
+		ws.sendSuccess(w, map[string]interface{}{"enabled": false})
+		return
+	}
+
+	list, err := queues.Queues(r.Context())
+	if err != nil {
+		ws.sendError(w, http.StatusBadGateway, "TASK_QUEUES_UNAVAILABLE", err.Error(), "")
+		return
+	}
+	failed, err := queues.FailedTasks(r.Context(), adminFailedTaskLimit)
+	if err != nil {
+		ws.sendError(w, http.StatusBadGateway, "TASK_QUEUES_UNAVAILABLE", err.Error(), "")
+		return
+	}
+	ws.sendSuccess(w, map[string]interface{}{
+		"enabled": true,
+		"queues":  list,
+		"failed":  failed,
+	})
+}
+
+// handleAdminRetryTask retries the failed task given by the id param
+func (ws *WebServer) handleAdminRetryTask(w http.ResponseWriter, r *http.Request) {
+	queues, ok := api.Lookup[api.TaskQueues](ws.api)
+	if !ok {
+		ws.sendError(w, http.StatusNotFound, "TASK_QUEUES_DISABLED", "no task backend is configured", "")
+		return
+	}
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		ws.sendError(w, http.StatusBadRequest, "MISSING_TASK_ID", "the id param is required", "")
+		return
+	}
+
+	if err := queues.RetryTask(r.Context(), id); err != nil {
+		ws.sendError(w, http.StatusBadGateway, "TASK_RETRY_FAILED", err.Error(), "")
+		return
+	}
+	ws.logger.Infof("Admin retried task %s", id)
+	ws.sendSuccess(w, map[string]interface{}{"retried": id})
+}
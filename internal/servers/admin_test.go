@@ -0,0 +1,295 @@
+package servers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+)
+
+// fakeTaskQueues is an api.TaskQueues with one failed task
+type fakeTaskQueues struct {
+	retried []string
+}
+
+func (q *fakeTaskQueues) Queues(_ context.Context) ([]api.TaskQueue, error) {
+	return []api.TaskQueue{{Name: "default", Pending: 2, Failed: 1}}, nil
+}
+
+func (q *fakeTaskQueues) FailedTasks(_ context.Context, _ int) ([]api.FailedTask, error) {
+	return []api.FailedTask{{ID: "task-1", Queue: "default", Task: "email:send", Error: "smtp down"}}, nil
+}
+
+func (q *fakeTaskQueues) RetryTask(_ context.Context, id string) error {
+	if id != "task-1" {
+		return errors.New("no such task")
+	}
+	q.retried = append(q.retried, id)
+	return nil
+}
+
+// setupAdminServer returns an initialized web server with the admin
+// dashboard at /admin, for the user admin with the password "hunter2"
+func setupAdminServer(t *testing.T, actions ...api.Action) (*WebServer, *api.API) {
+	ws, apiInstance := setupTestServer(t)
+	ws.config.AdminEnabled = true
+	ws.config.AdminRoute = "/admin"
+	ws.config.AdminUser = "admin"
+	ws.config.AdminPassword = "hunter2"
+	apiInstance.Config.Server.Web = ws.config
+
+	for _, action := range actions {
+		if err := apiInstance.RegisterAction(action); err != nil {
+			t.Fatalf("Failed to register action: %v", err)
+		}
+	}
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+	return ws, apiInstance
+}
+
+// adminRequest sends a request to the admin dashboard as the admin user
+func adminRequest(ws *WebServer, method, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.SetBasicAuth("admin", "hunter2")
+	w := httptest.NewRecorder()
+	ws.server.Handler.ServeHTTP(w, req)
+	return w
+}
+
+// decodeAdminData decodes the data of a successful admin response
+func decodeAdminData(t *testing.T, w *httptest.ResponseRecorder, data interface{}) {
+	t.Helper()
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var body struct {
+		Success bool            `json:"success"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if err := json.Unmarshal(body.Data, data); err != nil {
+		t.Fatalf("Failed to decode data: %v", err)
+	}
+}
+
+func TestAdmin_RequiresCredentials(t *testing.T) {
+	ws, _ := setupAdminServer(t)
+
+	tests := []struct {
+		name     string
+		user     string
+		password string
+		status   int
+	}{
+		{"no credentials", "", "", http.StatusUnauthorized},
+		{"wrong password", "admin", "hunter3", http.StatusUnauthorized},
+		{"wrong user", "root", "hunter2", http.StatusUnauthorized},
+		{"admin", "admin", "hunter2", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/admin/api/overview", nil)
+			if tt.user != "" {
+				req.SetBasicAuth(tt.user, tt.password)
+			}
+			w := httptest.NewRecorder()
+			ws.server.Handler.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, w.Code)
+			}
+			if tt.status == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+				t.Error("Expected a WWW-Authenticate header")
+			}
+		})
+	}
+}
+
+func TestAdmin_ServesPage(t *testing.T) {
+	ws, _ := setupAdminServer(t)
+
+	w := adminRequest(ws, "GET", "/admin")
+	if w.Code != http.StatusMovedPermanently || w.Header().Get("Location") != "/admin/" {
+		t.Errorf("Expected a redirect to /admin/, got %d %q", w.Code, w.Header().Get("Location"))
+	}
+
+	w = adminRequest(ws, "GET", "/admin/")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") {
+		t.Errorf("Expected an HTML page, got %q", w.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(w.Body.String(), "actionhero admin") {
+		t.Error("Expected the dashboard page")
+	}
+}
+
+func TestAdmin_Disabled(t *testing.T) {
+	ws, _ := setupTestServer(t)
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	w := adminRequest(ws, "GET", "/admin/api/overview")
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 without the dashboard, got %d", w.Code)
+	}
+}
+
+func TestAdmin_ListsActions(t *testing.T) {
+	ws, _ := setupAdminServer(t, newTestAction("user:view", "/users/:id", api.HTTPMethodGET, nil, nil))
+
+	var actions []adminAction
+	decodeAdminData(t, adminRequest(ws, "GET", "/admin/api/actions"), &actions)
+	if len(actions) != 1 {
+		t.Fatalf("Expected 1 action, got %d", len(actions))
+	}
+	if actions[0].Name != "user:view" || actions[0].Method != "GET" || actions[0].Route != "/api/users/:id" {
+		t.Errorf("Unexpected action: %+v", actions[0])
+	}
+}
+
+func TestAdmin_MasksConfig(t *testing.T) {
+	ws, _ := setupAdminServer(t)
+
+	var values map[string]struct {
+		Value interface{} `json:"value"`
+	}
+	decodeAdminData(t, adminRequest(ws, "GET", "/admin/api/config"), &values)
+	password, ok := values["server.web.adminpassword"]
+	if !ok {
+		t.Fatal("Expected server.web.adminpassword to be listed")
+	}
+	if password.Value != "*******" {
+		t.Errorf("Expected the admin password to be masked, got %v", password.Value)
+	}
+}
+
+func TestAdmin_ListsRecentErrors(t *testing.T) {
+	ws, _ := setupAdminServer(t, newTestAction("test:fail", "/fail", api.HTTPMethodGET, nil, errors.New("boom")))
+
+	req := httptest.NewRequest("GET", "/api/fail", nil)
+	ws.server.Handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	var recent []adminError
+	decodeAdminData(t, adminRequest(ws, "GET", "/admin/api/errors"), &recent)
+	if len(recent) != 1 {
+		t.Fatalf("Expected 1 error, got %d", len(recent))
+	}
+	if recent[0].Action != "test:fail" || recent[0].Message != "boom" {
+		t.Errorf("Unexpected error: %+v", recent[0])
+	}
+}
+
+func TestErrorLog_KeepsMostRecent(t *testing.T) {
+	log := &errorLog{}
+	for i := 0; i < adminErrorLimit+5; i++ {
+		log.report(context.Background(), api.ErrorReport{Error: fmt.Errorf("error %d", i)})
+	}
+
+	recent := log.recent()
+	if len(recent) != adminErrorLimit {
+		t.Fatalf("Expected %d errors, got %d", adminErrorLimit, len(recent))
+	}
+	if recent[0].Message != fmt.Sprintf("error %d", adminErrorLimit+4) {
+		t.Errorf("Expected the newest error first, got %q", recent[0].Message)
+	}
+	if recent[len(recent)-1].Message != "error 5" {
+		t.Errorf("Expected the oldest kept error last, got %q", recent[len(recent)-1].Message)
+	}
+}
+
+func TestAdmin_Tasks(t *testing.T) {
+	ws, apiInstance := setupAdminServer(t)
+
+	var disabled map[string]interface{}
+	decodeAdminData(t, adminRequest(ws, "GET", "/admin/api/tasks"), &disabled)
+	if disabled["enabled"] != false {
+		t.Errorf("Expected tasks to be disabled without a backend, got %v", disabled)
+	}
+
+	queues := &fakeTaskQueues{}
+	api.Provide[api.TaskQueues](apiInstance, queues)
+
+	var tasks struct {
+		Enabled bool             `json:"enabled"`
+		Queues  []api.TaskQueue  `json:"queues"`
+		Failed  []api.FailedTask `json:"failed"`
+	}
+	decodeAdminData(t, adminRequest(ws, "GET", "/admin/api/tasks"), &tasks)
+	if !tasks.Enabled || len(tasks.Queues) != 1 || len(tasks.Failed) != 1 {
+		t.Fatalf("Unexpected tasks: %+v", tasks)
+	}
+
+	w := adminRequest(ws, "POST", "/admin/api/tasks/retry?id=task-1")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(queues.retried) != 1 || queues.retried[0] != "task-1" {
+		t.Errorf("Expected task-1 to be retried, got %v", queues.retried)
+	}
+
+	if w := adminRequest(ws, "POST", "/admin/api/tasks/retry?id=task-2"); w.Code != http.StatusBadGateway {
+		t.Errorf("Expected status 502 for an unknown task, got %d", w.Code)
+	}
+	if w := adminRequest(ws, "POST", "/admin/api/tasks/retry"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without an id, got %d", w.Code)
+	}
+}
+
+func TestAdmin_RejectsCrossOriginChanges(t *testing.T) {
+	ws, apiInstance := setupAdminServer(t)
+	queues := &fakeTaskQueues{}
+	api.Provide[api.TaskQueues](apiInstance, queues)
+
+	req := httptest.NewRequest("POST", "/admin/api/tasks/retry?id=task-1", nil)
+	req.SetBasicAuth("admin", "hunter2")
+	req.Header.Set("Origin", "https://evil.example.com")
+	w := httptest.NewRecorder()
+	ws.server.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403, got %d", w.Code)
+	}
+	if len(queues.retried) != 0 {
+		t.Errorf("Expected no retries, got %v", queues.retried)
+	}
+}
+
+func TestAdmin_AllowedIPs(t *testing.T) {
+	ws, _ := setupTestServer(t)
+	ws.config.AdminEnabled = true
+	ws.config.AdminRoute = "/admin"
+	ws.config.AdminUser = "admin"
+	ws.config.AdminPassword = "hunter2"
+	ws.config.AdminAllowedIPs = "127.0.0.1"
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	for _, tt := range []struct {
+		remoteAddr string
+		status     int
+	}{
+		{"127.0.0.1:5000", http.StatusOK},
+		{"10.2.3.4:5000", http.StatusForbidden},
+	} {
+		req := httptest.NewRequest("GET", "/admin/api/overview", nil)
+		req.SetBasicAuth("admin", "hunter2")
+		req.RemoteAddr = tt.remoteAddr
+		w := httptest.NewRecorder()
+		ws.server.Handler.ServeHTTP(w, req)
+		if w.Code != tt.status {
+			t.Errorf("From %s: expected status %d, got %d", tt.remoteAddr, tt.status, w.Code)
+		}
+	}
+}
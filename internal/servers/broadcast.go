@@ -0,0 +1,145 @@
+package servers
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"runtime"
+	"sync"
+)
+
+// broadcastQueueSize is how many messages each broadcast shard buffers
+const broadcastQueueSize = 256
+
+type broadcastMessage struct {
+	channel string
+	data    []byte
+}
+
+// broadcastShard indexes the subscribers of some of the channels and
+// delivers their broadcasts. Each channel always maps to the same shard, so
+// its messages are delivered in order, while channels on different shards
+// are delivered in parallel.
+type broadcastShard struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[*wsConnection]struct{} // channel -> subscribers
+	queue       chan broadcastMessage
+}
+
+// newBroadcastShards creates one shard per broadcast worker (0 = one per CPU)
+func newBroadcastShards(workers int) []*broadcastShard {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	shards := make([]*broadcastShard, workers)
+	for i := range shards {
+		shards[i] = &broadcastShard{
+			subscribers: make(map[string]map[*wsConnection]struct{}),
+			queue:       make(chan broadcastMessage, broadcastQueueSize),
+		}
+	}
+	return shards
+}
+
+// broadcastShardFor returns the shard that owns a channel
+func (ws *WebServer) broadcastShardFor(channel string) *broadcastShard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(channel))
+	return ws.broadcastShards[h.Sum32()%uint32(len(ws.broadcastShards))]
+}
+
+// subscribe adds a connection to a channel's subscribers
+func (ws *WebServer) subscribe(wsConn *wsConnection, channel string) {
+	wsConn.connection.Subscribe(channel)
+
+	shard := ws.broadcastShardFor(channel)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	if shard.subscribers[channel] == nil {
+		shard.subscribers[channel] = make(map[*wsConnection]struct{})
+	}
+	shard.subscribers[channel][wsConn] = struct{}{}
+}
+
+// unsubscribe removes a connection from a channel's subscribers
+func (ws *WebServer) unsubscribe(wsConn *wsConnection, channel string) {
+	wsConn.connection.Unsubscribe(channel)
+
+	shard := ws.broadcastShardFor(channel)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	delete(shard.subscribers[channel], wsConn)
+	if len(shard.subscribers[channel]) == 0 {
+		delete(shard.subscribers, channel)
+	}
+}
+
+// unsubscribeAll removes a closing connection from every channel. Once it
+// returns, no worker sends to the connection again.
+func (ws *WebServer) unsubscribeAll(wsConn *wsConnection) {
+	for _, channel := range wsConn.connection.Channels() {
+		ws.unsubscribe(wsConn, channel)
+	}
+}
+
+// subscriberCount returns how many connections are subscribed to a channel
+func (ws *WebServer) subscriberCount(channel string) int {
+	shard := ws.broadcastShardFor(channel)
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+	return len(shard.subscribers[channel])
+}
+
+// deliverBroadcasts sends a shard's queued messages to their channel's
+// subscribers, until the server stops
+func (ws *WebServer) deliverBroadcasts(shard *broadcastShard) {
+	defer ws.wg.Done()
+
+	for {
+		select {
+		case msg := <-shard.queue:
+			ws.fanOut(shard, msg)
+		case <-ws.ctx.Done():
+			return
+		}
+	}
+}
+
+// fanOut queues a message for each subscriber of its channel
+func (ws *WebServer) fanOut(shard *broadcastShard, msg broadcastMessage) {
+	// The read lock keeps connections from closing their send channel
+	// mid-delivery (see unsubscribeAll)
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+	for conn := range shard.subscribers[msg.channel] {
+		select {
+		case conn.send <- msg.data:
+		default:
+			// Channel full, skip this message
+			ws.logger.Warnf("Failed to send broadcast to connection %s (channel full)", conn.connection.ID)
+		}
+	}
+}
+
+// Broadcast sends a message to all connections subscribed to a channel
+func (ws *WebServer) Broadcast(channel string, data interface{}) error {
+	message := map[string]interface{}{
+		"type":    "broadcast",
+		"channel": channel,
+		"data":    data,
+	}
+
+	messageData, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal broadcast message: %w", err)
+	}
+
+	select {
+	case ws.broadcastShardFor(channel).queue <- broadcastMessage{channel: channel, data: messageData}:
+		return nil
+	case <-ws.ctx.Done():
+		return fmt.Errorf("server is shutting down")
+	default:
+		return fmt.Errorf("broadcast channel is full")
+	}
+}
@@ -0,0 +1,133 @@
+package servers
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+)
+
+// newTestWSConnection returns a connection that only buffers what is sent to it
+func newTestWSConnection(id string) *wsConnection {
+	return &wsConnection{
+		connection: api.NewConnection("websocket", "127.0.0.1", id, nil),
+		send:       make(chan []byte, 256),
+	}
+}
+
+func TestNewBroadcastShards(t *testing.T) {
+	if got := len(newBroadcastShards(0)); got != runtime.GOMAXPROCS(0) {
+		t.Errorf("Expected one shard per CPU, got %d", got)
+	}
+	if got := len(newBroadcastShards(3)); got != 3 {
+		t.Errorf("Expected 3 shards, got %d", got)
+	}
+}
+
+func TestWebServer_SubscriberIndex(t *testing.T) {
+	ws, _ := setupTestServer(t)
+	ws.broadcastShards = newBroadcastShards(4)
+	a, b := newTestWSConnection("a"), newTestWSConnection("b")
+
+	ws.subscribe(a, "news")
+	ws.subscribe(a, "alerts")
+	ws.subscribe(b, "news")
+	if got := ws.subscriberCount("news"); got != 2 {
+		t.Errorf("Expected 2 news subscribers, got %d", got)
+	}
+
+	ws.unsubscribe(b, "news")
+	if got := ws.subscriberCount("news"); got != 1 {
+		t.Errorf("Expected 1 news subscriber, got %d", got)
+	}
+	if b.connection.IsSubscribed("news") {
+		t.Error("Expected the connection to be unsubscribed too")
+	}
+
+	ws.unsubscribeAll(a)
+	if ws.subscriberCount("news") != 0 || ws.subscriberCount("alerts") != 0 {
+		t.Error("Expected a closed connection to leave every channel")
+	}
+	for _, shard := range ws.broadcastShards {
+		if len(shard.subscribers) != 0 {
+			t.Errorf("Expected empty channels to be dropped, got %v", shard.subscribers)
+		}
+	}
+}
+
+func TestWebServer_BroadcastFanOut(t *testing.T) {
+	ws, _ := setupTestServer(t)
+	ws.broadcastShards = newBroadcastShards(4)
+	for _, shard := range ws.broadcastShards {
+		ws.wg.Add(1)
+		go ws.deliverBroadcasts(shard)
+	}
+	defer func() {
+		ws.cancel()
+		ws.wg.Wait()
+	}()
+
+	subscriber, other := newTestWSConnection("subscriber"), newTestWSConnection("other")
+	ws.subscribe(subscriber, "room")
+	ws.subscribe(other, "lobby")
+
+	const count = 50
+	for i := 0; i < count; i++ {
+		if err := ws.Broadcast("room", i); err != nil {
+			t.Fatalf("Broadcast failed: %v", err)
+		}
+	}
+
+	// Messages on one channel arrive in order
+	for i := 0; i < count; i++ {
+		select {
+		case data := <-subscriber.send:
+			var msg map[string]interface{}
+			if err := json.Unmarshal(data, &msg); err != nil {
+				t.Fatalf("Failed to decode broadcast: %v", err)
+			}
+			if fmt.Sprint(msg["data"]) != fmt.Sprint(i) || msg["channel"] != "room" {
+				t.Fatalf("Expected message %d on room, got %v", i, msg)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for message %d", i)
+		}
+	}
+
+	select {
+	case data := <-other.send:
+		t.Errorf("Expected no message for another channel, got %s", data)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// recordingBroadcaster stands in for another api.Broadcaster
+type recordingBroadcaster struct{ channels []string }
+
+func (b *recordingBroadcaster) Broadcast(channel string, data interface{}) error {
+	b.channels = append(b.channels, channel)
+	return nil
+}
+
+func TestWebServer_ProvidesBroadcaster(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+	if broadcaster, ok := api.Lookup[api.Broadcaster](apiInstance); !ok || broadcaster != ws {
+		t.Errorf("Expected the web server to be the broadcaster, got %v", broadcaster)
+	}
+
+	ws, apiInstance = setupTestServer(t)
+	provided := &recordingBroadcaster{}
+	api.Provide[api.Broadcaster](apiInstance, provided)
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+	if broadcaster, _ := api.Lookup[api.Broadcaster](apiInstance); broadcaster != provided {
+		t.Error("Expected the provided broadcaster to be kept")
+	}
+}
@@ -0,0 +1,40 @@
+package servers
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+)
+
+// pprofPrefix is the path prefix net/http/pprof's Index handler expects
+const pprofPrefix = "/debug/pprof/"
+
+// newDebugHandler returns a handler serving pprof profiles under route+"/pprof/"
+// and runtime variables (memstats, cmdline, expvars) under route+"/vars"
+func newDebugHandler(route string) http.Handler {
+	route = strings.TrimSuffix(route, "/")
+	mux := http.NewServeMux()
+
+	mux.Handle(route+"/pprof/", rewritePprofPath(route+"/pprof/", http.HandlerFunc(pprof.Index)))
+	mux.HandleFunc(route+"/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc(route+"/pprof/profile", pprof.Profile)
+	mux.HandleFunc(route+"/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc(route+"/pprof/trace", pprof.Trace)
+	mux.Handle(route+"/vars", expvar.Handler())
+
+	return mux
+}
+
+// rewritePprofPath maps a custom route prefix onto the /debug/pprof/ prefix
+// that pprof.Index uses to find the requested profile
+func rewritePprofPath(prefix string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if prefix != pprofPrefix {
+			r2 := r.Clone(r.Context())
+			r2.URL.Path = pprofPrefix + strings.TrimPrefix(r.URL.Path, prefix)
+			r = r2
+		}
+		next.ServeHTTP(w, r)
+	})
+}
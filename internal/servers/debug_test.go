@@ -0,0 +1,83 @@
+package servers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDebugHandler_CustomRoute(t *testing.T) {
+	handler := newDebugHandler("/internal/debug")
+
+	tests := []struct {
+		name     string
+		path     string
+		contains string
+	}{
+		{"pprof index", "/internal/debug/pprof/", "goroutine"},
+		{"goroutine profile", "/internal/debug/pprof/goroutine?debug=1", "goroutine profile"},
+		{"cmdline", "/internal/debug/pprof/cmdline", ""},
+		{"vars", "/internal/debug/vars", "memstats"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d", w.Code)
+			}
+			if tt.contains != "" && !strings.Contains(w.Body.String(), tt.contains) {
+				t.Errorf("Expected body to contain %q", tt.contains)
+			}
+		})
+	}
+}
+
+func TestWebServer_DebugEndpoints(t *testing.T) {
+	ws, _ := setupTestServer(t)
+	ws.config.DebugEnabled = true
+	ws.config.DebugRoute = "/debug"
+	ws.config.DebugPort = 0
+
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/debug/vars", nil)
+	w := httptest.NewRecorder()
+	ws.server.Handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var vars map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&vars); err != nil {
+		t.Fatalf("Failed to decode vars: %v", err)
+	}
+	if vars["memstats"] == nil {
+		t.Error("Expected memstats in debug vars")
+	}
+}
+
+func TestWebServer_DebugEndpointsDisabled(t *testing.T) {
+	ws, _ := setupTestServer(t)
+
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/debug/pprof/", nil)
+	w := httptest.NewRecorder()
+	ws.server.Handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 when debug is disabled, got %d", w.Code)
+	}
+}
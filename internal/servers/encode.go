@@ -0,0 +1,72 @@
+package servers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// maxPooledBuffer is the largest buffer returned to the pool, so one large
+// response doesn't keep its memory alive
+const maxPooledBuffer = 64 << 10
+
+// jsonEncoder is a reusable buffer with an encoder writing to it
+type jsonEncoder struct {
+	buf bytes.Buffer
+	enc *json.Encoder
+}
+
+// encoderPool reuses response buffers and encoders across requests
+var encoderPool = sync.Pool{
+	New: func() interface{} {
+		e := &jsonEncoder{}
+		e.enc = json.NewEncoder(&e.buf)
+		return e
+	},
+}
+
+// successResponse is the body of a successful HTTP response
+type successResponse struct {
+	Data    interface{} `json:"data"`
+	Success bool        `json:"success"`
+}
+
+// errorResponse is the body of a failed HTTP response
+type errorResponse struct {
+	Error   util.ErrorJSON `json:"error"`
+	Success bool           `json:"success"`
+}
+
+// writeJSON encodes v with a pooled encoder and writes it with status. The
+// body is encoded before anything is written, so an encoding failure can
+// still be reported as a server error.
+func (ws *WebServer) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	e := encoderPool.Get().(*jsonEncoder)
+	defer func() {
+		if e.buf.Cap() <= maxPooledBuffer {
+			encoderPool.Put(e)
+		}
+	}()
+	e.buf.Reset()
+
+	if err := e.enc.Encode(v); err != nil {
+		ws.logger.Errorf("Error encoding response: %v", err)
+		e.buf.Reset()
+		_ = e.enc.Encode(errorResponse{Error: util.ErrorJSON{
+			Code:    string(util.ErrorTypeConnectionActionRun),
+			Message: "failed to encode response",
+		}})
+		status = http.StatusInternalServerError
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", strconv.Itoa(e.buf.Len()))
+	w.WriteHeader(status)
+	if _, err := w.Write(e.buf.Bytes()); err != nil {
+		ws.logger.Errorf("Error writing response: %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package servers
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+)
+
+// ipFilter accepts IPs that are in allow (or any IP, when allow is empty)
+// and not in deny
+type ipFilter struct {
+	allow []netip.Prefix
+	deny  []netip.Prefix
+}
+
+// allows returns whether requests from addr are accepted. When a list is
+// configured, requests without a parseable IP are rejected.
+func (f ipFilter) allows(addr netip.Addr) bool {
+	if !addr.IsValid() {
+		return len(f.allow) == 0 && len(f.deny) == 0
+	}
+	if containsIP(f.deny, addr) {
+		return false
+	}
+	return len(f.allow) == 0 || containsIP(f.allow, addr)
+}
+
+// containsIP returns whether addr is in any of the prefixes
+func containsIP(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ipRules are the IP filters of the web server: one for everything, and
+// ones for the metrics, debug, and admin endpoints, which may replace its
+// allowlist
+type ipRules struct {
+	global  ipFilter
+	metrics ipFilter
+	debug   ipFilter
+	admin   ipFilter
+}
+
+// newIPRules parses the IP lists of the web server config
+func newIPRules(cfg config.WebServerConfig) (ipRules, error) {
+	allow, err := config.ParseIPList(cfg.AllowedIPs)
+	if err != nil {
+		return ipRules{}, err
+	}
+	deny, err := config.ParseIPList(cfg.DeniedIPs)
+	if err != nil {
+		return ipRules{}, err
+	}
+	rules := ipRules{
+		global:  ipFilter{allow: allow, deny: deny},
+		metrics: ipFilter{allow: allow, deny: deny},
+		debug:   ipFilter{allow: allow, deny: deny},
+		admin:   ipFilter{allow: allow, deny: deny},
+	}
+
+	if cfg.MetricsAllowedIPs != "" {
+		if rules.metrics.allow, err = config.ParseIPList(cfg.MetricsAllowedIPs); err != nil {
+			return ipRules{}, err
+		}
+	}
+	if cfg.DebugAllowedIPs != "" {
+		if rules.debug.allow, err = config.ParseIPList(cfg.DebugAllowedIPs); err != nil {
+			return ipRules{}, err
+		}
+	}
+	if cfg.AdminAllowedIPs != "" {
+		if rules.admin.allow, err = config.ParseIPList(cfg.AdminAllowedIPs); err != nil {
+			return ipRules{}, err
+		}
+	}
+	return rules, nil
+}
+
+// remoteIP returns the IP a request came from
+func remoteIP(r *http.Request) netip.Addr {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}
+	}
+	return addr.Unmap()
+}
+
+// ipFilterFor returns the filter for a request path
+func (ws *WebServer) ipFilterFor(path string) ipFilter {
+	ws.ipRulesMu.RLock()
+	defer ws.ipRulesMu.RUnlock()
+
+	if ws.config.MetricsEnabled && path == ws.config.MetricsRoute {
+		return ws.ipRules.metrics
+	}
+	if ws.config.DebugEnabled && ws.config.DebugPort == 0 && strings.HasPrefix(path, strings.TrimSuffix(ws.config.DebugRoute, "/")+"/") {
+		return ws.ipRules.debug
+	}
+	if ws.config.AdminEnabled && isUnderRoute(path, ws.config.AdminRoute) {
+		return ws.ipRules.admin
+	}
+	return ws.ipRules.global
+}
+
+// isUnderRoute returns whether path is route or a path below it
+func isUnderRoute(path, route string) bool {
+	route = strings.TrimSuffix(route, "/")
+	return path == route || strings.HasPrefix(path, route+"/")
+}
+
+// ipFilterMiddleware rejects requests and WebSocket upgrades from IPs that
+// aren't allowed, before they are routed
+func (ws *WebServer) ipFilterMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !ws.ipFilterFor(r.URL.Path).allows(remoteIP(r)) {
+			ws.rejectIP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// debugIPFilter guards the separate debug listener with the debug filter
+func (ws *WebServer) debugIPFilter(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws.ipRulesMu.RLock()
+		filter := ws.ipRules.debug
+		ws.ipRulesMu.RUnlock()
+
+		if !filter.allows(remoteIP(r)) {
+			ws.rejectIP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// actionAllowsIP returns whether an action's own allowlist (WebConfig.AllowedIPs)
+// accepts the request. Denied IPs were already rejected by the middleware.
+func (ws *WebServer) actionAllowsIP(actionName string, r *http.Request) bool {
+	ws.routesMu.RLock()
+	allow, ok := ws.actionAllowedIPs[actionName]
+	ws.routesMu.RUnlock()
+	return !ok || (ipFilter{allow: allow}).allows(remoteIP(r))
+}
+
+// rejectIP responds to a request from an IP that isn't allowed
+func (ws *WebServer) rejectIP(w http.ResponseWriter, r *http.Request) {
+	ws.logger.Debugf("Rejected request from %s to %s (IP not allowed)", r.RemoteAddr, r.URL.Path)
+	ws.sendError(w, http.StatusForbidden, "IP_NOT_ALLOWED", "requests from this IP are not allowed", "")
+}
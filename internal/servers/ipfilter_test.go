@@ -0,0 +1,108 @@
+package servers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/evantahler/go-actionhero/internal/config"
+)
+
+func TestIPFilter(t *testing.T) {
+	allow, _ := config.ParseIPList("10.0.0.0/8, 192.168.1.5")
+	deny, _ := config.ParseIPList("10.0.0.66")
+	filter := ipFilter{allow: allow, deny: deny}
+
+	tests := []struct {
+		ip      string
+		allowed bool
+	}{
+		{"10.1.2.3", true},
+		{"192.168.1.5", true},
+		{"192.168.1.6", false},
+		{"10.0.0.66", false},
+		{"::ffff:10.1.2.3", true},
+	}
+	for _, tt := range tests {
+		if got := filter.allows(netip.MustParseAddr(tt.ip).Unmap()); got != tt.allowed {
+			t.Errorf("allows(%s) = %v, expected %v", tt.ip, got, tt.allowed)
+		}
+	}
+
+	if !(ipFilter{}).allows(netip.Addr{}) {
+		t.Error("Expected an empty filter to allow any request")
+	}
+	if filter.allows(netip.Addr{}) {
+		t.Error("Expected a request without an IP to be rejected when lists are configured")
+	}
+}
+
+func TestWebServer_IPFilter(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+	ws.config.AllowedIPs = "10.0.0.0/8"
+	ws.config.DeniedIPs = "10.0.0.66"
+	ws.config.MetricsEnabled = true
+	ws.config.MetricsRoute = "/metrics"
+	ws.config.MetricsAllowedIPs = "127.0.0.1"
+
+	internal := newTestAction("test:internal", "/internal", api.HTTPMethodGET, "secret", nil)
+	internal.ActionWeb.AllowedIPs = []string{"10.1.0.0/16"}
+	for _, action := range []api.Action{newTestAction("test:public", "/public", api.HTTPMethodGET, "ok", nil), internal} {
+		if err := apiInstance.RegisterAction(action); err != nil {
+			t.Fatalf("Failed to register action: %v", err)
+		}
+	}
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		remoteAddr string
+		status     int
+	}{
+		{"allowed IP", "/api/public", "10.2.3.4:5000", http.StatusOK},
+		{"IP outside the allowlist", "/api/public", "192.168.1.1:5000", http.StatusForbidden},
+		{"denied IP", "/api/public", "10.0.0.66:5000", http.StatusForbidden},
+		{"WebSocket upgrade", "/ws", "192.168.1.1:5000", http.StatusForbidden},
+		{"metrics override allows", "/metrics", "127.0.0.1:5000", http.StatusOK},
+		{"metrics override rejects", "/metrics", "10.2.3.4:5000", http.StatusForbidden},
+		{"action allowlist allows", "/api/internal", "10.1.2.3:5000", http.StatusOK},
+		{"action allowlist rejects", "/api/internal", "10.2.3.4:5000", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			req.RemoteAddr = tt.remoteAddr
+			w := httptest.NewRecorder()
+			ws.server.Handler.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Errorf("Expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestWebServer_IPFilterReload(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+	if err := apiInstance.RegisterAction(newTestAction("test:public", "/public", api.HTTPMethodGET, "ok", nil)); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	apiInstance.Config.Server.Web.DeniedIPs = "192.0.2.0/24"
+	ws.handleConfigReloaded(context.Background(), api.Event{})
+
+	req := httptest.NewRequest("GET", "/api/public", nil)
+	w := httptest.NewRecorder()
+	ws.server.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected the reloaded denylist to reject %s, got %d", req.RemoteAddr, w.Code)
+	}
+}
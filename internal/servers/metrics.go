@@ -0,0 +1,30 @@
+package servers
+
+import (
+	"net/http"
+)
+
+// handleMetrics serves the per-action latency and error metrics, connection
+// counts, circuit breakers, uptime checks, and how many log entries were dropped, as JSON
+func (ws *WebServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		ws.sendError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Method not allowed", "")
+		return
+	}
+
+	ws.sendSuccess(w, map[string]interface{}{
+		"actions":  ws.api.Metrics.Snapshot(),
+		"http":     ws.api.HTTPMetrics.Snapshot(),
+		"breakers": ws.api.BreakerStats(),
+		"uptime":   ws.api.UptimeChecks(),
+		"connections": map[string]interface{}{
+			"open":            ws.ConnectionCount(),
+			"idleClosed":      ws.idleClosed.Load(),
+			"sessionsExpired": ws.sessionsExpired.Load(),
+			"rateLimited":     ws.rateLimited.Load(),
+		},
+		"logger": map[string]interface{}{
+			"dropped": ws.api.Logger.Dropped(),
+		},
+	})
+}
@@ -0,0 +1,71 @@
+package servers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWebServer_MetricsEndpoint(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+	ws.config.MetricsEnabled = true
+	ws.config.MetricsRoute = "/metrics"
+
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	apiInstance.Metrics.Record("test:action", 25*time.Millisecond, nil)
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	ws.server.Handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var response struct {
+		Data struct {
+			Actions map[string]struct {
+				Count int64   `json:"count"`
+				P50Ms float64 `json:"p50Ms"`
+			} `json:"actions"`
+			Logger struct {
+				Dropped *int64 `json:"dropped"`
+			} `json:"logger"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	stats, ok := response.Data.Actions["test:action"]
+	if !ok {
+		t.Fatal("Expected metrics for test:action")
+	}
+	if stats.Count != 1 || stats.P50Ms != 25 {
+		t.Errorf("Unexpected metrics: %+v", stats)
+	}
+	if response.Data.Logger.Dropped == nil || *response.Data.Logger.Dropped != 0 {
+		t.Error("Expected the dropped log entry count")
+	}
+}
+
+func TestWebServer_MetricsEndpointDisabled(t *testing.T) {
+	ws, _ := setupTestServer(t)
+
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	ws.server.Handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 when metrics are disabled, got %d", w.Code)
+	}
+}
@@ -0,0 +1,72 @@
+package servers
+
+import (
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/util"
+	"github.com/gorilla/websocket"
+)
+
+// messageLimiter is a token bucket limiting the messages a WebSocket
+// connection may send. It is only used by the connection's read loop, so it
+// isn't safe for concurrent use.
+type messageLimiter struct {
+	rate     float64 // Tokens added per second
+	burst    float64 // Bucket size
+	tokens   float64
+	last     time.Time
+	warnings int // Messages rejected since the bucket was last full
+}
+
+// newMessageLimiter returns a limiter allowing rate messages per second with
+// bursts of burst messages, or nil when rate is 0 (unlimited)
+func newMessageLimiter(rate float64, burst int) *messageLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	return &messageLimiter{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// allow takes a token for a message received at now, reporting whether one
+// was available
+func (l *messageLimiter) allow(now time.Time) bool {
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	l.last = now
+	if l.tokens >= l.burst {
+		// The client has slowed down long enough to be forgiven
+		l.tokens = l.burst
+		l.warnings = 0
+	}
+
+	if l.tokens < 1 {
+		l.warnings++
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// rateLimit checks a message against the connection's rate limit. Messages
+// over the limit are rejected with a warning until the connection has used
+// up server.web.messageratewarnings, then the connection is closed.
+func (ws *WebServer) rateLimit(wsConn *wsConnection) (allowed, disconnect bool) {
+	if wsConn.limiter == nil || wsConn.limiter.allow(time.Now()) {
+		return true, false
+	}
+
+	ws.rateLimited.Add(1)
+	ws.rateLimitMu.RLock()
+	warnings := ws.config.MessageRateWarnings
+	ws.rateLimitMu.RUnlock()
+	if wsConn.limiter.warnings > warnings {
+		ws.logger.Warnf("Closing WebSocket connection %s: message rate limit exceeded", wsConn.connection.ID)
+		wsConn.closeMessage = websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "rate limit exceeded")
+		return false, true
+	}
+
+	ws.sendWebSocketErrorBody(wsConn, util.ErrorJSON{
+		Code:    string(util.ErrorTypeConnectionRateLimited),
+		Message: "Too many messages, slow down or the connection will be closed",
+	})
+	return false, false
+}
@@ -0,0 +1,141 @@
+package servers
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestMessageLimiter(t *testing.T) {
+	if newMessageLimiter(0, 10) != nil {
+		t.Error("Expected no limiter without a rate")
+	}
+
+	limiter := newMessageLimiter(2, 3)
+	now := limiter.last
+
+	for i := 0; i < 3; i++ {
+		if !limiter.allow(now) {
+			t.Fatalf("Expected message %d of the burst to be allowed", i+1)
+		}
+	}
+	if limiter.allow(now) || limiter.warnings != 1 {
+		t.Errorf("Expected a message past the burst to be rejected with a warning, got %d warnings", limiter.warnings)
+	}
+
+	// 2 messages per second refill a token every 500ms
+	if !limiter.allow(now.Add(500 * time.Millisecond)) {
+		t.Error("Expected a refilled token to be allowed")
+	}
+	if limiter.warnings != 1 {
+		t.Errorf("Expected warnings to be kept until the bucket refills, got %d", limiter.warnings)
+	}
+
+	if !limiter.allow(now.Add(time.Minute)) || limiter.warnings != 0 {
+		t.Errorf("Expected a full bucket to clear warnings, got %d", limiter.warnings)
+	}
+}
+
+func TestWebServer_WebSocketRateLimit(t *testing.T) {
+	_, apiInstance := setupTestServer(t)
+	apiInstance.Config.Server.Web.MessageRate = 1
+	apiInstance.Config.Server.Web.MessageBurst = 2
+	apiInstance.Config.Server.Web.MessageRateWarnings = 2
+
+	ws := NewTestWebServer(t, apiInstance)
+
+	dialer := websocket.Dialer{}
+	conn, _, err := dialer.Dial(ws.WebSocketURL, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect to WebSocket: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	for i := 0; i < 5; i++ {
+		if err := conn.WriteJSON(map[string]interface{}{"type": "paramsView"}); err != nil {
+			t.Fatalf("Failed to send message: %v", err)
+		}
+	}
+
+	// The burst is answered, the next messages are warned, then the connection closes
+	var types []string
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			var closeErr *websocket.CloseError
+			if !errors.As(err, &closeErr) || closeErr.Code != websocket.ClosePolicyViolation {
+				t.Errorf("Expected a policy violation close, got %v", err)
+			}
+			break
+		}
+
+		var msg struct {
+			Type  string `json:"type"`
+			Error struct {
+				Code string `json:"code"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Fatalf("Failed to decode message: %v", err)
+		}
+		if msg.Error.Code != "" {
+			types = append(types, msg.Error.Code)
+		} else {
+			types = append(types, msg.Type)
+		}
+	}
+
+	expected := []string{"params", "params", "CONNECTION_RATE_LIMITED", "CONNECTION_RATE_LIMITED"}
+	if len(types) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, types)
+	}
+	for i := range expected {
+		if types[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, types)
+			break
+		}
+	}
+	if ws.rateLimited.Load() != 3 {
+		t.Errorf("Expected 3 rate-limited messages, got %d", ws.rateLimited.Load())
+	}
+}
+
+func TestWebServer_WebSocketRateLimitReload(t *testing.T) {
+	_, apiInstance := setupTestServer(t)
+	ws := NewTestWebServer(t, apiInstance)
+
+	// Connections opened after a reload get its limits
+	next := *apiInstance.CurrentConfig()
+	next.Server.Web.MessageRate = 1
+	next.Server.Web.MessageBurst = 1
+	next.Server.Web.MessageRateWarnings = 0
+	apiInstance.ReloadConfig(&next)
+
+	dialer := websocket.Dialer{}
+	conn, _, err := dialer.Dial(ws.WebSocketURL, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect to WebSocket: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	for i := 0; i < 2; i++ {
+		if err := conn.WriteJSON(map[string]interface{}{"type": "paramsView"}); err != nil {
+			t.Fatalf("Failed to send message: %v", err)
+		}
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var msg map[string]interface{}
+	if err := conn.ReadJSON(&msg); err != nil || msg["type"] != "params" {
+		t.Fatalf("Expected the first message to be answered, got %v (%v)", msg, err)
+	}
+	_, _, err = conn.ReadMessage()
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) || closeErr.Code != websocket.ClosePolicyViolation {
+		t.Errorf("Expected the reloaded limit to close the connection, got %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package servers
+
+import (
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/gorilla/websocket"
+)
+
+// reapConnections periodically closes idle WebSocket connections and
+// removes expired sessions, until the server stops
+func (ws *WebServer) reapConnections() {
+	defer ws.wg.Done()
+
+	ticker := time.NewTicker(ws.config.ReapInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ws.reap()
+		case <-ws.ctx.Done():
+			return
+		}
+	}
+}
+
+// reap sweeps every WebSocket connection once
+func (ws *WebServer) reap() {
+	ws.connectionsMu.RLock()
+	conns := make([]*wsConnection, 0, len(ws.connections))
+	for _, conn := range ws.connections {
+		conns = append(conns, conn)
+	}
+	ws.connectionsMu.RUnlock()
+
+	var sessionTTL time.Duration
+	if ws.api.Config != nil {
+		sessionTTL = ws.api.Config.Session.TTL
+	}
+
+	for _, wsConn := range conns {
+		conn := wsConn.connection
+		if conn.ExpireSession(sessionTTL) {
+			ws.sessionsExpired.Add(1)
+			ws.logger.Debugf("Session expired on connection %s", conn.ID)
+			ws.api.Emit(ws.ctx, api.Event{Name: api.EventSessionExpire, Connection: conn})
+		}
+
+		if idle := conn.IdleFor(); ws.config.IdleTimeout > 0 && idle > ws.config.IdleTimeout {
+			ws.idleClosed.Add(1)
+			ws.logger.Debugf("Closing WebSocket connection %s, idle for %s", conn.ID, idle.Round(time.Second))
+			ws.api.Emit(ws.ctx, api.Event{Name: api.EventConnectionIdle, Connection: conn, Duration: idle})
+			ws.closeIdle(wsConn)
+		}
+	}
+}
+
+// closeIdle tells the client why its connection is closing, then closes it.
+// The read loop then removes the connection and emits connection:close.
+func (ws *WebServer) closeIdle(wsConn *wsConnection) {
+	message := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "idle timeout")
+	_ = wsConn.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(time.Second))
+	if err := wsConn.conn.Close(); err != nil {
+		ws.logger.Warnf("Error closing idle WebSocket connection: %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package servers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/gorilla/websocket"
+)
+
+func TestWebServer_ReapsIdleConnections(t *testing.T) {
+	_, apiInstance := setupTestServer(t)
+	apiInstance.Config.Server.Web.IdleTimeout = 100 * time.Millisecond
+	apiInstance.Config.Server.Web.ReapInterval = 20 * time.Millisecond
+	apiInstance.Config.Session.TTL = time.Hour
+
+	opened := make(chan *api.Connection, 1)
+	expired := make(chan *api.Connection, 1)
+	idle := make(chan api.Event, 1)
+	apiInstance.On(api.EventConnectionOpen, func(_ context.Context, event api.Event) {
+		// A session created before the TTL
+		event.Connection.SetSession(&api.SessionData{ID: "stale", CreatedAt: time.Now().Add(-2 * time.Hour).Unix()})
+		opened <- event.Connection
+	})
+	apiInstance.On(api.EventSessionExpire, func(_ context.Context, event api.Event) {
+		expired <- event.Connection
+	})
+	apiInstance.On(api.EventConnectionIdle, func(_ context.Context, event api.Event) {
+		idle <- event
+	})
+
+	ws := NewTestWebServer(t, apiInstance)
+
+	dialer := websocket.Dialer{}
+	conn, _, err := dialer.Dial(ws.WebSocketURL, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect to WebSocket: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	openedConn := <-opened
+
+	select {
+	case expiredConn := <-expired:
+		if expiredConn.ID != openedConn.ID || expiredConn.Session != nil {
+			t.Error("Expected the stale session to be removed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for session:expire event")
+	}
+
+	select {
+	case event := <-idle:
+		if event.Connection.ID != openedConn.ID || event.Duration < ws.config.IdleTimeout {
+			t.Errorf("Unexpected connection:idle event: %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for connection:idle event")
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, _, err = conn.ReadMessage()
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) || closeErr.Text != "idle timeout" {
+		t.Errorf("Expected an idle timeout close message, got %v", err)
+	}
+	if ws.idleClosed.Load() != 1 || ws.sessionsExpired.Load() != 1 {
+		t.Errorf("Expected 1 idle connection and 1 expired session, got %d and %d", ws.idleClosed.Load(), ws.sessionsExpired.Load())
+	}
+}
+
+func TestWebServer_KeepsActiveConnections(t *testing.T) {
+	_, apiInstance := setupTestServer(t)
+	apiInstance.Config.Server.Web.IdleTimeout = 150 * time.Millisecond
+	apiInstance.Config.Server.Web.ReapInterval = 20 * time.Millisecond
+
+	ws := NewTestWebServer(t, apiInstance)
+
+	dialer := websocket.Dialer{}
+	conn, _, err := dialer.Dial(ws.WebSocketURL, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect to WebSocket: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	// Messages reset the idle timer
+	for i := 0; i < 5; i++ {
+		time.Sleep(50 * time.Millisecond)
+		if err := conn.WriteJSON(map[string]interface{}{"type": "paramsView"}); err != nil {
+			t.Fatalf("Failed to send message: %v", err)
+		}
+		if _, _, err := conn.ReadMessage(); err != nil {
+			t.Fatalf("Expected the active connection to stay open: %v", err)
+		}
+	}
+	if ws.idleClosed.Load() != 0 {
+		t.Errorf("Expected no idle connections, got %d", ws.idleClosed.Load())
+	}
+}
@@ -0,0 +1,113 @@
+package servers
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+)
+
+// routeCache remembers the most recently matched routes by method and path,
+// so repeated requests skip pattern matching. It is cleared whenever the
+// routes are rebuilt.
+type routeCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List               // Most recently used first
+	entries map[string]*list.Element // Key -> element holding a *routeCacheEntry
+}
+
+type routeCacheEntry struct {
+	key    string
+	action *api.ActionDescriptor
+	params map[string]string
+}
+
+// newRouteCache creates a cache of up to size routes (nil when size is 0,
+// which disables caching)
+func newRouteCache(size int) *routeCache {
+	if size <= 0 {
+		return nil
+	}
+	return &routeCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+// routeCacheKey returns the cache key of a request
+func routeCacheKey(method, path string) string {
+	return method + " " + path
+}
+
+// get returns the cached action and a copy of the path params for key
+func (c *routeCache) get(key string) (*api.ActionDescriptor, map[string]string, bool) {
+	if c == nil {
+		return nil, nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, nil, false
+	}
+	c.order.MoveToFront(element)
+	entry := element.Value.(*routeCacheEntry)
+	return entry.action, copyParams(entry.params), true
+}
+
+// add caches a matched route and a copy of its path params, evicting the
+// least recently used route when full
+func (c *routeCache) add(key string, action *api.ActionDescriptor, params map[string]string) {
+	if c == nil {
+		return
+	}
+	params = copyParams(params)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		element.Value = &routeCacheEntry{key: key, action: action, params: params}
+		return
+	}
+	if c.order.Len() >= c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*routeCacheEntry).key)
+	}
+	c.entries[key] = c.order.PushFront(&routeCacheEntry{key: key, action: action, params: params})
+}
+
+// copyParams copies path params, so callers can't change cached ones
+func copyParams(params map[string]string) map[string]string {
+	copied := make(map[string]string, len(params))
+	for name, value := range params {
+		copied[name] = value
+	}
+	return copied
+}
+
+// clear removes every cached route
+func (c *routeCache) clear() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	c.entries = make(map[string]*list.Element, c.size)
+}
+
+// len returns how many routes are cached
+func (c *routeCache) len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
@@ -0,0 +1,77 @@
+package servers
+
+import (
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+)
+
+func TestRouteCache(t *testing.T) {
+	if newRouteCache(0) != nil {
+		t.Error("Expected no cache with size 0")
+	}
+
+	cache := newRouteCache(2)
+	a := &api.ActionDescriptor{Name: "a"}
+	b := &api.ActionDescriptor{Name: "b"}
+	c := &api.ActionDescriptor{Name: "c"}
+
+	cache.add("GET /a", a, map[string]string{"id": "1"})
+	cache.add("GET /b", b, nil)
+	if _, _, ok := cache.get("GET /a"); !ok {
+		t.Fatal("Expected GET /a to be cached")
+	}
+
+	// GET /a was used more recently, so GET /b is evicted
+	cache.add("GET /c", c, nil)
+	if _, _, ok := cache.get("GET /b"); ok {
+		t.Error("Expected the least recently used route to be evicted")
+	}
+
+	action, params, ok := cache.get("GET /a")
+	if !ok || action != a || params["id"] != "1" {
+		t.Fatalf("Expected GET /a with id 1, got %v %v %v", action, params, ok)
+	}
+	params["id"] = "changed"
+	if _, params, _ := cache.get("GET /a"); params["id"] != "1" {
+		t.Error("Expected cached params not to change")
+	}
+
+	cache.clear()
+	if cache.len() != 0 {
+		t.Errorf("Expected an empty cache, got %d routes", cache.len())
+	}
+}
+
+func TestWebServer_RouteCacheInvalidation(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+	ws.routeCache = newRouteCache(10)
+
+	if err := apiInstance.RegisterAction(newTestAction("test:user", "/users/:id", api.HTTPMethodGET, "v1", nil)); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		action, params, err := ws.matchRoute("GET", "/api/users/42")
+		if err != nil || action.Name != "test:user" || params["id"] != "42" {
+			t.Fatalf("Expected test:user with id 42, got %v %v %v", action, params, err)
+		}
+	}
+	if ws.routeCache.len() != 1 {
+		t.Errorf("Expected 1 cached route, got %d", ws.routeCache.len())
+	}
+
+	// Changing the actions rebuilds the routes and clears the cache
+	if err := apiInstance.UnregisterAction("test:user"); err != nil {
+		t.Fatalf("Failed to unregister action: %v", err)
+	}
+	if _, _, err := ws.matchRoute("GET", "/api/users/42"); err == nil {
+		t.Error("Expected no route after the action was unregistered")
+	}
+	if ws.routeCache.len() != 0 {
+		t.Errorf("Expected the cache to be cleared, got %d routes", ws.routeCache.len())
+	}
+}
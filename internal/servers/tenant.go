@@ -0,0 +1,39 @@
+package servers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// tenantMiddleware resolves the tenant of action and WebSocket requests when
+// tenancy is enabled, and puts it on the request's context, where new
+// connections pick it up. Requests without a valid tenant are rejected.
+func (ws *WebServer) tenantMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !ws.api.Config.Tenancy.Enabled {
+			next(w, r)
+			return
+		}
+
+		tenant, err := ws.api.ResolveTenant(r)
+		if err != nil {
+			requestID := requestIDFromHeader(r)
+			w.Header().Set(requestIDHeader, requestID)
+			status, code, message := http.StatusBadRequest, string(util.ErrorTypeConnectionTenantRequired), err.Error()
+			var typedErr *util.TypedError
+			if errors.As(err, &typedErr) {
+				status, code, message = typedErr.HTTPStatus(), typedErr.Code(), typedErr.Message
+			}
+			ws.logger.Debugf("Rejected request for %s: %v", r.URL.Path, err)
+			ws.sendError(w, status, code, message, requestID)
+			return
+		}
+		if tenant != nil {
+			r = r.WithContext(api.WithTenant(r.Context(), tenant))
+		}
+		next(w, r)
+	}
+}
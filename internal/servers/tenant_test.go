@@ -0,0 +1,77 @@
+package servers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/evantahler/go-actionhero/internal/config"
+)
+
+// tenantAction returns the tenant it runs for
+type tenantAction struct {
+	api.BaseAction
+}
+
+func (a *tenantAction) Run(ctx context.Context, _ interface{}, conn *api.Connection) (interface{}, error) {
+	return map[string]interface{}{"tenant": api.TenantFromContext(ctx), "connTenant": conn.Tenant()}, nil
+}
+
+func TestWebServer_Tenancy(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+	apiInstance.Config.Tenancy = config.DefaultTenancyConfig()
+	apiInstance.Config.Tenancy.Enabled = true
+	apiInstance.Config.Tenancy.Schema = "tenant_{tenant}"
+
+	action := &tenantAction{BaseAction: api.BaseAction{
+		ActionName: "test:tenant",
+		ActionWeb:  &api.WebConfig{Route: "/tenant", Method: api.HTTPMethodGET},
+	}}
+	if err := apiInstance.RegisterAction(action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/api/tenant", nil)
+	req.Header.Set("X-Tenant-ID", "acme")
+	w := httptest.NewRecorder()
+	ws.server.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response struct {
+		Data struct {
+			Tenant     api.Tenant `json:"tenant"`
+			ConnTenant api.Tenant `json:"connTenant"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	body := response.Data
+	want := api.Tenant{ID: "acme", Schema: "tenant_acme"}
+	if body.Tenant != want || body.ConnTenant != want {
+		t.Errorf("Expected the action and connection to have tenant %+v, got %+v and %+v", want, body.Tenant, body.ConnTenant)
+	}
+
+	// Requests without a tenant are rejected
+	w = httptest.NewRecorder()
+	ws.server.Handler.ServeHTTP(w, httptest.NewRequest("GET", "/api/tenant", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without a tenant, got %d", w.Code)
+	}
+	var errorResponse map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &errorResponse); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	errorBody, _ := errorResponse["error"].(map[string]interface{})
+	if errorBody["code"] != "CONNECTION_TENANT_REQUIRED" {
+		t.Errorf("Expected a CONNECTION_TENANT_REQUIRED error, got %s", w.Body.String())
+	}
+}
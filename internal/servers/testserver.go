@@ -0,0 +1,52 @@
+package servers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+)
+
+// TestWebServer is a web server listening on a random local port, for tests
+type TestWebServer struct {
+	*WebServer
+	URL          string // e.g., http://127.0.0.1:54321 (https:// with TLS)
+	WebSocketURL string // e.g., ws://127.0.0.1:54321/ws (wss:// with TLS)
+}
+
+// NewTestWebServer initializes and starts a web server for apiInstance on a
+// random port of 127.0.0.1, ready for requests once it returns. It is stopped
+// when the test ends. Failures to start fail the test.
+//
+//	ts := servers.NewTestWebServer(t, apiInstance)
+//	resp, err := http.Get(ts.URL + "/api/status")
+func NewTestWebServer(t testing.TB, apiInstance *api.API) *TestWebServer {
+	t.Helper()
+
+	ws := NewWebServer(apiInstance)
+	ws.config.Host = "127.0.0.1"
+	ws.config.Port = 0
+
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize test web server: %v", err)
+	}
+	if err := ws.Start(); err != nil {
+		t.Fatalf("Failed to start test web server: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := ws.Stop(); err != nil {
+			t.Errorf("Failed to stop test web server: %v", err)
+		}
+	})
+
+	addr := fmt.Sprintf("127.0.0.1:%d", ws.port)
+	httpScheme, wsScheme := "http://", "ws://"
+	if ws.secure {
+		httpScheme, wsScheme = "https://", "wss://"
+	}
+	return &TestWebServer{
+		WebServer:    ws,
+		URL:          httpScheme + addr,
+		WebSocketURL: wsScheme + addr + "/ws",
+	}
+}
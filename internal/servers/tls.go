@@ -0,0 +1,123 @@
+package servers
+
+import (
+	"crypto/tls"
+	"fmt"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/util"
+	"github.com/fsnotify/fsnotify"
+)
+
+// certReloader serves a TLS certificate loaded from files and reloads it
+// when they change, so certificates can be rotated without a restart.
+// Existing connections keep the certificate they were established with.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	logger   *util.Logger
+	cert     atomic.Pointer[tls.Certificate]
+	watcher  *fsnotify.Watcher
+	debounce time.Duration
+	done     chan struct{}
+}
+
+// newCertReloader loads the certificate and starts watching its files
+func newCertReloader(certFile, keyFile string, logger *util.Logger) (*certReloader, error) {
+	r := &certReloader{
+		certFile: absPath(certFile),
+		keyFile:  absPath(keyFile),
+		logger:   logger,
+		debounce: 250 * time.Millisecond,
+		done:     make(chan struct{}),
+	}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	// Watch the directories, so files replaced by a rename (as by most
+	// certificate tools and Kubernetes secret volumes) are seen
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate watcher: %w", err)
+	}
+	for _, dir := range []string{filepath.Dir(r.certFile), filepath.Dir(r.keyFile)} {
+		if err := watcher.Add(dir); err != nil {
+			_ = watcher.Close()
+			return nil, fmt.Errorf("failed to watch %s: %w", dir, err)
+		}
+	}
+	r.watcher = watcher
+
+	go r.run()
+	return r, nil
+}
+
+// load reads the certificate and key, replacing the served certificate
+func (r *certReloader) load() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	r.cert.Store(&cert)
+	return nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate
+func (r *certReloader) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.cert.Load(), nil
+}
+
+// run reloads the certificate after its files change, until Close
+func (r *certReloader) run() {
+	defer close(r.done)
+
+	var debounce <-chan time.Time
+	for {
+		select {
+		case event, ok := <-r.watcher.Events:
+			if !ok {
+				return
+			}
+			// Changes in a Kubernetes secret volume swap the "..data" symlink
+			// instead of the files themselves
+			name := absPath(event.Name)
+			if name == r.certFile || name == r.keyFile || filepath.Base(name) == "..data" {
+				debounce = time.After(r.debounce)
+			}
+
+		case err, ok := <-r.watcher.Errors:
+			if !ok {
+				return
+			}
+			r.logger.Warnf("TLS certificate watcher error: %v", err)
+
+		case <-debounce:
+			debounce = nil
+			if err := r.load(); err != nil {
+				// Certificate and key may be written one at a time; keep
+				// serving the current certificate until both match
+				r.logger.Errorf("Keeping the current TLS certificate: %v", err)
+				continue
+			}
+			r.logger.Info("Reloaded TLS certificate")
+		}
+	}
+}
+
+// Close stops watching the certificate files
+func (r *certReloader) Close() error {
+	err := r.watcher.Close()
+	<-r.done
+	return err
+}
+
+// absPath returns the absolute form of path, or path when that fails
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
@@ -0,0 +1,159 @@
+package servers
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// writeTestCert writes a self-signed certificate for localhost with the
+// given serial number, replacing the files like certificate tools do
+func writeTestCert(t *testing.T, certFile, keyFile string, serial int64) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+
+	write := func(path, blockType string, der []byte) {
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			t.Fatalf("Failed to replace %s: %v", path, err)
+		}
+	}
+	write(keyFile, "EC PRIVATE KEY", keyDER)
+	write(certFile, "CERTIFICATE", der)
+}
+
+// servedSerial returns the serial number of the certificate the reloader serves
+func servedSerial(t *testing.T, r *certReloader) int64 {
+	t.Helper()
+	cert, _ := r.GetCertificate(nil)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+	return leaf.SerialNumber.Int64()
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	writeTestCert(t, certFile, keyFile, 1)
+
+	logger := util.NewLogger(config.LoggerConfig{Level: "fatal"})
+	r, err := newCertReloader(certFile, keyFile, logger)
+	if err != nil {
+		t.Fatalf("Failed to load certificate: %v", err)
+	}
+	defer func() { _ = r.Close() }()
+	r.debounce = 10 * time.Millisecond
+
+	if serial := servedSerial(t, r); serial != 1 {
+		t.Fatalf("Expected certificate 1, got %d", serial)
+	}
+
+	writeTestCert(t, certFile, keyFile, 2)
+	deadline := time.Now().Add(2 * time.Second)
+	for servedSerial(t, r) != 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the rotated certificate")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// A broken certificate keeps the current one
+	if err := os.WriteFile(certFile, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("Failed to write certificate: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if serial := servedSerial(t, r); serial != 2 {
+		t.Errorf("Expected to keep certificate 2, got %d", serial)
+	}
+}
+
+func TestCertReloader_MissingFiles(t *testing.T) {
+	logger := util.NewLogger(config.LoggerConfig{Level: "fatal"})
+	if _, err := newCertReloader("missing.pem", "missing-key.pem", logger); err == nil {
+		t.Error("Expected an error for missing certificate files")
+	}
+}
+
+func TestWebServer_TLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	writeTestCert(t, certFile, keyFile, 7)
+
+	_, apiInstance := setupTestServer(t)
+	apiInstance.Config.Server.Web.TLSCertFile = certFile
+	apiInstance.Config.Server.Web.TLSKeyFile = keyFile
+	if err := apiInstance.RegisterAction(newTestAction("test:status", "/status", api.HTTPMethodGET, "ok", nil)); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	ws := NewTestWebServer(t, apiInstance)
+
+	if addresses := ws.Addresses(); addresses[0] != ws.URL+"/api" || addresses[1] != ws.WebSocketURL || !strings.HasPrefix(ws.URL, "https://") {
+		t.Errorf("Expected secure addresses, got %v", addresses)
+	}
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	resp, err := client.Get(ws.URL + "/api/status")
+	if err != nil {
+		t.Fatalf("Failed to make HTTPS request: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK || resp.TLS.PeerCertificates[0].SerialNumber.Int64() != 7 {
+		t.Errorf("Expected a 200 over TLS with certificate 7, got %d", resp.StatusCode)
+	}
+}
+
+func TestWebServer_CertificateSource(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+	served := &tls.Certificate{}
+	api.Provide[api.CertificateSource](apiInstance, func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		return served, nil
+	})
+
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+	if ws.server.TLSConfig == nil || ws.certs != nil {
+		t.Fatal("Expected TLS from the provided certificate source")
+	}
+	if cert, _ := ws.server.TLSConfig.GetCertificate(nil); cert != served {
+		t.Error("Expected the provided certificate source to be used")
+	}
+}
@@ -1,61 +1,152 @@
 package servers
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"mime"
+	"net"
 	"net/http"
+	"net/netip"
+	"net/url"
+	"os"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/evantahler/go-actionhero/internal/api"
 	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/i18n"
+	"github.com/evantahler/go-actionhero/internal/openapi"
 	"github.com/evantahler/go-actionhero/internal/util"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 )
 
+// requestIDHeader is the header used to receive and return the request ID
+const requestIDHeader = "X-Request-ID"
+
+// cacheHeader reports whether a cacheable action's response came from the cache
+const cacheHeader = "X-Cache"
+
+// maxRequestIDLength bounds request IDs accepted from clients
+const maxRequestIDLength = 128
+
+// maxBodyPrealloc caps what is allocated up front for a request body of known
+// length, so a large Content-Length can't make the server allocate it before
+// the body arrives
+const maxBodyPrealloc = 1 << 20
+
 // WebServer implements the Server interface for HTTP and WebSocket
 type WebServer struct {
 	api    *api.API
 	config config.WebServerConfig
 	logger *util.Logger
 
-	server   *http.Server
-	routes   []routeEntry
-	upgrader websocket.Upgrader
+	server      *http.Server
+	secure      bool          // The server speaks TLS (Serve sets a TLSConfig on every server)
+	debugServer *http.Server  // Internal pprof/debug listener (when DebugPort > 0)
+	certs       *certReloader // Reloads the TLS certificate files (when configured)
+	routes      []routeEntry
+	upgrader    websocket.Upgrader
+
+	// Ports listened on, which differ from the configured ones when those are 0
+	port      int
+	debugPort int
+
+	// Guards the routes and input schemas, which are rebuilt when the
+	// registered actions change (see api.ActionsRevision)
+	routesMu       sync.RWMutex
+	routesRevision uint64
+	routeCache     *routeCache // Recently matched routes (nil when disabled)
+
+	// Guards the CORS settings, which can change on config reload
+	corsMu sync.RWMutex
+
+	// Guards the WebSocket message rate limits, which can change on config
+	// reload (connections keep the limits they were opened with, except the
+	// number of warnings)
+	rateLimitMu sync.RWMutex
+
+	// IP allow and deny lists, which can change on config reload
+	ipRules   ipRules
+	ipRulesMu sync.RWMutex
+
+	// OpenAPI input schemas by action name, used when ValidateRequests is enabled
+	inputSchemas map[string]map[string]interface{}
+
+	// Allowlists of actions that set WebConfig.AllowedIPs, by action name
+	actionAllowedIPs map[string][]netip.Prefix
+
+	// Connections of HTTP requests, reused once their response is sent
+	httpConnections api.ConnectionPool
 
 	// WebSocket connection management
 	connections   map[string]*wsConnection
 	connectionsMu sync.RWMutex
 
-	// Channels for broadcasting
-	broadcast chan broadcastMessage
+	// Connections closed for being idle and sessions expired by the reaper,
+	// and messages rejected by the message rate limit
+	idleClosed      atomic.Int64
+	sessionsExpired atomic.Int64
+	rateLimited     atomic.Int64
+
+	// Broadcast workers, each with the subscribers of its channels
+	broadcastShards []*broadcastShard
+
+	// Recent reported errors, listed by the admin dashboard
+	errors *errorLog
 
 	// Shutdown
 	ctx    context.Context
 	cancel context.CancelFunc
 	wg     sync.WaitGroup
+
+	subscribed bool // Event handlers are registered once, across restarts
 }
 
 type routeEntry struct {
 	pattern    *regexp.Regexp
 	paramNames []string
 	method     api.HTTPMethod
-	action     api.Action
+	action     *api.ActionDescriptor
 }
 
 type wsConnection struct {
 	conn       *websocket.Conn
 	connection *api.Connection
 	send       chan []byte
+	limiter    *messageLimiter // nil when messages aren't rate limited
+
+	// Actions run off the read loop, up to server.web.maxactionsinflight at
+	// once (a slot is taken from inFlight while one runs)
+	inFlight chan struct{}
+	actions  sync.WaitGroup
+	// lastResponse is closed once the latest action has sent its response,
+	// so with server.web.orderedresponses the next one waits for it. Only
+	// the read loop uses it.
+	lastResponse chan struct{}
+
+	// closeMessage is sent, after the queued messages, when the server
+	// closes the connection (nil = close right away)
+	closeMessage []byte
 }
 
-type broadcastMessage struct {
-	channel string
-	data    []byte
+func init() {
+	if err := api.RegisterServerFactory(config.WebServerName, func(a *api.API) api.Server {
+		return NewWebServer(a)
+	}); err != nil {
+		panic(err)
+	}
 }
 
 // NewWebServer creates a new web server instance
@@ -63,14 +154,19 @@ func NewWebServer(apiInstance *api.API) *WebServer {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &WebServer{
-		api:         apiInstance,
-		config:      apiInstance.Config.Server.Web,
-		logger:      apiInstance.Logger,
-		routes:      make([]routeEntry, 0),
-		connections: make(map[string]*wsConnection),
-		broadcast:   make(chan broadcastMessage, 256),
-		ctx:         ctx,
-		cancel:      cancel,
+		api:             apiInstance,
+		config:          apiInstance.Config.Server.Web,
+		logger:          apiInstance.Logger,
+		port:            apiInstance.Config.Server.Web.Port,
+		debugPort:       apiInstance.Config.Server.Web.DebugPort,
+		routes:          make([]routeEntry, 0),
+		connections:     make(map[string]*wsConnection),
+		inputSchemas:    make(map[string]map[string]interface{}),
+		broadcastShards: newBroadcastShards(apiInstance.Config.Server.Web.BroadcastWorkers),
+		routeCache:      newRouteCache(apiInstance.Config.Server.Web.RouteCacheSize),
+		errors:          &errorLog{},
+		ctx:             ctx,
+		cancel:          cancel,
 		upgrader: websocket.Upgrader{
 			ReadBufferSize:  1024,
 			WriteBufferSize: 1024,
@@ -87,49 +183,106 @@ func (ws *WebServer) Name() string {
 	return "web"
 }
 
+// ConnectionCount returns the number of open WebSocket connections
+func (ws *WebServer) ConnectionCount() int {
+	ws.connectionsMu.RLock()
+	defer ws.connectionsMu.RUnlock()
+	return len(ws.connections)
+}
+
 // Initialize sets up the web server
 func (ws *WebServer) Initialize() error {
 	ws.logger.Info("Initializing web server...")
 
-	// Build routes from registered actions
-	actions := ws.api.GetActions()
-	for _, action := range actions {
-		webConfig := api.GetActionWeb(action)
-		if webConfig == nil {
-			continue
-		}
-
-		pattern, paramNames, err := compileRoute(webConfig.Route)
-		if err != nil {
-			return fmt.Errorf("failed to compile route for action %s: %w", api.GetActionName(action), err)
-		}
+	// Start from scratch, so the server can be initialized again after a stop
+	ws.ctx, ws.cancel = context.WithCancel(context.Background())
+	ws.port, ws.debugPort = ws.config.Port, ws.config.DebugPort
+	if err := ws.buildRoutes(); err != nil {
+		return err
+	}
+	rules, err := newIPRules(ws.config)
+	if err != nil {
+		return fmt.Errorf("invalid IP list: %w", err)
+	}
+	ws.ipRulesMu.Lock()
+	ws.ipRules = rules
+	ws.ipRulesMu.Unlock()
 
-		ws.routes = append(ws.routes, routeEntry{
-			pattern:    pattern,
-			paramNames: paramNames,
-			method:     webConfig.Method,
-			action:     action,
-		})
+	// Let actions broadcast through this server
+	if _, ok := api.Lookup[api.Broadcaster](ws.api); !ok {
+		api.Provide[api.Broadcaster](ws.api, ws)
+	}
 
-		ws.logger.Debugf("Registered route: %s %s -> %s", webConfig.Method, webConfig.Route, api.GetActionName(action))
+	// Pick up CORS changes when the config is reloaded, and keep recent
+	// errors for the admin dashboard
+	if !ws.subscribed {
+		ws.api.On(api.EventConfigReloaded, ws.handleConfigReloaded)
+		ws.api.RegisterActionsValidator(ws.validateRoutes)
+		if ws.config.AdminEnabled {
+			ws.api.RegisterErrorReporter(ws.errors.report)
+		}
+		ws.subscribed = true
 	}
 
 	// Create HTTP server
 	mux := http.NewServeMux()
 
 	// Register handlers
-	mux.HandleFunc("/ws", ws.handleWebSocket)
-	mux.HandleFunc("/", ws.handleHTTP)
+	mux.HandleFunc("/ws", ws.tenantMiddleware(ws.handleWebSocket))
+	mux.HandleFunc("/", ws.tenantMiddleware(ws.handleHTTP))
 
 	// Add static file serving if enabled
 	if ws.config.StaticFilesEnabled {
 		fs := http.FileServer(http.Dir(ws.config.StaticFilesDirectory))
-		mux.Handle(ws.config.StaticFilesRoute+"/", http.StripPrefix(ws.config.StaticFilesRoute, fs))
+		mux.Handle(ws.config.StaticFilesRoute+"/", http.StripPrefix(ws.config.StaticFilesRoute, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			clearWriteDeadline(w)
+			fs.ServeHTTP(w, r)
+		})))
 		ws.logger.Infof("Static files enabled: %s -> %s", ws.config.StaticFilesRoute, ws.config.StaticFilesDirectory)
 	}
 
-	// Wrap with CORS middleware
-	handler := ws.corsMiddleware(mux)
+	// Serve signed URLs of locally stored files
+	if handler, ok := ws.api.Files.(http.Handler); ok && ws.api.Config.Storage.Route != "" {
+		route := strings.TrimSuffix(ws.api.Config.Storage.Route, "/")
+		mux.Handle(route+"/", http.StripPrefix(route, handler))
+		ws.logger.Infof("Stored files served at %s", route)
+	}
+
+	// Add per-action metrics endpoint if enabled
+	if ws.config.MetricsEnabled {
+		mux.HandleFunc(ws.config.MetricsRoute, ws.handleMetrics)
+		ws.logger.Infof("Metrics endpoint enabled: %s", ws.config.MetricsRoute)
+	}
+
+	// Add pprof and runtime debug endpoints if enabled
+	if ws.config.DebugEnabled {
+		debugRoute := strings.TrimSuffix(ws.config.DebugRoute, "/")
+		debugHandler := newDebugHandler(debugRoute)
+		if ws.config.DebugPort > 0 {
+			// A separate listener without write timeouts, so long CPU profiles complete
+			ws.debugServer = &http.Server{
+				Addr:              fmt.Sprintf("%s:%d", ws.config.DebugHost, ws.config.DebugPort),
+				Handler:           ws.debugIPFilter(debugHandler),
+				ReadHeaderTimeout: 15 * time.Second,
+			}
+			ws.logger.Infof("Debug endpoints enabled: %s%s", ws.debugServer.Addr, debugRoute)
+		} else {
+			mux.Handle(debugRoute+"/", debugHandler)
+			ws.logger.Infof("Debug endpoints enabled: %s", debugRoute)
+		}
+	}
+
+	// Add the admin dashboard if enabled
+	if ws.config.AdminEnabled {
+		adminRoute := strings.TrimSuffix(ws.config.AdminRoute, "/")
+		adminHandler := ws.newAdminHandler(adminRoute)
+		mux.Handle(adminRoute, adminHandler)
+		mux.Handle(adminRoute+"/", adminHandler)
+		ws.logger.Infof("Admin dashboard enabled: %s", adminRoute)
+	}
+
+	// Wrap with CORS and IP filter middleware
+	handler := ws.corsMiddleware(ws.ipFilterMiddleware(mux))
 
 	ws.server = &http.Server{
 		Addr:         fmt.Sprintf("%s:%d", ws.config.Host, ws.config.Port),
@@ -139,36 +292,117 @@ func (ws *WebServer) Initialize() error {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	return ws.configureTLS()
+}
+
+// configureTLS serves HTTPS with a provided api.CertificateSource or the
+// configured certificate files
+func (ws *WebServer) configureTLS() error {
+	ws.secure = false
+	if ws.certs != nil {
+		_ = ws.certs.Close()
+		ws.certs = nil
+	}
+
+	var getCertificate api.CertificateSource
+	if source, ok := api.Lookup[api.CertificateSource](ws.api); ok {
+		getCertificate = source
+		ws.logger.Info("TLS enabled with the provided certificate source")
+	} else if ws.config.TLSCertFile != "" {
+		certs, err := newCertReloader(ws.config.TLSCertFile, ws.config.TLSKeyFile, ws.logger)
+		if err != nil {
+			return err
+		}
+		ws.certs = certs
+		getCertificate = certs.GetCertificate
+		ws.logger.Infof("TLS enabled: %s", ws.config.TLSCertFile)
+	} else {
+		return nil
+	}
+
+	ws.secure = true
+	ws.server.TLSConfig = &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: getCertificate,
+	}
 	return nil
 }
 
-// Start starts the web server
+// Start starts the web server. Its listeners are bound before it returns, so
+// it is ready for requests (and a port already in use is an error).
 func (ws *WebServer) Start() error {
 	ws.logger.Infof("Starting web server on %s:%d...", ws.config.Host, ws.config.Port)
 
-	// Start broadcast handler
-	ws.wg.Add(1)
-	go ws.handleBroadcasts()
+	listener, err := net.Listen("tcp", ws.server.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to start web server: %w", err)
+	}
+	ws.port = listener.Addr().(*net.TCPAddr).Port
+
+	var debugListener net.Listener
+	if ws.debugServer != nil {
+		debugListener, err = net.Listen("tcp", ws.debugServer.Addr)
+		if err != nil {
+			_ = listener.Close()
+			return fmt.Errorf("failed to start web server: debug listener: %w", err)
+		}
+		ws.debugPort = debugListener.Addr().(*net.TCPAddr).Port
+	}
+
+	// Start broadcast workers
+	for _, shard := range ws.broadcastShards {
+		ws.wg.Add(1)
+		go ws.deliverBroadcasts(shard)
+	}
+
+	// Start closing idle connections and expiring sessions
+	if ws.config.ReapInterval > 0 {
+		ws.wg.Add(1)
+		go ws.reapConnections()
+	}
 
-	// Start HTTP server in goroutine, but capture startup errors
-	errChan := make(chan error, 1)
 	ws.wg.Add(1)
 	go func() {
 		defer ws.wg.Done()
-		if err := ws.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			errChan <- err
+		var err error
+		if ws.secure {
+			err = ws.server.ServeTLS(listener, "", "")
+		} else {
+			err = ws.server.Serve(listener)
+		}
+		if err != nil && err != http.ErrServerClosed {
+			ws.logger.Errorf("Web server failed: %v", err)
 		}
 	}()
 
-	// Wait briefly to catch immediate startup errors (e.g., port already in use)
-	select {
-	case err := <-errChan:
-		return fmt.Errorf("failed to start web server: %w", err)
-	case <-time.After(100 * time.Millisecond):
-		// Server started successfully
-		ws.logger.Infof("Web server started successfully")
-		return nil
+	if debugListener != nil {
+		ws.wg.Add(1)
+		go func() {
+			defer ws.wg.Done()
+			if err := ws.debugServer.Serve(debugListener); err != nil && err != http.ErrServerClosed {
+				ws.logger.Errorf("Debug listener failed: %v", err)
+			}
+		}()
 	}
+
+	ws.logger.Infof("Web server started successfully")
+	return nil
+}
+
+// Addresses returns where the web server accepts HTTP and WebSocket
+// connections (and debug requests, on a separate listener)
+func (ws *WebServer) Addresses() []string {
+	addr := fmt.Sprintf("%s:%d", ws.config.Host, ws.port)
+	httpScheme, wsScheme := "http://", "ws://"
+	if ws.secure {
+		httpScheme, wsScheme = "https://", "wss://"
+	}
+	addresses := []string{httpScheme + addr + ws.config.APIRoute, wsScheme + addr + "/ws"}
+	if ws.debugServer != nil {
+		debugAddr := fmt.Sprintf("%s:%d", ws.config.DebugHost, ws.debugPort)
+		addresses = append(addresses, "http://"+debugAddr+strings.TrimSuffix(ws.config.DebugRoute, "/"))
+	}
+	return addresses
 }
 
 // Stop stops the web server gracefully
@@ -191,6 +425,12 @@ func (ws *WebServer) Stop() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	if ws.debugServer != nil {
+		if err := ws.debugServer.Shutdown(ctx); err != nil {
+			ws.logger.Warnf("Error shutting down debug listener: %v", err)
+		}
+	}
+
 	if err := ws.server.Shutdown(ctx); err != nil {
 		ws.logger.Errorf("Error shutting down web server: %v", err)
 		return err
@@ -199,21 +439,61 @@ func (ws *WebServer) Stop() error {
 	// Wait for goroutines to finish
 	ws.wg.Wait()
 
+	if ws.certs != nil {
+		if err := ws.certs.Close(); err != nil {
+			ws.logger.Warnf("Error closing TLS certificate watcher: %v", err)
+		}
+		ws.certs = nil
+	}
+
 	ws.logger.Info("Web server stopped successfully")
 	return nil
 }
 
+// handleConfigReloaded applies reloaded CORS settings and IP lists
+func (ws *WebServer) handleConfigReloaded(_ context.Context, _ api.Event) {
+	web := ws.api.CurrentConfig().Server.Web
+
+	ws.corsMu.Lock()
+	ws.config.AllowedOrigins = web.AllowedOrigins
+	ws.config.AllowedMethods = web.AllowedMethods
+	ws.config.AllowedHeaders = web.AllowedHeaders
+	ws.corsMu.Unlock()
+
+	ws.rateLimitMu.Lock()
+	ws.config.MessageRate = web.MessageRate
+	ws.config.MessageBurst = web.MessageBurst
+	ws.config.MessageRateWarnings = web.MessageRateWarnings
+	ws.rateLimitMu.Unlock()
+
+	rules, err := newIPRules(web)
+	if err != nil {
+		ws.logger.Errorf("Keeping the current IP lists: %v", err)
+		return
+	}
+	ws.ipRulesMu.Lock()
+	ws.ipRules = rules
+	ws.ipRulesMu.Unlock()
+}
+
 // corsMiddleware adds CORS headers to responses
 func (ws *WebServer) corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Set CORS headers
+		ws.corsMu.RLock()
 		w.Header().Set("Access-Control-Allow-Origin", ws.config.AllowedOrigins)
 		w.Header().Set("Access-Control-Allow-Methods", ws.config.AllowedMethods)
 		w.Header().Set("Access-Control-Allow-Headers", ws.config.AllowedHeaders)
+		ws.corsMu.RUnlock()
 		w.Header().Set("Access-Control-Allow-Credentials", "true")
 
-		// Handle preflight requests
+		// Handle preflight requests, with the CORS policy of the route asked about
 		if r.Method == "OPTIONS" {
+			if method := r.Header.Get("Access-Control-Request-Method"); method != "" {
+				if action, _, err := ws.matchRoute(method, r.URL.Path); err == nil && action.Web.CORS != nil {
+					setActionCORSHeaders(w, r, action.Web.CORS)
+				}
+			}
 			w.WriteHeader(http.StatusOK)
 			return
 		}
@@ -222,53 +502,385 @@ func (ws *WebServer) corsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// newHTTPConnection creates the connection for an HTTP request, from the
+// pool. Put it back with releaseHTTPConnection once the response is sent.
+func (ws *WebServer) newHTTPConnection(r *http.Request) *api.Connection {
+	conn := ws.httpConnections.Get("http", r.RemoteAddr, uuid.New().String(), nil)
+	conn.SetClientInfo(ws.api.LocateClient(api.ClientInfoFromRequest(r)))
+	conn.SetTenant(api.TenantFromContext(r.Context()))
+	conn.SetDryRun(api.ParseDryRun(r.Header.Get(api.DryRunHeader)))
+	return conn
+}
+
+// releaseHTTPConnection returns an HTTP request's connection to the pool
+func (ws *WebServer) releaseHTTPConnection(conn *api.Connection) {
+	ws.httpConnections.Put(conn)
+}
+
 // handleHTTP handles HTTP requests
 func (ws *WebServer) handleHTTP(w http.ResponseWriter, r *http.Request) {
+	// Propagate the caller's request ID, or start a new one, and echo it back
+	requestID := requestIDFromHeader(r)
+	ctx := util.WithRequestID(r.Context(), requestID)
+	ctx = util.WithRequestHeaders(ctx, r.Header)
+	w.Header().Set(requestIDHeader, requestID)
+	locales := i18n.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+
 	// Find matching route
 	action, params, err := ws.matchRoute(r.Method, r.URL.Path)
 	if err != nil {
 		// For 404s, still log via connection
-		conn := api.NewConnection("http", r.RemoteAddr, uuid.New().String(), nil)
-		result := conn.Act(r.Context(), ws.api, "", nil, r.Method, r.URL.String())
-		ws.sendError(w, http.StatusNotFound, "ROUTE_NOT_FOUND", result.Error.Error())
+		conn := ws.newHTTPConnection(r)
+		defer ws.releaseHTTPConnection(conn)
+		result := conn.Act(ctx, ws.api, "", nil, r.Method, r.URL.String())
+		ws.sendError(w, http.StatusNotFound, "ROUTE_NOT_FOUND", result.Error.Error(), requestID)
 		return
 	}
 
-	actionName := api.GetActionName(action)
+	actionName := action.Name
+	if !ws.actionAllowsIP(actionName, r) {
+		ws.rejectIP(w, r)
+		return
+	}
+	if action.Web.CORS != nil {
+		setActionCORSHeaders(w, r, action.Web.CORS)
+	}
+	if action.Deprecation != nil {
+		setDeprecationHeaders(w, action.Deprecation)
+	}
+
+	if ws.config.ValidateRequests && !isSupportedContentType(r) {
+		ws.sendError(w, http.StatusBadRequest, "INVALID_CONTENT_TYPE",
+			fmt.Sprintf("unsupported Content-Type %q", r.Header.Get("Content-Type")), requestID)
+		return
+	}
+
+	if ws.config.MaxBodySize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, int64(ws.config.MaxBodySize))
+	}
 
 	// Parse request parameters
-	allParams, err := ws.parseRequest(r, params)
+	allParams, input, err := ws.parseRequest(r, params, action)
 	if err != nil {
-		conn := api.NewConnection("http", r.RemoteAddr, uuid.New().String(), nil)
-		conn.Act(r.Context(), ws.api, actionName, allParams, r.Method, r.URL.String())
-		ws.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		conn := ws.newHTTPConnection(r)
+		defer ws.releaseHTTPConnection(conn)
+		conn.Act(ctx, ws.api, actionName, allParams, r.Method, r.URL.String())
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			ws.sendError(w, http.StatusRequestEntityTooLarge, "REQUEST_TOO_LARGE",
+				fmt.Sprintf("request body exceeds %s", ws.config.MaxBodySize), requestID)
+			return
+		}
+		ws.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error(), requestID)
 		return
 	}
 
+	if schema, ok := ws.inputSchema(actionName); ok {
+		if errs := openapi.Validate(schema, allParams); len(errs) > 0 {
+			ws.logger.WithContext(ctx).Debugf("Request for %s failed schema validation: %v", actionName, errs)
+			ws.sendErrorBody(w, http.StatusUnprocessableEntity, ws.api.LocalizeError(util.ErrorJSON{
+				Code:      string(util.ErrorTypeConnectionActionParamValidation),
+				Message:   "request params do not match the schema",
+				RequestID: requestID,
+				Details:   ws.localizeValidationErrors(errs, ws.api.NegotiateLocales(allParams, locales)),
+			}, ws.api.NegotiateLocales(allParams, locales)))
+			return
+		}
+	}
+
 	// Create connection and execute action
-	conn := api.NewConnection("http", r.RemoteAddr, uuid.New().String(), nil)
-	result := conn.Act(r.Context(), ws.api, actionName, allParams, r.Method, r.URL.String())
+	if input != nil {
+		ctx = api.WithDecodedInput(ctx, allParams, input)
+	}
+	conn := ws.newHTTPConnection(r)
+	defer ws.releaseHTTPConnection(conn)
+	conn.SetLocales(locales...)
+	result := conn.Act(ctx, ws.api, actionName, allParams, r.Method, r.URL.String())
 
 	if result.Error != nil {
+		status := http.StatusInternalServerError
 		if typedErr, ok := result.Error.(*util.TypedError); ok {
-			ws.sendError(w, typedErr.HTTPStatus(), typedErr.Code(), typedErr.Message)
-		} else {
-			ws.sendError(w, http.StatusInternalServerError, "INTERNAL_ERROR", result.Error.Error())
+			if typedErr.RetryAfter > 0 {
+				w.Header().Set("Retry-After", retryAfterSeconds(typedErr.RetryAfter))
+			}
+			status = typedErr.HTTPStatus()
 		}
+		ws.sendErrorBody(w, status, ws.api.ErrorJSON(result.Error, requestID, result.Locales...))
 		return
 	}
 
+	// Report whether cacheable responses came from the cache
+	if action.Cache != nil {
+		if result.Cached {
+			w.Header().Set(cacheHeader, "HIT")
+		} else {
+			w.Header().Set(cacheHeader, "MISS")
+		}
+	}
+	if action.Web.CacheControl != "" {
+		w.Header().Set("Cache-Control", action.Web.CacheControl)
+	}
+
 	// Send response
+	if raw, ok := result.Response.(*api.RawResponse); ok {
+		ws.sendRaw(w, raw)
+		return
+	}
+	if file, ok := result.Response.(*api.FileResponse); ok {
+		ws.sendFile(w, r, file, requestID)
+		return
+	}
+	if page, ok := result.Response.(*api.PaginatedResponse); ok {
+		setPaginationHeaders(w, r, page.Pagination)
+	}
 	ws.sendSuccess(w, result.Response)
 }
 
+// setPaginationHeaders describes a page of a list action: the total count in
+// X-Total-Count, and links to the other pages in a Link header (RFC 8288)
+func setPaginationHeaders(w http.ResponseWriter, r *http.Request, meta api.PaginationMeta) {
+	if meta.Total != nil {
+		w.Header().Set("X-Total-Count", strconv.Itoa(*meta.Total))
+	}
+
+	link := func(rel string, set map[string]string) string {
+		query := r.URL.Query()
+		for key, value := range set {
+			query.Set(key, value)
+		}
+		u := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+		return fmt.Sprintf("<%s>; rel=%q", u.String(), rel)
+	}
+	page := func(n int) map[string]string {
+		return map[string]string{"page": strconv.Itoa(n)}
+	}
+
+	var links []string
+	if meta.NextCursor != "" {
+		links = append(links, link("next", map[string]string{"cursor": meta.NextCursor}))
+	} else if meta.TotalPages != nil {
+		links = append(links, link("first", page(1)))
+		if meta.Page > 1 {
+			links = append(links, link("prev", page(min(meta.Page-1, max(*meta.TotalPages, 1)))))
+		}
+		if meta.Page < *meta.TotalPages {
+			links = append(links, link("next", page(meta.Page+1)))
+		}
+		links = append(links, link("last", page(max(*meta.TotalPages, 1))))
+	}
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+}
+
+// setActionCORSHeaders replaces the server's CORS headers with an action's
+// policy. Requests from origins it doesn't allow get no
+// Access-Control-Allow-Origin, so browsers refuse them.
+func setActionCORSHeaders(w http.ResponseWriter, r *http.Request, cors *api.CORSConfig) {
+	header := w.Header()
+	header.Del("Access-Control-Allow-Credentials")
+	if cors.AllowCredentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+	if len(cors.AllowedOrigins) == 0 {
+		return
+	}
+
+	header.Add("Vary", "Origin")
+	if origin := cors.AllowOrigin(r.Header.Get("Origin")); origin != "" {
+		header.Set("Access-Control-Allow-Origin", origin)
+	} else {
+		header.Del("Access-Control-Allow-Origin")
+	}
+}
+
+// setDeprecationHeaders signals a deprecated action to HTTP callers: a
+// Deprecation header (RFC 9745) and, when the removal date is known, a
+// Sunset header (RFC 8594)
+func setDeprecationHeaders(w http.ResponseWriter, deprecation *api.DeprecationConfig) {
+	if deprecation.Since.IsZero() {
+		w.Header().Set("Deprecation", "true")
+	} else {
+		w.Header().Set("Deprecation", fmt.Sprintf("@%d", deprecation.Since.Unix()))
+	}
+	if !deprecation.Sunset.IsZero() {
+		w.Header().Set("Sunset", deprecation.Sunset.UTC().Format(http.TimeFormat))
+	}
+}
+
+// retryAfterSeconds formats d for a Retry-After header: whole seconds,
+// rounded up so callers never retry early
+func retryAfterSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
+
+// isSupportedContentType returns whether a request body (if any) uses a
+// content type the web server parses: JSON or URL-encoded form data
+func isSupportedContentType(r *http.Request) bool {
+	if r.Method != "POST" && r.Method != "PUT" && r.Method != "PATCH" {
+		return true
+	}
+	if r.ContentLength == 0 {
+		return true
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	return strings.Contains(contentType, "application/json") ||
+		strings.Contains(contentType, "application/x-www-form-urlencoded")
+}
+
+// requestIDFromHeader returns the caller's X-Request-ID if it is usable,
+// otherwise a new request ID
+func requestIDFromHeader(r *http.Request) string {
+	requestID := r.Header.Get(requestIDHeader)
+	if !isValidRequestID(requestID) {
+		return uuid.New().String()
+	}
+	return requestID
+}
+
+// isValidRequestID returns whether a client-supplied request ID is short and
+// printable ASCII, so it is safe to log and echo back
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, ch := range requestID {
+		if ch < 0x21 || ch > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// routeTable is what the web server derives from the registered actions
+type routeTable struct {
+	routes           []routeEntry
+	inputSchemas     map[string]map[string]interface{} // OpenAPI input schemas, when ValidateRequests is enabled
+	actionAllowedIPs map[string][]netip.Prefix         // Allowlists of actions that set WebConfig.AllowedIPs
+}
+
+// newRouteTable builds the routes (and input schemas) of actions, failing if
+// any of them can't be served
+func (ws *WebServer) newRouteTable(actions []*api.ActionDescriptor) (*routeTable, error) {
+	table := &routeTable{
+		inputSchemas:     make(map[string]map[string]interface{}),
+		actionAllowedIPs: make(map[string][]netip.Prefix),
+	}
+
+	actions, err := api.WebRoutes(actions)
+	if err != nil {
+		return nil, err
+	}
+	for _, action := range actions {
+		webConfig := action.Web
+		pattern, paramNames, err := compileRoute(webConfig.Route)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile route for action %s: %w", action.Name, err)
+		}
+
+		if len(webConfig.AllowedIPs) > 0 {
+			allow, err := config.ParseIPList(strings.Join(webConfig.AllowedIPs, ","))
+			if err != nil {
+				return nil, fmt.Errorf("invalid allowed IPs for action %s: %w", action.Name, err)
+			}
+			table.actionAllowedIPs[action.Name] = allow
+		}
+
+		table.routes = append(table.routes, routeEntry{
+			pattern:    pattern,
+			paramNames: paramNames,
+			method:     webConfig.Method,
+			action:     action,
+		})
+
+		if ws.config.ValidateRequests {
+			if action.Inputs != nil {
+				table.inputSchemas[action.Name] = openapi.SchemaFromStruct(action.Inputs)
+			}
+		}
+	}
+	return table, nil
+}
+
+// validateRoutes is an api.ActionsValidator refusing action changes that
+// would leave routes the web server can't serve
+func (ws *WebServer) validateRoutes(actions []*api.ActionDescriptor) error {
+	_, err := ws.newRouteTable(actions)
+	return err
+}
+
+// buildRoutes builds the routes (and input schemas) from the registered
+// actions, into a new table that replaces the current one only if it builds
+func (ws *WebServer) buildRoutes() error {
+	revision := ws.api.ActionsRevision()
+	table, err := ws.newRouteTable(ws.api.GetActionDescriptors())
+	if err != nil {
+		return err
+	}
+
+	ws.routesMu.Lock()
+	defer ws.routesMu.Unlock()
+	ws.routes = table.routes
+	ws.inputSchemas = table.inputSchemas
+	ws.actionAllowedIPs = table.actionAllowedIPs
+	ws.routesRevision = revision
+	ws.routeCache.clear()
+	for _, route := range table.routes {
+		ws.logger.Debugf("Registered route: %s %s -> %s", route.method, route.action.Web.Route, route.action.Name)
+	}
+	return nil
+}
+
+// refreshRoutes rebuilds the routes when actions were registered,
+// unregistered, or replaced since they were built. Changes that would break
+// the routes are refused (see validateRoutes), so a rebuild only fails for
+// actions changed before the server was initialized; then the error is
+// logged, and the current routes are kept, without cached matches, which may
+// name a replaced action.
+func (ws *WebServer) refreshRoutes() {
+	ws.routesMu.RLock()
+	current := ws.routesRevision == ws.api.ActionsRevision()
+	ws.routesMu.RUnlock()
+	if current {
+		return
+	}
+
+	if err := ws.buildRoutes(); err != nil {
+		ws.logger.Errorf("Failed to rebuild routes: %v", err)
+		ws.routesMu.Lock()
+		ws.routesRevision = ws.api.ActionsRevision()
+		ws.routeCache.clear()
+		ws.routesMu.Unlock()
+	}
+}
+
+// inputSchema returns the OpenAPI input schema of an action, if requests to
+// it are validated
+func (ws *WebServer) inputSchema(actionName string) (map[string]interface{}, bool) {
+	ws.routesMu.RLock()
+	defer ws.routesMu.RUnlock()
+
+	schema, ok := ws.inputSchemas[actionName]
+	return schema, ok
+}
+
 // matchRoute finds the action that matches the given method and path
-func (ws *WebServer) matchRoute(method, path string) (api.Action, map[string]string, error) {
+func (ws *WebServer) matchRoute(method, path string) (*api.ActionDescriptor, map[string]string, error) {
 	// Remove API route prefix if present
 	if ws.config.APIRoute != "" && strings.HasPrefix(path, ws.config.APIRoute) {
 		path = strings.TrimPrefix(path, ws.config.APIRoute)
 	}
 
+	ws.refreshRoutes()
+	ws.routesMu.RLock()
+	defer ws.routesMu.RUnlock()
+
+	key := routeCacheKey(method, path)
+	if action, params, ok := ws.routeCache.get(key); ok {
+		return action, params, nil
+	}
+
 	for _, route := range ws.routes {
 		if string(route.method) != method {
 			continue
@@ -285,15 +897,25 @@ func (ws *WebServer) matchRoute(method, path string) (api.Action, map[string]str
 			params[name] = matches[i+1]
 		}
 
+		// Cached while the routes are read-locked, so a rebuild can't
+		// clear the cache in between
+		ws.routeCache.add(key, route.action, params)
 		return route.action, params, nil
 	}
 
 	return nil, nil, fmt.Errorf("no route found for %s %s", method, path)
 }
 
-// parseRequest extracts all parameters from the request
-func (ws *WebServer) parseRequest(r *http.Request, pathParams map[string]string) (map[string]interface{}, error) {
-	params := make(map[string]interface{})
+// parseRequest extracts all parameters from the request. Body params take
+// precedence over query params, which take precedence over path params.
+// When action has a typed input (see api.InputDecoder) and all its params
+// are in a JSON body, the body is also decoded straight into the input,
+// which is returned so the action needn't convert the params to it. The
+// params are still returned, for validation, caching, events, and logs.
+func (ws *WebServer) parseRequest(r *http.Request, pathParams map[string]string, action *api.ActionDescriptor) (map[string]interface{}, interface{}, error) {
+	query := r.URL.Query()
+	params := make(map[string]interface{}, len(pathParams)+len(query))
+	var input interface{}
 
 	// Add path parameters
 	for k, v := range pathParams {
@@ -301,7 +923,7 @@ func (ws *WebServer) parseRequest(r *http.Request, pathParams map[string]string)
 	}
 
 	// Add query parameters
-	for k, v := range r.URL.Query() {
+	for k, v := range query {
 		if len(v) == 1 {
 			params[k] = v[0]
 		} else {
@@ -313,20 +935,25 @@ func (ws *WebServer) parseRequest(r *http.Request, pathParams map[string]string)
 	if r.Method == "POST" || r.Method == "PUT" || r.Method == "PATCH" {
 		contentType := r.Header.Get("Content-Type")
 
-		if strings.Contains(contentType, "application/json") {
-			// Parse JSON body
-			var jsonBody map[string]interface{}
-			if err := json.NewDecoder(r.Body).Decode(&jsonBody); err != nil {
-				return nil, fmt.Errorf("failed to parse JSON body: %w", err)
+		decoder, typed := action.Action.(api.InputDecoder)
+		if typed && len(params) == 0 && strings.Contains(contentType, "application/json") {
+			var err error
+			if input, err = parseJSONBodyInput(r, params, decoder); err != nil {
+				return nil, nil, err
 			}
-			// Merge JSON body params
-			for k, v := range jsonBody {
-				params[k] = v
+		} else if strings.Contains(contentType, "application/json") {
+			// Decoding into the existing map merges the body params over the
+			// others, without building a second map. A JSON null body would
+			// set the map to nil, so it is decoded through a copy of the
+			// reference.
+			body := params
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				return nil, nil, fmt.Errorf("failed to parse JSON body: %w", err)
 			}
 		} else if strings.Contains(contentType, "application/x-www-form-urlencoded") {
 			// Parse form data
 			if err := r.ParseForm(); err != nil {
-				return nil, fmt.Errorf("failed to parse form data: %w", err)
+				return nil, nil, fmt.Errorf("failed to parse form data: %w", err)
 			}
 			for k, v := range r.PostForm {
 				if len(v) == 1 {
@@ -338,40 +965,149 @@ func (ws *WebServer) parseRequest(r *http.Request, pathParams map[string]string)
 		}
 	}
 
-	return params, nil
+	return params, input, nil
+}
+
+// parseJSONBodyInput merges a JSON body's params into params, as
+// parseRequest does, and decodes the body straight into the action's typed
+// input, returning it. It is only used when params is empty: path and query
+// params would have to be converted to the input too. The input is nil when
+// the body doesn't fit it; the action reports that when it converts the
+// params.
+func parseJSONBodyInput(r *http.Request, params map[string]interface{}, decoder api.InputDecoder) (interface{}, error) {
+	data, err := readBody(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JSON body: %w", err)
+	}
+	body := params
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON body: %w", err)
+	}
+	input, err := decoder.DecodeInput(data)
+	if err != nil {
+		return nil, nil
+	}
+	return input, nil
+}
+
+// readBody reads a request's body, in a single allocation when its length is
+// known and at most maxBodyPrealloc
+func readBody(r *http.Request) ([]byte, error) {
+	if r.ContentLength > 0 && r.ContentLength <= maxBodyPrealloc {
+		data := make([]byte, r.ContentLength)
+		if _, err := io.ReadFull(r.Body, data); err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+	return io.ReadAll(r.Body)
 }
 
 // sendSuccess sends a successful JSON response
 func (ws *WebServer) sendSuccess(w http.ResponseWriter, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
+	ws.writeJSON(w, http.StatusOK, successResponse{Success: true, Data: data})
+}
+
+// sendRaw sends an action's raw (non-JSON) response body
+func (ws *WebServer) sendRaw(w http.ResponseWriter, raw *api.RawResponse) {
+	if raw.ContentType != "" {
+		w.Header().Set("Content-Type", raw.ContentType)
+	}
 	w.WriteHeader(http.StatusOK)
 
-	response := map[string]interface{}{
-		"success": true,
-		"data":    data,
+	if _, err := w.Write(raw.Body); err != nil {
+		ws.logger.Errorf("Error writing response: %v", err)
 	}
+}
 
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		ws.logger.Errorf("Error encoding response: %v", err)
+// sendFile streams an action's file response. http.ServeContent handles Range
+// and conditional requests, and copies *os.File content with sendfile.
+func (ws *WebServer) sendFile(w http.ResponseWriter, r *http.Request, file *api.FileResponse, requestID string) {
+	content, modTime := file.Content, file.ModTime
+	if content == nil {
+		f, err := os.Open(file.Path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				ws.sendError(w, http.StatusNotFound, "FILE_NOT_FOUND", "file not found", requestID)
+				return
+			}
+			ws.logger.Errorf("Error opening file %s: %v", file.Path, err)
+			ws.sendError(w, http.StatusInternalServerError, "FILE_UNREADABLE", "file could not be read", requestID)
+			return
+		}
+		info, err := f.Stat()
+		if err != nil || info.IsDir() {
+			_ = f.Close()
+			ws.sendError(w, http.StatusNotFound, "FILE_NOT_FOUND", "file not found", requestID)
+			return
+		}
+		if modTime.IsZero() {
+			modTime = info.ModTime()
+		}
+		content = f
+	}
+	if closer, ok := content.(io.Closer); ok {
+		defer func() { _ = closer.Close() }()
 	}
+
+	name := file.FileName()
+	if file.ContentType != "" {
+		w.Header().Set("Content-Type", file.ContentType)
+	}
+	if file.Download {
+		disposition := "attachment"
+		if name != "" {
+			disposition = mime.FormatMediaType(disposition, map[string]string{"filename": name})
+		}
+		w.Header().Set("Content-Disposition", disposition)
+	}
+	clearWriteDeadline(w)
+	http.ServeContent(w, r, name, modTime, content)
 }
 
-// sendError sends an error JSON response
-func (ws *WebServer) sendError(w http.ResponseWriter, status int, code, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
+// clearWriteDeadline lifts the server's write timeout for one response.
+// Files can take longer to send than it allows (large downloads, slow
+// clients), so they are streamed without one.
+func clearWriteDeadline(w http.ResponseWriter) {
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+}
 
-	response := map[string]interface{}{
-		"success": false,
-		"error": map[string]interface{}{
-			"code":    code,
-			"message": message,
-		},
+// sendError sends an error JSON response. The request ID is included when not empty.
+func (ws *WebServer) sendError(w http.ResponseWriter, status int, code, message, requestID string) {
+	ws.sendErrorWithDetails(w, status, code, message, requestID, nil)
+}
+
+// sendErrorWithDetails sends an error JSON response with optional details
+// (e.g., schema validation errors)
+func (ws *WebServer) sendErrorWithDetails(w http.ResponseWriter, status int, code, message, requestID string, details interface{}) {
+	ws.sendErrorBody(w, status, util.ErrorJSON{Code: code, Message: message, RequestID: requestID, Details: details})
+}
+
+// sendErrorBody sends an error JSON response
+func (ws *WebServer) sendErrorBody(w http.ResponseWriter, status int, errorBody util.ErrorJSON) {
+	ws.writeJSON(w, status, errorResponse{Success: false, Error: errorBody})
+}
+
+// localizeValidationErrors translates schema validation messages using the
+// catalog key "validation.<rule>" (e.g., "validation.minLength"). {limit} in
+// a translation is replaced with the rule's value.
+func (ws *WebServer) localizeValidationErrors(errs []openapi.ValidationError, locales []string) []openapi.ValidationError {
+	if ws.api.Messages == nil || len(locales) == 0 {
+		return errs
 	}
 
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		ws.logger.Errorf("Error encoding error response: %v", err)
+	localized := make([]openapi.ValidationError, len(errs))
+	for i, validationErr := range errs {
+		vars := map[string]string{}
+		if validationErr.Limit != nil {
+			vars["limit"] = fmt.Sprint(validationErr.Limit)
+		}
+		if message, ok := ws.api.Messages.Translate(locales, "validation."+validationErr.Rule, vars); ok {
+			validationErr.Message = message
+		}
+		localized[i] = validationErr
 	}
+	return localized
 }
 
 // compileRoute converts a route pattern to a regex
@@ -412,12 +1148,27 @@ func (ws *WebServer) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	// Create connection
 	connID := uuid.New().String()
 	apiConn := api.NewConnection("websocket", r.RemoteAddr, connID, conn)
-
+	apiConn.SetLocales(i18n.ParseAcceptLanguage(r.Header.Get("Accept-Language"))...)
+	clientInfo := api.ClientInfoFromRequest(r)
+	clientInfo.Protocol = "websocket"
+	clientInfo.Subprotocol = conn.Subprotocol()
+	apiConn.SetClientInfo(ws.api.LocateClient(clientInfo))
+	apiConn.SetTenant(api.TenantFromContext(r.Context()))
+	apiConn.SetDryRun(api.ParseDryRun(r.Header.Get(api.DryRunHeader)))
+
+	ws.rateLimitMu.RLock()
+	limiter := newMessageLimiter(ws.config.MessageRate, ws.config.MessageBurst)
+	ws.rateLimitMu.RUnlock()
 	wsConn := &wsConnection{
 		conn:       conn,
 		connection: apiConn,
 		send:       make(chan []byte, 256),
+		limiter:    limiter,
+		inFlight:   make(chan struct{}, max(ws.config.MaxActionsInFlight, 1)),
 	}
+	apiConn.SetProgressHandler(func(progress api.Progress) {
+		ws.sendWebSocketProgress(wsConn, progress)
+	})
 
 	// Register connection
 	ws.connectionsMu.Lock()
@@ -425,6 +1176,7 @@ func (ws *WebServer) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	ws.connectionsMu.Unlock()
 
 	ws.logger.Debugf("WebSocket connection established: %s", connID)
+	ws.api.Emit(ws.ctx, api.Event{Name: api.EventConnectionOpen, Connection: apiConn})
 
 	// Start goroutines for reading and writing
 	ws.wg.Add(2)
@@ -435,6 +1187,7 @@ func (ws *WebServer) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 // readWebSocket reads messages from WebSocket
 func (ws *WebServer) readWebSocket(wsConn *wsConnection) {
 	defer func() {
+		wsConn.actions.Wait() // Their responses are sent before the connection closes
 		ws.wg.Done()
 		_ = ws.removeConnection(wsConn)
 	}()
@@ -449,8 +1202,13 @@ func (ws *WebServer) readWebSocket(wsConn *wsConnection) {
 			break
 		}
 
-		// Handle message
-		ws.handleWebSocketMessage(wsConn, msg)
+		allowed, disconnect := ws.rateLimit(wsConn)
+		if disconnect {
+			break
+		}
+		if allowed {
+			ws.handleWebSocketMessage(wsConn, msg)
+		}
 	}
 }
 
@@ -467,7 +1225,11 @@ func (ws *WebServer) writeWebSocket(wsConn *wsConnection) {
 		select {
 		case message, ok := <-wsConn.send:
 			if !ok {
-				if err := wsConn.conn.WriteMessage(websocket.CloseMessage, []byte{}); err != nil {
+				closeMessage := wsConn.closeMessage
+				if closeMessage == nil {
+					closeMessage = []byte{}
+				}
+				if err := wsConn.conn.WriteMessage(websocket.CloseMessage, closeMessage); err != nil {
 					ws.logger.Warnf("Error writing close message: %v", err)
 				}
 				return
@@ -486,9 +1248,11 @@ func (ws *WebServer) writeWebSocket(wsConn *wsConnection) {
 
 // handleWebSocketMessage processes incoming WebSocket messages
 func (ws *WebServer) handleWebSocketMessage(wsConn *wsConnection, msg map[string]interface{}) {
+	wsConn.connection.Touch()
+
 	messageType, ok := msg["type"].(string)
 	if !ok {
-		ws.sendWebSocketError(wsConn, "INVALID_MESSAGE", "Message type is required")
+		ws.sendWebSocketError(wsConn, "INVALID_MESSAGE", "Message type is required", "")
 		return
 	}
 
@@ -499,8 +1263,19 @@ func (ws *WebServer) handleWebSocketMessage(wsConn *wsConnection, msg map[string
 		ws.handleWebSocketSubscribe(wsConn, msg)
 	case "unsubscribe":
 		ws.handleWebSocketUnsubscribe(wsConn, msg)
+	case "paramAdd":
+		ws.handleWebSocketParamAdd(wsConn, msg)
+	case "paramDelete":
+		ws.handleWebSocketParamDelete(wsConn, msg)
+	case "paramsDelete":
+		wsConn.connection.ClearParams()
+		ws.sendWebSocketParams(wsConn)
+	case "paramsView":
+		ws.sendWebSocketParams(wsConn)
+	case "locale":
+		ws.handleWebSocketLocale(wsConn, msg)
 	default:
-		ws.sendWebSocketError(wsConn, "UNKNOWN_MESSAGE_TYPE", fmt.Sprintf("Unknown message type: %s", messageType))
+		ws.sendWebSocketError(wsConn, "UNKNOWN_MESSAGE_TYPE", fmt.Sprintf("Unknown message type: %s", messageType), "")
 	}
 }
 
@@ -508,7 +1283,7 @@ func (ws *WebServer) handleWebSocketMessage(wsConn *wsConnection, msg map[string
 func (ws *WebServer) handleWebSocketAction(wsConn *wsConnection, msg map[string]interface{}) {
 	actionName, ok := msg["action"].(string)
 	if !ok {
-		ws.sendWebSocketError(wsConn, "INVALID_MESSAGE", "Action name is required")
+		ws.sendWebSocketError(wsConn, "INVALID_MESSAGE", "Action name is required", "")
 		return
 	}
 
@@ -517,30 +1292,49 @@ func (ws *WebServer) handleWebSocketAction(wsConn *wsConnection, msg map[string]
 		params = make(map[string]interface{})
 	}
 
-	// Execute action via Connection.Act()
-	result := wsConn.connection.Act(context.Background(), ws.api, actionName, params, "WEBSOCKET", "")
-	if result.Error != nil {
-		if typedErr, ok := result.Error.(*util.TypedError); ok {
-			ws.sendWebSocketError(wsConn, typedErr.Code(), typedErr.Message)
-		} else {
-			ws.sendWebSocketError(wsConn, "INTERNAL_ERROR", result.Error.Error())
-		}
-		return
+	// Use the client's request ID if one was sent with the message
+	ctx := context.Background()
+	if requestID, ok := msg["requestId"].(string); ok && isValidRequestID(requestID) {
+		ctx = util.WithRequestID(ctx, requestID)
 	}
 
-	// Send response
-	ws.sendWebSocketSuccess(wsConn, result.Response)
+	// Run the action off the read loop, so a slow one doesn't hold up the
+	// connection's later messages. Once the connection has as many actions
+	// running as it may, reading waits for one to finish.
+	wsConn.inFlight <- struct{}{}
+	previous := wsConn.lastResponse
+	done := make(chan struct{})
+	wsConn.lastResponse = done
+	wsConn.actions.Add(1)
+
+	go func() {
+		defer wsConn.actions.Done()
+		defer close(done)
+
+		// Execute action via Connection.Act()
+		result := wsConn.connection.Act(ctx, ws.api, actionName, params, "WEBSOCKET", "")
+		<-wsConn.inFlight
+		if ws.config.OrderedResponses && previous != nil {
+			<-previous
+		}
+
+		if result.Error != nil {
+			ws.sendWebSocketLocalizedError(wsConn, ws.api.ErrorJSON(result.Error, result.RequestID, result.Locales...))
+			return
+		}
+		ws.sendWebSocketSuccess(wsConn, result.Response, result.RequestID)
+	}()
 }
 
 // handleWebSocketSubscribe handles subscription requests
 func (ws *WebServer) handleWebSocketSubscribe(wsConn *wsConnection, msg map[string]interface{}) {
 	channel, ok := msg["channel"].(string)
 	if !ok {
-		ws.sendWebSocketError(wsConn, "INVALID_MESSAGE", "Channel name is required")
+		ws.sendWebSocketError(wsConn, "INVALID_MESSAGE", "Channel name is required", "")
 		return
 	}
 
-	wsConn.connection.Subscribe(channel)
+	ws.subscribe(wsConn, channel)
 	ws.logger.Debugf("Connection %s subscribed to channel: %s", wsConn.connection.ID, channel)
 
 	// Send confirmation
@@ -556,11 +1350,11 @@ func (ws *WebServer) handleWebSocketSubscribe(wsConn *wsConnection, msg map[stri
 func (ws *WebServer) handleWebSocketUnsubscribe(wsConn *wsConnection, msg map[string]interface{}) {
 	channel, ok := msg["channel"].(string)
 	if !ok {
-		ws.sendWebSocketError(wsConn, "INVALID_MESSAGE", "Channel name is required")
+		ws.sendWebSocketError(wsConn, "INVALID_MESSAGE", "Channel name is required", "")
 		return
 	}
 
-	wsConn.connection.Unsubscribe(channel)
+	ws.unsubscribe(wsConn, channel)
 	ws.logger.Debugf("Connection %s unsubscribed from channel: %s", wsConn.connection.ID, channel)
 
 	// Send confirmation
@@ -572,26 +1366,114 @@ func (ws *WebServer) handleWebSocketUnsubscribe(wsConn *wsConnection, msg map[st
 	wsConn.send <- data
 }
 
-// sendWebSocketSuccess sends a success message via WebSocket
-func (ws *WebServer) sendWebSocketSuccess(wsConn *wsConnection, data interface{}) {
+// handleWebSocketParamAdd stores a sticky param, merged into every later
+// action call on the connection
+func (ws *WebServer) handleWebSocketParamAdd(wsConn *wsConnection, msg map[string]interface{}) {
+	key, ok := msg["key"].(string)
+	if !ok || key == "" {
+		ws.sendWebSocketError(wsConn, "INVALID_MESSAGE", "Param key is required", "")
+		return
+	}
+
+	wsConn.connection.SetParam(key, msg["value"])
+	ws.sendWebSocketParams(wsConn)
+}
+
+// handleWebSocketParamDelete removes a sticky param
+func (ws *WebServer) handleWebSocketParamDelete(wsConn *wsConnection, msg map[string]interface{}) {
+	key, ok := msg["key"].(string)
+	if !ok || key == "" {
+		ws.sendWebSocketError(wsConn, "INVALID_MESSAGE", "Param key is required", "")
+		return
+	}
+
+	wsConn.connection.DeleteParam(key)
+	ws.sendWebSocketParams(wsConn)
+}
+
+// handleWebSocketLocale sets the connection's preferred locales. The locale
+// may be a single tag ("fr") or an Accept-Language list ("fr-CH, fr;q=0.9").
+func (ws *WebServer) handleWebSocketLocale(wsConn *wsConnection, msg map[string]interface{}) {
+	locale, _ := msg["locale"].(string)
+	locales := i18n.ParseAcceptLanguage(locale)
+	if len(locales) == 0 {
+		ws.sendWebSocketError(wsConn, "INVALID_MESSAGE", "Locale is required", "")
+		return
+	}
+
+	wsConn.connection.SetLocales(locales...)
 	response := map[string]interface{}{
-		"type":    "response",
-		"success": true,
-		"data":    data,
+		"type":    "locale",
+		"locales": locales,
+	}
+	data, _ := json.Marshal(response)
+	wsConn.send <- data
+}
+
+// sendWebSocketParams sends the connection's sticky params
+func (ws *WebServer) sendWebSocketParams(wsConn *wsConnection) {
+	response := map[string]interface{}{
+		"type":   "params",
+		"params": wsConn.connection.Params(),
+	}
+	data, _ := json.Marshal(response)
+	wsConn.send <- data
+}
+
+// sendWebSocketSuccess sends an action's response via WebSocket, with its
+// request ID so clients can match responses to requests
+func (ws *WebServer) sendWebSocketSuccess(wsConn *wsConnection, data interface{}, requestID string) {
+	response := map[string]interface{}{
+		"type":      "response",
+		"success":   true,
+		"data":      data,
+		"requestId": requestID,
 	}
 	responseData, _ := json.Marshal(response)
 	wsConn.send <- responseData
 }
 
-// sendWebSocketError sends an error message via WebSocket
-func (ws *WebServer) sendWebSocketError(wsConn *wsConnection, code, message string) {
+// sendWebSocketProgress sends an action's progress report via WebSocket.
+// Reports are dropped rather than holding up the action while the client is
+// behind on reading.
+func (ws *WebServer) sendWebSocketProgress(wsConn *wsConnection, progress api.Progress) {
+	response := map[string]interface{}{
+		"type":    "progress",
+		"percent": progress.Percent,
+	}
+	if progress.Message != "" {
+		response["message"] = progress.Message
+	}
+	if progress.RequestID != "" {
+		response["requestId"] = progress.RequestID
+	}
+	data, _ := json.Marshal(response)
+	select {
+	case wsConn.send <- data:
+	default:
+	}
+}
+
+// sendWebSocketError sends an error message via WebSocket. The request ID is included when not empty.
+func (ws *WebServer) sendWebSocketError(wsConn *wsConnection, code, message, requestID string) {
+	ws.sendWebSocketErrorBody(wsConn, util.ErrorJSON{Code: code, Message: message, RequestID: requestID})
+}
+
+// sendWebSocketErrorBody sends an error response to a WebSocket connection,
+// translated into the connection's locale (its locale sticky param, then its
+// Accept-Language or locale message)
+func (ws *WebServer) sendWebSocketErrorBody(wsConn *wsConnection, errorBody util.ErrorJSON) {
+	locales := ws.api.NegotiateLocales(wsConn.connection.Params(), wsConn.connection.Locales())
+	ws.sendWebSocketLocalizedError(wsConn, ws.api.LocalizeError(errorBody, locales))
+}
+
+// sendWebSocketLocalizedError sends an already translated error response to a
+// WebSocket connection
+func (ws *WebServer) sendWebSocketLocalizedError(wsConn *wsConnection, errorBody util.ErrorJSON) {
 	response := map[string]interface{}{
 		"type":    "response",
 		"success": false,
-		"error": map[string]interface{}{
-			"code":    code,
-			"message": message,
-		},
+		"error":   errorBody,
 	}
 	responseData, _ := json.Marshal(response)
 	wsConn.send <- responseData
@@ -602,8 +1484,16 @@ func (ws *WebServer) removeConnection(wsConn *wsConnection) error {
 	ws.connectionsMu.Lock()
 	delete(ws.connections, wsConn.connection.ID)
 	ws.connectionsMu.Unlock()
+	ws.unsubscribeAll(wsConn)
+
+	ws.api.Emit(context.Background(), api.Event{Name: api.EventConnectionClose, Connection: wsConn.connection})
 
 	close(wsConn.send)
+	if wsConn.closeMessage != nil {
+		// The writer closes the connection once it has sent everything
+		ws.logger.Debugf("WebSocket connection closing: %s", wsConn.connection.ID)
+		return nil
+	}
 	if err := wsConn.conn.Close(); err != nil {
 		ws.logger.Warnf("Error closing WebSocket connection: %v", err)
 		return err
@@ -612,52 +1502,3 @@ func (ws *WebServer) removeConnection(wsConn *wsConnection) error {
 	ws.logger.Debugf("WebSocket connection closed: %s", wsConn.connection.ID)
 	return nil
 }
-
-// handleBroadcasts handles broadcasting messages to subscribed connections
-func (ws *WebServer) handleBroadcasts() {
-	defer ws.wg.Done()
-
-	for {
-		select {
-		case msg := <-ws.broadcast:
-			ws.connectionsMu.RLock()
-			for _, conn := range ws.connections {
-				if conn.connection.IsSubscribed(msg.channel) {
-					select {
-					case conn.send <- msg.data:
-					default:
-						// Channel full, skip this message
-						ws.logger.Warnf("Failed to send broadcast to connection %s (channel full)", conn.connection.ID)
-					}
-				}
-			}
-			ws.connectionsMu.RUnlock()
-
-		case <-ws.ctx.Done():
-			return
-		}
-	}
-}
-
-// Broadcast sends a message to all connections subscribed to a channel
-func (ws *WebServer) Broadcast(channel string, data interface{}) error {
-	message := map[string]interface{}{
-		"type":    "broadcast",
-		"channel": channel,
-		"data":    data,
-	}
-
-	messageData, err := json.Marshal(message)
-	if err != nil {
-		return fmt.Errorf("failed to marshal broadcast message: %w", err)
-	}
-
-	select {
-	case ws.broadcast <- broadcastMessage{channel: channel, data: messageData}:
-		return nil
-	case <-ws.ctx.Done():
-		return fmt.Errorf("server is shutting down")
-	default:
-		return fmt.Errorf("broadcast channel is full")
-	}
-}
@@ -0,0 +1,158 @@
+package servers
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+func BenchmarkWebServer_HTTPAction(b *testing.B) {
+	ws, apiInstance := setupTestServer(nil)
+	ws.routeCache = newRouteCache(config.DefaultWebServerConfig().RouteCacheSize)
+	action := newTestAction("test:bench", "/bench/:id", api.HTTPMethodGET, map[string]interface{}{"name": "bench"}, nil)
+	if err := apiInstance.RegisterAction(action); err != nil {
+		b.Fatalf("Failed to register action: %v", err)
+	}
+	if err := ws.Initialize(); err != nil {
+		b.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		req := httptest.NewRequest("GET", "/api/bench/42?limit=10", nil)
+		w := httptest.NewRecorder()
+		ws.server.Handler.ServeHTTP(w, req)
+	}
+}
+
+func BenchmarkWebServer_HTTPActionJSONBody(b *testing.B) {
+	ws, apiInstance := setupTestServer(nil)
+	action := newTestAction("test:benchbody", "/bench", api.HTTPMethodPOST, map[string]interface{}{"name": "bench"}, nil)
+	if err := apiInstance.RegisterAction(action); err != nil {
+		b.Fatalf("Failed to register action: %v", err)
+	}
+	if err := ws.Initialize(); err != nil {
+		b.Fatalf("Failed to initialize server: %v", err)
+	}
+	body := `{"name":"bench","email":"bench@example.com","tags":["a","b"],"age":42}`
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		req := httptest.NewRequest("POST", "/api/bench?limit=10", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		ws.server.Handler.ServeHTTP(w, req)
+	}
+}
+
+func BenchmarkWebServer_HTTPActionTypedJSONBody(b *testing.B) {
+	ws, apiInstance := setupTestServer(nil)
+	action := api.NewTypedAction[echoInput, echoInput](&echoTypedAction{BaseAction: api.BaseAction{
+		ActionName: "test:benchtyped",
+		ActionWeb:  &api.WebConfig{Route: "/bench", Method: api.HTTPMethodPOST},
+	}})
+	if err := apiInstance.RegisterAction(action); err != nil {
+		b.Fatalf("Failed to register action: %v", err)
+	}
+	if err := ws.Initialize(); err != nil {
+		b.Fatalf("Failed to initialize server: %v", err)
+	}
+	body := `{"id":"bench","page":2,"count":42}`
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		req := httptest.NewRequest("POST", "/api/bench", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		ws.server.Handler.ServeHTTP(w, req)
+	}
+}
+
+func BenchmarkWebServer_SendError(b *testing.B) {
+	ws, _ := setupTestServer(nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w := httptest.NewRecorder()
+		ws.sendErrorBody(w, 404, util.ErrorJSON{Code: "ROUTE_NOT_FOUND", Message: "not found", RequestID: "abc"})
+	}
+}
+
+func BenchmarkWebServer_MatchRoute(b *testing.B) {
+	for _, size := range []int{0, config.DefaultWebServerConfig().RouteCacheSize} {
+		b.Run(fmt.Sprintf("cache=%d", size), func(b *testing.B) {
+			ws, apiInstance := setupTestServer(nil)
+			ws.routeCache = newRouteCache(size)
+			for i := 0; i < 100; i++ {
+				action := newTestAction(fmt.Sprintf("test:route%d", i), fmt.Sprintf("/route%d/:id", i), api.HTTPMethodGET, nil, nil)
+				if err := apiInstance.RegisterAction(action); err != nil {
+					b.Fatalf("Failed to register action: %v", err)
+				}
+			}
+			if err := ws.buildRoutes(); err != nil {
+				b.Fatalf("Failed to build routes: %v", err)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := ws.matchRoute("GET", "/api/route99/42"); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkWebServer_SendSuccess(b *testing.B) {
+	ws, _ := setupTestServer(nil)
+	data := map[string]interface{}{"id": 42, "name": "bench", "tags": []string{"a", "b"}}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w := httptest.NewRecorder()
+		ws.sendSuccess(w, data)
+	}
+}
+
+func BenchmarkWebServer_Broadcast(b *testing.B) {
+	for _, subscribers := range []int{100, 10000} {
+		b.Run(fmt.Sprintf("subscribers=%d", subscribers), func(b *testing.B) {
+			ws, _ := setupTestServer(nil)
+			ws.broadcastShards = newBroadcastShards(4)
+			conns := make([]*wsConnection, subscribers)
+			for i := range conns {
+				conns[i] = newTestWSConnection(fmt.Sprintf("conn-%d", i))
+				ws.subscribe(conns[i], "room")
+			}
+			// An idle channel on the same server isn't visited
+			for i := 0; i < subscribers; i++ {
+				ws.subscribe(newTestWSConnection(fmt.Sprintf("idle-%d", i)), "lobby")
+			}
+			shard := ws.broadcastShardFor("room")
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := ws.Broadcast("room", i); err != nil {
+					b.Fatal(err)
+				}
+				// Deliver inline so only the fan-out is measured
+				ws.fanOut(shard, <-shard.queue)
+				for _, conn := range conns {
+					<-conn.send
+				}
+			}
+		})
+	}
+}
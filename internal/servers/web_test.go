@@ -4,8 +4,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -101,6 +108,198 @@ func TestWebServer_Initialize(t *testing.T) {
 	}
 }
 
+func TestWebServer_Addresses(t *testing.T) {
+	ws, _ := setupTestServer(t)
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	want := []string{"http://localhost:9999/api", "ws://localhost:9999/ws"}
+	if got := ws.Addresses(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected addresses %v, got %v", want, got)
+	}
+}
+
+func TestNewTestWebServer(t *testing.T) {
+	_, apiInstance := setupTestServer(t)
+	if err := apiInstance.RegisterAction(newTestAction("test:status", "/status", api.HTTPMethodGET, "ok", nil)); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	ws := NewTestWebServer(t, apiInstance)
+
+	if ws.port == 0 || ws.port == apiInstance.Config.Server.Web.Port {
+		t.Errorf("Expected a random port, got %d", ws.port)
+	}
+	want := []string{ws.URL + "/api", ws.WebSocketURL}
+	if got := ws.Addresses(); !reflect.DeepEqual(got, want) || !strings.HasPrefix(ws.URL, "http://") {
+		t.Errorf("Expected addresses %v on the bound port, got %v", want, got)
+	}
+
+	// Ready without waiting
+	resp, err := http.Get(ws.URL + "/api/status")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200, got %d", resp.StatusCode)
+	}
+}
+
+func TestWebServer_StartPortInUse(t *testing.T) {
+	_, apiInstance := setupTestServer(t)
+	running := NewTestWebServer(t, apiInstance)
+
+	ws := NewWebServer(apiInstance)
+	ws.config.Port = running.port
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+	if err := ws.Start(); err == nil {
+		_ = ws.Stop()
+		t.Fatal("Expected an error starting on a port in use")
+	}
+}
+
+func TestWebServer_Restart(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+	ws.config.Port = 0 // A random port
+
+	action := newTestAction("test:restart", "/restart", api.HTTPMethodGET, "ok", nil)
+	if err := apiInstance.RegisterAction(action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := ws.Initialize(); err != nil {
+			t.Fatalf("Failed to initialize server: %v", err)
+		}
+		if err := ws.Start(); err != nil {
+			t.Fatalf("Failed to start server (run %d): %v", i+1, err)
+		}
+
+		resp, err := http.Get(ws.Addresses()[0] + "/restart")
+		if err != nil {
+			t.Fatalf("Request failed (run %d): %v", i+1, err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected 200 (run %d), got %d", i+1, resp.StatusCode)
+		}
+
+		if err := ws.Stop(); err != nil {
+			t.Fatalf("Failed to stop server: %v", err)
+		}
+	}
+
+	if len(ws.routes) != 1 {
+		t.Errorf("Expected routes not to be duplicated, got %d", len(ws.routes))
+	}
+}
+
+func TestWebServer_HotSwapActions(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+
+	if err := apiInstance.RegisterAction(newTestAction("test:swap", "/swap", api.HTTPMethodGET, "v1", nil)); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	get := func(path string) (int, map[string]interface{}) {
+		req := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		ws.server.Handler.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		_ = json.NewDecoder(w.Body).Decode(&response)
+		return w.Code, response
+	}
+
+	// Registered after Initialize
+	if err := apiInstance.RegisterAction(newTestAction("test:late", "/late", api.HTTPMethodGET, "late", nil)); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	if status, _ := get("/api/late"); status != http.StatusOK {
+		t.Errorf("Expected a route for the late action, got %d", status)
+	}
+
+	// Replaced
+	if err := apiInstance.ReplaceAction(newTestAction("test:swap", "/swapped", api.HTTPMethodGET, "v2", nil)); err != nil {
+		t.Fatalf("Failed to replace action: %v", err)
+	}
+	if status, _ := get("/api/swap"); status != http.StatusNotFound {
+		t.Errorf("Expected the old route to be gone, got %d", status)
+	}
+	status, response := get("/api/swapped")
+	if status != http.StatusOK || response["data"].(map[string]interface{})["data"] != "v2" {
+		t.Errorf("Expected the replacement's response, got %d %v", status, response)
+	}
+
+	// Unregistered
+	if err := apiInstance.UnregisterAction("test:swap"); err != nil {
+		t.Fatalf("Failed to unregister action: %v", err)
+	}
+	if status, _ := get("/api/swapped"); status != http.StatusNotFound {
+		t.Errorf("Expected the route to be gone, got %d", status)
+	}
+}
+
+func TestWebServer_ReplaceActionRouteConflict(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+
+	if err := apiInstance.RegisterAction(newTestAction("test:first", "/first", api.HTTPMethodGET, "first", nil)); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	if err := apiInstance.RegisterAction(newTestAction("test:second", "/second", api.HTTPMethodGET, "second", nil)); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	get := func(path string) (int, map[string]interface{}) {
+		req := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		ws.server.Handler.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		_ = json.NewDecoder(w.Body).Decode(&response)
+		return w.Code, response
+	}
+	data := func(response map[string]interface{}) interface{} {
+		if data, ok := response["data"].(map[string]interface{}); ok {
+			return data["data"]
+		}
+		return nil
+	}
+
+	// Cache the route of test:second
+	if _, response := get("/api/second"); data(response) != "second" {
+		t.Fatalf("Expected test:second's response, got %v", response)
+	}
+
+	// A replacement whose route conflicts with another action's is refused
+	if err := apiInstance.ReplaceAction(newTestAction("test:second", "/first", api.HTTPMethodGET, "conflict", nil)); err == nil {
+		t.Fatal("Expected an error replacing an action with a conflicting route")
+	}
+	if _, response := get("/api/second"); data(response) != "second" {
+		t.Errorf("Expected the old action to keep serving, got %v", response)
+	}
+	if _, response := get("/api/first"); data(response) != "first" {
+		t.Errorf("Expected the other action to keep serving, got %v", response)
+	}
+
+	// A replacement on the same route is served instead of the cached match
+	if err := apiInstance.ReplaceAction(newTestAction("test:second", "/second", api.HTTPMethodGET, "v2", nil)); err != nil {
+		t.Fatalf("Failed to replace action: %v", err)
+	}
+	if _, response := get("/api/second"); data(response) != "v2" {
+		t.Errorf("Expected the replacement's response, got %v", response)
+	}
+}
+
 func TestWebServer_CORS(t *testing.T) {
 	ws, apiInstance := setupTestServer(t)
 
@@ -134,6 +333,84 @@ func TestWebServer_CORS(t *testing.T) {
 	}
 }
 
+func TestWebServer_ActionCORS(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+
+	action := newTestAction("test:partner", "/partner", api.HTTPMethodGET, "ok", nil)
+	action.ActionWeb.CORS = &api.CORSConfig{AllowedOrigins: []string{"https://partner.example.com"}, AllowCredentials: true}
+	action.ActionWeb.CacheControl = "public, max-age=60"
+	failing := newTestAction("test:failing", "/failing", api.HTTPMethodGET, nil, errors.New("boom"))
+	failing.ActionWeb.CacheControl = "public, max-age=60"
+	for _, a := range []api.Action{action, failing} {
+		if err := apiInstance.RegisterAction(a); err != nil {
+			t.Fatalf("Failed to register action: %v", err)
+		}
+	}
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	serve := func(method, path, origin string, header http.Header) *http.Response {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Origin", origin)
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		w := httptest.NewRecorder()
+		ws.server.Handler.ServeHTTP(w, req)
+		return w.Result()
+	}
+
+	resp := serve("GET", "/api/partner", "https://partner.example.com", nil)
+	if got := resp.Header.Get("Access-Control-Allow-Origin"); got != "https://partner.example.com" {
+		t.Errorf("Expected the partner origin to be allowed, got %q", got)
+	}
+	if resp.Header.Get("Access-Control-Allow-Credentials") != "true" || resp.Header.Get("Vary") != "Origin" {
+		t.Errorf("Expected credentials and Vary: Origin, got %v", resp.Header)
+	}
+	if got := resp.Header.Get("Cache-Control"); got != "public, max-age=60" {
+		t.Errorf("Expected the action's Cache-Control, got %q", got)
+	}
+
+	resp = serve("GET", "/api/partner", "https://evil.example.com", nil)
+	if got := resp.Header.Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Expected other origins to be refused, got %q", got)
+	}
+
+	resp = serve("OPTIONS", "/api/partner", "https://evil.example.com", http.Header{"Access-Control-Request-Method": {"GET"}})
+	if got := resp.Header.Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Expected preflights from other origins to be refused, got %q", got)
+	}
+
+	resp = serve("GET", "/api/failing", "https://evil.example.com", nil)
+	if got := resp.Header.Get("Cache-Control"); got != "" {
+		t.Errorf("Expected errors not to be cacheable, got %q", got)
+	}
+	if got := resp.Header.Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Expected the server's CORS policy for other actions, got %q", got)
+	}
+}
+
+func TestWebServer_CORSReload(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	next := *apiInstance.Config
+	next.Server.Web.AllowedOrigins = "https://example.com"
+	apiInstance.ReloadConfig(&next)
+
+	req := httptest.NewRequest("OPTIONS", "/api/test", nil)
+	w := httptest.NewRecorder()
+	ws.server.Handler.ServeHTTP(w, req)
+
+	if origin := w.Header().Get("Access-Control-Allow-Origin"); origin != "https://example.com" {
+		t.Errorf("Expected reloaded origin, got %q", origin)
+	}
+}
+
 func TestWebServer_OPTIONS(t *testing.T) {
 	ws, _ := setupTestServer(t)
 	if err := ws.Initialize(); err != nil {
@@ -154,6 +431,64 @@ func TestWebServer_OPTIONS(t *testing.T) {
 	}
 }
 
+func TestWebServer_NamespaceRoutes(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+
+	if err := apiInstance.RegisterNamespace(api.Namespace{Name: "admin", RoutePrefix: "/admin"}); err != nil {
+		t.Fatalf("Failed to register namespace: %v", err)
+	}
+	if err := apiInstance.RegisterAction(newTestAction("admin:status", "/status", api.HTTPMethodGET, "ok", nil)); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	for path, want := range map[string]int{"/api/admin/status": http.StatusOK, "/api/status": http.StatusNotFound} {
+		req := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		ws.server.Handler.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("Expected %d for %s, got %d", want, path, w.Code)
+		}
+	}
+}
+
+func TestWebServer_RouteConflicts(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+	for _, action := range []api.Action{
+		newTestAction("user:get", "/user/:userId", api.HTTPMethodGET, "get", nil),
+		newTestAction("user:view", "/user/:id", api.HTTPMethodGET, "view", nil),
+	} {
+		if err := apiInstance.RegisterAction(action); err != nil {
+			t.Fatalf("Failed to register action: %v", err)
+		}
+	}
+	if err := ws.Initialize(); err == nil || !strings.Contains(err.Error(), "user:get and user:view") {
+		t.Fatalf("Expected the route conflict to fail initialization, got %v", err)
+	}
+
+	override := newTestAction("user:view", "/user/:id", api.HTTPMethodGET, "view", nil)
+	override.ActionWeb.Override = true
+	if err := apiInstance.ReplaceAction(override); err != nil {
+		t.Fatalf("Failed to replace action: %v", err)
+	}
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/api/user/1", nil)
+	w := httptest.NewRecorder()
+	ws.server.Handler.ServeHTTP(w, req)
+	var response map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if data, _ := response["data"].(map[string]interface{}); data["data"] != "view" {
+		t.Errorf("Expected the overriding action to serve the route, got %v", response)
+	}
+}
+
 func TestWebServer_RouteMatching(t *testing.T) {
 	ws, apiInstance := setupTestServer(t)
 
@@ -295,10 +630,22 @@ func TestWebServer_QueryParameters(t *testing.T) {
 	}
 }
 
-func TestWebServer_JSONBody(t *testing.T) {
+// rawAction returns a non-JSON body
+type rawAction struct {
+	api.BaseAction
+}
+
+func (a *rawAction) Run(ctx context.Context, params interface{}, conn *api.Connection) (interface{}, error) {
+	return &api.RawResponse{ContentType: "text/plain", Body: []byte("hello")}, nil
+}
+
+func TestWebServer_RawResponse(t *testing.T) {
 	ws, apiInstance := setupTestServer(t)
 
-	action := newTestAction("test:json", "/json", api.HTTPMethodPOST, nil, nil)
+	action := &rawAction{BaseAction: api.BaseAction{
+		ActionName: "test:raw",
+		ActionWeb:  &api.WebConfig{Route: "/raw", Method: api.HTTPMethodGET},
+	}}
 	if err := apiInstance.RegisterAction(action); err != nil {
 		t.Fatalf("Failed to register action: %v", err)
 	}
@@ -307,99 +654,774 @@ func TestWebServer_JSONBody(t *testing.T) {
 		t.Fatalf("Failed to initialize server: %v", err)
 	}
 
-	// Create JSON body
-	body := map[string]interface{}{
-		"name":  "John",
-		"age":   30,
-		"email": "john@example.com",
-	}
-	bodyBytes, _ := json.Marshal(body)
-
-	// Make request
-	req := httptest.NewRequest("POST", "/api/json", bytes.NewReader(bodyBytes))
-	req.Header.Set("Content-Type", "application/json")
+	req := httptest.NewRequest("GET", "/api/raw", nil)
 	w := httptest.NewRecorder()
 
 	ws.server.Handler.ServeHTTP(w, req)
 
-	// Check response
-	var response map[string]interface{}
-	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
-		t.Fatalf("Failed to decode response: %v", err)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
 	}
-
-	data := response["data"].(map[string]interface{})
-	params := data["params"].(map[string]interface{})
-
-	if params["name"] != "John" {
-		t.Errorf("Expected name='John', got '%v'", params["name"])
+	if ct := w.Header().Get("Content-Type"); ct != "text/plain" {
+		t.Errorf("Expected Content-Type text/plain, got %q", ct)
 	}
-	if params["age"].(float64) != 30 {
-		t.Errorf("Expected age=30, got '%v'", params["age"])
+	if w.Body.String() != "hello" {
+		t.Errorf("Expected raw body 'hello', got %q", w.Body.String())
 	}
 }
 
-func TestWebServer_ErrorHandling(t *testing.T) {
+// fileAction returns a file response built for each request
+type fileAction struct {
+	api.BaseAction
+	response func() *api.FileResponse
+}
+
+func (a *fileAction) Run(ctx context.Context, params interface{}, conn *api.Connection) (interface{}, error) {
+	return a.response(), nil
+}
+
+func TestWebServer_FileResponse(t *testing.T) {
 	ws, apiInstance := setupTestServer(t)
 
-	// Register action that returns an error
-	action := newTestAction("test:error", "/error", api.HTTPMethodGET, nil,
-		util.NewTypedError(util.ErrorTypeConnectionActionRun, "Something went wrong"))
-	if err := apiInstance.RegisterAction(action); err != nil {
-		t.Fatalf("Failed to register action: %v", err)
+	path := filepath.Join(t.TempDir(), "report.txt")
+	if err := os.WriteFile(path, []byte("0123456789"), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	actions := map[string]func() *api.FileResponse{
+		"/file":     func() *api.FileResponse { return &api.FileResponse{Path: path} },
+		"/download": func() *api.FileResponse { return &api.FileResponse{Path: path, Name: "r.txt", Download: true} },
+		"/missing":  func() *api.FileResponse { return &api.FileResponse{Path: path + ".missing"} },
+		"/content": func() *api.FileResponse {
+			return &api.FileResponse{Content: strings.NewReader("hello"), ContentType: "application/octet-stream"}
+		},
+	}
+	for route, response := range actions {
+		action := &fileAction{BaseAction: api.BaseAction{
+			ActionName: "test:file" + strings.TrimPrefix(route, "/"),
+			ActionWeb:  &api.WebConfig{Route: route, Method: api.HTTPMethodGET},
+		}, response: response}
+		if err := apiInstance.RegisterAction(action); err != nil {
+			t.Fatalf("Failed to register action: %v", err)
+		}
 	}
-
 	if err := ws.Initialize(); err != nil {
 		t.Fatalf("Failed to initialize server: %v", err)
 	}
 
-	// Make request
-	req := httptest.NewRequest("GET", "/api/error", nil)
-	w := httptest.NewRecorder()
+	get := func(path, rangeHeader string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api"+path, nil)
+		if rangeHeader != "" {
+			req.Header.Set("Range", rangeHeader)
+		}
+		w := httptest.NewRecorder()
+		ws.server.Handler.ServeHTTP(w, req)
+		return w
+	}
 
-	ws.server.Handler.ServeHTTP(w, req)
+	w := get("/file", "")
+	if w.Code != http.StatusOK || w.Body.String() != "0123456789" {
+		t.Errorf("Expected the whole file, got %d %q", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Expected a text/plain content type from the file name, got %q", ct)
+	}
+	if w.Header().Get("Last-Modified") == "" {
+		t.Error("Expected a Last-Modified header")
+	}
 
-	// Check response
-	resp := w.Result()
-	if resp.StatusCode != http.StatusInternalServerError {
-		t.Errorf("Expected status 500, got %d", resp.StatusCode)
+	w = get("/file", "bytes=2-5")
+	if w.Code != http.StatusPartialContent || w.Body.String() != "2345" {
+		t.Errorf("Expected bytes 2-5, got %d %q", w.Code, w.Body.String())
+	}
+	if cr := w.Header().Get("Content-Range"); cr != "bytes 2-5/10" {
+		t.Errorf("Expected Content-Range bytes 2-5/10, got %q", cr)
 	}
 
-	var response map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		t.Fatalf("Failed to decode response: %v", err)
+	w = get("/download", "")
+	if cd := w.Header().Get("Content-Disposition"); cd != "attachment; filename=r.txt" {
+		t.Errorf("Expected an attachment named r.txt, got %q", cd)
 	}
 
-	if response["success"].(bool) {
-		t.Errorf("Expected success=false")
+	w = get("/content", "")
+	if w.Body.String() != "hello" || w.Header().Get("Content-Type") != "application/octet-stream" {
+		t.Errorf("Expected the content with its type, got %q %q", w.Body.String(), w.Header().Get("Content-Type"))
 	}
 
-	errorData := response["error"].(map[string]interface{})
-	if errorData["code"] != "CONNECTION_ACTION_RUN" {
-		t.Errorf("Expected error code 'CONNECTION_ACTION_RUN', got '%v'", errorData["code"])
+	if w = get("/missing", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing file, got %d", w.Code)
 	}
 }
 
-func TestWebServer_CompileRoute(t *testing.T) {
-	tests := []struct {
-		pattern     string
-		path        string
-		shouldMatch bool
-		params      map[string]string
-	}{
-		{"/users", "/users", true, map[string]string{}},
-		{"/users/:id", "/users/123", true, map[string]string{"id": "123"}},
-		{"/users/:userId/posts/:postId", "/users/123/posts/456", true, map[string]string{"userId": "123", "postId": "456"}},
-		{"/users/:id", "/users", false, nil},
-		{"/users", "/posts", false, nil},
+// slowReader serves its content a chunk at a time, pausing before each
+type slowReader struct {
+	*bytes.Reader
+	chunk int
+	delay time.Duration
+}
+
+func (r *slowReader) Read(p []byte) (int, error) {
+	time.Sleep(r.delay)
+	if len(p) > r.chunk {
+		p = p[:r.chunk]
 	}
+	return r.Reader.Read(p)
+}
 
-	for _, tt := range tests {
-		t.Run(tt.pattern+" -> "+tt.path, func(t *testing.T) {
-			regex, paramNames, err := compileRoute(tt.pattern)
-			if err != nil {
-				t.Fatalf("Failed to compile route: %v", err)
-			}
+func TestWebServer_FileResponseOutlastsWriteTimeout(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+	ws.config.Port = 0 // A random port
+
+	content := bytes.Repeat([]byte("0123456789"), 10000)
+	action := &fileAction{BaseAction: api.BaseAction{
+		ActionName: "test:slowfile",
+		ActionWeb:  &api.WebConfig{Route: "/slow", Method: api.HTTPMethodGET},
+	}, response: func() *api.FileResponse {
+		return &api.FileResponse{Content: &slowReader{Reader: bytes.NewReader(content), chunk: 10000, delay: 20 * time.Millisecond}}
+	}}
+	if err := apiInstance.RegisterAction(action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+	ws.server.WriteTimeout = 50 * time.Millisecond // The file takes ~200ms to send
+	if err := ws.Start(); err != nil {
+		t.Fatalf("Failed to start server: %v", err)
+	}
+	defer func() { _ = ws.Stop() }()
+
+	resp, err := http.Get(ws.Addresses()[0] + "/slow")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Expected the whole file, the download was cut off after %d bytes: %v", len(body), err)
+	}
+	if !bytes.Equal(body, content) {
+		t.Errorf("Expected %d bytes, got %d", len(content), len(body))
+	}
+}
+
+type validatedInput struct {
+	Email string `json:"email" validate:"required,email"`
+	Count int    `json:"count" validate:"min=1"`
+}
+
+func TestWebServer_ValidateRequests(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+	ws.config.ValidateRequests = true
+
+	action := newTestAction("test:validated", "/validated", api.HTTPMethodPOST, nil, nil)
+	action.ActionInputs = validatedInput{}
+	if err := apiInstance.RegisterAction(action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	post := func(contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/validated", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		ws.server.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	// Valid requests reach the action
+	if w := post("application/json", `{"email": "mario@example.com", "count": 2}`); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := post("application/x-www-form-urlencoded", "email=mario%40example.com&count=2"); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 for form data, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Invalid params return 422 with schema pointers
+	w := post("application/json", `{"email": "nope", "count": 0}`)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected status 422, got %d", w.Code)
+	}
+	var response map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	errorBody := response["error"].(map[string]interface{})
+	details, ok := errorBody["details"].([]interface{})
+	if !ok || len(details) != 2 {
+		t.Fatalf("Expected 2 validation details, got %v", errorBody["details"])
+	}
+	if details[0].(map[string]interface{})["pointer"] != "/count" {
+		t.Errorf("Expected first detail to point at /count, got %v", details[0])
+	}
+
+	// Unsupported content types are rejected
+	if w := post("text/plain", "hello"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for text/plain, got %d", w.Code)
+	}
+}
+
+func TestWebServer_LocalizedErrors(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+	ws.config.ValidateRequests = true
+	apiInstance.Config.I18n = config.DefaultI18nConfig()
+	apiInstance.Messages.Add("fr", map[string]string{
+		string(util.ErrorTypeConnectionActionRun):             "L'action a échoué",
+		string(util.ErrorTypeConnectionActionParamValidation): "Paramètres invalides",
+		"validation.minimum":                                  "doit être au moins {limit}",
+	})
+
+	failing := newTestAction("test:failing", "/failing", api.HTTPMethodGET, nil,
+		util.NewTypedError(util.ErrorTypeConnectionActionRun, "action failed"))
+	validated := newTestAction("test:validated", "/validated", api.HTTPMethodPOST, nil, nil)
+	validated.ActionInputs = validatedInput{}
+	for _, action := range []api.Action{failing, validated} {
+		if err := apiInstance.RegisterAction(action); err != nil {
+			t.Fatalf("Failed to register action: %v", err)
+		}
+	}
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	send := func(method, path, body, acceptLanguage string) map[string]interface{} {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		if acceptLanguage != "" {
+			req.Header.Set("Accept-Language", acceptLanguage)
+		}
+		w := httptest.NewRecorder()
+		ws.server.Handler.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return response["error"].(map[string]interface{})
+	}
+
+	if errorBody := send("GET", "/api/failing", "", ""); errorBody["message"] != "action failed" {
+		t.Errorf("Expected the untranslated message, got %v", errorBody["message"])
+	}
+	if errorBody := send("GET", "/api/failing", "", "de, fr-CA;q=0.8"); errorBody["message"] != "L'action a échoué" {
+		t.Errorf("Expected the French message, got %v", errorBody["message"])
+	}
+	if errorBody := send("GET", "/api/failing?locale=fr", "", "de"); errorBody["message"] != "L'action a échoué" {
+		t.Errorf("Expected the locale param to pick French, got %v", errorBody["message"])
+	}
+
+	errorBody := send("POST", "/api/validated", `{"email": "mario@example.com", "count": 0}`, "fr")
+	if errorBody["message"] != "Paramètres invalides" {
+		t.Errorf("Expected the French message, got %v", errorBody["message"])
+	}
+	detail := errorBody["details"].([]interface{})[0].(map[string]interface{})
+	if detail["rule"] != "minimum" || detail["message"] != "doit être au moins 1" {
+		t.Errorf("Expected the French validation message, got %v", detail)
+	}
+	errorBody = send("POST", "/api/validated", `{"email": "mario@example.com", "count": 0, "locale": "fr"}`, "")
+	if errorBody["message"] != "Paramètres invalides" {
+		t.Errorf("Expected the locale param to pick French, got %v", errorBody["message"])
+	}
+}
+
+func TestWebServer_JSONBody(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+
+	action := newTestAction("test:json", "/json", api.HTTPMethodPOST, nil, nil)
+	if err := apiInstance.RegisterAction(action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	// Create JSON body
+	body := map[string]interface{}{
+		"name":  "John",
+		"age":   30,
+		"email": "john@example.com",
+	}
+	bodyBytes, _ := json.Marshal(body)
+
+	// Make request
+	req := httptest.NewRequest("POST", "/api/json", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	ws.server.Handler.ServeHTTP(w, req)
+
+	// Check response
+	var response map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	data := response["data"].(map[string]interface{})
+	params := data["params"].(map[string]interface{})
+
+	if params["name"] != "John" {
+		t.Errorf("Expected name='John', got '%v'", params["name"])
+	}
+	if params["age"].(float64) != 30 {
+		t.Errorf("Expected age=30, got '%v'", params["age"])
+	}
+}
+
+// echoInput is the input of echoTypedAction
+type echoInput struct {
+	ID    string `json:"id"`
+	Page  int    `json:"page"`
+	Count int64  `json:"count"`
+}
+
+// echoTypedAction is a typed action that returns its input
+type echoTypedAction struct {
+	api.BaseAction
+}
+
+func (a *echoTypedAction) Run(_ context.Context, input echoInput, _ *api.Connection) (echoInput, error) {
+	return input, nil
+}
+
+func TestWebServer_JSONBodyTypedInput(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+
+	action := api.NewTypedAction[echoInput, echoInput](&echoTypedAction{BaseAction: api.BaseAction{
+		ActionName: "test:typed",
+		ActionWeb:  &api.WebConfig{Route: "/typed", Method: api.HTTPMethodPOST},
+	}})
+	if err := apiInstance.RegisterAction(action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	post := func(path, body string) (int, echoInput) {
+		req := httptest.NewRequest("POST", "/api"+path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		ws.server.Handler.ServeHTTP(w, req)
+
+		var response struct {
+			Data echoInput `json:"data"`
+		}
+		_ = json.NewDecoder(w.Body).Decode(&response)
+		return w.Code, response.Data
+	}
+
+	// The body is decoded straight into the input; converted from the
+	// params map, the count would lose precision as a float64
+	code, input := post("/typed", `{"id": "abc", "page": 2, "count": 9007199254740993}`)
+	if code != http.StatusOK || input != (echoInput{ID: "abc", Page: 2, Count: 9007199254740993}) {
+		t.Errorf("Expected the decoded input, got %d %+v", code, input)
+	}
+
+	// With query params too, the merged params are converted
+	code, input = post("/typed?id=abc", `{"page": 2}`)
+	if code != http.StatusOK || input != (echoInput{ID: "abc", Page: 2}) {
+		t.Errorf("Expected the query and body params, got %d %+v", code, input)
+	}
+
+	// A body that doesn't fit the input fails as it did through the params
+	if code, _ := post("/typed", `{"page": "two"}`); code == http.StatusOK {
+		t.Errorf("Expected a body that doesn't match the input to fail, got %d", code)
+	}
+}
+
+func TestWebServer_JSONBodyPrecedence(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+
+	action := newTestAction("test:jsonmerge", "/merge/:id", api.HTTPMethodPOST, nil, nil)
+	if err := apiInstance.RegisterAction(action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	post := func(body string) map[string]interface{} {
+		req := httptest.NewRequest("POST", "/api/merge/path?page=1&sort=name", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		ws.server.Handler.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return response["data"].(map[string]interface{})["params"].(map[string]interface{})
+	}
+
+	// Body params override query params, which override path params
+	params := post(`{"page": 2}`)
+	if params["id"] != "path" || params["sort"] != "name" || params["page"] != float64(2) {
+		t.Errorf("Expected id=path, sort=name, page=2, got %v", params)
+	}
+
+	// A null body keeps the path and query params
+	params = post(`null`)
+	if params["id"] != "path" || params["page"] != "1" {
+		t.Errorf("Expected id=path and page=1, got %v", params)
+	}
+}
+
+func TestWebServer_MaxBodySize(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+	ws.config.MaxBodySize = 64 * config.Byte
+
+	action := newTestAction("test:limited", "/limited", api.HTTPMethodPOST, nil, nil)
+	if err := apiInstance.RegisterAction(action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"under limit", `{"name":"John"}`, http.StatusOK},
+		{"over limit", `{"name":"` + strings.Repeat("x", 100) + `"}`, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/limited", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			ws.server.Handler.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Errorf("Expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestWebServer_ErrorHandling(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+
+	// Register action that returns an error
+	action := newTestAction("test:error", "/error", api.HTTPMethodGET, nil,
+		util.NewTypedError(util.ErrorTypeConnectionActionRun, "Something went wrong"))
+	if err := apiInstance.RegisterAction(action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	// Make request
+	req := httptest.NewRequest("GET", "/api/error", nil)
+	w := httptest.NewRecorder()
+
+	ws.server.Handler.ServeHTTP(w, req)
+
+	// Check response
+	resp := w.Result()
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", resp.StatusCode)
+	}
+
+	var response map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if response["success"].(bool) {
+		t.Errorf("Expected success=false")
+	}
+
+	errorData := response["error"].(map[string]interface{})
+	if errorData["code"] != "CONNECTION_ACTION_RUN" {
+		t.Errorf("Expected error code 'CONNECTION_ACTION_RUN', got '%v'", errorData["code"])
+	}
+}
+
+func TestWebServer_CacheHeader(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+
+	action := newTestAction("test:cached", "/cached", api.HTTPMethodGET, "data", nil)
+	action.ActionCache = &api.CacheConfig{TTL: time.Minute}
+	if err := apiInstance.RegisterAction(action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	for _, want := range []string{"MISS", "HIT"} {
+		req := httptest.NewRequest("GET", "/api/cached", nil)
+		w := httptest.NewRecorder()
+		ws.server.Handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		if got := w.Header().Get("X-Cache"); got != want {
+			t.Errorf("Expected X-Cache '%s', got '%s'", want, got)
+		}
+	}
+}
+
+func TestWebServer_DeprecationHeaders(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+
+	since := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, 1, 31, 0, 0, 0, 0, time.UTC)
+	old := newTestAction("test:old", "/old", api.HTTPMethodGET, "data", nil)
+	old.ActionDeprecation = &api.DeprecationConfig{Since: since, Sunset: sunset}
+	bare := newTestAction("test:bare", "/bare", api.HTTPMethodGET, "data", nil)
+	bare.ActionDeprecation = &api.DeprecationConfig{}
+	current := newTestAction("test:current", "/current", api.HTTPMethodGET, "data", nil)
+	for _, action := range []*testAction{old, bare, current} {
+		if err := apiInstance.RegisterAction(action); err != nil {
+			t.Fatalf("Failed to register action: %v", err)
+		}
+	}
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	tests := []struct {
+		path            string
+		wantDeprecation string
+		wantSunset      string
+	}{
+		{"/api/old", "@1780272000", "Sun, 31 Jan 2027 00:00:00 GMT"},
+		{"/api/bare", "true", ""},
+		{"/api/current", "", ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		w := httptest.NewRecorder()
+		ws.server.Handler.ServeHTTP(w, req)
+
+		if got := w.Header().Get("Deprecation"); got != tt.wantDeprecation {
+			t.Errorf("%s: expected Deprecation '%s', got '%s'", tt.path, tt.wantDeprecation, got)
+		}
+		if got := w.Header().Get("Sunset"); got != tt.wantSunset {
+			t.Errorf("%s: expected Sunset '%s', got '%s'", tt.path, tt.wantSunset, got)
+		}
+	}
+}
+
+func TestWebServer_PaginationHeaders(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+
+	opts := api.PaginationOptions{SortFields: []string{"name"}}
+	list := api.NewAction("user:list").Get("/users").Handler(
+		func(_ context.Context, params interface{}, _ *api.Connection) (interface{}, error) {
+			input, err := api.ParsePagination(params, opts)
+			if err != nil {
+				return nil, err
+			}
+			return api.Paginate(input, []string{"ann", "bob"}, 45), nil
+		})
+	feed := api.NewAction("feed:list").Get("/feed").Handler(
+		func(_ context.Context, params interface{}, _ *api.Connection) (interface{}, error) {
+			input, err := api.ParsePagination(params, opts)
+			if err != nil {
+				return nil, err
+			}
+			return api.Paginate(input, []string{"post"}, -1).WithNextCursor(api.EncodeCursor("post")), nil
+		})
+	for _, action := range []api.Action{list, feed} {
+		if err := apiInstance.RegisterAction(action); err != nil {
+			t.Fatalf("Failed to register action: %v", err)
+		}
+	}
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	tests := []struct {
+		path      string
+		status    int
+		wantTotal string
+		wantLink  string
+	}{
+		{"/api/users?page=2&perPage=20&sort=name", http.StatusOK, "45",
+			`</api/users?page=1&perPage=20&sort=name>; rel="first", ` +
+				`</api/users?page=1&perPage=20&sort=name>; rel="prev", ` +
+				`</api/users?page=3&perPage=20&sort=name>; rel="next", ` +
+				`</api/users?page=3&perPage=20&sort=name>; rel="last"`},
+		{"/api/users?page=3&perPage=20", http.StatusOK, "45",
+			`</api/users?page=1&perPage=20>; rel="first", ` +
+				`</api/users?page=2&perPage=20>; rel="prev", ` +
+				`</api/users?page=3&perPage=20>; rel="last"`},
+		{"/api/feed", http.StatusOK, "", `</api/feed?cursor=cG9zdA>; rel="next"`},
+		{"/api/users?sort=password", http.StatusBadRequest, "", ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		w := httptest.NewRecorder()
+		ws.server.Handler.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d: %s", tt.path, tt.status, w.Code, w.Body.String())
+		}
+		if got := w.Header().Get("X-Total-Count"); got != tt.wantTotal {
+			t.Errorf("%s: expected X-Total-Count '%s', got '%s'", tt.path, tt.wantTotal, got)
+		}
+		if got := w.Header().Get("Link"); got != tt.wantLink {
+			t.Errorf("%s: expected Link '%s', got '%s'", tt.path, tt.wantLink, got)
+		}
+	}
+}
+
+func TestWebServer_RetryAfter(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+
+	action := newTestAction("test:saturated", "/saturated", api.HTTPMethodGET, nil,
+		util.NewTypedError(util.ErrorTypeConnectionActionSaturated, "busy", util.WithRetryAfter(1500*time.Millisecond)))
+	if err := apiInstance.RegisterAction(action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/api/saturated", nil)
+	w := httptest.NewRecorder()
+	ws.server.Handler.ServeHTTP(w, req)
+
+	resp := w.Result()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Retry-After"); got != "2" {
+		t.Errorf("Expected Retry-After '2', got '%s'", got)
+	}
+}
+
+func TestWebServer_RegisteredErrorType(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+
+	const paymentRequired util.ErrorType = "TEST_PAYMENT_REQUIRED"
+	if err := util.RegisterErrorType(paymentRequired, util.ErrorTypeInfo{HTTPStatus: http.StatusPaymentRequired, Message: "Payment required"}); err != nil {
+		t.Fatalf("Failed to register error type: %v", err)
+	}
+
+	action := newTestAction("test:pay", "/pay", api.HTTPMethodGET, nil,
+		util.NewTypedError(paymentRequired, "card declined by issuer 0042"))
+	if err := apiInstance.RegisterAction(action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/api/pay", nil)
+	w := httptest.NewRecorder()
+	ws.server.Handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusPaymentRequired {
+		t.Errorf("Expected status 402, got %d", w.Code)
+	}
+	var response map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	errorBody := response["error"].(map[string]interface{})
+	if errorBody["code"] != string(paymentRequired) || errorBody["message"] != "Payment required" {
+		t.Errorf("Expected the registered code and client-safe message, got %v", errorBody)
+	}
+}
+
+func TestWebServer_ErrorJSON(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+
+	action := newTestAction("test:invalid", "/invalid", api.HTTPMethodGET, nil,
+		util.NewTypedError(util.ErrorTypeConnectionActionParamValidation, "email is invalid",
+			util.WithKey("email"), util.WithValue("nope"), util.WithOriginalError(errors.New("missing @"))))
+	if err := apiInstance.RegisterAction(action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	get := func() map[string]interface{} {
+		req := httptest.NewRequest("GET", "/api/invalid", nil)
+		w := httptest.NewRecorder()
+		ws.server.Handler.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return response["error"].(map[string]interface{})
+	}
+
+	errorBody := get()
+	if errorBody["key"] != "email" || errorBody["value"] != "nope" {
+		t.Errorf("Expected key and value, got %v", errorBody)
+	}
+	if _, ok := errorBody["stack"]; ok {
+		t.Error("Expected no stack in standard mode")
+	}
+	if _, ok := errorBody["originalError"]; ok {
+		t.Error("Expected no original error in standard mode")
+	}
+
+	apiInstance.Config.Process.ErrorDisclosure = config.ErrorDisclosureDebug
+	errorBody = get()
+	if errorBody["stack"] == nil || errorBody["originalError"] != "missing @" {
+		t.Errorf("Expected stack and original error in debug mode, got %v", errorBody)
+	}
+
+	// Production mode hides server errors behind the request ID
+	failing := newTestAction("test:broken", "/broken", api.HTTPMethodGET, nil,
+		util.NewTypedError(util.ErrorTypeConnectionActionRun, "pq: connection refused"))
+	if err := apiInstance.RegisterAction(failing); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	apiInstance.Config.Process.ErrorDisclosure = config.ErrorDisclosureProduction
+
+	req := httptest.NewRequest("GET", "/api/broken", nil)
+	w := httptest.NewRecorder()
+	ws.server.Handler.ServeHTTP(w, req)
+	var response map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	errorBody = response["error"].(map[string]interface{})
+	requestID := w.Header().Get("X-Request-ID")
+	if errorBody["message"] != "An internal error occurred (request ID: "+requestID+")" || errorBody["requestId"] != requestID {
+		t.Errorf("Expected a generic message with the request ID %q, got %v", requestID, errorBody)
+	}
+}
+
+func TestWebServer_CompileRoute(t *testing.T) {
+	tests := []struct {
+		pattern     string
+		path        string
+		shouldMatch bool
+		params      map[string]string
+	}{
+		{"/users", "/users", true, map[string]string{}},
+		{"/users/:id", "/users/123", true, map[string]string{"id": "123"}},
+		{"/users/:userId/posts/:postId", "/users/123/posts/456", true, map[string]string{"userId": "123", "postId": "456"}},
+		{"/users/:id", "/users", false, nil},
+		{"/users", "/posts", false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" -> "+tt.path, func(t *testing.T) {
+			regex, paramNames, err := compileRoute(tt.pattern)
+			if err != nil {
+				t.Fatalf("Failed to compile route: %v", err)
+			}
 
 			matches := regex.FindStringSubmatch(tt.path)
 			didMatch := matches != nil
@@ -425,7 +1447,7 @@ func TestWebServer_CompileRoute(t *testing.T) {
 }
 
 func TestWebServer_WebSocket(t *testing.T) {
-	ws, apiInstance := setupTestServer(t)
+	_, apiInstance := setupTestServer(t)
 
 	// Register test action
 	action := newTestAction("test:ws", "/test", api.HTTPMethodGET, "websocket response", nil)
@@ -433,22 +1455,11 @@ func TestWebServer_WebSocket(t *testing.T) {
 		t.Fatalf("Failed to register action: %v", err)
 	}
 
-	if err := ws.Initialize(); err != nil {
-		t.Fatalf("Failed to initialize server: %v", err)
-	}
-
-	// Start server
-	if err := ws.Start(); err != nil {
-		t.Fatalf("Failed to start server: %v", err)
-	}
-	defer func() { _ = ws.Stop() }()
-
-	// Give server time to start
-	time.Sleep(100 * time.Millisecond)
+	ws := NewTestWebServer(t, apiInstance)
 
 	// Create WebSocket connection
 	dialer := websocket.Dialer{}
-	conn, _, err := dialer.Dial("ws://localhost:9999/ws", nil)
+	conn, _, err := dialer.Dial(ws.WebSocketURL, nil)
 	if err != nil {
 		t.Fatalf("Failed to connect to WebSocket: %v", err)
 	}
@@ -481,25 +1492,191 @@ func TestWebServer_WebSocket(t *testing.T) {
 	}
 }
 
-func TestWebServer_WebSocketSubscription(t *testing.T) {
-	ws, _ := setupTestServer(t)
+func TestWebServer_WebSocketConcurrentActions(t *testing.T) {
+	tests := []struct {
+		name     string
+		inFlight int
+		ordered  bool
+		want     []string // Request IDs, in the order their responses arrive
+	}{
+		{"concurrent", 10, false, []string{"fast", "slow"}},
+		{"ordered", 10, true, []string{"slow", "fast"}},
+		{"one at a time", 1, false, []string{"slow", "fast"}},
+	}
 
-	if err := ws.Initialize(); err != nil {
-		t.Fatalf("Failed to initialize server: %v", err)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, apiInstance := setupTestServer(t)
+			apiInstance.Config.Server.Web.MaxActionsInFlight = tt.inFlight
+			apiInstance.Config.Server.Web.OrderedResponses = tt.ordered
+			sleep := api.NewAction("test:sleep").Handler(
+				func(_ context.Context, params interface{}, _ *api.Connection) (interface{}, error) {
+					ms, _ := params.(map[string]interface{})["ms"].(float64)
+					time.Sleep(time.Duration(ms) * time.Millisecond)
+					return nil, nil
+				})
+			if err := apiInstance.RegisterAction(sleep); err != nil {
+				t.Fatalf("Failed to register action: %v", err)
+			}
+			ws := NewTestWebServer(t, apiInstance)
+
+			conn, _, err := (&websocket.Dialer{}).Dial(ws.WebSocketURL, nil)
+			if err != nil {
+				t.Fatalf("Failed to connect to WebSocket: %v", err)
+			}
+			defer func() { _ = conn.Close() }()
+
+			for _, request := range []struct {
+				id string
+				ms int
+			}{{"slow", 200}, {"fast", 0}} {
+				if err := conn.WriteJSON(map[string]interface{}{
+					"type": "action", "action": "test:sleep", "requestId": request.id,
+					"params": map[string]interface{}{"ms": request.ms},
+				}); err != nil {
+					t.Fatalf("Failed to send WebSocket message: %v", err)
+				}
+			}
+
+			var got []string
+			for range tt.want {
+				var response map[string]interface{}
+				if err := conn.ReadJSON(&response); err != nil {
+					t.Fatalf("Failed to read WebSocket response: %v", err)
+				}
+				requestID, _ := response["requestId"].(string)
+				got = append(got, requestID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected responses %v, got %v", tt.want, got)
+			}
+		})
 	}
+}
 
-	// Start server
-	if err := ws.Start(); err != nil {
-		t.Fatalf("Failed to start server: %v", err)
+func TestWebServer_ActionContext(t *testing.T) {
+	_, apiInstance := setupTestServer(t)
+
+	// What the action saw, read while it runs: HTTP connections are reused
+	// once the response is sent
+	type actionContext struct {
+		api       *api.API
+		config    bool
+		connType  string
+		requestID string
+	}
+	contexts := make(chan actionContext, 2)
+	action := api.NewAction("test:context").Get("/context").Handler(
+		func(ctx context.Context, _ interface{}, _ *api.Connection) (interface{}, error) {
+			seen := actionContext{
+				api:       api.APIFromContext(ctx),
+				config:    api.ConfigFromContext(ctx) != nil,
+				requestID: util.RequestIDFromContext(ctx),
+			}
+			if conn := api.ConnectionFromContext(ctx); conn != nil {
+				seen.connType = conn.Type
+			}
+			contexts <- seen
+			return nil, nil
+		})
+	if err := apiInstance.RegisterAction(action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
 	}
-	defer func() { _ = ws.Stop() }()
+	ws := NewTestWebServer(t, apiInstance)
+
+	resp, err := http.Get(ws.URL + "/api/context")
+	if err != nil {
+		t.Fatalf("HTTP request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	conn, _, err := (&websocket.Dialer{}).Dial(ws.WebSocketURL, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect to WebSocket: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	if err := conn.WriteJSON(map[string]interface{}{"type": "action", "action": "test:context"}); err != nil {
+		t.Fatalf("Failed to send WebSocket message: %v", err)
+	}
+	var response map[string]interface{}
+	if err := conn.ReadJSON(&response); err != nil {
+		t.Fatalf("Failed to read WebSocket response: %v", err)
+	}
+
+	for _, transport := range []string{"http", "websocket"} {
+		seen := <-contexts
+		if seen.api != apiInstance || !seen.config {
+			t.Errorf("Expected the API and config in the %s action's context", transport)
+		}
+		if seen.connType != transport {
+			t.Errorf("Expected the %s connection in the action's context, got %q", transport, seen.connType)
+		}
+		if seen.requestID == "" {
+			t.Errorf("Expected a request ID in the %s action's context", transport)
+		}
+	}
+}
+
+func TestWebServer_DryRun(t *testing.T) {
+	_, apiInstance := setupTestServer(t)
+
+	ran := false
+	action := api.NewAction("test:dryrun").Post("/dryrun").Handler(
+		func(context.Context, interface{}, *api.Connection) (interface{}, error) {
+			ran = true
+			return nil, nil
+		})
+	if err := apiInstance.RegisterAction(action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	ws := NewTestWebServer(t, apiInstance)
+
+	req, _ := http.NewRequest(http.MethodPost, ws.URL+"/api/dryrun?name=x", nil)
+	req.Header.Set(api.DryRunHeader, "true")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("HTTP request failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var body map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if data, _ := body["data"].(map[string]interface{}); resp.StatusCode != http.StatusOK || data["dryRun"] != true || data["route"] != "/dryrun" {
+		t.Errorf("Expected a dry run response, got %d %v", resp.StatusCode, body)
+	}
+
+	header := http.Header{}
+	header.Set(api.DryRunHeader, "1")
+	conn, _, err := (&websocket.Dialer{}).Dial(ws.WebSocketURL, header)
+	if err != nil {
+		t.Fatalf("Failed to connect to WebSocket: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	if err := conn.WriteJSON(map[string]interface{}{"type": "action", "action": "test:dryrun"}); err != nil {
+		t.Fatalf("Failed to send WebSocket message: %v", err)
+	}
+	var message map[string]interface{}
+	if err := conn.ReadJSON(&message); err != nil {
+		t.Fatalf("Failed to read WebSocket response: %v", err)
+	}
+	if data, _ := message["data"].(map[string]interface{}); data["dryRun"] != true {
+		t.Errorf("Expected a dry run response over WebSocket, got %v", message)
+	}
+
+	if ran {
+		t.Error("Expected the action not to run in a dry run")
+	}
+}
+
+func TestWebServer_WebSocketSubscription(t *testing.T) {
+	_, apiInstance := setupTestServer(t)
 
-	// Give server time to start
-	time.Sleep(100 * time.Millisecond)
+	ws := NewTestWebServer(t, apiInstance)
 
 	// Create WebSocket connection
 	dialer := websocket.Dialer{}
-	conn, _, err := dialer.Dial("ws://localhost:9999/ws", nil)
+	conn, _, err := dialer.Dial(ws.WebSocketURL, nil)
 	if err != nil {
 		t.Fatalf("Failed to connect to WebSocket: %v", err)
 	}
@@ -570,3 +1747,310 @@ func TestWebServer_WebSocketSubscription(t *testing.T) {
 		t.Errorf("Expected type='unsubscribed', got '%v'", unsubResponse["type"])
 	}
 }
+
+func TestWebServer_WebSocketStickyParams(t *testing.T) {
+	_, apiInstance := setupTestServer(t)
+
+	action := newTestAction("test:sticky", "/sticky", api.HTTPMethodGET, nil, nil)
+	if err := apiInstance.RegisterAction(action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	ws := NewTestWebServer(t, apiInstance)
+
+	dialer := websocket.Dialer{}
+	conn, _, err := dialer.Dial(ws.WebSocketURL, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect to WebSocket: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	send := func(request map[string]interface{}) map[string]interface{} {
+		t.Helper()
+		if err := conn.WriteJSON(request); err != nil {
+			t.Fatalf("Failed to send WebSocket message: %v", err)
+		}
+		var response map[string]interface{}
+		if err := conn.ReadJSON(&response); err != nil {
+			t.Fatalf("Failed to read WebSocket response: %v", err)
+		}
+		return response
+	}
+	actionParams := func(params map[string]interface{}) map[string]interface{} {
+		t.Helper()
+		response := send(map[string]interface{}{"type": "action", "action": "test:sticky", "params": params})
+		return response["data"].(map[string]interface{})["params"].(map[string]interface{})
+	}
+
+	send(map[string]interface{}{"type": "paramAdd", "key": "locale", "value": "fr"})
+	response := send(map[string]interface{}{"type": "paramAdd", "key": "token", "value": "secret"})
+	if response["type"] != "params" || len(response["params"].(map[string]interface{})) != 2 {
+		t.Errorf("Expected both params to be stored, got %v", response)
+	}
+
+	// Sticky params are merged in; the call's own params win
+	params := actionParams(map[string]interface{}{"locale": "en", "foo": "bar"})
+	if params["token"] != "secret" || params["locale"] != "en" || params["foo"] != "bar" {
+		t.Errorf("Expected merged params, got %v", params)
+	}
+
+	send(map[string]interface{}{"type": "paramDelete", "key": "token"})
+	response = send(map[string]interface{}{"type": "paramsView"})
+	if stored := response["params"].(map[string]interface{}); len(stored) != 1 || stored["locale"] != "fr" {
+		t.Errorf("Expected only locale to remain, got %v", stored)
+	}
+
+	send(map[string]interface{}{"type": "paramsDelete"})
+	if params := actionParams(nil); len(params) != 0 {
+		t.Errorf("Expected no params after paramsDelete, got %v", params)
+	}
+
+	if response := send(map[string]interface{}{"type": "paramAdd"}); response["success"] != false {
+		t.Errorf("Expected an error without a key, got %v", response)
+	}
+}
+
+func TestWebServer_WebSocketLocale(t *testing.T) {
+	_, apiInstance := setupTestServer(t)
+	apiInstance.Messages.Add("fr", map[string]string{"UNKNOWN_MESSAGE_TYPE": "Type de message inconnu"})
+	apiInstance.Messages.Add("es", map[string]string{"UNKNOWN_MESSAGE_TYPE": "Tipo de mensaje desconocido"})
+
+	ws := NewTestWebServer(t, apiInstance)
+
+	dialer := websocket.Dialer{}
+	conn, _, err := dialer.Dial(ws.WebSocketURL, http.Header{"Accept-Language": []string{"fr"}})
+	if err != nil {
+		t.Fatalf("Failed to connect to WebSocket: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	send := func(request map[string]interface{}) map[string]interface{} {
+		t.Helper()
+		if err := conn.WriteJSON(request); err != nil {
+			t.Fatalf("Failed to send WebSocket message: %v", err)
+		}
+		var response map[string]interface{}
+		if err := conn.ReadJSON(&response); err != nil {
+			t.Fatalf("Failed to read WebSocket response: %v", err)
+		}
+		return response
+	}
+	message := func(response map[string]interface{}) interface{} {
+		return response["error"].(map[string]interface{})["message"]
+	}
+
+	// The upgrade request's Accept-Language sets the initial locale
+	if got := message(send(map[string]interface{}{"type": "bogus"})); got != "Type de message inconnu" {
+		t.Errorf("Expected the French message, got %v", got)
+	}
+
+	response := send(map[string]interface{}{"type": "locale", "locale": "es-MX"})
+	if response["type"] != "locale" {
+		t.Fatalf("Expected a locale response, got %v", response)
+	}
+	if got := message(send(map[string]interface{}{"type": "bogus"})); got != "Tipo de mensaje desconocido" {
+		t.Errorf("Expected the Spanish message, got %v", got)
+	}
+
+	if got := send(map[string]interface{}{"type": "locale"}); got["success"] != false {
+		t.Errorf("Expected an error without a locale, got %v", got)
+	}
+}
+
+func TestWebServer_WebSocketProgress(t *testing.T) {
+	_, apiInstance := setupTestServer(t)
+	action := api.NewAction("test:import").Get("/import").Handler(func(ctx context.Context, _ interface{}, conn *api.Connection) (interface{}, error) {
+		conn.Progress(25, "reading")
+		api.ReportProgress(ctx, 75, "writing")
+		return "imported", nil
+	})
+	if err := apiInstance.RegisterAction(action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	ws := NewTestWebServer(t, apiInstance)
+
+	dialer := websocket.Dialer{}
+	conn, _, err := dialer.Dial(ws.WebSocketURL, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect to WebSocket: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if err := conn.WriteJSON(map[string]interface{}{"type": "action", "action": "test:import", "messageId": "m1"}); err != nil {
+		t.Fatalf("Failed to send WebSocket message: %v", err)
+	}
+
+	var messages []map[string]interface{}
+	for len(messages) < 3 {
+		var message map[string]interface{}
+		if err := conn.ReadJSON(&message); err != nil {
+			t.Fatalf("Failed to read WebSocket message: %v", err)
+		}
+		messages = append(messages, message)
+	}
+
+	if messages[0]["type"] != "progress" || messages[0]["percent"] != 25.0 || messages[0]["message"] != "reading" {
+		t.Errorf("Expected a progress message, got %v", messages[0])
+	}
+	if _, ok := messages[0]["requestId"]; ok {
+		t.Errorf("Expected no request ID from conn.Progress, got %v", messages[0])
+	}
+	if messages[1]["type"] != "progress" || messages[1]["percent"] != 75.0 || messages[1]["requestId"] == nil {
+		t.Errorf("Expected a progress message with the request ID, got %v", messages[1])
+	}
+	if messages[2]["type"] != "response" || messages[2]["success"] != true {
+		t.Errorf("Expected the action's response, got %v", messages[2])
+	}
+}
+
+func TestWebServer_WebSocketConnectionEvents(t *testing.T) {
+	_, apiInstance := setupTestServer(t)
+
+	opened := make(chan *api.Connection, 1)
+	closed := make(chan *api.Connection, 1)
+	apiInstance.On(api.EventConnectionOpen, func(_ context.Context, event api.Event) {
+		opened <- event.Connection
+	})
+	apiInstance.On(api.EventConnectionClose, func(_ context.Context, event api.Event) {
+		closed <- event.Connection
+	})
+
+	ws := NewTestWebServer(t, apiInstance)
+
+	dialer := websocket.Dialer{}
+	conn, _, err := dialer.Dial(ws.WebSocketURL, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect to WebSocket: %v", err)
+	}
+
+	var openedConn *api.Connection
+	select {
+	case openedConn = <-opened:
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for connection:open event")
+	}
+	if info := openedConn.ClientInfo(); info.Protocol != "websocket" || info.UserAgent == "" || info.Fingerprint == "" {
+		t.Errorf("Expected the WebSocket client info, got %+v", info)
+	}
+
+	_ = conn.Close()
+
+	select {
+	case closedConn := <-closed:
+		if closedConn.ID != openedConn.ID {
+			t.Errorf("Expected close event for %s, got %s", openedConn.ID, closedConn.ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for connection:close event")
+	}
+}
+
+func TestWebServer_RequestID(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+
+	action := newTestAction("test:error", "/error", api.HTTPMethodGET, nil,
+		util.NewTypedError(util.ErrorTypeConnectionActionRun, "Something went wrong"))
+	if err := apiInstance.RegisterAction(action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		incoming string
+		wantSame bool
+	}{
+		{"propagates caller ID", "abc-123", true},
+		{"generates missing ID", "", false},
+		{"replaces invalid ID", "bad id\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/error", nil)
+			if tt.incoming != "" {
+				req.Header.Set("X-Request-ID", tt.incoming)
+			}
+			w := httptest.NewRecorder()
+			ws.server.Handler.ServeHTTP(w, req)
+
+			headerID := w.Header().Get("X-Request-ID")
+			if headerID == "" {
+				t.Fatal("Expected X-Request-ID response header")
+			}
+			if tt.wantSame && headerID != tt.incoming {
+				t.Errorf("Expected request ID %q, got %q", tt.incoming, headerID)
+			}
+			if !tt.wantSame && headerID == tt.incoming {
+				t.Errorf("Expected a generated request ID, got %q", headerID)
+			}
+
+			var response map[string]interface{}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			errorData := response["error"].(map[string]interface{})
+			if errorData["requestId"] != headerID {
+				t.Errorf("Expected error payload requestId %q, got %v", headerID, errorData["requestId"])
+			}
+		})
+	}
+}
+
+func TestWebServer_SendSuccessEncodingError(t *testing.T) {
+	ws, _ := setupTestServer(t)
+
+	w := httptest.NewRecorder()
+	ws.sendSuccess(w, map[string]interface{}{"bad": make(chan int)})
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", w.Code)
+	}
+	if got := w.Header().Get("Content-Length"); got != strconv.Itoa(w.Body.Len()) {
+		t.Errorf("Expected Content-Length %d, got %s", w.Body.Len(), got)
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response["success"] != false {
+		t.Errorf("Expected success false, got %v", response["success"])
+	}
+
+	// The pooled encoder is reusable after a failure
+	w = httptest.NewRecorder()
+	ws.sendSuccess(w, "ok")
+	if w.Body.String() != "{\"data\":\"ok\",\"success\":true}\n" {
+		t.Errorf("Unexpected body %q", w.Body.String())
+	}
+}
+
+// servedFiles is an api.Files that serves its files over HTTP, as the local
+// storage backend does
+type servedFiles struct {
+	api.Files
+}
+
+func (servedFiles) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte("file " + r.URL.Path))
+}
+
+func TestWebServer_ServesStoredFiles(t *testing.T) {
+	ws, apiInstance := setupTestServer(t)
+	apiInstance.Config.Storage.Route = "/files"
+	apiInstance.Files = servedFiles{}
+
+	if err := ws.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	ws.server.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/files/avatars/42.png?signature=x", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "file /avatars/42.png" {
+		t.Errorf("Expected the file handler, got %d %q", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,90 @@
+// Package statsd emits action and connection metrics to a StatsD or Datadog agent
+package statsd
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+)
+
+// invalidMetricChars matches characters that aren't safe in a metric name
+var invalidMetricChars = regexp.MustCompile(`[^a-zA-Z0-9_.\-]`)
+
+// Client sends metrics to StatsD over UDP. Sends are fire-and-forget:
+// a missing or slow agent never blocks or fails the caller.
+type Client struct {
+	conn   net.Conn
+	prefix string
+	tags   string // Pre-rendered Datadog tag suffix ("|#a:b,c:d"), or ""
+}
+
+// NewClient creates a client for the configured StatsD agent
+func NewClient(cfg config.StatsDConfig) (*Client, error) {
+	address := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to statsd at %s: %w", address, err)
+	}
+
+	return &Client{
+		conn:   conn,
+		prefix: cfg.Prefix,
+		tags:   renderTags(cfg.Tags),
+	}, nil
+}
+
+// Count adds value to a counter
+func (c *Client) Count(name string, value int64) {
+	c.send(name, strconv.FormatInt(value, 10), "c")
+}
+
+// Increment adds one to a counter
+func (c *Client) Increment(name string) {
+	c.Count(name, 1)
+}
+
+// Timing records a duration in milliseconds
+func (c *Client) Timing(name string, d time.Duration) {
+	c.send(name, strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', -1, 64), "ms")
+}
+
+// Gauge sets a gauge to value
+func (c *Client) Gauge(name string, value float64) {
+	c.send(name, strconv.FormatFloat(value, 'f', -1, 64), "g")
+}
+
+// Close closes the UDP connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// send writes a single metric packet
+func (c *Client) send(name, value, metricType string) {
+	packet := c.prefix + name + ":" + value + "|" + metricType + c.tags
+	// UDP writes only fail locally (e.g., no route); metrics are best-effort
+	_, _ = c.conn.Write([]byte(packet))
+}
+
+// MetricName makes s safe to use as part of a metric name (e.g., "user:create" -> "user_create")
+func MetricName(s string) string {
+	return invalidMetricChars.ReplaceAllString(s, "_")
+}
+
+// renderTags converts "a:b, c:d" into the Datadog suffix "|#a:b,c:d"
+func renderTags(tags string) string {
+	parts := make([]string, 0)
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			parts = append(parts, tag)
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "|#" + strings.Join(parts, ",")
+}
@@ -0,0 +1,123 @@
+package statsd
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+)
+
+// Initializer emits action, connection, and task metrics to StatsD when statsd.enabled is set.
+//
+// Metrics (before the configured prefix):
+//
+//	actions.<action>.duration  timer
+//	actions.<action>.success   counter
+//	actions.<action>.error     counter
+//	connections.opened         counter
+//	connections.closed         counter
+//	connections.active         gauge
+//	tasks.<task>.enqueued      counter
+//	tasks.<task>.duration      timer
+//	tasks.<task>.success       counter
+//	tasks.<task>.error         counter
+type Initializer struct {
+	client      *Client
+	connections int64
+	subscribed  bool // Event handlers are registered once, across restarts
+}
+
+// NewInitializer creates the StatsD initializer
+func NewInitializer() *Initializer {
+	return &Initializer{}
+}
+
+// Name returns the initializer name
+func (i *Initializer) Name() string {
+	return "statsd"
+}
+
+// Priority returns the initialization priority
+func (i *Initializer) Priority() int {
+	return 100
+}
+
+// Initialize connects to StatsD and subscribes to framework events
+func (i *Initializer) Initialize(a *api.API) error {
+	if !a.Config.StatsD.Enabled {
+		return nil
+	}
+
+	client, err := NewClient(a.Config.StatsD)
+	if err != nil {
+		return err
+	}
+	i.client = client
+
+	// Subscribe once, so a restarted API doesn't count events twice
+	if !i.subscribed {
+		a.On(api.EventActionComplete, i.recordAction)
+		a.On(api.EventActionError, i.recordAction)
+		a.On(api.EventConnectionOpen, i.recordConnectionOpen)
+		a.On(api.EventConnectionClose, i.recordConnectionClose)
+		a.On(api.EventTaskEnqueued, i.recordTaskEnqueued)
+		a.On(api.EventTaskComplete, i.recordTask)
+		a.On(api.EventTaskError, i.recordTask)
+		i.subscribed = true
+	}
+
+	a.Logger.Infof("StatsD metrics enabled: %s:%d", a.Config.StatsD.Host, a.Config.StatsD.Port)
+	return nil
+}
+
+// Start does nothing; metrics are emitted as events happen
+func (i *Initializer) Start(_ *api.API) error {
+	return nil
+}
+
+// Stop closes the StatsD connection
+func (i *Initializer) Stop(_ *api.API) error {
+	if i.client == nil {
+		return nil
+	}
+	return i.client.Close()
+}
+
+// recordAction emits the duration and outcome of an action
+func (i *Initializer) recordAction(_ context.Context, event api.Event) {
+	name := "actions." + MetricName(event.Action)
+	i.client.Timing(name+".duration", event.Duration)
+	if event.Error != nil {
+		i.client.Increment(name + ".error")
+	} else {
+		i.client.Increment(name + ".success")
+	}
+}
+
+// recordConnectionOpen counts an opened connection
+func (i *Initializer) recordConnectionOpen(_ context.Context, _ api.Event) {
+	i.client.Increment("connections.opened")
+	i.client.Gauge("connections.active", float64(atomic.AddInt64(&i.connections, 1)))
+}
+
+// recordConnectionClose counts a closed connection
+func (i *Initializer) recordConnectionClose(_ context.Context, _ api.Event) {
+	i.client.Increment("connections.closed")
+	i.client.Gauge("connections.active", float64(atomic.AddInt64(&i.connections, -1)))
+}
+
+// recordTaskEnqueued counts a queued task
+func (i *Initializer) recordTaskEnqueued(_ context.Context, event api.Event) {
+	i.client.Increment("tasks." + MetricName(event.Action) + ".enqueued")
+}
+
+// recordTask emits the duration and outcome of a task run by a worker
+func (i *Initializer) recordTask(_ context.Context, event api.Event) {
+	name := "tasks." + MetricName(event.Action)
+	i.client.Timing(name+".duration", event.Duration)
+	if event.Error != nil {
+		i.client.Increment(name + ".error")
+	} else {
+		i.client.Increment(name + ".success")
+	}
+}
@@ -0,0 +1,180 @@
+package statsd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// listen starts a UDP listener and returns its config and a function that
+// reads the next packet
+func listen(t *testing.T) (config.StatsDConfig, func() string) {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	cfg := config.DefaultStatsDConfig()
+	cfg.Enabled = true
+	cfg.Port = conn.LocalAddr().(*net.UDPAddr).Port
+
+	read := func() string {
+		buf := make([]byte, 1024)
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("Failed to read packet: %v", err)
+		}
+		return string(buf[:n])
+	}
+
+	return cfg, read
+}
+
+func TestClient_Packets(t *testing.T) {
+	cfg, read := listen(t)
+	cfg.Tags = "env:test, service:api"
+
+	client, err := NewClient(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	tests := []struct {
+		name string
+		send func()
+		want string
+	}{
+		{"increment", func() { client.Increment("hits") }, "actionhero.hits:1|c|#env:test,service:api"},
+		{"count", func() { client.Count("hits", 5) }, "actionhero.hits:5|c|#env:test,service:api"},
+		{"timing", func() { client.Timing("latency", 1500*time.Microsecond) }, "actionhero.latency:1.5|ms|#env:test,service:api"},
+		{"gauge", func() { client.Gauge("active", 3) }, "actionhero.active:3|g|#env:test,service:api"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.send()
+			if got := read(); got != tt.want {
+				t.Errorf("Expected packet %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestClient_NoTags(t *testing.T) {
+	cfg, read := listen(t)
+	cfg.Prefix = ""
+
+	client, err := NewClient(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	client.Increment("hits")
+	if got := read(); got != "hits:1|c" {
+		t.Errorf("Expected packet without tags, got %q", got)
+	}
+}
+
+func TestMetricName(t *testing.T) {
+	if got := MetricName("user:create"); got != "user_create" {
+		t.Errorf("Expected 'user_create', got %q", got)
+	}
+	if got := MetricName("status"); got != "status" {
+		t.Errorf("Expected 'status', got %q", got)
+	}
+}
+
+func TestInitializer_ActionAndConnectionMetrics(t *testing.T) {
+	cfg, read := listen(t)
+
+	apiInstance := api.New(&config.Config{StatsD: cfg}, util.NewLogger(config.LoggerConfig{Level: "fatal"}))
+	initializer := NewInitializer()
+	if err := initializer.Initialize(apiInstance); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	defer func() { _ = initializer.Stop(apiInstance) }()
+
+	ctx := context.Background()
+
+	apiInstance.Emit(ctx, api.Event{Name: api.EventActionComplete, Action: "user:create", Duration: 2 * time.Millisecond})
+	if got := read(); got != "actionhero.actions.user_create.duration:2|ms" {
+		t.Errorf("Unexpected duration packet %q", got)
+	}
+	if got := read(); got != "actionhero.actions.user_create.success:1|c" {
+		t.Errorf("Unexpected success packet %q", got)
+	}
+
+	apiInstance.Emit(ctx, api.Event{Name: api.EventActionError, Action: "user:create", Error: errors.New("failed")})
+	read()
+	if got := read(); got != "actionhero.actions.user_create.error:1|c" {
+		t.Errorf("Unexpected error packet %q", got)
+	}
+
+	apiInstance.Emit(ctx, api.Event{Name: api.EventConnectionOpen})
+	if got := read(); got != "actionhero.connections.opened:1|c" {
+		t.Errorf("Unexpected opened packet %q", got)
+	}
+	if got := read(); !strings.HasPrefix(got, "actionhero.connections.active:1|g") {
+		t.Errorf("Unexpected active packet %q", got)
+	}
+}
+
+func TestInitializer_TaskMetrics(t *testing.T) {
+	cfg, read := listen(t)
+
+	apiInstance := api.New(&config.Config{StatsD: cfg}, util.NewLogger(config.LoggerConfig{Level: "fatal"}))
+	initializer := NewInitializer()
+	if err := initializer.Initialize(apiInstance); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	defer func() { _ = initializer.Stop(apiInstance) }()
+
+	ctx := context.Background()
+
+	apiInstance.Emit(ctx, api.Event{Name: api.EventTaskEnqueued, Action: "email:send"})
+	if got := read(); got != "actionhero.tasks.email_send.enqueued:1|c" {
+		t.Errorf("Unexpected enqueued packet %q", got)
+	}
+
+	apiInstance.Emit(ctx, api.Event{Name: api.EventTaskComplete, Action: "email:send", Duration: 3 * time.Millisecond})
+	if got := read(); got != "actionhero.tasks.email_send.duration:3|ms" {
+		t.Errorf("Unexpected duration packet %q", got)
+	}
+	if got := read(); got != "actionhero.tasks.email_send.success:1|c" {
+		t.Errorf("Unexpected success packet %q", got)
+	}
+
+	apiInstance.Emit(ctx, api.Event{Name: api.EventTaskError, Action: "email:send", Error: errors.New("failed")})
+	read()
+	if got := read(); got != "actionhero.tasks.email_send.error:1|c" {
+		t.Errorf("Unexpected error packet %q", got)
+	}
+}
+
+func TestInitializer_Disabled(t *testing.T) {
+	apiInstance := api.New(&config.Config{}, util.NewLogger(config.LoggerConfig{Level: "fatal"}))
+	initializer := NewInitializer()
+
+	if err := initializer.Initialize(apiInstance); err != nil {
+		t.Fatalf("Expected no error when disabled, got %v", err)
+	}
+	if initializer.client != nil {
+		t.Error("Expected no client when statsd is disabled")
+	}
+	if err := initializer.Stop(apiInstance); err != nil {
+		t.Errorf("Expected Stop to succeed when disabled, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"github.com/evantahler/go-actionhero/internal/api"
+)
+
+// Initializer sets the API's Files when storage.enabled is set
+type Initializer struct{}
+
+// NewInitializer creates the storage initializer
+func NewInitializer() *Initializer {
+	return &Initializer{}
+}
+
+// Name returns the initializer name
+func (i *Initializer) Name() string {
+	return "storage"
+}
+
+// Priority returns the initialization priority
+func (i *Initializer) Priority() int {
+	return 100
+}
+
+// Initialize sets up the backend configured by storage.backend
+func (i *Initializer) Initialize(a *api.API) error {
+	cfg := a.Config.Storage
+	if !cfg.Enabled {
+		return nil
+	}
+
+	files, err := newFiles(cfg)
+	if err != nil {
+		return err
+	}
+	a.Files = files
+
+	if cfg.Backend == "s3" {
+		a.Logger.Infof("File storage enabled: s3 bucket %s", cfg.S3Bucket)
+	} else {
+		if cfg.URLSecret == "" {
+			a.Logger.Warn("storage.urlsecret is not set: signed URLs of local files are only valid until restart, on this process")
+		}
+		a.Logger.Infof("File storage enabled: %s, served at %s", cfg.Directory, cfg.Route)
+	}
+	return nil
+}
+
+// Start does nothing; the backend is ready once initialized
+func (i *Initializer) Start(_ *api.API) error {
+	return nil
+}
+
+// Stop does nothing; the backend holds no connections
+func (i *Initializer) Stop(_ *api.API) error {
+	return nil
+}
@@ -0,0 +1,199 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/evantahler/go-actionhero/internal/config"
+)
+
+// localFiles stores files under a directory. Its signed URLs point at the
+// web server's storage.route, which it serves (see ServeHTTP). The content
+// type given to Put is not kept: files are served with the type of their
+// extension.
+type localFiles struct {
+	directory string
+	baseURL   string // storage.publicurl + storage.route
+	secret    []byte
+	now       func() time.Time
+}
+
+// newLocalFiles creates a local backend, signing URLs with a random secret
+// when storage.urlsecret is not set
+func newLocalFiles(cfg config.StorageConfig) (*localFiles, error) {
+	f := &localFiles{
+		directory: cfg.Directory,
+		baseURL:   strings.TrimSuffix(cfg.PublicURL, "/") + strings.TrimSuffix(cfg.Route, "/"),
+		secret:    []byte(cfg.URLSecret),
+		now:       time.Now,
+	}
+	if len(f.secret) == 0 {
+		f.secret = make([]byte, 32)
+		if _, err := rand.Read(f.secret); err != nil {
+			return nil, fmt.Errorf("failed to generate url secret: %w", err)
+		}
+	}
+	if err := os.MkdirAll(f.directory, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	return f, nil
+}
+
+// path returns the file path of key
+func (f *localFiles) path(key string) (string, error) {
+	if err := checkKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(f.directory, filepath.FromSlash(key)), nil
+}
+
+// Put writes body to a temporary file next to the key's, then renames it,
+// so readers never see a partial file
+func (f *localFiles) Put(ctx context.Context, key string, body io.Reader, _ string) error {
+	path, err := f.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", key, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to store %s: %w", key, err)
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+
+	if _, err := io.Copy(tmp, contextReader{ctx: ctx, r: body}); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to store %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to store %s: %w", key, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store %s: %w", key, err)
+	}
+	return nil
+}
+
+// Get opens the key's file
+func (f *localFiles) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := f.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", api.ErrFileNotFound, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", key, err)
+	}
+	if info, err := file.Stat(); err == nil && info.IsDir() {
+		_ = file.Close()
+		return nil, fmt.Errorf("%w: %s", api.ErrFileNotFound, key)
+	}
+	return file, nil
+}
+
+// Delete removes the key's file
+func (f *localFiles) Delete(_ context.Context, key string) error {
+	path, err := f.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// SignedURL returns <storage.publicurl><storage.route>/<key> with an
+// expiry and a signature of both
+func (f *localFiles) SignedURL(_ context.Context, key string, expires time.Duration) (string, error) {
+	if err := checkKey(key); err != nil {
+		return "", err
+	}
+	expiresAt := strconv.FormatInt(f.now().Add(expires).Unix(), 10)
+	query := url.Values{"expires": {expiresAt}, "signature": {f.sign(key, expiresAt)}}
+	return f.baseURL + "/" + escapeKey(key) + "?" + query.Encode(), nil
+}
+
+// ServeHTTP serves files by signed URL, with the route already stripped
+// from the request path
+func (f *localFiles) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	expiresAt := r.URL.Query().Get("expires")
+	expiry, err := strconv.ParseInt(expiresAt, 10, 64)
+	signature := r.URL.Query().Get("signature")
+	if err != nil || f.now().Unix() > expiry || !hmac.Equal([]byte(signature), []byte(f.sign(key, expiresAt))) {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	file, err := f.Get(r.Context(), key)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer file.Close()
+	osFile := file.(*os.File)
+	info, err := osFile.Stat()
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeContent(w, r, info.Name(), info.ModTime(), osFile)
+}
+
+// sign returns the hex-encoded HMAC of key and its expiry
+func (f *localFiles) sign(key, expiresAt string) string {
+	mac := hmac.New(sha256.New, f.secret)
+	mac.Write([]byte(key + "\n" + expiresAt))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// escapeKey escapes each segment of key for use in a URL path
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// contextReader stops reading once its context is done, so canceled
+// requests stop writing uploads
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+// Read reads from the underlying reader unless the context is done
+func (c contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
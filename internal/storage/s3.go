@@ -0,0 +1,217 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/evantahler/go-actionhero/internal/awssig"
+	"github.com/evantahler/go-actionhero/internal/config"
+)
+
+// s3Files stores files in an S3 bucket (or an S3-compatible one, e.g.,
+// MinIO or R2), signing requests with credentials from AWS_ACCESS_KEY_ID,
+// AWS_SECRET_ACCESS_KEY, and AWS_SESSION_TOKEN. Uploads are streamed, with
+// an unsigned payload.
+type s3Files struct {
+	bucket      string
+	region      string
+	endpoint    *url.URL
+	pathStyle   bool
+	credentials awssig.Credentials
+	client      *http.Client
+	now         func() time.Time
+}
+
+// newS3Files creates an S3 backend, falling back to AWS_REGION (or
+// AWS_DEFAULT_REGION) for the region
+func newS3Files(cfg config.StorageConfig) (*s3Files, error) {
+	f := &s3Files{
+		bucket:      cfg.S3Bucket,
+		region:      cfg.S3Region,
+		pathStyle:   cfg.S3PathStyle,
+		credentials: awssig.CredentialsFromEnv(),
+		client:      &http.Client{}, // Uploads and downloads are bounded by their context
+		now:         time.Now,
+	}
+	if f.region == "" {
+		f.region = os.Getenv("AWS_REGION")
+	}
+	if f.region == "" {
+		f.region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if f.region == "" {
+		return nil, errors.New("aws region is not set (storage.s3region or AWS_REGION)")
+	}
+	if !f.credentials.Valid() {
+		return nil, errors.New("aws credentials are not set (AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY)")
+	}
+
+	endpoint := cfg.S3Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", f.region)
+	}
+	var err error
+	if f.endpoint, err = url.Parse(strings.TrimSuffix(endpoint, "/")); err != nil {
+		return nil, fmt.Errorf("invalid storage.s3endpoint: %w", err)
+	}
+	return f, nil
+}
+
+// objectURL returns the URL of key's object: in the bucket's host name, or
+// in the path with storage.s3pathstyle
+func (f *s3Files) objectURL(key string) (*url.URL, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	u := *f.endpoint
+	path := "/" + key
+	if f.pathStyle {
+		path = "/" + f.bucket + path
+	} else {
+		u.Host = f.bucket + "." + u.Host
+	}
+	u.Path = f.endpoint.Path + path
+	u.RawPath = awssig.EscapePath(u.Path)
+	return &u, nil
+}
+
+// do signs and sends a request for key's object
+func (f *s3Files) do(ctx context.Context, method, key string, body io.Reader, contentType string) (*http.Response, error) {
+	u, err := f.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+	body, size, err := measure(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.ContentLength = size
+		if size == 0 {
+			req.Body = http.NoBody
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+	}
+	req.Header.Set("X-Amz-Content-Sha256", awssig.UnsignedPayload)
+	awssig.SignPayloadHash(req, awssig.UnsignedPayload, f.credentials, f.region, "s3", f.now())
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 request failed: %w", err)
+	}
+	return resp, nil
+}
+
+// Put uploads body as key's object
+func (f *s3Files) Put(ctx context.Context, key string, body io.Reader, contentType string) error {
+	resp, err := f.do(ctx, http.MethodPut, key, body, contentType)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return s3Error(resp)
+	}
+	return nil
+}
+
+// Get downloads key's object
+func (f *s3Files) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := f.do(ctx, http.MethodGet, key, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s", api.ErrFileNotFound, key)
+	default:
+		defer resp.Body.Close()
+		return nil, s3Error(resp)
+	}
+}
+
+// Delete deletes key's object (S3 reports success when there is none)
+func (f *s3Files) Delete(ctx context.Context, key string) error {
+	resp, err := f.do(ctx, http.MethodDelete, key, nil, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return s3Error(resp)
+	}
+	return nil
+}
+
+// SignedURL returns a presigned GET URL of key's object (S3 allows at most
+// 7 days)
+func (f *s3Files) SignedURL(_ context.Context, key string, expires time.Duration) (string, error) {
+	u, err := f.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+	return awssig.Presign(http.MethodGet, u, f.credentials, f.region, "s3", f.now(), expires), nil
+}
+
+// measure returns body with its length, which S3 requires up front: read
+// from in-memory readers and files, or by buffering anything else
+func measure(body io.Reader) (io.Reader, int64, error) {
+	switch b := body.(type) {
+	case nil:
+		return nil, 0, nil
+	case *bytes.Reader:
+		return b, int64(b.Len()), nil
+	case *bytes.Buffer:
+		return b, int64(b.Len()), nil
+	case *strings.Reader:
+		return b, int64(b.Len()), nil
+	case io.Seeker:
+		offset, err := b.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, 0, err
+		}
+		end, err := b.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, 0, err
+		}
+		if _, err := b.Seek(offset, io.SeekStart); err != nil {
+			return nil, 0, err
+		}
+		return body, end - offset, nil
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, 0, err
+	}
+	return bytes.NewReader(data), int64(len(data)), nil
+}
+
+// s3Error describes a failed response from its XML error document
+func s3Error(resp *http.Response) error {
+	var s3Err struct {
+		Code    string
+		Message string
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	_ = xml.Unmarshal(body, &s3Err)
+	return fmt.Errorf("s3 returned %d: %s %s", resp.StatusCode, s3Err.Code, s3Err.Message)
+}
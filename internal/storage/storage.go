@@ -0,0 +1,38 @@
+// Package storage stores files for actions (api.Files) on local disk or in
+// an S3-compatible bucket, chosen by storage.backend.
+package storage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/evantahler/go-actionhero/internal/config"
+)
+
+// newFiles creates the backend configured by storage.backend
+func newFiles(cfg config.StorageConfig) (api.Files, error) {
+	switch cfg.Backend {
+	case "local":
+		return newLocalFiles(cfg)
+	case "s3":
+		return newS3Files(cfg)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
+	}
+}
+
+// checkKey returns an error unless key is a slash-separated relative path
+// without empty, "." or ".." segments, so it names the same file in every
+// backend
+func checkKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("invalid file key: must not be empty")
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return fmt.Errorf("invalid file key %q: must be a relative path without empty, . or .. segments", key)
+		}
+	}
+	return nil
+}
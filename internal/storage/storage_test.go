@@ -0,0 +1,299 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// newTestLocalFiles creates a local backend in a temporary directory
+func newTestLocalFiles(t *testing.T) *localFiles {
+	t.Helper()
+	cfg := config.DefaultStorageConfig()
+	cfg.Directory = t.TempDir()
+	cfg.PublicURL = "https://api.example.com"
+	cfg.URLSecret = "secret"
+	files, err := newLocalFiles(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create local files: %v", err)
+	}
+	return files
+}
+
+// read reads the file stored under key
+func read(t *testing.T, files api.Files, key string) string {
+	t.Helper()
+	file, err := files.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Failed to get %s: %v", key, err)
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", key, err)
+	}
+	return string(data)
+}
+
+func TestCheckKey(t *testing.T) {
+	for _, key := range []string{"a.png", "avatars/42.png", "a/b/c..d.txt"} {
+		if err := checkKey(key); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", key, err)
+		}
+	}
+	for _, key := range []string{"", "/etc/passwd", "../secret", "a/../../b", "a//b", "a/", "./a"} {
+		if err := checkKey(key); err == nil {
+			t.Errorf("Expected %q to be invalid", key)
+		}
+	}
+}
+
+func TestLocalFiles(t *testing.T) {
+	ctx := context.Background()
+	files := newTestLocalFiles(t)
+
+	if err := files.Put(ctx, "avatars/42.png", strings.NewReader("first"), "image/png"); err != nil {
+		t.Fatalf("Failed to put: %v", err)
+	}
+	if err := files.Put(ctx, "avatars/42.png", strings.NewReader("second"), "image/png"); err != nil {
+		t.Fatalf("Failed to replace: %v", err)
+	}
+	if got := read(t, files, "avatars/42.png"); got != "second" {
+		t.Errorf("Expected the replaced file, got %q", got)
+	}
+	entries, _ := os.ReadDir(files.directory + "/avatars")
+	if len(entries) != 1 {
+		t.Errorf("Expected no temporary files left behind, got %d entries", len(entries))
+	}
+
+	if err := files.Delete(ctx, "avatars/42.png"); err != nil {
+		t.Fatalf("Failed to delete: %v", err)
+	}
+	if err := files.Delete(ctx, "avatars/42.png"); err != nil {
+		t.Errorf("Expected deleting a missing file to succeed, got %v", err)
+	}
+	if _, err := files.Get(ctx, "avatars/42.png"); !errors.Is(err, api.ErrFileNotFound) {
+		t.Errorf("Expected ErrFileNotFound, got %v", err)
+	}
+	if _, err := files.Get(ctx, "avatars"); !errors.Is(err, api.ErrFileNotFound) {
+		t.Errorf("Expected ErrFileNotFound for a directory, got %v", err)
+	}
+	if err := files.Put(ctx, "../escape.txt", strings.NewReader("x"), ""); err == nil {
+		t.Error("Expected an invalid key error")
+	}
+}
+
+func TestLocalFiles_SignedURL(t *testing.T) {
+	ctx := context.Background()
+	files := newTestLocalFiles(t)
+	if err := files.Put(ctx, "docs/my report.txt", strings.NewReader("hello"), "text/plain"); err != nil {
+		t.Fatalf("Failed to put: %v", err)
+	}
+
+	signed, err := files.SignedURL(ctx, "docs/my report.txt", time.Minute)
+	if err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+	if !strings.HasPrefix(signed, "https://api.example.com/files/docs/my%20report.txt?expires=") {
+		t.Fatalf("Unexpected URL: %s", signed)
+	}
+
+	handler := http.StripPrefix("/files", files)
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+	target := strings.TrimPrefix(signed, "https://api.example.com")
+
+	rec := get(target)
+	if rec.Code != http.StatusOK || rec.Body.String() != "hello" {
+		t.Errorf("Expected the file, got %d %q", rec.Code, rec.Body.String())
+	}
+	if contentType := rec.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/plain") {
+		t.Errorf("Expected the extension's content type, got %q", contentType)
+	}
+	if rec := get(strings.Replace(target, "my%20report", "other", 1)); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for another key, got %d", rec.Code)
+	}
+	if rec := get(strings.Replace(target, "signature=", "signature=0", 1)); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a bad signature, got %d", rec.Code)
+	}
+
+	files.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	if rec := get(target); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 once expired, got %d", rec.Code)
+	}
+}
+
+func TestLocalFiles_RandomSecret(t *testing.T) {
+	cfg := config.DefaultStorageConfig()
+	cfg.Directory = t.TempDir()
+	first, _ := newLocalFiles(cfg)
+	second, _ := newLocalFiles(cfg)
+	if first.sign("a", "1") == second.sign("a", "1") {
+		t.Error("Expected a random secret per backend")
+	}
+}
+
+// fakeS3 is an in-memory S3 bucket, addressed in the path
+type fakeS3 struct {
+	mu       sync.Mutex
+	objects  map[string]string
+	types    map[string]string
+	requests []*http.Request
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r)
+	if !strings.Contains(r.Header.Get("Authorization"), "/us-west-2/s3/aws4_request") ||
+		r.Header.Get("X-Amz-Content-Sha256") != "UNSIGNED-PAYLOAD" {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>`))
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, "/uploads/")
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		s.objects[key] = string(data)
+		s.types[key] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		data, ok := s.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`<Error><Code>NoSuchKey</Code></Error>`))
+			return
+		}
+		_, _ = w.Write([]byte(data))
+	case http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3Files(t *testing.T) {
+	ctx := context.Background()
+	bucket := &fakeS3{objects: map[string]string{}, types: map[string]string{}}
+	server := httptest.NewServer(bucket)
+	defer server.Close()
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+	cfg := config.DefaultStorageConfig()
+	cfg.Backend = "s3"
+	cfg.S3Bucket = "uploads"
+	cfg.S3Region = "us-west-2"
+	cfg.S3Endpoint = server.URL
+	cfg.S3PathStyle = true
+	files, err := newFiles(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create s3 files: %v", err)
+	}
+
+	if err := files.Put(ctx, "avatars/my photo.png", strings.NewReader("png"), "image/png"); err != nil {
+		t.Fatalf("Failed to put: %v", err)
+	}
+	if bucket.objects["avatars/my photo.png"] != "png" || bucket.types["avatars/my photo.png"] != "image/png" {
+		t.Errorf("Unexpected objects: %v %v", bucket.objects, bucket.types)
+	}
+	if path := bucket.requests[0].URL.EscapedPath(); path != "/uploads/avatars/my%20photo.png" {
+		t.Errorf("Unexpected path: %s", path)
+	}
+	if err := files.Put(ctx, "empty.txt", strings.NewReader(""), ""); err != nil {
+		t.Fatalf("Failed to put an empty file: %v", err)
+	}
+	if got := read(t, files, "avatars/my photo.png"); got != "png" {
+		t.Errorf("Unexpected file: %q", got)
+	}
+
+	if err := files.Delete(ctx, "avatars/my photo.png"); err != nil {
+		t.Fatalf("Failed to delete: %v", err)
+	}
+	if _, err := files.Get(ctx, "avatars/my photo.png"); !errors.Is(err, api.ErrFileNotFound) {
+		t.Errorf("Expected ErrFileNotFound, got %v", err)
+	}
+
+	signed, err := files.SignedURL(ctx, "avatars/my photo.png", time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+	if !strings.HasPrefix(signed, server.URL+"/uploads/avatars/my%20photo.png?X-Amz-Algorithm=") ||
+		!strings.Contains(signed, "X-Amz-Expires=3600") || !strings.Contains(signed, "&X-Amz-Signature=") {
+		t.Errorf("Unexpected URL: %s", signed)
+	}
+
+	cfg.S3Region = "eu-west-1" // Rejected by the fake bucket
+	files, _ = newFiles(cfg)
+	err = files.Put(ctx, "a.txt", strings.NewReader("a"), "")
+	if err == nil || !strings.Contains(err.Error(), "403: AccessDenied Access Denied") {
+		t.Errorf("Expected the S3 error, got %v", err)
+	}
+}
+
+func TestS3Files_VirtualHost(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	cfg := config.DefaultStorageConfig()
+	cfg.Backend = "s3"
+	cfg.S3Bucket = "uploads"
+	cfg.S3Region = "us-east-1"
+	files, err := newS3Files(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create s3 files: %v", err)
+	}
+	u, _ := files.objectURL("a/b.txt")
+	if u.String() != "https://uploads.s3.us-east-1.amazonaws.com/a/b.txt" {
+		t.Errorf("Unexpected URL: %s", u)
+	}
+}
+
+func TestS3Files_MissingSettings(t *testing.T) {
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	cfg := config.DefaultStorageConfig()
+	cfg.Backend = "s3"
+	cfg.S3Bucket = "uploads"
+	if _, err := newFiles(cfg); err == nil || !strings.Contains(err.Error(), "region") {
+		t.Errorf("Expected a missing region error, got %v", err)
+	}
+	cfg.S3Region = "us-east-1"
+	if _, err := newFiles(cfg); err == nil || !strings.Contains(err.Error(), "credentials") {
+		t.Errorf("Expected a missing credentials error, got %v", err)
+	}
+}
+
+func TestInitializer(t *testing.T) {
+	cfg := &config.Config{Storage: config.DefaultStorageConfig()}
+	a := api.New(cfg, util.NewLogger(config.LoggerConfig{Level: "fatal"}))
+	if err := NewInitializer().Initialize(a); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	if err := a.Files.Put(context.Background(), "a.txt", strings.NewReader("a"), ""); !errors.Is(err, api.ErrStorageDisabled) {
+		t.Errorf("Expected ErrStorageDisabled, got %v", err)
+	}
+
+	cfg.Storage.Enabled = true
+	cfg.Storage.Directory = t.TempDir()
+	if err := NewInitializer().Initialize(a); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	if err := a.Files.Put(context.Background(), "a.txt", strings.NewReader("a"), ""); err != nil {
+		t.Errorf("Expected the local backend, got %v", err)
+	}
+}
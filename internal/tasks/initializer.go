@@ -0,0 +1,72 @@
+package tasks
+
+import (
+	"github.com/evantahler/go-actionhero/internal/api"
+)
+
+// Initializer runs background tasks when tasks.enabled is set: it makes the
+// queues in Redis the backend of the API's Tasks and its api.TaskQueues, and
+// while the API runs,
+// tasks.taskprocessors processors run the jobs of tasks.queues
+type Initializer struct {
+	queue  *Queue
+	worker *Worker
+}
+
+// NewInitializer creates the tasks initializer
+func NewInitializer() *Initializer {
+	return &Initializer{}
+}
+
+// Name returns the initializer name
+func (i *Initializer) Name() string {
+	return "tasks"
+}
+
+// Priority returns the initialization priority
+func (i *Initializer) Priority() int {
+	return 100
+}
+
+// Initialize creates the queues, and makes them the backend of the API's
+// Tasks and its api.TaskQueues. Redis is connected to on first use, so the
+// API starts even while it is down.
+func (i *Initializer) Initialize(a *api.API) error {
+	cfg := a.Config.Tasks
+	if !cfg.Enabled {
+		i.queue = nil
+		a.Tasks.SetBackend(nil)
+		return nil
+	}
+
+	i.queue = NewQueue(a.Config.Redis, cfg.Queues)
+	a.Tasks.SetBackend(i.queue)
+	api.Provide[api.TaskQueues](a, i.queue)
+	return nil
+}
+
+// Start starts the task processors
+func (i *Initializer) Start(a *api.API) error {
+	cfg := a.Config.Tasks
+	if i.queue == nil || cfg.TaskProcessors == 0 {
+		return nil
+	}
+
+	i.worker = NewWorker(a, i.queue, cfg)
+	i.worker.Start()
+	a.Logger.Infof("Task workers started: %d processors on queues %v", cfg.TaskProcessors, cfg.Queues)
+	return nil
+}
+
+// Stop stops the task processors, waiting for their jobs to finish
+func (i *Initializer) Stop(a *api.API) error {
+	if i.worker != nil {
+		i.worker.Stop()
+		i.worker = nil
+	}
+	if i.queue != nil {
+		a.Tasks.SetBackend(nil)
+		return i.queue.Close()
+	}
+	return nil
+}
@@ -0,0 +1,335 @@
+// Package tasks runs actions as background tasks: jobs are queued in Redis,
+// and workers run them with the action's ActionTask configuration
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/google/uuid"
+)
+
+// Redis keys of the task queues
+const (
+	keyPrefix      = "actionhero:tasks:"
+	failedKey      = keyPrefix + "failed"     // Hash of failed tasks, by ID
+	delayedKey     = keyPrefix + "delayed"    // Sorted set of delayed jobs, scored by when they are due (Unix ms)
+	processingKey  = keyPrefix + "processing" // List of the jobs workers are running
+	claimedKey     = keyPrefix + "claimed"    // Sorted set of the jobs on the processing list, scored by when they were claimed (Unix ms)
+	queueKeyPrefix = keyPrefix + "queue:"
+)
+
+// promoteScript moves up to ARGV[2] delayed jobs (KEYS[1]) due by ARGV[1] to
+// the end of their queues, whose keys start with ARGV[3], and returns how
+// many it moved. As a script, each job is moved atomically: it can't be lost
+// between the two keys, or moved twice by workers promoting at once.
+const promoteScript = `
+local jobs = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+local promoted = 0
+for _, data in ipairs(jobs) do
+	redis.call('ZREM', KEYS[1], data)
+	local ok, job = pcall(cjson.decode, data)
+	if ok and type(job) == 'table' and type(job.queue) == 'string' then
+		redis.call('LPUSH', ARGV[3] .. job.queue, data)
+		promoted = promoted + 1
+	end
+end
+return promoted
+`
+
+// claimScript takes the next job from the first of the queues KEYS[1..n-2]
+// that has one, and adds it to the processing list KEYS[n-1] in the same
+// step, so a job taken by a worker that dies before running it isn't lost.
+// When it was claimed (ARGV[1], Unix ms) is recorded in KEYS[n], for finding
+// the jobs of dead workers (see recoverScript). It returns the job's queue
+// and the job, or nil when the queues are empty.
+const claimScript = `
+for i = 1, #KEYS - 2 do
+	local data = redis.call('RPOP', KEYS[i])
+	if data then
+		redis.call('LPUSH', KEYS[#KEYS - 1], data)
+		redis.call('ZADD', KEYS[#KEYS], ARGV[1], data)
+		return {KEYS[i], data}
+	end
+end
+return nil
+`
+
+// ackScript removes a job (ARGV[1]) from the processing list KEYS[1] and
+// the claim times KEYS[2], and returns how many it removed from the list
+const ackScript = `
+redis.call('ZREM', KEYS[2], ARGV[1])
+return redis.call('LREM', KEYS[1], 1, ARGV[1])
+`
+
+// recoverScript takes back a job (ARGV[1]) claimed (KEYS[1]) no later than
+// ARGV[2] (Unix ms) from the processing list KEYS[2]. Without a failed task
+// ID (ARGV[3]), it is added to the front of its queue KEYS[3]; otherwise the
+// failed task ARGV[4] is recorded in the failed hash KEYS[3]. It returns 0,
+// doing nothing, when the job has finished or was claimed again since.
+const recoverScript = `
+local claimed = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if not claimed or tonumber(claimed) > tonumber(ARGV[2]) then
+	return 0
+end
+redis.call('ZREM', KEYS[1], ARGV[1])
+if redis.call('LREM', KEYS[2], 1, ARGV[1]) == 0 then
+	return 0
+end
+if ARGV[3] == '' then
+	redis.call('RPUSH', KEYS[3], ARGV[1])
+else
+	redis.call('HSET', KEYS[3], ARGV[3], ARGV[4])
+end
+return 1
+`
+
+// commandTimeout bounds connecting to Redis and each (non-blocking) command
+const commandTimeout = 5 * time.Second
+
+// queueKey returns the key of the list holding queue's jobs
+func queueKey(queue string) string {
+	return queueKeyPrefix + queue
+}
+
+// Job is a task waiting in a queue: an action to run, with its params
+type Job = api.Task
+
+// Queue keeps jobs in Redis, in a list per queue (first in, first out), and
+// the tasks that failed in a hash, so they can be inspected and retried.
+// Delayed jobs wait in a sorted set until workers promote them to their
+// queue. Workers move each job they take to a processing list, and remove it
+// once it has run (see Queue.ack); jobs left there by dead workers are taken
+// back once stuck (see Queue.recover). It implements api.TaskBackend and
+// api.TaskQueues.
+type Queue struct {
+	client *client
+	queues []string // Configured queues, reported by Queues
+}
+
+// NewQueue creates the queues stored in the configured Redis server
+func NewQueue(redis config.RedisConfig, queues []string) *Queue {
+	return &Queue{client: newClient(redis, commandTimeout), queues: queues}
+}
+
+// Enqueue adds a job running action with params to the end of queue. Use
+// the API's Tasks.Enqueue, which checks the action and serializes its params.
+func (q *Queue) Enqueue(ctx context.Context, action string, params map[string]interface{}, queue string) (*Job, error) {
+	job := newJob(action, params, queue)
+	if err := q.push(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// EnqueueAt keeps a job running action with params until at, when a worker
+// adds it to the end of queue. Jobs due already are added right away.
+func (q *Queue) EnqueueAt(ctx context.Context, at time.Time, action string, params map[string]interface{}, queue string) (*Job, error) {
+	job := newJob(action, params, queue)
+	if !at.After(job.EnqueuedAt) {
+		return q.Enqueue(ctx, action, params, queue)
+	}
+	job.RunAt = at.UTC()
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job: %w", err)
+	}
+	if _, err := q.client.Do(ctx, "ZADD", delayedKey, strconv.FormatInt(at.UnixMilli(), 10), string(data)); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Queues returns the configured queues, with how many jobs wait in each and
+// how many of their tasks failed
+func (q *Queue) Queues(ctx context.Context) ([]api.TaskQueue, error) {
+	failed, err := q.allFailed(ctx)
+	if err != nil {
+		return nil, err
+	}
+	failedByQueue := make(map[string]int)
+	for _, task := range failed {
+		failedByQueue[task.Queue]++
+	}
+
+	queues := make([]api.TaskQueue, 0, len(q.queues))
+	for _, name := range q.queues {
+		reply, err := q.client.Do(ctx, "LLEN", queueKey(name))
+		if err != nil {
+			return nil, err
+		}
+		pending, _ := reply.(int64)
+		queues = append(queues, api.TaskQueue{Name: name, Pending: int(pending), Failed: failedByQueue[name]})
+	}
+	return queues, nil
+}
+
+// FailedTasks returns up to limit failed tasks, most recent first
+func (q *Queue) FailedTasks(ctx context.Context, limit int) ([]api.FailedTask, error) {
+	failed, err := q.allFailed(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(failed, func(i, j int) bool { return failed[i].FailedAt.After(failed[j].FailedAt) })
+	if limit > 0 && len(failed) > limit {
+		failed = failed[:limit]
+	}
+	return failed, nil
+}
+
+// RetryTask queues the failed task id again, at the end of its queue
+func (q *Queue) RetryTask(ctx context.Context, id string) error {
+	reply, err := q.client.Do(ctx, "HGET", failedKey, id)
+	if err != nil {
+		return err
+	}
+	data, ok := reply.(string)
+	if !ok {
+		return fmt.Errorf("failed task %s not found", id)
+	}
+	var task api.FailedTask
+	if err := json.Unmarshal([]byte(data), &task); err != nil {
+		return fmt.Errorf("failed task %s is corrupt: %w", id, err)
+	}
+
+	job := &Job{ID: task.ID, Action: task.Task, Queue: task.Queue, Params: task.Params, EnqueuedAt: time.Now().UTC()}
+	if err := q.push(ctx, job); err != nil {
+		return err
+	}
+	_, err = q.client.Do(ctx, "HDEL", failedKey, id)
+	return err
+}
+
+// promote moves up to limit delayed jobs due by now to their queues, and
+// returns how many it moved (see promoteScript)
+func (q *Queue) promote(ctx context.Context, now time.Time, limit int) (int, error) {
+	reply, err := q.client.Do(ctx, evalArgs(promoteScript, []string{delayedKey},
+		strconv.FormatInt(now.UnixMilli(), 10), strconv.Itoa(limit), queueKeyPrefix)...)
+	if err != nil {
+		return 0, err
+	}
+	promoted, _ := reply.(int64)
+	return int(promoted), nil
+}
+
+// ack removes a job a worker has finished with (data, as claimed) from the
+// processing list
+func (q *Queue) ack(ctx context.Context, data string) error {
+	_, err := q.client.Do(ctx, evalArgs(ackScript, []string{processingKey, claimedKey}, data)...)
+	return err
+}
+
+// stuck returns up to limit jobs (as claimed) still on the processing list
+// that were claimed before cutoff
+func (q *Queue) stuck(ctx context.Context, cutoff time.Time, limit int) ([]string, error) {
+	reply, err := q.client.Do(ctx, "ZRANGEBYSCORE", claimedKey, "-inf",
+		strconv.FormatInt(cutoff.UnixMilli(), 10), "LIMIT", "0", strconv.Itoa(limit))
+	if err != nil {
+		return nil, err
+	}
+	values, _ := reply.([]interface{})
+	stuck := make([]string, 0, len(values))
+	for _, value := range values {
+		if data, ok := value.(string); ok {
+			stuck = append(stuck, data)
+		}
+	}
+	return stuck, nil
+}
+
+// recover takes job (data, as claimed before cutoff) back from the
+// processing list: to the front of its queue with retry, and to the failed
+// tasks with cause otherwise. It returns false when the job finished or was
+// claimed again in the meantime (see recoverScript).
+func (q *Queue) recover(ctx context.Context, data string, cutoff time.Time, job *Job, retry bool, cause error) (bool, error) {
+	destination, failedID, failed := queueKey(job.Queue), "", ""
+	if !retry {
+		record, err := json.Marshal(newFailedTask(job, cause))
+		if err != nil {
+			return false, fmt.Errorf("failed to encode failed task: %w", err)
+		}
+		destination, failedID, failed = failedKey, job.ID, string(record)
+	}
+
+	reply, err := q.client.Do(ctx, evalArgs(recoverScript, []string{claimedKey, processingKey, destination},
+		data, strconv.FormatInt(cutoff.UnixMilli(), 10), failedID, failed)...)
+	if err != nil {
+		return false, err
+	}
+	recovered, _ := reply.(int64)
+	return recovered == 1, nil
+}
+
+// Close closes the queue's connections
+func (q *Queue) Close() error {
+	return q.client.Close()
+}
+
+// newJob creates a job running action with params on queue
+func newJob(action string, params map[string]interface{}, queue string) *Job {
+	return &Job{
+		ID:         uuid.New().String(),
+		Action:     action,
+		Queue:      queue,
+		Params:     params,
+		EnqueuedAt: time.Now().UTC(),
+	}
+}
+
+// push adds job to the end of its queue
+func (q *Queue) push(ctx context.Context, job *Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to encode job: %w", err)
+	}
+	_, err = q.client.Do(ctx, "LPUSH", queueKey(job.Queue), string(data))
+	return err
+}
+
+// fail records that job failed with cause, so it can be retried
+func (q *Queue) fail(ctx context.Context, job *Job, cause error) error {
+	data, err := json.Marshal(newFailedTask(job, cause))
+	if err != nil {
+		return fmt.Errorf("failed to encode failed task: %w", err)
+	}
+	_, err = q.client.Do(ctx, "HSET", failedKey, job.ID, string(data))
+	return err
+}
+
+// newFailedTask records that job failed with cause
+func newFailedTask(job *Job, cause error) api.FailedTask {
+	return api.FailedTask{
+		ID:       job.ID,
+		Queue:    job.Queue,
+		Task:     job.Action,
+		Params:   job.Params,
+		Error:    cause.Error(),
+		FailedAt: time.Now().UTC(),
+	}
+}
+
+// allFailed returns every failed task
+func (q *Queue) allFailed(ctx context.Context) ([]api.FailedTask, error) {
+	reply, err := q.client.Do(ctx, "HVALS", failedKey)
+	if err != nil {
+		return nil, err
+	}
+	values, _ := reply.([]interface{})
+	failed := make([]api.FailedTask, 0, len(values))
+	for _, value := range values {
+		data, _ := value.(string)
+		var task api.FailedTask
+		if err := json.Unmarshal([]byte(data), &task); err != nil {
+			continue // Not written by this package
+		}
+		failed = append(failed, task)
+	}
+	return failed, nil
+}
@@ -0,0 +1,231 @@
+package tasks
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+)
+
+// maxIdleConns is how many idle connections the client keeps for reuse
+const maxIdleConns = 4
+
+// redisError is an error reply from Redis (e.g., "WRONGTYPE ...")
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// client is a minimal Redis client: just enough of the protocol (RESP) for
+// the task queues, so the framework doesn't depend on a Redis library
+type client struct {
+	address  string
+	password string
+	db       int
+	timeout  time.Duration // Dial and command timeout when ctx has no deadline
+
+	mu   sync.Mutex
+	idle []*redisConn
+}
+
+// redisConn is a connection to Redis
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// newClient creates a client of the configured Redis server. It connects
+// lazily, on the first command.
+func newClient(cfg config.RedisConfig, timeout time.Duration) *client {
+	return &client{
+		address:  net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		password: cfg.Password,
+		db:       cfg.DB,
+		timeout:  timeout,
+	}
+}
+
+// Do runs a command on an idle connection (or a new one), e.g.
+// Do(ctx, "LPUSH", key, value). Replies are strings, int64s, nil, or
+// []interface{} of those; error replies are returned as errors.
+func (c *client) Do(ctx context.Context, args ...string) (interface{}, error) {
+	conn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := conn.do(c.deadline(ctx, 0), args...)
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		_ = conn.close() // The connection is in an unknown state
+		return nil, err
+	}
+	c.put(conn)
+	return reply, err
+}
+
+// Close closes the idle connections
+func (c *client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, conn := range c.idle {
+		_ = conn.close()
+	}
+	c.idle = nil
+	return nil
+}
+
+// dial opens a connection, authenticated and on the configured database
+func (c *client) dial(ctx context.Context) (*redisConn, error) {
+	dialer := net.Dialer{Timeout: c.timeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", c.address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to redis at %s: %w", c.address, err)
+	}
+	conn := &redisConn{conn: netConn, reader: bufio.NewReader(netConn)}
+
+	if c.password != "" {
+		if _, err := conn.do(c.deadline(ctx, 0), "AUTH", c.password); err != nil {
+			_ = conn.close()
+			return nil, err
+		}
+	}
+	if c.db != 0 {
+		if _, err := conn.do(c.deadline(ctx, 0), "SELECT", strconv.Itoa(c.db)); err != nil {
+			_ = conn.close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// get returns an idle connection, or dials a new one
+func (c *client) get(ctx context.Context) (*redisConn, error) {
+	c.mu.Lock()
+	if n := len(c.idle); n > 0 {
+		conn := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		c.mu.Unlock()
+		return conn, nil
+	}
+	c.mu.Unlock()
+	return c.dial(ctx)
+}
+
+// put returns a connection to the idle pool
+func (c *client) put(conn *redisConn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.idle) >= maxIdleConns {
+		_ = conn.close()
+		return
+	}
+	c.idle = append(c.idle, conn)
+}
+
+// deadline returns when a command must complete: ctx's deadline, or the
+// client's timeout (plus block, for blocking commands)
+func (c *client) deadline(ctx context.Context, block time.Duration) time.Time {
+	if deadline, ok := ctx.Deadline(); ok && block == 0 {
+		return deadline
+	}
+	return time.Now().Add(c.timeout + block)
+}
+
+// do writes a command and reads its reply
+func (rc *redisConn) do(deadline time.Time, args ...string) (interface{}, error) {
+	if err := rc.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+	if _, err := rc.conn.Write(encodeCommand(args)); err != nil {
+		return nil, err
+	}
+	return readReply(rc.reader)
+}
+
+// close closes the connection
+func (rc *redisConn) close() error {
+	return rc.conn.Close()
+}
+
+// evalArgs returns the command running the Lua script with keys and args
+func evalArgs(script string, keys []string, args ...string) []string {
+	command := make([]string, 0, 3+len(keys)+len(args))
+	command = append(command, "EVAL", script, strconv.Itoa(len(keys)))
+	command = append(command, keys...)
+	return append(command, args...)
+}
+
+// encodeCommand encodes args as a RESP array of bulk strings
+func encodeCommand(args []string) []byte {
+	buf := make([]byte, 0, 64)
+	buf = append(buf, '*')
+	buf = strconv.AppendInt(buf, int64(len(args)), 10)
+	buf = append(buf, '\r', '\n')
+	for _, arg := range args {
+		buf = append(buf, '$')
+		buf = strconv.AppendInt(buf, int64(len(arg)), 10)
+		buf = append(buf, '\r', '\n')
+		buf = append(buf, arg...)
+		buf = append(buf, '\r', '\n')
+	}
+	return buf
+}
+
+// readReply reads one RESP reply
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("redis: malformed reply %q", line)
+	}
+	kind, payload := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return payload, nil
+	case '-':
+		return nil, redisError(payload)
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '$':
+		size, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed bulk length %q", payload)
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return string(data[:size]), nil
+	case '*':
+		count, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed array length %q", payload)
+		}
+		if count < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, count)
+		for i := range items {
+			if items[i], err = readReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("redis: unknown reply type %q", kind)
+}
@@ -0,0 +1,815 @@
+package tasks
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// fakeRedis is an in-memory Redis server with the commands the task queues
+// use. It runs the queues' Lua scripts natively.
+type fakeRedis struct {
+	listener net.Listener
+	password string
+
+	mu       sync.Mutex
+	lists    map[string][]string
+	hashes   map[string]map[string]string
+	zsets    map[string]map[string]float64
+	commands []string // Names of the commands received, in order
+}
+
+func newFakeRedis(t *testing.T, password string) (*fakeRedis, config.RedisConfig) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	r := &fakeRedis{
+		listener: listener,
+		password: password,
+		lists:    make(map[string][]string),
+		hashes:   make(map[string]map[string]string),
+		zsets:    make(map[string]map[string]float64),
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	go r.serve()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return r, config.RedisConfig{Host: "127.0.0.1", Port: addr.Port, Password: password, DB: 2}
+}
+
+func (r *fakeRedis) serve() {
+	for {
+		conn, err := r.listener.Accept()
+		if err != nil {
+			return
+		}
+		go r.handle(conn)
+	}
+}
+
+func (r *fakeRedis) handle(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	reader := bufio.NewReader(conn)
+	authed := r.password == ""
+	for {
+		reply, err := readReply(reader)
+		if err != nil {
+			return
+		}
+		items, _ := reply.([]interface{})
+		args := make([]string, len(items))
+		for i, item := range items {
+			args[i], _ = item.(string)
+		}
+		if len(args) == 0 {
+			return
+		}
+
+		name := strings.ToUpper(args[0])
+		r.mu.Lock()
+		r.commands = append(r.commands, name)
+		r.mu.Unlock()
+
+		var out string
+		switch {
+		case name == "AUTH":
+			authed = len(args) == 2 && args[1] == r.password
+			out = "+OK\r\n"
+			if !authed {
+				out = "-WRONGPASS invalid password\r\n"
+			}
+		case !authed:
+			out = "-NOAUTH Authentication required.\r\n"
+		default:
+			out = r.run(name, args[1:])
+		}
+		if _, err := conn.Write([]byte(out)); err != nil {
+			return
+		}
+	}
+}
+
+func (r *fakeRedis) run(name string, args []string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch name {
+	case "PING", "SELECT":
+		return "+OK\r\n"
+	case "LPUSH":
+		r.lists[args[0]] = append([]string{args[1]}, r.lists[args[0]]...)
+		return ":" + strconv.Itoa(len(r.lists[args[0]])) + "\r\n"
+	case "LLEN":
+		return ":" + strconv.Itoa(len(r.lists[args[0]])) + "\r\n"
+	case "LREM": // key count value (count is always 1)
+		return ":" + strconv.Itoa(r.lrem(args[0], args[2])) + "\r\n"
+	case "EVAL": // script numkeys key... arg...
+		numKeys, _ := strconv.Atoi(args[1])
+		keys, argv := args[2:2+numKeys], args[2+numKeys:]
+		switch args[0] {
+		case promoteScript:
+			return r.promote(keys[0], argv[0], argv[1], argv[2])
+		case claimScript:
+			return r.claim(keys[:len(keys)-2], keys[len(keys)-2], keys[len(keys)-1], argv[0])
+		case ackScript:
+			delete(r.zsets[keys[1]], argv[0])
+			return ":" + strconv.Itoa(r.lrem(keys[0], argv[0])) + "\r\n"
+		case recoverScript:
+			return r.recover(keys[0], keys[1], keys[2], argv)
+		}
+		return "-ERR unknown script\r\n"
+	case "HSET":
+		if r.hashes[args[0]] == nil {
+			r.hashes[args[0]] = make(map[string]string)
+		}
+		r.hashes[args[0]][args[1]] = args[2]
+		return ":1\r\n"
+	case "HGET":
+		value, ok := r.hashes[args[0]][args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return bulk(value)
+	case "HDEL":
+		delete(r.hashes[args[0]], args[1])
+		return ":1\r\n"
+	case "HVALS":
+		out := "*" + strconv.Itoa(len(r.hashes[args[0]])) + "\r\n"
+		for _, value := range r.hashes[args[0]] {
+			out += bulk(value)
+		}
+		return out
+	case "ZADD":
+		if r.zsets[args[0]] == nil {
+			r.zsets[args[0]] = make(map[string]float64)
+		}
+		score, _ := strconv.ParseFloat(args[1], 64)
+		r.zsets[args[0]][args[2]] = score
+		return ":1\r\n"
+	case "ZREM":
+		if _, ok := r.zsets[args[0]][args[1]]; !ok {
+			return ":0\r\n"
+		}
+		delete(r.zsets[args[0]], args[1])
+		return ":1\r\n"
+	case "ZRANGEBYSCORE": // key min max LIMIT offset count
+		members := r.due(args[0], args[2], args[5])
+		out := "*" + strconv.Itoa(len(members)) + "\r\n"
+		for _, member := range members {
+			out += bulk(member)
+		}
+		return out
+	}
+	return "-ERR unknown command '" + name + "'\r\n"
+}
+
+// due returns up to limit members of the sorted set key scored up to upTo,
+// lowest first
+func (r *fakeRedis) due(key, upTo, limit string) []string {
+	maxScore, _ := strconv.ParseFloat(upTo, 64)
+	count, _ := strconv.Atoi(limit)
+	var members []string
+	for member, score := range r.zsets[key] {
+		if score <= maxScore {
+			members = append(members, member)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return r.zsets[key][members[i]] < r.zsets[key][members[j]]
+	})
+	if len(members) > count {
+		members = members[:count]
+	}
+	return members
+}
+
+// promote runs promoteScript
+func (r *fakeRedis) promote(delayed, now, limit, prefix string) string {
+	promoted := 0
+	for _, data := range r.due(delayed, now, limit) {
+		delete(r.zsets[delayed], data)
+		var job Job
+		if json.Unmarshal([]byte(data), &job) != nil {
+			continue
+		}
+		key := prefix + job.Queue
+		r.lists[key] = append([]string{data}, r.lists[key]...)
+		promoted++
+	}
+	return ":" + strconv.Itoa(promoted) + "\r\n"
+}
+
+// claim runs claimScript
+func (r *fakeRedis) claim(queues []string, processing, claimed, now string) string {
+	for _, key := range queues {
+		if list := r.lists[key]; len(list) > 0 {
+			data := list[len(list)-1]
+			r.lists[key] = list[:len(list)-1]
+			r.lists[processing] = append([]string{data}, r.lists[processing]...)
+			if r.zsets[claimed] == nil {
+				r.zsets[claimed] = make(map[string]float64)
+			}
+			r.zsets[claimed][data], _ = strconv.ParseFloat(now, 64)
+			return "*2\r\n" + bulk(key) + bulk(data)
+		}
+	}
+	return "$-1\r\n"
+}
+
+// recover runs recoverScript
+func (r *fakeRedis) recover(claimed, processing, destination string, argv []string) string {
+	data := argv[0]
+	score, ok := r.zsets[claimed][data]
+	cutoff, _ := strconv.ParseFloat(argv[1], 64)
+	if !ok || score > cutoff {
+		return ":0\r\n"
+	}
+	delete(r.zsets[claimed], data)
+	if r.lrem(processing, data) == 0 {
+		return ":0\r\n"
+	}
+	if argv[2] == "" {
+		r.lists[destination] = append(r.lists[destination], data)
+	} else {
+		if r.hashes[destination] == nil {
+			r.hashes[destination] = make(map[string]string)
+		}
+		r.hashes[destination][argv[2]] = argv[3]
+	}
+	return ":1\r\n"
+}
+
+// lrem removes the first value from the list key, returning how many it
+// removed
+func (r *fakeRedis) lrem(key, value string) int {
+	for i, item := range r.lists[key] {
+		if item == value {
+			r.lists[key] = append(r.lists[key][:i:i], r.lists[key][i+1:]...)
+			return 1
+		}
+	}
+	return 0
+}
+
+func (r *fakeRedis) received(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, command := range r.commands {
+		if command == name {
+			return true
+		}
+	}
+	return false
+}
+
+func bulk(value string) string {
+	return "$" + strconv.Itoa(len(value)) + "\r\n" + value + "\r\n"
+}
+
+func newTestAPI(t *testing.T, redis config.RedisConfig, tasks config.TasksConfig) *api.API {
+	t.Helper()
+	return api.New(&config.Config{Redis: redis, Tasks: tasks}, util.NewLogger(config.LoggerConfig{Level: "error"}))
+}
+
+func TestQueue(t *testing.T) {
+	fake, redisCfg := newFakeRedis(t, "secret")
+	queue := NewQueue(redisCfg, []string{"high", "default"})
+	defer func() { _ = queue.Close() }()
+	ctx := context.Background()
+
+	job, err := queue.Enqueue(ctx, "user:welcome", map[string]interface{}{"id": "1"}, "default")
+	if err != nil {
+		t.Fatalf("Failed to enqueue: %v", err)
+	}
+	if job.ID == "" || job.Queue != "default" {
+		t.Errorf("Expected a job with an ID in the default queue, got %+v", job)
+	}
+	if !fake.received("AUTH") || !fake.received("SELECT") {
+		t.Error("Expected the client to authenticate and select the database")
+	}
+
+	if err := queue.fail(ctx, job, errors.New("boom")); err != nil {
+		t.Fatalf("Failed to record a failed task: %v", err)
+	}
+	queues, err := queue.Queues(ctx)
+	if err != nil {
+		t.Fatalf("Failed to list queues: %v", err)
+	}
+	if len(queues) != 2 || queues[0].Name != "high" || queues[1].Pending != 1 || queues[1].Failed != 1 {
+		t.Errorf("Expected the configured queues with their counts, got %+v", queues)
+	}
+
+	failed, err := queue.FailedTasks(ctx, 10)
+	if err != nil {
+		t.Fatalf("Failed to list failed tasks: %v", err)
+	}
+	if len(failed) != 1 || failed[0].Task != "user:welcome" || failed[0].Error != "boom" || failed[0].Params["id"] != "1" {
+		t.Fatalf("Expected the failed task, got %+v", failed)
+	}
+
+	if err := queue.RetryTask(ctx, job.ID); err != nil {
+		t.Fatalf("Failed to retry: %v", err)
+	}
+	queues, _ = queue.Queues(ctx)
+	if queues[1].Pending != 2 || queues[1].Failed != 0 {
+		t.Errorf("Expected the retried task back in its queue, got %+v", queues)
+	}
+	if err := queue.RetryTask(ctx, job.ID); err == nil {
+		t.Error("Expected retrying a task that isn't failed to fail")
+	}
+}
+
+func TestQueue_Delayed(t *testing.T) {
+	fake, redisCfg := newFakeRedis(t, "")
+	queue := NewQueue(redisCfg, []string{"default"})
+	defer func() { _ = queue.Close() }()
+	ctx := context.Background()
+
+	now := time.Now()
+	soon, err := queue.EnqueueAt(ctx, now.Add(time.Minute), "user:remind", map[string]interface{}{"id": "1"}, "default")
+	if err != nil {
+		t.Fatalf("Failed to enqueue: %v", err)
+	}
+	if soon.RunAt.IsZero() {
+		t.Errorf("Expected the job's due time, got %+v", soon)
+	}
+	if _, err := queue.EnqueueAt(ctx, now.Add(time.Hour), "user:remind", nil, "default"); err != nil {
+		t.Fatalf("Failed to enqueue: %v", err)
+	}
+	if _, err := queue.EnqueueAt(ctx, now.Add(-time.Second), "user:remind", nil, "default"); err != nil {
+		t.Fatalf("Failed to enqueue: %v", err)
+	}
+
+	pending := func() int {
+		queues, err := queue.Queues(ctx)
+		if err != nil {
+			t.Fatalf("Failed to list queues: %v", err)
+		}
+		return queues[0].Pending
+	}
+	if got := pending(); got != 1 {
+		t.Errorf("Expected only the job due already to be queued, got %d", got)
+	}
+
+	// Nothing is due yet
+	if promoted, err := queue.promote(ctx, now, 10); err != nil || promoted != 0 {
+		t.Errorf("Expected nothing to promote, got %d (%v)", promoted, err)
+	}
+
+	promoted, err := queue.promote(ctx, now.Add(2*time.Minute), 10)
+	if err != nil || promoted != 1 {
+		t.Fatalf("Expected the due job to be promoted, got %d (%v)", promoted, err)
+	}
+	if got := pending(); got != 2 {
+		t.Errorf("Expected the promoted job in its queue, got %d pending", got)
+	}
+	fake.mu.Lock()
+	delayed := len(fake.zsets[delayedKey])
+	fake.mu.Unlock()
+	if delayed != 1 {
+		t.Errorf("Expected the later job to stay delayed, got %d", delayed)
+	}
+
+	// Promoted jobs aren't promoted again
+	if promoted, _ := queue.promote(ctx, now.Add(2*time.Minute), 10); promoted != 0 {
+		t.Errorf("Expected nothing more to promote, got %d", promoted)
+	}
+}
+
+func TestQueue_WrongPassword(t *testing.T) {
+	_, redisCfg := newFakeRedis(t, "secret")
+	redisCfg.Password = "wrong"
+	queue := NewQueue(redisCfg, []string{"default"})
+	defer func() { _ = queue.Close() }()
+
+	_, err := queue.Enqueue(context.Background(), "user:welcome", nil, "default")
+	var replyErr redisError
+	if !errors.As(err, &replyErr) {
+		t.Errorf("Expected the server's error, got %v", err)
+	}
+}
+
+func TestWorker(t *testing.T) {
+	_, redisCfg := newFakeRedis(t, "")
+	tasksCfg := config.TasksConfig{Enabled: true, TaskProcessors: 1, Queues: []string{"high", "default"}, Timeout: 100 * time.Millisecond}
+	a := newTestAPI(t, redisCfg, tasksCfg)
+
+	var reportsMu sync.Mutex
+	reports := make(map[string]api.ErrorReport)
+	a.RegisterErrorReporter(func(_ context.Context, report api.ErrorReport) {
+		reportsMu.Lock()
+		defer reportsMu.Unlock()
+		reports[report.Action] = report
+	})
+	outcomes := make(map[string]bool)
+	for _, name := range []string{api.EventTaskComplete, api.EventTaskError} {
+		a.On(name, func(_ context.Context, event api.Event) {
+			reportsMu.Lock()
+			defer reportsMu.Unlock()
+			outcomes[event.Action+"/"+event.Name] = true
+		})
+	}
+
+	ran := make(chan string, 10)
+	task := &api.TaskConfig{Queue: "default"}
+	for _, action := range []api.Action{
+		api.NewAction("test:task").Task(task).Handler(func(_ context.Context, params interface{}, _ *api.Connection) (interface{}, error) {
+			ran <- params.(map[string]interface{})["name"].(string)
+			return nil, nil
+		}),
+		api.NewAction("test:failing").Task(task).Handler(func(context.Context, interface{}, *api.Connection) (interface{}, error) {
+			return nil, errors.New("task failed")
+		}),
+		api.NewAction("test:slow").Task(task).Handler(func(ctx context.Context, _ interface{}, _ *api.Connection) (interface{}, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}),
+		api.NewAction("test:notask").Handler(func(context.Context, interface{}, *api.Connection) (interface{}, error) {
+			ran <- "notask"
+			return nil, nil
+		}),
+	} {
+		if err := a.RegisterAction(action); err != nil {
+			t.Fatalf("Failed to register action: %v", err)
+		}
+	}
+
+	queue := NewQueue(redisCfg, tasksCfg.Queues)
+	defer func() { _ = queue.Close() }()
+	ctx := context.Background()
+	for _, enqueue := range []struct{ action, name, queue string }{
+		{"test:task", "first", "default"},
+		{"test:failing", "", "default"},
+		{"test:slow", "", "default"},
+		{"test:notask", "", "default"},
+		{"test:task", "urgent", "high"},
+	} {
+		if _, err := queue.Enqueue(ctx, enqueue.action, map[string]interface{}{"name": enqueue.name}, enqueue.queue); err != nil {
+			t.Fatalf("Failed to enqueue: %v", err)
+		}
+	}
+
+	worker := NewWorker(a, queue, tasksCfg)
+	worker.Start()
+
+	var names []string
+	for len(names) < 2 {
+		select {
+		case name := <-ran:
+			names = append(names, name)
+		case <-time.After(3 * time.Second):
+			t.Fatalf("Expected both tasks to run, got %v", names)
+		}
+	}
+	if names[0] != "urgent" {
+		t.Errorf("Expected the high queue to run first, got %v", names)
+	}
+
+	var failed []api.FailedTask
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		failed, _ = queue.FailedTasks(ctx, 10)
+		if len(failed) == 3 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	worker.Stop()
+
+	errorsByTask := make(map[string]string)
+	for _, task := range failed {
+		errorsByTask[task.Task] = task.Error
+	}
+	if !strings.Contains(errorsByTask["test:failing"], "task failed") {
+		t.Errorf("Expected the failing task's error, got %q", errorsByTask["test:failing"])
+	}
+	if !strings.Contains(errorsByTask["test:slow"], string(util.ErrorTypeConnectionActionTimeout)) {
+		t.Errorf("Expected the slow task to time out, got %q", errorsByTask["test:slow"])
+	}
+	if !strings.Contains(errorsByTask["test:notask"], "is not a task") {
+		t.Errorf("Expected actions without ActionTask to be refused, got %q", errorsByTask["test:notask"])
+	}
+	select {
+	case name := <-ran:
+		t.Errorf("Expected no other action to run, got %s", name)
+	default:
+	}
+
+	reportsMu.Lock()
+	defer reportsMu.Unlock()
+	for _, action := range []string{"test:failing", "test:slow", "test:notask"} {
+		report, ok := reports[action]
+		if !ok {
+			t.Errorf("Expected %s's failure to be reported", action)
+			continue
+		}
+		if report.Source != api.ErrorSourceTask || report.Connection != nil {
+			t.Errorf("Expected %s to be reported as a task error without a connection, got source %q, connection %v", action, report.Source, report.Connection)
+		}
+		if report.RequestID == "" || report.Params == nil {
+			t.Errorf("Expected %s's report to carry the job's ID and params, got %+v", action, report)
+		}
+	}
+	for _, outcome := range []string{"test:task/task:complete", "test:failing/task:error", "test:slow/task:error", "test:notask/task:error"} {
+		if !outcomes[outcome] {
+			t.Errorf("Expected a %s event, got %v", outcome, outcomes)
+		}
+	}
+	if len(reports) != 3 {
+		t.Errorf("Expected only the three failures to be reported, got %d", len(reports))
+	}
+	if report := reports["test:slow"]; report.Type != util.ErrorTypeConnectionActionTimeout {
+		t.Errorf("Expected the timeout to be reported as %s, got %s", util.ErrorTypeConnectionActionTimeout, report.Type)
+	}
+}
+
+func TestWorker_Delayed(t *testing.T) {
+	_, redisCfg := newFakeRedis(t, "")
+	tasksCfg := config.TasksConfig{Enabled: true, TaskProcessors: 1, Queues: []string{"default"}, Timeout: time.Second}
+	a := newTestAPI(t, redisCfg, tasksCfg)
+
+	ran := make(chan time.Time, 1)
+	if err := a.RegisterAction(api.NewAction("test:task").Task(&api.TaskConfig{Queue: "default"}).Handler(
+		func(context.Context, interface{}, *api.Connection) (interface{}, error) {
+			ran <- time.Now()
+			return nil, nil
+		})); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	queue := NewQueue(redisCfg, tasksCfg.Queues)
+	defer func() { _ = queue.Close() }()
+	a.Tasks.SetBackend(queue)
+	worker := NewWorker(a, queue, tasksCfg)
+	worker.Start()
+	defer worker.Stop()
+
+	due := time.Now().Add(500 * time.Millisecond)
+	if _, err := a.EnqueueAt(context.Background(), due, "test:task", nil, ""); err != nil {
+		t.Fatalf("Failed to enqueue: %v", err)
+	}
+	select {
+	case at := <-ran:
+		if at.Before(due) {
+			t.Errorf("Expected the task to run once due (%v), ran at %v", due, at)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("Expected the worker to promote and run the delayed task")
+	}
+}
+
+func TestWorker_Processing(t *testing.T) {
+	fake, redisCfg := newFakeRedis(t, "")
+	tasksCfg := config.TasksConfig{Enabled: true, TaskProcessors: 1, Queues: []string{"default"}, Timeout: time.Second}
+	a := newTestAPI(t, redisCfg, tasksCfg)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	if err := a.RegisterAction(api.NewAction("test:task").Task(&api.TaskConfig{Queue: "default"}).Handler(
+		func(context.Context, interface{}, *api.Connection) (interface{}, error) {
+			close(started)
+			<-release
+			return nil, nil
+		})); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	queue := NewQueue(redisCfg, tasksCfg.Queues)
+	defer func() { _ = queue.Close() }()
+	if _, err := queue.Enqueue(context.Background(), "test:task", nil, "default"); err != nil {
+		t.Fatalf("Failed to enqueue: %v", err)
+	}
+	fake.mu.Lock()
+	fake.lists[queueKey("default")] = append([]string{"not a job"}, fake.lists[queueKey("default")]...)
+	fake.mu.Unlock()
+
+	processing := func() []string {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		return append([]string(nil), fake.lists[processingKey]...)
+	}
+
+	worker := NewWorker(a, queue, tasksCfg)
+	worker.Start()
+	defer worker.Stop()
+
+	select {
+	case <-started:
+	case <-time.After(3 * time.Second):
+		t.Fatal("Expected the worker to run the task")
+	}
+	if jobs := processing(); len(jobs) != 1 || !strings.Contains(jobs[0], "test:task") {
+		t.Errorf("Expected the running job on the processing list, got %v", jobs)
+	}
+	close(release)
+
+	deadline := time.Now().Add(3 * time.Second)
+	for len(processing()) > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	fake.mu.Lock()
+	pending := len(fake.lists[queueKey("default")])
+	claimed := len(fake.zsets[claimedKey])
+	fake.mu.Unlock()
+	if jobs := processing(); len(jobs) != 0 || pending != 0 || claimed != 0 {
+		t.Errorf("Expected finished and malformed jobs to be removed, got %v processing, %d pending, and %d claimed", jobs, pending, claimed)
+	}
+}
+
+func TestWorker_StuckJobs(t *testing.T) {
+	for _, retry := range []bool{true, false} {
+		t.Run(fmt.Sprintf("retry=%v", retry), func(t *testing.T) {
+			fake, redisCfg := newFakeRedis(t, "")
+			tasksCfg := config.TasksConfig{
+				Enabled:            true,
+				TaskProcessors:     1,
+				Queues:             []string{"default"},
+				Timeout:            time.Second,
+				StuckWorkerTimeout: 100 * time.Millisecond,
+				RetryStuckJobs:     retry,
+			}
+			a := newTestAPI(t, redisCfg, tasksCfg)
+			var reportsMu sync.Mutex
+			var reports []api.ErrorReport
+			a.RegisterErrorReporter(func(_ context.Context, report api.ErrorReport) {
+				reportsMu.Lock()
+				defer reportsMu.Unlock()
+				reports = append(reports, report)
+			})
+
+			ran := make(chan struct{}, 1)
+			if err := a.RegisterAction(api.NewAction("test:task").Task(&api.TaskConfig{Queue: "default"}).Handler(
+				func(context.Context, interface{}, *api.Connection) (interface{}, error) {
+					ran <- struct{}{}
+					return nil, nil
+				})); err != nil {
+				t.Fatalf("Failed to register action: %v", err)
+			}
+
+			queue := NewQueue(redisCfg, tasksCfg.Queues)
+			defer func() { _ = queue.Close() }()
+			job, err := queue.Enqueue(context.Background(), "test:task", nil, "default")
+			if err != nil {
+				t.Fatalf("Failed to enqueue: %v", err)
+			}
+
+			// A worker claims the job, then dies before running it
+			dead := NewWorker(a, queue, tasksCfg)
+			conn, err := queue.client.dial(context.Background())
+			if err != nil {
+				t.Fatalf("Failed to connect: %v", err)
+			}
+			if claimed, _, err := dead.poll(conn); err != nil || claimed == nil || claimed.ID != job.ID {
+				t.Fatalf("Expected the dead worker to claim the job, got %v, %v", claimed, err)
+			}
+			_ = conn.close()
+
+			worker := NewWorker(a, queue, tasksCfg)
+			worker.Start()
+			defer worker.Stop()
+
+			state := func() (processing, claimed, pending int) {
+				fake.mu.Lock()
+				defer fake.mu.Unlock()
+				return len(fake.lists[processingKey]), len(fake.zsets[claimedKey]), len(fake.lists[queueKey("default")])
+			}
+			if retry {
+				select {
+				case <-ran:
+				case <-time.After(5 * time.Second):
+					t.Fatal("Expected the stuck job to be queued and run again")
+				}
+			} else {
+				deadline := time.Now().Add(5 * time.Second)
+				var failed []api.FailedTask
+				for len(failed) == 0 && time.Now().Before(deadline) {
+					time.Sleep(20 * time.Millisecond)
+					failed, _ = queue.FailedTasks(context.Background(), 10)
+				}
+				if len(failed) != 1 || failed[0].ID != job.ID || !strings.Contains(failed[0].Error, "stuck") {
+					t.Fatalf("Expected the stuck job to be failed, got %+v", failed)
+				}
+				select {
+				case <-ran:
+					t.Error("Expected the failed job not to run")
+				default:
+				}
+				reportsMu.Lock()
+				if len(reports) != 1 || reports[0].Source != api.ErrorSourceTask || reports[0].Action != "test:task" {
+					t.Errorf("Expected the stuck job to be reported as a task failure, got %+v", reports)
+				}
+				reportsMu.Unlock()
+			}
+
+			deadline := time.Now().Add(3 * time.Second)
+			for time.Now().Before(deadline) {
+				if processing, claimed, pending := state(); processing+claimed+pending == 0 {
+					return
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+			processing, claimed, pending := state()
+			t.Errorf("Expected the job to be taken off the processing list, got %d processing, %d claimed, and %d pending", processing, claimed, pending)
+		})
+	}
+}
+
+func TestWorker_RedisDown(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	_ = listener.Close() // Nothing listens on the port now
+
+	tasksCfg := config.TasksConfig{Enabled: true, TaskProcessors: 1, Queues: []string{"default"}, Timeout: time.Second}
+	redisCfg := config.RedisConfig{Host: "127.0.0.1", Port: port}
+	a := newTestAPI(t, redisCfg, tasksCfg)
+	queue := NewQueue(redisCfg, tasksCfg.Queues)
+
+	worker := NewWorker(a, queue, tasksCfg)
+	worker.Start()
+	time.Sleep(50 * time.Millisecond)
+
+	stopped := make(chan struct{})
+	go func() {
+		worker.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the worker to stop while redis is down")
+	}
+}
+
+func TestInitializer(t *testing.T) {
+	_, redisCfg := newFakeRedis(t, "")
+
+	disabled := newTestAPI(t, redisCfg, config.TasksConfig{Enabled: false})
+	initializer := NewInitializer()
+	if err := initializer.Initialize(disabled); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	if _, ok := api.Lookup[api.TaskQueues](disabled); ok {
+		t.Error("Expected no task queues while tasks are disabled")
+	}
+	if _, err := disabled.Tasks.Enqueue(context.Background(), "test:task", nil, ""); err == nil {
+		t.Error("Expected enqueuing to fail while tasks are disabled")
+	}
+
+	tasksCfg := config.TasksConfig{Enabled: true, TaskProcessors: 1, Queues: []string{"default"}, Timeout: time.Second}
+	a := newTestAPI(t, redisCfg, tasksCfg)
+	ran := make(chan interface{}, 1)
+	if err := a.RegisterAction(api.NewAction("test:task").Task(&api.TaskConfig{Queue: "default"}).Handler(
+		func(_ context.Context, params interface{}, _ *api.Connection) (interface{}, error) {
+			ran <- params
+			return nil, nil
+		})); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	a.RegisterInitializer(NewInitializer())
+	if err := a.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	if err := a.Start(); err != nil {
+		t.Fatalf("Failed to start: %v", err)
+	}
+	defer func() { _ = a.Stop() }()
+
+	if _, ok := api.Lookup[api.TaskQueues](a); !ok {
+		t.Fatal("Expected the task queues to be provided")
+	}
+	params := struct {
+		UserID int `json:"userId"`
+	}{UserID: 7}
+	task, err := a.Tasks.Enqueue(context.Background(), "test:task", params, "")
+	if err != nil {
+		t.Fatalf("Failed to enqueue: %v", err)
+	}
+	if task.Queue != "default" || task.Params["userId"] != 7.0 {
+		t.Errorf("Expected the task on the default queue with its params, got %+v", task)
+	}
+	select {
+	case got := <-ran:
+		if got.(map[string]interface{})["userId"] != 7.0 {
+			t.Errorf("Expected the task to run with its params, got %v", got)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("Expected the started worker to run the task")
+	}
+}
@@ -0,0 +1,339 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// pollInterval is how long a processor waits to check the queues again when
+// they are empty
+const pollInterval = 500 * time.Millisecond
+
+// How often due delayed jobs are promoted to their queues, and how many are
+// moved at a time
+const (
+	promoteInterval = time.Second
+	promoteBatch    = 100
+)
+
+// Bounds of the wait between attempts to reach Redis while it is down
+const (
+	minReconnectBackoff = 500 * time.Millisecond
+	maxReconnectBackoff = 30 * time.Second
+)
+
+// Worker runs queued jobs with TaskProcessors processors. Each takes the next
+// job from the first of its queues that has one, so earlier queues are
+// drained first, and runs the job's action within the task timeout. Jobs
+// stay on the processing list while they run. While it runs, the worker also
+// promotes delayed jobs to their queues when due, and takes back the jobs
+// that have been on the processing list for longer than StuckWorkerTimeout,
+// left there by workers that died mid-run: they are queued again with
+// RetryStuckJobs, and failed otherwise.
+type Worker struct {
+	api          *api.API
+	queue        *Queue
+	queues       []string
+	processors   int
+	timeout      time.Duration
+	stuckTimeout time.Duration // 0 = jobs are never considered stuck
+	retryStuck   bool
+	logger       *util.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewWorker creates a worker of queue's jobs, configured by cfg
+func NewWorker(a *api.API, queue *Queue, cfg config.TasksConfig) *Worker {
+	return &Worker{
+		api:          a,
+		queue:        queue,
+		queues:       cfg.Queues,
+		processors:   cfg.TaskProcessors,
+		timeout:      cfg.Timeout,
+		stuckTimeout: cfg.StuckWorkerTimeout,
+		retryStuck:   cfg.RetryStuckJobs,
+		logger:       a.Logger,
+	}
+}
+
+// Start starts the processors, and the promotion of delayed and stuck jobs
+func (w *Worker) Start() {
+	if w.cancel != nil {
+		return // Already running
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	for i := 0; i < w.processors; i++ {
+		w.wg.Add(1)
+		go w.process(ctx, i)
+	}
+	w.wg.Add(1)
+	go w.promote(ctx)
+}
+
+// Stop stops taking jobs, and waits for the jobs being run to finish
+func (w *Worker) Stop() {
+	if w.cancel == nil {
+		return
+	}
+	w.cancel()
+	w.wg.Wait()
+	w.cancel = nil
+}
+
+// process takes and runs jobs until ctx is done. It has its own connection,
+// so processors don't compete for the client's idle ones, and reconnects
+// with a backoff while Redis is down.
+func (w *Worker) process(ctx context.Context, id int) {
+	defer w.wg.Done()
+
+	var conn *redisConn
+	var stopClosing func() bool
+	disconnect := func() {
+		if conn != nil {
+			stopClosing()
+			_ = conn.close()
+			conn = nil
+		}
+	}
+	defer disconnect()
+
+	backoff := minReconnectBackoff
+	down := false
+	for ctx.Err() == nil {
+		var err error
+		if conn == nil {
+			if conn, err = w.queue.client.dial(ctx); err == nil {
+				// Closing the connection on Stop interrupts a poll
+				c := conn
+				stopClosing = context.AfterFunc(ctx, func() { _ = c.close() })
+			}
+		}
+
+		var job *Job
+		var data string
+		if err == nil {
+			job, data, err = w.poll(conn)
+		}
+		if err != nil {
+			disconnect()
+			if ctx.Err() != nil {
+				return
+			}
+			if !down {
+				w.logger.Warnf("Task processor %d can't reach redis, retrying: %v", id, err)
+				down = true
+			}
+			sleep(ctx, backoff)
+			backoff = min(2*backoff, maxReconnectBackoff)
+			continue
+		}
+
+		if down {
+			w.logger.Infof("Task processor %d reconnected to redis", id)
+			down = false
+			backoff = minReconnectBackoff
+		}
+		if data == "" {
+			sleep(ctx, pollInterval)
+			continue
+		}
+		if job != nil {
+			w.run(job)
+		}
+		w.ack(data)
+	}
+}
+
+// promote moves due delayed jobs to their queues, and takes back stuck jobs,
+// every promoteInterval until ctx is done. Failures are retried on the next
+// tick; the processors report Redis being down.
+func (w *Worker) promote(ctx context.Context) {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(promoteInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for ctx.Err() == nil {
+			promoted, err := w.queue.promote(ctx, time.Now(), promoteBatch)
+			if err != nil {
+				w.logger.Debugf("Failed to promote delayed tasks: %v", err)
+				break
+			}
+			if promoted > 0 {
+				w.logger.Debugf("Promoted %d delayed tasks", promoted)
+			}
+			if promoted < promoteBatch {
+				break
+			}
+		}
+		if w.stuckTimeout > 0 {
+			w.recoverStuck(ctx)
+		}
+	}
+}
+
+// recoverStuck takes back the jobs claimed more than stuckTimeout ago, whose
+// processors must have died mid-run (see Queue.recover). Jobs failed this way
+// are passed to the error reporters, as other task failures are.
+func (w *Worker) recoverStuck(ctx context.Context) {
+	cutoff := time.Now().Add(-w.stuckTimeout)
+	stuck, err := w.queue.stuck(ctx, cutoff, promoteBatch)
+	if err != nil {
+		w.logger.Debugf("Failed to look for stuck tasks: %v", err)
+		return
+	}
+
+	for _, data := range stuck {
+		var job Job
+		if err := json.Unmarshal([]byte(data), &job); err != nil {
+			w.logger.Errorf("Dropping a malformed stuck job: %v", err)
+			w.ack(data)
+			continue
+		}
+
+		cause := fmt.Errorf("task was stuck: its worker didn't finish it within %s", w.stuckTimeout)
+		recovered, err := w.queue.recover(ctx, data, cutoff, &job, w.retryStuck, cause)
+		if err != nil {
+			w.logger.Errorf("Failed to take back stuck task %s: %v", job.ID, err)
+			continue
+		}
+		if !recovered {
+			continue // It finished, or was taken back by another worker
+		}
+		if w.retryStuck {
+			w.logger.Warnf("Task %s (%s) was stuck; queued it again", job.Action, job.ID)
+			continue
+		}
+		w.logger.Warnf("Task %s (%s) was stuck; moved it to the failed tasks", job.Action, job.ID)
+		w.api.ReportError(util.WithRequestID(context.WithoutCancel(ctx), job.ID), api.ErrorReport{
+			Error:  cause,
+			Source: api.ErrorSourceTask,
+			Action: job.Action,
+			Params: job.Params,
+		})
+	}
+}
+
+// poll takes the next job, moving it to the processing list and recording
+// when it was claimed (see claimScript). It returns the job and its data, as it is stored on the
+// processing list, or no data when the queues are empty. Malformed jobs are
+// returned as data only, so they are removed without running.
+func (w *Worker) poll(conn *redisConn) (*Job, string, error) {
+	keys := make([]string, 0, len(w.queues)+1)
+	for _, queue := range w.queues {
+		keys = append(keys, queueKey(queue))
+	}
+	keys = append(keys, processingKey, claimedKey)
+
+	reply, err := conn.do(w.queue.client.deadline(context.Background(), 0),
+		evalArgs(claimScript, keys, strconv.FormatInt(time.Now().UnixMilli(), 10))...)
+	if err != nil {
+		return nil, "", err
+	}
+	item, _ := reply.([]interface{}) // [key, job], or nil when the queues are empty
+	if len(item) != 2 {
+		return nil, "", nil
+	}
+
+	data, _ := item[1].(string)
+	var job Job
+	if err := json.Unmarshal([]byte(data), &job); err != nil {
+		w.logger.Errorf("Dropping a malformed job from %v: %v", item[0], err)
+		return nil, data, nil
+	}
+	return &job, data, nil
+}
+
+// ack removes a job the processor has finished with from the processing list
+func (w *Worker) ack(data string) {
+	ctx, cancel := context.WithTimeout(context.Background(), w.queue.client.timeout)
+	defer cancel()
+	if err := w.queue.ack(ctx, data); err != nil {
+		w.logger.Errorf("Failed to remove a finished task from the processing list: %v", err)
+	}
+}
+
+// run runs job. If it fails, the failure is passed to the error reporters
+// and recorded on the failed list. Jobs aren't canceled by Stop; they have
+// until the task timeout to finish.
+func (w *Worker) run(job *Job) {
+	ctx := util.WithRequestID(context.Background(), job.ID)
+	if w.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := w.execute(ctx, job)
+	event := api.Event{Name: api.EventTaskComplete, Action: job.Action, Params: job.Params, Error: err, Duration: time.Since(start)}
+	if err != nil {
+		event.Name = api.EventTaskError
+	}
+	w.api.Emit(context.WithoutCancel(ctx), event)
+	if err == nil {
+		return
+	}
+	w.logger.Warnf("Task %s (%s) failed: %v", job.Action, job.ID, err)
+	w.api.ReportError(context.WithoutCancel(ctx), api.ErrorReport{
+		Error:  err,
+		Source: api.ErrorSourceTask,
+		Action: job.Action,
+		Params: job.Params,
+	})
+
+	failCtx, cancel := context.WithTimeout(context.Background(), w.queue.client.timeout)
+	defer cancel()
+	if err := w.queue.fail(failCtx, job, err); err != nil {
+		w.logger.Errorf("Failed to record failed task %s: %v", job.ID, err)
+	}
+}
+
+// execute runs job's action on a task connection
+func (w *Worker) execute(ctx context.Context, job *Job) error {
+	descriptor, ok := w.api.GetActionDescriptor(job.Action)
+	if !ok {
+		return fmt.Errorf("action not found: %s", job.Action)
+	}
+	if descriptor.Task == nil {
+		return fmt.Errorf("action %s is not a task (it has no ActionTask)", job.Action)
+	}
+
+	conn := api.NewConnection(api.ConnectionTypeTask, job.Queue, job.ID, nil)
+	conn.SetClientInfo(api.ClientInfo{Protocol: "task"})
+	result := conn.Act(ctx, w.api, job.Action, job.Params, "TASK", "")
+	if result.Error != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return util.NewTypedError(util.ErrorTypeConnectionActionTimeout,
+			fmt.Sprintf("task timed out after %s", w.timeout),
+			util.WithOriginalError(result.Error))
+	}
+	return result.Error
+}
+
+// sleep waits for d, or until ctx is done
+func sleep(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
@@ -0,0 +1,170 @@
+// Package uptime checks URLs periodically (the API itself, and the services
+// it depends on), so deployments can monitor themselves without external
+// tooling
+package uptime
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+// Checker requests each of its URLs every interval, and keeps the latest
+// result of each. A URL is up when it responds with a status below 400.
+type Checker struct {
+	urls     []string
+	interval time.Duration
+	client   *http.Client
+	logger   *util.Logger
+
+	mu      sync.Mutex
+	results map[string]*api.UptimeCheck
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewChecker creates a checker of urls. Each check is limited to timeout.
+// logger may be nil.
+func NewChecker(urls []string, interval, timeout time.Duration, logger *util.Logger) *Checker {
+	results := make(map[string]*api.UptimeCheck, len(urls))
+	for _, url := range urls {
+		results[url] = &api.UptimeCheck{URL: url}
+	}
+	return &Checker{
+		urls:     urls,
+		interval: interval,
+		client:   &http.Client{Timeout: timeout},
+		logger:   logger,
+		results:  results,
+	}
+}
+
+// Start checks the URLs now, then every interval until Stop
+func (c *Checker) Start() {
+	if c.cancel != nil {
+		return // Already checking
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.done = make(chan struct{})
+
+	go func() {
+		defer close(c.done)
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		for {
+			c.CheckAll(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops checking, canceling any checks in flight
+func (c *Checker) Stop() {
+	if c.cancel == nil {
+		return
+	}
+	c.cancel()
+	<-c.done
+	c.cancel = nil
+}
+
+// CheckAll checks every URL once, concurrently
+func (c *Checker) CheckAll(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, url := range c.urls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.check(ctx, url)
+		}()
+	}
+	wg.Wait()
+}
+
+// UptimeChecks returns the latest result of each URL, by URL. It implements
+// api.UptimeChecker.
+func (c *Checker) UptimeChecks() []api.UptimeCheck {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	checks := make([]api.UptimeCheck, 0, len(c.results))
+	for _, result := range c.results {
+		checks = append(checks, *result)
+	}
+	sort.Slice(checks, func(i, j int) bool { return checks[i].URL < checks[j].URL })
+	return checks
+}
+
+// check requests url, and records the result
+func (c *Checker) check(ctx context.Context, url string) {
+	start := time.Now()
+	status, err := c.request(ctx, url)
+	if ctx.Err() != nil {
+		return // Stopped mid-check; it says nothing about the URL
+	}
+	latency := time.Since(start)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := c.results[url]
+	wasUp, checked := result.Up, result.Checks > 0
+	result.Checks++
+	result.Status = status
+	result.LatencyMs = latency.Milliseconds()
+	result.CheckedAt = start.Unix()
+	result.Up = err == nil
+	if err != nil {
+		result.Error = err.Error()
+		result.ConsecutiveFailures++
+		result.Failures++
+	} else {
+		result.Error = ""
+		result.ConsecutiveFailures = 0
+		result.LastUpAt = start.Unix()
+	}
+
+	if c.logger == nil {
+		return
+	}
+	switch {
+	case err != nil && (wasUp || !checked):
+		c.logger.Warnf("Uptime check of %s failed: %v", url, err)
+	case err == nil && !wasUp && checked:
+		c.logger.Infof("Uptime check of %s recovered after %d failures", url, result.Failures)
+	}
+}
+
+// request GETs url, returning its status, and an error when it isn't up
+func (c *Checker) request(ctx context.Context, url string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", "actionhero-uptime")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10)) // Lets the connection be reused
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return resp.StatusCode, fmt.Errorf("responded %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
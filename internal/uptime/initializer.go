@@ -0,0 +1,69 @@
+package uptime
+
+import (
+	"context"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+)
+
+// Initializer checks the URLs of uptime.urls while the API runs, when
+// uptime.enabled is set. Their results are shown by the status action and
+// metrics (see API.UptimeChecks).
+type Initializer struct {
+	checker    *Checker
+	subscribed bool // The start handler is registered once, across restarts
+}
+
+// NewInitializer creates the uptime initializer
+func NewInitializer() *Initializer {
+	return &Initializer{}
+}
+
+// Name returns the initializer name
+func (i *Initializer) Name() string {
+	return "uptime"
+}
+
+// Priority returns the initialization priority
+func (i *Initializer) Priority() int {
+	return 100
+}
+
+// Initialize creates the checker, and provides it as the API's
+// api.UptimeChecker
+func (i *Initializer) Initialize(a *api.API) error {
+	cfg := a.Config.Uptime
+	if !cfg.Enabled {
+		i.checker = nil
+		return nil
+	}
+
+	i.checker = NewChecker(cfg.CheckURLs(), cfg.Interval, cfg.Timeout, a.Logger)
+	api.Provide[api.UptimeChecker](a, i.checker)
+
+	// Check once the servers are up too, so the API can check itself
+	if !i.subscribed {
+		a.On(api.EventStart, func(_ context.Context, _ api.Event) {
+			if i.checker != nil {
+				i.checker.Start()
+			}
+		})
+		i.subscribed = true
+	}
+
+	a.Logger.Infof("Uptime checks enabled: %d URLs every %s", len(i.checker.urls), cfg.Interval)
+	return nil
+}
+
+// Start does nothing; checks start once the servers have started too
+func (i *Initializer) Start(_ *api.API) error {
+	return nil
+}
+
+// Stop stops checking the URLs
+func (i *Initializer) Stop(_ *api.API) error {
+	if i.checker != nil {
+		i.checker.Stop()
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package uptime
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/api"
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/evantahler/go-actionhero/internal/util"
+)
+
+func TestChecker_CheckAll(t *testing.T) {
+	var healthy atomic.Bool
+	healthy.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	up, slow := server.URL+"/health", server.URL+"/slow"
+	checker := NewChecker([]string{up, slow}, time.Minute, 50*time.Millisecond, nil)
+
+	checks := checker.UptimeChecks()
+	if len(checks) != 2 || checks[0].URL != up || checks[0].CheckedAt != 0 {
+		t.Fatalf("Expected 2 unchecked URLs, got %+v", checks)
+	}
+
+	checker.CheckAll(context.Background())
+	checks = checker.UptimeChecks()
+	if !checks[0].Up || checks[0].Status != http.StatusOK || checks[0].LastUpAt == 0 {
+		t.Errorf("Expected %s up, got %+v", up, checks[0])
+	}
+	if checks[1].Up || checks[1].Error == "" || checks[1].ConsecutiveFailures != 1 {
+		t.Errorf("Expected %s to time out, got %+v", slow, checks[1])
+	}
+
+	healthy.Store(false)
+	checker.CheckAll(context.Background())
+	check := checker.UptimeChecks()[0]
+	if check.Up || check.Status != http.StatusServiceUnavailable || check.Checks != 2 || check.Failures != 1 {
+		t.Errorf("Expected %s down with a 503, got %+v", up, check)
+	}
+
+	healthy.Store(true)
+	checker.CheckAll(context.Background())
+	check = checker.UptimeChecks()[0]
+	if !check.Up || check.ConsecutiveFailures != 0 || check.Error != "" {
+		t.Errorf("Expected %s to recover, got %+v", up, check)
+	}
+}
+
+func TestChecker_StartStop(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+	}))
+	defer server.Close()
+
+	checker := NewChecker([]string{server.URL}, 20*time.Millisecond, 10*time.Millisecond, nil)
+	checker.Start()
+	checker.Start() // Already checking
+	time.Sleep(70 * time.Millisecond)
+	checker.Stop()
+	checker.Stop()
+
+	made := requests.Load()
+	if made < 2 {
+		t.Errorf("Expected checks every interval, got %d", made)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if requests.Load() != made {
+		t.Error("Expected no checks after Stop")
+	}
+}
+
+func TestInitializer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+	defer server.Close()
+
+	cfg := &config.Config{Uptime: config.DefaultUptimeConfig()}
+	a := api.New(cfg, util.NewLogger(config.LoggerConfig{Level: "error"}))
+	a.RegisterInitializer(NewInitializer())
+
+	if err := a.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	if checks := a.UptimeChecks(); len(checks) != 0 {
+		t.Errorf("Expected no checks while disabled, got %+v", checks)
+	}
+
+	cfg.Uptime.Enabled = true
+	cfg.Uptime.URLs = server.URL
+	a = api.New(cfg, util.NewLogger(config.LoggerConfig{Level: "error"}))
+	a.RegisterInitializer(NewInitializer())
+	if err := a.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	if err := a.Start(); err != nil {
+		t.Fatalf("Failed to start: %v", err)
+	}
+	defer func() { _ = a.Stop() }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		checks := a.UptimeChecks()
+		if len(checks) == 1 && checks[0].Up {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %s to be checked once started, got %+v", server.URL, checks)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,78 @@
+package util
+
+import (
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// AsyncWriter writes to another writer from a background goroutine, so
+// callers never wait on slow output. Up to a bounded number of writes are
+// buffered; writes that don't fit are dropped and counted.
+type AsyncWriter struct {
+	out     io.Writer
+	lines   chan []byte
+	done    chan struct{}
+	dropped atomic.Int64
+
+	mu     sync.RWMutex // Guards closed against writes racing Close
+	closed bool
+}
+
+// NewAsyncWriter starts writing to out, buffering up to size writes
+func NewAsyncWriter(out io.Writer, size int) *AsyncWriter {
+	w := &AsyncWriter{
+		out:   out,
+		lines: make(chan []byte, size),
+		done:  make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Write queues a copy of p. It never blocks: when the buffer is full, p is
+// dropped and still reported as written, so the logger doesn't fail.
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+
+	line := make([]byte, len(p))
+	copy(line, p)
+	select {
+	case w.lines <- line:
+	default:
+		w.dropped.Add(1)
+	}
+	return len(p), nil
+}
+
+// Dropped returns how many writes were dropped because the buffer was full
+func (w *AsyncWriter) Dropped() int64 {
+	return w.dropped.Load()
+}
+
+// Close flushes buffered writes and stops the background goroutine. It
+// doesn't close the underlying writer.
+func (w *AsyncWriter) Close() error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.lines)
+	}
+	w.mu.Unlock()
+
+	<-w.done
+	return nil
+}
+
+// run writes buffered lines until the writer is closed
+func (w *AsyncWriter) run() {
+	defer close(w.done)
+	for line := range w.lines {
+		_, _ = w.out.Write(line)
+	}
+}
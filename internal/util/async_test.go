@@ -0,0 +1,96 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+)
+
+// blockingWriter blocks every write until release is closed
+type blockingWriter struct {
+	release chan struct{}
+	mu      sync.Mutex
+	buf     bytes.Buffer
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.release
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func TestAsyncWriter_FlushOnClose(t *testing.T) {
+	out := &blockingWriter{release: make(chan struct{})}
+	close(out.release)
+	w := NewAsyncWriter(out, 100)
+
+	for _, line := range []string{"one\n", "two\n", "three\n"} {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatalf("Failed to write: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close: %v", err)
+	}
+
+	if out.buf.String() != "one\ntwo\nthree\n" {
+		t.Errorf("Expected every write to be flushed in order, got %q", out.buf.String())
+	}
+	if _, err := w.Write([]byte("late\n")); err == nil {
+		t.Error("Expected an error writing after Close")
+	}
+}
+
+func TestAsyncWriter_DropsWhenFull(t *testing.T) {
+	out := &blockingWriter{release: make(chan struct{})}
+	w := NewAsyncWriter(out, 2)
+
+	// The first write may already be taken by the background goroutine,
+	// which blocks on it, so at most 3 of the 10 are kept
+	for i := 0; i < 10; i++ {
+		if n, err := w.Write([]byte("x\n")); err != nil || n != 2 {
+			t.Fatalf("Expected dropped writes to succeed, got %d, %v", n, err)
+		}
+	}
+	if dropped := w.Dropped(); dropped < 7 {
+		t.Errorf("Expected at least 7 dropped writes, got %d", dropped)
+	}
+
+	close(out.release)
+	_ = w.Close()
+	if kept := strings.Count(out.buf.String(), "x"); int64(kept)+w.Dropped() != 10 {
+		t.Errorf("Expected kept and dropped writes to add up to 10, got %d kept and %d dropped", kept, w.Dropped())
+	}
+}
+
+func TestLogger_AsyncBuffer(t *testing.T) {
+	cfg := config.DefaultLoggerConfig()
+	cfg.File = filepath.Join(t.TempDir(), "actionhero.log")
+	cfg.Stdout = false
+	cfg.AsyncBuffer = 100
+	logger := NewLogger(cfg)
+
+	logger.Info("buffered")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Failed to close logger: %v", err)
+	}
+
+	content, err := os.ReadFile(cfg.File)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal(content, &entry); err != nil || entry["msg"] != "buffered" {
+		t.Errorf("Expected the entry to be flushed on Close, got %q", content)
+	}
+	if logger.Dropped() != 0 {
+		t.Errorf("Expected no dropped entries, got %d", logger.Dropped())
+	}
+}
@@ -0,0 +1,47 @@
+package util
+
+import (
+	"context"
+	"net/http"
+)
+
+// requestIDKey is the context key for the request/correlation ID
+type requestIDKey struct{}
+
+// requestHeadersKey is the context key for the headers of the request being served
+type requestHeadersKey struct{}
+
+// RequestIDField is the log field name used for the request ID
+const RequestIDField = "requestId"
+
+// WithRequestID returns a copy of ctx carrying the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored in ctx, or "" if there is none
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if requestID, ok := ctx.Value(requestIDKey{}).(string); ok {
+		return requestID
+	}
+	return ""
+}
+
+// WithRequestHeaders returns a copy of ctx carrying the request's headers
+func WithRequestHeaders(ctx context.Context, headers http.Header) context.Context {
+	return context.WithValue(ctx, requestHeadersKey{}, headers)
+}
+
+// RequestHeadersFromContext returns the request headers stored in ctx, or nil if there are none
+func RequestHeadersFromContext(ctx context.Context) http.Header {
+	if ctx == nil {
+		return nil
+	}
+	if headers, ok := ctx.Value(requestHeadersKey{}).(http.Header); ok {
+		return headers
+	}
+	return nil
+}
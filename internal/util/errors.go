@@ -2,9 +2,14 @@
 package util
 
 import (
+	"errors"
 	"fmt"
 	"runtime"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/config"
 )
 
 // ErrorType represents different types of errors in the system
@@ -16,6 +21,14 @@ const (
 	ErrorTypeConnectionActionNotFound ErrorType = "CONNECTION_ACTION_NOT_FOUND"
 	// ErrorTypeConnectionActionRun occurs when an action fails during execution
 	ErrorTypeConnectionActionRun ErrorType = "CONNECTION_ACTION_RUN"
+	// ErrorTypeConnectionActionTimeout occurs when an action runs past its timeout
+	ErrorTypeConnectionActionTimeout ErrorType = "CONNECTION_ACTION_TIMEOUT"
+	// ErrorTypeConnectionActionSaturated occurs when an action is at its
+	// concurrency limit and the request is shed
+	ErrorTypeConnectionActionSaturated ErrorType = "CONNECTION_ACTION_SATURATED"
+	// ErrorTypeConnectionActionResponseTooLarge occurs when an action's
+	// response is larger than its ActionMaxResponseSize
+	ErrorTypeConnectionActionResponseTooLarge ErrorType = "CONNECTION_ACTION_RESPONSE_TOO_LARGE"
 	// ErrorTypeConnectionActionParamRequired occurs when a required parameter is missing
 	ErrorTypeConnectionActionParamRequired ErrorType = "CONNECTION_ACTION_PARAM_REQUIRED"
 	// ErrorTypeConnectionActionParamValidation occurs when parameter validation fails
@@ -26,6 +39,12 @@ const (
 	ErrorTypeConnectionNotSubscribed ErrorType = "CONNECTION_NOT_SUBSCRIBED"
 	// ErrorTypeConnectionTypeNotFound occurs when a connection type is not recognized
 	ErrorTypeConnectionTypeNotFound ErrorType = "CONNECTION_TYPE_NOT_FOUND"
+	// ErrorTypeConnectionRateLimited occurs when a connection sends messages
+	// faster than its rate limit
+	ErrorTypeConnectionRateLimited ErrorType = "CONNECTION_RATE_LIMITED"
+	// ErrorTypeConnectionTenantRequired occurs when a request's tenant is
+	// missing or invalid while tenancy is required
+	ErrorTypeConnectionTenantRequired ErrorType = "CONNECTION_TENANT_REQUIRED"
 
 	// ErrorTypeServerInitialization occurs when server initialization fails
 	ErrorTypeServerInitialization ErrorType = "SERVER_INITIALIZATION"
@@ -36,6 +55,14 @@ const (
 
 	// ErrorTypeActionValidation occurs when action validation fails
 	ErrorTypeActionValidation ErrorType = "ACTION_VALIDATION"
+
+	// ErrorTypeDependencyUnavailable occurs when a call to a downstream
+	// dependency is refused because its circuit breaker is open
+	ErrorTypeDependencyUnavailable ErrorType = "DEPENDENCY_UNAVAILABLE"
+
+	// ErrorTypeTaskEnqueue occurs when an action can't be queued as a
+	// background task
+	ErrorTypeTaskEnqueue ErrorType = "TASK_ENQUEUE"
 )
 
 // TypedError represents an error with a specific type and optional metadata
@@ -46,6 +73,7 @@ type TypedError struct {
 	Value         interface{}
 	Stack         string
 	OriginalError error
+	RetryAfter    time.Duration // Suggested wait before retrying (0 = none)
 }
 
 // Error implements the error interface
@@ -56,8 +84,62 @@ func (e *TypedError) Error() string {
 	return fmt.Sprintf("%s: %s", e.Type, e.Message)
 }
 
+// Unwrap returns the original error, for errors.Is and errors.As
+func (e *TypedError) Unwrap() error {
+	return e.OriginalError
+}
+
+// ErrorTypeInfo describes how errors of a registered type are presented to clients
+type ErrorTypeInfo struct {
+	HTTPStatus int    // Status code for web responses
+	Message    string // Client-safe message sent instead of the error's own ("" = send the error's message)
+}
+
+var (
+	errorTypes   = make(map[ErrorType]ErrorTypeInfo)
+	errorTypesMu sync.RWMutex
+)
+
+// RegisterErrorType maps an error type to an HTTP status and, optionally, a
+// client-safe message. Applications register their own types at startup;
+// registering a built-in type overrides its default status.
+func RegisterErrorType(typ ErrorType, info ErrorTypeInfo) error {
+	if typ == "" {
+		return fmt.Errorf("error type is required")
+	}
+	if info.HTTPStatus < 400 || info.HTTPStatus > 599 {
+		return fmt.Errorf("error type %s: HTTP status must be between 400 and 599 (got %d)", typ, info.HTTPStatus)
+	}
+
+	errorTypesMu.Lock()
+	defer errorTypesMu.Unlock()
+	errorTypes[typ] = info
+	return nil
+}
+
+// LookupErrorType returns the registration of an error type
+func LookupErrorType(typ ErrorType) (ErrorTypeInfo, bool) {
+	errorTypesMu.RLock()
+	defer errorTypesMu.RUnlock()
+	info, ok := errorTypes[typ]
+	return info, ok
+}
+
+// ClientMessage returns the message to send to clients: the registered
+// client-safe message for this error type, or the error's own message
+func (e *TypedError) ClientMessage() string {
+	if info, ok := LookupErrorType(e.Type); ok && info.Message != "" {
+		return info.Message
+	}
+	return e.Message
+}
+
 // HTTPStatus returns the HTTP status code for this error type
 func (e *TypedError) HTTPStatus() int {
+	if info, ok := LookupErrorType(e.Type); ok {
+		return info.HTTPStatus
+	}
+
 	switch e.Type {
 	case ErrorTypeConnectionActionNotFound:
 		return 404 // Not Found
@@ -67,14 +149,20 @@ func (e *TypedError) HTTPStatus() int {
 		return 401 // Unauthorized
 	case ErrorTypeConnectionNotSubscribed:
 		return 400 // Bad Request
-	case ErrorTypeConnectionTypeNotFound:
+	case ErrorTypeConnectionTypeNotFound, ErrorTypeConnectionTenantRequired:
 		return 400 // Bad Request
+	case ErrorTypeConnectionRateLimited:
+		return 429 // Too Many Requests
 	case ErrorTypeServerInitialization, ErrorTypeServerStart, ErrorTypeServerStop:
 		return 503 // Service Unavailable
 	case ErrorTypeActionValidation:
 		return 400 // Bad Request
-	case ErrorTypeConnectionActionRun:
+	case ErrorTypeConnectionActionRun, ErrorTypeConnectionActionResponseTooLarge, ErrorTypeTaskEnqueue:
 		return 500 // Internal Server Error
+	case ErrorTypeConnectionActionTimeout:
+		return 504 // Gateway Timeout
+	case ErrorTypeConnectionActionSaturated, ErrorTypeDependencyUnavailable:
+		return 503 // Service Unavailable
 	default:
 		return 500 // Internal Server Error
 	}
@@ -85,6 +173,77 @@ func (e *TypedError) Code() string {
 	return string(e.Type)
 }
 
+// ErrorJSON is the JSON shape of errors sent to clients, shared by the web,
+// WebSocket, and CLI outputs
+type ErrorJSON struct {
+	Code          string      `json:"code"`
+	Message       string      `json:"message"`
+	Key           string      `json:"key,omitempty"`   // The param or field the error is about
+	Value         interface{} `json:"value,omitempty"` // The offending value
+	RequestID     string      `json:"requestId,omitempty"`
+	Details       interface{} `json:"details,omitempty"`       // e.g., schema validation errors
+	Stack         string      `json:"stack,omitempty"`         // Only in debug disclosure mode
+	OriginalError string      `json:"originalError,omitempty"` // Only in debug disclosure mode
+}
+
+// GenericErrorMessage replaces the message of server errors in production
+// disclosure mode
+const GenericErrorMessage = "An internal error occurred"
+
+// ToJSON returns the client-facing form of the error for a disclosure mode
+// (config.ErrorDisclosure*). Debug adds the stack and original error. Production
+// replaces the message, key, and value of server errors (5xx) with
+// GenericErrorMessage, unless their type has a registered client-safe message;
+// the request ID lets operators find the details in the logs.
+func (e *TypedError) ToJSON(requestID, disclosure string) ErrorJSON {
+	body := ErrorJSON{
+		Code:      e.Code(),
+		Message:   e.ClientMessage(),
+		Key:       e.Key,
+		Value:     e.Value,
+		RequestID: requestID,
+	}
+
+	switch disclosure {
+	case config.ErrorDisclosureDebug:
+		body.Stack = e.Stack
+		if e.OriginalError != nil {
+			body.OriginalError = e.OriginalError.Error()
+		}
+	case config.ErrorDisclosureProduction:
+		if info, ok := LookupErrorType(e.Type); e.HTTPStatus() >= 500 && (!ok || info.Message == "") {
+			body.Message = genericErrorMessage(requestID)
+			body.Key = ""
+			body.Value = nil
+		}
+	}
+	return body
+}
+
+// ErrorToJSON returns the client-facing form of any error for a disclosure
+// mode. Errors that aren't TypedErrors are reported as INTERNAL_ERROR.
+func ErrorToJSON(err error, requestID, disclosure string) ErrorJSON {
+	var typedErr *TypedError
+	if errors.As(err, &typedErr) {
+		return typedErr.ToJSON(requestID, disclosure)
+	}
+
+	message := err.Error()
+	if disclosure == config.ErrorDisclosureProduction {
+		message = genericErrorMessage(requestID)
+	}
+	return ErrorJSON{Code: "INTERNAL_ERROR", Message: message, RequestID: requestID}
+}
+
+// genericErrorMessage returns GenericErrorMessage with the request ID to quote
+// when reporting the error
+func genericErrorMessage(requestID string) string {
+	if requestID == "" {
+		return GenericErrorMessage
+	}
+	return fmt.Sprintf("%s (request ID: %s)", GenericErrorMessage, requestID)
+}
+
 // NewTypedError creates a new TypedError
 func NewTypedError(typ ErrorType, message string, opts ...TypedErrorOption) *TypedError {
 	err := &TypedError{
@@ -117,6 +276,13 @@ func WithValue(value interface{}) TypedErrorOption {
 	}
 }
 
+// WithRetryAfter sets how long the caller should wait before retrying
+func WithRetryAfter(d time.Duration) TypedErrorOption {
+	return func(e *TypedError) {
+		e.RetryAfter = d
+	}
+}
+
 // WithOriginalError sets the original error
 func WithOriginalError(err error) TypedErrorOption {
 	return func(e *TypedError) {
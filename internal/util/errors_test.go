@@ -1,9 +1,13 @@
 package util
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/config"
 )
 
 func TestTypedError_Error(t *testing.T) {
@@ -90,3 +94,151 @@ func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(substr) == 0 ||
 		strings.Contains(s, substr))
 }
+
+func TestRegisterErrorType(t *testing.T) {
+	const typ ErrorType = "PAYMENT_REQUIRED"
+	t.Cleanup(func() {
+		errorTypesMu.Lock()
+		delete(errorTypes, typ)
+		errorTypesMu.Unlock()
+	})
+
+	err := NewTypedError(typ, "card 4242 declined by issuer")
+	if err.HTTPStatus() != 500 {
+		t.Errorf("Expected unregistered type to be 500, got %d", err.HTTPStatus())
+	}
+
+	if regErr := RegisterErrorType(typ, ErrorTypeInfo{HTTPStatus: 402, Message: "Payment required"}); regErr != nil {
+		t.Fatalf("Failed to register error type: %v", regErr)
+	}
+	if err.HTTPStatus() != 402 {
+		t.Errorf("Expected registered status 402, got %d", err.HTTPStatus())
+	}
+	if err.ClientMessage() != "Payment required" {
+		t.Errorf("Expected client-safe message, got %q", err.ClientMessage())
+	}
+	if err.Message != "card 4242 declined by issuer" {
+		t.Errorf("Expected the error's own message to be kept, got %q", err.Message)
+	}
+
+	// Without a registered message, the error's own message is sent
+	plain := NewTypedError(ErrorTypeActionValidation, "name is too long")
+	if plain.ClientMessage() != "name is too long" {
+		t.Errorf("Expected the error's own message, got %q", plain.ClientMessage())
+	}
+
+	for _, info := range []ErrorTypeInfo{{HTTPStatus: 200}, {HTTPStatus: 600}} {
+		if regErr := RegisterErrorType(typ, info); regErr == nil {
+			t.Errorf("Expected an error for status %d", info.HTTPStatus)
+		}
+	}
+	if regErr := RegisterErrorType("", ErrorTypeInfo{HTTPStatus: 400}); regErr == nil {
+		t.Error("Expected an error for an empty type")
+	}
+}
+
+func TestTypedError_ToJSON(t *testing.T) {
+	err := NewTypedError(
+		ErrorTypeConnectionActionParamValidation,
+		"email is invalid",
+		WithKey("email"),
+		WithValue("not-an-email"),
+		WithOriginalError(errors.New("missing @")),
+	)
+
+	body := err.ToJSON("req-1", config.ErrorDisclosureStandard)
+	if body.Code != "CONNECTION_ACTION_PARAM_VALIDATION" || body.Message != "email is invalid" {
+		t.Errorf("Unexpected code or message: %+v", body)
+	}
+	if body.Key != "email" || body.Value != "not-an-email" || body.RequestID != "req-1" {
+		t.Errorf("Expected key, value, and request ID, got %+v", body)
+	}
+	if body.Stack != "" || body.OriginalError != "" {
+		t.Errorf("Expected no internal details in standard mode, got %+v", body)
+	}
+
+	debugBody := err.ToJSON("req-1", config.ErrorDisclosureDebug)
+	if debugBody.Stack == "" || debugBody.OriginalError != "missing @" {
+		t.Errorf("Expected stack and original error in debug mode, got %+v", debugBody)
+	}
+
+	// The JSON shape is stable: empty optional fields are omitted
+	data, marshalErr := json.Marshal(NewTypedError(ErrorTypeConnectionActionRun, "boom").ToJSON("", config.ErrorDisclosureStandard))
+	if marshalErr != nil {
+		t.Fatalf("Failed to marshal: %v", marshalErr)
+	}
+	if string(data) != `{"code":"CONNECTION_ACTION_RUN","message":"boom"}` {
+		t.Errorf("Unexpected JSON: %s", data)
+	}
+}
+
+func TestErrorToJSON(t *testing.T) {
+	wrapped := fmt.Errorf("wrapped: %w", NewTypedError(ErrorTypeConnectionActionNotFound, "no such action"))
+	if body := ErrorToJSON(wrapped, "", config.ErrorDisclosureStandard); body.Code != "CONNECTION_ACTION_NOT_FOUND" {
+		t.Errorf("Expected the wrapped TypedError's code, got %+v", body)
+	}
+
+	body := ErrorToJSON(errors.New("plain"), "req-2", config.ErrorDisclosureDebug)
+	if body.Code != "INTERNAL_ERROR" || body.Message != "plain" || body.RequestID != "req-2" {
+		t.Errorf("Expected INTERNAL_ERROR for a plain error, got %+v", body)
+	}
+}
+
+func TestTypedError_ToJSON_Production(t *testing.T) {
+	const registered ErrorType = "TEST_UPSTREAM_DOWN"
+	t.Cleanup(func() {
+		errorTypesMu.Lock()
+		delete(errorTypes, registered)
+		errorTypesMu.Unlock()
+	})
+	if err := RegisterErrorType(registered, ErrorTypeInfo{HTTPStatus: 502, Message: "Upstream unavailable"}); err != nil {
+		t.Fatalf("Failed to register error type: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		err     error
+		message string
+	}{
+		{
+			name:    "server error",
+			err:     NewTypedError(ErrorTypeConnectionActionRun, "pq: relation users does not exist", WithKey("query"), WithValue("SELECT 1")),
+			message: "An internal error occurred (request ID: req-3)",
+		},
+		{
+			name:    "server error with a client-safe message",
+			err:     NewTypedError(registered, "dial tcp 10.0.0.7:443: refused"),
+			message: "Upstream unavailable",
+		},
+		{
+			name:    "client error",
+			err:     NewTypedError(ErrorTypeConnectionActionParamRequired, "email is required", WithKey("email")),
+			message: "email is required",
+		},
+		{
+			name:    "plain error",
+			err:     errors.New("open /etc/secrets: permission denied"),
+			message: "An internal error occurred (request ID: req-3)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := ErrorToJSON(tt.err, "req-3", config.ErrorDisclosureProduction)
+			if body.Message != tt.message {
+				t.Errorf("Expected message %q, got %q", tt.message, body.Message)
+			}
+			if body.RequestID != "req-3" || body.Stack != "" || body.OriginalError != "" {
+				t.Errorf("Expected the request ID and no internal details, got %+v", body)
+			}
+		})
+	}
+
+	hidden := ErrorToJSON(tests[0].err, "req-3", config.ErrorDisclosureProduction)
+	if hidden.Key != "" || hidden.Value != nil {
+		t.Errorf("Expected a server error's key and value to be hidden, got %+v", hidden)
+	}
+	if client := ErrorToJSON(tests[2].err, "", config.ErrorDisclosureProduction); client.Key != "email" {
+		t.Errorf("Expected a client error's key, got %+v", client)
+	}
+}
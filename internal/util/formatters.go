@@ -0,0 +1,88 @@
+package util
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+// Built-in log formats (logger.format)
+const (
+	LogFormatText   = "text"   // Human-readable, colored when logger.colorize is set
+	LogFormatJSON   = "json"   // One JSON object per line
+	LogFormatLogfmt = "logfmt" // key=value pairs, never colored
+)
+
+// logTimestampFormat is the timestamp format of JSON and logfmt output
+const logTimestampFormat = "2006-01-02T15:04:05.000Z07:00"
+
+var (
+	logFormatters   = make(map[string]logrus.Formatter)
+	logFormattersMu sync.RWMutex
+)
+
+// RegisterLogFormatter registers a formatter by name so it can be selected
+// with logger.format. Register formatters before the logger is created,
+// typically in an init function.
+func RegisterLogFormatter(name string, formatter logrus.Formatter) error {
+	if name == "" {
+		return fmt.Errorf("log formatter name is required")
+	}
+	if formatter == nil {
+		return fmt.Errorf("log formatter '%s' is nil", name)
+	}
+	switch name {
+	case LogFormatText, LogFormatJSON, LogFormatLogfmt:
+		return fmt.Errorf("log format '%s' is built in", name)
+	}
+
+	logFormattersMu.Lock()
+	defer logFormattersMu.Unlock()
+	if _, exists := logFormatters[name]; exists {
+		return fmt.Errorf("log formatter '%s' is already registered", name)
+	}
+	logFormatters[name] = formatter
+	return nil
+}
+
+// newFormatter returns the formatter for logger.format. An empty format is
+// text when colorizing and JSON otherwise. It reports false for a format
+// that is neither built in nor registered.
+func newFormatter(cfg config.LoggerConfig) (logrus.Formatter, bool) {
+	format := cfg.Format
+	if format == "" {
+		format = LogFormatJSON
+		if cfg.Colorize {
+			format = LogFormatText
+		}
+	}
+
+	switch format {
+	case LogFormatText:
+		return &logrus.TextFormatter{
+			FullTimestamp: cfg.Timestamp,
+			ForceColors:   cfg.Colorize,
+			DisableColors: !cfg.Colorize,
+		}, true
+	case LogFormatJSON:
+		return &logrus.JSONFormatter{
+			TimestampFormat:  logTimestampFormat,
+			DisableTimestamp: !cfg.Timestamp,
+		}, true
+	case LogFormatLogfmt:
+		return &logrus.TextFormatter{
+			FullTimestamp:    true,
+			TimestampFormat:  logTimestampFormat,
+			DisableTimestamp: !cfg.Timestamp,
+			DisableColors:    true,
+			QuoteEmptyFields: true,
+		}, true
+	}
+
+	logFormattersMu.RLock()
+	defer logFormattersMu.RUnlock()
+	formatter, ok := logFormatters[format]
+	return formatter, ok
+}
@@ -0,0 +1,89 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+// prefixFormatter formats entries as "PREFIX <message>"
+type prefixFormatter struct{}
+
+func (prefixFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	return []byte("PREFIX " + entry.Message + "\n"), nil
+}
+
+func TestLogger_Format(t *testing.T) {
+	logLine := func(cfg config.LoggerConfig) string {
+		logger := NewLogger(cfg)
+		var buf bytes.Buffer
+		logger.SetOutput(&buf)
+		logger.WithField("user", "evan").Info("hello world")
+		return strings.TrimSpace(buf.String())
+	}
+
+	cfg := config.DefaultLoggerConfig()
+	cfg.Timestamp = false
+
+	cfg.Format = LogFormatLogfmt
+	if line := logLine(cfg); line != `level=info msg="hello world" user=evan` {
+		t.Errorf("Unexpected logfmt output: %s", line)
+	}
+
+	cfg.Format = LogFormatJSON
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(logLine(cfg)), &entry); err != nil {
+		t.Fatalf("Expected JSON output: %v", err)
+	}
+	if entry["msg"] != "hello world" || entry["user"] != "evan" {
+		t.Errorf("Unexpected JSON output: %v", entry)
+	}
+
+	// Messages are only colorized in the text format
+	cfg.Format = LogFormatLogfmt
+	if NewLogger(cfg).Colorize("x", ColorBlue) != "x" {
+		t.Error("Expected no colors with the logfmt format")
+	}
+}
+
+func TestRegisterLogFormatter(t *testing.T) {
+	t.Cleanup(func() {
+		logFormattersMu.Lock()
+		delete(logFormatters, "test-prefix")
+		logFormattersMu.Unlock()
+	})
+
+	if err := RegisterLogFormatter("test-prefix", prefixFormatter{}); err != nil {
+		t.Fatalf("Failed to register formatter: %v", err)
+	}
+	if err := RegisterLogFormatter("test-prefix", prefixFormatter{}); err == nil {
+		t.Error("Expected an error for a duplicate formatter")
+	}
+	if err := RegisterLogFormatter(LogFormatJSON, prefixFormatter{}); err == nil {
+		t.Error("Expected an error for a built-in format")
+	}
+
+	cfg := config.DefaultLoggerConfig()
+	cfg.Format = "test-prefix"
+	logger := NewLogger(cfg)
+	var buf bytes.Buffer
+	logger.SetOutput(&buf)
+	logger.Info("hello")
+	if buf.String() != "PREFIX hello\n" {
+		t.Errorf("Expected the registered formatter, got %q", buf.String())
+	}
+
+	// Unknown formats fall back to JSON
+	cfg.Format = "test-missing"
+	logger = NewLogger(cfg)
+	buf.Reset()
+	logger.SetOutput(&buf)
+	logger.Info("hello")
+	if !json.Valid(bytes.TrimSpace(buf.Bytes())) {
+		t.Errorf("Expected JSON output for an unknown format, got %q", buf.String())
+	}
+}
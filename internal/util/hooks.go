@@ -0,0 +1,97 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+// LogHookFactory builds a log hook from the logger configuration. Hooks that
+// implement io.Closer are closed with the logger.
+type LogHookFactory func(cfg config.LoggerConfig) (logrus.Hook, error)
+
+var (
+	logHooks   = make(map[string]LogHookFactory)
+	logHooksMu sync.RWMutex
+)
+
+// RegisterLogHook registers a hook by name so it can be enabled with
+// logger.hooks (e.g., "loki,slack"). Register hooks before the logger is
+// created, typically in an init function.
+func RegisterLogHook(name string, factory LogHookFactory) error {
+	if name == "" {
+		return fmt.Errorf("log hook name is required")
+	}
+
+	logHooksMu.Lock()
+	defer logHooksMu.Unlock()
+	if _, exists := logHooks[name]; exists {
+		return fmt.Errorf("log hook '%s' is already registered", name)
+	}
+	logHooks[name] = factory
+	return nil
+}
+
+// openHooks attaches the hooks named in logger.hooks. Unknown hooks and
+// hooks that fail to build are reported and skipped.
+func (l *Logger) openHooks(cfg config.LoggerConfig) {
+	for _, name := range strings.Split(cfg.Hooks, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		logHooksMu.RLock()
+		factory, ok := logHooks[name]
+		logHooksMu.RUnlock()
+		if !ok {
+			l.Logger.Warnf("Unknown log hook %q (register it with RegisterLogHook)", name)
+			continue
+		}
+
+		hook, err := factory(cfg)
+		if err != nil {
+			l.Logger.Warnf("Not using log hook %s: %v", name, err)
+			continue
+		}
+		l.Logger.AddHook(hook)
+		l.hooks = append(l.hooks, hook)
+	}
+}
+
+// funcHook adapts a function to logrus.Hook
+type funcHook struct {
+	levels []logrus.Level
+	fire   func(entry *logrus.Entry) error
+}
+
+// NewLogHook returns a hook that calls fire for entries at minLevel or more
+// severe (e.g., "error" for errors, fatals, and panics). Hooks run
+// synchronously while logging, so slow destinations should buffer.
+func NewLogHook(minLevel string, fire func(entry *logrus.Entry) error) (logrus.Hook, error) {
+	level, err := logrus.ParseLevel(minLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	var levels []logrus.Level
+	for _, l := range logrus.AllLevels {
+		if l <= level {
+			levels = append(levels, l)
+		}
+	}
+	return &funcHook{levels: levels, fire: fire}, nil
+}
+
+// Levels implements logrus.Hook
+func (h *funcHook) Levels() []logrus.Level {
+	return h.levels
+}
+
+// Fire implements logrus.Hook
+func (h *funcHook) Fire(entry *logrus.Entry) error {
+	return h.fire(entry)
+}
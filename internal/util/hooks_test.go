@@ -0,0 +1,106 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+// closingHook records entries and whether it was closed
+type closingHook struct {
+	entries []string
+	closed  bool
+}
+
+func (h *closingHook) Levels() []logrus.Level { return logrus.AllLevels }
+
+func (h *closingHook) Fire(entry *logrus.Entry) error {
+	h.entries = append(h.entries, entry.Message)
+	return nil
+}
+
+func (h *closingHook) Close() error {
+	h.closed = true
+	return nil
+}
+
+func TestRegisterLogHook(t *testing.T) {
+	hook := &closingHook{}
+	var hookCfg config.LoggerConfig
+	t.Cleanup(func() {
+		logHooksMu.Lock()
+		delete(logHooks, "test-recorder")
+		delete(logHooks, "test-broken")
+		logHooksMu.Unlock()
+	})
+
+	if err := RegisterLogHook("test-recorder", func(cfg config.LoggerConfig) (logrus.Hook, error) {
+		hookCfg = cfg
+		return hook, nil
+	}); err != nil {
+		t.Fatalf("Failed to register hook: %v", err)
+	}
+	if err := RegisterLogHook("test-recorder", nil); err == nil {
+		t.Error("Expected an error for a duplicate hook")
+	}
+	if err := RegisterLogHook("test-broken", func(config.LoggerConfig) (logrus.Hook, error) {
+		return nil, errors.New("no endpoint configured")
+	}); err != nil {
+		t.Fatalf("Failed to register hook: %v", err)
+	}
+
+	cfg := config.DefaultLoggerConfig()
+	cfg.Colorize = false
+	cfg.Hooks = "test-recorder, test-broken, test-missing"
+	logger := NewLogger(cfg)
+	var buf bytes.Buffer
+	logger.SetOutput(&buf)
+
+	// The hook also receives the warnings about the other hooks
+	logger.Info("shipped")
+	if len(hook.entries) == 0 || hook.entries[len(hook.entries)-1] != "shipped" {
+		t.Errorf("Expected the hook to receive the entry, got %v", hook.entries)
+	}
+	if hookCfg.Hooks != cfg.Hooks {
+		t.Error("Expected the factory to receive the logger config")
+	}
+	if len(logger.hooks) != 1 {
+		t.Errorf("Expected only the working hook to be attached, got %d", len(logger.hooks))
+	}
+
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Failed to close logger: %v", err)
+	}
+	if !hook.closed {
+		t.Error("Expected the hook to be closed with the logger")
+	}
+}
+
+func TestNewLogHook(t *testing.T) {
+	var fired []string
+	hook, err := NewLogHook("warn", func(entry *logrus.Entry) error {
+		fired = append(fired, entry.Message)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to create hook: %v", err)
+	}
+
+	logger := NewLogger(config.DefaultLoggerConfig())
+	logger.SetOutput(&bytes.Buffer{})
+	logger.AddHook(hook)
+
+	logger.Info("ignored")
+	logger.Warn("warned")
+	logger.Error("failed")
+	if len(fired) != 2 || fired[0] != "warned" || fired[1] != "failed" {
+		t.Errorf("Expected warn and error entries, got %v", fired)
+	}
+
+	if _, err := NewLogHook("loud", nil); err == nil {
+		t.Error("Expected an error for an unknown level")
+	}
+}
@@ -1,6 +1,10 @@
 package util
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 
 	"github.com/evantahler/go-actionhero/internal/config"
@@ -13,12 +17,17 @@ const (
 	ColorBlue    = color.FgBlue
 	ColorMagenta = color.FgMagenta
 	ColorGray    = color.FgHiBlack
+	ColorYellow  = color.FgYellow
 )
 
 // Logger wraps logrus.Logger with our configuration
 type Logger struct {
 	*logrus.Logger
-	config config.LoggerConfig
+	config  config.LoggerConfig
+	sampler *Sampler       // Limits repeated errors (nil when sampling is disabled)
+	sinks   []logSink      // Destinations besides stdout (file, syslog, journald)
+	hooks   []logrus.Hook  // Hooks enabled with logger.hooks
+	async   []*AsyncWriter // Buffered stdout and file writers (logger.asyncbuffer)
 }
 
 // NewLogger creates a new logger with the given configuration
@@ -35,23 +44,103 @@ func NewLogger(cfg config.LoggerConfig) *Logger {
 	// Set output
 	logger.SetOutput(os.Stdout)
 
-	// Set formatter
-	if cfg.Colorize {
-		logger.SetFormatter(&logrus.TextFormatter{
-			FullTimestamp: cfg.Timestamp,
-			ForceColors:   true,
-		})
-	} else {
-		logger.SetFormatter(&logrus.JSONFormatter{
-			TimestampFormat:  "2006-01-02T15:04:05.000Z07:00",
-			DisableTimestamp: !cfg.Timestamp,
-		})
+	// Set formatter. Messages are only colorized in the text format.
+	formatter, knownFormat := newFormatter(cfg)
+	if !knownFormat {
+		formatter, _ = newFormatter(config.LoggerConfig{Format: LogFormatJSON, Timestamp: cfg.Timestamp})
+	}
+	logger.SetFormatter(formatter)
+	if cfg.Format != "" && cfg.Format != LogFormatText {
+		cfg.Colorize = false
 	}
 
-	return &Logger{
+	l := &Logger{
 		Logger: logger,
 		config: cfg,
 	}
+
+	l.openSinks(cfg)
+	if len(l.sinks) > 0 && !cfg.Stdout {
+		logger.SetOutput(io.Discard)
+	} else if cfg.AsyncBuffer > 0 {
+		stdout := NewAsyncWriter(os.Stdout, cfg.AsyncBuffer)
+		l.async = append(l.async, stdout)
+		logger.SetOutput(stdout)
+	}
+	if len(l.async) > 0 {
+		// Flush buffered entries, including the fatal one, before exiting
+		logger.ExitFunc = func(code int) {
+			_ = l.Close()
+			os.Exit(code)
+		}
+	}
+	l.openHooks(cfg)
+	if !knownFormat {
+		l.Logger.Warnf("Unknown log format %q (register it with RegisterLogFormatter), using json", cfg.Format)
+	}
+
+	if cfg.ErrorSampleFirst > 0 && cfg.ErrorSampleWindow > 0 {
+		window := cfg.ErrorSampleWindow
+		l.sampler = NewSampler(cfg.ErrorSampleFirst, cfg.ErrorSampleThereafter, window, func(key string, suppressed int64) {
+			l.Logger.Warnf("Suppressed %d similar errors in the last %s: %s", suppressed, window, key)
+		})
+	}
+
+	return l
+}
+
+// Close flushes buffered entries and closes the log file, syslog/journald
+// connections, and configured hooks that implement io.Closer
+func (l *Logger) Close() error {
+	var errs []error
+	for _, w := range l.async {
+		if err := w.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, sink := range l.sinks {
+		if err := sink.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, hook := range l.hooks {
+		if closer, ok := hook.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Dropped returns how many entries were dropped because the async buffer
+// (logger.asyncbuffer) was full
+func (l *Logger) Dropped() int64 {
+	var dropped int64
+	for _, w := range l.async {
+		dropped += w.Dropped()
+	}
+	return dropped
+}
+
+// UpdateLevel changes the log level at runtime (e.g., on config reload)
+func (l *Logger) UpdateLevel(level string) error {
+	parsed, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	l.SetLevel(parsed)
+	l.config.Level = level
+	return nil
+}
+
+// AllowError returns whether an error identified by key should be logged.
+// Identical keys are sampled when logger.errorsamplefirst is set.
+func (l *Logger) AllowError(key string) bool {
+	if l.sampler == nil {
+		return true
+	}
+	return l.sampler.Allow(key)
 }
 
 // Debug logs a debug message
@@ -84,13 +173,20 @@ func (l *Logger) Warnf(format string, args ...interface{}) {
 	l.Logger.Warnf(format, args...)
 }
 
-// Error logs an error message
+// Error logs an error message (sampled when repeated)
 func (l *Logger) Error(args ...interface{}) {
+	if !l.AllowError(fmt.Sprint(args...)) {
+		return
+	}
 	l.Logger.Error(args...)
 }
 
-// Errorf logs a formatted error message
+// Errorf logs a formatted error message. Messages from the same format
+// string are considered similar and sampled when repeated.
 func (l *Logger) Errorf(format string, args ...interface{}) {
+	if !l.AllowError(format) {
+		return
+	}
 	l.Logger.Errorf(format, args...)
 }
 
@@ -114,6 +210,16 @@ func (l *Logger) WithFields(fields logrus.Fields) *logrus.Entry {
 	return l.Logger.WithFields(fields)
 }
 
+// WithContext returns a log entry carrying the context and, if present,
+// the request ID stored in it
+func (l *Logger) WithContext(ctx context.Context) *logrus.Entry {
+	entry := l.Logger.WithContext(ctx)
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		entry = entry.WithField(RequestIDField, requestID)
+	}
+	return entry
+}
+
 // ColorizeIf colorizes text if colorization is enabled and bold is true,
 // or applies color without bold if bold is false
 func (l *Logger) ColorizeIf(text string, colorAttr color.Attribute, bold bool) string {
@@ -2,6 +2,11 @@ package util
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -136,6 +141,53 @@ func TestLogger_Colorize(t *testing.T) {
 	}
 }
 
+func TestLogger_File(t *testing.T) {
+	tests := []struct {
+		name   string
+		stdout bool
+	}{
+		{"file and stdout", true},
+		{"file only", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultLoggerConfig()
+			cfg.File = filepath.Join(t.TempDir(), "log", "actionhero.log")
+			cfg.Stdout = tt.stdout
+			logger := NewLogger(cfg)
+
+			var stdout bytes.Buffer
+			if tt.stdout {
+				logger.SetOutput(&stdout)
+			}
+			logger.Info(logger.Colorize("hello", ColorBlue))
+			if err := logger.Close(); err != nil {
+				t.Fatalf("Failed to close logger: %v", err)
+			}
+
+			content, err := os.ReadFile(cfg.File)
+			if err != nil {
+				t.Fatalf("Failed to read log file: %v", err)
+			}
+			var entry map[string]interface{}
+			if err := json.Unmarshal(content, &entry); err != nil {
+				t.Fatalf("Expected a JSON line, got %q", content)
+			}
+			if entry["msg"] != "hello" || entry["level"] != "info" {
+				t.Errorf("Expected an uncolored info entry, got %v", entry)
+			}
+
+			if tt.stdout && !strings.Contains(stdout.String(), "hello") {
+				t.Errorf("Expected stdout to have the entry, got %q", stdout.String())
+			}
+			if !tt.stdout && logger.Out != io.Discard {
+				t.Error("Expected stdout to be discarded")
+			}
+		})
+	}
+}
+
 func TestLogger_Timestamp(t *testing.T) {
 	cfg := config.DefaultLoggerConfig()
 	cfg.Timestamp = true
@@ -173,3 +225,31 @@ func TestDefaultLoggerConfig(t *testing.T) {
 		t.Error("Expected default timestamp to be true")
 	}
 }
+
+func TestLogger_WithContextRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(config.LoggerConfig{Level: "info", Colorize: false})
+	logger.SetOutput(&buf)
+
+	logger.WithContext(WithRequestID(context.Background(), "req-123")).Info("with id")
+	if !strings.Contains(buf.String(), `"requestId":"req-123"`) {
+		t.Errorf("Expected request ID in log output, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	logger.WithContext(context.Background()).Info("without id")
+	if strings.Contains(buf.String(), "requestId") {
+		t.Errorf("Expected no request ID in log output, got: %s", buf.String())
+	}
+}
+
+func TestRequestIDFromContext(t *testing.T) {
+	if id := RequestIDFromContext(context.Background()); id != "" {
+		t.Errorf("Expected empty request ID, got %q", id)
+	}
+
+	ctx := WithRequestID(context.Background(), "req-456")
+	if id := RequestIDFromContext(ctx); id != "req-456" {
+		t.Errorf("Expected 'req-456', got %q", id)
+	}
+}
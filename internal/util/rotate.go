@@ -0,0 +1,240 @@
+package util
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat names rotated files (e.g., actionhero-20261017T150405.000.log)
+const backupTimeFormat = "20060102T150405.000"
+
+// RotatingFile is an io.WriteCloser that appends to a file and rotates it
+// when it grows past a size or gets older than an age. Rotated files are
+// renamed with a timestamp, optionally gzipped, and pruned to a number of
+// backups.
+type RotatingFile struct {
+	path       string
+	maxSize    int64         // 0 = no size limit
+	maxAge     time.Duration // 0 = no age limit
+	maxBackups int           // 0 = keep every rotated file
+	compress   bool
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	now      func() time.Time
+
+	// Compression and pruning run in the background, one rotation at a
+	// time; Close waits for them
+	background   sync.WaitGroup
+	backgroundMu sync.Mutex
+}
+
+// NewRotatingFile opens (or creates) the file at path for appending,
+// creating its directory if needed
+func NewRotatingFile(path string, maxSize int64, maxAge time.Duration, maxBackups int, compress bool) (*RotatingFile, error) {
+	r := &RotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxAge:     maxAge,
+		maxBackups: maxBackups,
+		compress:   compress,
+		now:        time.Now,
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Write appends p to the file, rotating it first if p would take it past
+// its size limit or the file is past its age limit
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+	if r.shouldRotate(int64(len(p))) {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Rotate rotates the file now
+func (r *RotatingFile) Rotate() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return os.ErrClosed
+	}
+	return r.rotate()
+}
+
+// Close closes the file and waits for background compression and pruning
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	var err error
+	if r.file != nil {
+		err = r.file.Close()
+		r.file = nil
+	}
+	r.mu.Unlock()
+
+	r.background.Wait()
+	return err
+}
+
+// shouldRotate returns whether the file must be rotated before writing n bytes.
+// A file is never rotated while empty.
+func (r *RotatingFile) shouldRotate(n int64) bool {
+	if r.size == 0 {
+		return false
+	}
+	if r.maxSize > 0 && r.size+n > r.maxSize {
+		return true
+	}
+	return r.maxAge > 0 && r.now().Sub(r.openedAt) >= r.maxAge
+}
+
+// open opens the file for appending
+func (r *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	r.file = file
+	r.size = info.Size()
+	r.openedAt = r.now()
+	return nil
+}
+
+// rotate renames the current file to a timestamped backup and opens a new
+// one. Callers must hold mu.
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+	r.file = nil
+
+	backup := r.backupName(r.now())
+	if err := os.Rename(r.path, backup); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	if err := r.open(); err != nil {
+		return err
+	}
+
+	r.background.Add(1)
+	go func() {
+		defer r.background.Done()
+		r.backgroundMu.Lock()
+		defer r.backgroundMu.Unlock()
+		if r.compress {
+			_ = compressFile(backup)
+		}
+		r.prune()
+	}()
+	return nil
+}
+
+// backupName returns the name of a file rotated at t
+func (r *RotatingFile) backupName(t time.Time) string {
+	ext := filepath.Ext(r.path)
+	base := strings.TrimSuffix(r.path, ext)
+	return fmt.Sprintf("%s-%s%s", base, t.Format(backupTimeFormat), ext)
+}
+
+// Backups returns the rotated files, oldest first
+func (r *RotatingFile) Backups() ([]string, error) {
+	ext := filepath.Ext(r.path)
+	prefix := strings.TrimSuffix(filepath.Base(r.path), ext) + "-"
+
+	entries, err := os.ReadDir(filepath.Dir(r.path))
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []string
+	for _, entry := range entries {
+		name := entry.Name()
+		stamp, ok := strings.CutPrefix(name, prefix)
+		if !ok {
+			continue
+		}
+		stamp = strings.TrimSuffix(strings.TrimSuffix(stamp, ".gz"), ext)
+		if _, err := time.Parse(backupTimeFormat, stamp); err != nil {
+			continue
+		}
+		backups = append(backups, filepath.Join(filepath.Dir(r.path), name))
+	}
+
+	// Timestamps sort chronologically
+	sort.Strings(backups)
+	return backups, nil
+}
+
+// prune removes the oldest backups beyond maxBackups
+func (r *RotatingFile) prune() {
+	if r.maxBackups <= 0 {
+		return
+	}
+	backups, err := r.Backups()
+	if err != nil || len(backups) <= r.maxBackups {
+		return
+	}
+	for _, backup := range backups[:len(backups)-r.maxBackups] {
+		_ = os.Remove(backup)
+	}
+}
+
+// compressFile gzips path to path.gz and removes the original
+func compressFile(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(out)
+	if _, err := io.Copy(gz, in); err != nil {
+		_ = out.Close()
+		_ = os.Remove(path + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,132 @@
+package util
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotatingFile_SizeRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	file, err := NewRotatingFile(path, 10, 0, 0, false)
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	clock := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	file.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		if _, err := file.Write([]byte(line)); err != nil {
+			t.Fatalf("Failed to write: %v", err)
+		}
+	}
+	if err := file.Close(); err != nil {
+		t.Fatalf("Failed to close: %v", err)
+	}
+
+	backups, err := file.Backups()
+	if err != nil {
+		t.Fatalf("Failed to list backups: %v", err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("Expected 2 backups, got %v", backups)
+	}
+	if !strings.HasPrefix(filepath.Base(backups[0]), "app-20261017T") || filepath.Ext(backups[0]) != ".log" {
+		t.Errorf("Unexpected backup name %s", backups[0])
+	}
+
+	oldest, _ := os.ReadFile(backups[0])
+	current, _ := os.ReadFile(path)
+	if string(oldest) != "first\n" || string(current) != "third\n" {
+		t.Errorf("Expected oldest 'first' and current 'third', got %q and %q", oldest, current)
+	}
+}
+
+func TestRotatingFile_AgeRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	file, err := NewRotatingFile(path, 0, time.Hour, 0, false)
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	file.now = func() time.Time { return now }
+	file.openedAt = now
+
+	_, _ = file.Write([]byte("morning\n"))
+	now = now.Add(30 * time.Minute)
+	_, _ = file.Write([]byte("still morning\n"))
+	now = now.Add(time.Hour)
+	_, _ = file.Write([]byte("afternoon\n"))
+	_ = file.Close()
+
+	backups, _ := file.Backups()
+	if len(backups) != 1 {
+		t.Fatalf("Expected 1 backup, got %v", backups)
+	}
+	rotated, _ := os.ReadFile(backups[0])
+	if string(rotated) != "morning\nstill morning\n" {
+		t.Errorf("Unexpected rotated content %q", rotated)
+	}
+}
+
+func TestRotatingFile_CompressAndPrune(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	file, err := NewRotatingFile(path, 0, 0, 2, true)
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	clock := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	file.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+
+	for i := 0; i < 4; i++ {
+		if _, err := file.Write([]byte("entry\n")); err != nil {
+			t.Fatalf("Failed to write: %v", err)
+		}
+		if err := file.Rotate(); err != nil {
+			t.Fatalf("Failed to rotate: %v", err)
+		}
+	}
+	_ = file.Close()
+
+	backups, _ := file.Backups()
+	if len(backups) != 2 {
+		t.Fatalf("Expected 2 backups after pruning, got %v", backups)
+	}
+	for _, backup := range backups {
+		if !strings.HasSuffix(backup, ".log.gz") {
+			t.Errorf("Expected a compressed backup, got %s", backup)
+			continue
+		}
+		f, _ := os.Open(backup)
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatalf("Failed to read gzip: %v", err)
+		}
+		content, _ := io.ReadAll(gz)
+		_ = f.Close()
+		if string(content) != "entry\n" {
+			t.Errorf("Unexpected compressed content %q", content)
+		}
+	}
+}
+
+func TestRotatingFile_WriteAfterClose(t *testing.T) {
+	file, err := NewRotatingFile(filepath.Join(t.TempDir(), "app.log"), 0, 0, 0, false)
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	_ = file.Close()
+	if _, err := file.Write([]byte("late\n")); err == nil {
+		t.Error("Expected an error writing to a closed file")
+	}
+}
@@ -0,0 +1,104 @@
+package util
+
+import (
+	"sync"
+	"time"
+)
+
+// Sampler limits how often identical messages are logged. Within each window
+// the first N occurrences of a key are allowed, then every Mth; the rest are
+// suppressed and reported once the window ends.
+type Sampler struct {
+	first        int
+	thereafter   int
+	window       time.Duration
+	onSuppressed func(key string, suppressed int64)
+
+	entries   map[string]*sampleEntry
+	lastSweep time.Time
+	mu        sync.Mutex
+}
+
+// sampleEntry tracks one key within the current window
+type sampleEntry struct {
+	windowStart time.Time
+	count       int64
+	suppressed  int64
+	timer       *time.Timer
+}
+
+// NewSampler creates a sampler. onSuppressed is called at the end of a window
+// in which any occurrences of key were suppressed.
+func NewSampler(first, thereafter int, window time.Duration, onSuppressed func(key string, suppressed int64)) *Sampler {
+	return &Sampler{
+		first:        first,
+		thereafter:   thereafter,
+		window:       window,
+		onSuppressed: onSuppressed,
+		entries:      make(map[string]*sampleEntry),
+		lastSweep:    time.Now(),
+	}
+}
+
+// Allow records an occurrence of key and returns whether it should be logged
+func (s *Sampler) Allow(key string) bool {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	entry, exists := s.entries[key]
+	if !exists || (entry.timer == nil && now.Sub(entry.windowStart) >= s.window) {
+		entry = &sampleEntry{windowStart: now}
+		s.entries[key] = entry
+	}
+
+	entry.count++
+	if entry.count <= int64(s.first) {
+		return true
+	}
+	if s.thereafter > 0 && (entry.count-int64(s.first))%int64(s.thereafter) == 0 {
+		return true
+	}
+
+	entry.suppressed++
+	if entry.timer == nil {
+		remaining := s.window - now.Sub(entry.windowStart)
+		entry.timer = time.AfterFunc(remaining, func() { s.flush(key) })
+	}
+	return false
+}
+
+// flush ends the window for key and reports any suppressed occurrences
+func (s *Sampler) flush(key string) {
+	s.mu.Lock()
+	entry, exists := s.entries[key]
+	if !exists {
+		s.mu.Unlock()
+		return
+	}
+	suppressed := entry.suppressed
+	delete(s.entries, key)
+	s.mu.Unlock()
+
+	if suppressed > 0 && s.onSuppressed != nil {
+		s.onSuppressed(key, suppressed)
+	}
+}
+
+// sweep drops expired entries without pending summaries, at most once per
+// window, so keys that stop occurring don't accumulate
+func (s *Sampler) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < s.window {
+		return
+	}
+	s.lastSweep = now
+
+	for key, entry := range s.entries {
+		if entry.timer == nil && now.Sub(entry.windowStart) >= s.window {
+			delete(s.entries, key)
+		}
+	}
+}
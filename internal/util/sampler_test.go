@@ -0,0 +1,114 @@
+package util
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+)
+
+func TestSampler_FirstThenEvery(t *testing.T) {
+	s := NewSampler(3, 5, time.Minute, nil)
+
+	allowed := 0
+	for i := 0; i < 23; i++ {
+		if s.Allow("key") {
+			allowed++
+		}
+	}
+
+	// 3 first + occurrences 8, 13, 18, 23
+	if allowed != 7 {
+		t.Errorf("Expected 7 allowed, got %d", allowed)
+	}
+
+	if !s.Allow("other") {
+		t.Error("Expected a different key to be allowed")
+	}
+}
+
+func TestSampler_ReportsSuppressed(t *testing.T) {
+	var mu sync.Mutex
+	reported := map[string]int64{}
+	done := make(chan struct{})
+
+	s := NewSampler(2, 0, 50*time.Millisecond, func(key string, suppressed int64) {
+		mu.Lock()
+		reported[key] = suppressed
+		mu.Unlock()
+		close(done)
+	})
+
+	for i := 0; i < 10; i++ {
+		s.Allow("boom")
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for suppressed summary")
+	}
+
+	mu.Lock()
+	if reported["boom"] != 8 {
+		t.Errorf("Expected 8 suppressed, got %d", reported["boom"])
+	}
+	mu.Unlock()
+
+	// A new window starts after the summary
+	if !s.Allow("boom") {
+		t.Error("Expected key to be allowed again in a new window")
+	}
+}
+
+func TestLogger_ErrorSampling(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(config.LoggerConfig{
+		Level:                 "info",
+		ErrorSampleFirst:      2,
+		ErrorSampleThereafter: 0,
+		ErrorSampleWindow:     50 * time.Millisecond,
+	})
+	var mu sync.Mutex
+	logger.SetOutput(&lockedWriter{w: &buf, mu: &mu})
+
+	for i := 0; i < 10; i++ {
+		logger.Errorf("downstream failed: %d", i)
+	}
+	time.Sleep(150 * time.Millisecond)
+
+	mu.Lock()
+	output := buf.String()
+	mu.Unlock()
+
+	if count := strings.Count(output, "downstream failed"); count != 3 {
+		t.Errorf("Expected 2 errors plus 1 summary mentioning the error, got %d\n%s", count, output)
+	}
+	if !strings.Contains(output, "Suppressed 8 similar errors") {
+		t.Errorf("Expected suppressed summary, got:\n%s", output)
+	}
+}
+
+func TestLogger_ErrorSamplingDisabled(t *testing.T) {
+	logger := NewLogger(config.LoggerConfig{Level: "info"})
+	for i := 0; i < 1000; i++ {
+		if !logger.AllowError("same") {
+			t.Fatal("Expected all errors to be allowed when sampling is disabled")
+		}
+	}
+}
+
+// lockedWriter serializes writes from the logger and the summary timer
+type lockedWriter struct {
+	w  *bytes.Buffer
+	mu *sync.Mutex
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
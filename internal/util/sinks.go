@@ -0,0 +1,223 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+// logSink receives every log entry, in addition to stdout
+type logSink interface {
+	Send(entry *logrus.Entry) error
+	Close() error
+}
+
+// openSinks attaches the log file, syslog, and journald sinks that are
+// configured. A sink that can't be opened is reported and skipped, so a
+// misconfigured destination never stops the process from logging.
+func (l *Logger) openSinks(cfg config.LoggerConfig) {
+	add := func(name string, sink logSink, err error) {
+		if err != nil {
+			l.Logger.Warnf("Not logging to %s: %v", name, err)
+			return
+		}
+		l.sinks = append(l.sinks, sink)
+		l.Logger.AddHook(&sinkHook{sink: sink})
+	}
+
+	if cfg.File != "" {
+		sink, err := newFileSink(cfg)
+		add("file "+cfg.File, sink, err)
+		if err == nil && sink.async != nil {
+			l.async = append(l.async, sink.async)
+		}
+	}
+	if cfg.Syslog != "" {
+		sink, err := newSyslogSink(cfg)
+		add("syslog", sink, err)
+	}
+	if cfg.Journald {
+		sink, err := newJournaldSink(cfg)
+		add("journald", sink, err)
+	}
+}
+
+// ansiEscape matches terminal color codes, which are removed before entries
+// reach a sink
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// sinkHook forwards every entry to a sink
+type sinkHook struct {
+	sink logSink
+}
+
+// Levels implements logrus.Hook
+func (h *sinkHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook
+func (h *sinkHook) Fire(entry *logrus.Entry) error {
+	plain := *entry
+	plain.Message = ansiEscape.ReplaceAllString(entry.Message, "")
+	return h.sink.Send(&plain)
+}
+
+// fileSink writes entries to a rotating file as JSON lines, whatever the
+// stdout format
+type fileSink struct {
+	file      *RotatingFile
+	async     *AsyncWriter // Buffers writes to file (nil when synchronous)
+	out       io.Writer    // file or async
+	formatter logrus.Formatter
+}
+
+// newFileSink opens the log file
+func newFileSink(cfg config.LoggerConfig) (*fileSink, error) {
+	file, err := NewRotatingFile(cfg.File, int64(cfg.FileMaxSize), cfg.FileMaxAge, cfg.FileMaxBackups, cfg.FileCompress)
+	if err != nil {
+		return nil, err
+	}
+	sink := &fileSink{file: file, out: file, formatter: &logrus.JSONFormatter{TimestampFormat: logTimestampFormat}}
+	if cfg.AsyncBuffer > 0 {
+		sink.async = NewAsyncWriter(file, cfg.AsyncBuffer)
+		sink.out = sink.async
+	}
+	return sink, nil
+}
+
+// Send implements logSink
+func (s *fileSink) Send(entry *logrus.Entry) error {
+	line, err := s.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	_, err = s.out.Write(line)
+	return err
+}
+
+// Close implements logSink
+func (s *fileSink) Close() error {
+	if s.async != nil {
+		_ = s.async.Close()
+	}
+	return s.file.Close()
+}
+
+// Syslog severities (RFC 5424), also used as journald priorities
+const (
+	severityEmergency = 0
+	severityCritical  = 2
+	severityError     = 3
+	severityWarning   = 4
+	severityInfo      = 6
+	severityDebug     = 7
+)
+
+// severity maps a log level to its syslog severity
+func severity(level logrus.Level) int {
+	switch level {
+	case logrus.PanicLevel:
+		return severityEmergency
+	case logrus.FatalLevel:
+		return severityCritical
+	case logrus.ErrorLevel:
+		return severityError
+	case logrus.WarnLevel:
+		return severityWarning
+	case logrus.InfoLevel:
+		return severityInfo
+	default:
+		return severityDebug
+	}
+}
+
+// syslogFacility returns the code of a facility name
+func syslogFacility(name string) (int, error) {
+	for code, facility := range config.SyslogFacilities {
+		if facility != "" && strings.EqualFold(facility, name) {
+			return code, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown syslog facility %q", name)
+}
+
+// syslogTarget splits logger.syslog into a network and address. "local"
+// uses the local syslog daemon (empty network and address).
+func syslogTarget(value string) (network, address string, err error) {
+	if value == "local" {
+		return "", "", nil
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "udp" && u.Scheme != "tcp") || u.Host == "" {
+		return "", "", fmt.Errorf("invalid syslog address %q (use \"local\", udp://host:port, or tcp://host:port)", value)
+	}
+	return u.Scheme, u.Host, nil
+}
+
+// syslogMessage formats an entry for syslog: the message followed by its
+// fields in key order (syslog adds the timestamp and severity)
+func syslogMessage(entry *logrus.Entry) string {
+	keys := make([]string, 0, len(entry.Data))
+	for key := range entry.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(entry.Message)
+	for _, key := range keys {
+		fmt.Fprintf(&b, " %s=%v", key, entry.Data[key])
+	}
+	return b.String()
+}
+
+// journalFieldInvalid matches characters not allowed in journald field names
+var journalFieldInvalid = regexp.MustCompile(`[^A-Z0-9_]`)
+
+// journalFieldName converts a log field name to a journald field name
+// (e.g., "requestId" becomes "REQUESTID")
+func journalFieldName(name string) string {
+	name = journalFieldInvalid.ReplaceAllString(strings.ToUpper(name), "_")
+	return strings.TrimLeft(name, "_")
+}
+
+// journalMessage encodes an entry in the journald native protocol
+func journalMessage(entry *logrus.Entry, identifier string) []byte {
+	var b strings.Builder
+	writeField := func(name, value string) {
+		if name == "" {
+			return
+		}
+		if !strings.Contains(value, "\n") {
+			b.WriteString(name + "=" + value + "\n")
+			return
+		}
+		// Multi-line values are length-prefixed (64-bit little endian)
+		b.WriteString(name + "\n")
+		size := uint64(len(value))
+		for i := 0; i < 8; i++ {
+			b.WriteByte(byte(size >> (8 * i)))
+		}
+		b.WriteString(value + "\n")
+	}
+
+	writeField("MESSAGE", entry.Message)
+	writeField("PRIORITY", fmt.Sprint(severity(entry.Level)))
+	writeField("SYSLOG_IDENTIFIER", identifier)
+	for key, value := range entry.Data {
+		switch name := journalFieldName(key); name {
+		case "MESSAGE", "PRIORITY", "SYSLOG_IDENTIFIER":
+			// Reserved for the fields above
+		default:
+			writeField(name, fmt.Sprint(value))
+		}
+	}
+	return []byte(b.String())
+}
@@ -0,0 +1,19 @@
+//go:build !unix
+
+package util
+
+import (
+	"errors"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+)
+
+// newSyslogSink reports that syslog isn't available on this platform
+func newSyslogSink(_ config.LoggerConfig) (logSink, error) {
+	return nil, errors.New("syslog is not supported on this platform")
+}
+
+// newJournaldSink reports that journald isn't available on this platform
+func newJournaldSink(_ config.LoggerConfig) (logSink, error) {
+	return nil, errors.New("journald is not supported on this platform")
+}
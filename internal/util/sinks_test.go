@@ -0,0 +1,90 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestSeverity(t *testing.T) {
+	tests := []struct {
+		level logrus.Level
+		want  int
+	}{
+		{logrus.PanicLevel, 0},
+		{logrus.FatalLevel, 2},
+		{logrus.ErrorLevel, 3},
+		{logrus.WarnLevel, 4},
+		{logrus.InfoLevel, 6},
+		{logrus.DebugLevel, 7},
+		{logrus.TraceLevel, 7},
+	}
+	for _, tt := range tests {
+		if got := severity(tt.level); got != tt.want {
+			t.Errorf("severity(%s) = %d, want %d", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestSyslogTarget(t *testing.T) {
+	tests := []struct {
+		value   string
+		network string
+		address string
+		wantErr bool
+	}{
+		{"local", "", "", false},
+		{"udp://logs.example.com:514", "udp", "logs.example.com:514", false},
+		{"tcp://10.0.0.5:6514", "tcp", "10.0.0.5:6514", false},
+		{"logs.example.com:514", "", "", true},
+		{"http://logs.example.com", "", "", true},
+	}
+	for _, tt := range tests {
+		network, address, err := syslogTarget(tt.value)
+		if (err != nil) != tt.wantErr || network != tt.network || address != tt.address {
+			t.Errorf("syslogTarget(%q) = %q, %q, %v", tt.value, network, address, err)
+		}
+	}
+}
+
+func TestSyslogFacility(t *testing.T) {
+	if code, err := syslogFacility("local0"); err != nil || code != 16 {
+		t.Errorf("Expected local0 to be 16, got %d, %v", code, err)
+	}
+	if code, err := syslogFacility("DAEMON"); err != nil || code != 3 {
+		t.Errorf("Expected daemon to be 3, got %d, %v", code, err)
+	}
+	if _, err := syslogFacility("local9"); err == nil {
+		t.Error("Expected an error for an unknown facility")
+	}
+}
+
+func TestSyslogMessage(t *testing.T) {
+	entry := &logrus.Entry{Message: "request done", Data: logrus.Fields{"status": 200, "requestId": "abc"}}
+	if got := syslogMessage(entry); got != "request done requestId=abc status=200" {
+		t.Errorf("Unexpected syslog message %q", got)
+	}
+}
+
+func TestJournalMessage(t *testing.T) {
+	entry := &logrus.Entry{
+		Level:   logrus.WarnLevel,
+		Message: "line one\nline two",
+		Data:    logrus.Fields{"requestId": "abc", "_private": "x", "message": "shadowed"},
+	}
+	msg := string(journalMessage(entry, "actionhero"))
+
+	for _, want := range []string{"PRIORITY=4\n", "SYSLOG_IDENTIFIER=actionhero\n", "REQUESTID=abc\n", "PRIVATE=x\n"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("Expected %q in %q", want, msg)
+		}
+	}
+	// Multi-line values are length-prefixed
+	if !strings.HasPrefix(msg, "MESSAGE\n\x11\x00\x00\x00\x00\x00\x00\x00line one\nline two\n") {
+		t.Errorf("Expected a length-prefixed MESSAGE, got %q", msg)
+	}
+	if strings.Contains(msg, "shadowed") {
+		t.Error("Expected fields not to override MESSAGE")
+	}
+}
@@ -0,0 +1,88 @@
+//go:build unix
+
+package util
+
+import (
+	"log/syslog"
+	"net"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+// journalSocket is the journald native protocol socket
+var journalSocket = "/run/systemd/journal/socket"
+
+// syslogSink sends entries to a local or remote syslog daemon
+type syslogSink struct {
+	writer *syslog.Writer
+}
+
+// newSyslogSink connects to the syslog daemon in logger.syslog
+func newSyslogSink(cfg config.LoggerConfig) (*syslogSink, error) {
+	network, address, err := syslogTarget(cfg.Syslog)
+	if err != nil {
+		return nil, err
+	}
+	facility, err := syslogFacility(cfg.SyslogFacility)
+	if err != nil {
+		return nil, err
+	}
+
+	// The severity is set per message; the facility is shifted into place
+	writer, err := syslog.Dial(network, address, syslog.Priority(facility<<3)|syslog.LOG_INFO, cfg.SyslogTag)
+	if err != nil {
+		return nil, err
+	}
+	return &syslogSink{writer: writer}, nil
+}
+
+// Send implements logSink
+func (s *syslogSink) Send(entry *logrus.Entry) error {
+	message := syslogMessage(entry)
+	switch severity(entry.Level) {
+	case severityEmergency:
+		return s.writer.Emerg(message)
+	case severityCritical:
+		return s.writer.Crit(message)
+	case severityError:
+		return s.writer.Err(message)
+	case severityWarning:
+		return s.writer.Warning(message)
+	case severityInfo:
+		return s.writer.Info(message)
+	default:
+		return s.writer.Debug(message)
+	}
+}
+
+// Close implements logSink
+func (s *syslogSink) Close() error {
+	return s.writer.Close()
+}
+
+// journaldSink sends entries to systemd-journald with their fields
+type journaldSink struct {
+	conn       net.Conn
+	identifier string
+}
+
+// newJournaldSink connects to the journald socket
+func newJournaldSink(cfg config.LoggerConfig) (*journaldSink, error) {
+	conn, err := net.Dial("unixgram", journalSocket)
+	if err != nil {
+		return nil, err
+	}
+	return &journaldSink{conn: conn, identifier: cfg.SyslogTag}, nil
+}
+
+// Send implements logSink
+func (s *journaldSink) Send(entry *logrus.Entry) error {
+	_, err := s.conn.Write(journalMessage(entry, s.identifier))
+	return err
+}
+
+// Close implements logSink
+func (s *journaldSink) Close() error {
+	return s.conn.Close()
+}
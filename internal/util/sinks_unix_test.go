@@ -0,0 +1,100 @@
+//go:build unix
+
+package util
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+)
+
+func TestLogger_Syslog(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	cfg := config.DefaultLoggerConfig()
+	cfg.Syslog = "udp://" + listener.LocalAddr().String()
+	cfg.SyslogFacility = "daemon"
+	cfg.SyslogTag = "myapp"
+	logger := NewLogger(cfg)
+	defer func() { _ = logger.Close() }()
+
+	logger.WithField("requestId", "abc").Error("database unavailable")
+
+	buf := make([]byte, 1024)
+	_ = listener.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := listener.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("Failed to read syslog message: %v", err)
+	}
+	msg := string(buf[:n])
+
+	// daemon (3) * 8 + err (3) = 27
+	if !strings.HasPrefix(msg, "<27>") {
+		t.Errorf("Expected priority <27>, got %q", msg)
+	}
+	if !strings.Contains(msg, "myapp[") || !strings.Contains(msg, "database unavailable requestId=abc") {
+		t.Errorf("Unexpected syslog message %q", msg)
+	}
+}
+
+func TestLogger_Journald(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "journal.socket")
+	listener, err := net.ListenPacket("unixgram", socket)
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	original := journalSocket
+	journalSocket = socket
+	t.Cleanup(func() { journalSocket = original })
+
+	cfg := config.DefaultLoggerConfig()
+	cfg.Journald = true
+	cfg.Stdout = false
+	logger := NewLogger(cfg)
+	defer func() { _ = logger.Close() }()
+
+	logger.Info(logger.Colorize("started", ColorBlue))
+
+	buf := make([]byte, 1024)
+	_ = listener.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := listener.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("Failed to read journald message: %v", err)
+	}
+	msg := string(buf[:n])
+	for _, want := range []string{"MESSAGE=started\n", "PRIORITY=6\n", "SYSLOG_IDENTIFIER=actionhero\n"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("Expected %q in %q", want, msg)
+		}
+	}
+}
+
+func TestLogger_UnavailableSink(t *testing.T) {
+	original := journalSocket
+	journalSocket = filepath.Join(t.TempDir(), "missing.socket")
+	t.Cleanup(func() { journalSocket = original })
+
+	cfg := config.DefaultLoggerConfig()
+	cfg.Journald = true
+	cfg.Stdout = false
+	logger := NewLogger(cfg)
+
+	// Without a working sink, logs keep going to stdout
+	if len(logger.sinks) != 0 {
+		t.Errorf("Expected no sinks, got %d", len(logger.sinks))
+	}
+	if logger.Out != os.Stdout {
+		t.Error("Expected stdout to be kept when no sink could be opened")
+	}
+}
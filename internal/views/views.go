@@ -0,0 +1,175 @@
+// Package views renders server-side HTML pages with html/template, in a
+// layout and with shared partials, so actions can serve pages alongside the
+// JSON API.
+package views
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+)
+
+// ErrViewNotFound is returned when rendering a page that was not loaded
+var ErrViewNotFound = errors.New("view not found")
+
+// contentTemplate is the name each page is parsed under, so a layout
+// renders the page with {{template "content" .}}
+const contentTemplate = "content"
+
+// Views holds the pages in views.directory, by path relative to it (e.g.,
+// "users/show.html"). Templates in layouts/ and partials/ are not pages:
+// every page can use the partials ({{template "partials/nav.html" .}}), and
+// renders in views.layout, which includes the page with
+// {{template "content" .}}. Pages can fill other parts of the layout with
+// {{define "title"}}...{{end}}, which the layout declares with
+// {{block "title" .}}default{{end}}.
+type Views struct {
+	mu     sync.RWMutex
+	cfg    config.ViewsConfig
+	funcs  template.FuncMap
+	pages  map[string]*template.Template
+	layout string // views.layout, or "" when there is no such file
+}
+
+// New creates an empty set of views
+func New() *Views {
+	return &Views{funcs: template.FuncMap{}, pages: map[string]*template.Template{}}
+}
+
+// Funcs adds functions templates can call. Add them before the views are
+// loaded (e.g., from an initializer).
+func (v *Views) Funcs(funcs template.FuncMap) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for name, fn := range funcs {
+		v.funcs[name] = fn
+	}
+}
+
+// Load parses the templates in cfg.Directory, replacing the loaded pages,
+// and returns how many pages there are
+func (v *Views) Load(cfg config.ViewsConfig) (int, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.cfg = cfg
+	return v.load()
+}
+
+// load parses the templates in v.cfg.Directory; the caller holds the lock
+func (v *Views) load() (int, error) {
+	dir := v.cfg.Directory
+	var pages, shared []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || (filepath.Ext(path) != ".html" && filepath.Ext(path) != ".tmpl") {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if strings.HasPrefix(rel, "layouts/") || strings.HasPrefix(rel, "partials/") {
+			shared = append(shared, rel)
+		} else {
+			pages = append(pages, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Layouts and partials, parsed once and cloned for each page
+	base := template.New("").Funcs(v.funcs)
+	for _, name := range shared {
+		if err := parse(base.New(name), dir, name); err != nil {
+			return 0, err
+		}
+	}
+	layout := ""
+	if v.cfg.Layout != "" && base.Lookup(v.cfg.Layout) != nil {
+		layout = v.cfg.Layout
+	}
+
+	loaded := make(map[string]*template.Template, len(pages))
+	for _, name := range pages {
+		page, err := base.Clone()
+		if err != nil {
+			return 0, err
+		}
+		if err := parse(page.New(contentTemplate), dir, name); err != nil {
+			return 0, err
+		}
+		loaded[name] = page
+	}
+	v.pages, v.layout = loaded, layout
+	return len(loaded), nil
+}
+
+// parse parses the file name in dir into t
+func parse(t *template.Template, dir, name string) error {
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+	if err != nil {
+		return err
+	}
+	if _, err := t.Parse(string(data)); err != nil {
+		return fmt.Errorf("invalid view %s: %w", name, err)
+	}
+	return nil
+}
+
+// Names returns the loaded pages, sorted
+func (v *Views) Names() []string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	names := make([]string, 0, len(v.pages))
+	for name := range v.pages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Render writes the page name, rendered with data in the layout. With
+// views.reload set, the templates are read again first.
+func (v *Views) Render(w io.Writer, name string, data interface{}) error {
+	v.mu.RLock()
+	reload := v.cfg.Reload
+	v.mu.RUnlock()
+	if reload {
+		v.mu.Lock()
+		_, err := v.load()
+		v.mu.Unlock()
+		if err != nil {
+			return fmt.Errorf("failed to reload views: %w", err)
+		}
+	}
+
+	v.mu.RLock()
+	page, ok := v.pages[name]
+	layout := v.layout
+	v.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrViewNotFound, name)
+	}
+
+	if layout == "" {
+		layout = contentTemplate
+	}
+	if err := page.ExecuteTemplate(w, layout, data); err != nil {
+		return fmt.Errorf("failed to render view %s: %w", name, err)
+	}
+	return nil
+}
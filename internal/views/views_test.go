@@ -0,0 +1,133 @@
+package views
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/evantahler/go-actionhero/internal/config"
+)
+
+// writeViews writes files (by slash-separated path) into a new views
+// directory
+func writeViews(t *testing.T, files map[string]string) config.ViewsConfig {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := config.DefaultViewsConfig()
+	cfg.Directory = dir
+	return cfg
+}
+
+// render renders name, failing the test on errors
+func render(t *testing.T, v *Views, name string, data interface{}) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := v.Render(&buf, name, data); err != nil {
+		t.Fatalf("Failed to render %s: %v", name, err)
+	}
+	return buf.String()
+}
+
+func TestViews_Render(t *testing.T) {
+	cfg := writeViews(t, map[string]string{
+		"layouts/main.html":  `<title>{{block "title" .}}My App{{end}}</title>{{template "partials/nav.html" .}}<main>{{template "content" .}}</main>`,
+		"partials/nav.html":  `<nav>{{.User}}</nav>`,
+		"index.html":         `<p>Welcome {{.User}}</p>`,
+		"users/show.html":    `{{define "title"}}{{.User}} - My App{{end}}<h1>{{.User | shout}}</h1>`,
+		"users/README.md":    "not a view",
+		"layouts/other.html": `other`,
+	})
+
+	v := New()
+	v.Funcs(template.FuncMap{"shout": strings.ToUpper})
+	loaded, err := v.Load(cfg)
+	if err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	if loaded != 2 || !reflect.DeepEqual(v.Names(), []string{"index.html", "users/show.html"}) {
+		t.Errorf("Expected only pages to be views, got %d %v", loaded, v.Names())
+	}
+
+	data := map[string]string{"User": "<mario>"}
+	if got := render(t, v, "index.html", data); got != `<title>My App</title><nav>&lt;mario&gt;</nav><main><p>Welcome &lt;mario&gt;</p></main>` {
+		t.Errorf("Unexpected page: %s", got)
+	}
+	if got := render(t, v, "users/show.html", data); got != `<title>&lt;mario&gt; - My App</title><nav>&lt;mario&gt;</nav><main><h1>&lt;MARIO&gt;</h1></main>` {
+		t.Errorf("Expected the page's title block, got %s", got)
+	}
+
+	if err := v.Render(&bytes.Buffer{}, "missing.html", nil); !errors.Is(err, ErrViewNotFound) {
+		t.Errorf("Expected ErrViewNotFound, got %v", err)
+	}
+}
+
+func TestViews_NoLayout(t *testing.T) {
+	cfg := writeViews(t, map[string]string{"index.html": `<p>{{.}}</p>`})
+	v := New()
+	if _, err := v.Load(cfg); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	if got := render(t, v, "index.html", "hi"); got != "<p>hi</p>" {
+		t.Errorf("Expected the page alone without a layout file, got %s", got)
+	}
+}
+
+func TestViews_Reload(t *testing.T) {
+	cfg := writeViews(t, map[string]string{"index.html": `v1`})
+	v := New()
+	if _, err := v.Load(cfg); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cfg.Directory, "index.html"), []byte("v2"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := render(t, v, "index.html", nil); got != "v1" {
+		t.Errorf("Expected the loaded template without reload, got %s", got)
+	}
+
+	cfg.Reload = true
+	if _, err := v.Load(cfg); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cfg.Directory, "index.html"), []byte("v3"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := render(t, v, "index.html", nil); got != "v3" {
+		t.Errorf("Expected the template to be read again, got %s", got)
+	}
+}
+
+func TestViews_Errors(t *testing.T) {
+	cfg := writeViews(t, map[string]string{"index.html": `{{.Missing`})
+	if _, err := New().Load(cfg); err == nil || !strings.Contains(err.Error(), "invalid view index.html") {
+		t.Errorf("Expected a parse error, got %v", err)
+	}
+
+	cfg = writeViews(t, map[string]string{"index.html": `{{.Name.Other}}`})
+	v := New()
+	if _, err := v.Load(cfg); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	if err := v.Render(&bytes.Buffer{}, "index.html", map[string]int{"Name": 1}); err == nil {
+		t.Error("Expected an execution error")
+	}
+
+	cfg.Directory = filepath.Join(cfg.Directory, "missing")
+	if _, err := New().Load(cfg); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected a not-exist error, got %v", err)
+	}
+}